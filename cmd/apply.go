@@ -0,0 +1,234 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"scheduler/internal/manifest"
+	pb "scheduler/proto/gen"
+)
+
+var applyCmd = &cobra.Command{
+	Use:   "apply -f PATH",
+	Short: "Create or update environments to match manifests",
+	Long: `Create or update environments to match the manifests in files and
+directories. Manifests are matched to environments by name and tenant:
+missing environments are created, changed ones updated and the others left
+alone.
+
+With --prune, environments of the same tenants that no manifest names are
+deleted. Limit pruning with --selector when the tenants hold environments
+managed elsewhere. --dry-run prints the changes without making them.`,
+	Args:              cobra.NoArgs,
+	PersistentPreRunE: prepareClient,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		paths, _ := cmd.Flags().GetStringSlice("filename")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		prune, _ := cmd.Flags().GetBool("prune")
+		selector, _ := cmd.Flags().GetStringToString("selector")
+
+		sources, err := readManifests(paths)
+		if err != nil {
+			return err
+		}
+
+		return withClient(func(ctx context.Context, client pb.SchedulerServiceClient) error {
+			applied := make(map[string]map[string]bool)
+			failed := 0
+			for _, source := range sources {
+				resp, err := applyManifest(ctx, client, source, dryRun)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "%s: %v\n", source, err)
+					failed++
+					continue
+				}
+				tenantName := resp.GetEnvironment().GetTenant()
+				if applied[tenantName] == nil {
+					applied[tenantName] = make(map[string]bool)
+				}
+				applied[tenantName][source.manifest.Spec.GetName()] = true
+			}
+			if failed > 0 {
+				// Pruning after a failure could delete what a broken
+				// manifest was meant to keep
+				return fmt.Errorf("%d of %d manifests failed to apply", failed, len(sources))
+			}
+			if prune {
+				return pruneEnvironments(ctx, client, applied, selector, dryRun)
+			}
+			return nil
+		})
+	},
+}
+
+// manifestSource is a manifest together with where it was read from
+type manifestSource struct {
+	path     string
+	manifest *manifest.Manifest
+}
+
+func (s manifestSource) String() string {
+	return fmt.Sprintf("%s:%d", s.path, s.manifest.Line)
+}
+
+// readManifests parses every manifest in the given files and directories,
+// reporting all invalid files at once. Directories contribute their .yaml,
+// .yml and .json files.
+func readManifests(paths []string) ([]manifestSource, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if path == "-" || err == nil && !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		for _, entry := range entries {
+			switch filepath.Ext(entry.Name()) {
+			case ".yaml", ".yml", ".json":
+				if !entry.IsDir() {
+					files = append(files, filepath.Join(path, entry.Name()))
+				}
+			}
+		}
+	}
+
+	var sources []manifestSource
+	var problems []string
+	seen := make(map[string]manifestSource)
+	for _, file := range files {
+		var data []byte
+		var err error
+		if file == "-" {
+			data, err = io.ReadAll(os.Stdin)
+		} else {
+			data, err = os.ReadFile(file)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		manifests, err := manifest.Parse(data)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s:\n%v", file, err))
+			continue
+		}
+		for _, m := range manifests {
+			source := manifestSource{path: file, manifest: m}
+			key := m.Tenant + "/" + m.Spec.GetName()
+			if previous, ok := seen[key]; ok {
+				problems = append(problems, fmt.Sprintf("%s: environment %s is also declared at %s", source, m.Spec.GetName(), previous))
+				continue
+			}
+			seen[key] = source
+			sources = append(sources, source)
+		}
+	}
+	if len(problems) > 0 {
+		return nil, errors.New(strings.Join(problems, "\n"))
+	}
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("no manifests found in %s", strings.Join(paths, ", "))
+	}
+	return sources, nil
+}
+
+// applyManifest sends one manifest to the server and prints the outcome
+func applyManifest(ctx context.Context, client pb.SchedulerServiceClient, source manifestSource, dryRun bool) (*pb.ApplyManifestResponse, error) {
+	data, err := manifest.Marshal(source.manifest)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.ApplyManifest(ctx, &pb.ApplyManifestRequest{Manifest: string(data), DryRun: dryRun})
+	if err != nil {
+		return nil, rpcError(err)
+	}
+
+	format := viper.GetString("client.output")
+	if format != outputTable {
+		return resp, printMessage(os.Stdout, format, resp)
+	}
+	suffix := ""
+	if dryRun {
+		suffix = " (dry run)"
+	}
+	name := source.manifest.Spec.GetName()
+	switch resp.GetAction() {
+	case pb.ManifestAction_MANIFEST_ACTION_CREATED:
+		fmt.Printf("environment %s created%s\n", name, suffix)
+	case pb.ManifestAction_MANIFEST_ACTION_UPDATED:
+		fmt.Printf("environment %s configured%s\n", name, suffix)
+		printChanges("  ", resp.GetChanges())
+	default:
+		fmt.Printf("environment %s unchanged\n", name)
+	}
+	return resp, nil
+}
+
+// pruneEnvironments deletes the environments of the applied tenants that no
+// manifest named and that match selector
+func pruneEnvironments(ctx context.Context, client pb.SchedulerServiceClient, applied map[string]map[string]bool, selector map[string]string, dryRun bool) error {
+	tenants := make([]string, 0, len(applied))
+	for tenantName := range applied {
+		tenants = append(tenants, tenantName)
+	}
+	sort.Strings(tenants)
+
+	// Page tokens are offsets into the listing, so deleting while paging
+	// would skip environments. Every page is listed before the first delete.
+	var pruned []*pb.Environment
+	for _, tenantName := range tenants {
+		req := &pb.ListEnvironmentsRequest{Filters: selector, Tenant: tenantName}
+		for {
+			resp, err := client.ListEnvironments(ctx, req)
+			if err != nil {
+				return rpcError(err)
+			}
+			for _, env := range resp.GetEnvironments() {
+				if !applied[tenantName][env.GetName()] {
+					pruned = append(pruned, env)
+				}
+			}
+			if resp.GetNextPageToken() == "" {
+				break
+			}
+			req.PageToken = resp.GetNextPageToken()
+		}
+	}
+
+	for _, env := range pruned {
+		if dryRun {
+			fmt.Printf("environment %s pruned (dry run)\n", env.GetName())
+			continue
+		}
+		if _, err := client.DeleteEnvironment(ctx, &pb.DeleteEnvironmentRequest{Id: env.GetId()}); err != nil {
+			return fmt.Errorf("failed to prune %s: %w", env.GetName(), rpcError(err))
+		}
+		fmt.Printf("environment %s pruned\n", env.GetName())
+	}
+	return nil
+}
+
+func init() {
+	addClientFlags(applyCmd)
+	applyCmd.Flags().StringSliceP("filename", "f", nil, "manifest file or directory of manifests, repeatable, - for stdin")
+	applyCmd.Flags().Bool("dry-run", false, "print the changes without making them")
+	applyCmd.Flags().Bool("prune", false, "delete environments of the same tenants that no manifest names")
+	applyCmd.Flags().StringToStringP("selector", "l", nil, "only prune environments with these labels, key=value")
+	applyCmd.MarkFlagRequired("filename")
+	rootCmd.AddCommand(applyCmd)
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"scheduler/internal/certs"
+)
+
+var certsCmd = &cobra.Command{
+	Use:   "certs",
+	Short: "Manage TLS certificates",
+}
+
+var certsInitCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Generate a development CA with server and client certificates",
+	Long: `Generate a local certificate authority together with a server certificate
+and a client certificate signed by it. The files are meant for development
+setups and can be referenced from the tls section of the config file.`,
+	Run: initCerts,
+}
+
+func init() {
+	certsInitCmd.Flags().String("dir", "certs", "directory to write the certificates to")
+	certsInitCmd.Flags().StringSlice("host", []string{"localhost", "127.0.0.1"}, "DNS names and IP addresses the server certificate is valid for")
+	certsInitCmd.Flags().String("client-name", "scheduler-client", "common name of the client certificate")
+	certsInitCmd.Flags().Duration("validity", 365*24*time.Hour, "how long the certificates are valid")
+	certsInitCmd.Flags().Bool("force", false, "overwrite existing certificates")
+
+	certsCmd.AddCommand(certsInitCmd)
+	rootCmd.AddCommand(certsCmd)
+}
+
+func initCerts(cmd *cobra.Command, args []string) {
+	dir, _ := cmd.Flags().GetString("dir")
+	hosts, _ := cmd.Flags().GetStringSlice("host")
+	clientName, _ := cmd.Flags().GetString("client-name")
+	validity, _ := cmd.Flags().GetDuration("validity")
+	force, _ := cmd.Flags().GetBool("force")
+
+	err := certs.Init(dir, certs.InitOptions{
+		Hosts:      hosts,
+		ClientName: clientName,
+		Validity:   validity,
+		Overwrite:  force,
+	})
+	if err != nil {
+		log.Fatalf("Failed to generate certificates: %v", err)
+	}
+
+	fmt.Printf("Wrote CA, server and client certificates to %s\n", dir)
+	fmt.Println("\nAdd the following to your config file to enable mTLS:")
+	fmt.Printf(`tls:
+  enabled: true
+  cert_file: %q
+  key_file: %q
+  client_ca_file: %q
+  require_client_cert: true
+`, filepath.Join(dir, certs.ServerFile), filepath.Join(dir, certs.ServerKeyFile), filepath.Join(dir, certs.CAFile))
+}
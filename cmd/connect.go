@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	"scheduler/internal/auth"
+	"scheduler/internal/certs"
+	pb "scheduler/proto/gen"
+)
+
+// addClientFlags adds the connection and output flags of the client
+// commands to cmd and its subcommands
+func addClientFlags(cmd *cobra.Command) {
+	flags := cmd.PersistentFlags()
+	flags.StringP("output", "o", outputTable, "output format: table, json or yaml")
+	flags.String("token", "", "bearer token, an API key or a JWT")
+	flags.String("ca", "", "CA bundle to verify the server with, enables TLS")
+	flags.String("cert", "", "client certificate for mTLS")
+	flags.String("key", "", "client certificate key for mTLS")
+	flags.Duration("timeout", 30*time.Second, "how long to wait for the server")
+	flags.String("color", colorAuto, "color diffs: auto, always or never")
+}
+
+// prepareClient binds the client flags of the running command to the client
+// section of the config. Binding happens here rather than in init since
+// several commands define the same flags.
+func prepareClient(cmd *cobra.Command, args []string) error {
+	// Arguments have been parsed at this point, so failures from here on
+	// are not usage errors
+	cmd.SilenceUsage = true
+
+	flags := cmd.Flags()
+	for key, flag := range map[string]string{
+		"client.output":    "output",
+		"client.token":     "token",
+		"client.ca_file":   "ca",
+		"client.cert_file": "cert",
+		"client.key_file":  "key",
+		"client.timeout":   "timeout",
+		"client.color":     "color",
+	} {
+		if err := viper.BindPFlag(key, flags.Lookup(flag)); err != nil {
+			return err
+		}
+	}
+	if err := viper.BindEnv("client.token", "SCHEDULER_TOKEN"); err != nil {
+		return err
+	}
+	if err := validateColor(viper.GetString("client.color")); err != nil {
+		return err
+	}
+	return validateOutput(viper.GetString("client.output"))
+}
+
+// dialScheduler connects to the server at the --host and --port flags. TLS is
+// used when a CA bundle or client certificate is configured, and the bearer
+// token is attached to every call.
+func dialScheduler() (pb.SchedulerServiceClient, *grpc.ClientConn, error) {
+	address := net.JoinHostPort(viper.GetString("host"), strconv.Itoa(viper.GetInt("port")))
+
+	// Plaintext is only used when no TLS files are given
+	transport := insecure.NewCredentials()
+	caFile := viper.GetString("client.ca_file")
+	certFile := viper.GetString("client.cert_file")
+	if caFile != "" || certFile != "" {
+		tlsConfig, err := certs.ClientConfig(certs.ClientOptions{
+			CAFile:     caFile,
+			CertFile:   certFile,
+			KeyFile:    viper.GetString("client.key_file"),
+			ServerName: viper.GetString("client.server_name"),
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to load TLS configuration: %w", err)
+		}
+		transport = credentials.NewTLS(tlsConfig)
+	}
+
+	dialOptions := []grpc.DialOption{grpc.WithTransportCredentials(transport)}
+	if token := viper.GetString("client.token"); token != "" {
+		dialOptions = append(dialOptions, grpc.WithPerRPCCredentials(auth.BearerToken{
+			Token:    token,
+			Insecure: transport.Info().SecurityProtocol == "insecure",
+		}))
+	}
+
+	conn, err := grpc.NewClient(address, dialOptions...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to %s: %w", address, err)
+	}
+	return pb.NewSchedulerServiceClient(conn), conn, nil
+}
+
+// rpcError renders a gRPC error as its code and message
+func rpcError(err error) error {
+	st := status.Convert(err)
+	return fmt.Errorf("%s: %s", st.Code(), st.Message())
+}
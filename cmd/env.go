@@ -0,0 +1,508 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "scheduler/proto/gen"
+)
+
+var envCmd = &cobra.Command{
+	Use:   "env",
+	Short: "Manage environments on a scheduler server",
+	Long: `Manage environments on the scheduler server at --host and --port.
+
+Connection settings can also come from the client section of the config file
+and the bearer token from the SCHEDULER_TOKEN environment variable.`,
+	PersistentPreRunE: prepareClient,
+}
+
+var envCreateCmd = &cobra.Command{
+	Use:   "create {-f spec.yaml | NAME --template TEMPLATE}",
+	Short: "Create an environment from a specification file or a template",
+	Long: `Create an environment from a manifest or specification file, or expand a
+template into an environment called NAME. Template parameters are given with
+--set, and "scheduler template get" lists them.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		file, _ := cmd.Flags().GetString("file")
+		tenant, _ := cmd.Flags().GetString("tenant")
+		templateName, _ := cmd.Flags().GetString("template")
+		if templateName != "" {
+			if file != "" || len(args) != 1 {
+				return errors.New("--template takes the environment name as the only argument and no --file")
+			}
+			params, _ := cmd.Flags().GetStringToString("set")
+			labels, _ := cmd.Flags().GetStringToString("label")
+			version, _ := cmd.Flags().GetInt32("template-version")
+			return withClient(func(ctx context.Context, client pb.SchedulerServiceClient) error {
+				resp, err := client.CreateEnvironmentFromTemplate(ctx, &pb.CreateEnvironmentFromTemplateRequest{
+					Template:        templateName,
+					TemplateVersion: version,
+					Name:            args[0],
+					Parameters:      params,
+					Tenant:          tenant,
+					Labels:          labels,
+				})
+				if err != nil {
+					return rpcError(err)
+				}
+				return printEnvironments(resp, resp.GetEnvironment())
+			})
+		}
+
+		if file == "" || len(args) != 0 {
+			return errors.New("either --file or a name and --template are required")
+		}
+		m, err := readSpecFile(file)
+		if err != nil {
+			return err
+		}
+		if tenant == "" {
+			tenant = m.Tenant
+		}
+		return withClient(func(ctx context.Context, client pb.SchedulerServiceClient) error {
+			resp, err := client.CreateEnvironment(ctx, &pb.CreateEnvironmentRequest{Spec: m.Spec, Tenant: tenant})
+			if err != nil {
+				return rpcError(err)
+			}
+			return printEnvironments(resp, resp.GetEnvironment())
+		})
+	},
+}
+
+var envGetCmd = &cobra.Command{
+	Use:   "get ID",
+	Short: "Show an environment",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		reveal, _ := cmd.Flags().GetBool("reveal")
+		return withClient(func(ctx context.Context, client pb.SchedulerServiceClient) error {
+			resp, err := client.GetEnvironment(ctx, &pb.GetEnvironmentRequest{Id: args[0], RevealSensitive: reveal})
+			if err != nil {
+				return rpcError(err)
+			}
+			return printEnvironments(resp, resp.GetEnvironment())
+		})
+	},
+}
+
+var envListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List environments",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		selector, _ := cmd.Flags().GetStringToString("selector")
+		tenant, _ := cmd.Flags().GetString("tenant")
+		return withClient(func(ctx context.Context, client pb.SchedulerServiceClient) error {
+			// Every page is fetched so that the output is complete
+			all := &pb.ListEnvironmentsResponse{}
+			req := &pb.ListEnvironmentsRequest{Filters: selector, Tenant: tenant}
+			for {
+				resp, err := client.ListEnvironments(ctx, req)
+				if err != nil {
+					return rpcError(err)
+				}
+				all.Environments = append(all.Environments, resp.GetEnvironments()...)
+				all.TotalCount = resp.GetTotalCount()
+				if resp.GetNextPageToken() == "" {
+					break
+				}
+				req.PageToken = resp.GetNextPageToken()
+			}
+			return printEnvironments(all, all.GetEnvironments()...)
+		})
+	},
+}
+
+var envUpdateCmd = &cobra.Command{
+	Use:   "update ID -f spec.yaml",
+	Short: "Replace the specification of an environment",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		file, _ := cmd.Flags().GetString("file")
+		m, err := readSpecFile(file)
+		if err != nil {
+			return err
+		}
+		return withClient(func(ctx context.Context, client pb.SchedulerServiceClient) error {
+			resp, err := client.UpdateEnvironment(ctx, &pb.UpdateEnvironmentRequest{Id: args[0], Spec: m.Spec})
+			if err != nil {
+				return rpcError(err)
+			}
+			return printEnvironments(resp, resp.GetEnvironment())
+		})
+	},
+}
+
+var envPlanCmd = &cobra.Command{
+	Use:   "plan ID -f spec.yaml",
+	Short: "Show what updating an environment would change",
+	Long: `Show what updating an environment to a specification would change without
+changing anything: which containers would be created, recreated, left alone
+or removed, and the fields that differ. Updating a running environment carries
+out exactly that plan: removed and recreated containers stop, created and
+recreated ones start around the deploy hooks, and unchanged containers keep
+running. A failed update leaves the environment failed until it is restarted.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		file, _ := cmd.Flags().GetString("file")
+		m, err := readSpecFile(file)
+		if err != nil {
+			return err
+		}
+		return withClient(func(ctx context.Context, client pb.SchedulerServiceClient) error {
+			resp, err := client.PlanEnvironmentUpdate(ctx, &pb.PlanEnvironmentUpdateRequest{Id: args[0], Spec: m.Spec})
+			if err != nil {
+				return rpcError(err)
+			}
+			if viper.GetString("client.output") != outputTable {
+				return printMessage(os.Stdout, viper.GetString("client.output"), resp)
+			}
+			printPlan(resp)
+			return nil
+		})
+	},
+}
+
+var envDeleteCmd = &cobra.Command{
+	Use:   "delete ID",
+	Short: "Delete an environment, stopping it first",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		purge, _ := cmd.Flags().GetBool("purge-volumes")
+		return withClient(func(ctx context.Context, client pb.SchedulerServiceClient) error {
+			resp, err := client.DeleteEnvironment(ctx, &pb.DeleteEnvironmentRequest{Id: args[0], PurgeVolumes: purge})
+			if err != nil {
+				return rpcError(err)
+			}
+			if viper.GetString("client.output") != outputTable {
+				return printMessage(os.Stdout, viper.GetString("client.output"), resp)
+			}
+			fmt.Printf("Deleted environment %s\n", args[0])
+			return nil
+		})
+	},
+}
+
+var envStartCmd = &cobra.Command{
+	Use:   "start ID",
+	Short: "Start the containers of an environment",
+	Long: `Start the containers of an environment. When the host is full the
+environment waits in the admission queue and its position is shown.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return withClient(func(ctx context.Context, client pb.SchedulerServiceClient) error {
+			resp, err := client.StartEnvironment(ctx, &pb.StartEnvironmentRequest{Id: args[0]})
+			if err != nil {
+				return rpcError(err)
+			}
+			return printEnvironments(resp, resp.GetEnvironment())
+		})
+	},
+}
+
+var envStopCmd = &cobra.Command{
+	Use:   "stop ID",
+	Short: "Stop the containers of an environment",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		force, _ := cmd.Flags().GetBool("force")
+		return withClient(func(ctx context.Context, client pb.SchedulerServiceClient) error {
+			resp, err := client.StopEnvironment(ctx, &pb.StopEnvironmentRequest{Id: args[0], Force: force})
+			if err != nil {
+				return rpcError(err)
+			}
+			return printEnvironments(resp, resp.GetEnvironment())
+		})
+	},
+}
+
+var envRestartCmd = &cobra.Command{
+	Use:   "restart ID",
+	Short: "Restart the containers of an environment",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return withClient(func(ctx context.Context, client pb.SchedulerServiceClient) error {
+			resp, err := client.RestartEnvironment(ctx, &pb.RestartEnvironmentRequest{Id: args[0]})
+			if err != nil {
+				return rpcError(err)
+			}
+			return printEnvironments(resp, resp.GetEnvironment())
+		})
+	},
+}
+
+var envStatusCmd = &cobra.Command{
+	Use:   "status ID",
+	Short: "Show the status of an environment and its containers",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		reveal, _ := cmd.Flags().GetBool("reveal")
+		return withClient(func(ctx context.Context, client pb.SchedulerServiceClient) error {
+			resp, err := client.GetEnvironmentStatus(ctx, &pb.GetEnvironmentStatusRequest{Id: args[0], RevealSensitive: reveal})
+			if err != nil {
+				return rpcError(err)
+			}
+			if viper.GetString("client.output") != outputTable {
+				return printMessage(os.Stdout, viper.GetString("client.output"), resp)
+			}
+			return printStatus(resp)
+		})
+	},
+}
+
+var envLogsCmd = &cobra.Command{
+	Use:   "logs ID",
+	Short: "Print the logs of the containers of an environment",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		follow, _ := cmd.Flags().GetBool("follow")
+		tail, _ := cmd.Flags().GetInt32("tail")
+		since, _ := cmd.Flags().GetString("since")
+		containerName, _ := cmd.Flags().GetString("container")
+
+		req := &pb.GetEnvironmentLogsRequest{Id: args[0], ContainerName: containerName, TailLines: tail, Follow: follow}
+		if since != "" {
+			sinceTime, err := parseSince(since)
+			if err != nil {
+				return err
+			}
+			req.Since = timestamppb.New(sinceTime)
+		}
+
+		client, conn, err := dialScheduler()
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		// Followed logs run until interrupted, so only the other calls time out
+		ctx := context.Background()
+		if !follow {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, viper.GetDuration("client.timeout"))
+			defer cancel()
+		}
+		stream, err := client.GetEnvironmentLogs(ctx, req)
+		if err != nil {
+			return rpcError(err)
+		}
+		for {
+			entry, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			if err != nil {
+				return rpcError(err)
+			}
+			if viper.GetString("client.output") != outputTable {
+				if err := printMessage(os.Stdout, viper.GetString("client.output"), entry); err != nil {
+					return err
+				}
+				continue
+			}
+			fmt.Printf("%s %s | %s\n", entry.GetTimestamp().AsTime().Local().Format(time.RFC3339), entry.GetContainerName(), entry.GetMessage())
+		}
+	},
+}
+
+func init() {
+	addClientFlags(envCmd)
+
+	envCreateCmd.Flags().StringP("file", "f", "", "manifest or specification file, YAML or JSON, - for stdin")
+	envCreateCmd.Flags().String("tenant", "", "tenant to create the environment in, overrides the manifest")
+	envCreateCmd.Flags().String("template", "", "template to expand instead of reading a file")
+	envCreateCmd.Flags().Int32("template-version", 0, "template version to expand, the latest when 0")
+	envCreateCmd.Flags().StringToString("set", nil, "template parameter, key=value, repeatable")
+	envCreateCmd.Flags().StringToString("label", nil, "label to add to the labels of the template, key=value")
+	envGetCmd.Flags().Bool("reveal", false, "show sensitive fields unmasked, if permitted")
+	envStatusCmd.Flags().Bool("reveal", false, "show the output of database steps and hooks, if permitted")
+	envListCmd.Flags().StringToStringP("selector", "l", nil, "only list environments with these labels, key=value")
+	envListCmd.Flags().String("tenant", "", "only list environments of this tenant")
+	envUpdateCmd.Flags().StringP("file", "f", "", "manifest or specification file, YAML or JSON, - for stdin")
+	envUpdateCmd.MarkFlagRequired("file")
+	envPlanCmd.Flags().StringP("file", "f", "", "manifest or specification file, YAML or JSON, - for stdin")
+	envPlanCmd.MarkFlagRequired("file")
+	envDeleteCmd.Flags().Bool("purge-volumes", false, "also delete the named volumes the environment created")
+	envStopCmd.Flags().Bool("force", false, "kill the containers without waiting for them to exit")
+	envLogsCmd.Flags().BoolP("follow", "f", false, "keep streaming new log lines")
+	envLogsCmd.Flags().Int32("tail", 0, "only print the last lines of each container, 0 for all")
+	envLogsCmd.Flags().String("since", "", "only print lines newer than a duration like 10m or an RFC 3339 time")
+	envLogsCmd.Flags().StringP("container", "c", "", "only print the logs of this container")
+
+	envCmd.AddCommand(envCreateCmd, envGetCmd, envListCmd, envUpdateCmd, envPlanCmd, envDeleteCmd,
+		envStartCmd, envStopCmd, envRestartCmd, envStatusCmd, envLogsCmd)
+	rootCmd.AddCommand(envCmd)
+}
+
+// withClient connects to the server and runs call with a context bounded by
+// the --timeout flag
+func withClient(call func(ctx context.Context, client pb.SchedulerServiceClient) error) error {
+	client, conn, err := dialScheduler()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), viper.GetDuration("client.timeout"))
+	defer cancel()
+	return call(ctx, client)
+}
+
+// printEnvironments writes resp in the selected output format. The table
+// format lists environments instead of the whole response.
+func printEnvironments(resp proto.Message, environments ...*pb.Environment) error {
+	format := viper.GetString("client.output")
+	if format != outputTable {
+		return printMessage(os.Stdout, format, resp)
+	}
+	table := newTable("ID", "NAME", "TENANT", "STATUS", "REVISION", "CONTAINERS", "AGE")
+	for _, env := range environments {
+		state := enumName(env.GetStatus(), "ENVIRONMENT_STATUS_")
+		if env.GetQueuePosition() > 0 {
+			state = fmt.Sprintf("%s (queued #%d)", state, env.GetQueuePosition())
+		}
+		tableRow(table, env.GetId(), env.GetName(), env.GetTenant(), state,
+			env.GetRevision(), len(env.GetContainers()), age(env.GetCreatedAt()))
+	}
+	return table.Flush()
+}
+
+// printStatus writes an environment followed by a table of its containers
+func printStatus(resp *pb.GetEnvironmentStatusResponse) error {
+	if err := printEnvironments(resp, resp.GetEnvironment()); err != nil {
+		return err
+	}
+	containers := resp.GetEnvironment().GetContainers()
+	if len(containers) == 0 {
+		fmt.Println("\nNo containers running")
+		return nil
+	}
+	fmt.Println()
+	table := newTable("NAME", "ID", "IMAGE", "IP", "STATUS", "AGE")
+	for _, container := range containers {
+		tableRow(table, container.GetName(), container.GetId(), container.GetImage(), container.GetIpAddress(),
+			enumName(container.GetStatus(), "CONTAINER_STATUS_"), age(container.GetStartedAt()))
+	}
+	if err := table.Flush(); err != nil {
+		return err
+	}
+
+	if steps := resp.GetEnvironment().GetDatabaseSteps(); len(steps) > 0 {
+		fmt.Println()
+		table = newTable("DATABASE STEP", "NAME", "RESULT", "AGE", "OUTPUT")
+		for _, step := range steps {
+			result, output := stepResult(step.GetSucceeded(), step.GetOutput())
+			tableRow(table, strings.ReplaceAll(strings.ToLower(enumName(step.GetStep(), "DATABASE_STEP_")), "_", " "),
+				step.GetName(), result, age(step.GetCompletedAt()), output)
+		}
+		if err := table.Flush(); err != nil {
+			return err
+		}
+	}
+
+	if hooks := resp.GetEnvironment().GetHookResults(); len(hooks) > 0 {
+		fmt.Println()
+		table = newTable("HOOK", "NAME", "RESULT", "AGE", "OUTPUT")
+		for _, hook := range hooks {
+			result, output := stepResult(hook.GetSucceeded(), hook.GetOutput())
+			tableRow(table, strings.ReplaceAll(strings.ToLower(enumName(hook.GetPhase(), "HOOK_PHASE_")), "_", " "),
+				hook.GetName(), result, age(hook.GetCompletedAt()), output)
+		}
+		if err := table.Flush(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// stepResult describes the outcome of a database step or hook for a table,
+// with the last line of the output of failures. The full output is in -o yaml.
+func stepResult(succeeded bool, output string) (string, string) {
+	if succeeded {
+		return "succeeded", ""
+	}
+	lines := strings.Split(output, "\n")
+	return "failed", lines[len(lines)-1]
+}
+
+// printPlan writes the containers of a plan with the changes behind each
+// action, followed by the changes that affect no container
+func printPlan(resp *pb.PlanEnvironmentUpdateResponse) {
+	env := resp.GetEnvironment()
+	if len(resp.GetChanges()) == 0 {
+		fmt.Printf("environment %s (%s) is up to date\n", env.GetName(), env.GetId())
+		return
+	}
+	counts := make(map[pb.ContainerAction]int)
+	for _, plan := range resp.GetContainers() {
+		counts[plan.GetAction()]++
+	}
+	fmt.Printf("environment %s (%s): %d to create, %d to recreate, %d to remove, %d unchanged\n\n",
+		env.GetName(), env.GetId(),
+		counts[pb.ContainerAction_CONTAINER_ACTION_CREATE], counts[pb.ContainerAction_CONTAINER_ACTION_RECREATE],
+		counts[pb.ContainerAction_CONTAINER_ACTION_REMOVE], counts[pb.ContainerAction_CONTAINER_ACTION_UNCHANGED])
+
+	shown := make(map[string]bool)
+	for _, plan := range resp.GetContainers() {
+		line := fmt.Sprintf("%s (%s) %s", plan.GetAlias(), plan.GetName(), enumName(plan.GetAction(), "CONTAINER_ACTION_"))
+		var notes []string
+		if plan.GetVolumesChanged() {
+			notes = append(notes, "volumes change")
+		}
+		if plan.GetPortsChanged() {
+			notes = append(notes, "ports change")
+		}
+		if len(notes) > 0 {
+			line += ", " + strings.Join(notes, ", ")
+		}
+		switch plan.GetAction() {
+		case pb.ContainerAction_CONTAINER_ACTION_CREATE:
+			fmt.Println(colorize(ansiGreen, "+ "+line))
+		case pb.ContainerAction_CONTAINER_ACTION_RECREATE:
+			fmt.Println(colorize(ansiYellow, "~ "+line))
+		case pb.ContainerAction_CONTAINER_ACTION_REMOVE:
+			fmt.Println(colorize(ansiRed, "- "+line))
+		default:
+			fmt.Println("  " + line)
+		}
+		printChanges("    ", plan.GetChanges())
+		for _, change := range plan.GetChanges() {
+			shown[change.GetPath()] = true
+		}
+	}
+
+	var other []*pb.FieldChange
+	for _, change := range resp.GetChanges() {
+		if !shown[change.GetPath()] {
+			other = append(other, change)
+		}
+	}
+	if len(other) > 0 {
+		fmt.Println("\nother changes")
+		printChanges("    ", other)
+	}
+}
+
+// parseSince parses the --since flag as a duration before now or a time
+func parseSince(value string) (time.Time, error) {
+	if duration, err := time.ParseDuration(value); err == nil {
+		return time.Now().Add(-duration), nil
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --since %q, use a duration like 10m or an RFC 3339 time", value)
+	}
+	return parsed, nil
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"scheduler/internal/compose"
+	"scheduler/internal/manifest"
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Translate environments from other formats into manifests",
+}
+
+var importComposeCmd = &cobra.Command{
+	Use:   "compose FILE",
+	Short: "Translate a docker-compose file into a manifest",
+	Long: `Translate the services of a docker-compose file into an environment manifest
+printed on stdout. A Postgres service becomes the database of the stack, and
+the frontend and backend are recognized by their names, images and ports. The
+other services become additional services.
+
+Compose features that cannot be represented are reported on stderr.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		name, _ := cmd.Flags().GetString("name")
+		tenant, _ := cmd.Flags().GetString("tenant")
+		output, _ := cmd.Flags().GetString("output")
+
+		result, err := compose.Load(args[0])
+		if err != nil {
+			return err
+		}
+		if name != "" {
+			result.Spec.Name = name
+			result.Spec.ApplicationStack.Name = name
+		}
+
+		services := make([]string, 0, len(result.Roles))
+		for service := range result.Roles {
+			services = append(services, service)
+		}
+		sort.Strings(services)
+		for _, service := range services {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", service, result.Roles[service])
+		}
+		for _, warning := range result.Warnings {
+			fmt.Fprintf(os.Stderr, "warning: %s\n", warning)
+		}
+
+		data, err := manifest.Marshal(&manifest.Manifest{Tenant: tenant, Spec: result.Spec})
+		if err != nil {
+			return err
+		}
+		if output == "" || output == "-" {
+			_, err = os.Stdout.Write(data)
+			return err
+		}
+		if err := os.WriteFile(output, data, 0o644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", output, err)
+		}
+		return nil
+	},
+}
+
+func init() {
+	importComposeCmd.Flags().String("name", "", "environment name, defaults to the compose project name")
+	importComposeCmd.Flags().String("tenant", "", "tenant to set in the manifest")
+	importComposeCmd.Flags().StringP("output", "o", "", "file to write the manifest to instead of stdout")
+
+	importCmd.AddCommand(importComposeCmd)
+	rootCmd.AddCommand(importCmd)
+}
@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/viper"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gopkg.in/yaml.v3"
+
+	pb "scheduler/proto/gen"
+)
+
+// Output formats of the client commands
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+	outputYAML  = "yaml"
+)
+
+// Settings of the --color flag
+const (
+	colorAuto   = "auto"
+	colorAlways = "always"
+	colorNever  = "never"
+)
+
+// ANSI colors of diff lines
+const (
+	ansiRed    = "\x1b[31m"
+	ansiGreen  = "\x1b[32m"
+	ansiYellow = "\x1b[33m"
+	ansiReset  = "\x1b[0m"
+)
+
+// validateOutput checks the --output flag
+func validateOutput(format string) error {
+	switch format {
+	case outputTable, outputJSON, outputYAML:
+		return nil
+	default:
+		return fmt.Errorf("unknown output format %q, must be table, json or yaml", format)
+	}
+}
+
+// validateColor checks the --color flag
+func validateColor(setting string) error {
+	switch setting {
+	case colorAuto, colorAlways, colorNever:
+		return nil
+	default:
+		return fmt.Errorf("unknown color setting %q, must be auto, always or never", setting)
+	}
+}
+
+// colorize wraps text in an ANSI color when the --color flag asks for it.
+// With auto, stdout must be a terminal and NO_COLOR unset.
+func colorize(color, text string) string {
+	switch viper.GetString("client.color") {
+	case colorNever:
+		return text
+	case colorAuto:
+		if _, ok := os.LookupEnv("NO_COLOR"); ok {
+			return text
+		}
+		if info, err := os.Stdout.Stat(); err != nil || info.Mode()&os.ModeCharDevice == 0 {
+			return text
+		}
+	}
+	return color + text + ansiReset
+}
+
+// printChanges lists field changes one per line, indented by prefix: added
+// fields in green, removed in red and modified in yellow
+func printChanges(prefix string, changes []*pb.FieldChange) {
+	for _, change := range changes {
+		switch change.GetType() {
+		case pb.ChangeType_CHANGE_TYPE_ADDED:
+			fmt.Println(colorize(ansiGreen, fmt.Sprintf("%s+ %s: %s", prefix, change.GetPath(), change.GetNewValue())))
+		case pb.ChangeType_CHANGE_TYPE_REMOVED:
+			fmt.Println(colorize(ansiRed, fmt.Sprintf("%s- %s: %s", prefix, change.GetPath(), change.GetOldValue())))
+		default:
+			fmt.Println(colorize(ansiYellow, fmt.Sprintf("%s~ %s: %s -> %s", prefix, change.GetPath(), change.GetOldValue(), change.GetNewValue())))
+		}
+	}
+}
+
+// printMessage writes msg as JSON or YAML using the proto field names, the
+// same names spec files use
+func printMessage(out io.Writer, format string, msg proto.Message) error {
+	data, err := protojson.MarshalOptions{Multiline: true, Indent: "  ", UseProtoNames: true}.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", msg.ProtoReflect().Descriptor().Name(), err)
+	}
+	if format == outputJSON {
+		_, err = fmt.Fprintln(out, string(data))
+		return err
+	}
+
+	// JSON is valid YAML, so decoding it into a node keeps the field order.
+	// Only the flow style has to be reset to get block YAML.
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return fmt.Errorf("failed to convert to YAML: %w", err)
+	}
+	resetStyle(&node)
+	encoder := yaml.NewEncoder(out)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&node); err != nil {
+		return fmt.Errorf("failed to write YAML: %w", err)
+	}
+	return encoder.Close()
+}
+
+func resetStyle(node *yaml.Node) {
+	if node.Kind != yaml.ScalarNode || node.Tag != "!!str" {
+		node.Style = 0
+	} else {
+		// Strings that look like other types keep their quotes
+		node.Style &^= yaml.FlowStyle
+		if !needsQuotes(node.Value) {
+			node.Style = 0
+		}
+	}
+	for _, child := range node.Content {
+		resetStyle(child)
+	}
+}
+
+// needsQuotes reports whether a string would be read back as another type
+// without quotes
+func needsQuotes(value string) bool {
+	var decoded any
+	if err := yaml.Unmarshal([]byte(value), &decoded); err != nil {
+		return true
+	}
+	_, isString := decoded.(string)
+	return !isString || decoded != value
+}
+
+// newTable starts a table on stdout with the given column headers
+func newTable(headers ...string) *tabwriter.Writer {
+	table := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(table, strings.Join(headers, "\t"))
+	return table
+}
+
+// tableRow writes one row of a table
+func tableRow(table *tabwriter.Writer, columns ...any) {
+	cells := make([]string, len(columns))
+	for i, column := range columns {
+		cells[i] = fmt.Sprint(column)
+		if cells[i] == "" {
+			cells[i] = "-"
+		}
+	}
+	fmt.Fprintln(table, strings.Join(cells, "\t"))
+}
+
+// age renders the time since ts in its largest unit, like 5m or 3d
+func age(ts *timestamppb.Timestamp) string {
+	if ts == nil {
+		return "-"
+	}
+	elapsed := time.Since(ts.AsTime())
+	switch {
+	case elapsed < time.Minute:
+		return fmt.Sprintf("%ds", int(elapsed.Seconds()))
+	case elapsed < time.Hour:
+		return fmt.Sprintf("%dm", int(elapsed.Minutes()))
+	case elapsed < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(elapsed.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(elapsed.Hours()/24))
+	}
+}
+
+// enumName strips the type prefix from an enum value name, turning
+// ENVIRONMENT_STATUS_RUNNING into RUNNING
+func enumName(value fmt.Stringer, prefix string) string {
+	return strings.TrimPrefix(value.String(), prefix)
+}
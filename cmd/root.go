@@ -33,6 +33,13 @@ func init() {
 
 	viper.BindPFlag("host", rootCmd.PersistentFlags().Lookup("host"))
 	viper.BindPFlag("port", rootCmd.PersistentFlags().Lookup("port"))
+
+	viper.SetDefault("data_dir", "/var/lib/scheduler")
+	viper.SetDefault("containerd.namespace", "scheduler")
+	viper.SetDefault("network.subnet_pool", []string{"172.20.0.0/14"})
+	viper.SetDefault("network.subnet_prefix_length", 24)
+	viper.SetDefault("dns.enabled", true)
+	viper.SetDefault("dns.port", 53)
 }
 
 func initConfig() {
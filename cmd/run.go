@@ -1,18 +1,41 @@
 package cmd
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"log"
 	"net"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 
+	"scheduler/internal/admission"
+	"scheduler/internal/audit"
+	"scheduler/internal/auth"
+	"scheduler/internal/backup"
+	"scheduler/internal/capacity"
+	"scheduler/internal/certs"
+	"scheduler/internal/container"
+	"scheduler/internal/dns"
+	"scheduler/internal/middleware"
+	"scheduler/internal/network"
+	"scheduler/internal/quota"
+	"scheduler/internal/revision"
+	"scheduler/internal/secrets"
 	"scheduler/internal/service"
+	"scheduler/internal/snapshot"
+	"scheduler/internal/store"
+	"scheduler/internal/templates"
+	"scheduler/internal/tenant"
+	"scheduler/internal/volume"
 	pb "scheduler/proto/gen"
 )
 
@@ -35,11 +58,248 @@ func runServer(cmd *cobra.Command, args []string) {
 
 	fmt.Printf("Starting scheduler server on %s\n", address)
 
-	// Create gRPC server
-	server := grpc.NewServer()
+	// Create gRPC server. Sensitive fields are masked in every response and
+	// in request logs.
+	unary := []grpc.UnaryServerInterceptor{}
+	stream := []grpc.StreamServerInterceptor{}
+	if viper.GetBool("logging.requests") {
+		unary = append(unary, middleware.UnaryLogging(viper.GetBool("logging.payloads")))
+		stream = append(stream, middleware.StreamLogging())
+	}
+	// Record mutating calls and refused calls in the audit log, ahead of
+	// authentication so that its failures are recorded too
+	var auditLog *audit.Log
+	if viper.GetBool("audit.enabled") {
+		auditRoot := viper.GetString("audit.data_root")
+		if auditRoot == "" {
+			auditRoot = filepath.Join(viper.GetString("data_dir"), "audit")
+		}
+		var err error
+		auditLog, err = audit.Open(auditRoot, viper.GetString("audit.sink_file"))
+		if err != nil {
+			log.Fatalf("Failed to open audit log: %v", err)
+		}
+		defer auditLog.Close()
+		unary = append(unary, middleware.UnaryAudit(auditLog))
+	}
+	// Without authentication, revealing sensitive fields is all or nothing
+	allowReveal := viper.GetBool("redaction.allow_reveal")
+	revealPolicy := func(ctx context.Context) bool { return allowReveal }
+	if viper.GetBool("auth.enabled") {
+		authenticator, err := newAuthenticator()
+		if err != nil {
+			log.Fatalf("Failed to configure authentication: %v", err)
+		}
+		unary = append(unary, middleware.UnaryAuth(authenticator))
+		stream = append(stream, middleware.StreamAuth(authenticator))
+		revealPolicy = middleware.RevealForAdmins
+	} else {
+		log.Printf("Authentication is disabled, every caller has full access")
+	}
+	unary = append(unary, middleware.UnaryRedaction(revealPolicy))
+	stream = append(stream, middleware.StreamRedaction())
+	serverOptions := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(unary...),
+		grpc.ChainStreamInterceptor(stream...),
+	}
+	if viper.GetBool("tls.enabled") {
+		reloader, err := certs.NewReloader(certs.ServerOptions{
+			CertFile:          viper.GetString("tls.cert_file"),
+			KeyFile:           viper.GetString("tls.key_file"),
+			ClientCAFile:      viper.GetString("tls.client_ca_file"),
+			RequireClientCert: viper.GetBool("tls.require_client_cert"),
+		})
+		if err != nil {
+			log.Fatalf("Failed to load TLS certificates: %v", err)
+		}
+		serverOptions = append(serverOptions, grpc.Creds(credentials.NewTLS(reloader.TLSConfig())))
+	} else {
+		log.Printf("TLS is disabled, serving plaintext gRPC")
+	}
+	server := grpc.NewServer(serverOptions...)
+
+	// Open the environment store
+	dataDir := viper.GetString("data_dir")
+	environmentStore, err := store.New(dataDir)
+	if err != nil {
+		log.Fatalf("Failed to open store in %s: %v", dataDir, err)
+	}
+
+	// Open the revision history of environment specifications
+	revisionRoot := viper.GetString("revisions.data_root")
+	if revisionRoot == "" {
+		revisionRoot = filepath.Join(dataDir, "revisions")
+	}
+	revisions, err := revision.NewStore(revisionRoot)
+	if err != nil {
+		log.Fatalf("Failed to open revision store: %v", err)
+	}
+
+	// Open the user stack templates, next to the built-in ones
+	templateRoot := viper.GetString("templates.data_root")
+	if templateRoot == "" {
+		templateRoot = filepath.Join(dataDir, "templates")
+	}
+	templateRegistry, err := templates.NewRegistry(templateRoot)
+	if err != nil {
+		log.Fatalf("Failed to open template registry: %v", err)
+	}
+
+	// Create the subnet allocator
+	subnets, err := network.NewSubnetAllocator(
+		viper.GetStringSlice("network.subnet_pool"),
+		viper.GetInt("network.subnet_prefix_length"),
+	)
+	if err != nil {
+		log.Fatalf("Failed to create subnet allocator: %v", err)
+	}
+
+	// Load the tenants and register the subnet pools of those that have one
+	var configuredTenants []tenant.Tenant
+	if err := viper.UnmarshalKey("tenants", &configuredTenants); err != nil {
+		log.Fatalf("Failed to read tenants: %v", err)
+	}
+	tenants, err := tenant.NewRegistry(
+		configuredTenants,
+		viper.GetString("containerd.namespace"),
+		viper.GetInt("network.subnet_prefix_length"),
+	)
+	if err != nil {
+		log.Fatalf("Failed to load tenants: %v", err)
+	}
+	for _, name := range tenants.Names() {
+		if t, _ := tenants.Get(name); t.HasOwnPool() {
+			if err := subnets.AddPool(t.Name, t.SubnetPool, t.SubnetPrefixLength); err != nil {
+				log.Fatalf("Failed to add subnet pool of tenant %s: %v", t.Name, err)
+			}
+		}
+	}
+
+	// Create the named volume manager
+	volumeRoot := viper.GetString("volumes.data_root")
+	if volumeRoot == "" {
+		volumeRoot = filepath.Join(dataDir, "volumes")
+	}
+	volumes, err := volume.NewManager(volumeRoot)
+	if err != nil {
+		log.Fatalf("Failed to create volume manager: %v", err)
+	}
+
+	// Create the volume snapshot manager
+	snapshotRoot := viper.GetString("snapshots.data_root")
+	if snapshotRoot == "" {
+		snapshotRoot = filepath.Join(dataDir, "snapshots")
+	}
+	snapshots, err := snapshot.NewManager(snapshotRoot, volumes)
+	if err != nil {
+		log.Fatalf("Failed to create snapshot manager: %v", err)
+	}
+
+	// No container runtime is configured yet. Operations that run containers
+	// fail with FailedPrecondition; everything else works against the stores.
+	var runtime container.Runtime = container.Unavailable{}
+
+	// Create the database backup manager
+	backupRoot := viper.GetString("backups.data_root")
+	if backupRoot == "" {
+		backupRoot = filepath.Join(dataDir, "backups")
+	}
+	backups, err := backup.NewManager(backupRoot, runtime)
+	if err != nil {
+		log.Fatalf("Failed to create backup manager: %v", err)
+	}
+
+	// Open the secret store when a key is configured
+	var secretStore *secrets.Store
+	if keyFile := viper.GetString("secrets.key_file"); keyFile != "" {
+		key, err := secrets.LoadKey(keyFile)
+		if err != nil {
+			log.Fatalf("Failed to load secrets key: %v", err)
+		}
+		secretRoot := viper.GetString("secrets.data_root")
+		if secretRoot == "" {
+			secretRoot = filepath.Join(dataDir, "secrets")
+		}
+		secretStore, err = secrets.NewStore(secretRoot, key)
+		if err != nil {
+			log.Fatalf("Failed to open secret store: %v", err)
+		}
+	}
+
+	// Create the embedded DNS resolvers for environment networks
+	var dnsManager *dns.Manager
+	if viper.GetBool("dns.enabled") {
+		upstreams := viper.GetStringSlice("dns.upstream")
+		if len(upstreams) == 0 {
+			upstreams, err = dns.UpstreamsFromResolvConf("/etc/resolv.conf")
+			if err != nil {
+				log.Printf("Failed to read upstream nameservers: %v", err)
+			}
+		}
+		dnsManager = dns.NewManager(viper.GetInt("dns.port"), upstreams)
+		defer dnsManager.Close()
+	}
+
+	// Read the host-wide quota
+	var hostQuota quota.Resources
+	if err := viper.UnmarshalKey("quota", &hostQuota); err != nil {
+		log.Fatalf("Failed to read quota: %v", err)
+	}
+
+	// Discover the host capacity
+	var node *capacity.Node
+	if viper.GetBool("capacity.enabled") {
+		discovered, err := capacity.Discover(dataDir)
+		if err != nil {
+			log.Fatalf("Failed to discover host capacity: %v", err)
+		}
+		var overcommit capacity.Overcommit
+		if err := viper.UnmarshalKey("capacity.overcommit", &overcommit); err != nil {
+			log.Fatalf("Failed to read overcommit ratios: %v", err)
+		}
+		if err := overcommit.Validate(); err != nil {
+			log.Fatalf("Invalid capacity configuration: %v", err)
+		}
+		node = &capacity.Node{Capacity: discovered, Overcommit: overcommit}
+		log.Printf("Host capacity: %d MB memory, %g CPU cores, %d MB disk", discovered.MemoryMB, discovered.CPUCores, discovered.DiskMB)
+	}
+
+	// Open the admission queue for environments waiting for capacity
+	var queue *admission.Queue
+	if node != nil && viper.GetBool("admission.queue") {
+		queueRoot := viper.GetString("admission.data_root")
+		if queueRoot == "" {
+			queueRoot = filepath.Join(dataDir, "admission")
+		}
+		queue, err = admission.NewQueue(queueRoot)
+		if err != nil {
+			log.Fatalf("Failed to open admission queue: %v", err)
+		}
+	}
 
 	// Create and register the scheduler service
-	schedulerService := service.NewSchedulerService()
+	schedulerService, err := service.NewSchedulerService(service.Options{
+		Runtime:    runtime,
+		Tenants:    tenants,
+		HostQuota:  hostQuota,
+		Node:       node,
+		Queue:      queue,
+		Preemption: viper.GetBool("admission.preemption"),
+		Store:      environmentStore,
+		Subnets:    subnets,
+		DNS:        dnsManager,
+		Volumes:    volumes,
+		Snapshots:  snapshots,
+		Backups:    backups,
+		Secrets:    secretStore,
+		Audit:      auditLog,
+		Revisions:  revisions,
+		Templates:  templateRegistry,
+		ScriptsDir: viper.GetString("database.scripts_dir"),
+	})
+	if err != nil {
+		log.Fatalf("Failed to create scheduler service: %v", err)
+	}
 	pb.RegisterSchedulerServiceServer(server, schedulerService)
 
 	// Create listener
@@ -48,8 +308,18 @@ func runServer(cmd *cobra.Command, args []string) {
 		log.Fatalf("Failed to listen on %s: %v", address, err)
 	}
 
-	// Handle graceful shutdown
-	// TODO: Use context for graceful shutdown of services
+	// Background work stops when the context is canceled on shutdown
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Take scheduled volume snapshots
+	go snapshots.RunSchedule(ctx, time.Minute)
+
+	// Take scheduled database backups
+	go schedulerService.RunBackupSchedule(ctx, time.Minute)
+
+	// Start queued environments as capacity frees up
+	go schedulerService.RunAdmissionQueue(ctx)
 
 	// Start server in goroutine
 	go func() {
@@ -67,6 +337,54 @@ func runServer(cmd *cobra.Command, args []string) {
 	fmt.Println("\nShutting down server...")
 
 	// Graceful shutdown
+	cancel()
 	server.GracefulStop()
 	fmt.Println("Server stopped")
 }
+
+// newAuthenticator builds the authenticator from the API keys and JWT
+// settings in the auth section of the config
+func newAuthenticator() (*auth.Authenticator, error) {
+	var apiKeys []auth.APIKey
+	if err := viper.UnmarshalKey("auth.api_keys", &apiKeys); err != nil {
+		return nil, fmt.Errorf("invalid auth.api_keys: %w", err)
+	}
+
+	var verifier *auth.JWTVerifier
+	secretFile := viper.GetString("auth.jwt.hmac_secret_file")
+	publicKeyFile := viper.GetString("auth.jwt.public_key_file")
+	if secretFile != "" || publicKeyFile != "" {
+		opts := auth.JWTOptions{
+			PublicKeyFile: publicKeyFile,
+			Issuer:        viper.GetString("auth.jwt.issuer"),
+			Audience:      viper.GetString("auth.jwt.audience"),
+			RoleClaim:     viper.GetString("auth.jwt.role_claim"),
+			SelectorClaim: viper.GetString("auth.jwt.selector_claim"),
+			TenantClaim:   viper.GetString("auth.jwt.tenant_claim"),
+		}
+		if secretFile != "" {
+			secret, err := os.ReadFile(secretFile)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read JWT secret: %w", err)
+			}
+			opts.HMACSecret = bytes.TrimSpace(secret)
+		}
+		var err error
+		if verifier, err = auth.NewJWTVerifier(opts); err != nil {
+			return nil, err
+		}
+	}
+
+	var clientCerts []auth.ClientCert
+	if err := viper.UnmarshalKey("auth.client_certs", &clientCerts); err != nil {
+		return nil, fmt.Errorf("invalid auth.client_certs: %w", err)
+	}
+	if len(clientCerts) > 0 && !viper.GetBool("tls.enabled") {
+		return nil, fmt.Errorf("auth.client_certs requires tls.enabled with a client CA")
+	}
+
+	if len(apiKeys) == 0 && len(clientCerts) == 0 && verifier == nil {
+		return nil, fmt.Errorf("auth is enabled but neither API keys, client certificates nor JWT verification are configured")
+	}
+	return auth.NewAuthenticator(apiKeys, clientCerts, verifier)
+}
@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"scheduler/internal/manifest"
+)
+
+// readSpecFile reads an environment from a manifest or a bare specification
+// in YAML or JSON, or from stdin when path is "-". Bare specifications are
+// returned as a manifest without a tenant.
+func readSpecFile(path string) (*manifest.Manifest, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	if !manifest.IsManifest(data) {
+		spec, err := manifest.ParseSpec(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid specification in %s:\n%w", path, err)
+		}
+		return &manifest.Manifest{Spec: spec}, nil
+	}
+	manifests, err := manifest.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid manifest in %s:\n%w", path, err)
+	}
+	if len(manifests) != 1 {
+		return nil, fmt.Errorf("%s holds %d manifests, expected a single one", path, len(manifests))
+	}
+	return manifests[0], nil
+}
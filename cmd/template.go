@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	pb "scheduler/proto/gen"
+)
+
+var templateCmd = &cobra.Command{
+	Use:   "template",
+	Short: "Manage the stack templates of a scheduler server",
+	Long: `Inspect and register the stack templates environments can be created
+from with "scheduler env create NAME --template TEMPLATE".`,
+	PersistentPreRunE: prepareClient,
+}
+
+var templateListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List templates",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return withClient(func(ctx context.Context, client pb.SchedulerServiceClient) error {
+			resp, err := client.ListTemplates(ctx, &pb.ListTemplatesRequest{})
+			if err != nil {
+				return rpcError(err)
+			}
+			if viper.GetString("client.output") != outputTable {
+				return printMessage(os.Stdout, viper.GetString("client.output"), resp)
+			}
+			table := newTable("NAME", "VERSION", "BUILTIN", "PARAMETERS", "DESCRIPTION")
+			for _, t := range resp.GetTemplates() {
+				builtin := ""
+				if t.GetBuiltin() {
+					builtin = "yes"
+				}
+				tableRow(table, t.GetName(), t.GetVersion(), builtin, len(t.GetParameters()), t.GetDescription())
+			}
+			return table.Flush()
+		})
+	},
+}
+
+var templateGetCmd = &cobra.Command{
+	Use:   "get NAME",
+	Short: "Show a template and its parameters",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		version, _ := cmd.Flags().GetInt32("version")
+		return withClient(func(ctx context.Context, client pb.SchedulerServiceClient) error {
+			resp, err := client.GetTemplate(ctx, &pb.GetTemplateRequest{Name: args[0], Version: version})
+			if err != nil {
+				return rpcError(err)
+			}
+			if viper.GetString("client.output") != outputTable {
+				return printMessage(os.Stdout, viper.GetString("client.output"), resp)
+			}
+			t := resp.GetTemplate()
+			fmt.Printf("%s version %d: %s\n\n", t.GetName(), t.GetVersion(), t.GetDescription())
+			table := newTable("PARAMETER", "TYPE", "DEFAULT", "REQUIRED", "PATTERN", "DESCRIPTION")
+			for _, parameter := range t.GetParameters() {
+				required := ""
+				if parameter.GetRequired() {
+					required = "yes"
+				}
+				tableRow(table, parameter.GetName(), strings.ToLower(enumName(parameter.GetType(), "PARAMETER_TYPE_")),
+					parameter.GetDefaultValue(), required, parameter.GetPattern(), parameter.GetDescription())
+			}
+			return table.Flush()
+		})
+	},
+}
+
+var templateRegisterCmd = &cobra.Command{
+	Use:   "register -f template.yaml",
+	Short: "Register a template, or a new version of it",
+	Long: `Register a template manifest of kind Template, from stdin when the file
+is "-". Registering a name again adds a version; environments keep the
+version they were created from.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		file, _ := cmd.Flags().GetString("file")
+		if file == "" {
+			return errors.New("--file is required")
+		}
+		var data []byte
+		var err error
+		if file == "-" {
+			data, err = io.ReadAll(os.Stdin)
+		} else {
+			data, err = os.ReadFile(file)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		return withClient(func(ctx context.Context, client pb.SchedulerServiceClient) error {
+			resp, err := client.RegisterTemplate(ctx, &pb.RegisterTemplateRequest{Manifest: string(data)})
+			if err != nil {
+				return rpcError(err)
+			}
+			if viper.GetString("client.output") != outputTable {
+				return printMessage(os.Stdout, viper.GetString("client.output"), resp)
+			}
+			fmt.Printf("Registered template %s version %d\n", resp.GetTemplate().GetName(), resp.GetTemplate().GetVersion())
+			return nil
+		})
+	},
+}
+
+var templateDeleteCmd = &cobra.Command{
+	Use:   "delete NAME",
+	Short: "Delete every version of a template",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return withClient(func(ctx context.Context, client pb.SchedulerServiceClient) error {
+			resp, err := client.DeleteTemplate(ctx, &pb.DeleteTemplateRequest{Name: args[0]})
+			if err != nil {
+				return rpcError(err)
+			}
+			if viper.GetString("client.output") != outputTable {
+				return printMessage(os.Stdout, viper.GetString("client.output"), resp)
+			}
+			fmt.Printf("Deleted template %s\n", args[0])
+			return nil
+		})
+	},
+}
+
+func init() {
+	addClientFlags(templateCmd)
+	templateGetCmd.Flags().Int32("version", 0, "version to show, the latest when 0")
+	templateRegisterCmd.Flags().StringP("file", "f", "", "template manifest, - for stdin")
+	templateCmd.AddCommand(templateListCmd, templateGetCmd, templateRegisterCmd, templateDeleteCmd)
+	rootCmd.AddCommand(templateCmd)
+}
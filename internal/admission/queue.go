@@ -0,0 +1,152 @@
+package admission
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"scheduler/internal/fsutil"
+)
+
+// queueFile holds the persisted queue inside the queue root
+const queueFile = "queue.json"
+
+// Entry is an environment waiting for host capacity
+type Entry struct {
+	EnvironmentID string    `json:"environment_id"`
+	Priority      int32     `json:"priority"`
+	EnqueuedAt    time.Time `json:"enqueued_at"`
+	// Sequence orders entries of equal priority by arrival
+	Sequence uint64 `json:"sequence"`
+}
+
+// Queue orders environments waiting to start by descending priority and, within
+// a priority, by arrival. Every change is persisted so that the queue survives
+// restarts.
+type Queue struct {
+	mu       sync.Mutex
+	path     string
+	entries  []Entry
+	sequence uint64
+}
+
+// NewQueue opens the queue persisted in root, creating it if necessary
+func NewQueue(root string) (*Queue, error) {
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create admission queue directory %s: %w", root, err)
+	}
+	q := &Queue{path: filepath.Join(root, queueFile)}
+
+	data, err := os.ReadFile(q.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return q, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read admission queue: %w", err)
+	}
+	if err := json.Unmarshal(data, &q.entries); err != nil {
+		return nil, fmt.Errorf("failed to parse admission queue %s: %w", q.path, err)
+	}
+	for _, entry := range q.entries {
+		q.sequence = max(q.sequence, entry.Sequence)
+	}
+	q.sort()
+	return q, nil
+}
+
+// Push queues an environment. Environments already queued keep their place
+// among entries of their priority, but move when their priority changes.
+func (q *Queue) Push(environmentID string, priority int32) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for i, entry := range q.entries {
+		if entry.EnvironmentID == environmentID {
+			if entry.Priority == priority {
+				return nil
+			}
+			q.entries[i].Priority = priority
+			q.sort()
+			return q.save()
+		}
+	}
+	q.sequence++
+	q.entries = append(q.entries, Entry{
+		EnvironmentID: environmentID,
+		Priority:      priority,
+		EnqueuedAt:    time.Now().UTC(),
+		Sequence:      q.sequence,
+	})
+	q.sort()
+	return q.save()
+}
+
+// Remove drops an environment from the queue, reporting whether it was queued
+func (q *Queue) Remove(environmentID string) (bool, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for i, entry := range q.entries {
+		if entry.EnvironmentID == environmentID {
+			q.entries = append(q.entries[:i], q.entries[i+1:]...)
+			return true, q.save()
+		}
+	}
+	return false, nil
+}
+
+// Position returns the 1-based position of an environment in the queue, or
+// 0 when it is not queued
+func (q *Queue) Position(environmentID string) int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for i, entry := range q.entries {
+		if entry.EnvironmentID == environmentID {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// Entries returns the queued environments in admission order
+func (q *Queue) Entries() []Entry {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return append([]Entry(nil), q.entries...)
+}
+
+// Ahead reports whether an environment of the given priority would have to
+// wait behind queued entries
+func (q *Queue) Ahead(priority int32) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return len(q.entries) > 0 && q.entries[0].Priority >= priority
+}
+
+func (q *Queue) sort() {
+	sort.SliceStable(q.entries, func(i, j int) bool {
+		if q.entries[i].Priority != q.entries[j].Priority {
+			return q.entries[i].Priority > q.entries[j].Priority
+		}
+		return q.entries[i].Sequence < q.entries[j].Sequence
+	})
+}
+
+func (q *Queue) save() error {
+	data, err := json.MarshalIndent(q.entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode admission queue: %w", err)
+	}
+	if err := fsutil.WriteFileAtomic(q.path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write admission queue: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package admission
+
+import (
+	"reflect"
+	"testing"
+)
+
+// ids lists the environments of the queue in admission order
+func ids(q *Queue) []string {
+	var ids []string
+	for _, entry := range q.Entries() {
+		ids = append(ids, entry.EnvironmentID)
+	}
+	return ids
+}
+
+func TestQueueOrder(t *testing.T) {
+	q, err := NewQueue(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, push := range []struct {
+		id       string
+		priority int32
+	}{
+		{"low", 0},
+		{"high", 10},
+		{"mid-1", 5},
+		{"mid-2", 5},
+		// Pushing again keeps the place among equal priorities
+		{"mid-1", 5},
+	} {
+		if err := q.Push(push.id, push.priority); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got, want := ids(q), []string{"high", "mid-1", "mid-2", "low"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("queue = %v, want %v", got, want)
+	}
+
+	// A changed priority moves the entry, which arrived before the others
+	// of its new priority
+	if err := q.Push("low", 5); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := ids(q), []string{"high", "low", "mid-1", "mid-2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("queue after a priority change = %v, want %v", got, want)
+	}
+	if q.Position("mid-2") != 4 || q.Position("missing") != 0 {
+		t.Errorf("positions = %d and %d, want 4 and 0", q.Position("mid-2"), q.Position("missing"))
+	}
+
+	if !q.Ahead(10) || q.Ahead(11) {
+		t.Error("Ahead() does not compare against the first entry")
+	}
+	removed, err := q.Remove("high")
+	if err != nil || !removed {
+		t.Fatalf("Remove(high) = %v, %v", removed, err)
+	}
+	if removed, _ := q.Remove("high"); removed {
+		t.Error("Remove() of an entry that is not queued reported it removed")
+	}
+}
+
+func TestQueuePersists(t *testing.T) {
+	root := t.TempDir()
+	q, err := NewQueue(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"first", "second"} {
+		if err := q.Push(id, 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	reopened, err := NewQueue(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := ids(reopened), []string{"first", "second"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("reopened queue = %v, want %v", got, want)
+	}
+	// New entries still queue behind the restored ones
+	if err := reopened.Push("third", 1); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := ids(reopened), []string{"first", "second", "third"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("queue = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,186 @@
+package audit
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+
+	pb "scheduler/proto/gen"
+)
+
+// eventsFile holds the events inside the audit root, one JSON object per line
+const eventsFile = "events.jsonl"
+
+// Filter selects audit events. Empty fields match every event. Tenant
+// selects the calls that targeted the tenant or were made by its callers.
+type Filter struct {
+	EnvironmentID string
+	Subject       string
+	Tenant        string
+	// Since is inclusive and Until exclusive
+	Since time.Time
+	Until time.Time
+}
+
+// Matches reports whether event is selected by f
+func (f Filter) Matches(event *pb.AuditEvent) bool {
+	switch {
+	case f.EnvironmentID != "" && event.GetEnvironmentId() != f.EnvironmentID:
+		return false
+	case f.Subject != "" && event.GetSubject() != f.Subject:
+		return false
+	case f.Tenant != "" && event.GetTenant() != f.Tenant && event.GetCallerTenant() != f.Tenant:
+		return false
+	case !f.Since.IsZero() && event.GetTime().AsTime().Before(f.Since):
+		return false
+	case !f.Until.IsZero() && !event.GetTime().AsTime().Before(f.Until):
+		return false
+	}
+	return true
+}
+
+// Log is an append-only store of audit events. Events are appended to a JSON
+// lines file, and optionally to a sink file for shipping to a SIEM, and kept
+// in memory for queries.
+type Log struct {
+	mu     sync.Mutex
+	file   *os.File
+	sink   *os.File
+	events []*pb.AuditEvent
+}
+
+// Open opens the audit log in root, loading the events recorded so far. When
+// sinkPath is set every event is appended to that file as well.
+func Open(root, sinkPath string) (*Log, error) {
+	if err := os.MkdirAll(root, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create audit directory %s: %w", root, err)
+	}
+	path := filepath.Join(root, eventsFile)
+
+	l := &Log{}
+	if err := l.load(path); err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o640)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log %s: %w", path, err)
+	}
+	l.file = file
+
+	if sinkPath != "" {
+		sink, err := os.OpenFile(sinkPath, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o640)
+		if err != nil {
+			file.Close()
+			return nil, fmt.Errorf("failed to open audit sink %s: %w", sinkPath, err)
+		}
+		l.sink = sink
+	}
+	return l, nil
+}
+
+func (l *Log) load(path string) error {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read audit log %s: %w", path, err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		event := &pb.AuditEvent{}
+		if err := protojson.Unmarshal(scanner.Bytes(), event); err != nil {
+			return fmt.Errorf("failed to parse audit log %s line %d: %w", path, line, err)
+		}
+		l.events = append(l.events, event)
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read audit log %s: %w", path, err)
+	}
+	return nil
+}
+
+// Append records an event, assigning its ID. The event is synced to disk
+// before Append returns.
+func (l *Log) Append(event *pb.AuditEvent) error {
+	id, err := newEventID()
+	if err != nil {
+		return fmt.Errorf("failed to generate audit event ID: %w", err)
+	}
+	event.Id = id
+	data, err := protojson.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit event: %w", err)
+	}
+	data = append(data, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, err := l.file.Write(data); err != nil {
+		return fmt.Errorf("failed to write audit event: %w", err)
+	}
+	if err := l.file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync audit log: %w", err)
+	}
+	l.events = append(l.events, event)
+
+	if l.sink != nil {
+		if _, err := l.sink.Write(data); err != nil {
+			return fmt.Errorf("failed to write audit event to sink: %w", err)
+		}
+	}
+	return nil
+}
+
+// List returns the events selected by filter, newest first
+func (l *Log) List(filter Filter) []*pb.AuditEvent {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var matched []*pb.AuditEvent
+	for i := len(l.events) - 1; i >= 0; i-- {
+		if filter.Matches(l.events[i]) {
+			matched = append(matched, l.events[i])
+		}
+	}
+	return matched
+}
+
+// Close closes the audit log and sink files
+func (l *Log) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	err := l.file.Close()
+	if l.sink != nil {
+		if sinkErr := l.sink.Close(); err == nil {
+			err = sinkErr
+		}
+	}
+	return err
+}
+
+// newEventID generates a random audit event identifier
+func newEventID() (string, error) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return "evt-" + hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,92 @@
+package audit
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "scheduler/proto/gen"
+)
+
+func TestAppendAndReopen(t *testing.T) {
+	root := t.TempDir()
+	sinkPath := filepath.Join(t.TempDir(), "sink.jsonl")
+	l, err := Open(root, sinkPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, method := range []string{"CreateEnvironment", "StartEnvironment"} {
+		if err := l.Append(&pb.AuditEvent{Method: method, Subject: "ops"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	sink, err := os.ReadFile(sinkPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Count(string(sink), "\n"); lines != 2 {
+		t.Errorf("sink holds %d lines, want 2", lines)
+	}
+
+	reopened, err := Open(root, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reopened.Close()
+	events := reopened.List(Filter{})
+	if len(events) != 2 || events[0].GetMethod() != "StartEnvironment" || events[1].GetMethod() != "CreateEnvironment" {
+		t.Fatalf("List() after reopening = %v, want both events newest first", events)
+	}
+	if !strings.HasPrefix(events[0].GetId(), "evt-") || events[0].GetId() == events[1].GetId() {
+		t.Errorf("event IDs = %s, %s, want distinct evt- IDs", events[0].GetId(), events[1].GetId())
+	}
+}
+
+func TestOpenRejectsCorruptLog(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, eventsFile), []byte("{\"method\":\"a\"}\nnot json\n"), 0o640); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Open(root, ""); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("Open() error = %v, want one naming line 2", err)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	event := &pb.AuditEvent{
+		Time:          timestamppb.New(start),
+		EnvironmentId: "env-1",
+		Subject:       "ops",
+		Tenant:        "team-a",
+	}
+	tests := []struct {
+		name   string
+		filter Filter
+		want   bool
+	}{
+		{name: "empty", filter: Filter{}, want: true},
+		{name: "all fields", filter: Filter{EnvironmentID: "env-1", Subject: "ops", Tenant: "team-a"}, want: true},
+		{name: "other environment", filter: Filter{EnvironmentID: "env-2"}},
+		{name: "other subject", filter: Filter{Subject: "ci"}},
+		{name: "other tenant", filter: Filter{Tenant: "team-b"}},
+		{name: "since is inclusive", filter: Filter{Since: start}, want: true},
+		{name: "until is exclusive", filter: Filter{Until: start}},
+		{name: "within the window", filter: Filter{Since: start.Add(-time.Hour), Until: start.Add(time.Hour)}, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Matches(event); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
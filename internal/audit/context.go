@@ -0,0 +1,86 @@
+package audit
+
+import (
+	"context"
+	"sync"
+
+	pb "scheduler/proto/gen"
+)
+
+// Call is the event of a call being audited. The audit interceptor runs
+// before authentication and the handler, which fill in what only they learn
+// through the context: the caller and the tenant the call targets.
+type Call struct {
+	mu    sync.Mutex
+	event *pb.AuditEvent
+	// authFailed is set when authentication or authorization refused the call
+	authFailed bool
+}
+
+// NewCall starts the audit record of a call
+func NewCall(event *pb.AuditEvent) *Call {
+	return &Call{event: event}
+}
+
+// Event returns the audit event of the call
+func (c *Call) Event() *pb.AuditEvent {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.event
+}
+
+// AuthFailed reports whether authentication or authorization refused the call
+func (c *Call) AuthFailed() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.authFailed
+}
+
+type callKey struct{}
+
+// NewContext returns a context carrying the audit record of a call
+func NewContext(ctx context.Context, call *Call) context.Context {
+	return context.WithValue(ctx, callKey{}, call)
+}
+
+// SetCaller records the authenticated caller of a call
+func SetCaller(ctx context.Context, subject, role, tenant string) {
+	update(ctx, func(c *Call) {
+		c.event.Subject, c.event.Role, c.event.CallerTenant = subject, role, tenant
+	})
+}
+
+// SetClientCommonName records the common name of the verified client
+// certificate a call was made with
+func SetClientCommonName(ctx context.Context, commonName string) {
+	update(ctx, func(c *Call) { c.event.ClientCommonName = commonName })
+}
+
+// SetAuthFailed marks a call refused by authentication or authorization
+func SetAuthFailed(ctx context.Context) {
+	update(ctx, func(c *Call) { c.authFailed = true })
+}
+
+// SetTenant records the tenant of the environment, volume or secret a call
+// targets. The first tenant recorded wins.
+func SetTenant(ctx context.Context, tenant string) {
+	update(ctx, func(c *Call) {
+		if c.event.Tenant == "" {
+			c.event.Tenant = tenant
+		}
+	})
+}
+
+// update changes the audit record of the call of ctx, if it is audited
+func update(ctx context.Context, change func(*Call)) {
+	call, ok := ctx.Value(callKey{}).(*Call)
+	if !ok {
+		return
+	}
+	call.mu.Lock()
+	defer call.mu.Unlock()
+
+	change(call)
+}
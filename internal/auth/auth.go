@@ -0,0 +1,164 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrUnauthenticated is returned when a token is missing, unknown or invalid
+var ErrUnauthenticated = errors.New("invalid or missing credentials")
+
+// Identity is an authenticated caller
+type Identity struct {
+	// Subject names the caller, the API key name or the JWT subject
+	Subject string
+	Role    Role
+	// Tenant binds the caller to one tenant, empty allows every tenant
+	Tenant string
+	// Selector restricts the caller to environments whose labels contain
+	// every key/value pair, empty allows every environment
+	Selector map[string]string
+}
+
+// Matches reports whether the caller may touch a resource with labels
+func (i *Identity) Matches(labels map[string]string) bool {
+	for key, value := range i.Selector {
+		if labels[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+type identityKey struct{}
+
+// NewContext returns a context carrying identity
+func NewContext(ctx context.Context, identity *Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+// FromContext returns the identity of the caller, if authentication is enabled
+func FromContext(ctx context.Context) (*Identity, bool) {
+	identity, ok := ctx.Value(identityKey{}).(*Identity)
+	return identity, ok
+}
+
+// APIKey is a static bearer token from the config file. Either Key or its
+// hex encoded SHA-256 digest in KeySHA256 must be set.
+type APIKey struct {
+	Name      string            `mapstructure:"name"`
+	Key       string            `mapstructure:"key"`
+	KeySHA256 string            `mapstructure:"key_sha256"`
+	Role      string            `mapstructure:"role"`
+	Tenant    string            `mapstructure:"tenant"`
+	Selector  map[string]string `mapstructure:"selector"`
+}
+
+// ClientCert maps the common name of a client certificate, verified against
+// the client CA by mutual TLS, to an identity
+type ClientCert struct {
+	CommonName string            `mapstructure:"common_name"`
+	Role       string            `mapstructure:"role"`
+	Tenant     string            `mapstructure:"tenant"`
+	Selector   map[string]string `mapstructure:"selector"`
+}
+
+// Authenticator resolves bearer tokens and client certificates to
+// identities. Tokens are looked up among the static API keys first and
+// verified as JWTs otherwise.
+type Authenticator struct {
+	keys  map[[sha256.Size]byte]*Identity
+	certs map[string]*Identity
+	jwt   *JWTVerifier
+}
+
+// NewAuthenticator creates an authenticator for the given API keys, client
+// certificates and, when jwt is not nil, JWTs
+func NewAuthenticator(keys []APIKey, certs []ClientCert, jwt *JWTVerifier) (*Authenticator, error) {
+	a := &Authenticator{
+		keys:  make(map[[sha256.Size]byte]*Identity),
+		certs: make(map[string]*Identity),
+		jwt:   jwt,
+	}
+	for _, key := range keys {
+		if key.Name == "" {
+			return nil, errors.New("API key name is required")
+		}
+		role, err := ParseRole(key.Role)
+		if err != nil {
+			return nil, fmt.Errorf("API key %s: %w", key.Name, err)
+		}
+
+		var digest [sha256.Size]byte
+		switch {
+		case key.Key != "" && key.KeySHA256 != "":
+			return nil, fmt.Errorf("API key %s: set either key or key_sha256", key.Name)
+		case key.Key != "":
+			digest = sha256.Sum256([]byte(key.Key))
+		case key.KeySHA256 != "":
+			decoded, err := hex.DecodeString(key.KeySHA256)
+			if err != nil || len(decoded) != sha256.Size {
+				return nil, fmt.Errorf("API key %s: key_sha256 must be a hex encoded SHA-256 digest", key.Name)
+			}
+			copy(digest[:], decoded)
+		default:
+			return nil, fmt.Errorf("API key %s: key or key_sha256 is required", key.Name)
+		}
+		if _, ok := a.keys[digest]; ok {
+			return nil, fmt.Errorf("API key %s is configured twice", key.Name)
+		}
+		a.keys[digest] = &Identity{Subject: key.Name, Role: role, Tenant: key.Tenant, Selector: key.Selector}
+	}
+	for _, cert := range certs {
+		if cert.CommonName == "" {
+			return nil, errors.New("client certificate common_name is required")
+		}
+		role, err := ParseRole(cert.Role)
+		if err != nil {
+			return nil, fmt.Errorf("client certificate %s: %w", cert.CommonName, err)
+		}
+		if _, ok := a.certs[cert.CommonName]; ok {
+			return nil, fmt.Errorf("client certificate %s is configured twice", cert.CommonName)
+		}
+		a.certs[cert.CommonName] = &Identity{Subject: cert.CommonName, Role: role, Tenant: cert.Tenant, Selector: cert.Selector}
+	}
+	return a, nil
+}
+
+// AuthenticateClientCert resolves the common name of a verified client
+// certificate to the identity configured for it
+func (a *Authenticator) AuthenticateClientCert(commonName string) (*Identity, error) {
+	identity, ok := a.certs[commonName]
+	if !ok {
+		return nil, fmt.Errorf("%w: no identity for client certificate %s", ErrUnauthenticated, commonName)
+	}
+	return identity, nil
+}
+
+// Authenticate resolves a bearer token to the identity it belongs to
+func (a *Authenticator) Authenticate(token string) (*Identity, error) {
+	if token == "" {
+		return nil, ErrUnauthenticated
+	}
+
+	digest := sha256.Sum256([]byte(token))
+	for candidate, identity := range a.keys {
+		if subtle.ConstantTimeCompare(candidate[:], digest[:]) == 1 {
+			return identity, nil
+		}
+	}
+
+	if a.jwt != nil && strings.Count(token, ".") == 2 {
+		identity, err := a.jwt.Verify(token)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrUnauthenticated, err)
+		}
+		return identity, nil
+	}
+	return nil, ErrUnauthenticated
+}
@@ -0,0 +1,114 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAuthenticate(t *testing.T) {
+	digest := sha256.Sum256([]byte("hashed-key"))
+	verifier := newTestVerifier(t, JWTOptions{HMACSecret: testSecret})
+	verifier.now = time.Now
+	a, err := NewAuthenticator(
+		[]APIKey{
+			{Name: "ops", Key: "ops-key", Role: "admin"},
+			{Name: "ci", KeySHA256: hex.EncodeToString(digest[:]), Role: "deployer", Tenant: "team-a"},
+		},
+		[]ClientCert{{CommonName: "runner", Role: "viewer"}},
+		verifier,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims := map[string]any{"sub": "jwt-user", "role": "viewer", "exp": time.Now().Add(time.Hour).Unix()}
+
+	tests := []struct {
+		name        string
+		token       string
+		wantSubject string
+		wantRole    Role
+	}{
+		{name: "plain key", token: "ops-key", wantSubject: "ops", wantRole: RoleAdmin},
+		{name: "hashed key", token: "hashed-key", wantSubject: "ci", wantRole: RoleDeployer},
+		{name: "JWT", token: signToken(t, "HS256", claims, signHMAC(testSecret)), wantSubject: "jwt-user", wantRole: RoleViewer},
+		{name: "unknown key", token: "other-key"},
+		{name: "empty token"},
+		{name: "invalid JWT", token: signToken(t, "HS256", claims, signHMAC([]byte("fedcba9876543210fedcba9876543210")))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			identity, err := a.Authenticate(tt.token)
+			if tt.wantSubject == "" {
+				if !errors.Is(err, ErrUnauthenticated) {
+					t.Errorf("Authenticate() error = %v, want ErrUnauthenticated", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if identity.Subject != tt.wantSubject || identity.Role != tt.wantRole {
+				t.Errorf("Authenticate() = %+v, want %s as %s", identity, tt.wantSubject, tt.wantRole)
+			}
+		})
+	}
+
+	if identity, err := a.AuthenticateClientCert("runner"); err != nil || identity.Role != RoleViewer {
+		t.Errorf("AuthenticateClientCert(runner) = %+v, %v", identity, err)
+	}
+	if _, err := a.AuthenticateClientCert("stranger"); !errors.Is(err, ErrUnauthenticated) {
+		t.Errorf("AuthenticateClientCert(stranger) error = %v, want ErrUnauthenticated", err)
+	}
+}
+
+func TestNewAuthenticatorRejects(t *testing.T) {
+	tests := []struct {
+		name  string
+		keys  []APIKey
+		certs []ClientCert
+	}{
+		{name: "unnamed key", keys: []APIKey{{Key: "k", Role: "admin"}}},
+		{name: "unknown role", keys: []APIKey{{Name: "a", Key: "k", Role: "root"}}},
+		{name: "key and digest", keys: []APIKey{{Name: "a", Key: "k", KeySHA256: "00", Role: "admin"}}},
+		{name: "no key", keys: []APIKey{{Name: "a", Role: "admin"}}},
+		{name: "malformed digest", keys: []APIKey{{Name: "a", KeySHA256: "abc", Role: "admin"}}},
+		{name: "duplicate key", keys: []APIKey{{Name: "a", Key: "k", Role: "admin"}, {Name: "b", Key: "k", Role: "viewer"}}},
+		{name: "certificate without common name", certs: []ClientCert{{Role: "admin"}}},
+		{name: "duplicate certificate", certs: []ClientCert{{CommonName: "ci", Role: "admin"}, {CommonName: "ci", Role: "viewer"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewAuthenticator(tt.keys, tt.certs, nil); err == nil {
+				t.Error("NewAuthenticator() succeeded, want an error")
+			}
+		})
+	}
+}
+
+func TestIdentityMatches(t *testing.T) {
+	identity := &Identity{Selector: map[string]string{"team": "a", "tier": "dev"}}
+	if !identity.Matches(map[string]string{"team": "a", "tier": "dev", "app": "web"}) {
+		t.Error("labels containing the selector do not match")
+	}
+	if identity.Matches(map[string]string{"team": "a"}) {
+		t.Error("labels missing a selector key match")
+	}
+	if !(&Identity{}).Matches(nil) {
+		t.Error("an empty selector does not match everything")
+	}
+}
+
+func TestRequiredRole(t *testing.T) {
+	if got := RequiredRole("/scheduler.v1.SchedulerService/GetEnvironment"); got != RoleViewer {
+		t.Errorf("RequiredRole(GetEnvironment) = %s, want viewer", got)
+	}
+	if got := RequiredRole("/scheduler.v1.SchedulerService/DeleteSecret"); got != RoleAdmin {
+		t.Errorf("RequiredRole(DeleteSecret) = %s, want admin", got)
+	}
+	if got := RequiredRole("/scheduler.v1.SchedulerService/SomethingNew"); got != RoleAdmin {
+		t.Errorf("RequiredRole() of an unlisted RPC = %s, want admin", got)
+	}
+}
@@ -0,0 +1,21 @@
+package auth
+
+import "context"
+
+// BearerToken attaches a bearer token to every call of a client connection.
+// It implements credentials.PerRPCCredentials.
+type BearerToken struct {
+	Token string
+	// Insecure allows sending the token over a plaintext connection
+	Insecure bool
+}
+
+// GetRequestMetadata implements credentials.PerRPCCredentials
+func (t BearerToken) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + t.Token}, nil
+}
+
+// RequireTransportSecurity implements credentials.PerRPCCredentials
+func (t BearerToken) RequireTransportSecurity() bool {
+	return !t.Insecure
+}
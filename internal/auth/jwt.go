@@ -0,0 +1,267 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// clockSkew is tolerated when checking expiry and not-before times
+const clockSkew = time.Minute
+
+// JWTOptions configures how JWTs are verified. Exactly one of HMACSecret and
+// PublicKeyFile must be set.
+type JWTOptions struct {
+	// HMACSecret verifies HS256 tokens
+	HMACSecret []byte
+	// PublicKeyFile holds a PEM encoded RSA or ECDSA P-256 public key or
+	// certificate verifying RS256 or ES256 tokens
+	PublicKeyFile string
+	// Issuer and Audience are checked against the iss and aud claims when set
+	Issuer   string
+	Audience string
+	// RoleClaim names the claim holding the role, defaults to "role"
+	RoleClaim string
+	// SelectorClaim names the claim holding the label selector object,
+	// defaults to "selector"
+	SelectorClaim string
+	// TenantClaim names the claim holding the tenant, defaults to "tenant"
+	TenantClaim string
+}
+
+// JWTVerifier verifies JWTs locally, without contacting the issuer
+type JWTVerifier struct {
+	opts      JWTOptions
+	algorithm string
+	publicKey crypto.PublicKey
+	now       func() time.Time
+}
+
+// NewJWTVerifier creates a verifier from opts
+func NewJWTVerifier(opts JWTOptions) (*JWTVerifier, error) {
+	if opts.RoleClaim == "" {
+		opts.RoleClaim = "role"
+	}
+	if opts.SelectorClaim == "" {
+		opts.SelectorClaim = "selector"
+	}
+	if opts.TenantClaim == "" {
+		opts.TenantClaim = "tenant"
+	}
+	v := &JWTVerifier{opts: opts, now: time.Now}
+
+	switch {
+	case len(opts.HMACSecret) > 0 && opts.PublicKeyFile != "":
+		return nil, errors.New("set either an HMAC secret or a public key for JWTs, not both")
+	case len(opts.HMACSecret) > 0:
+		if len(opts.HMACSecret) < sha256.Size {
+			return nil, fmt.Errorf("JWT HMAC secret must be at least %d bytes", sha256.Size)
+		}
+		v.algorithm = "HS256"
+	case opts.PublicKeyFile != "":
+		key, err := loadPublicKey(opts.PublicKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		switch key := key.(type) {
+		case *rsa.PublicKey:
+			v.algorithm = "RS256"
+		case *ecdsa.PublicKey:
+			if key.Curve != elliptic.P256() {
+				return nil, errors.New("ECDSA JWT keys must use the P-256 curve")
+			}
+			v.algorithm = "ES256"
+		default:
+			return nil, fmt.Errorf("unsupported JWT public key type %T", key)
+		}
+		v.publicKey = key
+	default:
+		return nil, errors.New("JWTs need an HMAC secret or a public key")
+	}
+	return v, nil
+}
+
+// Verify checks the signature and claims of a token and returns the identity
+// it carries
+func (v *JWTVerifier) Verify(token string) (*Identity, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("malformed token")
+	}
+
+	var header struct {
+		Algorithm string `json:"alg"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("malformed token header: %w", err)
+	}
+	// The algorithm is pinned by the configured key, never chosen by the token
+	if header.Algorithm != v.algorithm {
+		return nil, fmt.Errorf("unexpected signing algorithm %q", header.Algorithm)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("malformed token signature: %w", err)
+	}
+	if err := v.verifySignature(parts[0]+"."+parts[1], signature); err != nil {
+		return nil, err
+	}
+
+	claims := make(map[string]json.RawMessage)
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("malformed token claims: %w", err)
+	}
+	if err := v.checkClaims(claims); err != nil {
+		return nil, err
+	}
+
+	identity := &Identity{}
+	if raw, ok := claims["sub"]; ok {
+		json.Unmarshal(raw, &identity.Subject)
+	}
+	var roleName string
+	if err := json.Unmarshal(claims[v.opts.RoleClaim], &roleName); err != nil {
+		return nil, fmt.Errorf("token has no %s claim", v.opts.RoleClaim)
+	}
+	if identity.Role, err = ParseRole(roleName); err != nil {
+		return nil, err
+	}
+	if raw, ok := claims[v.opts.TenantClaim]; ok {
+		if err := json.Unmarshal(raw, &identity.Tenant); err != nil {
+			return nil, fmt.Errorf("token claim %s must be a string", v.opts.TenantClaim)
+		}
+	}
+	if raw, ok := claims[v.opts.SelectorClaim]; ok {
+		if err := json.Unmarshal(raw, &identity.Selector); err != nil {
+			return nil, fmt.Errorf("token claim %s must map labels to values", v.opts.SelectorClaim)
+		}
+	}
+	return identity, nil
+}
+
+func (v *JWTVerifier) verifySignature(signed string, signature []byte) error {
+	digest := sha256.Sum256([]byte(signed))
+	switch v.algorithm {
+	case "HS256":
+		mac := hmac.New(sha256.New, v.opts.HMACSecret)
+		mac.Write([]byte(signed))
+		if !hmac.Equal(mac.Sum(nil), signature) {
+			return errors.New("invalid token signature")
+		}
+	case "RS256":
+		if err := rsa.VerifyPKCS1v15(v.publicKey.(*rsa.PublicKey), crypto.SHA256, digest[:], signature); err != nil {
+			return errors.New("invalid token signature")
+		}
+	case "ES256":
+		// JWS encodes ECDSA signatures as the fixed size concatenation of r and s
+		if len(signature) != 64 {
+			return errors.New("invalid token signature")
+		}
+		r := new(big.Int).SetBytes(signature[:32])
+		s := new(big.Int).SetBytes(signature[32:])
+		if !ecdsa.Verify(v.publicKey.(*ecdsa.PublicKey), digest[:], r, s) {
+			return errors.New("invalid token signature")
+		}
+	}
+	return nil
+}
+
+// checkClaims validates the registered time, issuer and audience claims.
+// Tokens must expire.
+func (v *JWTVerifier) checkClaims(claims map[string]json.RawMessage) error {
+	now := v.now()
+
+	var expiry float64
+	if err := json.Unmarshal(claims["exp"], &expiry); err != nil {
+		return errors.New("token has no exp claim")
+	}
+	if now.After(time.Unix(int64(expiry), 0).Add(clockSkew)) {
+		return errors.New("token has expired")
+	}
+	if raw, ok := claims["nbf"]; ok {
+		var notBefore float64
+		if err := json.Unmarshal(raw, &notBefore); err != nil {
+			return errors.New("malformed nbf claim")
+		}
+		if now.Add(clockSkew).Before(time.Unix(int64(notBefore), 0)) {
+			return errors.New("token is not valid yet")
+		}
+	}
+
+	if v.opts.Issuer != "" {
+		var issuer string
+		json.Unmarshal(claims["iss"], &issuer)
+		if issuer != v.opts.Issuer {
+			return fmt.Errorf("unexpected token issuer %q", issuer)
+		}
+	}
+	if v.opts.Audience != "" {
+		// aud is either a single string or a list of strings
+		var audiences []string
+		if err := json.Unmarshal(claims["aud"], &audiences); err != nil {
+			var audience string
+			json.Unmarshal(claims["aud"], &audience)
+			audiences = []string{audience}
+		}
+		if !slices.Contains(audiences, v.opts.Audience) {
+			return errors.New("token is not meant for this audience")
+		}
+	}
+	return nil
+}
+
+func decodeSegment(segment string, into any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, into)
+}
+
+// loadPublicKey reads a PEM encoded public key or certificate
+func loadPublicKey(path string) (crypto.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JWT public key %s: %w", path, err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data found in %s", path)
+	}
+
+	switch block.Type {
+	case "CERTIFICATE":
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse certificate %s: %w", path, err)
+		}
+		return cert.PublicKey, nil
+	case "RSA PUBLIC KEY":
+		key, err := x509.ParsePKCS1PublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse public key %s: %w", path, err)
+		}
+		return key, nil
+	default:
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse public key %s: %w", path, err)
+		}
+		return key, nil
+	}
+}
@@ -0,0 +1,281 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var (
+	testSecret = []byte("0123456789abcdef0123456789abcdef")
+	testNow    = time.Unix(1_700_000_000, 0)
+)
+
+// signToken encodes claims into a token signed by sign under the algorithm alg
+func signToken(t *testing.T, alg string, claims map[string]any, sign func(signed string) []byte) string {
+	t.Helper()
+	segment := func(value any) string {
+		data, err := json.Marshal(value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+	signed := segment(map[string]string{"alg": alg, "typ": "JWT"}) + "." + segment(claims)
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sign(signed))
+}
+
+func signHMAC(secret []byte) func(string) []byte {
+	return func(signed string) []byte {
+		mac := hmac.New(sha256.New, secret)
+		mac.Write([]byte(signed))
+		return mac.Sum(nil)
+	}
+}
+
+// writePublicKey writes the PEM encoding of key to a file and returns its path
+func writePublicKey(t *testing.T, key crypto.PublicKey) string {
+	t.Helper()
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "key.pem")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func newTestVerifier(t *testing.T, opts JWTOptions) *JWTVerifier {
+	t.Helper()
+	v, err := NewJWTVerifier(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v.now = func() time.Time { return testNow }
+	return v
+}
+
+// validClaims returns claims every verifier in these tests accepts
+func validClaims() map[string]any {
+	return map[string]any{
+		"sub":  "ci",
+		"role": "deployer",
+		"exp":  testNow.Add(time.Hour).Unix(),
+	}
+}
+
+func TestJWTVerify(t *testing.T) {
+	v := newTestVerifier(t, JWTOptions{HMACSecret: testSecret, Issuer: "idp", Audience: "scheduler"})
+
+	tests := []struct {
+		name   string
+		change func(claims map[string]any)
+		// alg and secret override the header algorithm and the signing secret
+		alg     string
+		secret  []byte
+		want    *Identity
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			change: func(claims map[string]any) {
+				claims["tenant"] = "team-a"
+				claims["selector"] = map[string]string{"team": "a"}
+			},
+			want: &Identity{Subject: "ci", Role: RoleDeployer, Tenant: "team-a", Selector: map[string]string{"team": "a"}},
+		},
+		{
+			name:   "audience list",
+			change: func(claims map[string]any) { claims["aud"] = []string{"other", "scheduler"} },
+			want:   &Identity{Subject: "ci", Role: RoleDeployer},
+		},
+		{
+			name:   "expired within the clock skew",
+			change: func(claims map[string]any) { claims["exp"] = testNow.Add(-30 * time.Second).Unix() },
+			want:   &Identity{Subject: "ci", Role: RoleDeployer},
+		},
+		{
+			name:    "expired",
+			change:  func(claims map[string]any) { claims["exp"] = testNow.Add(-2 * clockSkew).Unix() },
+			wantErr: true,
+		},
+		{
+			name:    "no expiry",
+			change:  func(claims map[string]any) { delete(claims, "exp") },
+			wantErr: true,
+		},
+		{
+			name:    "not valid yet",
+			change:  func(claims map[string]any) { claims["nbf"] = testNow.Add(2 * clockSkew).Unix() },
+			wantErr: true,
+		},
+		{
+			name:    "wrong issuer",
+			change:  func(claims map[string]any) { claims["iss"] = "elsewhere" },
+			wantErr: true,
+		},
+		{
+			name:    "wrong audience",
+			change:  func(claims map[string]any) { claims["aud"] = "other" },
+			wantErr: true,
+		},
+		{
+			name:    "unknown role",
+			change:  func(claims map[string]any) { claims["role"] = "root" },
+			wantErr: true,
+		},
+		{
+			name:    "no role",
+			change:  func(claims map[string]any) { delete(claims, "role") },
+			wantErr: true,
+		},
+		{
+			name:    "malformed selector",
+			change:  func(claims map[string]any) { claims["selector"] = "team=a" },
+			wantErr: true,
+		},
+		{
+			name:    "wrong secret",
+			secret:  []byte("fedcba9876543210fedcba9876543210"),
+			wantErr: true,
+		},
+		{
+			name:    "algorithm none",
+			alg:     "none",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := validClaims()
+			claims["iss"] = "idp"
+			claims["aud"] = "scheduler"
+			if tt.change != nil {
+				tt.change(claims)
+			}
+			alg, secret := "HS256", testSecret
+			if tt.alg != "" {
+				alg = tt.alg
+			}
+			if tt.secret != nil {
+				secret = tt.secret
+			}
+
+			got, err := v.Verify(signToken(t, alg, claims, signHMAC(secret)))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Verify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Verify() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJWTVerifyPublicKeys(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signRSA := func(signed string) []byte {
+		digest := sha256.Sum256([]byte(signed))
+		signature, err := rsa.SignPKCS1v15(rand.Reader, rsaKey, crypto.SHA256, digest[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signature
+	}
+	signEC := func(signed string) []byte {
+		digest := sha256.Sum256([]byte(signed))
+		r, s, err := ecdsa.Sign(rand.Reader, ecKey, digest[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		signature := make([]byte, 64)
+		r.FillBytes(signature[:32])
+		s.FillBytes(signature[32:])
+		return signature
+	}
+
+	rsaVerifier := newTestVerifier(t, JWTOptions{PublicKeyFile: writePublicKey(t, &rsaKey.PublicKey)})
+	ecVerifier := newTestVerifier(t, JWTOptions{PublicKeyFile: writePublicKey(t, &ecKey.PublicKey)})
+
+	tests := []struct {
+		name     string
+		verifier *JWTVerifier
+		token    string
+		wantErr  bool
+	}{
+		{name: "RS256", verifier: rsaVerifier, token: signToken(t, "RS256", validClaims(), signRSA)},
+		{name: "ES256", verifier: ecVerifier, token: signToken(t, "ES256", validClaims(), signEC)},
+		{
+			// An HMAC keyed with the public key must not pass for a signature
+			name:     "HS256 against a public key",
+			verifier: rsaVerifier,
+			token:    signToken(t, "HS256", validClaims(), signHMAC(x509.MarshalPKCS1PublicKey(&rsaKey.PublicKey))),
+			wantErr:  true,
+		},
+		{
+			name:     "signed by another key",
+			verifier: ecVerifier,
+			token:    signToken(t, "RS256", validClaims(), signRSA),
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.verifier.Verify(tt.token)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Verify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewJWTVerifier(t *testing.T) {
+	p384, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		opts    JWTOptions
+		wantErr bool
+	}{
+		{name: "HMAC secret", opts: JWTOptions{HMACSecret: testSecret}},
+		{name: "short HMAC secret", opts: JWTOptions{HMACSecret: []byte("short")}, wantErr: true},
+		{name: "no key", opts: JWTOptions{}, wantErr: true},
+		{name: "secret and key", opts: JWTOptions{HMACSecret: testSecret, PublicKeyFile: "key.pem"}, wantErr: true},
+		{name: "P-384 key", opts: JWTOptions{PublicKeyFile: writePublicKey(t, &p384.PublicKey)}, wantErr: true},
+		{name: "missing key file", opts: JWTOptions{PublicKeyFile: filepath.Join(t.TempDir(), "missing.pem")}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewJWTVerifier(tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewJWTVerifier() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
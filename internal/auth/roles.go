@@ -0,0 +1,100 @@
+package auth
+
+import (
+	"fmt"
+
+	pb "scheduler/proto/gen"
+)
+
+// Role grants access to a set of RPCs. Each role includes the permissions of
+// the roles below it.
+type Role int
+
+const (
+	// RoleViewer may read environments, volumes, backups and secret metadata
+	RoleViewer Role = iota + 1
+	// RoleDeployer may additionally create, change and run environments
+	RoleDeployer
+	// RoleAdmin may additionally delete volumes and secrets, restore data,
+	// manage stack templates, reveal sensitive fields and read the audit log
+	RoleAdmin
+)
+
+// ParseRole parses a role name from the config file or a token
+func ParseRole(name string) (Role, error) {
+	switch name {
+	case "viewer":
+		return RoleViewer, nil
+	case "deployer":
+		return RoleDeployer, nil
+	case "admin":
+		return RoleAdmin, nil
+	default:
+		return 0, fmt.Errorf("unknown role %q, must be viewer, deployer or admin", name)
+	}
+}
+
+func (r Role) String() string {
+	switch r {
+	case RoleViewer:
+		return "viewer"
+	case RoleDeployer:
+		return "deployer"
+	case RoleAdmin:
+		return "admin"
+	default:
+		return fmt.Sprintf("Role(%d)", int(r))
+	}
+}
+
+// methodRoles maps each RPC to the least role allowed to call it
+var methodRoles = map[string]Role{
+	pb.SchedulerService_GetEnvironment_FullMethodName:           RoleViewer,
+	pb.SchedulerService_ListEnvironments_FullMethodName:         RoleViewer,
+	pb.SchedulerService_ListEnvironmentRevisions_FullMethodName: RoleViewer,
+	pb.SchedulerService_GetEnvironmentRevision_FullMethodName:   RoleViewer,
+	pb.SchedulerService_GetEnvironmentStatus_FullMethodName:     RoleViewer,
+	pb.SchedulerService_PlanEnvironmentUpdate_FullMethodName:    RoleViewer,
+	pb.SchedulerService_GetEnvironmentLogs_FullMethodName:       RoleViewer,
+	pb.SchedulerService_GetVolume_FullMethodName:                RoleViewer,
+	pb.SchedulerService_ListVolumes_FullMethodName:              RoleViewer,
+	pb.SchedulerService_ListVolumeSnapshots_FullMethodName:      RoleViewer,
+	pb.SchedulerService_ListDatabaseBackups_FullMethodName:      RoleViewer,
+	pb.SchedulerService_ListSecrets_FullMethodName:              RoleViewer,
+	pb.SchedulerService_GetQuotaUsage_FullMethodName:            RoleViewer,
+	pb.SchedulerService_GetNodeInfo_FullMethodName:              RoleViewer,
+	pb.SchedulerService_ListTemplates_FullMethodName:            RoleViewer,
+	pb.SchedulerService_GetTemplate_FullMethodName:              RoleViewer,
+
+	pb.SchedulerService_CreateEnvironment_FullMethodName:             RoleDeployer,
+	pb.SchedulerService_UpdateEnvironment_FullMethodName:             RoleDeployer,
+	pb.SchedulerService_ApplyManifest_FullMethodName:                 RoleDeployer,
+	pb.SchedulerService_CreateEnvironmentFromTemplate_FullMethodName: RoleDeployer,
+	pb.SchedulerService_RollbackEnvironment_FullMethodName:           RoleDeployer,
+	pb.SchedulerService_DeleteEnvironment_FullMethodName:             RoleDeployer,
+	pb.SchedulerService_StartEnvironment_FullMethodName:              RoleDeployer,
+	pb.SchedulerService_StopEnvironment_FullMethodName:               RoleDeployer,
+	pb.SchedulerService_RestartEnvironment_FullMethodName:            RoleDeployer,
+	pb.SchedulerService_CreateVolume_FullMethodName:                  RoleDeployer,
+	pb.SchedulerService_UpdateVolume_FullMethodName:                  RoleDeployer,
+	pb.SchedulerService_SnapshotVolume_FullMethodName:                RoleDeployer,
+	pb.SchedulerService_BackupDatabase_FullMethodName:                RoleDeployer,
+	pb.SchedulerService_CreateSecret_FullMethodName:                  RoleDeployer,
+
+	pb.SchedulerService_DeleteVolume_FullMethodName:     RoleAdmin,
+	pb.SchedulerService_RestoreVolume_FullMethodName:    RoleAdmin,
+	pb.SchedulerService_RestoreDatabase_FullMethodName:  RoleAdmin,
+	pb.SchedulerService_DeleteSecret_FullMethodName:     RoleAdmin,
+	pb.SchedulerService_RegisterTemplate_FullMethodName: RoleAdmin,
+	pb.SchedulerService_DeleteTemplate_FullMethodName:   RoleAdmin,
+	pb.SchedulerService_ListAuditEvents_FullMethodName:  RoleAdmin,
+}
+
+// RequiredRole returns the least role allowed to call an RPC. RPCs without
+// a rule require admin so that new RPCs are closed by default.
+func RequiredRole(fullMethod string) Role {
+	if role, ok := methodRoles[fullMethod]; ok {
+		return role
+	}
+	return RoleAdmin
+}
@@ -0,0 +1,346 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"scheduler/internal/container"
+	"scheduler/internal/fsutil"
+	pb "scheduler/proto/gen"
+)
+
+// ErrNotFound is returned when a backup does not exist
+var ErrNotFound = errors.New("backup not found")
+
+const (
+	dumpExtension     = ".dump"
+	metadataExtension = ".json"
+	// maxStderr bounds how much pg_dump/pg_restore output is kept for errors
+	maxStderr = 4096
+)
+
+var (
+	idPattern          = regexp.MustCompile(`^[0-9]{8}T[0-9]{6}Z-[0-9a-f]{8}$`)
+	environmentPattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+)
+
+// Target identifies the Postgres database a backup is taken from or
+// restored into
+type Target struct {
+	EnvironmentID string
+	Namespace     string
+	ContainerID   string
+	Database      string
+	Username      string
+	Password      string
+}
+
+// Manager takes logical backups by running pg_dump inside the database
+// container and streams them to files under a data root, one directory per
+// environment
+type Manager struct {
+	root    string
+	runtime container.Runtime
+
+	mu    sync.Mutex
+	locks map[string]*sync.Mutex
+}
+
+// NewManager creates a manager storing backups under root
+func NewManager(root string, runtime container.Runtime) (*Manager, error) {
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create backup root %s: %w", root, err)
+	}
+	return &Manager{
+		root:    root,
+		runtime: runtime,
+		locks:   make(map[string]*sync.Mutex),
+	}, nil
+}
+
+// Backup dumps the target database in pg_dump's compressed custom format
+func (m *Manager) Backup(ctx context.Context, target Target, scheduled bool) (*pb.DatabaseBackup, error) {
+	if !environmentPattern.MatchString(target.EnvironmentID) {
+		return nil, fmt.Errorf("invalid environment ID %q", target.EnvironmentID)
+	}
+	unlock := m.lock(target.EnvironmentID)
+	defer unlock()
+
+	id, err := newID(time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate backup ID: %w", err)
+	}
+
+	dir := filepath.Join(m.root, target.EnvironmentID)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create backup directory %s: %w", dir, err)
+	}
+
+	partial, err := os.CreateTemp(dir, "."+id+"-*"+dumpExtension)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create backup file: %w", err)
+	}
+	defer os.Remove(partial.Name())
+
+	command := []string{"pg_dump", "--format=custom", "--username", target.Username, "--dbname", target.Database}
+	if err := m.exec(ctx, target, command, nil, partial); err != nil {
+		partial.Close()
+		return nil, fmt.Errorf("pg_dump failed: %w", err)
+	}
+	if err := partial.Sync(); err != nil {
+		partial.Close()
+		return nil, fmt.Errorf("failed to sync backup file: %w", err)
+	}
+	if err := partial.Close(); err != nil {
+		return nil, fmt.Errorf("failed to close backup file: %w", err)
+	}
+
+	info, err := os.Stat(partial.Name())
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat backup file: %w", err)
+	}
+	if err := os.Rename(partial.Name(), m.dumpPath(target.EnvironmentID, id)); err != nil {
+		return nil, fmt.Errorf("failed to store backup file: %w", err)
+	}
+
+	backup := &pb.DatabaseBackup{
+		Id:            id,
+		EnvironmentId: target.EnvironmentID,
+		DatabaseName:  target.Database,
+		SizeBytes:     info.Size(),
+		Scheduled:     scheduled,
+		CreatedAt:     timestamppb.Now(),
+	}
+	data, err := protojson.MarshalOptions{Multiline: true}.Marshal(backup)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode backup %s: %w", id, err)
+	}
+	if err := fsutil.WriteFileAtomic(m.metadataPath(target.EnvironmentID, id), data, 0o600); err != nil {
+		os.Remove(m.dumpPath(target.EnvironmentID, id))
+		return nil, err
+	}
+
+	return backup, nil
+}
+
+// Restore loads a backup into the target database with pg_restore, dropping
+// the objects it contains before recreating them
+func (m *Manager) Restore(ctx context.Context, target Target, id string) error {
+	if _, err := m.Get(target.EnvironmentID, id); err != nil {
+		return err
+	}
+	unlock := m.lock(target.EnvironmentID)
+	defer unlock()
+
+	dump, err := os.Open(m.dumpPath(target.EnvironmentID, id))
+	if err != nil {
+		return fmt.Errorf("failed to open backup %s: %w", id, err)
+	}
+	defer dump.Close()
+
+	command := []string{"pg_restore", "--clean", "--if-exists", "--no-owner", "--username", target.Username, "--dbname", target.Database}
+	if err := m.exec(ctx, target, command, dump, io.Discard); err != nil {
+		return fmt.Errorf("pg_restore failed: %w", err)
+	}
+	return nil
+}
+
+// Get returns a backup of an environment
+func (m *Manager) Get(environmentID, id string) (*pb.DatabaseBackup, error) {
+	if !environmentPattern.MatchString(environmentID) || !idPattern.MatchString(id) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+
+	data, err := os.ReadFile(m.metadataPath(environmentID, id))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backup %s: %w", id, err)
+	}
+
+	backup := &pb.DatabaseBackup{}
+	if err := protojson.Unmarshal(data, backup); err != nil {
+		return nil, fmt.Errorf("failed to decode backup %s: %w", id, err)
+	}
+	return backup, nil
+}
+
+// List returns the backups of an environment, oldest first
+func (m *Manager) List(environmentID string) ([]*pb.DatabaseBackup, error) {
+	if !environmentPattern.MatchString(environmentID) {
+		return nil, nil
+	}
+
+	entries, err := os.ReadDir(filepath.Join(m.root, environmentID))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backups of environment %s: %w", environmentID, err)
+	}
+
+	var backups []*pb.DatabaseBackup
+	for _, entry := range entries {
+		id := strings.TrimSuffix(entry.Name(), metadataExtension)
+		if entry.IsDir() || !idPattern.MatchString(id) || id == entry.Name() {
+			continue
+		}
+		backup, err := m.Get(environmentID, id)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		backups = append(backups, backup)
+	}
+	sort.Slice(backups, func(i, j int) bool {
+		left, right := backups[i].GetCreatedAt().AsTime(), backups[j].GetCreatedAt().AsTime()
+		if left.Equal(right) {
+			return backups[i].GetId() < backups[j].GetId()
+		}
+		return left.Before(right)
+	})
+	return backups, nil
+}
+
+// Delete removes a backup
+func (m *Manager) Delete(environmentID, id string) error {
+	if !environmentPattern.MatchString(environmentID) || !idPattern.MatchString(id) {
+		return fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	if err := os.Remove(m.metadataPath(environmentID, id)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove backup %s: %w", id, err)
+	}
+	if err := os.Remove(m.dumpPath(environmentID, id)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove backup %s: %w", id, err)
+	}
+	return nil
+}
+
+// DeleteAll removes every backup of an environment, used when the
+// environment is deleted
+func (m *Manager) DeleteAll(environmentID string) error {
+	if !environmentPattern.MatchString(environmentID) {
+		return fmt.Errorf("%w: %s", ErrNotFound, environmentID)
+	}
+	unlock := m.lock(environmentID)
+	defer unlock()
+
+	if err := os.RemoveAll(filepath.Join(m.root, environmentID)); err != nil {
+		return fmt.Errorf("failed to delete backups of %s: %w", environmentID, err)
+	}
+	return nil
+}
+
+// Prune deletes the oldest scheduled backups of an environment so that at
+// most retain of them are left. Backups taken on request are never pruned.
+func (m *Manager) Prune(environmentID string, retain int) error {
+	if retain <= 0 {
+		return nil
+	}
+
+	backups, err := m.List(environmentID)
+	if err != nil {
+		return err
+	}
+
+	var scheduled []*pb.DatabaseBackup
+	for _, backup := range backups {
+		if backup.GetScheduled() {
+			scheduled = append(scheduled, backup)
+		}
+	}
+	for len(scheduled) > retain {
+		if err := m.Delete(environmentID, scheduled[0].GetId()); err != nil {
+			return err
+		}
+		scheduled = scheduled[1:]
+	}
+	return nil
+}
+
+// exec runs a Postgres client command in the database container, failing
+// with its stderr when it exits non-zero
+func (m *Manager) exec(ctx context.Context, target Target, command []string, stdin io.Reader, stdout io.Writer) error {
+	env := map[string]string{}
+	if target.Password != "" {
+		env["PGPASSWORD"] = target.Password
+	}
+
+	stderr := &limitedBuffer{limit: maxStderr}
+	exitCode, err := m.runtime.Exec(ctx, target.Namespace, target.ContainerID, container.ExecOptions{
+		Command: command,
+		Env:     env,
+		Stdin:   stdin,
+		Stdout:  stdout,
+		Stderr:  stderr,
+	})
+	if err != nil {
+		return err
+	}
+	if exitCode != 0 {
+		return fmt.Errorf("exit code %d: %s", exitCode, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// lock serializes backups and restores of the same environment
+func (m *Manager) lock(environmentID string) func() {
+	m.mu.Lock()
+	lock, ok := m.locks[environmentID]
+	if !ok {
+		lock = &sync.Mutex{}
+		m.locks[environmentID] = lock
+	}
+	m.mu.Unlock()
+
+	lock.Lock()
+	return lock.Unlock
+}
+
+func (m *Manager) dumpPath(environmentID, id string) string {
+	return filepath.Join(m.root, environmentID, id+dumpExtension)
+}
+
+func (m *Manager) metadataPath(environmentID, id string) string {
+	return filepath.Join(m.root, environmentID, id+metadataExtension)
+}
+
+// newID generates a backup ID that sorts by creation time
+func newID(now time.Time) (string, error) {
+	buf := make([]byte, 4)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return now.UTC().Format("20060102T150405Z") + "-" + hex.EncodeToString(buf), nil
+}
+
+// limitedBuffer keeps the first limit bytes written to it and discards the rest
+type limitedBuffer struct {
+	bytes.Buffer
+	limit int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := b.limit - b.Len(); room > 0 {
+		b.Buffer.Write(p[:min(room, len(p))])
+	}
+	return len(p), nil
+}
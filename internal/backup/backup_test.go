@@ -0,0 +1,198 @@
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"scheduler/internal/container"
+)
+
+var testTarget = Target{
+	EnvironmentID: "env-1",
+	Namespace:     "test",
+	ContainerID:   "env-1-database",
+	Database:      "app",
+	Username:      "app",
+	Password:      "secret",
+}
+
+// newTestManager returns a manager whose running database container dumps
+// "dump of <database>" and records what pg_restore is fed in restored
+func newTestManager(t *testing.T) (*Manager, *container.Fake, *string) {
+	t.Helper()
+	restored := new(string)
+	runtime := &container.Fake{
+		OnExec: func(namespace, containerID string, opts container.ExecOptions) (int, error) {
+			if opts.Env["PGPASSWORD"] != testTarget.Password {
+				return 1, nil
+			}
+			switch opts.Command[0] {
+			case "pg_dump":
+				fmt.Fprintf(opts.Stdout, "dump of %s", opts.Command[len(opts.Command)-1])
+			case "pg_restore":
+				data, err := io.ReadAll(opts.Stdin)
+				if err != nil {
+					return 0, err
+				}
+				*restored = string(data)
+			}
+			return 0, nil
+		},
+	}
+	if err := runtime.Run(context.Background(), testTarget.Namespace, testTarget.ContainerID, container.Spec{Image: "postgres:16"}); err != nil {
+		t.Fatal(err)
+	}
+	m, err := NewManager(t.TempDir(), runtime)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m, runtime, restored
+}
+
+func TestBackupAndRestore(t *testing.T) {
+	m, runtime, restored := newTestManager(t)
+	ctx := context.Background()
+
+	taken, err := m.Backup(ctx, testTarget, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if taken.GetEnvironmentId() != "env-1" || taken.GetDatabaseName() != "app" || taken.GetSizeBytes() != int64(len("dump of app")) {
+		t.Errorf("Backup() = %v, want an 11 byte backup of app", taken)
+	}
+	data, err := os.ReadFile(m.dumpPath("env-1", taken.GetId()))
+	if err != nil || string(data) != "dump of app" {
+		t.Errorf("dump file = %q, %v, want the pg_dump output", data, err)
+	}
+
+	if err := m.Restore(ctx, testTarget, taken.GetId()); err != nil {
+		t.Fatal(err)
+	}
+	if *restored != "dump of app" {
+		t.Errorf("pg_restore input = %q, want the dump", *restored)
+	}
+
+	want := []string{
+		"run env-1-database",
+		"exec env-1-database pg_dump --format=custom --username app --dbname app",
+		"exec env-1-database pg_restore --clean --if-exists --no-owner --username app --dbname app",
+	}
+	if got := runtime.Calls(); !reflect.DeepEqual(got, want) {
+		t.Errorf("calls = %q, want %q", got, want)
+	}
+	if err := m.Restore(ctx, testTarget, "20240101T000000Z-00000000"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("restoring a missing backup = %v, want ErrNotFound", err)
+	}
+
+	// Backups need the database container to be running
+	if err := runtime.Remove(ctx, testTarget.Namespace, testTarget.ContainerID, 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Backup(ctx, testTarget, false); err == nil {
+		t.Error("backing up a stopped database succeeded")
+	}
+}
+
+func TestBackupFailure(t *testing.T) {
+	m, _, _ := newTestManager(t)
+	target := testTarget
+	target.Password = "wrong"
+
+	_, err := m.Backup(context.Background(), target, false)
+	if err == nil || !strings.Contains(err.Error(), "exit code 1") {
+		t.Fatalf("Backup() with a failing pg_dump = %v, want the exit code", err)
+	}
+	// Neither the partial dump nor metadata are left behind
+	entries, err := os.ReadDir(filepath.Join(m.root, "env-1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("files after a failed backup = %v, want none", entries)
+	}
+
+	target.EnvironmentID = "../escape"
+	if _, err := m.Backup(context.Background(), target, false); err == nil {
+		t.Error("backing up an environment with an unsafe ID succeeded")
+	}
+}
+
+func TestListAndPrune(t *testing.T) {
+	m, _, _ := newTestManager(t)
+	ctx := context.Background()
+
+	manual, err := m.Backup(ctx, testTarget, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range 3 {
+		if _, err := m.Backup(ctx, testTarget, true); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := m.Prune("env-1", 2); err != nil {
+		t.Fatal(err)
+	}
+	backups, err := m.List("env-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	scheduled := 0
+	for _, backup := range backups {
+		if backup.GetScheduled() {
+			scheduled++
+		}
+	}
+	if len(backups) != 3 || scheduled != 2 || backups[0].GetId() != manual.GetId() {
+		t.Errorf("backups after pruning = %v, want the manual one and two scheduled", backups)
+	}
+
+	if err := m.Delete("env-1", manual.GetId()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Get("env-1", manual.GetId()); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get after delete = %v, want ErrNotFound", err)
+	}
+	if backups, err := m.List("env-2"); err != nil || len(backups) != 0 {
+		t.Errorf("List() of an environment without backups = %v, %v, want none", backups, err)
+	}
+}
+
+func TestDeleteAll(t *testing.T) {
+	m, _, _ := newTestManager(t)
+	for range 2 {
+		if _, err := m.Backup(context.Background(), testTarget, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := m.DeleteAll("env-1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(m.root, "env-1")); !os.IsNotExist(err) {
+		t.Errorf("backup directory after DeleteAll() = %v, want it removed", err)
+	}
+	if err := m.DeleteAll("../env-1"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("DeleteAll() outside the backups = %v, want ErrNotFound", err)
+	}
+}
+
+func TestLimitedBuffer(t *testing.T) {
+	buf := &limitedBuffer{limit: 5}
+	for _, chunk := range []string{"abc", "defg", "hij"} {
+		if n, err := buf.Write([]byte(chunk)); n != len(chunk) || err != nil {
+			t.Errorf("Write(%q) = %d, %v, want the whole chunk accepted", chunk, n, err)
+		}
+	}
+	if buf.String() != "abcde" {
+		t.Errorf("buffer = %q, want abcde", buf.String())
+	}
+}
@@ -0,0 +1,109 @@
+package capacity
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"scheduler/internal/quota"
+	pb "scheduler/proto/gen"
+)
+
+// ErrInsufficient is returned when the host cannot fit an environment
+var ErrInsufficient = errors.New("insufficient host capacity")
+
+// Resources is an amount of host capacity
+type Resources struct {
+	MemoryMB int64
+	CPUCores float64
+	DiskMB   int64
+}
+
+// FromQuota converts the resources of a quota amount, dropping the
+// environment count
+func FromQuota(amount quota.Resources) Resources {
+	return Resources{MemoryMB: amount.MemoryMB, CPUCores: amount.CPUCores, DiskMB: amount.DiskMB}
+}
+
+// Add returns the sum of r and other
+func (r Resources) Add(other Resources) Resources {
+	return Resources{
+		MemoryMB: r.MemoryMB + other.MemoryMB,
+		CPUCores: r.CPUCores + other.CPUCores,
+		DiskMB:   r.DiskMB + other.DiskMB,
+	}
+}
+
+// Sub returns r less other
+func (r Resources) Sub(other Resources) Resources {
+	return Resources{
+		MemoryMB: r.MemoryMB - other.MemoryMB,
+		CPUCores: r.CPUCores - other.CPUCores,
+		DiskMB:   r.DiskMB - other.DiskMB,
+	}
+}
+
+// ToProto converts r to its API representation
+func (r Resources) ToProto() *pb.ResourceLimits {
+	return &pb.ResourceLimits{MemoryMb: r.MemoryMB, CpuCores: r.CPUCores, DiskMb: r.DiskMB}
+}
+
+// Overcommit holds the ratios by which the sum of the resource limits of
+// running environments may exceed the host capacity, 1 allowing no
+// overcommit
+type Overcommit struct {
+	Memory float64 `mapstructure:"memory"`
+	CPU    float64 `mapstructure:"cpu"`
+	Disk   float64 `mapstructure:"disk"`
+}
+
+// Validate checks that every ratio is at least 1, treating zero as 1
+func (o *Overcommit) Validate() error {
+	for _, ratio := range []*float64{&o.Memory, &o.CPU, &o.Disk} {
+		if *ratio == 0 {
+			*ratio = 1
+		}
+		if *ratio < 1 {
+			return fmt.Errorf("overcommit ratios must be at least 1, got %g", *ratio)
+		}
+	}
+	return nil
+}
+
+// Node is the capacity of the host the scheduler runs on
+type Node struct {
+	Capacity   Resources
+	Overcommit Overcommit
+}
+
+// Allocatable returns the capacity scaled by the overcommit ratios
+func (n Node) Allocatable() Resources {
+	return Resources{
+		MemoryMB: int64(float64(n.Capacity.MemoryMB) * n.Overcommit.Memory),
+		CPUCores: n.Capacity.CPUCores * n.Overcommit.CPU,
+		DiskMB:   int64(float64(n.Capacity.DiskMB) * n.Overcommit.Disk),
+	}
+}
+
+// Fit verifies that requested fits next to the reserved resources. The
+// error names every resource that is short.
+func (n Node) Fit(reserved, requested Resources) error {
+	allocatable := n.Allocatable()
+	var shortfalls []string
+	if total := reserved.MemoryMB + requested.MemoryMB; total > allocatable.MemoryMB {
+		shortfalls = append(shortfalls, fmt.Sprintf("memory_mb needs %d with %d of %d reserved, short by %d",
+			requested.MemoryMB, reserved.MemoryMB, allocatable.MemoryMB, total-allocatable.MemoryMB))
+	}
+	if total := reserved.CPUCores + requested.CPUCores; total > allocatable.CPUCores+1e-9 {
+		shortfalls = append(shortfalls, fmt.Sprintf("cpu_cores needs %g with %g of %g reserved, short by %g",
+			requested.CPUCores, reserved.CPUCores, allocatable.CPUCores, total-allocatable.CPUCores))
+	}
+	if total := reserved.DiskMB + requested.DiskMB; total > allocatable.DiskMB {
+		shortfalls = append(shortfalls, fmt.Sprintf("disk_mb needs %d with %d of %d reserved, short by %d",
+			requested.DiskMB, reserved.DiskMB, allocatable.DiskMB, total-allocatable.DiskMB))
+	}
+	if len(shortfalls) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrInsufficient, strings.Join(shortfalls, "; "))
+}
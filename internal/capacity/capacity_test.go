@@ -0,0 +1,77 @@
+package capacity
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNodeFit(t *testing.T) {
+	node := Node{
+		Capacity:   Resources{MemoryMB: 1000, CPUCores: 2, DiskMB: 10000},
+		Overcommit: Overcommit{Memory: 1.5, CPU: 2, Disk: 1},
+	}
+	if got, want := node.Allocatable(), (Resources{MemoryMB: 1500, CPUCores: 4, DiskMB: 10000}); got != want {
+		t.Fatalf("Allocatable() = %+v, want %+v", got, want)
+	}
+
+	tests := []struct {
+		name      string
+		reserved  Resources
+		requested Resources
+		want      string
+	}{
+		{
+			name:      "fits into the overcommitted capacity",
+			reserved:  Resources{MemoryMB: 1000, CPUCores: 3},
+			requested: Resources{MemoryMB: 500, CPUCores: 1},
+		},
+		{
+			name:      "memory short",
+			reserved:  Resources{MemoryMB: 1200},
+			requested: Resources{MemoryMB: 500},
+			want:      "insufficient host capacity: memory_mb needs 500 with 1200 of 1500 reserved, short by 200",
+		},
+		{
+			name:      "disk and cores short",
+			reserved:  Resources{CPUCores: 3.5, DiskMB: 9000},
+			requested: Resources{CPUCores: 1, DiskMB: 2000},
+			want: "insufficient host capacity: cpu_cores needs 1 with 3.5 of 4 reserved, short by 0.5; " +
+				"disk_mb needs 2000 with 9000 of 10000 reserved, short by 1000",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := node.Fit(tt.reserved, tt.requested)
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("Fit() error = %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrInsufficient) || err.Error() != tt.want {
+				t.Errorf("Fit() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestOvercommitValidate(t *testing.T) {
+	o := Overcommit{CPU: 4}
+	if err := o.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if o != (Overcommit{Memory: 1, CPU: 4, Disk: 1}) {
+		t.Errorf("Validate() left %+v, want unset ratios at 1", o)
+	}
+	if err := (&Overcommit{Memory: 0.5}).Validate(); err == nil {
+		t.Error("Validate() accepted a ratio below 1")
+	}
+}
+
+func TestResourcesArithmetic(t *testing.T) {
+	a := Resources{MemoryMB: 100, CPUCores: 1.5, DiskMB: 10}
+	b := Resources{MemoryMB: 40, CPUCores: 0.5, DiskMB: 10}
+	if got := a.Add(b).Sub(b); got != a {
+		t.Errorf("Add().Sub() = %+v, want %+v", got, a)
+	}
+}
@@ -0,0 +1,56 @@
+//go:build linux
+
+package capacity
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// Discover reads the memory and CPU count of the host from /proc and the size
+// of the filesystem holding dataDir
+func Discover(dataDir string) (Resources, error) {
+	memoryMB, err := totalMemoryMB("/proc/meminfo")
+	if err != nil {
+		return Resources{}, err
+	}
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(dataDir, &stat); err != nil {
+		return Resources{}, fmt.Errorf("failed to stat filesystem of %s: %w", dataDir, err)
+	}
+	return Resources{
+		MemoryMB: memoryMB,
+		CPUCores: float64(runtime.NumCPU()),
+		DiskMB:   int64(stat.Blocks) * int64(stat.Bsize) >> 20,
+	}, nil
+}
+
+// totalMemoryMB parses MemTotal out of a meminfo file
+func totalMemoryMB(path string) (int64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == "MemTotal:" {
+			kilobytes, err := strconv.ParseInt(fields[1], 10, 64)
+			if err != nil {
+				return 0, fmt.Errorf("invalid MemTotal in %s: %w", path, err)
+			}
+			return kilobytes >> 10, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return 0, fmt.Errorf("no MemTotal in %s", path)
+}
@@ -0,0 +1,27 @@
+//go:build linux
+
+package capacity
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTotalMemoryMB(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "meminfo")
+	if err := os.WriteFile(path, []byte("MemTotal:       16384000 kB\nMemFree:         1024 kB\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := totalMemoryMB(path); err != nil || got != 16000 {
+		t.Errorf("totalMemoryMB() = %d, %v, want 16000", got, err)
+	}
+
+	if err := os.WriteFile(path, []byte("MemFree: 1024 kB\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := totalMemoryMB(path); err == nil {
+		t.Error("totalMemoryMB() without MemTotal succeeded")
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package capacity
+
+import "errors"
+
+// Discover is only supported on Linux, where the capacity is read from /proc
+func Discover(dataDir string) (Resources, error) {
+	return Resources{}, errors.New("host capacity discovery is only supported on Linux, disable capacity.enabled")
+}
@@ -0,0 +1,157 @@
+package certs
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	"scheduler/internal/fsutil"
+)
+
+// File names written by Init
+const (
+	CAFile         = "ca.pem"
+	CAKeyFile      = "ca-key.pem"
+	ServerFile     = "server.pem"
+	ServerKeyFile  = "server-key.pem"
+	ClientFile     = "client.pem"
+	ClientKeyFile  = "client-key.pem"
+	organization   = "scheduler"
+	serialBitCount = 128
+)
+
+// InitOptions controls the certificates generated by Init
+type InitOptions struct {
+	// Hosts are the DNS names and IP addresses the server certificate is
+	// valid for
+	Hosts []string
+	// ClientName is the common name of the client certificate
+	ClientName string
+	Validity   time.Duration
+	// Overwrite replaces existing files instead of failing
+	Overwrite bool
+}
+
+// Init generates a local CA in dir together with a server and a client
+// certificate signed by it. It is meant for development setups.
+func Init(dir string, opts InitOptions) error {
+	if len(opts.Hosts) == 0 {
+		return errors.New("at least one server host is required")
+	}
+	if !opts.Overwrite {
+		for _, name := range []string{CAFile, CAKeyFile, ServerFile, ServerKeyFile, ClientFile, ClientKeyFile} {
+			if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+				return fmt.Errorf("%s already exists", filepath.Join(dir, name))
+			}
+		}
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create certificate directory %s: %w", dir, err)
+	}
+
+	notBefore := time.Now().Add(-time.Hour)
+	notAfter := notBefore.Add(opts.Validity)
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return fmt.Errorf("failed to generate CA key: %w", err)
+	}
+	caTemplate := &x509.Certificate{
+		Subject:               pkix.Name{Organization: []string{organization}, CommonName: "scheduler development CA"},
+		NotBefore:             notBefore,
+		NotAfter:              notAfter,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLenZero:        true,
+	}
+	caCert, err := issue(caTemplate, caTemplate, caKey, caKey)
+	if err != nil {
+		return fmt.Errorf("failed to create CA certificate: %w", err)
+	}
+	if err := writePair(dir, CAFile, CAKeyFile, caCert.Raw, caKey); err != nil {
+		return err
+	}
+
+	serverTemplate := &x509.Certificate{
+		Subject:     pkix.Name{Organization: []string{organization}, CommonName: opts.Hosts[0]},
+		NotBefore:   notBefore,
+		NotAfter:    notAfter,
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	for _, host := range opts.Hosts {
+		if ip := net.ParseIP(host); ip != nil {
+			serverTemplate.IPAddresses = append(serverTemplate.IPAddresses, ip)
+		} else {
+			serverTemplate.DNSNames = append(serverTemplate.DNSNames, host)
+		}
+	}
+	if err := issueLeaf(dir, ServerFile, ServerKeyFile, serverTemplate, caCert, caKey); err != nil {
+		return fmt.Errorf("failed to create server certificate: %w", err)
+	}
+
+	clientTemplate := &x509.Certificate{
+		Subject:     pkix.Name{Organization: []string{organization}, CommonName: opts.ClientName},
+		NotBefore:   notBefore,
+		NotAfter:    notAfter,
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	if err := issueLeaf(dir, ClientFile, ClientKeyFile, clientTemplate, caCert, caKey); err != nil {
+		return fmt.Errorf("failed to create client certificate: %w", err)
+	}
+	return nil
+}
+
+// issueLeaf generates a key and a certificate for it signed by the CA
+func issueLeaf(dir, certName, keyName string, template, caCert *x509.Certificate, caKey *ecdsa.PrivateKey) error {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+	cert, err := issue(template, caCert, key, caKey)
+	if err != nil {
+		return err
+	}
+	return writePair(dir, certName, keyName, cert.Raw, key)
+}
+
+func issue(template, parent *x509.Certificate, key, signer *ecdsa.PrivateKey) (*x509.Certificate, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), serialBitCount))
+	if err != nil {
+		return nil, err
+	}
+	template.SerialNumber = serial
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, signer)
+	if err != nil {
+		return nil, err
+	}
+	return x509.ParseCertificate(der)
+}
+
+// writePair writes a certificate and its private key as PEM files, keeping
+// the key readable by the owner only
+func writePair(dir, certName, keyName string, der []byte, key *ecdsa.PrivateKey) error {
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return fmt.Errorf("failed to encode private key: %w", err)
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})
+
+	if err := fsutil.WriteFileAtomic(filepath.Join(dir, keyName), keyPEM, 0o600); err != nil {
+		return err
+	}
+	return fsutil.WriteFileAtomic(filepath.Join(dir, certName), certPEM, 0o644)
+}
@@ -0,0 +1,248 @@
+package certs
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// initDir runs Init for localhost into a temporary directory
+func initDir(t *testing.T, clientName string) string {
+	t.Helper()
+	dir := filepath.Join(t.TempDir(), "certs")
+	err := Init(dir, InitOptions{
+		Hosts:      []string{"localhost", "127.0.0.1"},
+		ClientName: clientName,
+		Validity:   24 * time.Hour,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func readCertificate(t *testing.T, path string) *x509.Certificate {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		t.Fatalf("%s holds no PEM block", path)
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert
+}
+
+func TestInit(t *testing.T) {
+	dir := initDir(t, "alice")
+
+	for _, name := range []string{CAKeyFile, ServerKeyFile, ClientKeyFile} {
+		info, err := os.Stat(filepath.Join(dir, name))
+		if err != nil || info.Mode().Perm() != 0o600 {
+			t.Errorf("%s = %v, %v, want mode 0600", name, info, err)
+		}
+	}
+
+	pool, err := LoadPool(filepath.Join(dir, CAFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := readCertificate(t, filepath.Join(dir, ServerFile))
+	if _, err := server.Verify(x509.VerifyOptions{Roots: pool, DNSName: "localhost"}); err != nil {
+		t.Errorf("server certificate does not verify for localhost: %v", err)
+	}
+	if err := server.VerifyHostname("127.0.0.1"); err != nil {
+		t.Errorf("server certificate is not valid for 127.0.0.1: %v", err)
+	}
+	client := readCertificate(t, filepath.Join(dir, ClientFile))
+	_, err = client.Verify(x509.VerifyOptions{Roots: pool, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}})
+	if err != nil {
+		t.Errorf("client certificate does not verify: %v", err)
+	}
+	if client.Subject.CommonName != "alice" {
+		t.Errorf("client common name = %q, want alice", client.Subject.CommonName)
+	}
+
+	// Existing files are only replaced on request
+	if err := Init(dir, InitOptions{Hosts: []string{"localhost"}, Validity: time.Hour}); err == nil {
+		t.Error("running Init over existing files succeeded")
+	}
+	if err := Init(dir, InitOptions{Hosts: []string{"localhost"}, Validity: time.Hour, Overwrite: true}); err != nil {
+		t.Errorf("running Init with Overwrite = %v", err)
+	}
+	if err := Init(t.TempDir(), InitOptions{Validity: time.Hour}); err == nil {
+		t.Error("running Init without hosts succeeded")
+	}
+}
+
+// handshake connects a client built from clientOpts to a server using
+// reloader over loopback and returns the client certificates the server saw
+func handshake(t *testing.T, reloader *Reloader, clientOpts ClientOptions) ([]*x509.Certificate, error) {
+	t.Helper()
+	clientConfig, err := ClientConfig(clientOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", reloader.TLSConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	type result struct {
+		peers []*x509.Certificate
+		err   error
+	}
+	serverDone := make(chan result, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			serverDone <- result{err: err}
+			return
+		}
+		defer conn.Close()
+		server := conn.(*tls.Conn)
+		err = server.Handshake()
+		serverDone <- result{peers: server.ConnectionState().PeerCertificates, err: err}
+	}()
+
+	client, clientErr := tls.Dial("tcp", listener.Addr().String(), clientConfig)
+	if clientErr == nil {
+		defer client.Close()
+	}
+	// With TLS 1.3 the client finishes its side before the server has
+	// checked the client certificate, so the server decides the outcome
+	served := <-serverDone
+	if served.err != nil {
+		return nil, served.err
+	}
+	return served.peers, clientErr
+}
+
+func TestMutualTLS(t *testing.T) {
+	dir := initDir(t, "alice")
+	reloader, err := NewReloader(ServerOptions{
+		CertFile:          filepath.Join(dir, ServerFile),
+		KeyFile:           filepath.Join(dir, ServerKeyFile),
+		ClientCAFile:      filepath.Join(dir, CAFile),
+		RequireClientCert: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	peers, err := handshake(t, reloader, ClientOptions{
+		CAFile:     filepath.Join(dir, CAFile),
+		CertFile:   filepath.Join(dir, ClientFile),
+		KeyFile:    filepath.Join(dir, ClientKeyFile),
+		ServerName: "localhost",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(peers) == 0 || peers[0].Subject.CommonName != "alice" {
+		t.Errorf("server saw client certificates %v, want alice", peers)
+	}
+
+	if _, err := handshake(t, reloader, ClientOptions{CAFile: filepath.Join(dir, CAFile), ServerName: "localhost"}); err == nil {
+		t.Error("handshake without a client certificate succeeded")
+	}
+
+	// A certificate from another CA is rejected
+	other := initDir(t, "mallory")
+	_, err = handshake(t, reloader, ClientOptions{
+		CAFile:     filepath.Join(dir, CAFile),
+		CertFile:   filepath.Join(other, ClientFile),
+		KeyFile:    filepath.Join(other, ClientKeyFile),
+		ServerName: "localhost",
+	})
+	if err == nil {
+		t.Error("handshake with a client certificate of another CA succeeded")
+	}
+}
+
+func TestReload(t *testing.T) {
+	dir := initDir(t, "alice")
+	reloader, err := NewReloader(ServerOptions{
+		CertFile: filepath.Join(dir, ServerFile),
+		KeyFile:  filepath.Join(dir, ServerKeyFile),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := reloader.current()
+
+	// Regenerate the certificates and pretend the check interval passed
+	err = Init(dir, InitOptions{Hosts: []string{"localhost"}, ClientName: "alice", Validity: 24 * time.Hour, Overwrite: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	future := time.Now().Add(time.Minute)
+	if err := os.Chtimes(filepath.Join(dir, ServerFile), future, future); err != nil {
+		t.Fatal(err)
+	}
+	reloader.checkedAt = time.Time{}
+	reloaded := reloader.current()
+	if reloaded == first {
+		t.Fatal("configuration was not reloaded after the files changed")
+	}
+	_, err = handshake(t, reloader, ClientOptions{CAFile: filepath.Join(dir, CAFile), ServerName: "localhost"})
+	if err != nil {
+		t.Errorf("handshake against the reloaded certificate = %v", err)
+	}
+
+	// A broken file keeps the previous configuration in use
+	if err := os.WriteFile(filepath.Join(dir, ServerFile), []byte("broken"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	later := future.Add(time.Minute)
+	if err := os.Chtimes(filepath.Join(dir, ServerFile), later, later); err != nil {
+		t.Fatal(err)
+	}
+	reloader.checkedAt = time.Time{}
+	if reloader.current() != reloaded {
+		t.Error("a broken certificate file replaced the configuration")
+	}
+}
+
+func TestNewReloaderRejects(t *testing.T) {
+	dir := initDir(t, "alice")
+	tests := []struct {
+		name string
+		opts ServerOptions
+	}{
+		{name: "no key", opts: ServerOptions{CertFile: filepath.Join(dir, ServerFile)}},
+		{
+			name: "required client certificates without a CA",
+			opts: ServerOptions{CertFile: filepath.Join(dir, ServerFile), KeyFile: filepath.Join(dir, ServerKeyFile), RequireClientCert: true},
+		},
+		{
+			name: "missing file",
+			opts: ServerOptions{CertFile: filepath.Join(dir, "missing.pem"), KeyFile: filepath.Join(dir, ServerKeyFile)},
+		},
+		{
+			name: "key of another certificate",
+			opts: ServerOptions{CertFile: filepath.Join(dir, ServerFile), KeyFile: filepath.Join(dir, ClientKeyFile)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewReloader(tt.opts); err == nil {
+				t.Error("NewReloader() succeeded")
+			}
+		})
+	}
+
+	if _, err := LoadPool(filepath.Join(dir, ServerKeyFile)); err == nil {
+		t.Error("loading a key file as a CA pool succeeded")
+	}
+}
@@ -0,0 +1,206 @@
+package certs
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// checkInterval bounds how often the files are checked for changes
+const checkInterval = 5 * time.Second
+
+// ServerOptions locates the files the server's TLS configuration is built from
+type ServerOptions struct {
+	CertFile string
+	KeyFile  string
+	// ClientCAFile holds the CAs client certificates are verified against,
+	// empty disables client certificate verification
+	ClientCAFile string
+	// RequireClientCert rejects clients that present no certificate
+	RequireClientCert bool
+}
+
+// Reloader serves a TLS configuration that follows the certificate, key and
+// client CA files on disk. Changes are picked up on the next handshake
+// without a restart. If reloading fails, the previous configuration stays in
+// use.
+type Reloader struct {
+	opts ServerOptions
+
+	mu        sync.Mutex
+	config    *tls.Config
+	modTimes  map[string]time.Time
+	checkedAt time.Time
+}
+
+// NewReloader loads the files of opts and returns a reloader serving them
+func NewReloader(opts ServerOptions) (*Reloader, error) {
+	if opts.CertFile == "" || opts.KeyFile == "" {
+		return nil, errors.New("TLS requires a certificate and key file")
+	}
+	if opts.RequireClientCert && opts.ClientCAFile == "" {
+		return nil, errors.New("requiring client certificates needs a client CA file")
+	}
+
+	r := &Reloader{opts: opts}
+	modTimes, err := r.stat()
+	if err != nil {
+		return nil, err
+	}
+	config, err := r.load()
+	if err != nil {
+		return nil, err
+	}
+	r.config = config
+	r.modTimes = modTimes
+	r.checkedAt = time.Now()
+	return r, nil
+}
+
+// TLSConfig returns the server configuration to hand to the listener
+func (r *Reloader) TLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			return r.current(), nil
+		},
+	}
+}
+
+// current returns the configuration for a new handshake, reloading it first
+// when one of the files changed
+func (r *Reloader) current() *tls.Config {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if time.Since(r.checkedAt) < checkInterval {
+		return r.config
+	}
+	r.checkedAt = time.Now()
+
+	modTimes, err := r.stat()
+	if err != nil {
+		log.Printf("certs: %v", err)
+		return r.config
+	}
+	if sameModTimes(modTimes, r.modTimes) {
+		return r.config
+	}
+
+	config, err := r.load()
+	if err != nil {
+		log.Printf("certs: keeping previous certificates: %v", err)
+		return r.config
+	}
+	log.Printf("certs: reloaded %s", r.opts.CertFile)
+	r.config = config
+	r.modTimes = modTimes
+	return r.config
+}
+
+func (r *Reloader) load() (*tls.Config, error) {
+	certificate, err := tls.LoadX509KeyPair(r.opts.CertFile, r.opts.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load server certificate: %w", err)
+	}
+	config := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{certificate},
+		// The configuration returned per handshake replaces the one gRPC
+		// advertises HTTP/2 on, so ALPN has to be repeated here
+		NextProtos: []string{"h2"},
+	}
+
+	if r.opts.ClientCAFile != "" {
+		pool, err := LoadPool(r.opts.ClientCAFile)
+		if err != nil {
+			return nil, err
+		}
+		config.ClientCAs = pool
+		config.ClientAuth = tls.VerifyClientCertIfGiven
+		if r.opts.RequireClientCert {
+			config.ClientAuth = tls.RequireAndVerifyClientCert
+		}
+	}
+	return config, nil
+}
+
+// stat returns the modification times of the files
+func (r *Reloader) stat() (map[string]time.Time, error) {
+	modTimes := make(map[string]time.Time)
+	for _, path := range []string{r.opts.CertFile, r.opts.KeyFile, r.opts.ClientCAFile} {
+		if path == "" {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat %s: %w", path, err)
+		}
+		modTimes[path] = info.ModTime()
+	}
+	return modTimes, nil
+}
+
+func sameModTimes(left, right map[string]time.Time) bool {
+	if len(left) != len(right) {
+		return false
+	}
+	for path, modTime := range left {
+		if !right[path].Equal(modTime) {
+			return false
+		}
+	}
+	return true
+}
+
+// ClientOptions locates the files a client's TLS configuration is built from
+type ClientOptions struct {
+	// CAFile holds the CAs the server certificate is verified against, empty
+	// uses the system roots
+	CAFile string
+	// CertFile and KeyFile hold the client certificate presented for mTLS
+	CertFile   string
+	KeyFile    string
+	ServerName string
+}
+
+// ClientConfig builds the TLS configuration of a client
+func ClientConfig(opts ClientOptions) (*tls.Config, error) {
+	config := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: opts.ServerName,
+	}
+	if opts.CAFile != "" {
+		pool, err := LoadPool(opts.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		config.RootCAs = pool
+	}
+	if opts.CertFile != "" || opts.KeyFile != "" {
+		certificate, err := tls.LoadX509KeyPair(opts.CertFile, opts.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{certificate}
+	}
+	return config, nil
+}
+
+// LoadPool reads a PEM bundle of CA certificates
+func LoadPool(path string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA file %s: %w", path, err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no certificates found in CA file %s", path)
+	}
+	return pool, nil
+}
@@ -0,0 +1,344 @@
+package compose
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// project is the part of a docker-compose file the importer understands.
+// Keys it does not know end up in Extra so that they can be reported.
+type project struct {
+	Name     string                    `yaml:"name"`
+	Version  string                    `yaml:"version"`
+	Services map[string]*service       `yaml:"services"`
+	Volumes  map[string]*namedVolume   `yaml:"volumes"`
+	Networks map[string]map[string]any `yaml:"networks"`
+	Extra    map[string]any            `yaml:",inline"`
+}
+
+type namedVolume struct {
+	// Name overrides the project-prefixed name of the volume
+	Name     string         `yaml:"name"`
+	External bool           `yaml:"external"`
+	Extra    map[string]any `yaml:",inline"`
+}
+
+type service struct {
+	Image         string         `yaml:"image"`
+	Build         any            `yaml:"build"`
+	ContainerName string         `yaml:"container_name"`
+	Command       words          `yaml:"command"`
+	Entrypoint    words          `yaml:"entrypoint"`
+	Environment   environment    `yaml:"environment"`
+	EnvFile       stringList     `yaml:"env_file"`
+	Ports         []port         `yaml:"ports"`
+	Expose        []string       `yaml:"expose"`
+	Volumes       []mount        `yaml:"volumes"`
+	Healthcheck   *healthcheck   `yaml:"healthcheck"`
+	Restart       string         `yaml:"restart"`
+	DependsOn     dependsOn      `yaml:"depends_on"`
+	Networks      stringList     `yaml:"networks"`
+	Deploy        *deploy        `yaml:"deploy"`
+	MemLimit      string         `yaml:"mem_limit"`
+	CPUs          string         `yaml:"cpus"`
+	Extra         map[string]any `yaml:",inline"`
+}
+
+type healthcheck struct {
+	Test        words  `yaml:"test"`
+	Interval    string `yaml:"interval"`
+	Timeout     string `yaml:"timeout"`
+	Retries     int32  `yaml:"retries"`
+	StartPeriod string `yaml:"start_period"`
+	Disable     bool   `yaml:"disable"`
+}
+
+type deploy struct {
+	Replicas  *int `yaml:"replicas"`
+	Resources struct {
+		Limits struct {
+			CPUs   string `yaml:"cpus"`
+			Memory string `yaml:"memory"`
+		} `yaml:"limits"`
+	} `yaml:"resources"`
+	Extra map[string]any `yaml:",inline"`
+}
+
+// words is a command given as a list or as a single shell-style string
+type words struct {
+	Values []string
+	// Shell is set when the value was a string, which compose runs split
+	// into words
+	Shell bool
+}
+
+func (w *words) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		w.Shell = true
+		values, err := splitWords(node.Value)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", node.Line, err)
+		}
+		w.Values = values
+		return nil
+	}
+	return node.Decode(&w.Values)
+}
+
+// stringList is a list that may also be given as a single string, or as a
+// mapping whose keys are the values, such as networks with options
+type stringList []string
+
+func (l *stringList) UnmarshalYAML(node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		*l = stringList{node.Value}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			*l = append(*l, node.Content[i].Value)
+		}
+	default:
+		var values []string
+		if err := node.Decode(&values); err != nil {
+			return err
+		}
+		*l = values
+	}
+	return nil
+}
+
+// environment holds variables given as a KEY=VALUE list or a mapping. A nil
+// value is a variable compose would take from the shell.
+type environment map[string]*string
+
+func (e *environment) UnmarshalYAML(node *yaml.Node) error {
+	*e = make(environment)
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i].Value, node.Content[i+1]
+			if value.Tag == "!!null" {
+				(*e)[key] = nil
+				continue
+			}
+			text := value.Value
+			(*e)[key] = &text
+		}
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			key, value, ok := strings.Cut(item.Value, "=")
+			if !ok {
+				(*e)[key] = nil
+				continue
+			}
+			(*e)[key] = &value
+		}
+	default:
+		return fmt.Errorf("line %d: environment must be a list or a mapping", node.Line)
+	}
+	return nil
+}
+
+// dependsOn holds the services a service depends on with their condition,
+// from either the list or the mapping form
+type dependsOn map[string]string
+
+func (d *dependsOn) UnmarshalYAML(node *yaml.Node) error {
+	*d = make(dependsOn)
+	switch node.Kind {
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			(*d)[item.Value] = "service_started"
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			var options struct {
+				Condition string `yaml:"condition"`
+			}
+			if err := node.Content[i+1].Decode(&options); err != nil {
+				return err
+			}
+			if options.Condition == "" {
+				options.Condition = "service_started"
+			}
+			(*d)[node.Content[i].Value] = options.Condition
+		}
+	default:
+		return fmt.Errorf("line %d: depends_on must be a list or a mapping", node.Line)
+	}
+	return nil
+}
+
+// port is a published port from either the short "[ip:][host:]container[/protocol]"
+// syntax or the long syntax
+type port struct {
+	HostIP    string
+	Host      string
+	Container string
+	Protocol  string
+}
+
+func (p *port) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.MappingNode {
+		var long struct {
+			Target    string `yaml:"target"`
+			Published string `yaml:"published"`
+			Protocol  string `yaml:"protocol"`
+			HostIP    string `yaml:"host_ip"`
+		}
+		if err := node.Decode(&long); err != nil {
+			return err
+		}
+		*p = port{HostIP: long.HostIP, Host: long.Published, Container: long.Target, Protocol: long.Protocol}
+		return nil
+	}
+
+	spec, protocol, _ := strings.Cut(node.Value, "/")
+	p.Protocol = protocol
+	// The host IP may be an IPv6 address in brackets, which holds colons
+	if strings.HasPrefix(spec, "[") {
+		end := strings.Index(spec, "]:")
+		if end < 0 {
+			return fmt.Errorf("line %d: invalid port %q", node.Line, node.Value)
+		}
+		p.HostIP, spec = spec[1:end], spec[end+2:]
+	}
+	parts := strings.Split(spec, ":")
+	switch len(parts) {
+	case 1:
+		p.Container = parts[0]
+	case 2:
+		p.Host, p.Container = parts[0], parts[1]
+	case 3:
+		p.HostIP, p.Host, p.Container = parts[0], parts[1], parts[2]
+	default:
+		return fmt.Errorf("line %d: invalid port %q", node.Line, node.Value)
+	}
+	return nil
+}
+
+// mount is a volume from either the short "source:target[:mode]" syntax or
+// the long syntax. Type is volume, bind or tmpfs.
+type mount struct {
+	Type     string
+	Source   string
+	Target   string
+	ReadOnly bool
+}
+
+func (m *mount) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.MappingNode {
+		var long struct {
+			Type     string `yaml:"type"`
+			Source   string `yaml:"source"`
+			Target   string `yaml:"target"`
+			ReadOnly bool   `yaml:"read_only"`
+		}
+		if err := node.Decode(&long); err != nil {
+			return err
+		}
+		*m = mount{Type: long.Type, Source: long.Source, Target: long.Target, ReadOnly: long.ReadOnly}
+		if m.Type == "" {
+			m.Type = mountType(m.Source)
+		}
+		return nil
+	}
+
+	parts := strings.Split(node.Value, ":")
+	switch len(parts) {
+	case 1:
+		m.Target = parts[0]
+	case 2:
+		m.Source, m.Target = parts[0], parts[1]
+	case 3:
+		m.Source, m.Target = parts[0], parts[1]
+		for _, option := range strings.Split(parts[2], ",") {
+			if option == "ro" {
+				m.ReadOnly = true
+			}
+		}
+	default:
+		return fmt.Errorf("line %d: invalid volume %q", node.Line, node.Value)
+	}
+	m.Type = mountType(m.Source)
+	return nil
+}
+
+// mountType tells bind mounts, whose source is a path, from named volumes
+func mountType(source string) string {
+	if strings.HasPrefix(source, "/") || strings.HasPrefix(source, ".") || strings.HasPrefix(source, "~") {
+		return "bind"
+	}
+	return "volume"
+}
+
+// splitWords splits a command line into words the way a POSIX shell does
+// for quoting, without expanding anything
+func splitWords(line string) ([]string, error) {
+	var words []string
+	var current strings.Builder
+	inWord := false
+	var quote rune
+	escaped := false
+	for _, r := range line {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped, inWord = true, true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote, inWord = r, true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inWord {
+				words = append(words, current.String())
+				current.Reset()
+				inWord = false
+			}
+		default:
+			current.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated quote in %q", line)
+	}
+	if inWord {
+		words = append(words, current.String())
+	}
+	return words, nil
+}
+
+// parseMegabytes reads a compose byte size such as 512m, 1g or 1073741824
+// and rounds it up to whole megabytes
+func parseMegabytes(size string) (int64, error) {
+	text := strings.ToLower(strings.TrimSpace(size))
+	text = strings.TrimSuffix(text, "b")
+	multiplier := int64(1)
+	switch {
+	case strings.HasSuffix(text, "k"):
+		multiplier = 1 << 10
+	case strings.HasSuffix(text, "m"):
+		multiplier = 1 << 20
+	case strings.HasSuffix(text, "g"):
+		multiplier = 1 << 30
+	}
+	if multiplier != 1 {
+		text = text[:len(text)-1]
+	}
+	value, err := strconv.ParseFloat(text, 64)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("invalid size %q", size)
+	}
+	bytes := int64(value * float64(multiplier))
+	return (bytes + 1<<20 - 1) >> 20, nil
+}
@@ -0,0 +1,256 @@
+package compose
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+
+	pb "scheduler/proto/gen"
+)
+
+// writeProject writes a compose file and the files next to it into a
+// directory named after the project and returns the path of the compose file
+func writeProject(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := filepath.Join(t.TempDir(), "Shop App")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return filepath.Join(dir, "docker-compose.yml")
+}
+
+const shop = `services:
+  db:
+    image: postgres:16
+    environment:
+      POSTGRES_DB: shop
+      POSTGRES_USER: shop
+      POSTGRES_PASSWORD: hunter2
+    volumes:
+      - pgdata:/var/lib/postgresql/data
+  api:
+    image: shop/api:1
+    command: ["serve", "--port", "8080"]
+    env_file: api.env
+    environment:
+      DATABASE_URL: postgres://shop@db:5432/shop
+      LOG_LEVEL: debug
+    depends_on:
+      - db
+    ports:
+      - "8080:8080"
+    healthcheck:
+      test: ["CMD-SHELL", "curl -f localhost:8080"]
+      interval: 1m30s
+    restart: on-failure:3
+    deploy:
+      resources:
+        limits:
+          memory: 512m
+          cpus: "0.5"
+  web:
+    image: nginx:1.27
+    depends_on: [api]
+    ports:
+      - 127.0.0.1:80:80/tcp
+    volumes:
+      - ./site:/usr/share/nginx/html:ro
+  cache:
+    image: redis:7
+    container_name: shop-cache
+volumes:
+  pgdata:
+`
+
+func TestLoad(t *testing.T) {
+	path := writeProject(t, map[string]string{
+		"docker-compose.yml": shop,
+		"api.env":            "# defaults\nLOG_LEVEL=info\nexport FEATURE='on'\n",
+	})
+	result, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantRoles := map[string]string{"db": "database", "api": "backend", "web": "frontend"}
+	if !reflect.DeepEqual(result.Roles, wantRoles) {
+		t.Errorf("roles = %v, want %v", result.Roles, wantRoles)
+	}
+
+	dir := filepath.Dir(path)
+	want := &pb.EnvironmentSpecification{
+		Name: "shopapp",
+		ApplicationStack: &pb.ApplicationStack{
+			Name: "shopapp",
+			Database: &pb.DatabaseConfig{
+				Container: &pb.ContainerConfig{
+					Name:    "db",
+					Image:   "postgres:16",
+					Volumes: []*pb.VolumeMount{{Name: "shopapp_pgdata", MountPath: "/var/lib/postgresql/data"}},
+				},
+				DatabaseName:      "shop",
+				Username:          "shop",
+				Password:          "hunter2",
+				PersistentStorage: true,
+			},
+			Backend: &pb.BackendConfig{Container: &pb.ContainerConfig{
+				Name:  "api",
+				Image: "shop/api:1",
+				Args:  []string{"serve", "--port", "8080"},
+				EnvironmentVariables: map[string]string{
+					"DATABASE_URL": "postgres://shop@db:5432/shop",
+					"LOG_LEVEL":    "debug",
+					"FEATURE":      "on",
+				},
+				Ports: []*pb.PortMapping{{ContainerPort: 8080, HostPort: 8080, Protocol: "tcp"}},
+				HealthCheck: &pb.HealthCheck{
+					Command:         []string{"/bin/sh", "-c", "curl -f localhost:8080"},
+					IntervalSeconds: 90,
+				},
+				RestartPolicy: pb.RestartPolicy_RESTART_POLICY_ON_FAILURE,
+				Resources:     &pb.ResourceLimits{MemoryMb: 512, CpuCores: 0.5},
+			}},
+			Frontend: &pb.FrontendConfig{Container: &pb.ContainerConfig{
+				Name:    "web",
+				Image:   "nginx:1.27",
+				Ports:   []*pb.PortMapping{{ContainerPort: 80, HostPort: 80, Protocol: "tcp"}},
+				Volumes: []*pb.VolumeMount{{Name: "site", MountPath: "/usr/share/nginx/html", HostPath: filepath.Join(dir, "site"), ReadOnly: true}},
+			}},
+			AdditionalServices: map[string]*pb.ContainerConfig{
+				"cache": {Name: "cache", Image: "redis:7"},
+			},
+		},
+	}
+	if !proto.Equal(result.Spec, want) {
+		t.Errorf("spec = %v\nwant %v", result.Spec, want)
+	}
+
+	for _, warning := range []string{
+		"service api: the retry limit of restart on-failure:3 was dropped",
+		"service web: port 80 is published on all addresses instead of 127.0.0.1 only",
+		"service cache: container_name shop-cache was ignored",
+		"the Postgres password is stored in the manifest in plain text",
+	} {
+		if !containsPrefix(result.Warnings, warning) {
+			t.Errorf("warnings = %q, want %q", result.Warnings, warning)
+		}
+	}
+}
+
+func containsPrefix(values []string, prefix string) bool {
+	for _, value := range values {
+		if strings.HasPrefix(value, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestLoadRoles(t *testing.T) {
+	tests := []struct {
+		name     string
+		services string
+		want     map[string]string
+	}{
+		{
+			// The Postgres the others depend on is the application database
+			name: "two databases",
+			services: `  analytics:
+    image: timescale/timescaledb
+  main:
+    image: postgres:16
+  server:
+    image: app:1
+    depends_on: [main]
+`,
+			want: map[string]string{"main": "database", "server": "backend"},
+		},
+		{
+			name: "web server without backend",
+			services: `  proxy:
+    image: caddy:2
+    ports: ["443:443"]
+  worker:
+    image: jobs:1
+`,
+			want: map[string]string{"proxy": "frontend"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Load(writeProject(t, map[string]string{"docker-compose.yml": "services:\n" + tt.services}))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(result.Roles, tt.want) {
+				t.Errorf("roles = %v, want %v", result.Roles, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	if _, err := Load(writeProject(t, map[string]string{"docker-compose.yml": "version: '3'\n"})); err == nil {
+		t.Error("loading a file without services succeeded")
+	}
+	if _, err := Load(writeProject(t, map[string]string{"docker-compose.yml": "services:\n  api:\n    image: x\n    env_file: missing.env\n"})); err == nil {
+		t.Error("loading a missing env_file succeeded")
+	}
+	if _, err := Load(writeProject(t, map[string]string{"docker-compose.yml": "services:\n  api:\n    ports: ['1:2:3:4']\n"})); err == nil {
+		t.Error("loading an invalid port succeeded")
+	}
+}
+
+func TestSplitWords(t *testing.T) {
+	tests := []struct {
+		line    string
+		want    []string
+		wantErr bool
+	}{
+		{line: `serve --port 8080`, want: []string{"serve", "--port", "8080"}},
+		{line: `sh -c 'echo "hi there"'`, want: []string{"sh", "-c", `echo "hi there"`}},
+		{line: `echo a\ b ""`, want: []string{"echo", "a b", ""}},
+		{line: `echo 'open`, wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := splitWords(tt.line)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("splitWords(%q) error = %v, wantErr %v", tt.line, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitWords(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestParseMegabytes(t *testing.T) {
+	tests := []struct {
+		size    string
+		want    int64
+		wantErr bool
+	}{
+		{size: "512m", want: 512},
+		{size: "1g", want: 1024},
+		{size: "1.5GB", want: 1536},
+		{size: "1073741824", want: 1024},
+		{size: "100k", want: 1},
+		{size: "lots", wantErr: true},
+		{size: "-1m", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseMegabytes(tt.size)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseMegabytes(%q) = %d, %v, want %d", tt.size, got, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,490 @@
+package compose
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"scheduler/internal/stack"
+	pb "scheduler/proto/gen"
+)
+
+// Result is an environment translated from a compose file
+type Result struct {
+	Spec *pb.EnvironmentSpecification
+	// Roles maps services to the stack role they were given, frontend,
+	// backend or database, the others being additional services
+	Roles map[string]string
+	// Warnings name the compose features that could not be represented
+	Warnings []string
+}
+
+// Load translates the docker-compose file at path into an environment
+// specification. Postgres, frontend and backend services are recognized by
+// their image, name and ports, and the rest become additional services.
+// Relative paths in the file are resolved against its directory.
+func Load(path string) (*Result, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	var p project
+	if err := yaml.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if len(p.Services) == 0 {
+		return nil, fmt.Errorf("%s defines no services", path)
+	}
+	dir, err := filepath.Abs(filepath.Dir(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", path, err)
+	}
+
+	c := &converter{project: &p, dir: dir, name: p.Name}
+	if c.name == "" {
+		// Compose names projects after their directory as well
+		c.name = filepath.Base(dir)
+	}
+	c.name = projectName(c.name)
+	return c.convert()
+}
+
+type converter struct {
+	project  *project
+	dir      string
+	name     string
+	warnings []string
+}
+
+func (c *converter) warn(format string, args ...any) {
+	c.warnings = append(c.warnings, fmt.Sprintf(format, args...))
+}
+
+func (c *converter) convert() (*Result, error) {
+	for _, key := range sortedKeys(c.project.Extra) {
+		c.warn("top-level %s is not supported and was ignored", key)
+	}
+	for _, name := range sortedKeys(c.project.Volumes) {
+		if volume := c.project.Volumes[name]; volume != nil && len(volume.Extra) > 0 {
+			c.warn("volume %s: %s not supported, volumes are local directories", name, strings.Join(sortedKeys(volume.Extra), ", "))
+		}
+	}
+
+	names := sortedKeys(c.project.Services)
+	containers := make(map[string]*pb.ContainerConfig, len(names))
+	for _, name := range names {
+		container, err := c.container(name, c.project.Services[name])
+		if err != nil {
+			return nil, err
+		}
+		containers[name] = container
+	}
+
+	roles := c.assignRoles(names)
+	app := &pb.ApplicationStack{Name: c.name}
+	for _, name := range names {
+		switch roles[name] {
+		case stack.RoleDatabase:
+			app.Database = c.database(containers[name])
+		case stack.RoleBackend:
+			app.Backend = &pb.BackendConfig{Container: containers[name]}
+		case stack.RoleFrontend:
+			app.Frontend = &pb.FrontendConfig{Container: containers[name]}
+		default:
+			if app.AdditionalServices == nil {
+				app.AdditionalServices = make(map[string]*pb.ContainerConfig)
+			}
+			app.AdditionalServices[name] = containers[name]
+		}
+	}
+	c.checkStartupOrder(names, app)
+
+	spec := &pb.EnvironmentSpecification{
+		Name:             c.name,
+		ApplicationStack: app,
+		Network:          c.network(names),
+	}
+	return &Result{Spec: spec, Roles: roles, Warnings: c.warnings}, nil
+}
+
+// container translates the settings of one service
+func (c *converter) container(name string, s *service) (*pb.ContainerConfig, error) {
+	if s == nil {
+		s = &service{}
+	}
+	// Services keep their compose name, which the others use as host name
+	container := &pb.ContainerConfig{Name: name, Image: s.Image}
+	if s.ContainerName != "" && s.ContainerName != name {
+		c.warn("service %s: container_name %s was ignored so that other services can still reach it as %s", name, s.ContainerName, name)
+	}
+	if s.Build != nil {
+		if s.Image == "" {
+			c.warn("service %s: build is not supported, set image to an image built from it", name)
+		} else {
+			c.warn("service %s: build was ignored, the image %s is used as is", name, s.Image)
+		}
+	}
+
+	// The compose entrypoint is the command of the container and the
+	// compose command its arguments
+	container.Command = s.Entrypoint.Values
+	container.Args = s.Command.Values
+
+	env, err := c.environment(name, s)
+	if err != nil {
+		return nil, err
+	}
+	container.EnvironmentVariables = env
+
+	for _, p := range s.Ports {
+		mapping, ok := c.port(name, p)
+		if ok {
+			container.Ports = append(container.Ports, mapping)
+		}
+	}
+	if len(s.Expose) > 0 {
+		c.warn("service %s: expose is not needed, containers of an environment reach each other on every port", name)
+	}
+	for _, m := range s.Volumes {
+		if mount, ok := c.mount(name, m); ok {
+			container.Volumes = append(container.Volumes, mount)
+		}
+	}
+
+	container.HealthCheck = c.healthCheck(name, s.Healthcheck)
+	container.RestartPolicy = c.restartPolicy(name, s.Restart)
+	container.Resources = c.resources(name, s)
+
+	for _, key := range sortedKeys(s.Extra) {
+		c.warn("service %s: %s is not supported and was ignored", name, key)
+	}
+	return container, nil
+}
+
+func (c *converter) environment(name string, s *service) (map[string]string, error) {
+	env := make(map[string]string)
+	// Files are read first so that the environment key overrides them, as
+	// in compose
+	for _, file := range s.EnvFile {
+		values, err := readEnvFile(c.resolve(file))
+		if err != nil {
+			return nil, fmt.Errorf("service %s: %w", name, err)
+		}
+		for key, value := range values {
+			env[key] = value
+		}
+	}
+	for _, key := range sortedKeys(s.Environment) {
+		value := s.Environment[key]
+		if value == nil {
+			c.warn("service %s: %s takes its value from the shell, set it explicitly", name, key)
+			continue
+		}
+		if strings.Contains(*value, "${") {
+			c.warn("service %s: %s uses variable substitution, which is not supported", name, key)
+		}
+		env[key] = *value
+	}
+	if len(env) == 0 {
+		return nil, nil
+	}
+	return env, nil
+}
+
+func (c *converter) port(name string, p port) (*pb.PortMapping, bool) {
+	if strings.Contains(p.Container, "-") || strings.Contains(p.Host, "-") {
+		c.warn("service %s: port range %s was ignored, map each port on its own", name, p.Container)
+		return nil, false
+	}
+	if p.HostIP != "" {
+		c.warn("service %s: port %s is published on all addresses instead of %s only", name, p.Container, p.HostIP)
+	}
+	containerPort, err := strconv.ParseInt(p.Container, 10, 32)
+	if err != nil {
+		c.warn("service %s: invalid port %q was ignored", name, p.Container)
+		return nil, false
+	}
+	mapping := &pb.PortMapping{ContainerPort: int32(containerPort), Protocol: p.Protocol}
+	if mapping.Protocol == "" {
+		mapping.Protocol = "tcp"
+	}
+	if p.Host != "" {
+		hostPort, err := strconv.ParseInt(p.Host, 10, 32)
+		if err != nil {
+			c.warn("service %s: invalid host port %q was ignored", name, p.Host)
+			return nil, false
+		}
+		mapping.HostPort = int32(hostPort)
+	}
+	return mapping, true
+}
+
+func (c *converter) mount(name string, m mount) (*pb.VolumeMount, bool) {
+	switch m.Type {
+	case "bind":
+		source := c.resolve(m.Source)
+		return &pb.VolumeMount{Name: volumeName(filepath.Base(source)), MountPath: m.Target, HostPath: source, ReadOnly: m.ReadOnly}, true
+	case "volume":
+		if m.Source == "" {
+			// Anonymous volumes get a name of their own so that the data
+			// survives restarts like it would in compose
+			generated := c.name + "_" + name + "_" + volumeName(strings.Trim(m.Target, "/"))
+			c.warn("service %s: anonymous volume %s became the named volume %s", name, m.Target, generated)
+			return &pb.VolumeMount{Name: generated, MountPath: m.Target, ReadOnly: m.ReadOnly}, true
+		}
+		return &pb.VolumeMount{Name: c.volumeName(name, m.Source), MountPath: m.Target, ReadOnly: m.ReadOnly}, true
+	default:
+		c.warn("service %s: %s mount on %s is not supported and was ignored", name, m.Type, m.Target)
+		return nil, false
+	}
+}
+
+// volumeName maps a compose volume to a scheduler volume, prefixed with the
+// project name like compose does unless the volume sets its own name
+func (c *converter) volumeName(service, source string) string {
+	volume, declared := c.project.Volumes[source]
+	if !declared {
+		c.warn("service %s: volume %s is not declared in the top-level volumes", service, source)
+	}
+	if volume != nil && volume.Name != "" {
+		return volume.Name
+	}
+	if volume != nil && volume.External {
+		return source
+	}
+	return c.name + "_" + source
+}
+
+func (c *converter) healthCheck(name string, h *healthcheck) *pb.HealthCheck {
+	if h == nil || h.Disable || len(h.Test.Values) == 0 {
+		return nil
+	}
+	check := &pb.HealthCheck{Retries: h.Retries}
+	switch test := h.Test.Values; {
+	case h.Test.Shell:
+		check.Command = []string{"/bin/sh", "-c", strings.Join(test, " ")}
+	case test[0] == "NONE":
+		return nil
+	case test[0] == "CMD":
+		check.Command = test[1:]
+	case test[0] == "CMD-SHELL":
+		check.Command = []string{"/bin/sh", "-c", strings.Join(test[1:], " ")}
+	default:
+		check.Command = test
+	}
+	check.IntervalSeconds = c.seconds(name, "interval", h.Interval)
+	check.TimeoutSeconds = c.seconds(name, "timeout", h.Timeout)
+	check.StartPeriodSeconds = c.seconds(name, "start_period", h.StartPeriod)
+	return check
+}
+
+// seconds converts a compose duration such as 1m30s, rounding up
+func (c *converter) seconds(name, field, value string) int32 {
+	if value == "" {
+		return 0
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		c.warn("service %s: invalid healthcheck %s %q was ignored", name, field, value)
+		return 0
+	}
+	return int32((duration + time.Second - 1) / time.Second)
+}
+
+func (c *converter) restartPolicy(name, restart string) pb.RestartPolicy {
+	policy, retries, _ := strings.Cut(restart, ":")
+	switch policy {
+	case "":
+		return pb.RestartPolicy_RESTART_POLICY_UNSPECIFIED
+	case "no":
+		return pb.RestartPolicy_RESTART_POLICY_NO
+	case "always":
+		return pb.RestartPolicy_RESTART_POLICY_ALWAYS
+	case "on-failure":
+		if retries != "" {
+			c.warn("service %s: the retry limit of restart %s was dropped", name, restart)
+		}
+		return pb.RestartPolicy_RESTART_POLICY_ON_FAILURE
+	case "unless-stopped":
+		return pb.RestartPolicy_RESTART_POLICY_UNLESS_STOPPED
+	default:
+		c.warn("service %s: unknown restart policy %q was ignored", name, restart)
+		return pb.RestartPolicy_RESTART_POLICY_UNSPECIFIED
+	}
+}
+
+// resources reads the limits from deploy.resources or the older mem_limit
+// and cpus keys
+func (c *converter) resources(name string, s *service) *pb.ResourceLimits {
+	memory, cpus := s.MemLimit, s.CPUs
+	if s.Deploy != nil {
+		if limits := s.Deploy.Resources.Limits; limits.Memory != "" || limits.CPUs != "" {
+			memory, cpus = limits.Memory, limits.CPUs
+		}
+		if s.Deploy.Replicas != nil && *s.Deploy.Replicas != 1 {
+			c.warn("service %s: replicas is not supported, one container is run", name)
+		}
+		for _, key := range sortedKeys(s.Deploy.Extra) {
+			c.warn("service %s: deploy.%s is not supported and was ignored", name, key)
+		}
+	}
+	if memory == "" && cpus == "" {
+		return nil
+	}
+
+	limits := &pb.ResourceLimits{}
+	if memory != "" {
+		megabytes, err := parseMegabytes(memory)
+		if err != nil {
+			c.warn("service %s: memory limit: %v", name, err)
+		}
+		limits.MemoryMb = megabytes
+	}
+	if cpus != "" {
+		cores, err := strconv.ParseFloat(cpus, 64)
+		if err != nil {
+			c.warn("service %s: invalid cpus %q was ignored", name, cpus)
+		}
+		limits.CpuCores = cores
+	}
+	return limits
+}
+
+// database fills the database settings from the variables of the official
+// Postgres image. The variables are dropped from the container since the
+// scheduler sets them from the database settings.
+func (c *converter) database(container *pb.ContainerConfig) *pb.DatabaseConfig {
+	env := container.GetEnvironmentVariables()
+	db := &pb.DatabaseConfig{
+		Container:    container,
+		DatabaseName: env["POSTGRES_DB"],
+		Username:     env["POSTGRES_USER"],
+		Password:     env["POSTGRES_PASSWORD"],
+	}
+	for _, key := range []string{"POSTGRES_DB", "POSTGRES_USER", "POSTGRES_PASSWORD"} {
+		delete(env, key)
+	}
+	if db.Password != "" {
+		c.warn("the Postgres password is stored in the manifest in plain text, consider a secret:// reference")
+	}
+	for _, m := range container.GetVolumes() {
+		if strings.HasPrefix(m.GetMountPath(), "/var/lib/postgresql/data") {
+			db.PersistentStorage = true
+			db.StoragePath = m.GetHostPath()
+		}
+	}
+	return db
+}
+
+// network keeps the name of the network the services share. Environments
+// have one network, so several compose networks are merged.
+func (c *converter) network(names []string) *pb.NetworkConfig {
+	used := make(map[string]bool)
+	for _, name := range names {
+		if s := c.project.Services[name]; s != nil {
+			for _, network := range s.Networks {
+				used[network] = true
+			}
+		}
+	}
+	if len(used) == 0 {
+		return nil
+	}
+	networks := sortedKeys(used)
+	if len(networks) > 1 {
+		c.warn("networks %s were merged into one, every service can reach every other", strings.Join(networks, ", "))
+	}
+	return &pb.NetworkConfig{NetworkName: c.name + "_" + networks[0]}
+}
+
+// checkStartupOrder warns about dependencies the fixed startup order of the
+// stack does not satisfy
+func (c *converter) checkStartupOrder(names []string, app *pb.ApplicationStack) {
+	position := make(map[string]int)
+	for i, member := range stack.Members(app) {
+		position[member.Container.GetName()] = i
+	}
+	for _, name := range names {
+		s := c.project.Services[name]
+		if s == nil {
+			continue
+		}
+		for _, dependency := range sortedKeys(s.DependsOn) {
+			if _, ok := position[dependency]; !ok {
+				c.warn("service %s: depends on unknown service %s", name, dependency)
+				continue
+			}
+			if position[dependency] > position[name] {
+				c.warn("service %s: depends on %s, which starts after it; the database, backend and frontend start before additional services", name, dependency)
+			}
+		}
+	}
+}
+
+func (c *converter) resolve(path string) string {
+	if strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, path[2:])
+		}
+	}
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(c.dir, path)
+}
+
+// readEnvFile reads KEY=VALUE lines, skipping blank lines and comments
+func readEnvFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read env_file: %w", err)
+	}
+	values := make(map[string]string)
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, _ := strings.Cut(strings.TrimPrefix(line, "export "), "=")
+		values[strings.TrimSpace(key)] = strings.Trim(strings.TrimSpace(value), `"'`)
+	}
+	return values, nil
+}
+
+var invalidNameCharacters = regexp.MustCompile(`[^a-zA-Z0-9_.-]+`)
+
+// volumeName turns a path element into a valid volume name
+func volumeName(text string) string {
+	name := strings.Trim(invalidNameCharacters.ReplaceAllString(text, "_"), "_.-")
+	if name == "" {
+		return "data"
+	}
+	return name
+}
+
+// projectName normalizes a project name the way compose does: lower case
+// letters, digits, dashes and underscores
+func projectName(name string) string {
+	name = strings.ToLower(name)
+	name = regexp.MustCompile(`[^a-z0-9_-]+`).ReplaceAllString(name, "")
+	if name == "" {
+		return "compose"
+	}
+	return name
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,165 @@
+package compose
+
+import (
+	"regexp"
+	"strings"
+
+	"scheduler/internal/stack"
+)
+
+var (
+	postgresImage  = regexp.MustCompile(`postgres|postgis|timescale`)
+	webServerImage = regexp.MustCompile(`^(nginx|httpd|caddy|traefik|haproxy)`)
+	backendName    = regexp.MustCompile(`(^|[-_])(backend|api|server|app)([-_]|$)`)
+	frontendName   = regexp.MustCompile(`(^|[-_])(frontend|web|ui|client|proxy|nginx)([-_]|$)`)
+)
+
+// assignRoles picks the services that become the database, backend and
+// frontend of the stack. Postgres is recognized by its image, the backend by
+// its name and its dependency on the database, and the frontend by its name,
+// web server image and published HTTP ports.
+func (c *converter) assignRoles(names []string) map[string]string {
+	roles := make(map[string]string)
+
+	var databases []string
+	for _, name := range names {
+		if postgresImage.MatchString(imageName(c.service(name).Image)) {
+			databases = append(databases, name)
+		}
+	}
+	database := ""
+	if len(databases) > 0 {
+		// The one the others depend on is the application database
+		database = databases[0]
+		for _, candidate := range databases {
+			if c.dependedOn(candidate) {
+				database = candidate
+				break
+			}
+		}
+		roles[database] = stack.RoleDatabase
+		for _, other := range databases {
+			if other != database {
+				c.warn("service %s also runs Postgres and became an additional service, %s is the database", other, database)
+			}
+		}
+	}
+
+	backend := c.best(names, roles, func(name string) int {
+		score := c.backendScore(name, database)
+		if score <= c.frontendScore(name, "") {
+			return 0
+		}
+		return score
+	})
+	if backend != "" {
+		roles[backend] = stack.RoleBackend
+	}
+	frontend := c.best(names, roles, func(name string) int {
+		return c.frontendScore(name, backend)
+	})
+	if frontend != "" {
+		roles[frontend] = stack.RoleFrontend
+	}
+	return roles
+}
+
+// best returns the unassigned service with the highest positive score, the
+// first in name order on ties
+func (c *converter) best(names []string, roles map[string]string, score func(string) int) string {
+	best, bestScore := "", 0
+	for _, name := range names {
+		if _, taken := roles[name]; taken {
+			continue
+		}
+		if s := score(name); s > bestScore {
+			best, bestScore = name, s
+		}
+	}
+	return best
+}
+
+func (c *converter) backendScore(name, database string) int {
+	s := c.service(name)
+	score := 0
+	if backendName.MatchString(name) {
+		score += 3
+	}
+	if database != "" {
+		if _, ok := s.DependsOn[database]; ok {
+			score += 2
+		} else if referencesHost(s.Environment, database) {
+			score += 2
+		}
+	}
+	if webServerImage.MatchString(imageName(s.Image)) {
+		score -= 3
+	}
+	return score
+}
+
+func (c *converter) frontendScore(name, backend string) int {
+	s := c.service(name)
+	score := 0
+	if frontendName.MatchString(name) {
+		score += 3
+	}
+	if webServerImage.MatchString(imageName(s.Image)) {
+		score += 2
+	}
+	for _, p := range s.Ports {
+		if p.Container == "80" || p.Container == "443" || p.Host == "80" || p.Host == "443" {
+			score += 2
+			break
+		}
+	}
+	if backend != "" {
+		if _, ok := s.DependsOn[backend]; ok {
+			score++
+		}
+	}
+	return score
+}
+
+// dependedOn reports whether any service depends on name
+func (c *converter) dependedOn(name string) bool {
+	for _, s := range c.project.Services {
+		if s == nil {
+			continue
+		}
+		if _, ok := s.DependsOn[name]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *converter) service(name string) *service {
+	if s := c.project.Services[name]; s != nil {
+		return s
+	}
+	return &service{}
+}
+
+// referencesHost reports whether a variable value names host, such as in
+// DATABASE_URL=postgres://user@db:5432/app
+func referencesHost(env environment, host string) bool {
+	pattern := regexp.MustCompile(`(^|[@/:=])` + regexp.QuoteMeta(host) + `($|[:/])`)
+	for _, value := range env {
+		if value != nil && pattern.MatchString(*value) {
+			return true
+		}
+	}
+	return false
+}
+
+// imageName strips the registry, repository path, tag and digest from an
+// image reference, leaving names like postgres or nginx
+func imageName(image string) string {
+	image, _, _ = strings.Cut(image, "@")
+	if slash := strings.LastIndex(image, "/"); slash >= 0 {
+		image = image[slash+1:]
+	}
+	image, _, _ = strings.Cut(image, ":")
+	return strings.ToLower(image)
+}
@@ -0,0 +1,176 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrFake is a failure tests script into a Fake
+var ErrFake = errors.New("scripted failure")
+
+// Fake is an in-memory Runtime for tests. It records every operation in
+// order and tracks which containers are running. Commands and one-shot
+// containers succeed unless a hook scripts otherwise.
+type Fake struct {
+	// OnRun, when set, is called before a container starts. An error fails
+	// the start.
+	OnRun func(namespace, containerID string, spec Spec) error
+	// OnExec, when set, runs commands in place of the container and returns
+	// their exit code. Output goes to opts.Stdout.
+	OnExec func(namespace, containerID string, opts ExecOptions) (int, error)
+	// OnRunOnce, when set, runs one-shot containers and returns their exit
+	// code
+	OnRunOnce func(namespace, containerID string, spec Spec, output io.Writer) (int, error)
+
+	mu      sync.Mutex
+	calls   []string
+	running map[string]Spec
+	logs    map[string][]LogLine
+}
+
+// Calls returns the operations run so far as "run <id>", "exec <id>
+// <command>", "once <id>", "remove <id>" and "logs <id>"
+func (f *Fake) Calls() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]string(nil), f.calls...)
+}
+
+// Running returns the spec of a running container
+func (f *Fake) Running(namespace, containerID string) (Spec, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	spec, ok := f.running[namespace+"/"+containerID]
+	return spec, ok
+}
+
+// RunningIDs returns the IDs of the running containers of a namespace,
+// sorted
+func (f *Fake) RunningIDs(namespace string) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var ids []string
+	for key := range f.running {
+		if id, ok := strings.CutPrefix(key, namespace+"/"); ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Exec implements Runtime
+func (f *Fake) Exec(ctx context.Context, namespace, containerID string, opts ExecOptions) (int, error) {
+	f.record("exec " + containerID + " " + strings.Join(opts.Command, " "))
+	if _, ok := f.Running(namespace, containerID); !ok {
+		return 0, fmt.Errorf("container %s is not running", containerID)
+	}
+	var exitCode int
+	var err error
+	if f.OnExec != nil {
+		exitCode, err = f.OnExec(namespace, containerID, opts)
+	}
+	// Input the command leaves unread is drained, as a real runtime would
+	if opts.Stdin != nil {
+		io.Copy(io.Discard, opts.Stdin)
+	}
+	return exitCode, err
+}
+
+// Run implements Runtime
+func (f *Fake) Run(ctx context.Context, namespace, containerID string, spec Spec) error {
+	f.record("run " + containerID)
+	if f.OnRun != nil {
+		if err := f.OnRun(namespace, containerID, spec); err != nil {
+			return err
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.running == nil {
+		f.running = make(map[string]Spec)
+	}
+	f.running[namespace+"/"+containerID] = spec
+	return nil
+}
+
+// RunOnce implements Runtime
+func (f *Fake) RunOnce(ctx context.Context, namespace, containerID string, spec Spec, output io.Writer) (int, error) {
+	f.record("once " + containerID)
+	if f.OnRunOnce == nil {
+		return 0, nil
+	}
+	return f.OnRunOnce(namespace, containerID, spec, output)
+}
+
+// Remove implements Runtime
+func (f *Fake) Remove(ctx context.Context, namespace, containerID string, timeout time.Duration) error {
+	f.record("remove " + containerID)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	delete(f.running, namespace+"/"+containerID)
+	return nil
+}
+
+// WriteLog adds a line to the log of a container
+func (f *Fake) WriteLog(namespace, containerID string, line LogLine) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.logs == nil {
+		f.logs = make(map[string][]LogLine)
+	}
+	key := namespace + "/" + containerID
+	f.logs[key] = append(f.logs[key], line)
+}
+
+// Logs implements Runtime. Followed logs deliver the lines written so far
+// and then wait for ctx to be done.
+func (f *Fake) Logs(ctx context.Context, namespace, containerID string, opts LogOptions, send func(LogLine) error) error {
+	f.record("logs " + containerID)
+
+	f.mu.Lock()
+	var lines []LogLine
+	for _, line := range f.logs[namespace+"/"+containerID] {
+		if !line.Time.Before(opts.Since) {
+			lines = append(lines, line)
+		}
+	}
+	f.mu.Unlock()
+
+	if opts.Tail > 0 && len(lines) > opts.Tail {
+		lines = lines[len(lines)-opts.Tail:]
+	}
+	for _, line := range lines {
+		if err := send(line); err != nil {
+			return err
+		}
+	}
+	if opts.Follow {
+		<-ctx.Done()
+	}
+	return nil
+}
+
+func (f *Fake) record(call string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls = append(f.calls, strings.TrimSpace(call))
+}
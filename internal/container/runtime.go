@@ -0,0 +1,122 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+// ErrUnavailable is returned when no container runtime is configured
+var ErrUnavailable = errors.New("container runtime is not configured")
+
+// ExecOptions describes a command to run inside a running container
+type ExecOptions struct {
+	Command []string
+	Env     map[string]string
+	Stdin   io.Reader
+	Stdout  io.Writer
+	Stderr  io.Writer
+}
+
+// Spec describes a container to create and start
+type Spec struct {
+	Image   string
+	Command []string
+	Args    []string
+	Env     map[string]string
+	Mounts  []Mount
+	// Files are written to an in-memory filesystem inside the container and
+	// never touch the host disk
+	Files     []File
+	IPAddress string
+	// Nameserver is the resolver containers use, empty keeps the image default
+	Nameserver  string
+	MemoryBytes int64
+	CPUCores    float64
+}
+
+// Mount binds a host directory into a container
+type Mount struct {
+	Source   string
+	Target   string
+	ReadOnly bool
+}
+
+// File is a file created inside a container when it starts
+type File struct {
+	Path string
+	Data []byte
+}
+
+// LogOptions selects the log lines of a container
+type LogOptions struct {
+	// Since skips lines written before it, the zero time keeps every line
+	Since time.Time
+	// Tail keeps only the last lines written so far, 0 keeps every line
+	Tail int
+	// Follow keeps delivering new lines until ctx is done
+	Follow bool
+}
+
+// LogLine is one line a container wrote to stdout or stderr
+type LogLine struct {
+	Time    time.Time
+	Stderr  bool
+	Message string
+}
+
+// Runtime is the interface the scheduler uses to drive containers on the
+// host. Containers are addressed by namespace and container ID.
+type Runtime interface {
+	// Exec runs a command inside a running container, waits for it to exit
+	// and returns its exit code
+	Exec(ctx context.Context, namespace, containerID string, opts ExecOptions) (int, error)
+
+	// Run creates and starts a container, replacing any existing container
+	// with the same ID
+	Run(ctx context.Context, namespace, containerID string, spec Spec) error
+
+	// RunOnce creates and starts a container, waits for it to exit, deletes
+	// it and returns its exit code. Its stdout and stderr are written to
+	// output. The container is killed when ctx is done.
+	RunOnce(ctx context.Context, namespace, containerID string, spec Spec, output io.Writer) (int, error)
+
+	// Remove stops a container, killing it once timeout has passed, and
+	// deletes it. Removing a container that does not exist is not an error.
+	Remove(ctx context.Context, namespace, containerID string, timeout time.Duration) error
+
+	// Logs passes the log lines of a container to send in the order they
+	// were written, stopping at the first error send returns. Containers that
+	// do not exist have no lines.
+	Logs(ctx context.Context, namespace, containerID string, opts LogOptions, send func(LogLine) error) error
+}
+
+// Unavailable is the Runtime used until a containerD client is configured.
+// Every operation fails with ErrUnavailable.
+type Unavailable struct{}
+
+// Exec implements Runtime
+func (Unavailable) Exec(ctx context.Context, namespace, containerID string, opts ExecOptions) (int, error) {
+	return 0, ErrUnavailable
+}
+
+// Run implements Runtime
+func (Unavailable) Run(ctx context.Context, namespace, containerID string, spec Spec) error {
+	return ErrUnavailable
+}
+
+// RunOnce implements Runtime
+func (Unavailable) RunOnce(ctx context.Context, namespace, containerID string, spec Spec, output io.Writer) (int, error) {
+	return 0, ErrUnavailable
+}
+
+// Remove implements Runtime
+func (Unavailable) Remove(ctx context.Context, namespace, containerID string, timeout time.Duration) error {
+	return ErrUnavailable
+}
+
+// Logs implements Runtime
+func (Unavailable) Logs(ctx context.Context, namespace, containerID string, opts LogOptions, send func(LogLine) error) error {
+	return ErrUnavailable
+}
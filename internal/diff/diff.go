@@ -0,0 +1,223 @@
+package diff
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+
+	"scheduler/internal/redact"
+	"scheduler/internal/secrets"
+	pb "scheduler/proto/gen"
+)
+
+// Messages returns the differences between two messages of the same type,
+// one per changed scalar field in declaration order. Lists of messages are
+// compared element by element and maps key by key. Either message may be a
+// typed nil, in which case every populated field of the other is reported.
+// Values of sensitive fields are masked unless they are secret references.
+func Messages(old, new proto.Message) []*pb.FieldChange {
+	d := &differ{}
+	d.message("", old.ProtoReflect(), new.ProtoReflect(), false)
+	return d.changes
+}
+
+type differ struct {
+	changes []*pb.FieldChange
+}
+
+func (d *differ) add(path string, hasOld, hasNew bool, oldValue, newValue string, sensitive bool) {
+	change := &pb.FieldChange{Path: path, Type: pb.ChangeType_CHANGE_TYPE_MODIFIED}
+	switch {
+	case !hasOld:
+		change.Type = pb.ChangeType_CHANGE_TYPE_ADDED
+	case !hasNew:
+		change.Type = pb.ChangeType_CHANGE_TYPE_REMOVED
+	}
+	if hasOld {
+		change.OldValue = mask(oldValue, sensitive)
+	}
+	if hasNew {
+		change.NewValue = mask(newValue, sensitive)
+	}
+	d.changes = append(d.changes, change)
+}
+
+// message compares two messages, either of which may be invalid to stand
+// for an absent message
+func (d *differ) message(path string, old, new protoreflect.Message, sensitive bool) {
+	fields := old.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		field := fields.Get(i)
+		hasOld := old.IsValid() && old.Has(field)
+		hasNew := new.IsValid() && new.Has(field)
+		if !hasOld && !hasNew {
+			continue
+		}
+		fieldPath := joinPath(path, string(field.Name()))
+		fieldSensitive := sensitive || redact.Sensitive(field)
+
+		switch {
+		case field.IsMap():
+			d.mapField(fieldPath, field, get(old, field, hasOld), get(new, field, hasNew), fieldSensitive)
+		case field.IsList() && field.Message() != nil:
+			var oldList, newList protoreflect.List
+			if hasOld {
+				oldList = old.Get(field).List()
+			}
+			if hasNew {
+				newList = new.Get(field).List()
+			}
+			for j := 0; j < max(length(oldList), length(newList)); j++ {
+				d.message(fmt.Sprintf("%s[%d]", fieldPath, j), element(oldList, j, field), element(newList, j, field), fieldSensitive)
+			}
+		case field.IsList():
+			oldValue, newValue := "", ""
+			if hasOld {
+				oldValue = renderList(field, old.Get(field).List())
+			}
+			if hasNew {
+				newValue = renderList(field, new.Get(field).List())
+			}
+			if !hasOld || !hasNew || oldValue != newValue {
+				d.add(fieldPath, hasOld, hasNew, oldValue, newValue, fieldSensitive)
+			}
+		case field.Message() != nil:
+			d.message(fieldPath, messageOf(old, field, hasOld), messageOf(new, field, hasNew), fieldSensitive)
+		default:
+			oldValue, newValue := "", ""
+			if hasOld {
+				oldValue = render(field, old.Get(field))
+			}
+			if hasNew {
+				newValue = render(field, new.Get(field))
+			}
+			if !hasOld || !hasNew || oldValue != newValue {
+				d.add(fieldPath, hasOld, hasNew, oldValue, newValue, fieldSensitive)
+			}
+		}
+	}
+}
+
+func (d *differ) mapField(path string, field protoreflect.FieldDescriptor, old, new protoreflect.Map, sensitive bool) {
+	keys := make(map[string]protoreflect.MapKey)
+	for _, entries := range []protoreflect.Map{old, new} {
+		if entries == nil {
+			continue
+		}
+		entries.Range(func(key protoreflect.MapKey, _ protoreflect.Value) bool {
+			keys[key.String()] = key
+			return true
+		})
+	}
+	names := make([]string, 0, len(keys))
+	for name := range keys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		key := keys[name]
+		hasOld := old != nil && old.Has(key)
+		hasNew := new != nil && new.Has(key)
+		entryPath := joinPath(path, name)
+		if field.MapValue().Message() != nil {
+			zero := zeroMessage(field.MapValue())
+			oldEntry, newEntry := zero, zero
+			if hasOld {
+				oldEntry = old.Get(key).Message()
+			}
+			if hasNew {
+				newEntry = new.Get(key).Message()
+			}
+			d.message(entryPath, oldEntry, newEntry, sensitive)
+			continue
+		}
+		oldValue, newValue := "", ""
+		if hasOld {
+			oldValue = render(field.MapValue(), old.Get(key))
+		}
+		if hasNew {
+			newValue = render(field.MapValue(), new.Get(key))
+		}
+		if !hasOld || !hasNew || oldValue != newValue {
+			d.add(entryPath, hasOld, hasNew, oldValue, newValue, sensitive)
+		}
+	}
+}
+
+func get(msg protoreflect.Message, field protoreflect.FieldDescriptor, has bool) protoreflect.Map {
+	if !has {
+		return nil
+	}
+	return msg.Get(field).Map()
+}
+
+func messageOf(msg protoreflect.Message, field protoreflect.FieldDescriptor, has bool) protoreflect.Message {
+	if !has {
+		return zeroMessage(field)
+	}
+	return msg.Get(field).Message()
+}
+
+func element(list protoreflect.List, i int, field protoreflect.FieldDescriptor) protoreflect.Message {
+	if i >= length(list) {
+		return zeroMessage(field)
+	}
+	return list.Get(i).Message()
+}
+
+// zeroMessage returns an invalid message of the type of field, standing for
+// a message that is not there
+func zeroMessage(field protoreflect.FieldDescriptor) protoreflect.Message {
+	mt, err := protoregistry.GlobalTypes.FindMessageByName(field.Message().FullName())
+	if err != nil {
+		panic(fmt.Sprintf("diff: message type %s is not registered", field.Message().FullName()))
+	}
+	return mt.Zero()
+}
+
+func length(list protoreflect.List) int {
+	if list == nil {
+		return 0
+	}
+	return list.Len()
+}
+
+// render formats a scalar the way manifests write it
+func render(field protoreflect.FieldDescriptor, value protoreflect.Value) string {
+	if field.Kind() == protoreflect.EnumKind {
+		enum := field.Enum()
+		zero := string(enum.Values().ByNumber(0).Name())
+		prefix := strings.TrimSuffix(zero, "UNSPECIFIED")
+		if v := enum.Values().ByNumber(value.Enum()); v != nil {
+			return strings.ToLower(strings.TrimPrefix(string(v.Name()), prefix))
+		}
+	}
+	return value.String()
+}
+
+func renderList(field protoreflect.FieldDescriptor, list protoreflect.List) string {
+	values := make([]string, list.Len())
+	for i := range values {
+		values[i] = render(field, list.Get(i))
+	}
+	return "[" + strings.Join(values, ", ") + "]"
+}
+
+func mask(value string, sensitive bool) string {
+	if _, ok := secrets.ParseReference(value); ok || !sensitive || value == "" {
+		return value
+	}
+	return redact.Mask
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
@@ -0,0 +1,132 @@
+package diff
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+
+	"scheduler/internal/redact"
+	pb "scheduler/proto/gen"
+)
+
+func testSpec() *pb.EnvironmentSpecification {
+	return &pb.EnvironmentSpecification{
+		Name:   "web",
+		Labels: map[string]string{"team": "a"},
+		ApplicationStack: &pb.ApplicationStack{
+			Backend: &pb.BackendConfig{
+				Container: &pb.ContainerConfig{
+					Image:         "backend:1",
+					Command:       []string{"serve"},
+					Ports:         []*pb.PortMapping{{ContainerPort: 8080, HostPort: 8080}},
+					RestartPolicy: pb.RestartPolicy_RESTART_POLICY_ALWAYS,
+				},
+				ApiKeys: map[string]string{"STRIPE": "sk_live"},
+			},
+			Database: &pb.DatabaseConfig{Username: "app", Password: "hunter2"},
+		},
+	}
+}
+
+func TestMessages(t *testing.T) {
+	modified, added, removed := pb.ChangeType_CHANGE_TYPE_MODIFIED, pb.ChangeType_CHANGE_TYPE_ADDED, pb.ChangeType_CHANGE_TYPE_REMOVED
+
+	tests := []struct {
+		name   string
+		change func(spec *pb.EnvironmentSpecification)
+		want   []*pb.FieldChange
+	}{
+		{name: "unchanged"},
+		{
+			name:   "scalar",
+			change: func(spec *pb.EnvironmentSpecification) { spec.ApplicationStack.Backend.Container.Image = "backend:2" },
+			want:   []*pb.FieldChange{{Path: "application_stack.backend.container.image", Type: modified, OldValue: "backend:1", NewValue: "backend:2"}},
+		},
+		{
+			name: "list element",
+			change: func(spec *pb.EnvironmentSpecification) {
+				spec.ApplicationStack.Backend.Container.Ports[0].HostPort = 9090
+			},
+			want: []*pb.FieldChange{{Path: "application_stack.backend.container.ports[0].host_port", Type: modified, OldValue: "8080", NewValue: "9090"}},
+		},
+		{
+			name: "added list element",
+			change: func(spec *pb.EnvironmentSpecification) {
+				spec.ApplicationStack.Backend.Container.Ports = append(spec.ApplicationStack.Backend.Container.Ports, &pb.PortMapping{ContainerPort: 9000})
+			},
+			want: []*pb.FieldChange{{Path: "application_stack.backend.container.ports[1].container_port", Type: added, NewValue: "9000"}},
+		},
+		{
+			name: "scalar list",
+			change: func(spec *pb.EnvironmentSpecification) {
+				spec.ApplicationStack.Backend.Container.Command = []string{"serve", "--debug"}
+			},
+			want: []*pb.FieldChange{{Path: "application_stack.backend.container.command", Type: modified, OldValue: "[serve]", NewValue: "[serve, --debug]"}},
+		},
+		{
+			name: "map keys",
+			change: func(spec *pb.EnvironmentSpecification) {
+				spec.Labels = map[string]string{"tier": "dev"}
+			},
+			want: []*pb.FieldChange{
+				{Path: "labels.team", Type: removed, OldValue: "a"},
+				{Path: "labels.tier", Type: added, NewValue: "dev"},
+			},
+		},
+		{
+			name: "enum",
+			change: func(spec *pb.EnvironmentSpecification) {
+				spec.ApplicationStack.Backend.Container.RestartPolicy = pb.RestartPolicy_RESTART_POLICY_ON_FAILURE
+			},
+			want: []*pb.FieldChange{{Path: "application_stack.backend.container.restart_policy", Type: modified, OldValue: "always", NewValue: "on_failure"}},
+		},
+		{
+			name: "sensitive",
+			change: func(spec *pb.EnvironmentSpecification) {
+				spec.ApplicationStack.Database.Password = "secret://db-password"
+				spec.ApplicationStack.Backend.ApiKeys["STRIPE"] = "sk_test"
+			},
+			want: []*pb.FieldChange{
+				{Path: "application_stack.backend.api_keys.STRIPE", Type: modified, OldValue: redact.Mask, NewValue: redact.Mask},
+				{Path: "application_stack.database.password", Type: modified, OldValue: redact.Mask, NewValue: "secret://db-password"},
+			},
+		},
+		{
+			name: "removed message",
+			change: func(spec *pb.EnvironmentSpecification) {
+				spec.ApplicationStack.Database = nil
+			},
+			want: []*pb.FieldChange{
+				{Path: "application_stack.database.username", Type: removed, OldValue: "app"},
+				{Path: "application_stack.database.password", Type: removed, OldValue: redact.Mask},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updated := testSpec()
+			if tt.change != nil {
+				tt.change(updated)
+			}
+			got := Messages(testSpec(), updated)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Messages() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if !proto.Equal(got[i], tt.want[i]) {
+					t.Errorf("change %d = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestMessagesNil(t *testing.T) {
+	var none *pb.DatabaseConfig
+	got := Messages(none, &pb.DatabaseConfig{DatabaseName: "app"})
+	want := &pb.FieldChange{Path: "database_name", Type: pb.ChangeType_CHANGE_TYPE_ADDED, NewValue: "app"}
+	if len(got) != 1 || !proto.Equal(got[0], want) {
+		t.Errorf("Messages() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,178 @@
+package dns
+
+import (
+	"net/netip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// startResolver serves a resolver on a free loopback port until the test ends
+func startResolver(t *testing.T, records map[string]netip.Addr, upstreams ...string) *Resolver {
+	t.Helper()
+	r, err := NewResolver("127.0.0.1:0", upstreams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.SetRecords(records)
+	go r.Serve()
+	t.Cleanup(func() { r.Close() })
+	return r
+}
+
+// lookup sends a single query to address and returns the reply
+func lookup(t *testing.T, address, name string, qtype dnsmessage.Type) dnsmessage.Message {
+	t.Helper()
+	query := dnsmessage.Message{
+		Header: dnsmessage.Header{ID: 42, RecursionDesired: true},
+		Questions: []dnsmessage.Question{{
+			Name:  dnsmessage.MustNewName(name),
+			Type:  qtype,
+			Class: dnsmessage.ClassINET,
+		}},
+	}
+	packed, err := query.Pack()
+	if err != nil {
+		t.Fatal(err)
+	}
+	reply, err := exchange(address, packed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var msg dnsmessage.Message
+	if err := msg.Unpack(reply); err != nil {
+		t.Fatal(err)
+	}
+	if msg.Header.ID != 42 {
+		t.Errorf("reply ID = %d, want 42", msg.Header.ID)
+	}
+	return msg
+}
+
+// answers returns the addresses in the answer section of a reply
+func answers(msg dnsmessage.Message) []netip.Addr {
+	var addrs []netip.Addr
+	for _, resource := range msg.Answers {
+		switch body := resource.Body.(type) {
+		case *dnsmessage.AResource:
+			addrs = append(addrs, netip.AddrFrom4(body.A))
+		case *dnsmessage.AAAAResource:
+			addrs = append(addrs, netip.AddrFrom16(body.AAAA))
+		}
+	}
+	return addrs
+}
+
+func TestResolver(t *testing.T) {
+	upstream := startResolver(t, map[string]netip.Addr{
+		"example.com": netip.MustParseAddr("192.0.2.10"),
+	})
+	r := startResolver(t, map[string]netip.Addr{
+		"Backend":   netip.MustParseAddr("10.10.0.2"),
+		"database.": netip.MustParseAddr("10.10.0.3"),
+		"ipv6":      netip.MustParseAddr("fd00::2"),
+	}, upstream.Addr().String())
+	address := r.Addr().String()
+
+	tests := []struct {
+		name  string
+		query string
+		qtype dnsmessage.Type
+		want  []netip.Addr
+	}{
+		{name: "local name", query: "backend.", qtype: dnsmessage.TypeA, want: []netip.Addr{netip.MustParseAddr("10.10.0.2")}},
+		{name: "case-insensitive", query: "DATABASE.", qtype: dnsmessage.TypeA, want: []netip.Addr{netip.MustParseAddr("10.10.0.3")}},
+		{name: "IPv6 name", query: "ipv6.", qtype: dnsmessage.TypeAAAA, want: []netip.Addr{netip.MustParseAddr("fd00::2")}},
+		{name: "other type of a local name", query: "backend.", qtype: dnsmessage.TypeAAAA},
+		{name: "forwarded", query: "example.com.", qtype: dnsmessage.TypeA, want: []netip.Addr{netip.MustParseAddr("192.0.2.10")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := lookup(t, address, tt.query, tt.qtype)
+			if msg.Header.RCode != dnsmessage.RCodeSuccess {
+				t.Fatalf("RCode = %v, want success", msg.Header.RCode)
+			}
+			if got := answers(msg); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("answers = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// Replacing the records drops names that are no longer listed
+	r.SetRecords(map[string]netip.Addr{"frontend": netip.MustParseAddr("10.10.0.4")})
+	if got := answers(lookup(t, address, "frontend.", dnsmessage.TypeA)); len(got) != 1 {
+		t.Errorf("answers for a new record = %v, want one address", got)
+	}
+}
+
+func TestResolverWithoutUpstreams(t *testing.T) {
+	r := startResolver(t, nil)
+	msg := lookup(t, r.Addr().String(), "example.com.", dnsmessage.TypeA)
+	if msg.Header.RCode != dnsmessage.RCodeServerFailure {
+		t.Errorf("RCode = %v, want SERVFAIL", msg.Header.RCode)
+	}
+}
+
+func TestManager(t *testing.T) {
+	loopback := netip.MustParseAddr("127.0.0.1")
+	m := NewManager(0, []string{"192.0.2.53", "192.0.2.54:5353"})
+	defer m.Close()
+
+	if want := []string{"192.0.2.53:53", "192.0.2.54:5353"}; !reflect.DeepEqual(m.upstreams, want) {
+		t.Errorf("upstreams = %v, want %v", m.upstreams, want)
+	}
+
+	records := map[string]netip.Addr{"backend": netip.MustParseAddr("10.10.0.2")}
+	if err := m.Sync("env-1", loopback, records); err != nil {
+		t.Fatal(err)
+	}
+	first := m.resolvers["env-1"].resolver
+	got := answers(lookup(t, first.Addr().String(), "backend.", dnsmessage.TypeA))
+	if !reflect.DeepEqual(got, []netip.Addr{netip.MustParseAddr("10.10.0.2")}) {
+		t.Errorf("answers = %v, want 10.10.0.2", got)
+	}
+
+	// Syncing on the same address only refreshes the records
+	records["frontend"] = netip.MustParseAddr("10.10.0.3")
+	if err := m.Sync("env-1", loopback, records); err != nil {
+		t.Fatal(err)
+	}
+	if m.resolvers["env-1"].resolver != first {
+		t.Error("syncing on the same address restarted the resolver")
+	}
+	if got := answers(lookup(t, first.Addr().String(), "frontend.", dnsmessage.TypeA)); len(got) != 1 {
+		t.Errorf("answers for the added record = %v, want one address", got)
+	}
+
+	m.Remove("env-1")
+	if _, ok := m.resolvers["env-1"]; ok {
+		t.Error("resolver is still registered after removal")
+	}
+	if _, err := first.conn.WriteTo([]byte{0}, first.Addr()); err == nil {
+		t.Error("removed resolver still accepts packets")
+	}
+}
+
+func TestUpstreamsFromResolvConf(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "resolv.conf")
+	conf := "# generated\nsearch example.com\nnameserver 192.0.2.1\nnameserver 2001:db8::1\noptions ndots:2\n"
+	if err := os.WriteFile(path, []byte(conf), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := UpstreamsFromResolvConf(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"192.0.2.1:53", "[2001:db8::1]:53"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("UpstreamsFromResolvConf() = %v, want %v", got, want)
+	}
+
+	if _, err := UpstreamsFromResolvConf(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("reading a missing resolv.conf succeeded")
+	}
+}
@@ -0,0 +1,118 @@
+package dns
+
+import (
+	"bufio"
+	"log"
+	"net"
+	"net/netip"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Manager runs one resolver per environment network, listening on the
+// network's gateway address
+type Manager struct {
+	mu        sync.Mutex
+	port      int
+	upstreams []string
+	resolvers map[string]*managedResolver
+}
+
+type managedResolver struct {
+	listen   netip.Addr
+	resolver *Resolver
+}
+
+// NewManager creates a manager whose resolvers listen on port and forward
+// unknown names to upstreams. Upstreams without a port default to port 53.
+func NewManager(port int, upstreams []string) *Manager {
+	normalized := make([]string, 0, len(upstreams))
+	for _, upstream := range upstreams {
+		if _, _, err := net.SplitHostPort(upstream); err != nil {
+			upstream = net.JoinHostPort(upstream, "53")
+		}
+		normalized = append(normalized, upstream)
+	}
+
+	return &Manager{
+		port:      port,
+		upstreams: normalized,
+		resolvers: make(map[string]*managedResolver),
+	}
+}
+
+// Sync starts the resolver for an environment network or refreshes its
+// records. A resolver whose gateway changed is restarted on the new address.
+func (m *Manager) Sync(environmentID string, listen netip.Addr, records map[string]netip.Addr) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	current, ok := m.resolvers[environmentID]
+	if ok && current.listen != listen {
+		current.resolver.Close()
+		delete(m.resolvers, environmentID)
+		ok = false
+	}
+
+	if !ok {
+		address := net.JoinHostPort(listen.String(), strconv.Itoa(m.port))
+		resolver, err := NewResolver(address, m.upstreams)
+		if err != nil {
+			return err
+		}
+		go func() {
+			if err := resolver.Serve(); err != nil {
+				log.Printf("dns: resolver for environment %s stopped: %v", environmentID, err)
+			}
+		}()
+		current = &managedResolver{listen: listen, resolver: resolver}
+		m.resolvers[environmentID] = current
+	}
+
+	current.resolver.SetRecords(records)
+	return nil
+}
+
+// Remove stops the resolver of an environment network
+func (m *Manager) Remove(environmentID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if current, ok := m.resolvers[environmentID]; ok {
+		current.resolver.Close()
+		delete(m.resolvers, environmentID)
+	}
+}
+
+// Close stops every resolver
+func (m *Manager) Close() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for environmentID, current := range m.resolvers {
+		current.resolver.Close()
+		delete(m.resolvers, environmentID)
+	}
+}
+
+// UpstreamsFromResolvConf returns the nameservers listed in a resolv.conf
+// file as host:port addresses
+func UpstreamsFromResolvConf(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var upstreams []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == "nameserver" {
+			upstreams = append(upstreams, net.JoinHostPort(fields[1], "53"))
+		}
+	}
+	return upstreams, scanner.Err()
+}
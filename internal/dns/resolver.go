@@ -0,0 +1,213 @@
+package dns
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/netip"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+const (
+	// recordTTL is kept short so clients pick up address changes quickly
+	recordTTL       = 10
+	maxMessageSize  = 4096
+	upstreamTimeout = 5 * time.Second
+)
+
+// Resolver answers DNS queries for the containers of one environment network
+// and forwards everything else to the upstream servers
+type Resolver struct {
+	mu        sync.RWMutex
+	records   map[string]netip.Addr
+	upstreams []string
+	conn      net.PacketConn
+}
+
+// NewResolver creates a resolver listening for UDP queries on address
+func NewResolver(address string, upstreams []string) (*Resolver, error) {
+	conn, err := net.ListenPacket("udp", address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", address, err)
+	}
+	return &Resolver{
+		records:   make(map[string]netip.Addr),
+		upstreams: upstreams,
+		conn:      conn,
+	}, nil
+}
+
+// Addr returns the address the resolver listens on
+func (r *Resolver) Addr() net.Addr {
+	return r.conn.LocalAddr()
+}
+
+// SetRecords replaces the names the resolver answers for. Names are matched
+// case-insensitively and without a trailing dot.
+func (r *Resolver) SetRecords(records map[string]netip.Addr) {
+	normalized := make(map[string]netip.Addr, len(records))
+	for name, addr := range records {
+		normalized[normalizeName(name)] = addr
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.records = normalized
+}
+
+// Serve answers queries until the resolver is closed
+func (r *Resolver) Serve() error {
+	buf := make([]byte, maxMessageSize)
+	for {
+		n, peer, err := r.conn.ReadFrom(buf)
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+
+		query := make([]byte, n)
+		copy(query, buf[:n])
+		go func() {
+			reply, err := r.handle(query)
+			if err != nil {
+				log.Printf("dns: failed to answer query from %s: %v", peer, err)
+				return
+			}
+			if _, err := r.conn.WriteTo(reply, peer); err != nil {
+				log.Printf("dns: failed to reply to %s: %v", peer, err)
+			}
+		}()
+	}
+}
+
+// Close stops the resolver
+func (r *Resolver) Close() error {
+	return r.conn.Close()
+}
+
+// handle builds the reply to a single query
+func (r *Resolver) handle(query []byte) ([]byte, error) {
+	var parser dnsmessage.Parser
+	header, err := parser.Start(query)
+	if err != nil {
+		return nil, fmt.Errorf("malformed query: %w", err)
+	}
+	question, err := parser.Question()
+	if err != nil {
+		return nil, fmt.Errorf("malformed question: %w", err)
+	}
+
+	r.mu.RLock()
+	addr, ok := r.records[normalizeName(question.Name.String())]
+	r.mu.RUnlock()
+
+	if !ok || header.OpCode != 0 || question.Class != dnsmessage.ClassINET {
+		return r.forward(header, question, query)
+	}
+	return answer(header, question, addr)
+}
+
+// answer replies authoritatively for a local name. Queries for other record
+// types get an empty answer rather than being leaked upstream.
+func answer(header dnsmessage.Header, question dnsmessage.Question, addr netip.Addr) ([]byte, error) {
+	builder := dnsmessage.NewBuilder(nil, dnsmessage.Header{
+		ID:                 header.ID,
+		Response:           true,
+		Authoritative:      true,
+		RecursionDesired:   header.RecursionDesired,
+		RecursionAvailable: true,
+		RCode:              dnsmessage.RCodeSuccess,
+	})
+	builder.EnableCompression()
+	if err := builder.StartQuestions(); err != nil {
+		return nil, err
+	}
+	if err := builder.Question(question); err != nil {
+		return nil, err
+	}
+	if err := builder.StartAnswers(); err != nil {
+		return nil, err
+	}
+
+	resource := dnsmessage.ResourceHeader{
+		Name:  question.Name,
+		Class: dnsmessage.ClassINET,
+		TTL:   recordTTL,
+	}
+	switch {
+	case question.Type == dnsmessage.TypeA && addr.Is4():
+		if err := builder.AResource(resource, dnsmessage.AResource{A: addr.As4()}); err != nil {
+			return nil, err
+		}
+	case question.Type == dnsmessage.TypeAAAA && addr.Is6():
+		if err := builder.AAAAResource(resource, dnsmessage.AAAAResource{AAAA: addr.As16()}); err != nil {
+			return nil, err
+		}
+	}
+
+	return builder.Finish()
+}
+
+// forward relays the query to the first upstream server that answers, or
+// replies with SERVFAIL when none does
+func (r *Resolver) forward(header dnsmessage.Header, question dnsmessage.Question, query []byte) ([]byte, error) {
+	var lastErr error
+	for _, upstream := range r.upstreams {
+		reply, err := exchange(upstream, query)
+		if err == nil {
+			return reply, nil
+		}
+		lastErr = err
+	}
+	if lastErr != nil {
+		log.Printf("dns: upstream lookup of %s failed: %v", question.Name, lastErr)
+	}
+
+	builder := dnsmessage.NewBuilder(nil, dnsmessage.Header{
+		ID:               header.ID,
+		Response:         true,
+		RecursionDesired: header.RecursionDesired,
+		RCode:            dnsmessage.RCodeServerFailure,
+	})
+	if err := builder.StartQuestions(); err != nil {
+		return nil, err
+	}
+	if err := builder.Question(question); err != nil {
+		return nil, err
+	}
+	return builder.Finish()
+}
+
+// exchange sends a raw query to an upstream server over UDP
+func exchange(upstream string, query []byte) ([]byte, error) {
+	conn, err := net.DialTimeout("udp", upstream, upstreamTimeout)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if err := conn.SetDeadline(time.Now().Add(upstreamTimeout)); err != nil {
+		return nil, err
+	}
+	if _, err := conn.Write(query); err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, maxMessageSize)
+	n, err := conn.Read(buf)
+	if err != nil {
+		return nil, err
+	}
+	return buf[:n], nil
+}
+
+func normalizeName(name string) string {
+	return strings.TrimSuffix(strings.ToLower(name), ".")
+}
@@ -0,0 +1,149 @@
+package manifest
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"gopkg.in/yaml.v3"
+
+	pb "scheduler/proto/gen"
+)
+
+// Marshal renders manifests as a stream of YAML documents that Parse reads
+// back. Fields use their proto names and enum values their short names.
+func Marshal(manifests ...*Manifest) ([]byte, error) {
+	var out bytes.Buffer
+	encoder := yaml.NewEncoder(&out)
+	encoder.SetIndent(2)
+	for _, m := range manifests {
+		if err := encoder.Encode(documentNode(m)); err != nil {
+			return nil, fmt.Errorf("failed to encode manifest %s: %w", m.Spec.GetName(), err)
+		}
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode manifests: %w", err)
+	}
+	return out.Bytes(), nil
+}
+
+func documentNode(m *Manifest) *yaml.Node {
+	version, kind := m.APIVersion, m.Kind
+	if version == "" {
+		version = APIVersion
+	}
+	if kind == "" {
+		kind = KindEnvironment
+	}
+
+	metadata := mappingNode()
+	appendPair(metadata, "name", stringNode(m.Spec.GetName()))
+	if m.Tenant != "" {
+		appendPair(metadata, "tenant", stringNode(m.Tenant))
+	}
+	if len(m.Spec.GetLabels()) > 0 {
+		labels := mappingNode()
+		for _, key := range sortedKeys(m.Spec.GetLabels()) {
+			appendPair(labels, key, stringNode(m.Spec.GetLabels()[key]))
+		}
+		appendPair(metadata, "labels", labels)
+	}
+
+	// Name and labels are written in the metadata only
+	spec := proto.Clone(m.Spec).(*pb.EnvironmentSpecification)
+	spec.Name, spec.Labels = "", nil
+
+	root := mappingNode()
+	appendPair(root, "apiVersion", stringNode(version))
+	appendPair(root, "kind", stringNode(kind))
+	appendPair(root, "metadata", metadata)
+	appendPair(root, "spec", messageNode(spec.ProtoReflect()))
+	return root
+}
+
+// messageNode renders the populated fields of msg in declaration order
+func messageNode(msg protoreflect.Message) *yaml.Node {
+	node := mappingNode()
+	fields := msg.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		field := fields.Get(i)
+		if !msg.Has(field) {
+			continue
+		}
+		appendPair(node, string(field.Name()), valueNode(field, msg.Get(field)))
+	}
+	return node
+}
+
+func valueNode(field protoreflect.FieldDescriptor, value protoreflect.Value) *yaml.Node {
+	switch {
+	case field.IsMap():
+		entries := make(map[string]protoreflect.Value)
+		value.Map().Range(func(key protoreflect.MapKey, entry protoreflect.Value) bool {
+			entries[key.String()] = entry
+			return true
+		})
+		node := mappingNode()
+		for _, key := range sortedKeys(entries) {
+			appendPair(node, key, singularNode(field.MapValue(), entries[key]))
+		}
+		return node
+	case field.IsList():
+		node := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		// Lists of scalars such as commands read best on one line
+		if field.Message() == nil {
+			node.Style = yaml.FlowStyle
+		}
+		list := value.List()
+		for i := 0; i < list.Len(); i++ {
+			node.Content = append(node.Content, singularNode(field, list.Get(i)))
+		}
+		return node
+	default:
+		return singularNode(field, value)
+	}
+}
+
+func singularNode(field protoreflect.FieldDescriptor, value protoreflect.Value) *yaml.Node {
+	switch field.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return messageNode(value.Message())
+	case protoreflect.EnumKind:
+		name := string(field.Enum().Values().ByNumber(value.Enum()).Name())
+		return stringNode(strings.ToLower(strings.TrimPrefix(name, enumPrefix(field.Enum()))))
+	case protoreflect.BoolKind:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: strconv.FormatBool(value.Bool())}
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!float", Value: strconv.FormatFloat(value.Float(), 'g', -1, 64)}
+	case protoreflect.StringKind:
+		return stringNode(value.String())
+	default:
+		// Every remaining kind is an integer
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: value.String()}
+	}
+}
+
+func mappingNode() *yaml.Node {
+	return &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+}
+
+func stringNode(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
+
+func appendPair(mapping *yaml.Node, key string, value *yaml.Node) {
+	mapping.Content = append(mapping.Content, stringNode(key), value)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
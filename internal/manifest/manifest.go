@@ -0,0 +1,559 @@
+package manifest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"gopkg.in/yaml.v3"
+
+	pb "scheduler/proto/gen"
+)
+
+// Version and kind of the manifests this package reads
+const (
+	APIVersion      = "scheduler/v1"
+	KindEnvironment = "Environment"
+)
+
+// Manifest is an environment declared in a manifest document:
+//
+//	apiVersion: scheduler/v1
+//	kind: Environment
+//	metadata:
+//	  name: webapp
+//	  tenant: team-a
+//	  labels:
+//	    team: backend
+//	spec:
+//	  application_stack: ...
+//
+// The spec holds the fields of EnvironmentSpecification under their proto or
+// JSON names, except name and labels which come from the metadata.
+type Manifest struct {
+	APIVersion string
+	Kind       string
+	// Tenant is empty when the manifest leaves the choice to the server
+	Tenant string
+	Spec   *pb.EnvironmentSpecification
+	// Line is where the document starts in the parsed input
+	Line int
+}
+
+// Error is a problem at a position of a manifest
+type Error struct {
+	Line    int
+	Column  int
+	Path    string
+	Message string
+}
+
+func (e *Error) Error() string {
+	if e.Path == "" {
+		return fmt.Sprintf("line %d, column %d: %s", e.Line, e.Column, e.Message)
+	}
+	return fmt.Sprintf("line %d, column %d: %s: %s", e.Line, e.Column, e.Path, e.Message)
+}
+
+// Errors are all the problems found in a manifest, in the order they appear
+type Errors []*Error
+
+func (e Errors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
+// Parse reads every manifest of a YAML or JSON stream. Documents are
+// separated by "---" lines and empty documents are skipped.
+func Parse(data []byte) ([]*Manifest, error) {
+	var manifests []*Manifest
+	d := &decoder{}
+	stream := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		var document yaml.Node
+		err := stream.Decode(&document)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid YAML: %w", err)
+		}
+		if len(document.Content) == 0 || isNull(document.Content[0]) {
+			continue
+		}
+		if m := d.manifest(document.Content[0]); m != nil {
+			manifests = append(manifests, m)
+		}
+	}
+	if err := d.err(); err != nil {
+		return nil, err
+	}
+	if len(manifests) == 0 {
+		return nil, errors.New("no manifest found")
+	}
+	return manifests, nil
+}
+
+// ParseSpec reads a bare EnvironmentSpecification from a single YAML or JSON
+// document, with the same checks as the spec of a manifest
+func ParseSpec(data []byte) (*pb.EnvironmentSpecification, error) {
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("invalid YAML: %w", err)
+	}
+	if len(document.Content) == 0 {
+		return nil, errors.New("no specification found")
+	}
+	d := &decoder{}
+	spec := &pb.EnvironmentSpecification{}
+	d.decode(document.Content[0], "", spec)
+	if err := d.err(); err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+// IsManifest reports whether data is a manifest rather than a bare
+// specification, judged by a top-level apiVersion field
+func IsManifest(data []byte) bool {
+	var header struct {
+		APIVersion string `yaml:"apiVersion"`
+	}
+	// Only the first document is looked at, later ones are handled by Parse
+	err := yaml.NewDecoder(bytes.NewReader(data)).Decode(&header)
+	return err == nil && header.APIVersion != ""
+}
+
+type decoder struct {
+	errs Errors
+}
+
+func (d *decoder) fail(node *yaml.Node, path, format string, args ...any) {
+	d.errs = append(d.errs, &Error{Line: node.Line, Column: node.Column, Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+func (d *decoder) err() error {
+	if len(d.errs) == 0 {
+		return nil
+	}
+	sort.SliceStable(d.errs, func(i, j int) bool {
+		if d.errs[i].Line != d.errs[j].Line {
+			return d.errs[i].Line < d.errs[j].Line
+		}
+		return d.errs[i].Column < d.errs[j].Column
+	})
+	return d.errs
+}
+
+// manifest decodes one document, recording its problems and returning nil
+// when it is unusable
+func (d *decoder) manifest(root *yaml.Node) *Manifest {
+	root = resolve(root)
+	if root.Kind != yaml.MappingNode {
+		d.fail(root, "", "expected a manifest mapping with apiVersion, kind, metadata and spec")
+		return nil
+	}
+	m := &Manifest{Spec: &pb.EnvironmentSpecification{}, Line: root.Line}
+	// Problems with apiVersion and kind point at their value when present
+	versionNode, kindNode := root, root
+	var metadata, spec *yaml.Node
+	forEachPair(root, func(key, value *yaml.Node) {
+		switch key.Value {
+		case "apiVersion":
+			m.APIVersion, versionNode = d.string(value, "apiVersion"), value
+		case "kind":
+			m.Kind, kindNode = d.string(value, "kind"), value
+		case "metadata":
+			metadata = value
+		case "spec":
+			spec = value
+		default:
+			d.fail(key, key.Value, "unknown field, a manifest has apiVersion, kind, metadata and spec")
+		}
+	})
+
+	switch m.APIVersion {
+	case APIVersion:
+	case "":
+		d.fail(root, "apiVersion", "is required, use %s", APIVersion)
+	default:
+		d.fail(versionNode, "apiVersion", "unsupported version %q, use %s", m.APIVersion, APIVersion)
+	}
+	switch m.Kind {
+	case KindEnvironment:
+	case "":
+		d.fail(root, "kind", "is required, use %s", KindEnvironment)
+	default:
+		d.fail(kindNode, "kind", "unsupported kind %q, use %s", m.Kind, KindEnvironment)
+	}
+
+	if metadata == nil {
+		d.fail(root, "metadata", "is required")
+	} else {
+		d.metadata(metadata, m)
+	}
+	if spec == nil || isNull(spec) {
+		d.fail(root, "spec", "is required")
+	} else {
+		// Name and labels live in the metadata so that there is only one
+		// place to set them
+		if resolve(spec).Kind == yaml.MappingNode {
+			forEachPair(resolve(spec), func(key, _ *yaml.Node) {
+				if key.Value == "name" || key.Value == "labels" {
+					d.fail(key, "spec."+key.Value, "set metadata.%s instead", key.Value)
+				}
+			})
+		}
+		name, labels := m.Spec.Name, m.Spec.Labels
+		d.decode(spec, "spec", m.Spec)
+		m.Spec.Name, m.Spec.Labels = name, labels
+	}
+	return m
+}
+
+func (d *decoder) metadata(node *yaml.Node, m *Manifest) {
+	node = resolve(node)
+	if node.Kind != yaml.MappingNode {
+		d.fail(node, "metadata", "expected a mapping")
+		return
+	}
+	forEachPair(node, func(key, value *yaml.Node) {
+		path := "metadata." + key.Value
+		switch key.Value {
+		case "name":
+			m.Spec.Name = d.string(value, path)
+		case "tenant":
+			m.Tenant = d.string(value, path)
+		case "labels":
+			labels := &pb.EnvironmentSpecification{}
+			d.decode(pair(key, value), "metadata", labels)
+			m.Spec.Labels = labels.Labels
+		default:
+			d.fail(key, path, "unknown field, metadata has name, tenant and labels")
+		}
+	})
+	if m.Spec.Name == "" {
+		d.fail(node, "metadata.name", "is required")
+	}
+}
+
+// string decodes a scalar that must be a string
+func (d *decoder) string(node *yaml.Node, path string) string {
+	node = resolve(node)
+	if node.Kind != yaml.ScalarNode {
+		d.fail(node, path, "expected a string")
+		return ""
+	}
+	return node.Value
+}
+
+// errorPosition matches the position and message of a protojson error
+var errorPosition = regexp.MustCompile(`\(line (\d+):\d+\): (.*)$`)
+
+// decode reads node into msg with the protobuf JSON mapping, so that fields
+// go by their proto or JSON names and unknown fields are rejected. The node
+// is rendered as JSON one token per line, which leads the line of a
+// protojson error back to the node behind it. protojson stops at the first
+// problem, so the offending node is left out and the rest decoded again
+// until every problem is found. path is where node sits in the document.
+func (d *decoder) decode(node *yaml.Node, path string, msg proto.Message) {
+	skip := make(map[*yaml.Node]bool)
+	for {
+		document := &jsonDocument{skip: skip}
+		document.message(node, path, msg.ProtoReflect().Descriptor())
+		err := protojson.Unmarshal(document.buf.Bytes(), msg)
+		if err == nil {
+			return
+		}
+		token, message := document.locate(err)
+		if token == nil || skip[token.node] {
+			d.fail(resolve(node), path, "%s", message)
+			return
+		}
+		d.fail(token.node, token.path, "%s", token.explain(message))
+		skip[token.node] = true
+	}
+}
+
+// jsonDocument is a YAML node rendered as JSON, one token per line
+type jsonDocument struct {
+	buf    bytes.Buffer
+	tokens []jsonToken
+	// skip holds the nodes left out, with the pair they are the key or
+	// value of
+	skip map[*yaml.Node]bool
+	// comma is set when the next token follows another value
+	comma bool
+}
+
+// jsonToken is the node behind a line of a jsonDocument
+type jsonToken struct {
+	node *yaml.Node
+	path string
+	// fields are the fields of the message a key belongs to, and field the
+	// one a value is decoded into, when known
+	fields protoreflect.FieldDescriptors
+	field  protoreflect.FieldDescriptor
+}
+
+// locate returns the token a protojson error points at, nil when it points
+// nowhere, and the message of the error without its position
+func (j *jsonDocument) locate(err error) (*jsonToken, string) {
+	match := errorPosition.FindStringSubmatch(err.Error())
+	if match == nil {
+		return nil, err.Error()
+	}
+	line, _ := strconv.Atoi(match[1])
+	if line < 1 || line > len(j.tokens) {
+		return nil, match[2]
+	}
+	return &j.tokens[line-1], match[2]
+}
+
+// explain adds what the message or field of the token accepts to a
+// protojson error message
+func (t *jsonToken) explain(message string) string {
+	switch {
+	case t.fields != nil && strings.HasPrefix(message, "unknown field"):
+		return message + suggestion(t.fields, t.node.Value)
+	case t.field != nil && t.field.Enum() != nil:
+		return message + ", use one of " + strings.Join(enumNames(t.field.Enum()), ", ")
+	}
+	return message
+}
+
+func (j *jsonDocument) write(text string, token jsonToken) {
+	if j.comma {
+		j.buf.WriteByte(',')
+		j.comma = false
+	}
+	j.buf.WriteString(text)
+	j.buf.WriteByte('\n')
+	j.tokens = append(j.tokens, token)
+}
+
+// object writes a mapping as a JSON object, each key followed by the value
+// visit writes
+func (j *jsonDocument) object(node *yaml.Node, path string, fields protoreflect.FieldDescriptors, visit func(key, value *yaml.Node, path string)) {
+	j.write("{", jsonToken{node: node, path: path})
+	first := true
+	forEachPair(node, func(key, value *yaml.Node) {
+		if j.skip[key] || j.skip[resolve(value)] {
+			return
+		}
+		j.comma = !first
+		first = false
+		keyPath := joinPath(path, key.Value)
+		j.write(quote(key.Value)+":", jsonToken{node: key, path: keyPath, fields: fields})
+		visit(key, value, keyPath)
+	})
+	j.write("}", jsonToken{node: node, path: path})
+}
+
+// array writes a sequence as a JSON array, each item written by visit
+func (j *jsonDocument) array(node *yaml.Node, path string, visit func(item *yaml.Node, path string)) {
+	j.write("[", jsonToken{node: node, path: path})
+	first := true
+	for i, item := range node.Content {
+		if j.skip[resolve(item)] {
+			continue
+		}
+		j.comma = !first
+		first = false
+		visit(item, fmt.Sprintf("%s[%d]", path, i))
+	}
+	j.write("]", jsonToken{node: node, path: path})
+}
+
+// message writes node as a message of type md, looking fields up by their
+// proto and then their JSON name
+func (j *jsonDocument) message(node *yaml.Node, path string, md protoreflect.MessageDescriptor) {
+	node = resolve(node)
+	if node.Kind != yaml.MappingNode {
+		j.value(node, path, nil)
+		return
+	}
+	fields := md.Fields()
+	j.object(node, path, fields, func(key, value *yaml.Node, path string) {
+		field := fields.ByName(protoreflect.Name(key.Value))
+		if field == nil {
+			field = fields.ByJSONName(key.Value)
+		}
+		j.field(value, path, field)
+	})
+}
+
+// field writes node as the value of field, or as it is when the field is
+// unknown and protojson rejects it anyway
+func (j *jsonDocument) field(node *yaml.Node, path string, field protoreflect.FieldDescriptor) {
+	node = resolve(node)
+	switch {
+	case field == nil:
+		j.value(node, path, nil)
+	case field.IsMap() && node.Kind == yaml.MappingNode:
+		j.object(node, path, nil, func(_, value *yaml.Node, path string) {
+			j.singular(value, path, field.MapValue())
+		})
+	case field.IsList() && node.Kind == yaml.SequenceNode:
+		j.array(node, path, func(item *yaml.Node, path string) {
+			j.singular(item, path, field)
+		})
+	case field.IsMap() || field.IsList():
+		j.value(node, path, field)
+	default:
+		j.singular(node, path, field)
+	}
+}
+
+// singular writes node as a single value of field. Enum values may be given
+// by their short name, such as on_failure or on-failure for
+// RESTART_POLICY_ON_FAILURE, which is written as the full name.
+func (j *jsonDocument) singular(node *yaml.Node, path string, field protoreflect.FieldDescriptor) {
+	node = resolve(node)
+	if field.Message() != nil {
+		j.message(node, path, field.Message())
+		return
+	}
+	if field.Enum() != nil && node.Kind == yaml.ScalarNode && node.ShortTag() == "!!str" {
+		if name, ok := enumName(field.Enum(), node.Value); ok {
+			j.write(quote(name), jsonToken{node: node, path: path, field: field})
+			return
+		}
+	}
+	j.value(node, path, field)
+}
+
+// value writes node as the JSON of its YAML types
+func (j *jsonDocument) value(node *yaml.Node, path string, field protoreflect.FieldDescriptor) {
+	node = resolve(node)
+	switch node.Kind {
+	case yaml.MappingNode:
+		j.object(node, path, nil, func(_, value *yaml.Node, path string) {
+			j.value(value, path, nil)
+		})
+	case yaml.SequenceNode:
+		j.array(node, path, func(item *yaml.Node, path string) {
+			j.value(item, path, nil)
+		})
+	default:
+		j.write(scalarJSON(node), jsonToken{node: node, path: path, field: field})
+	}
+}
+
+// scalarJSON renders a scalar as the JSON value of its YAML type, so that
+// 8080 is a number and "8080" a string as they would be in a JSON document.
+// Numbers keep their spelling when JSON has it, for the error messages.
+func scalarJSON(node *yaml.Node) string {
+	switch node.ShortTag() {
+	case "!!int", "!!float":
+		if json.Valid([]byte(node.Value)) {
+			return node.Value
+		}
+		fallthrough
+	case "!!null", "!!bool":
+		var value any
+		if node.Decode(&value) == nil {
+			if data, err := json.Marshal(value); err == nil {
+				return string(data)
+			}
+		}
+	}
+	return quote(node.Value)
+}
+
+func quote(text string) string {
+	data, _ := json.Marshal(text)
+	return string(data)
+}
+
+// enumName returns the full name of the enum value with a short name, such
+// as RESTART_POLICY_ON_FAILURE for on_failure or on-failure
+func enumName(enum protoreflect.EnumDescriptor, short string) (string, bool) {
+	name := enumPrefix(enum) + strings.ToUpper(strings.ReplaceAll(short, "-", "_"))
+	if short != strings.ToLower(short) || enum.Values().ByName(protoreflect.Name(name)) == nil {
+		return "", false
+	}
+	return name, true
+}
+
+// enumNames lists the short names of the values of an enum, without the
+// unspecified value
+func enumNames(enum protoreflect.EnumDescriptor) []string {
+	prefix := enumPrefix(enum)
+	values := enum.Values()
+	var names []string
+	for i := 0; i < values.Len(); i++ {
+		if values.Get(i).Number() == 0 {
+			continue
+		}
+		names = append(names, strings.ToLower(strings.TrimPrefix(string(values.Get(i).Name()), prefix)))
+	}
+	return names
+}
+
+// enumPrefix returns the prefix shared by the values of an enum, taken from
+// its zero value such as RESTART_POLICY_UNSPECIFIED
+func enumPrefix(enum protoreflect.EnumDescriptor) string {
+	zero := string(enum.Values().ByNumber(0).Name())
+	return strings.TrimSuffix(zero, "UNSPECIFIED")
+}
+
+// suggestion names the field a misspelled key most likely meant
+func suggestion(fields protoreflect.FieldDescriptors, key string) string {
+	normalized := normalize(key)
+	for i := 0; i < fields.Len(); i++ {
+		if normalize(string(fields.Get(i).Name())) == normalized {
+			return fmt.Sprintf(", did you mean %s?", fields.Get(i).Name())
+		}
+	}
+	return ""
+}
+
+// normalize drops case and separators, so that containerPort, container-port
+// and ContainerPort compare equal to container_port
+func normalize(key string) string {
+	return strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(key))
+}
+
+// pair returns a mapping of only key and value, to decode a single field
+// with the positions of the document
+func pair(key, value *yaml.Node) *yaml.Node {
+	return &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Line: key.Line, Column: key.Column, Content: []*yaml.Node{key, value}}
+}
+
+func forEachPair(node *yaml.Node, visit func(key, value *yaml.Node)) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		visit(node.Content[i], node.Content[i+1])
+	}
+}
+
+// resolve follows YAML aliases to the node they refer to
+func resolve(node *yaml.Node) *yaml.Node {
+	for node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+	return node
+}
+
+func isNull(node *yaml.Node) bool {
+	return node.Kind == yaml.ScalarNode && node.Tag == "!!null"
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,215 @@
+package manifest
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+
+	pb "scheduler/proto/gen"
+)
+
+const webapp = `apiVersion: scheduler/v1
+kind: Environment
+metadata:
+  name: webapp
+  tenant: team-a
+  labels:
+    team: backend
+spec:
+  priority: 5
+  applicationStack:
+    database:
+      container: &postgres
+        image: postgres:16
+      database_name: app
+    backend:
+      container:
+        image: backend:1
+        ports:
+          - container_port: 8080
+            host_port: 8080
+        restart_policy: on-failure
+---
+---
+apiVersion: scheduler/v1
+kind: Environment
+metadata:
+  name: worker
+spec:
+  application_stack:
+    additional_services:
+      queue:
+        image: rabbitmq:3
+`
+
+func TestParse(t *testing.T) {
+	manifests, err := Parse([]byte(webapp))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(manifests) != 2 {
+		t.Fatalf("Parse() returned %d manifests, want 2, the empty document skipped", len(manifests))
+	}
+
+	want := &pb.EnvironmentSpecification{
+		Name:     "webapp",
+		Labels:   map[string]string{"team": "backend"},
+		Priority: 5,
+		ApplicationStack: &pb.ApplicationStack{
+			Database: &pb.DatabaseConfig{
+				Container:    &pb.ContainerConfig{Image: "postgres:16"},
+				DatabaseName: "app",
+			},
+			Backend: &pb.BackendConfig{Container: &pb.ContainerConfig{
+				Image:         "backend:1",
+				Ports:         []*pb.PortMapping{{ContainerPort: 8080, HostPort: 8080}},
+				RestartPolicy: pb.RestartPolicy_RESTART_POLICY_ON_FAILURE,
+			}},
+		},
+	}
+	if !proto.Equal(manifests[0].Spec, want) {
+		t.Errorf("spec = %v, want %v", manifests[0].Spec, want)
+	}
+	if manifests[0].Tenant != "team-a" || manifests[0].Line != 1 {
+		t.Errorf("manifest = %+v, want tenant team-a at line 1", manifests[0])
+	}
+	if manifests[1].Spec.GetApplicationStack().GetAdditionalServices()["queue"].GetImage() != "rabbitmq:3" {
+		t.Errorf("second spec = %v, want the queue service", manifests[1].Spec)
+	}
+
+	// Marshal writes what Parse reads back
+	data, err := Marshal(manifests...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse() of marshaled manifests error = %v\n%s", err, data)
+	}
+	for i := range manifests {
+		if !proto.Equal(again[i].Spec, manifests[i].Spec) || again[i].Tenant != manifests[i].Tenant {
+			t.Errorf("manifest %d after a round trip = %v, want %v", i, again[i].Spec, manifests[i].Spec)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		// want holds the expected errors, each a line and a message fragment
+		want []string
+	}{
+		{
+			name:     "missing header",
+			manifest: "metadata:\n  name: web\nspec: {}\n",
+			want:     []string{"line 1, column 1: apiVersion: is required", "line 1, column 1: kind: is required"},
+		},
+		{
+			name:     "unsupported kind",
+			manifest: "apiVersion: scheduler/v1\nkind: Volume\nmetadata:\n  name: web\nspec: {}\n",
+			want:     []string{`line 2, column 7: kind: unsupported kind "Volume"`},
+		},
+		{
+			name: "misspelled field",
+			manifest: "apiVersion: scheduler/v1\nkind: Environment\nmetadata:\n  name: web\nspec:\n" +
+				"  application_stack:\n    backend:\n      container:\n        Image: x\n",
+			want: []string{`line 9, column 9: spec.application_stack.backend.container.Image: unknown field "Image", did you mean image?`},
+		},
+		{
+			name: "wrong types",
+			manifest: "apiVersion: scheduler/v1\nkind: Environment\nmetadata:\n  name: web\nspec:\n" +
+				"  priority: high\n  application_stack:\n    backend:\n      container:\n        restart_policy: sometimes\n",
+			want: []string{
+				`line 6, column 13: spec.priority: invalid value for int32 field priority: "high"`,
+				`line 10, column 25: spec.application_stack.backend.container.restart_policy: invalid value for enum field restartPolicy: "sometimes", use one of no, always, on_failure, unless_stopped`,
+			},
+		},
+		{
+			// Strings are not coerced from the other YAML types
+			name: "number for a string",
+			manifest: "apiVersion: scheduler/v1\nkind: Environment\nmetadata:\n  name: web\nspec:\n" +
+				"  application_stack:\n    backend:\n      container:\n        image: 1.0\n        environment_variables:\n          PORT: 8080\n",
+			want: []string{
+				`line 9, column 16: spec.application_stack.backend.container.image: invalid value for string field image: 1.0`,
+				`line 11, column 17: spec.application_stack.backend.container.environment_variables.PORT: invalid value for string field value: 8080`,
+			},
+		},
+		{
+			name: "enum short name case",
+			manifest: "apiVersion: scheduler/v1\nkind: Environment\nmetadata:\n  name: web\nspec:\n" +
+				"  application_stack:\n    backend:\n      container:\n        restart_policy: On-Failure\n",
+			want: []string{`line 9, column 25: spec.application_stack.backend.container.restart_policy: invalid value for enum field restartPolicy: "On-Failure"`},
+		},
+		{
+			name:     "name in the spec",
+			manifest: "apiVersion: scheduler/v1\nkind: Environment\nmetadata:\n  name: web\nspec:\n  name: other\n",
+			want:     []string{"line 6, column 3: spec.name: set metadata.name instead"},
+		},
+		{
+			name:     "field name case",
+			manifest: "apiVersion: scheduler/v1\nkind: Environment\nmetadata:\n  name: web\nspec:\n  priority: 1\n  Priority: 2\n",
+			want:     []string{`line 7, column 3: spec.Priority: unknown field "Priority", did you mean priority?`},
+		},
+		{
+			name:     "proto and JSON name",
+			manifest: "apiVersion: scheduler/v1\nkind: Environment\nmetadata:\n  name: web\nspec:\n  application_stack: {}\n  applicationStack: {}\n",
+			want:     []string{`line 7, column 3: spec.applicationStack: duplicate field "applicationStack"`},
+		},
+		{
+			name:     "no name",
+			manifest: "apiVersion: scheduler/v1\nkind: Environment\nmetadata: {}\nspec: {}\n",
+			want:     []string{"metadata.name: is required"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.manifest))
+			var errs Errors
+			if !errors.As(err, &errs) {
+				t.Fatalf("Parse() error = %v, want manifest errors", err)
+			}
+			if len(errs) != len(tt.want) {
+				t.Fatalf("Parse() error = %v, want %d errors", err, len(tt.want))
+			}
+			for i, want := range tt.want {
+				if !strings.Contains(errs[i].Error(), want) {
+					t.Errorf("error %d = %q, want it to contain %q", i, errs[i].Error(), want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseSpec(t *testing.T) {
+	spec, err := ParseSpec([]byte(`{"name": "web", "labels": {"team": "a"}, "priority": 2}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &pb.EnvironmentSpecification{Name: "web", Labels: map[string]string{"team": "a"}, Priority: 2}
+	if !proto.Equal(spec, want) {
+		t.Errorf("ParseSpec() = %v, want %v", spec, want)
+	}
+	spec, err = ParseSpec([]byte("application_stack:\n  backend:\n    container:\n      image: \"1.0\"\n      restart_policy: RESTART_POLICY_ALWAYS\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if container := spec.GetApplicationStack().GetBackend().GetContainer(); container.GetImage() != "1.0" || container.GetRestartPolicy() != pb.RestartPolicy_RESTART_POLICY_ALWAYS {
+		t.Errorf("ParseSpec() = %v, want the quoted string and the full enum name", spec)
+	}
+	if _, err := ParseSpec([]byte("nmae: web\n")); err == nil {
+		t.Error("ParseSpec() of an unknown field succeeded")
+	}
+}
+
+func TestIsManifest(t *testing.T) {
+	if !IsManifest([]byte(webapp)) {
+		t.Error("IsManifest() of a manifest = false")
+	}
+	if IsManifest([]byte("name: web\n")) {
+		t.Error("IsManifest() of a bare specification = true")
+	}
+}
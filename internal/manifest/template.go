@@ -0,0 +1,192 @@
+package manifest
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v3"
+
+	pb "scheduler/proto/gen"
+)
+
+// KindTemplate is the kind of template manifests
+const KindTemplate = "Template"
+
+// TemplateManifest is a stack template declared in a manifest document:
+//
+//	apiVersion: scheduler/v1
+//	kind: Template
+//	metadata:
+//	  name: cache
+//	  description: Redis with an application in front
+//	  labels:
+//	    team: ${team}
+//	parameters:
+//	  - name: http_port
+//	    type: integer
+//	    default_value: "8080"
+//	spec:
+//	  application_stack: ...
+//
+// Parameters hold the fields of TemplateParameter. The labels and the spec
+// may refer to parameters and are only decoded once the placeholders are
+// filled in, with DecodeTemplateSpec.
+type TemplateManifest struct {
+	// Template holds the name, description and parameters
+	Template *pb.Template
+	// Labels and Spec are the nodes of metadata.labels and spec, Labels is
+	// nil when the template sets no labels
+	Labels *yaml.Node
+	Spec   *yaml.Node
+}
+
+// ParseTemplate reads a single template manifest from YAML or JSON
+func ParseTemplate(data []byte) (*TemplateManifest, error) {
+	stream := yaml.NewDecoder(bytes.NewReader(data))
+	var document yaml.Node
+	if err := stream.Decode(&document); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("no template found")
+		}
+		return nil, fmt.Errorf("invalid YAML: %w", err)
+	}
+	var extra yaml.Node
+	if err := stream.Decode(&extra); !errors.Is(err, io.EOF) {
+		return nil, errors.New("expected a single template manifest")
+	}
+	if len(document.Content) == 0 || isNull(document.Content[0]) {
+		return nil, errors.New("no template found")
+	}
+
+	d := &decoder{}
+	t := d.template(document.Content[0])
+	if err := d.err(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// DecodeTemplateSpec decodes the labels and spec of a template manifest
+// after its placeholders have been filled in. Problems are reported at the
+// position of the nodes in the template.
+func DecodeTemplateSpec(labels, spec *yaml.Node) (*pb.EnvironmentSpecification, error) {
+	d := &decoder{}
+	result := &pb.EnvironmentSpecification{}
+	d.decode(spec, "spec", result)
+	if labels != nil {
+		withLabels := &pb.EnvironmentSpecification{}
+		d.decode(pair(keyNode("labels", labels), labels), "metadata", withLabels)
+		result.Labels = withLabels.Labels
+	}
+	if err := d.err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// template decodes a template document, recording its problems and
+// returning nil when it is unusable
+func (d *decoder) template(root *yaml.Node) *TemplateManifest {
+	root = resolve(root)
+	if root.Kind != yaml.MappingNode {
+		d.fail(root, "", "expected a template mapping with apiVersion, kind, metadata, parameters and spec")
+		return nil
+	}
+	t := &TemplateManifest{Template: &pb.Template{}}
+	var version, kind string
+	versionNode, kindNode := root, root
+	var metadata, parameters *yaml.Node
+	forEachPair(root, func(key, value *yaml.Node) {
+		switch key.Value {
+		case "apiVersion":
+			version, versionNode = d.string(value, "apiVersion"), value
+		case "kind":
+			kind, kindNode = d.string(value, "kind"), value
+		case "metadata":
+			metadata = value
+		case "parameters":
+			parameters = value
+		case "spec":
+			t.Spec = value
+		default:
+			d.fail(key, key.Value, "unknown field, a template has apiVersion, kind, metadata, parameters and spec")
+		}
+	})
+
+	switch version {
+	case APIVersion:
+	case "":
+		d.fail(root, "apiVersion", "is required, use %s", APIVersion)
+	default:
+		d.fail(versionNode, "apiVersion", "unsupported version %q, use %s", version, APIVersion)
+	}
+	switch kind {
+	case KindTemplate:
+	case "":
+		d.fail(root, "kind", "is required, use %s", KindTemplate)
+	default:
+		d.fail(kindNode, "kind", "unsupported kind %q, use %s", kind, KindTemplate)
+	}
+
+	if metadata == nil {
+		d.fail(root, "metadata", "is required")
+	} else {
+		d.templateMetadata(metadata, t)
+	}
+	if parameters != nil {
+		holder := &pb.Template{}
+		d.decode(pair(keyNode("parameters", parameters), parameters), "", holder)
+		t.Template.Parameters = holder.Parameters
+	}
+	if t.Spec == nil || isNull(t.Spec) {
+		d.fail(root, "spec", "is required")
+	} else if spec := resolve(t.Spec); spec.Kind != yaml.MappingNode {
+		d.fail(spec, "spec", "expected a mapping")
+	} else {
+		forEachPair(spec, func(key, _ *yaml.Node) {
+			switch key.Value {
+			case "name":
+				d.fail(key, "spec.name", "is set when an environment is created from the template")
+			case "labels":
+				d.fail(key, "spec.labels", "set metadata.labels instead")
+			}
+		})
+	}
+	return t
+}
+
+func (d *decoder) templateMetadata(node *yaml.Node, t *TemplateManifest) {
+	node = resolve(node)
+	if node.Kind != yaml.MappingNode {
+		d.fail(node, "metadata", "expected a mapping")
+		return
+	}
+	forEachPair(node, func(key, value *yaml.Node) {
+		path := "metadata." + key.Value
+		switch key.Value {
+		case "name":
+			t.Template.Name = d.string(value, path)
+		case "description":
+			t.Template.Description = d.string(value, path)
+		case "labels":
+			if resolve(value).Kind != yaml.MappingNode {
+				d.fail(value, path, "expected a mapping")
+				return
+			}
+			t.Labels = value
+		default:
+			d.fail(key, path, "unknown field, template metadata has name, description and labels")
+		}
+	})
+	if t.Template.Name == "" {
+		d.fail(node, "metadata.name", "is required")
+	}
+}
+
+// keyNode returns a key for value that the document does not spell out,
+// placed where the value is
+func keyNode(name string, value *yaml.Node) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: name, Line: value.Line, Column: value.Column}
+}
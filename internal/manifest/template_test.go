@@ -0,0 +1,85 @@
+package manifest
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+const cacheTemplate = `apiVersion: scheduler/v1
+kind: Template
+metadata:
+  name: cache
+  description: Redis with an application in front
+  labels:
+    team: ${team}
+parameters:
+  - name: http_port
+    type: integer
+    default_value: "8080"
+spec:
+  application_stack:
+    additional_services:
+      redis:
+        image: redis:7
+`
+
+func TestParseTemplate(t *testing.T) {
+	tm, err := ParseTemplate([]byte(cacheTemplate))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tm.Template.GetName() != "cache" || tm.Template.GetDescription() != "Redis with an application in front" {
+		t.Errorf("template = %v, want cache and its description", tm.Template)
+	}
+	if len(tm.Template.GetParameters()) != 1 || tm.Template.GetParameters()[0].GetDefaultValue() != "8080" {
+		t.Errorf("parameters = %v, want http_port defaulting to 8080", tm.Template.GetParameters())
+	}
+
+	// The placeholders stay in the nodes until they are filled in
+	var labels map[string]string
+	if err := tm.Labels.Decode(&labels); err != nil || labels["team"] != "${team}" {
+		t.Errorf("labels = %v, %v, want the team placeholder", labels, err)
+	}
+	tm.Labels.Content[1].Value = "backend"
+	spec, err := DecodeTemplateSpec(tm.Labels, tm.Spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if spec.GetLabels()["team"] != "backend" || spec.GetApplicationStack().GetAdditionalServices()["redis"].GetImage() != "redis:7" {
+		t.Errorf("DecodeTemplateSpec() = %v, want the filled in labels and the redis service", spec)
+	}
+}
+
+func TestParseTemplateErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		want     string
+	}{
+		{name: "empty", template: "", want: "no template found"},
+		{name: "two documents", template: cacheTemplate + "---\n" + cacheTemplate, want: "expected a single template manifest"},
+		{name: "environment kind", template: strings.Replace(cacheTemplate, "kind: Template", "kind: Environment", 1), want: `unsupported kind "Environment"`},
+		{name: "spec name", template: cacheTemplate + "  name: fixed\n", want: "spec.name: is set when an environment is created from the template"},
+		{name: "no name", template: strings.Replace(cacheTemplate, "  name: cache\n", "", 1), want: "metadata.name: is required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseTemplate([]byte(tt.template))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ParseTemplate() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+
+	// Problems in the spec point into the template
+	var spec yaml.Node
+	if err := yaml.Unmarshal([]byte("application_stack:\n  backend:\n    container:\n      imag: x\n"), &spec); err != nil {
+		t.Fatal(err)
+	}
+	_, err := DecodeTemplateSpec(nil, spec.Content[0])
+	if err == nil || !strings.Contains(err.Error(), "line 4, column 7: spec.application_stack.backend.container.imag: unknown field") {
+		t.Errorf("DecodeTemplateSpec() error = %v, want the position of the unknown field", err)
+	}
+}
@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"context"
+	"log"
+	"path"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"scheduler/internal/audit"
+	"scheduler/internal/redact"
+	pb "scheduler/proto/gen"
+)
+
+// UnaryAudit records every mutating call in the audit log with the caller,
+// the tenant it targeted, the request with sensitive fields masked, the
+// result and the duration. Calls are mutating unless their method name
+// starts with Get, List or Plan; calls that authentication or authorization
+// refuse are recorded whatever their method. It must run before
+// authentication, which records the caller for it.
+func UnaryAudit(auditLog *audit.Log) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		method := path.Base(info.FullMethod)
+		readOnly := strings.HasPrefix(method, "Get") || strings.HasPrefix(method, "List") || strings.HasPrefix(method, "Plan")
+
+		// The request is rendered up front since handlers may fill in defaults
+		payload := "{}"
+		if msg, ok := req.(proto.Message); ok {
+			payload = redact.String(msg)
+		}
+
+		start := time.Now()
+		call := audit.NewCall(&pb.AuditEvent{
+			Time:    timestamppb.New(start),
+			Method:  info.FullMethod,
+			Request: payload,
+		})
+		ctx = audit.NewContext(ctx, call)
+		if commonName := clientCommonName(ctx); commonName != "" {
+			audit.SetClientCommonName(ctx, commonName)
+		}
+		resp, err := handler(ctx, req)
+		if readOnly && !call.AuthFailed() {
+			return resp, err
+		}
+
+		event := call.Event()
+		event.EnvironmentId = auditedEnvironment(method, req, resp)
+		event.Code = status.Code(err).String()
+		event.DurationMs = time.Since(start).Milliseconds()
+		if err != nil {
+			event.Error = status.Convert(err).Message()
+		}
+		if r, ok := resp.(interface{ GetEnvironment() *pb.Environment }); ok && event.GetTenant() == "" {
+			event.Tenant = r.GetEnvironment().GetTenant()
+		}
+		if appendErr := auditLog.Append(event); appendErr != nil {
+			log.Printf("audit: failed to record %s: %v", info.FullMethod, appendErr)
+		}
+		return resp, err
+	}
+}
+
+// auditedEnvironment returns the ID of the environment a call targeted, taken
+// from the returned environment, an environment_id request field or the id
+// field of environment requests
+func auditedEnvironment(method string, req, resp any) string {
+	if r, ok := resp.(interface{ GetEnvironment() *pb.Environment }); ok && r.GetEnvironment().GetId() != "" {
+		return r.GetEnvironment().GetId()
+	}
+	if r, ok := req.(interface{ GetEnvironmentId() string }); ok {
+		return r.GetEnvironmentId()
+	}
+	if r, ok := req.(interface{ GetId() string }); ok && strings.Contains(method, "Environment") {
+		return r.GetId()
+	}
+	return ""
+}
@@ -0,0 +1,139 @@
+package middleware
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc"
+
+	"scheduler/internal/audit"
+	"scheduler/internal/auth"
+	pb "scheduler/proto/gen"
+)
+
+func TestUnaryAudit(t *testing.T) {
+	authenticator, err := auth.NewAuthenticator(
+		[]auth.APIKey{
+			{Name: "ops", Key: "ops-key", Role: "admin"},
+			{Name: "reader", Key: "reader-key", Role: "viewer", Tenant: "team-a"},
+		},
+		[]auth.ClientCert{{CommonName: "ci", Role: "deployer", Tenant: "team-b"}},
+		nil,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		method string
+		// target is the tenant the handler scope-checks
+		target string
+		// recorded is false when no event is expected
+		recorded bool
+		want     *pb.AuditEvent
+	}{
+		{
+			name:     "mutating call",
+			ctx:      withToken(context.Background(), "ops-key"),
+			method:   "/scheduler.v1.SchedulerService/DeleteEnvironment",
+			target:   "team-a",
+			recorded: true,
+			want:     &pb.AuditEvent{Subject: "ops", Role: "admin", Tenant: "team-a", Code: "OK"},
+		},
+		{
+			name:   "read-only call",
+			ctx:    withToken(context.Background(), "ops-key"),
+			method: "/scheduler.v1.SchedulerService/GetEnvironment",
+			target: "team-a",
+		},
+		{
+			name:     "unknown token",
+			ctx:      withToken(context.Background(), "wrong"),
+			method:   "/scheduler.v1.SchedulerService/GetEnvironment",
+			recorded: true,
+			want:     &pb.AuditEvent{Code: "Unauthenticated"},
+		},
+		{
+			name:     "missing role",
+			ctx:      withToken(context.Background(), "reader-key"),
+			method:   "/scheduler.v1.SchedulerService/DeleteEnvironment",
+			recorded: true,
+			want:     &pb.AuditEvent{Subject: "reader", Role: "viewer", CallerTenant: "team-a", Code: "PermissionDenied"},
+		},
+		{
+			name:     "client certificate",
+			ctx:      withClientCert(context.Background(), "ci"),
+			method:   "/scheduler.v1.SchedulerService/StartEnvironment",
+			target:   "team-b",
+			recorded: true,
+			want: &pb.AuditEvent{Subject: "ci", Role: "deployer", CallerTenant: "team-b", Tenant: "team-b",
+				ClientCommonName: "ci", Code: "OK"},
+		},
+		{
+			name:     "unknown client certificate",
+			ctx:      withClientCert(context.Background(), "stranger"),
+			method:   "/scheduler.v1.SchedulerService/GetEnvironment",
+			recorded: true,
+			want:     &pb.AuditEvent{ClientCommonName: "stranger", Code: "Unauthenticated"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auditLog, err := audit.Open(t.TempDir(), "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer auditLog.Close()
+
+			info := &grpc.UnaryServerInfo{FullMethod: tt.method}
+			handler := func(ctx context.Context, req any) (any, error) {
+				audit.SetTenant(ctx, tt.target)
+				return &pb.DeleteEnvironmentResponse{}, nil
+			}
+			authenticated := func(ctx context.Context, req any) (any, error) {
+				return UnaryAuth(authenticator)(ctx, req, info, handler)
+			}
+			UnaryAudit(auditLog)(tt.ctx, &pb.DeleteEnvironmentRequest{Id: "env-1"}, info, authenticated)
+
+			events := auditLog.List(audit.Filter{})
+			if !tt.recorded {
+				if len(events) != 0 {
+					t.Errorf("recorded %d events, want none", len(events))
+				}
+				return
+			}
+			if len(events) != 1 {
+				t.Fatalf("recorded %d events, want 1", len(events))
+			}
+			got := events[0]
+			if got.GetSubject() != tt.want.GetSubject() || got.GetRole() != tt.want.GetRole() ||
+				got.GetTenant() != tt.want.GetTenant() || got.GetCallerTenant() != tt.want.GetCallerTenant() ||
+				got.GetClientCommonName() != tt.want.GetClientCommonName() || got.GetCode() != tt.want.GetCode() {
+				t.Errorf("event = %v, want %v", got, tt.want)
+			}
+			if got.GetMethod() != tt.method || got.GetEnvironmentId() != "env-1" {
+				t.Errorf("event = %v, want method %s on env-1", got, tt.method)
+			}
+		})
+	}
+}
+
+func TestAuditFilterTenant(t *testing.T) {
+	events := []*pb.AuditEvent{
+		{Id: "target", Tenant: "team-a"},
+		{Id: "caller", CallerTenant: "team-a", Tenant: "team-b"},
+		{Id: "other", Tenant: "team-b"},
+	}
+	var matched []string
+	for _, event := range events {
+		if (audit.Filter{Tenant: "team-a"}).Matches(event) {
+			matched = append(matched, event.GetId())
+		}
+	}
+	if len(matched) != 2 || matched[0] != "target" || matched[1] != "caller" {
+		t.Errorf("matched %q, want the events targeting or made from team-a", matched)
+	}
+}
@@ -0,0 +1,109 @@
+package middleware
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"scheduler/internal/audit"
+	"scheduler/internal/auth"
+)
+
+// UnaryAuth authenticates the bearer token of every call, or its verified
+// client certificate when it has no token, and checks that the caller's
+// role may call the RPC. The identity is stored in the context
+// for handlers to scope by.
+func UnaryAuth(authenticator *auth.Authenticator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := authorize(ctx, authenticator, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamAuth is the streaming counterpart of UnaryAuth
+func StreamAuth(authenticator *auth.Authenticator) grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authorize(stream.Context(), authenticator, info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &contextStream{ServerStream: stream, ctx: ctx})
+	}
+}
+
+// RevealForAdmins lets only admins read sensitive fields unmasked
+func RevealForAdmins(ctx context.Context) bool {
+	identity, ok := auth.FromContext(ctx)
+	return ok && identity.Role >= auth.RoleAdmin
+}
+
+func authorize(ctx context.Context, authenticator *auth.Authenticator, fullMethod string) (context.Context, error) {
+	identity, err := authenticate(ctx, authenticator)
+	if err != nil {
+		audit.SetAuthFailed(ctx)
+		return nil, status.Errorf(codes.Unauthenticated, "%v", err)
+	}
+	audit.SetCaller(ctx, identity.Subject, identity.Role.String(), identity.Tenant)
+	if required := auth.RequiredRole(fullMethod); identity.Role < required {
+		audit.SetAuthFailed(ctx)
+		return nil, status.Errorf(codes.PermissionDenied, "%s requires the %s role, %s is a %s", fullMethod, required, identity.Subject, identity.Role)
+	}
+	return auth.NewContext(ctx, identity), nil
+}
+
+// authenticate resolves the bearer token of a call, or the verified client
+// certificate of calls without a token
+func authenticate(ctx context.Context, authenticator *auth.Authenticator) (*auth.Identity, error) {
+	if token := bearerToken(ctx); token != "" {
+		return authenticator.Authenticate(token)
+	}
+	if commonName := clientCommonName(ctx); commonName != "" {
+		return authenticator.AuthenticateClientCert(commonName)
+	}
+	return nil, auth.ErrUnauthenticated
+}
+
+// clientCommonName returns the common name of the verified client
+// certificate of a call, or "" without mutual TLS
+func clientCommonName(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(info.State.VerifiedChains) == 0 || len(info.State.VerifiedChains[0]) == 0 {
+		return ""
+	}
+	return info.State.VerifiedChains[0][0].Subject.CommonName
+}
+
+// bearerToken extracts the token from the authorization header of a call
+func bearerToken(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, value := range md.Get("authorization") {
+		scheme, token, ok := strings.Cut(value, " ")
+		if ok && strings.EqualFold(scheme, "bearer") {
+			return strings.TrimSpace(token)
+		}
+	}
+	return ""
+}
+
+// contextStream replaces the context of a server stream
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
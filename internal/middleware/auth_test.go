@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"scheduler/internal/auth"
+)
+
+// withClientCert returns a context of a call made over mutual TLS with a
+// verified client certificate
+func withClientCert(ctx context.Context, commonName string) context.Context {
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: commonName}}
+	return peer.NewContext(ctx, &peer.Peer{AuthInfo: credentials.TLSInfo{
+		State: tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}},
+	}})
+}
+
+// withToken returns a context of a call with a bearer token
+func withToken(ctx context.Context, token string) context.Context {
+	return metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", "Bearer "+token))
+}
+
+func TestUnaryAuth(t *testing.T) {
+	authenticator, err := auth.NewAuthenticator(
+		[]auth.APIKey{{Name: "ops", Key: "ops-key", Role: "admin"}},
+		[]auth.ClientCert{{CommonName: "ci", Role: "viewer", Tenant: "team-b"}},
+		nil,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		method  string
+		want    string
+		wantErr codes.Code
+	}{
+		{name: "token", ctx: withToken(context.Background(), "ops-key"), method: "/scheduler.v1.SchedulerService/DeleteEnvironment", want: "ops"},
+		{name: "client certificate", ctx: withClientCert(context.Background(), "ci"), method: "/scheduler.v1.SchedulerService/ListEnvironments", want: "ci"},
+		{
+			// A token takes precedence over the certificate of the connection
+			name:   "token over client certificate",
+			ctx:    withToken(withClientCert(context.Background(), "ci"), "ops-key"),
+			method: "/scheduler.v1.SchedulerService/DeleteEnvironment",
+			want:   "ops",
+		},
+		{name: "unknown certificate", ctx: withClientCert(context.Background(), "stranger"), method: "/scheduler.v1.SchedulerService/ListEnvironments", wantErr: codes.Unauthenticated},
+		{name: "no credentials", ctx: context.Background(), method: "/scheduler.v1.SchedulerService/ListEnvironments", wantErr: codes.Unauthenticated},
+		{name: "role too low", ctx: withClientCert(context.Background(), "ci"), method: "/scheduler.v1.SchedulerService/DeleteEnvironment", wantErr: codes.PermissionDenied},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var subject string
+			handler := func(ctx context.Context, req any) (any, error) {
+				identity, _ := auth.FromContext(ctx)
+				subject = identity.Subject
+				return nil, nil
+			}
+			_, err := UnaryAuth(authenticator)(tt.ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, handler)
+			if status.Code(err) != tt.wantErr {
+				t.Fatalf("UnaryAuth() error = %v, want %v", err, tt.wantErr)
+			}
+			if subject != tt.want {
+				t.Errorf("caller = %q, want %q", subject, tt.want)
+			}
+		})
+	}
+}
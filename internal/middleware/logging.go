@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"scheduler/internal/redact"
+)
+
+// UnaryLogging logs every call with its outcome and duration. With payloads
+// set the request is logged as well, with sensitive fields masked.
+func UnaryLogging(payloads bool) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		// The request is rendered up front since handlers may fill in defaults
+		payload := ""
+		if msg, ok := req.(proto.Message); ok && payloads {
+			payload = " request=" + redact.String(msg)
+		}
+
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		log.Printf("grpc: %s %s %s%s", info.FullMethod, status.Code(err), time.Since(start), payload)
+		return resp, err
+	}
+}
+
+// StreamLogging logs every stream with its outcome and duration once it ends
+func StreamLogging() grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, stream)
+		log.Printf("grpc: %s %s %s", info.FullMethod, status.Code(err), time.Since(start))
+		return err
+	}
+}
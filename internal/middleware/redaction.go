@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"scheduler/internal/redact"
+)
+
+// RevealPolicy decides whether the caller behind ctx may read sensitive
+// fields unmasked
+type RevealPolicy func(ctx context.Context) bool
+
+// revealer is implemented by requests that can ask for sensitive fields to
+// be returned unmasked
+type revealer interface {
+	GetRevealSensitive() bool
+}
+
+// UnaryRedaction masks sensitive fields in responses. Requests that set
+// reveal_sensitive receive them unmasked if allowed permits it and are
+// rejected otherwise.
+func UnaryRedaction(allowed RevealPolicy) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		reveal := false
+		if r, ok := req.(revealer); ok && r.GetRevealSensitive() {
+			if !allowed(ctx) {
+				return nil, status.Errorf(codes.PermissionDenied, "not allowed to reveal sensitive fields")
+			}
+			reveal = true
+		}
+
+		resp, err := handler(ctx, req)
+		if err != nil || reveal {
+			return resp, err
+		}
+		if msg, ok := resp.(proto.Message); ok {
+			return redact.Message(msg), nil
+		}
+		return resp, nil
+	}
+}
+
+// StreamRedaction masks sensitive fields in every message a server stream sends
+func StreamRedaction() grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &redactingStream{ServerStream: stream})
+	}
+}
+
+type redactingStream struct {
+	grpc.ServerStream
+}
+
+func (s *redactingStream) SendMsg(m any) error {
+	if msg, ok := m.(proto.Message); ok {
+		m = redact.Message(msg)
+	}
+	return s.ServerStream.SendMsg(m)
+}
@@ -0,0 +1,57 @@
+package network
+
+import (
+	"fmt"
+	"net/netip"
+)
+
+// AssignAddresses gives every name a host address in subnet. Addresses in
+// previous are kept when they are still inside the subnet, so containers do
+// not move around on updates. The network, gateway and broadcast addresses
+// are never handed out.
+func AssignAddresses(subnet netip.Prefix, gateway netip.Addr, names []string, previous map[string]netip.Addr) (map[string]netip.Addr, error) {
+	subnet = subnet.Masked()
+	used := map[netip.Addr]bool{
+		subnet.Addr(): true,
+		gateway:       true,
+	}
+	if subnet.Addr().Is4() {
+		used[lastAddr(subnet)] = true
+	}
+
+	assigned := make(map[string]netip.Addr, len(names))
+	for _, name := range names {
+		addr, ok := previous[name]
+		if ok && subnet.Contains(addr) && !used[addr] {
+			assigned[name] = addr
+			used[addr] = true
+		}
+	}
+
+	next := subnet.Addr()
+	for _, name := range names {
+		if _, ok := assigned[name]; ok {
+			continue
+		}
+		for used[next] {
+			next = next.Next()
+		}
+		if !subnet.Contains(next) {
+			return nil, fmt.Errorf("subnet %s has no free address for %s", subnet, name)
+		}
+		assigned[name] = next
+		used[next] = true
+	}
+
+	return assigned, nil
+}
+
+// lastAddr returns the highest address in the prefix
+func lastAddr(p netip.Prefix) netip.Addr {
+	addr := p.Masked().Addr().AsSlice()
+	for bit := p.Bits(); bit < len(addr)*8; bit++ {
+		addr[bit/8] |= byte(1) << (7 - bit%8)
+	}
+	last, _ := netip.AddrFromSlice(addr)
+	return last
+}
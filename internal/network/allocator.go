@@ -0,0 +1,250 @@
+package network
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"sort"
+	"sync"
+)
+
+var (
+	// ErrPoolExhausted is returned when no free subnet is left in the pool
+	ErrPoolExhausted = errors.New("subnet pool exhausted")
+	// ErrSubnetOverlap is returned when a requested subnet overlaps another
+	// environment or a host route
+	ErrSubnetOverlap = errors.New("subnet overlaps an existing network")
+)
+
+// SubnetAllocator carves non-overlapping environment subnets out of
+// configured pools and checks user-specified subnets for conflicts. Besides
+// the default pool, named pools can be added for environments that must draw
+// from their own address range.
+type SubnetAllocator struct {
+	mu         sync.Mutex
+	pools      map[string]subnetPool
+	assigned   map[string]netip.Prefix
+	hostRoutes func() ([]netip.Prefix, error)
+}
+
+// subnetPool is a set of prefixes handing out subnets of one length
+type subnetPool struct {
+	prefixes     []netip.Prefix
+	prefixLength int
+}
+
+// NewSubnetAllocator creates an allocator whose default pool hands out
+// subnets of the given prefix length from the pool prefixes
+func NewSubnetAllocator(pool []string, prefixLength int) (*SubnetAllocator, error) {
+	a := &SubnetAllocator{
+		pools:      make(map[string]subnetPool),
+		assigned:   make(map[string]netip.Prefix),
+		hostRoutes: HostRoutes,
+	}
+	if err := a.AddPool("", pool, prefixLength); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// AddPool registers a named pool. Its prefixes must not overlap those of any
+// other pool.
+func (a *SubnetAllocator) AddPool(name string, pool []string, prefixLength int) error {
+	if len(pool) == 0 {
+		return errors.New("subnet pool must contain at least one prefix")
+	}
+
+	prefixes := make([]netip.Prefix, 0, len(pool))
+	for _, entry := range pool {
+		prefix, err := netip.ParsePrefix(entry)
+		if err != nil {
+			return fmt.Errorf("invalid subnet pool entry %q: %w", entry, err)
+		}
+		prefix = prefix.Masked()
+		if prefixLength < prefix.Bits() || prefixLength > prefix.Addr().BitLen()-2 {
+			return fmt.Errorf("subnet prefix length /%d does not fit in pool entry %s", prefixLength, prefix)
+		}
+		prefixes = append(prefixes, prefix)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if _, ok := a.pools[name]; ok {
+		return fmt.Errorf("subnet pool %q is already registered", name)
+	}
+	for otherName, other := range a.pools {
+		for _, prefix := range prefixes {
+			for _, otherPrefix := range other.prefixes {
+				if prefix.Overlaps(otherPrefix) {
+					return fmt.Errorf("subnet pool entry %s overlaps %s of pool %q", prefix, otherPrefix, otherName)
+				}
+			}
+		}
+	}
+	a.pools[name] = subnetPool{prefixes: prefixes, prefixLength: prefixLength}
+	return nil
+}
+
+// Allocate assigns the first free subnet in the named pool to the
+// environment, the empty name selecting the default pool. Calling it again
+// for the same environment returns the existing assignment.
+func (a *SubnetAllocator) Allocate(environmentID, poolName string) (netip.Prefix, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if subnet, ok := a.assigned[environmentID]; ok {
+		return subnet, nil
+	}
+	pool, ok := a.pools[poolName]
+	if !ok {
+		return netip.Prefix{}, fmt.Errorf("unknown subnet pool %q", poolName)
+	}
+
+	routes, err := a.foreignRoutes()
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+
+	for _, prefix := range pool.prefixes {
+		candidate := netip.PrefixFrom(prefix.Addr(), pool.prefixLength)
+		for prefix.Contains(candidate.Addr()) {
+			if a.conflict(environmentID, candidate, routes) == "" {
+				a.assigned[environmentID] = candidate
+				return candidate, nil
+			}
+			next, ok := nextPrefix(candidate)
+			if !ok {
+				break
+			}
+			candidate = next
+		}
+	}
+
+	return netip.Prefix{}, fmt.Errorf("%w: no free /%d left", ErrPoolExhausted, pool.prefixLength)
+}
+
+// Reserve assigns a user-specified subnet to the environment after checking
+// it against the other environments and the host routing table
+func (a *SubnetAllocator) Reserve(environmentID string, subnet netip.Prefix) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if err := a.check(environmentID, subnet); err != nil {
+		return err
+	}
+	a.assigned[environmentID] = subnet.Masked()
+	return nil
+}
+
+// Check runs the checks Reserve would run without assigning the subnet
+func (a *SubnetAllocator) Check(environmentID string, subnet netip.Prefix) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return a.check(environmentID, subnet)
+}
+
+// check reports whether the subnet is free for the environment to use. It
+// must be called with a.mu held.
+func (a *SubnetAllocator) check(environmentID string, subnet netip.Prefix) error {
+	subnet = subnet.Masked()
+	routes, err := a.foreignRoutes()
+	if err != nil {
+		return err
+	}
+	if conflict := a.conflict(environmentID, subnet, routes); conflict != "" {
+		return fmt.Errorf("%w: %s overlaps %s", ErrSubnetOverlap, subnet, conflict)
+	}
+	return nil
+}
+
+// Restore records an assignment that was persisted earlier without checking
+// it for conflicts, used to rebuild allocator state at startup
+func (a *SubnetAllocator) Restore(environmentID string, subnet netip.Prefix) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.assigned[environmentID] = subnet.Masked()
+}
+
+// Release frees the subnet assigned to the environment
+func (a *SubnetAllocator) Release(environmentID string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	delete(a.assigned, environmentID)
+}
+
+// conflict describes what the subnet overlaps, or returns an empty string if
+// it is free for the environment to use
+func (a *SubnetAllocator) conflict(environmentID string, subnet netip.Prefix, routes []netip.Prefix) string {
+	ids := make([]string, 0, len(a.assigned))
+	for id := range a.assigned {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		if id != environmentID && a.assigned[id].Overlaps(subnet) {
+			return fmt.Sprintf("environment %s (%s)", id, a.assigned[id])
+		}
+	}
+	for _, route := range routes {
+		if route.Overlaps(subnet) {
+			return fmt.Sprintf("host route %s", route)
+		}
+	}
+	return ""
+}
+
+// foreignRoutes returns the host routes that were not created for one of
+// our own environment networks. Default routes are ignored since every
+// subnet overlaps them.
+func (a *SubnetAllocator) foreignRoutes() ([]netip.Prefix, error) {
+	routes, err := a.hostRoutes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read host routes: %w", err)
+	}
+
+	foreign := make([]netip.Prefix, 0, len(routes))
+	for _, route := range routes {
+		if route.Bits() == 0 {
+			continue
+		}
+		owned := false
+		for _, subnet := range a.assigned {
+			if subnet == route.Masked() {
+				owned = true
+				break
+			}
+		}
+		if !owned {
+			foreign = append(foreign, route)
+		}
+	}
+	return foreign, nil
+}
+
+// nextPrefix returns the prefix of the same length that directly follows p
+func nextPrefix(p netip.Prefix) (netip.Prefix, bool) {
+	addr := p.Addr().AsSlice()
+	bit := p.Bits() - 1
+	for bit >= 0 {
+		mask := byte(1) << (7 - bit%8)
+		if addr[bit/8]&mask == 0 {
+			addr[bit/8] |= mask
+			next, _ := netip.AddrFromSlice(addr)
+			return netip.PrefixFrom(next, p.Bits()), true
+		}
+		addr[bit/8] &^= mask
+		bit--
+	}
+	return netip.Prefix{}, false
+}
+
+// Gateway returns the conventional gateway address of a subnet, its first
+// host address
+func Gateway(subnet netip.Prefix) netip.Addr {
+	return subnet.Masked().Addr().Next()
+}
@@ -0,0 +1,256 @@
+package network
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// newTestAllocator returns an allocator over 10.10.0.0/16 handing out /24s
+// that sees routes as the host routing table
+func newTestAllocator(t *testing.T, routes ...string) *SubnetAllocator {
+	t.Helper()
+	a, err := NewSubnetAllocator([]string{"10.10.0.0/16"}, 24)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.hostRoutes = func() ([]netip.Prefix, error) {
+		var prefixes []netip.Prefix
+		for _, route := range routes {
+			prefixes = append(prefixes, netip.MustParsePrefix(route))
+		}
+		return prefixes, nil
+	}
+	return a
+}
+
+func TestAllocate(t *testing.T) {
+	a := newTestAllocator(t, "0.0.0.0/0", "10.10.1.0/24")
+
+	first, err := a.Allocate("env-1", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first != netip.MustParsePrefix("10.10.0.0/24") {
+		t.Errorf("first subnet = %s, want 10.10.0.0/24", first)
+	}
+	// 10.10.1.0/24 is taken by a host route, the default route is ignored
+	second, err := a.Allocate("env-2", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second != netip.MustParsePrefix("10.10.2.0/24") {
+		t.Errorf("second subnet = %s, want 10.10.2.0/24", second)
+	}
+	if again, _ := a.Allocate("env-1", ""); again != first {
+		t.Errorf("allocating again = %s, want the existing %s", again, first)
+	}
+
+	a.Release("env-1")
+	if reused, _ := a.Allocate("env-3", ""); reused != first {
+		t.Errorf("subnet after release = %s, want the released %s", reused, first)
+	}
+	if _, err := a.Allocate("env-4", "missing"); err == nil {
+		t.Error("allocating from an unknown pool succeeded")
+	}
+}
+
+func TestAllocateOwnRoutes(t *testing.T) {
+	// The bridge of an environment network adds a route for its subnet,
+	// which must not count as a conflict
+	a := newTestAllocator(t, "10.10.0.0/24")
+	a.Restore("env-1", netip.MustParsePrefix("10.10.0.0/24"))
+	if err := a.Reserve("env-1", netip.MustParsePrefix("10.10.0.0/24")); err != nil {
+		t.Errorf("reserving the subnet of the own route failed: %v", err)
+	}
+}
+
+func TestAllocatePoolExhausted(t *testing.T) {
+	a, err := NewSubnetAllocator([]string{"10.20.0.0/23"}, 24)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.hostRoutes = func() ([]netip.Prefix, error) { return nil, nil }
+	for _, id := range []string{"env-1", "env-2"} {
+		if _, err := a.Allocate(id, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := a.Allocate("env-3", ""); !errors.Is(err, ErrPoolExhausted) {
+		t.Errorf("Allocate() error = %v, want ErrPoolExhausted", err)
+	}
+}
+
+func TestNamedPools(t *testing.T) {
+	a := newTestAllocator(t)
+	if err := a.AddPool("isolated", []string{"172.30.0.0/16"}, 26); err != nil {
+		t.Fatal(err)
+	}
+	subnet, err := a.Allocate("env-1", "isolated")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if subnet != netip.MustParsePrefix("172.30.0.0/26") {
+		t.Errorf("subnet = %s, want 172.30.0.0/26", subnet)
+	}
+
+	if err := a.AddPool("overlapping", []string{"10.10.128.0/17"}, 24); err == nil {
+		t.Error("adding a pool overlapping the default pool succeeded")
+	}
+	if err := a.AddPool("isolated", []string{"172.31.0.0/16"}, 24); err == nil {
+		t.Error("adding a pool twice succeeded")
+	}
+	if err := a.AddPool("tiny", []string{"192.168.0.0/24"}, 16); err == nil {
+		t.Error("adding a pool whose subnets are larger than its prefixes succeeded")
+	}
+}
+
+func TestReserve(t *testing.T) {
+	a := newTestAllocator(t, "192.168.1.0/24")
+	if err := a.Reserve("env-1", netip.MustParsePrefix("10.50.0.7/24")); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		id     string
+		subnet string
+		want   error
+	}{
+		{name: "overlaps an environment", id: "env-2", subnet: "10.50.0.128/25", want: ErrSubnetOverlap},
+		{name: "overlaps a host route", id: "env-2", subnet: "192.168.0.0/16", want: ErrSubnetOverlap},
+		{name: "free", id: "env-2", subnet: "10.60.0.0/24"},
+		{name: "the environment's own subnet", id: "env-1", subnet: "10.50.0.0/24"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := a.Reserve(tt.id, netip.MustParsePrefix(tt.subnet))
+			if !errors.Is(err, tt.want) || (err != nil) != (tt.want != nil) {
+				t.Errorf("Reserve() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	a := newTestAllocator(t)
+	if err := a.Reserve("env-1", netip.MustParsePrefix("10.50.0.0/24")); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Check("env-2", netip.MustParsePrefix("10.50.0.0/25")); !errors.Is(err, ErrSubnetOverlap) {
+		t.Errorf("Check() error = %v, want ErrSubnetOverlap", err)
+	}
+	if err := a.Check("env-2", netip.MustParsePrefix("10.60.0.0/24")); err != nil {
+		t.Fatal(err)
+	}
+	// Checking does not assign the subnet
+	if err := a.Reserve("env-3", netip.MustParsePrefix("10.60.0.0/24")); err != nil {
+		t.Errorf("Reserve() after Check() error = %v, want the subnet still free", err)
+	}
+}
+
+func TestAssignAddresses(t *testing.T) {
+	subnet := netip.MustParsePrefix("10.10.0.0/29")
+	gateway := Gateway(subnet)
+	if gateway != netip.MustParseAddr("10.10.0.1") {
+		t.Fatalf("Gateway() = %s, want 10.10.0.1", gateway)
+	}
+
+	previous := map[string]netip.Addr{
+		"backend": netip.MustParseAddr("10.10.0.5"),
+		// Outside the subnet, so the database gets a new address
+		"database": netip.MustParseAddr("10.20.0.5"),
+	}
+	got, err := AssignAddresses(subnet, gateway, []string{"database", "backend", "frontend"}, previous)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]netip.Addr{
+		"database": netip.MustParseAddr("10.10.0.2"),
+		"backend":  netip.MustParseAddr("10.10.0.5"),
+		"frontend": netip.MustParseAddr("10.10.0.3"),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("AssignAddresses() = %v, want %v", got, want)
+	}
+
+	// A /29 holds 5 addresses besides network, gateway and broadcast
+	names := []string{"a", "b", "c", "d", "e", "f"}
+	if _, err := AssignAddresses(subnet, gateway, names, nil); err == nil {
+		t.Error("assigning more addresses than the subnet holds succeeded")
+	}
+	if got, err := AssignAddresses(subnet, gateway, names[:5], nil); err != nil || got["e"] != netip.MustParseAddr("10.10.0.6") {
+		t.Errorf("AssignAddresses() = %v, %v, want e at 10.10.0.6", got, err)
+	}
+}
+
+// routeHex encodes an IPv4 address the way /proc/net/route does, in host
+// byte order
+func routeHex(addr string) string {
+	a := netip.MustParseAddr(addr).As4()
+	return fmt.Sprintf("%08X", binary.NativeEndian.Uint32(a[:]))
+}
+
+func TestReadIPv4Routes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "route")
+	table := "Iface\tDestination\tGateway\tFlags\tRefCnt\tUse\tMetric\tMask\tMTU\tWindow\tIRTT\n" +
+		fmt.Sprintf("eth0\t%s\t%s\t0003\t0\t0\t0\t%s\t0\t0\t0\n", routeHex("0.0.0.0"), routeHex("192.168.1.1"), routeHex("0.0.0.0")) +
+		fmt.Sprintf("eth0\t%s\t%s\t0001\t0\t0\t0\t%s\t0\t0\t0\n", routeHex("192.168.1.0"), routeHex("0.0.0.0"), routeHex("255.255.255.0")) +
+		fmt.Sprintf("docker0\t%s\t%s\t0001\t0\t0\t0\t%s\t0\t0\t0\n", routeHex("172.17.0.0"), routeHex("0.0.0.0"), routeHex("255.255.0.0"))
+	if err := os.WriteFile(path, []byte(table), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := readIPv4Routes(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []netip.Prefix{
+		netip.MustParsePrefix("0.0.0.0/0"),
+		netip.MustParsePrefix("192.168.1.0/24"),
+		netip.MustParsePrefix("172.17.0.0/16"),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("readIPv4Routes() = %v, want %v", got, want)
+	}
+
+	if routes, err := readIPv4Routes(filepath.Join(t.TempDir(), "missing")); err != nil || routes != nil {
+		t.Errorf("readIPv4Routes() of a missing table = %v, %v, want no routes", routes, err)
+	}
+	if err := os.WriteFile(path, []byte("header\neth0 zzzz 0 0 0 0 0 FFFFFFFF\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readIPv4Routes(path); err == nil {
+		t.Error("reading a malformed destination succeeded")
+	}
+}
+
+func TestReadIPv6Routes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ipv6_route")
+	table := "fd000000000000000000000000000000 08 00000000000000000000000000000000 00 00000000000000000000000000000000 00000100 00000001 00000000 00000001 eth0\n" +
+		"fe800000000000000000000000000000 40 00000000000000000000000000000000 00 00000000000000000000000000000000 00000100 00000001 00000000 00000001 eth0\n"
+	if err := os.WriteFile(path, []byte(table), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := readIPv6Routes(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []netip.Prefix{netip.MustParsePrefix("fd00::/8"), netip.MustParsePrefix("fe80::/64")}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("readIPv6Routes() = %v, want %v", got, want)
+	}
+
+	if err := os.WriteFile(path, []byte("fd00 08\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readIPv6Routes(path); err == nil {
+		t.Error("reading a truncated destination succeeded")
+	}
+}
@@ -0,0 +1,105 @@
+package network
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/bits"
+	"net/netip"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	ipv4RouteTable = "/proc/net/route"
+	ipv6RouteTable = "/proc/net/ipv6_route"
+)
+
+// HostRoutes returns the destination prefixes of the host routing table. On
+// systems without procfs it returns no routes.
+func HostRoutes() ([]netip.Prefix, error) {
+	ipv4, err := readIPv4Routes(ipv4RouteTable)
+	if err != nil {
+		return nil, err
+	}
+	ipv6, err := readIPv6Routes(ipv6RouteTable)
+	if err != nil {
+		return nil, err
+	}
+	return append(ipv4, ipv6...), nil
+}
+
+// readIPv4Routes parses /proc/net/route, whose destination and mask columns
+// are hex encoded in host byte order
+func readIPv4Routes(path string) ([]netip.Prefix, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var routes []netip.Prefix
+	scanner := bufio.NewScanner(file)
+	scanner.Scan() // skip the header line
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 8 {
+			continue
+		}
+		destination, err := strconv.ParseUint(fields[1], 16, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid destination %q in %s: %w", fields[1], path, err)
+		}
+		mask, err := strconv.ParseUint(fields[7], 16, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid mask %q in %s: %w", fields[7], path, err)
+		}
+
+		var addr [4]byte
+		binary.NativeEndian.PutUint32(addr[:], uint32(destination))
+		var maskBytes [4]byte
+		binary.NativeEndian.PutUint32(maskBytes[:], uint32(mask))
+		prefixLength := bits.OnesCount32(binary.BigEndian.Uint32(maskBytes[:]))
+
+		routes = append(routes, netip.PrefixFrom(netip.AddrFrom4(addr), prefixLength).Masked())
+	}
+	return routes, scanner.Err()
+}
+
+// readIPv6Routes parses /proc/net/ipv6_route, whose destination column is hex
+// encoded in network byte order followed by the prefix length
+func readIPv6Routes(path string) ([]netip.Prefix, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var routes []netip.Prefix
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		destination, err := hex.DecodeString(fields[0])
+		if err != nil || len(destination) != 16 {
+			return nil, fmt.Errorf("invalid destination %q in %s", fields[0], path)
+		}
+		prefixLength, err := strconv.ParseUint(fields[1], 16, 8)
+		if err != nil {
+			return nil, fmt.Errorf("invalid prefix length %q in %s: %w", fields[1], path, err)
+		}
+
+		routes = append(routes, netip.PrefixFrom(netip.AddrFrom16([16]byte(destination)), int(prefixLength)).Masked())
+	}
+	return routes, scanner.Err()
+}
@@ -0,0 +1,82 @@
+package quota
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"scheduler/internal/stack"
+	pb "scheduler/proto/gen"
+)
+
+// ErrExceeded is returned when admitting an environment would exceed a quota
+var ErrExceeded = errors.New("quota exceeded")
+
+// Resources is an amount of the resources quotas apply to
+type Resources struct {
+	MemoryMB     int64   `mapstructure:"memory_mb"`
+	CPUCores     float64 `mapstructure:"cpu_cores"`
+	DiskMB       int64   `mapstructure:"disk_mb"`
+	Environments int     `mapstructure:"environments"`
+}
+
+// Add returns the sum of r and other
+func (r Resources) Add(other Resources) Resources {
+	return Resources{
+		MemoryMB:     r.MemoryMB + other.MemoryMB,
+		CPUCores:     r.CPUCores + other.CPUCores,
+		DiskMB:       r.DiskMB + other.DiskMB,
+		Environments: r.Environments + other.Environments,
+	}
+}
+
+// Requested sums the resource limits of every container of an environment
+// specification and counts it as one environment
+func Requested(spec *pb.EnvironmentSpecification) Resources {
+	requested := Resources{Environments: 1}
+	for _, member := range stack.Members(spec.GetApplicationStack()) {
+		limits := member.Container.GetResources()
+		requested.MemoryMB += limits.GetMemoryMb()
+		requested.CPUCores += limits.GetCpuCores()
+		requested.DiskMB += limits.GetDiskMb()
+	}
+	return requested
+}
+
+// Check verifies that adding requested to used stays within limits. Zero
+// limits are unlimited. The error names every exceeded resource together with
+// the shortfall.
+func Check(scope string, limits, used, requested Resources) error {
+	total := used.Add(requested)
+	var shortfalls []string
+	if limits.MemoryMB > 0 && total.MemoryMB > limits.MemoryMB {
+		shortfalls = append(shortfalls, fmt.Sprintf("memory_mb needs %d of %d with %d in use, short by %d",
+			requested.MemoryMB, limits.MemoryMB, used.MemoryMB, total.MemoryMB-limits.MemoryMB))
+	}
+	if limits.CPUCores > 0 && total.CPUCores > limits.CPUCores+1e-9 {
+		shortfalls = append(shortfalls, fmt.Sprintf("cpu_cores needs %g of %g with %g in use, short by %g",
+			requested.CPUCores, limits.CPUCores, used.CPUCores, total.CPUCores-limits.CPUCores))
+	}
+	if limits.DiskMB > 0 && total.DiskMB > limits.DiskMB {
+		shortfalls = append(shortfalls, fmt.Sprintf("disk_mb needs %d of %d with %d in use, short by %d",
+			requested.DiskMB, limits.DiskMB, used.DiskMB, total.DiskMB-limits.DiskMB))
+	}
+	if limits.Environments > 0 && total.Environments > limits.Environments {
+		shortfalls = append(shortfalls, fmt.Sprintf("environments limited to %d with %d in use",
+			limits.Environments, used.Environments))
+	}
+	if len(shortfalls) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w for %s: %s", ErrExceeded, scope, strings.Join(shortfalls, "; "))
+}
+
+// ToProto converts r to its API representation
+func (r Resources) ToProto() *pb.ResourceQuota {
+	return &pb.ResourceQuota{
+		MemoryMb:     r.MemoryMB,
+		CpuCores:     r.CPUCores,
+		DiskMb:       r.DiskMB,
+		Environments: int32(r.Environments),
+	}
+}
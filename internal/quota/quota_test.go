@@ -0,0 +1,89 @@
+package quota
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	pb "scheduler/proto/gen"
+)
+
+func TestRequested(t *testing.T) {
+	spec := &pb.EnvironmentSpecification{
+		ApplicationStack: &pb.ApplicationStack{
+			Database: &pb.DatabaseConfig{Container: &pb.ContainerConfig{
+				Resources: &pb.ResourceLimits{MemoryMb: 1024, CpuCores: 1, DiskMb: 5000},
+			}},
+			Backend: &pb.BackendConfig{Container: &pb.ContainerConfig{
+				Resources: &pb.ResourceLimits{MemoryMb: 512, CpuCores: 0.5},
+			}},
+			// Containers without limits count as nothing
+			Frontend: &pb.FrontendConfig{Container: &pb.ContainerConfig{}},
+			AdditionalServices: map[string]*pb.ContainerConfig{
+				"cache": {Resources: &pb.ResourceLimits{MemoryMb: 256, CpuCores: 0.25}},
+			},
+		},
+	}
+	want := Resources{MemoryMB: 1792, CPUCores: 1.75, DiskMB: 5000, Environments: 1}
+	if got := Requested(spec); got != want {
+		t.Errorf("Requested() = %+v, want %+v", got, want)
+	}
+	if got := Requested(&pb.EnvironmentSpecification{}); got != (Resources{Environments: 1}) {
+		t.Errorf("Requested() of an empty specification = %+v, want one environment", got)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	limits := Resources{MemoryMB: 4096, CPUCores: 2, Environments: 3}
+	tests := []struct {
+		name      string
+		used      Resources
+		requested Resources
+		want      []string
+	}{
+		{
+			name:      "within limits",
+			used:      Resources{MemoryMB: 2048, CPUCores: 1, Environments: 1},
+			requested: Resources{MemoryMB: 2048, CPUCores: 1, Environments: 1},
+		},
+		{
+			// Disk has no limit
+			name:      "unlimited resource",
+			requested: Resources{DiskMB: 1 << 20, Environments: 1},
+		},
+		{
+			// Summing tenths must not fail on rounding
+			name:      "fractional cores at the limit",
+			used:      Resources{CPUCores: 0.1 + 0.2 + 0.7},
+			requested: Resources{CPUCores: 1},
+		},
+		{
+			name:      "every shortfall",
+			used:      Resources{MemoryMB: 4000, CPUCores: 1.5, Environments: 3},
+			requested: Resources{MemoryMB: 200, CPUCores: 1, Environments: 1},
+			want: []string{
+				"memory_mb needs 200 of 4096 with 4000 in use, short by 104",
+				"cpu_cores needs 1 of 2 with 1.5 in use, short by 0.5",
+				"environments limited to 3 with 3 in use",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Check("tenant team-a", limits, tt.used, tt.requested)
+			if tt.want == nil {
+				if err != nil {
+					t.Errorf("Check() error = %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrExceeded) {
+				t.Fatalf("Check() error = %v, want ErrExceeded", err)
+			}
+			want := "quota exceeded for tenant team-a: " + strings.Join(tt.want, "; ")
+			if err.Error() != want {
+				t.Errorf("Check() error = %q, want %q", err.Error(), want)
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package redact
+
+import (
+	"strconv"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"scheduler/internal/secrets"
+	pb "scheduler/proto/gen"
+)
+
+// Mask replaces the value of sensitive fields
+const Mask = "[REDACTED]"
+
+// Message returns a copy of msg in which every field marked with the
+// (scheduler.v1.sensitive) option is masked, at any depth. Secret references
+// are kept since they only name a secret.
+func Message[T proto.Message](msg T) T {
+	if !msg.ProtoReflect().IsValid() {
+		return msg
+	}
+	clone := proto.Clone(msg).(T)
+	redact(clone.ProtoReflect())
+	return clone
+}
+
+// String renders msg as compact JSON with sensitive fields masked, for use in
+// logs and audit records
+func String(msg proto.Message) string {
+	if msg == nil {
+		return "{}"
+	}
+	data, err := protojson.Marshal(Message(msg))
+	if err != nil {
+		return "<unprintable " + string(msg.ProtoReflect().Descriptor().FullName()) + ">"
+	}
+	return string(data)
+}
+
+// Sensitive reports whether a field is marked with the (scheduler.v1.sensitive)
+// option
+func Sensitive(field protoreflect.FieldDescriptor) bool {
+	sensitive, _ := proto.GetExtension(field.Options(), pb.E_Sensitive).(bool)
+	return sensitive
+}
+
+// Masked returns the path of the first sensitive field of msg that holds
+// Mask, or "" when there is none. Masked responses sent back unchanged would
+// otherwise replace real passwords and keys with the mask.
+func Masked(msg proto.Message) string {
+	if msg == nil || !msg.ProtoReflect().IsValid() {
+		return ""
+	}
+	return masked(msg.ProtoReflect(), "")
+}
+
+func masked(msg protoreflect.Message, prefix string) string {
+	var found string
+	msg.Range(func(field protoreflect.FieldDescriptor, value protoreflect.Value) bool {
+		path := prefix + string(field.Name())
+		switch {
+		case field.IsMap():
+			value.Map().Range(func(key protoreflect.MapKey, entry protoreflect.Value) bool {
+				entryPath := path + "[" + key.String() + "]"
+				if field.MapValue().Kind() == protoreflect.MessageKind {
+					found = masked(entry.Message(), entryPath+".")
+				} else if Sensitive(field) && isMask(entry) {
+					found = entryPath
+				}
+				return found == ""
+			})
+		case field.IsList():
+			list := value.List()
+			for i := 0; i < list.Len() && found == ""; i++ {
+				entryPath := path + "[" + strconv.Itoa(i) + "]"
+				if field.Kind() == protoreflect.MessageKind {
+					found = masked(list.Get(i).Message(), entryPath+".")
+				} else if Sensitive(field) && isMask(list.Get(i)) {
+					found = entryPath
+				}
+			}
+		case field.Kind() == protoreflect.MessageKind:
+			found = masked(value.Message(), path+".")
+		case Sensitive(field) && isMask(value):
+			found = path
+		}
+		return found == ""
+	})
+	return found
+}
+
+// isMask reports whether a string or bytes value is Mask
+func isMask(value protoreflect.Value) bool {
+	switch v := value.Interface().(type) {
+	case string:
+		return v == Mask
+	case []byte:
+		return string(v) == Mask
+	}
+	return false
+}
+
+func redact(msg protoreflect.Message) {
+	// Fields are collected first since setting them while ranging over the
+	// message is not allowed
+	var sensitive []protoreflect.FieldDescriptor
+	msg.Range(func(field protoreflect.FieldDescriptor, value protoreflect.Value) bool {
+		switch {
+		case Sensitive(field):
+			sensitive = append(sensitive, field)
+		case field.IsMap():
+			if field.MapValue().Kind() == protoreflect.MessageKind {
+				value.Map().Range(func(_ protoreflect.MapKey, entry protoreflect.Value) bool {
+					redact(entry.Message())
+					return true
+				})
+			}
+		case field.IsList():
+			if field.Kind() == protoreflect.MessageKind {
+				list := value.List()
+				for i := 0; i < list.Len(); i++ {
+					redact(list.Get(i).Message())
+				}
+			}
+		case field.Kind() == protoreflect.MessageKind:
+			redact(value.Message())
+		}
+		return true
+	})
+
+	for _, field := range sensitive {
+		switch {
+		case field.IsMap() && maskable(field.MapValue()):
+			entries := msg.Mutable(field).Map()
+			entries.Range(func(key protoreflect.MapKey, entry protoreflect.Value) bool {
+				entries.Set(key, mask(field.MapValue(), entry))
+				return true
+			})
+		case field.IsList() && maskable(field):
+			list := msg.Mutable(field).List()
+			for i := 0; i < list.Len(); i++ {
+				list.Set(i, mask(field, list.Get(i)))
+			}
+		case !field.IsMap() && !field.IsList() && maskable(field):
+			msg.Set(field, mask(field, msg.Get(field)))
+		default:
+			// Messages and numbers cannot carry a mask, so they are dropped
+			msg.Clear(field)
+		}
+	}
+}
+
+// maskable reports whether values of a field can be replaced with Mask
+func maskable(field protoreflect.FieldDescriptor) bool {
+	return field.Kind() == protoreflect.StringKind || field.Kind() == protoreflect.BytesKind
+}
+
+// mask returns the masked form of a string or bytes value
+func mask(field protoreflect.FieldDescriptor, value protoreflect.Value) protoreflect.Value {
+	if field.Kind() == protoreflect.BytesKind {
+		return protoreflect.ValueOfBytes([]byte(Mask))
+	}
+	if _, ok := secrets.ParseReference(value.String()); ok || value.String() == "" {
+		return value
+	}
+	return protoreflect.ValueOfString(Mask)
+}
@@ -0,0 +1,119 @@
+package redact
+
+import (
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+
+	pb "scheduler/proto/gen"
+)
+
+func testSpec() *pb.EnvironmentSpecification {
+	return &pb.EnvironmentSpecification{
+		Name: "web",
+		ApplicationStack: &pb.ApplicationStack{
+			Backend: &pb.BackendConfig{
+				Container:                &pb.ContainerConfig{Image: "backend:1"},
+				DatabaseConnectionString: "postgres://app:hunter2@db/app",
+				ApiKeys:                  map[string]string{"STRIPE": "sk_live", "MAPS": "secret://maps-key"},
+			},
+			Database: &pb.DatabaseConfig{Username: "app", Password: "hunter2"},
+		},
+	}
+}
+
+func TestMessage(t *testing.T) {
+	spec := testSpec()
+	got := Message(spec)
+
+	backend := got.GetApplicationStack().GetBackend()
+	if backend.GetDatabaseConnectionString() != Mask {
+		t.Errorf("connection string = %q, want it masked", backend.GetDatabaseConnectionString())
+	}
+	if backend.GetApiKeys()["STRIPE"] != Mask {
+		t.Errorf("api key = %q, want it masked", backend.GetApiKeys()["STRIPE"])
+	}
+	if backend.GetApiKeys()["MAPS"] != "secret://maps-key" {
+		t.Errorf("secret reference = %q, want it kept", backend.GetApiKeys()["MAPS"])
+	}
+	if got.GetApplicationStack().GetDatabase().GetPassword() != Mask {
+		t.Errorf("password = %q, want it masked", got.GetApplicationStack().GetDatabase().GetPassword())
+	}
+	if got.GetApplicationStack().GetDatabase().GetUsername() != "app" || backend.GetContainer().GetImage() != "backend:1" {
+		t.Errorf("fields that are not sensitive changed: %v", got)
+	}
+	if !proto.Equal(spec, testSpec()) {
+		t.Error("Message() changed its argument")
+	}
+}
+
+func TestMessageBytesAndEmpty(t *testing.T) {
+	got := Message(&pb.CreateSecretRequest{Name: "db", Value: []byte("hunter2")})
+	if string(got.GetValue()) != Mask || got.GetName() != "db" {
+		t.Errorf("Message() = %v, want the value masked", got)
+	}
+
+	empty := Message(&pb.DatabaseConfig{Username: "app"})
+	if empty.GetPassword() != "" {
+		t.Errorf("empty password = %q, want it left empty", empty.GetPassword())
+	}
+
+	var nilSpec *pb.EnvironmentSpecification
+	if Message(nilSpec) != nil {
+		t.Error("Message(nil) is not nil")
+	}
+}
+
+func TestString(t *testing.T) {
+	got := String(testSpec())
+	if strings.Contains(got, "hunter2") || strings.Contains(got, "sk_live") {
+		t.Errorf("String() = %s, leaks a secret", got)
+	}
+	if !strings.Contains(got, "backend:1") {
+		t.Errorf("String() = %s, want the fields that are not sensitive", got)
+	}
+	if String(nil) != "{}" {
+		t.Errorf("String(nil) = %s, want {}", String(nil))
+	}
+}
+
+func TestMasked(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(spec *pb.EnvironmentSpecification)
+		want   string
+	}{
+		{name: "no mask"},
+		{
+			name:   "masked password",
+			change: func(spec *pb.EnvironmentSpecification) { spec.ApplicationStack.Database.Password = Mask },
+			want:   "application_stack.database.password",
+		},
+		{
+			name:   "masked api key",
+			change: func(spec *pb.EnvironmentSpecification) { spec.ApplicationStack.Backend.ApiKeys["STRIPE"] = Mask },
+			want:   "application_stack.backend.api_keys[STRIPE]",
+		},
+		{
+			// Only sensitive fields count, a mask elsewhere is just a value
+			name:   "mask in a field that is not sensitive",
+			change: func(spec *pb.EnvironmentSpecification) { spec.ApplicationStack.Database.Username = Mask },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := testSpec()
+			if tt.change != nil {
+				tt.change(spec)
+			}
+			if got := Masked(spec); got != tt.want {
+				t.Errorf("Masked() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if got := Masked(Message(testSpec())); got == "" {
+		t.Error("Masked() of a redacted specification found no mask")
+	}
+}
@@ -0,0 +1,175 @@
+package revision
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"scheduler/internal/fsutil"
+	pb "scheduler/proto/gen"
+)
+
+// ErrNotFound is returned when a revision does not exist
+var ErrNotFound = errors.New("revision not found")
+
+const revisionExtension = ".json"
+
+// Store persists the revisions of every environment, one immutable JSON
+// document per revision under a directory per environment
+type Store struct {
+	mu   sync.Mutex
+	root string
+}
+
+// NewStore opens the revision store rooted at root
+func NewStore(root string) (*Store, error) {
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create revision directory %s: %w", root, err)
+	}
+	return &Store{root: root}, nil
+}
+
+// Record stores spec as the next revision of an environment. rollbackOf names
+// the revision a rollback restored, 0 otherwise.
+func (s *Store) Record(environmentID string, spec *pb.EnvironmentSpecification, author string, rollbackOf int32) (*pb.EnvironmentRevision, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	numbers, err := s.numbers(environmentID)
+	if err != nil {
+		return nil, err
+	}
+	next := int32(1)
+	if len(numbers) > 0 {
+		next = numbers[len(numbers)-1] + 1
+	}
+
+	revision := &pb.EnvironmentRevision{
+		EnvironmentId: environmentID,
+		Number:        next,
+		Spec:          proto.Clone(spec).(*pb.EnvironmentSpecification),
+		CreatedAt:     timestamppb.Now(),
+		Author:        author,
+		RollbackOf:    rollbackOf,
+	}
+	data, err := protojson.MarshalOptions{Indent: "  "}.Marshal(revision)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode revision: %w", err)
+	}
+	if err := fsutil.WriteFileAtomic(s.path(environmentID, next), data, 0o600); err != nil {
+		return nil, fmt.Errorf("failed to write revision: %w", err)
+	}
+	return revision, nil
+}
+
+// SetDeployError records why deploying a revision to its environment failed
+func (s *Store) SetDeployError(environmentID string, number int32, message string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	revision, err := s.read(environmentID, number)
+	if err != nil {
+		return err
+	}
+	revision.DeployError = message
+	data, err := protojson.MarshalOptions{Indent: "  "}.Marshal(revision)
+	if err != nil {
+		return fmt.Errorf("failed to encode revision: %w", err)
+	}
+	if err := fsutil.WriteFileAtomic(s.path(environmentID, number), data, 0o600); err != nil {
+		return fmt.Errorf("failed to write revision: %w", err)
+	}
+	return nil
+}
+
+// Get returns one revision of an environment
+func (s *Store) Get(environmentID string, number int32) (*pb.EnvironmentRevision, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.read(environmentID, number)
+}
+
+// List returns the revisions of an environment, newest first
+func (s *Store) List(environmentID string) ([]*pb.EnvironmentRevision, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	numbers, err := s.numbers(environmentID)
+	if err != nil {
+		return nil, err
+	}
+	revisions := make([]*pb.EnvironmentRevision, 0, len(numbers))
+	for i := len(numbers) - 1; i >= 0; i-- {
+		revision, err := s.read(environmentID, numbers[i])
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, revision)
+	}
+	return revisions, nil
+}
+
+// Delete removes every revision of an environment
+func (s *Store) Delete(environmentID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.RemoveAll(filepath.Join(s.root, environmentID)); err != nil {
+		return fmt.Errorf("failed to delete revisions of %s: %w", environmentID, err)
+	}
+	return nil
+}
+
+func (s *Store) read(environmentID string, number int32) (*pb.EnvironmentRevision, error) {
+	data, err := os.ReadFile(s.path(environmentID, number))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s revision %d", ErrNotFound, environmentID, number)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read revision: %w", err)
+	}
+	revision := &pb.EnvironmentRevision{}
+	if err := protojson.Unmarshal(data, revision); err != nil {
+		return nil, fmt.Errorf("failed to decode %s revision %d: %w", environmentID, number, err)
+	}
+	return revision, nil
+}
+
+// numbers returns the revision numbers of an environment in ascending order
+func (s *Store) numbers(environmentID string) ([]int32, error) {
+	entries, err := os.ReadDir(filepath.Join(s.root, environmentID))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list revisions of %s: %w", environmentID, err)
+	}
+	var numbers []int32
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), revisionExtension)
+		if !ok || entry.IsDir() {
+			continue
+		}
+		number, err := strconv.ParseInt(name, 10, 32)
+		if err != nil {
+			continue
+		}
+		numbers = append(numbers, int32(number))
+	}
+	sort.Slice(numbers, func(i, j int) bool { return numbers[i] < numbers[j] })
+	return numbers, nil
+}
+
+func (s *Store) path(environmentID string, number int32) string {
+	return filepath.Join(s.root, environmentID, strconv.Itoa(int(number))+revisionExtension)
+}
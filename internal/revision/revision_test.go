@@ -0,0 +1,121 @@
+package revision
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+
+	pb "scheduler/proto/gen"
+)
+
+func TestRecordAndList(t *testing.T) {
+	s, err := NewStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	spec := &pb.EnvironmentSpecification{Name: "web", Description: "first"}
+	first, err := s.Record("env-1", spec, "alice", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Revisions keep their own copy of the specification
+	spec.Description = "changed"
+	if _, err := s.Record("env-1", spec, "bob", 0); err != nil {
+		t.Fatal(err)
+	}
+	rollback, err := s.Record("env-1", first.GetSpec(), "alice", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rollback.GetNumber() != 3 || rollback.GetRollbackOf() != 1 {
+		t.Errorf("Record() = revision %d restoring %d, want 3 restoring 1", rollback.GetNumber(), rollback.GetRollbackOf())
+	}
+
+	revisions, err := s.List("env-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var numbers []int32
+	for _, revision := range revisions {
+		numbers = append(numbers, revision.GetNumber())
+	}
+	if len(numbers) != 3 || numbers[0] != 3 || numbers[2] != 1 {
+		t.Fatalf("List() numbers = %v, want [3 2 1]", numbers)
+	}
+	got, err := s.Get("env-1", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.GetSpec().GetDescription() != "first" || got.GetAuthor() != "alice" || got.GetCreatedAt() == nil {
+		t.Errorf("Get() = %v, want the first specification by alice", got)
+	}
+
+	// Environments are numbered independently
+	other, err := s.Record("env-2", spec, "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other.GetNumber() != 1 {
+		t.Errorf("first revision of another environment = %d, want 1", other.GetNumber())
+	}
+}
+
+func TestSetDeployError(t *testing.T) {
+	s, err := NewStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorded, err := s.Record("env-1", &pb.EnvironmentSpecification{Name: "web"}, "alice", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetDeployError("env-1", 1, "backend failed to start"); err != nil {
+		t.Fatal(err)
+	}
+	got, err := s.Get("env-1", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorded.DeployError = "backend failed to start"
+	if !proto.Equal(got, recorded) {
+		t.Errorf("Get() = %v, want %v", got, recorded)
+	}
+	if err := s.SetDeployError("env-1", 2, "x"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("SetDeployError() of a missing revision = %v, want ErrNotFound", err)
+	}
+}
+
+func TestGetAndDelete(t *testing.T) {
+	root := t.TempDir()
+	s, err := NewStore(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Get("env-1", 1); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get() of a missing revision = %v, want ErrNotFound", err)
+	}
+	if revisions, err := s.List("env-1"); err != nil || len(revisions) != 0 {
+		t.Errorf("List() of an environment without revisions = %v, %v, want none", revisions, err)
+	}
+
+	if _, err := s.Record("env-1", &pb.EnvironmentSpecification{Name: "web"}, "", 0); err != nil {
+		t.Fatal(err)
+	}
+	// Files that are not revisions are skipped
+	if err := os.WriteFile(filepath.Join(root, "env-1", "notes.txt"), nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if revisions, err := s.List("env-1"); err != nil || len(revisions) != 1 {
+		t.Errorf("List() = %v, %v, want one revision", revisions, err)
+	}
+
+	if err := s.Delete("env-1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "env-1")); !os.IsNotExist(err) {
+		t.Errorf("revision directory after Delete() = %v, want it removed", err)
+	}
+}
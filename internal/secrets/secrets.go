@@ -0,0 +1,307 @@
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"scheduler/internal/fsutil"
+	pb "scheduler/proto/gen"
+)
+
+var (
+	// ErrNotFound is returned when a secret does not exist
+	ErrNotFound = errors.New("secret not found")
+	// ErrAlreadyExists is returned when creating a secret whose name is taken
+	ErrAlreadyExists = errors.New("secret already exists")
+	// ErrOtherTenant is returned when a secret belongs to another tenant
+	ErrOtherTenant = errors.New("secret belongs to another tenant")
+)
+
+// ReferencePrefix marks specification values that refer to a secret
+const ReferencePrefix = "secret://"
+
+// KeySize is the length of the AES-256 key secrets are encrypted with
+const KeySize = 32
+
+// MaxValueSize bounds the size of a single secret value
+const MaxValueSize = 64 * 1024
+
+const recordExtension = ".json"
+
+var namePattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]{0,127}$`)
+
+// ValidateName checks that a secret name is safe to use as a file name
+func ValidateName(name string) error {
+	if !namePattern.MatchString(name) {
+		return fmt.Errorf("invalid secret name %q: must match %s", name, namePattern)
+	}
+	return nil
+}
+
+// ParseReference returns the secret name of a secret://<name> reference and
+// whether value is a reference at all
+func ParseReference(value string) (string, bool) {
+	name, ok := strings.CutPrefix(value, ReferencePrefix)
+	return name, ok
+}
+
+// LoadKey reads a base64 encoded key from path, generating and writing a new
+// one when the file does not exist yet
+func LoadKey(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		key := make([]byte, KeySize)
+		if _, err := rand.Read(key); err != nil {
+			return nil, fmt.Errorf("failed to generate secrets key: %w", err)
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			return nil, fmt.Errorf("failed to create directory for secrets key: %w", err)
+		}
+		encoded := base64.StdEncoding.EncodeToString(key) + "\n"
+		if err := fsutil.WriteFileAtomic(path, []byte(encoded), 0o600); err != nil {
+			return nil, err
+		}
+		return key, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read secrets key %s: %w", path, err)
+	}
+
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode secrets key %s: %w", path, err)
+	}
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("secrets key %s must be %d bytes, got %d", path, KeySize, len(key))
+	}
+	return key, nil
+}
+
+// record is the on-disk form of a secret. The value is sealed with AES-GCM
+// using the secret name as additional data, so records cannot be swapped
+// between names.
+type record struct {
+	Name       string            `json:"name"`
+	Tenant     string            `json:"tenant,omitempty"`
+	Labels     map[string]string `json:"labels,omitempty"`
+	CreatedAt  time.Time         `json:"created_at"`
+	Ciphertext []byte            `json:"ciphertext"`
+}
+
+// Store keeps secrets encrypted at rest under a data root, one file per secret
+type Store struct {
+	mu   sync.Mutex
+	root string
+	aead cipher.AEAD
+}
+
+// NewStore creates a store keeping secrets under root, encrypted with key
+func NewStore(root string, key []byte) (*Store, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid secrets key: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("invalid secrets key: %w", err)
+	}
+	if err := os.MkdirAll(root, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create secret root %s: %w", root, err)
+	}
+	return &Store{root: root, aead: aead}, nil
+}
+
+// Create encrypts and stores a new secret owned by tenant
+func (s *Store) Create(name, tenant string, value []byte, labels map[string]string) (*pb.Secret, error) {
+	if err := ValidateName(name); err != nil {
+		return nil, err
+	}
+	if len(value) > MaxValueSize {
+		return nil, fmt.Errorf("secret %s is %d bytes, the limit is %d", name, len(value), MaxValueSize)
+	}
+
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := os.Stat(s.path(name)); err == nil {
+		return nil, fmt.Errorf("%w: %s", ErrAlreadyExists, name)
+	}
+
+	stored := record{
+		Name:       name,
+		Tenant:     tenant,
+		Labels:     labels,
+		CreatedAt:  time.Now().UTC(),
+		Ciphertext: s.aead.Seal(nonce, nonce, value, []byte(name)),
+	}
+	if err := s.write(&stored); err != nil {
+		return nil, err
+	}
+	return stored.metadata(), nil
+}
+
+// Assign makes tenant the owner of a secret, for secrets created before
+// secrets had owners
+func (s *Store) Assign(name, tenant string) (*pb.Secret, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, err := s.read(name)
+	if err != nil {
+		return nil, err
+	}
+	stored.Tenant = tenant
+	if err := s.write(stored); err != nil {
+		return nil, err
+	}
+	return stored.metadata(), nil
+}
+
+// Get returns the metadata of a secret
+func (s *Store) Get(name string) (*pb.Secret, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, err := s.read(name)
+	if err != nil {
+		return nil, err
+	}
+	return stored.metadata(), nil
+}
+
+// Value decrypts and returns the value of a secret of tenant. Secrets of
+// other tenants are never decrypted.
+func (s *Store) Value(name, tenant string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, err := s.read(name)
+	if err != nil {
+		return nil, err
+	}
+	if stored.Tenant != tenant {
+		return nil, fmt.Errorf("%w: %s", ErrOtherTenant, name)
+	}
+
+	nonceSize := s.aead.NonceSize()
+	if len(stored.Ciphertext) < nonceSize {
+		return nil, fmt.Errorf("secret %s is corrupt", name)
+	}
+	nonce, sealed := stored.Ciphertext[:nonceSize], stored.Ciphertext[nonceSize:]
+	value, err := s.aead.Open(nil, nonce, sealed, []byte(name))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt secret %s, the key may have changed: %w", name, err)
+	}
+	return value, nil
+}
+
+// List returns the metadata of every secret, ordered by name
+func (s *Store) List() ([]*pb.Secret, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := os.ReadDir(s.root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read secret root %s: %w", s.root, err)
+	}
+
+	var listed []*pb.Secret
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), recordExtension)
+		if entry.IsDir() || !ok || ValidateName(name) != nil {
+			continue
+		}
+		stored, err := s.read(name)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		listed = append(listed, stored.metadata())
+	}
+	sort.Slice(listed, func(i, j int) bool {
+		return listed[i].GetName() < listed[j].GetName()
+	})
+	return listed, nil
+}
+
+// Delete removes a secret. Callers are responsible for checking that no
+// environment still references it.
+func (s *Store) Delete(name string) error {
+	if ValidateName(name) != nil {
+		return fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := os.Remove(s.path(name))
+	if os.IsNotExist(err) {
+		return fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to remove secret %s: %w", name, err)
+	}
+	return nil
+}
+
+func (s *Store) read(name string) (*record, error) {
+	if ValidateName(name) != nil {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+
+	data, err := os.ReadFile(s.path(name))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read secret %s: %w", name, err)
+	}
+
+	stored := &record{}
+	if err := json.Unmarshal(data, stored); err != nil {
+		return nil, fmt.Errorf("failed to decode secret %s: %w", name, err)
+	}
+	return stored, nil
+}
+
+func (s *Store) write(stored *record) error {
+	data, err := json.MarshalIndent(stored, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode secret %s: %w", stored.Name, err)
+	}
+	return fsutil.WriteFileAtomic(s.path(stored.Name), data, 0o600)
+}
+
+func (s *Store) path(name string) string {
+	return filepath.Join(s.root, name+recordExtension)
+}
+
+func (r *record) metadata() *pb.Secret {
+	return &pb.Secret{
+		Name:      r.Name,
+		Labels:    r.Labels,
+		CreatedAt: timestamppb.New(r.CreatedAt),
+		Tenant:    r.Tenant,
+	}
+}
@@ -0,0 +1,161 @@
+package secrets
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newTestStore(t *testing.T) *Store {
+	t.Helper()
+	s, err := NewStore(filepath.Join(t.TempDir(), "secrets"), bytes.Repeat([]byte{1}, KeySize))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestParseReference(t *testing.T) {
+	tests := []struct {
+		value string
+		name  string
+		ok    bool
+	}{
+		{value: "secret://db-password", name: "db-password", ok: true},
+		{value: "secret://", name: "", ok: true},
+		{value: "plain value", name: "plain value", ok: false},
+	}
+	for _, tt := range tests {
+		name, ok := ParseReference(tt.value)
+		if name != tt.name || ok != tt.ok {
+			t.Errorf("ParseReference(%q) = %q, %v, want %q, %v", tt.value, name, ok, tt.name, tt.ok)
+		}
+	}
+}
+
+func TestLoadKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys", "secrets.key")
+
+	key, err := LoadKey(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(key) != KeySize {
+		t.Fatalf("generated key is %d bytes, want %d", len(key), KeySize)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("key file = %v, %v, want mode 0600", info, err)
+	}
+	again, err := LoadKey(path)
+	if err != nil || !bytes.Equal(again, key) {
+		t.Errorf("loading the key again = %x, %v, want the generated key", again, err)
+	}
+
+	short := base64.StdEncoding.EncodeToString([]byte("too short"))
+	if err := os.WriteFile(path, []byte(short), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadKey(path); err == nil {
+		t.Error("loading a short key succeeded")
+	}
+}
+
+func TestStore(t *testing.T) {
+	s := newTestStore(t)
+
+	created, err := s.Create("db-password", "a", []byte("hunter2"), map[string]string{"team": "a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.GetName() != "db-password" || created.GetLabels()["team"] != "a" || created.GetTenant() != "a" || created.GetCreatedAt() == nil {
+		t.Errorf("Create() = %v, want labelled metadata of tenant a", created)
+	}
+	if _, err := s.Create("db-password", "a", []byte("other"), nil); !errors.Is(err, ErrAlreadyExists) {
+		t.Errorf("creating twice = %v, want ErrAlreadyExists", err)
+	}
+	if _, err := s.Create("../escape", "a", []byte("x"), nil); err == nil {
+		t.Error("creating a secret with an unsafe name succeeded")
+	}
+	if _, err := s.Create("huge", "a", make([]byte, MaxValueSize+1), nil); err == nil {
+		t.Error("creating a secret above the size limit succeeded")
+	}
+
+	value, err := s.Value("db-password", "a")
+	if err != nil || string(value) != "hunter2" {
+		t.Errorf("Value() = %q, %v, want hunter2", value, err)
+	}
+	if _, err := s.Value("db-password", "b"); !errors.Is(err, ErrOtherTenant) {
+		t.Errorf("Value() for another tenant error = %v, want ErrOtherTenant", err)
+	}
+	// Secrets created before secrets had owners are assigned one
+	if _, err := s.Assign("db-password", "b"); err != nil {
+		t.Fatal(err)
+	}
+	if value, err := s.Value("db-password", "b"); err != nil || string(value) != "hunter2" {
+		t.Errorf("Value() after Assign = %q, %v, want hunter2", value, err)
+	}
+	// The value never reaches the disk in the clear
+	data, err := os.ReadFile(s.path("db-password"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "hunter2") {
+		t.Error("secret record contains the plaintext value")
+	}
+
+	if _, err := s.Create("api-key", "a", []byte("k"), nil); err != nil {
+		t.Fatal(err)
+	}
+	listed, err := s.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(listed) != 2 || listed[0].GetName() != "api-key" || listed[1].GetName() != "db-password" {
+		t.Errorf("List() = %v, want api-key and db-password", listed)
+	}
+
+	if err := s.Delete("api-key"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Get("api-key"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get after delete = %v, want ErrNotFound", err)
+	}
+	if err := s.Delete("api-key"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("deleting twice = %v, want ErrNotFound", err)
+	}
+}
+
+func TestValueTampering(t *testing.T) {
+	s := newTestStore(t)
+	if _, err := s.Create("a", "a", []byte("value of a"), nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Create("b", "a", []byte("value of b"), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// Records are bound to their name, so a swapped record fails to decrypt
+	record, err := os.ReadFile(s.path("a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(s.path("b"), record, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Value("b", "a"); err == nil {
+		t.Error("decrypting a record stored under another name succeeded")
+	}
+
+	// A different key cannot read the store
+	other, err := NewStore(s.root, bytes.Repeat([]byte{2}, KeySize))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := other.Value("a", "a"); err == nil {
+		t.Error("decrypting with another key succeeded")
+	}
+}
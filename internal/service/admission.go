@@ -0,0 +1,275 @@
+package service
+
+import (
+	"context"
+	"log"
+	"sort"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"scheduler/internal/capacity"
+	"scheduler/internal/quota"
+	pb "scheduler/proto/gen"
+)
+
+// preempted is a running environment chosen to make room for another one,
+// locked until it has been stopped
+type preempted struct {
+	env    *pb.Environment
+	unlock func()
+}
+
+// startEnvironment admits an environment and starts its containers, unless
+// it has to wait in the admission queue. The admission decision is made
+// under s.mu, which is released before containers are stopped or started.
+// It must be called with the environment lock held.
+func (s *SchedulerService) startEnvironment(ctx context.Context, env *pb.Environment) error {
+	previous := env.GetStatus()
+	s.mu.Lock()
+	start, victims, err := s.admit(env)
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if err := s.stopPreempted(ctx, env, victims); err != nil {
+		s.saveStatus(env, previous)
+		return err
+	}
+	if !start {
+		return nil
+	}
+	err = s.startContainers(ctx, env)
+	if err != nil && env.GetStatus() == pb.EnvironmentStatus_ENVIRONMENT_STATUS_CREATING {
+		// Nothing was started, so the capacity is released again
+		s.saveStatus(env, previous)
+	}
+	return err
+}
+
+// admit decides whether an environment can start now. When the host has
+// room, possibly after preempting running environments of lower priority,
+// env is marked creating so that its capacity stays reserved while its
+// containers start. Otherwise it is queued until capacity frees up, or
+// refused when queueing is disabled. Environments also queue behind waiting
+// environments of the same or higher priority. It must be called with s.mu
+// and the environment lock held; the caller stops the returned environments.
+func (s *SchedulerService) admit(env *pb.Environment) (bool, []preempted, error) {
+	priority := env.GetSpec().GetPriority()
+	if s.queue != nil && s.queue.Ahead(priority) {
+		return false, nil, s.enqueue(env)
+	}
+
+	var victims []preempted
+	if err := s.checkCapacity(env); err != nil {
+		if status.Code(err) != codes.ResourceExhausted {
+			return false, nil, err
+		}
+		victims = s.preempt(env)
+		if victims == nil {
+			if s.queue == nil {
+				return false, nil, err
+			}
+			return false, nil, s.enqueue(env)
+		}
+	}
+	if err := s.saveStatus(env, pb.EnvironmentStatus_ENVIRONMENT_STATUS_CREATING); err != nil {
+		for _, victim := range victims {
+			victim.unlock()
+		}
+		return false, nil, err
+	}
+	return true, victims, nil
+}
+
+// enqueue puts an environment into the admission queue and marks it pending
+func (s *SchedulerService) enqueue(env *pb.Environment) error {
+	if err := s.queue.Push(env.GetId(), env.GetSpec().GetPriority()); err != nil {
+		return status.Errorf(codes.Internal, "%v", err)
+	}
+	return s.saveStatus(env, pb.EnvironmentStatus_ENVIRONMENT_STATUS_PENDING)
+}
+
+// dequeue removes an environment from the admission queue, reporting whether
+// it was waiting
+func (s *SchedulerService) dequeue(env *pb.Environment) (bool, error) {
+	if s.queue == nil {
+		return false, nil
+	}
+	removed, err := s.queue.Remove(env.GetId())
+	if err != nil {
+		return false, status.Errorf(codes.Internal, "%v", err)
+	}
+	return removed, nil
+}
+
+// preempt picks running environments of lower priority than env to stop
+// until env fits, lowest priority and youngest first, and locks them.
+// Environments another operation holds are passed over. Nothing is picked
+// unless stopping every pick makes enough room. It must be called with s.mu
+// held.
+func (s *SchedulerService) preempt(env *pb.Environment) []preempted {
+	if !s.preemption || s.queue == nil {
+		return nil
+	}
+
+	priority := env.GetSpec().GetPriority()
+	var candidates []*pb.Environment
+	for _, other := range s.store.List() {
+		if other.GetStatus() == pb.EnvironmentStatus_ENVIRONMENT_STATUS_RUNNING && other.GetSpec().GetPriority() < priority {
+			candidates = append(candidates, other)
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].GetSpec().GetPriority() != candidates[j].GetSpec().GetPriority() {
+			return candidates[i].GetSpec().GetPriority() < candidates[j].GetSpec().GetPriority()
+		}
+		return candidates[i].GetCreatedAt().AsTime().After(candidates[j].GetCreatedAt().AsTime())
+	})
+
+	requested := capacity.FromQuota(quota.Requested(env.GetSpec()))
+	reserved, _ := s.reservedCapacity(env.GetId())
+	var victims []preempted
+	for _, candidate := range candidates {
+		if s.node.Fit(reserved, requested) == nil {
+			break
+		}
+		unlock, ok := s.tryLockEnvironment(candidate.GetId())
+		if !ok {
+			continue
+		}
+		reserved = reserved.Sub(capacity.FromQuota(quota.Requested(candidate.GetSpec())))
+		victims = append(victims, preempted{env: candidate, unlock: unlock})
+	}
+	if s.node.Fit(reserved, requested) != nil {
+		for _, victim := range victims {
+			victim.unlock()
+		}
+		return nil
+	}
+	return victims
+}
+
+// stopPreempted stops the environments preempted for env and queues them to
+// start again once capacity frees up, releasing their locks
+func (s *SchedulerService) stopPreempted(ctx context.Context, env *pb.Environment, victims []preempted) error {
+	var failed error
+	for _, victim := range victims {
+		err := s.stopContainers(ctx, victim.env, stopTimeout)
+		if err == nil {
+			err = s.enqueue(victim.env)
+		}
+		victim.unlock()
+		if err != nil {
+			if failed == nil {
+				failed = err
+			}
+			continue
+		}
+		log.Printf("Preempted environment %s (priority %d) to admit %s (priority %d)",
+			victim.env.GetId(), victim.env.GetSpec().GetPriority(), env.GetId(), env.GetSpec().GetPriority())
+	}
+	return failed
+}
+
+// RunAdmissionQueue starts queued environments in admission order whenever
+// capacity may have been freed, until ctx is canceled. Queued environments
+// start here rather than in the call that freed the capacity, so that no
+// call waits for the hooks and migrations of another environment.
+func (s *SchedulerService) RunAdmissionQueue(ctx context.Context) {
+	if s.queue == nil {
+		return
+	}
+	for {
+		s.drainQueue(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.queueReady:
+		}
+	}
+}
+
+// wakeQueue asks RunAdmissionQueue to look at the queue again
+func (s *SchedulerService) wakeQueue() {
+	if s.queue == nil {
+		return
+	}
+	select {
+	case s.queueReady <- struct{}{}:
+	default:
+	}
+}
+
+// drainQueue starts queued environments for as long as the head of the
+// queue fits into the host
+func (s *SchedulerService) drainQueue(ctx context.Context) {
+	for {
+		env, unlock := s.nextQueued()
+		if env == nil {
+			return
+		}
+
+		err := s.startContainers(ctx, env)
+		if err != nil && env.GetStatus() == pb.EnvironmentStatus_ENVIRONMENT_STATUS_CREATING {
+			// The runtime is unavailable, keep waiting
+			log.Printf("Failed to admit queued environment %s: %v", env.GetId(), err)
+			s.saveStatus(env, pb.EnvironmentStatus_ENVIRONMENT_STATUS_PENDING)
+			unlock()
+			return
+		}
+		if _, removeErr := s.queue.Remove(env.GetId()); removeErr != nil {
+			log.Printf("Failed to remove %s from the admission queue: %v", env.GetId(), removeErr)
+		}
+		unlock()
+		if err != nil {
+			log.Printf("Failed to start queued environment %s: %v", env.GetId(), err)
+			continue
+		}
+		log.Printf("Admitted queued environment %s", env.GetId())
+	}
+}
+
+// nextQueued locks the head of the admission queue and marks it creating
+// when it fits into the host. Entries of environments that were deleted or
+// started some other way are dropped. Nothing is returned while another
+// operation holds the head, which wakes the queue once it is done.
+func (s *SchedulerService) nextQueued() (*pb.Environment, func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, entry := range s.queue.Entries() {
+		unlock, ok := s.tryLockEnvironment(entry.EnvironmentID)
+		if !ok {
+			return nil, nil
+		}
+		env, err := s.store.Get(entry.EnvironmentID)
+		if err != nil || env.GetStatus() != pb.EnvironmentStatus_ENVIRONMENT_STATUS_PENDING {
+			if _, err := s.queue.Remove(entry.EnvironmentID); err != nil {
+				log.Printf("Failed to remove %s from the admission queue: %v", entry.EnvironmentID, err)
+			}
+			unlock()
+			continue
+		}
+		if s.checkCapacity(env) != nil {
+			unlock()
+			return nil, nil
+		}
+		if err := s.saveStatus(env, pb.EnvironmentStatus_ENVIRONMENT_STATUS_CREATING); err != nil {
+			log.Printf("Failed to admit queued environment %s: %v", env.GetId(), err)
+			unlock()
+			return nil, nil
+		}
+		return env, unlock
+	}
+	return nil, nil
+}
+
+// withQueuePosition records the admission queue position of env for responses
+func (s *SchedulerService) withQueuePosition(env *pb.Environment) *pb.Environment {
+	if s.queue != nil {
+		env.QueuePosition = int32(s.queue.Position(env.GetId()))
+	}
+	return env
+}
@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"scheduler/internal/admission"
+	"scheduler/internal/capacity"
+	"scheduler/internal/container"
+	pb "scheduler/proto/gen"
+)
+
+func TestSlowStartDoesNotBlockOtherEnvironments(t *testing.T) {
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	runtime := &container.Fake{}
+	s := newTestService(t, runtime)
+	slow := createEnvironment(t, s, testSpec("slow"))
+	other := createEnvironment(t, s, testSpec("other"))
+	runtime.OnRun = func(_, id string, _ container.Spec) error {
+		if id == slow.GetId()+"-database" {
+			close(entered)
+			<-release
+		}
+		return nil
+	}
+
+	ctx := context.Background()
+	started := make(chan error, 1)
+	go func() {
+		_, err := s.StartEnvironment(ctx, &pb.StartEnvironmentRequest{Id: slow.GetId()})
+		started <- err
+	}()
+	<-entered
+	defer func() {
+		close(release)
+		if err := <-started; err != nil {
+			t.Errorf("StartEnvironment(slow) error = %v", err)
+		}
+	}()
+
+	done := make(chan error, 1)
+	go func() {
+		if _, err := s.ListEnvironments(ctx, &pb.ListEnvironmentsRequest{}); err != nil {
+			done <- err
+			return
+		}
+		if _, err := s.GetEnvironment(ctx, &pb.GetEnvironmentRequest{Id: slow.GetId()}); err != nil {
+			done <- err
+			return
+		}
+		_, err := s.StartEnvironment(ctx, &pb.StartEnvironmentRequest{Id: other.GetId()})
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("calls during the slow start failed: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("calls on other environments waited for the slow start")
+	}
+}
+
+func TestQueuedEnvironmentStartsWhenCapacityFrees(t *testing.T) {
+	runtime := &container.Fake{}
+	s := newTestService(t, runtime, func(opts *Options) {
+		queue, err := admission.NewQueue(filepath.Join(t.TempDir(), "admission"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		opts.Queue = queue
+		opts.Node = &capacity.Node{
+			Capacity:   capacity.Resources{MemoryMB: 1000, CPUCores: 4, DiskMB: 1000},
+			Overcommit: capacity.Overcommit{Memory: 1, CPU: 1, Disk: 1},
+		}
+	})
+	sized := func(name string) *pb.EnvironmentSpecification {
+		spec := testSpec(name)
+		spec.ApplicationStack.Database.Container.Resources = &pb.ResourceLimits{MemoryMb: 600}
+		return spec
+	}
+	first := createEnvironment(t, s, sized("first"))
+	second := createEnvironment(t, s, sized("second"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.RunAdmissionQueue(ctx)
+
+	if _, err := s.StartEnvironment(ctx, &pb.StartEnvironmentRequest{Id: first.GetId()}); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := s.StartEnvironment(ctx, &pb.StartEnvironmentRequest{Id: second.GetId()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.GetEnvironment().GetStatus() != pb.EnvironmentStatus_ENVIRONMENT_STATUS_PENDING || resp.GetEnvironment().GetQueuePosition() != 1 {
+		t.Fatalf("second environment is %v at position %d, want queued first",
+			resp.GetEnvironment().GetStatus(), resp.GetEnvironment().GetQueuePosition())
+	}
+
+	if _, err := s.StopEnvironment(ctx, &pb.StopEnvironmentRequest{Id: first.GetId()}); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		env, _ := s.store.Get(second.GetId())
+		if env.GetStatus() == pb.EnvironmentStatus_ENVIRONMENT_STATUS_RUNNING {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("queued environment is %v after capacity freed, want RUNNING", env.GetStatus())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	for _, id := range runtime.RunningIDs(testNamespace) {
+		if strings.HasPrefix(id, first.GetId()) {
+			t.Errorf("container %s of the stopped environment still runs", id)
+		}
+	}
+}
+
+func TestPreemption(t *testing.T) {
+	tests := []struct {
+		name string
+		// runningPriority is the priority of the environment already running
+		runningPriority int32
+		wantPreempted   bool
+	}{
+		{name: "lower priority is preempted", runningPriority: 1, wantPreempted: true},
+		{name: "equal priority keeps running", runningPriority: 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runtime := &container.Fake{}
+			s := newTestService(t, runtime, func(opts *Options) {
+				queue, err := admission.NewQueue(filepath.Join(t.TempDir(), "admission"))
+				if err != nil {
+					t.Fatal(err)
+				}
+				opts.Queue = queue
+				opts.Preemption = true
+				opts.Node = &capacity.Node{
+					Capacity:   capacity.Resources{MemoryMB: 1000, CPUCores: 4, DiskMB: 1000},
+					Overcommit: capacity.Overcommit{Memory: 1, CPU: 1, Disk: 1},
+				}
+			})
+			sized := func(name string, priority int32) *pb.EnvironmentSpecification {
+				spec := testSpec(name)
+				spec.Priority = priority
+				spec.ApplicationStack.Database.Container.Resources = &pb.ResourceLimits{MemoryMb: 600}
+				return spec
+			}
+			running := createEnvironment(t, s, sized("running", tt.runningPriority))
+			urgent := createEnvironment(t, s, sized("urgent", 5))
+
+			ctx := context.Background()
+			if _, err := s.StartEnvironment(ctx, &pb.StartEnvironmentRequest{Id: running.GetId()}); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := s.StartEnvironment(ctx, &pb.StartEnvironmentRequest{Id: urgent.GetId()}); err != nil {
+				t.Fatal(err)
+			}
+
+			// The environment that lost out waits in the queue
+			wantRunning, wantQueued := running, urgent
+			if tt.wantPreempted {
+				wantRunning, wantQueued = urgent, running
+			}
+			if env, _ := s.store.Get(wantRunning.GetId()); env.GetStatus() != pb.EnvironmentStatus_ENVIRONMENT_STATUS_RUNNING {
+				t.Errorf("%s is %v, want RUNNING", env.GetName(), env.GetStatus())
+			}
+			if env, _ := s.store.Get(wantQueued.GetId()); env.GetStatus() != pb.EnvironmentStatus_ENVIRONMENT_STATUS_PENDING {
+				t.Errorf("%s is %v, want PENDING", env.GetName(), env.GetStatus())
+			}
+			if position := s.queue.Position(wantQueued.GetId()); position != 1 {
+				t.Errorf("%s is at queue position %d, want 1", wantQueued.GetName(), position)
+			}
+			for _, id := range runtime.RunningIDs(testNamespace) {
+				if strings.HasPrefix(id, wantQueued.GetId()) {
+					t.Errorf("container %s of the queued environment runs", id)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"strconv"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"scheduler/internal/audit"
+	"scheduler/internal/auth"
+	pb "scheduler/proto/gen"
+)
+
+// ListAuditEvents lists recorded mutating calls, newest first, with
+// pagination. Callers bound to a tenant only see the calls that targeted
+// the tenant or were made by its callers.
+func (s *SchedulerService) ListAuditEvents(ctx context.Context, req *pb.ListAuditEventsRequest) (*pb.ListAuditEventsResponse, error) {
+	if s.audit == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "audit logging is disabled, set audit.enabled")
+	}
+
+	pageSize := int(req.GetPageSize())
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+	offset := 0
+	if req.GetPageToken() != "" {
+		parsed, err := strconv.Atoi(req.GetPageToken())
+		if err != nil || parsed < 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid page token %q", req.GetPageToken())
+		}
+		offset = parsed
+	}
+
+	filter := audit.Filter{
+		EnvironmentID: req.GetEnvironmentId(),
+		Subject:       req.GetSubject(),
+	}
+	if req.GetSince() != nil {
+		filter.Since = req.GetSince().AsTime()
+	}
+	if req.GetUntil() != nil {
+		filter.Until = req.GetUntil().AsTime()
+	}
+	if identity, ok := auth.FromContext(ctx); ok {
+		filter.Tenant = identity.Tenant
+	}
+
+	events := s.audit.List(filter)
+	resp := &pb.ListAuditEventsResponse{}
+	if offset >= len(events) {
+		return resp, nil
+	}
+	end := min(offset+pageSize, len(events))
+	resp.Events = events[offset:end]
+	if end < len(events) {
+		resp.NextPageToken = strconv.Itoa(end)
+	}
+	return resp, nil
+}
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"scheduler/internal/audit"
+	"scheduler/internal/auth"
+)
+
+// checkScope verifies that the caller may access an environment of tenantName
+// with labels. Callers are unrestricted when authentication is disabled. The
+// tenant is recorded as the target of an audited call, refused or not.
+func checkScope(ctx context.Context, tenantName string, labels map[string]string) error {
+	audit.SetTenant(ctx, tenantName)
+	return scopeError(ctx, tenantName, labels)
+}
+
+// scopeError is checkScope without recording the tenant in the audit log
+func scopeError(ctx context.Context, tenantName string, labels map[string]string) error {
+	identity, ok := auth.FromContext(ctx)
+	switch {
+	case !ok:
+		return nil
+	case identity.Tenant != "" && identity.Tenant != tenantName:
+		return status.Errorf(codes.PermissionDenied, "%s may only access tenant %s", identity.Subject, identity.Tenant)
+	case !identity.Matches(labels):
+		return status.Errorf(codes.PermissionDenied, "%s may only access environments labelled %s", identity.Subject, formatSelector(identity.Selector))
+	}
+	return nil
+}
+
+// inScope reports whether the caller may access an environment of tenantName
+// with labels
+func inScope(ctx context.Context, tenantName string, labels map[string]string) bool {
+	return scopeError(ctx, tenantName, labels) == nil
+}
+
+// formatSelector renders a label selector as sorted key=value pairs
+func formatSelector(selector map[string]string) string {
+	pairs := make([]string, 0, len(selector))
+	for key, value := range selector {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
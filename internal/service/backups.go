@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"scheduler/internal/backup"
+	"scheduler/internal/container"
+	"scheduler/internal/stack"
+	pb "scheduler/proto/gen"
+)
+
+// BackupDatabase takes a logical backup of an environment's database. The
+// environment lock is held throughout, so the database cannot stop or the
+// environment be deleted under the dump.
+func (s *SchedulerService) BackupDatabase(ctx context.Context, req *pb.BackupDatabaseRequest) (*pb.BackupDatabaseResponse, error) {
+	unlock := s.lockEnvironment(req.GetId())
+	defer unlock()
+
+	env, err := s.getEnvironment(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	target, err := s.databaseTarget(env)
+	if err != nil {
+		return nil, err
+	}
+
+	taken, err := s.backups.Backup(ctx, target, false)
+	if err != nil {
+		return nil, backupError(err)
+	}
+	return &pb.BackupDatabaseResponse{Backup: taken}, nil
+}
+
+// ListDatabaseBackups lists the backups of an environment's database, oldest first
+func (s *SchedulerService) ListDatabaseBackups(ctx context.Context, req *pb.ListDatabaseBackupsRequest) (*pb.ListDatabaseBackupsResponse, error) {
+	env, err := s.getEnvironment(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	backups, err := s.backups.List(env.GetId())
+	if err != nil {
+		return nil, backupError(err)
+	}
+	return &pb.ListDatabaseBackupsResponse{Backups: backups}, nil
+}
+
+// RestoreDatabase loads a backup into the database of a running
+// environment, holding the environment lock while it does
+func (s *SchedulerService) RestoreDatabase(ctx context.Context, req *pb.RestoreDatabaseRequest) (*pb.RestoreDatabaseResponse, error) {
+	unlock := s.lockEnvironment(req.GetId())
+	defer unlock()
+
+	env, err := s.getEnvironment(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	target, err := s.databaseTarget(env)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.backups.Restore(ctx, target, req.GetBackupId()); err != nil {
+		return nil, backupError(err)
+	}
+	return &pb.RestoreDatabaseResponse{Environment: env}, nil
+}
+
+// RunBackupSchedule takes the database backups that are due under each
+// environment's backup policy and prunes scheduled backups beyond the
+// retention count. It checks the policies every interval until ctx is
+// canceled.
+func (s *SchedulerService) RunBackupSchedule(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		s.runScheduledBackups(ctx, time.Now())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *SchedulerService) runScheduledBackups(ctx context.Context, now time.Time) {
+	for _, env := range s.store.List() {
+		policy := env.GetSpec().GetApplicationStack().GetDatabase().GetBackupPolicy()
+		if policy.GetIntervalSeconds() <= 0 || env.GetStatus() != pb.EnvironmentStatus_ENVIRONMENT_STATUS_RUNNING {
+			continue
+		}
+		unlock := s.lockEnvironment(env.GetId())
+		s.runScheduledBackup(ctx, env.GetId(), now)
+		unlock()
+	}
+}
+
+// runScheduledBackup takes the backup of one environment if it is due and
+// prunes the old ones. It must be called with the environment lock held.
+func (s *SchedulerService) runScheduledBackup(ctx context.Context, id string, now time.Time) {
+	// The environment may have been stopped or deleted while waiting for
+	// its lock
+	env, err := s.store.Get(id)
+	if err != nil {
+		return
+	}
+	policy := env.GetSpec().GetApplicationStack().GetDatabase().GetBackupPolicy()
+	if policy.GetIntervalSeconds() <= 0 || env.GetStatus() != pb.EnvironmentStatus_ENVIRONMENT_STATUS_RUNNING {
+		return
+	}
+
+	backups, err := s.backups.List(env.GetId())
+	if err != nil {
+		log.Printf("backup: failed to list backups of environment %s: %v", env.GetId(), err)
+		return
+	}
+	var latest *pb.DatabaseBackup
+	for _, taken := range backups {
+		if taken.GetScheduled() {
+			latest = taken
+		}
+	}
+
+	interval := time.Duration(policy.GetIntervalSeconds()) * time.Second
+	if latest == nil || now.Sub(latest.GetCreatedAt().AsTime()) >= interval {
+		target, err := s.databaseTarget(env)
+		if err != nil {
+			log.Printf("backup: environment %s: %v", env.GetId(), err)
+			return
+		}
+		if _, err := s.backups.Backup(ctx, target, true); err != nil {
+			log.Printf("backup: scheduled backup of environment %s failed: %v", env.GetId(), err)
+			return
+		}
+	}
+
+	if err := s.backups.Prune(env.GetId(), int(policy.GetRetain())); err != nil {
+		log.Printf("backup: failed to prune backups of environment %s: %v", env.GetId(), err)
+	}
+}
+
+// databaseTarget locates the database container of a running environment
+func (s *SchedulerService) databaseTarget(env *pb.Environment) (backup.Target, error) {
+	database := env.GetSpec().GetApplicationStack().GetDatabase()
+	if database.GetContainer() == nil {
+		return backup.Target{}, status.Errorf(codes.FailedPrecondition, "environment %s has no database", env.GetId())
+	}
+	if env.GetStatus() != pb.EnvironmentStatus_ENVIRONMENT_STATUS_RUNNING {
+		return backup.Target{}, status.Errorf(codes.FailedPrecondition, "environment %s is %s, it must be running", env.GetId(), env.GetStatus())
+	}
+
+	username, databaseName := databaseCredentials(database)
+	password, err := s.resolveSecret(env, database.GetPassword())
+	if err != nil {
+		return backup.Target{}, err
+	}
+
+	return backup.Target{
+		EnvironmentID: env.GetId(),
+		Namespace:     s.namespaceFor(env),
+		ContainerID:   containerID(env.GetId(), stack.Member{Alias: stack.RoleDatabase}),
+		Database:      databaseName,
+		Username:      username,
+		Password:      password,
+	}, nil
+}
+
+// backupError maps backup errors onto gRPC status codes
+func backupError(err error) error {
+	switch {
+	case errors.Is(err, backup.ErrNotFound):
+		return status.Errorf(codes.NotFound, "%v", err)
+	case errors.Is(err, container.ErrUnavailable):
+		return status.Errorf(codes.FailedPrecondition, "%v", err)
+	default:
+		return status.Errorf(codes.Internal, "%v", err)
+	}
+}
@@ -0,0 +1,43 @@
+package service
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"scheduler/internal/backup"
+	"scheduler/internal/container"
+	pb "scheduler/proto/gen"
+)
+
+func TestDeleteEnvironmentBackups(t *testing.T) {
+	runtime := &container.Fake{}
+	dir := t.TempDir()
+	s := newTestService(t, runtime, func(opts *Options) {
+		backups, err := backup.NewManager(dir, runtime)
+		if err != nil {
+			t.Fatal(err)
+		}
+		opts.Backups = backups
+	})
+	ctx := context.Background()
+
+	env := createEnvironment(t, s, testSpec("backups"))
+	if _, err := s.StartEnvironment(ctx, &pb.StartEnvironmentRequest{Id: env.GetId()}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.BackupDatabase(ctx, &pb.BackupDatabaseRequest{Id: env.GetId()}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, env.GetId())); err != nil {
+		t.Fatalf("backup directory = %v, want it created", err)
+	}
+
+	if _, err := s.DeleteEnvironment(ctx, &pb.DeleteEnvironmentRequest{Id: env.GetId()}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, env.GetId())); !os.IsNotExist(err) {
+		t.Errorf("backup directory after deleting the environment = %v, want it removed", err)
+	}
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"net"
+	"net/url"
+	"strconv"
+
+	"scheduler/internal/stack"
+	pb "scheduler/proto/gen"
+)
+
+// defaultDatabaseUser is used when a DatabaseConfig leaves username empty,
+// matching the official Postgres image
+const defaultDatabaseUser = "postgres"
+
+// defaultDatabasePort is the port Postgres listens on when the database
+// container does not declare one
+const defaultDatabasePort = 5432
+
+// databaseCredentials returns the user and database name of a database with
+// the defaults of the official Postgres image filled in
+func databaseCredentials(database *pb.DatabaseConfig) (username, databaseName string) {
+	username = database.GetUsername()
+	if username == "" {
+		username = defaultDatabaseUser
+	}
+	databaseName = database.GetDatabaseName()
+	if databaseName == "" {
+		databaseName = username
+	}
+	return username, databaseName
+}
+
+// databaseEnv derives the variables connecting the backend of an environment
+// to its database: DATABASE_URL and the PG* variables libpq based drivers
+// read. The database is reached by its role alias when the environment has
+// a resolver and by its address on the environment network otherwise.
+// Nothing is returned for stacks without a database.
+func (s *SchedulerService) databaseEnv(env *pb.Environment) (map[string]string, error) {
+	database := env.GetSpec().GetApplicationStack().GetDatabase()
+	if database.GetContainer() == nil {
+		return nil, nil
+	}
+	username, databaseName := databaseCredentials(database)
+	password, err := s.resolveSecret(env, database.GetPassword())
+	if err != nil {
+		return nil, err
+	}
+
+	member := stack.Member{Alias: stack.RoleDatabase, Container: database.GetContainer()}
+	host := stack.RoleDatabase
+	if s.dns == nil {
+		id := containerID(env.GetId(), member)
+		for _, instance := range env.GetContainers() {
+			if instance.GetId() == id && instance.GetIpAddress() != "" {
+				host = instance.GetIpAddress()
+			}
+		}
+	}
+	port := strconv.Itoa(defaultDatabasePort)
+	if ports := database.GetContainer().GetPorts(); len(ports) > 0 {
+		port = strconv.Itoa(int(ports[0].GetContainerPort()))
+	}
+
+	connection := &url.URL{
+		Scheme: "postgresql",
+		User:   url.User(username),
+		Host:   net.JoinHostPort(host, port),
+		Path:   "/" + databaseName,
+	}
+	variables := map[string]string{
+		"PGHOST":     host,
+		"PGPORT":     port,
+		"PGDATABASE": databaseName,
+		"PGUSER":     username,
+	}
+	if password != "" {
+		connection.User = url.UserPassword(username, password)
+		variables["PGPASSWORD"] = password
+	}
+	variables["DATABASE_URL"] = connection.String()
+	return variables, nil
+}
+
+// postgresEnv returns the POSTGRES_* variables the official image creates
+// the database of an environment and its owner from on first start
+func (s *SchedulerService) postgresEnv(env *pb.Environment) (map[string]string, error) {
+	database := env.GetSpec().GetApplicationStack().GetDatabase()
+	username, databaseName := databaseCredentials(database)
+	password, err := s.resolveSecret(env, database.GetPassword())
+	if err != nil {
+		return nil, err
+	}
+	variables := map[string]string{
+		"POSTGRES_USER": username,
+		"POSTGRES_DB":   databaseName,
+	}
+	if password != "" {
+		variables["POSTGRES_PASSWORD"] = password
+	}
+	return variables, nil
+}
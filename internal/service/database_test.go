@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"scheduler/internal/container"
+	pb "scheduler/proto/gen"
+)
+
+func TestDatabaseEnvironment(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(database *pb.DatabaseConfig, backend *pb.ContainerConfig)
+		// database and backend hold the expected variables of the containers,
+		// an empty value for ones that must not be set
+		database map[string]string
+		backend  map[string]string
+	}{
+		{
+			name:     "derived",
+			database: map[string]string{"POSTGRES_USER": "app", "POSTGRES_DB": "app", "POSTGRES_PASSWORD": "secret"},
+			backend:  map[string]string{"PGPORT": "5432", "PGDATABASE": "app", "PGUSER": "app", "PGPASSWORD": "secret"},
+		},
+		{
+			name: "image defaults and declared port",
+			change: func(database *pb.DatabaseConfig, _ *pb.ContainerConfig) {
+				database.Username, database.DatabaseName, database.Password = "", "", ""
+				database.Container.Ports = []*pb.PortMapping{{ContainerPort: 6543}}
+			},
+			database: map[string]string{"POSTGRES_USER": "postgres", "POSTGRES_DB": "postgres", "POSTGRES_PASSWORD": ""},
+			backend:  map[string]string{"PGPORT": "6543", "PGDATABASE": "postgres", "PGUSER": "postgres", "PGPASSWORD": ""},
+		},
+		{
+			name: "explicit variables win",
+			change: func(_ *pb.DatabaseConfig, backend *pb.ContainerConfig) {
+				backend.EnvironmentVariables = map[string]string{"PGUSER": "reader"}
+			},
+			database: map[string]string{"POSTGRES_USER": "app"},
+			backend:  map[string]string{"PGUSER": "reader", "PGDATABASE": "app"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runtime := &container.Fake{}
+			s := newTestService(t, runtime)
+			spec := testSpec("database")
+			if tt.change != nil {
+				tt.change(spec.ApplicationStack.Database, spec.ApplicationStack.Backend.Container)
+			}
+			env := createEnvironment(t, s, spec)
+			if _, err := s.StartEnvironment(context.Background(), &pb.StartEnvironmentRequest{Id: env.GetId()}); err != nil {
+				t.Fatal(err)
+			}
+
+			database, _ := runtime.Running(testNamespace, env.GetId()+"-database")
+			backend, _ := runtime.Running(testNamespace, env.GetId()+"-backend")
+			for key, want := range tt.database {
+				if got := database.Env[key]; got != want {
+					t.Errorf("database %s = %q, want %q", key, got, want)
+				}
+			}
+			for key, want := range tt.backend {
+				if got := backend.Env[key]; got != want {
+					t.Errorf("backend %s = %q, want %q", key, got, want)
+				}
+			}
+			if host := backend.Env["PGHOST"]; host == "" || !strings.Contains(backend.Env["DATABASE_URL"], host) {
+				t.Errorf("DATABASE_URL = %q, want it to reach the database at %q", backend.Env["DATABASE_URL"], host)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"log"
+	"net/netip"
+
+	"scheduler/internal/stack"
+	pb "scheduler/proto/gen"
+)
+
+// syncDNS publishes the container names and role aliases of an environment
+// to the resolver on its network. It is called once the network bridge
+// exists, when containers start, and failures are logged rather than
+// returned so that a resolver that cannot bind does not fail the start.
+func (s *SchedulerService) syncDNS(env *pb.Environment) {
+	if s.dns == nil {
+		return
+	}
+
+	gateway, err := netip.ParseAddr(env.GetSpec().GetNetwork().GetGateway())
+	if err != nil {
+		log.Printf("dns: environment %s has invalid gateway: %v", env.GetId(), err)
+		return
+	}
+	if err := s.dns.Sync(env.GetId(), gateway, dnsRecords(env)); err != nil {
+		log.Printf("dns: failed to start resolver for environment %s: %v", env.GetId(), err)
+	}
+}
+
+// removeDNS stops the resolver on the network of an environment whose
+// containers are gone
+func (s *SchedulerService) removeDNS(env *pb.Environment) {
+	if s.dns != nil {
+		s.dns.Remove(env.GetId())
+	}
+}
+
+// dnsRecords maps every container name and role alias of an environment to
+// the address of its container instance
+func dnsRecords(env *pb.Environment) map[string]netip.Addr {
+	addresses := make(map[string]netip.Addr, len(env.GetContainers()))
+	for _, instance := range env.GetContainers() {
+		if addr, err := netip.ParseAddr(instance.GetIpAddress()); err == nil {
+			addresses[instance.GetId()] = addr
+		}
+	}
+
+	records := make(map[string]netip.Addr)
+	for _, member := range stack.Members(env.GetSpec().GetApplicationStack()) {
+		addr, ok := addresses[containerID(env.GetId(), member)]
+		if !ok {
+			continue
+		}
+		records[member.Alias] = addr
+		records[member.Name()] = addr
+	}
+	return records
+}
@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"scheduler/internal/container"
+	"scheduler/internal/stack"
+	pb "scheduler/proto/gen"
+)
+
+const (
+	// defaultExecHookTimeout bounds postStart and preStop commands that do
+	// not set a timeout
+	defaultExecHookTimeout = 30 * time.Second
+	// defaultHookContainerTimeout bounds deploy hook containers that do not
+	// set a timeout
+	defaultHookContainerTimeout = 10 * time.Minute
+)
+
+// validHookName matches deploy hook names, which are part of container IDs
+var validHookName = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// validateHooks checks the exec hooks of every container and the deploy
+// hooks of a specification
+func validateHooks(spec *pb.EnvironmentSpecification) error {
+	for _, member := range stack.Members(spec.GetApplicationStack()) {
+		lifecycle := member.Container.GetLifecycle()
+		if err := validateExecHook(member, "post_start", lifecycle.GetPostStart()); err != nil {
+			return err
+		}
+		if err := validateExecHook(member, "pre_stop", lifecycle.GetPreStop()); err != nil {
+			return err
+		}
+	}
+	if err := validateHookContainers("hooks.pre_deploy", spec.GetHooks().GetPreDeploy()); err != nil {
+		return err
+	}
+	return validateHookContainers("hooks.post_deploy", spec.GetHooks().GetPostDeploy())
+}
+
+// validateExecHook checks a postStart or preStop hook of a stack member
+func validateExecHook(member stack.Member, field string, hook *pb.ExecHook) error {
+	if hook == nil {
+		return nil
+	}
+	if len(hook.GetCommand()) == 0 {
+		return fmt.Errorf("%s: lifecycle.%s: command is required", member, field)
+	}
+	if hook.GetTimeoutSeconds() < 0 {
+		return fmt.Errorf("%s: lifecycle.%s: timeout_seconds must not be negative", member, field)
+	}
+	return nil
+}
+
+// validateHookContainers checks the hook containers of a deploy phase
+func validateHookContainers(field string, hooks []*pb.HookContainer) error {
+	names := make(map[string]bool)
+	for i, hook := range hooks {
+		path := fmt.Sprintf("%s[%d]", field, i)
+		switch {
+		case !validHookName.MatchString(hook.GetName()):
+			return fmt.Errorf("%s: name %q must be lowercase letters, digits and dashes", path, hook.GetName())
+		case names[hook.GetName()]:
+			return fmt.Errorf("%s: name %q is used more than once", path, hook.GetName())
+		case hook.GetImage() == "":
+			return fmt.Errorf("%s: image is required", path)
+		case hook.GetTimeoutSeconds() < 0:
+			return fmt.Errorf("%s: timeout_seconds must not be negative", path)
+		}
+		names[hook.GetName()] = true
+	}
+	return nil
+}
+
+// runDeployHooks runs the hook containers of a deploy phase in order,
+// stopping at the first one that fails
+func (s *SchedulerService) runDeployHooks(ctx context.Context, env *pb.Environment, phase pb.HookPhase, hooks []*pb.HookContainer) error {
+	for _, hook := range hooks {
+		output, err := s.runHookContainer(ctx, env, hook)
+		if errors.Is(err, container.ErrUnavailable) {
+			return err
+		}
+		recordHook(env, phase, hook.GetName(), output, err)
+		if err != nil {
+			return hookError(phase, "hook "+hook.GetName(), output, err)
+		}
+	}
+	return nil
+}
+
+// runHookContainer runs a deploy hook as a one-shot container
+func (s *SchedulerService) runHookContainer(ctx context.Context, env *pb.Environment, hook *pb.HookContainer) (string, error) {
+	variables := make(map[string]string, len(hook.GetEnvironmentVariables()))
+	for key, value := range hook.GetEnvironmentVariables() {
+		resolved, err := s.resolveSecret(env, value)
+		if err != nil {
+			return "", err
+		}
+		variables[key] = resolved
+	}
+	timeout := defaultHookContainerTimeout
+	if hook.GetTimeoutSeconds() > 0 {
+		timeout = time.Duration(hook.GetTimeoutSeconds()) * time.Second
+	}
+	spec := container.Spec{
+		Image:   hook.GetImage(),
+		Command: hook.GetCommand(),
+		Args:    hook.GetArgs(),
+		Env:     variables,
+	}
+	return s.runJob(ctx, env, env.GetId()+"-job-"+hook.GetName(), spec, timeout)
+}
+
+// runExecHook runs the postStart or preStop command of a stack member inside
+// its container. Members without the hook are left alone.
+func (s *SchedulerService) runExecHook(ctx context.Context, env *pb.Environment, member stack.Member, phase pb.HookPhase, hook *pb.ExecHook) error {
+	if hook == nil {
+		return nil
+	}
+	timeout := defaultExecHookTimeout
+	if hook.GetTimeoutSeconds() > 0 {
+		timeout = time.Duration(hook.GetTimeoutSeconds()) * time.Second
+	}
+	output, err := s.runCommand(ctx, env, containerID(env.GetId(), member), container.ExecOptions{Command: hook.GetCommand()}, timeout)
+	if errors.Is(err, container.ErrUnavailable) {
+		return err
+	}
+	recordHook(env, phase, member.Alias, output, err)
+	if err != nil {
+		return hookError(phase, "hook of "+member.String(), output, err)
+	}
+	return nil
+}
+
+// recordHook stores the result of a hook in the environment, replacing the
+// previous result of the same hook
+func recordHook(env *pb.Environment, phase pb.HookPhase, name, output string, err error) {
+	result := &pb.HookResult{
+		Phase:       phase,
+		Name:        name,
+		Succeeded:   err == nil,
+		Output:      output,
+		CompletedAt: timestamppb.Now(),
+	}
+	if err != nil && output == "" {
+		result.Output = err.Error()
+	}
+	for i, previous := range env.GetHookResults() {
+		if previous.GetPhase() == phase && previous.GetName() == name {
+			env.HookResults[i] = result
+			return
+		}
+	}
+	env.HookResults = append(env.HookResults, result)
+}
+
+// hookError describes a failed hook with the end of its output
+func hookError(phase pb.HookPhase, what, output string, err error) error {
+	if output == "" {
+		return fmt.Errorf("%s %s failed: %w", hookPhaseName(phase), what, err)
+	}
+	return fmt.Errorf("%s %s failed: %w\n%s", hookPhaseName(phase), what, err, output)
+}
+
+// hookPhaseName returns the name a phase has in specifications
+func hookPhaseName(phase pb.HookPhase) string {
+	switch phase {
+	case pb.HookPhase_HOOK_PHASE_PRE_DEPLOY:
+		return "preDeploy"
+	case pb.HookPhase_HOOK_PHASE_POST_START:
+		return "postStart"
+	case pb.HookPhase_HOOK_PHASE_POST_DEPLOY:
+		return "postDeploy"
+	default:
+		return "preStop"
+	}
+}
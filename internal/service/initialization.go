@@ -0,0 +1,295 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"scheduler/internal/container"
+	"scheduler/internal/stack"
+	pb "scheduler/proto/gen"
+)
+
+const (
+	// databaseReadyTimeout is how long a started database gets to pass its
+	// health check, on top of the start period of the check
+	databaseReadyTimeout = 2 * time.Minute
+	// databaseReadyInterval is how often the health check is run while
+	// waiting for the database
+	databaseReadyInterval = time.Second
+	// databaseScriptTimeout bounds each init script and seed data script
+	databaseScriptTimeout = 10 * time.Minute
+	// defaultMigrationTimeout bounds migrations that do not set a timeout
+	defaultMigrationTimeout = 10 * time.Minute
+)
+
+// validateDatabaseSteps checks the init scripts, migration and seed data of
+// a database
+func validateDatabaseSteps(database *pb.DatabaseConfig) error {
+	migration := database.GetMigration()
+	if len(database.GetInitScripts()) == 0 && migration == nil && len(database.GetSeedData()) == 0 {
+		return nil
+	}
+	if database.GetContainer() == nil {
+		return errors.New("database init scripts, migration and seed data require a database container")
+	}
+	if err := validateScripts("database.init_scripts", database.GetInitScripts()); err != nil {
+		return err
+	}
+	if err := validateScripts("database.seed_data", database.GetSeedData()); err != nil {
+		return err
+	}
+	if migration != nil {
+		if migration.GetImage() == "" {
+			return errors.New("database.migration: image is required")
+		}
+		if migration.GetTimeoutSeconds() < 0 {
+			return errors.New("database.migration: timeout_seconds must not be negative")
+		}
+	}
+	return nil
+}
+
+// validateScripts checks that the scripts of a step have unique names and
+// either inline SQL or a host path within the scripts directory
+func validateScripts(field string, scripts []*pb.DatabaseScript) error {
+	names := make(map[string]bool)
+	for i, script := range scripts {
+		path := fmt.Sprintf("%s[%d]", field, i)
+		switch {
+		case script.GetName() == "":
+			return fmt.Errorf("%s: name is required", path)
+		case names[script.GetName()]:
+			return fmt.Errorf("%s: name %q is used more than once", path, script.GetName())
+		case (script.GetSql() == "") == (script.GetHostPath() == ""):
+			return fmt.Errorf("%s: exactly one of sql and host_path is required", path)
+		case script.GetHostPath() != "" && !filepath.IsLocal(script.GetHostPath()):
+			return fmt.Errorf("%s: host_path %q must be relative to the scripts directory and stay inside it", path, script.GetHostPath())
+		}
+		names[script.GetName()] = true
+	}
+	return nil
+}
+
+// initializeDatabase waits for the freshly started database of an
+// environment to become healthy and runs its init scripts, migration and
+// seed data. Steps that already succeeded are skipped, unless the database
+// keeps no data across restarts, and every step run is recorded in the
+// environment. The first failing step stops the initialization.
+func (s *SchedulerService) initializeDatabase(ctx context.Context, env *pb.Environment) error {
+	database := env.GetSpec().GetApplicationStack().GetDatabase()
+	if len(database.GetInitScripts()) == 0 && database.GetMigration() == nil && len(database.GetSeedData()) == 0 {
+		return nil
+	}
+	if len(database.GetContainer().GetVolumes()) == 0 {
+		env.DatabaseSteps = nil
+	}
+	target := containerID(env.GetId(), stack.Member{Alias: stack.RoleDatabase})
+	if err := s.waitForDatabase(ctx, env, target); err != nil {
+		return err
+	}
+
+	for _, script := range database.GetInitScripts() {
+		if err := s.runDatabaseScript(ctx, env, target, pb.DatabaseStep_DATABASE_STEP_INIT_SCRIPT, script); err != nil {
+			return err
+		}
+	}
+	if migration := database.GetMigration(); migration != nil {
+		version := migration.GetVersion()
+		if version == "" {
+			version = migration.GetImage()
+		}
+		if !succeeded(env, pb.DatabaseStep_DATABASE_STEP_MIGRATION, version) {
+			output, err := s.runMigration(ctx, env, migration)
+			recordStep(env, pb.DatabaseStep_DATABASE_STEP_MIGRATION, version, output, err)
+			if err != nil {
+				return fmt.Errorf("migration %s failed: %w", version, err)
+			}
+		}
+	}
+	for _, script := range database.GetSeedData() {
+		if err := s.runDatabaseScript(ctx, env, target, pb.DatabaseStep_DATABASE_STEP_SEED_DATA, script); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// waitForDatabase runs the health check of the database container, or
+// pg_isready when it has none, until it passes
+func (s *SchedulerService) waitForDatabase(ctx context.Context, env *pb.Environment, target string) error {
+	database := env.GetSpec().GetApplicationStack().GetDatabase()
+	check := database.GetContainer().GetHealthCheck()
+	command := check.GetCommand()
+	if len(command) == 0 {
+		username, databaseName := databaseCredentials(database)
+		command = []string{"pg_isready", "-U", username, "-d", databaseName}
+	}
+	probeTimeout := time.Duration(check.GetTimeoutSeconds()) * time.Second
+	if probeTimeout <= 0 {
+		probeTimeout = 5 * time.Second
+	}
+	timeout := databaseReadyTimeout + time.Duration(check.GetStartPeriodSeconds())*time.Second
+
+	deadline := time.Now().Add(timeout)
+	for {
+		output, err := s.runCommand(ctx, env, target, container.ExecOptions{Command: command}, probeTimeout)
+		if err == nil {
+			return nil
+		}
+		if errors.Is(err, container.ErrUnavailable) {
+			return err
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("database did not become healthy within %s: %v: %s", timeout, err, output)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(databaseReadyInterval):
+		}
+	}
+}
+
+// runDatabaseScript runs an init or seed data script with psql in a single
+// transaction unless it already succeeded
+func (s *SchedulerService) runDatabaseScript(ctx context.Context, env *pb.Environment, target string, step pb.DatabaseStep, script *pb.DatabaseScript) error {
+	if succeeded(env, step, script.GetName()) {
+		return nil
+	}
+	sql := script.GetSql()
+	if script.GetHostPath() != "" {
+		data, err := s.readScript(script.GetHostPath())
+		if err != nil {
+			recordStep(env, step, script.GetName(), "", err)
+			return fmt.Errorf("%s %s failed: %w", stepName(step), script.GetName(), err)
+		}
+		sql = string(data)
+	}
+
+	database := env.GetSpec().GetApplicationStack().GetDatabase()
+	username, databaseName := databaseCredentials(database)
+	password, err := s.resolveSecret(env, database.GetPassword())
+	if err != nil {
+		return err
+	}
+	variables := map[string]string{}
+	if password != "" {
+		variables["PGPASSWORD"] = password
+	}
+	output, err := s.runCommand(ctx, env, target, container.ExecOptions{
+		Command: []string{"psql", "-v", "ON_ERROR_STOP=1", "--single-transaction", "-U", username, "-d", databaseName, "-f", "-"},
+		Env:     variables,
+		Stdin:   strings.NewReader(sql),
+	}, databaseScriptTimeout)
+	recordStep(env, step, script.GetName(), output, err)
+	if err != nil {
+		return fmt.Errorf("%s %s failed: %w", stepName(step), script.GetName(), err)
+	}
+	return nil
+}
+
+// readScript reads a script from the scripts directory. Symbolic links are
+// followed only while they stay inside the directory, so that specifications
+// cannot read other files of the scheduler host.
+func (s *SchedulerService) readScript(hostPath string) ([]byte, error) {
+	if s.scriptsDir == "" {
+		return nil, errors.New("host_path scripts are disabled, set database.scripts_dir")
+	}
+	if !filepath.IsLocal(hostPath) {
+		return nil, fmt.Errorf("host_path %q is outside the scripts directory", hostPath)
+	}
+	root, err := filepath.EvalSymlinks(s.scriptsDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve scripts directory: %w", err)
+	}
+	resolved, err := filepath.EvalSymlinks(filepath.Join(root, hostPath))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", hostPath, err)
+	}
+	if relative, err := filepath.Rel(root, resolved); err != nil || !filepath.IsLocal(relative) {
+		return nil, fmt.Errorf("host_path %q is outside the scripts directory", hostPath)
+	}
+	data, err := os.ReadFile(resolved)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", hostPath, err)
+	}
+	return data, nil
+}
+
+// runMigration runs the migration container of a database with the same
+// connection settings as the backend
+func (s *SchedulerService) runMigration(ctx context.Context, env *pb.Environment, migration *pb.DatabaseMigration) (string, error) {
+	variables, err := s.databaseEnv(env)
+	if err != nil {
+		return "", err
+	}
+	for key, value := range migration.GetEnvironmentVariables() {
+		resolved, err := s.resolveSecret(env, value)
+		if err != nil {
+			return "", err
+		}
+		variables[key] = resolved
+	}
+	timeout := defaultMigrationTimeout
+	if migration.GetTimeoutSeconds() > 0 {
+		timeout = time.Duration(migration.GetTimeoutSeconds()) * time.Second
+	}
+	spec := container.Spec{
+		Image:   migration.GetImage(),
+		Command: migration.GetCommand(),
+		Args:    migration.GetArgs(),
+		Env:     variables,
+	}
+	return s.runJob(ctx, env, env.GetId()+"-job-migration", spec, timeout)
+}
+
+// succeeded reports whether a step already succeeded for the environment
+func succeeded(env *pb.Environment, step pb.DatabaseStep, name string) bool {
+	for _, result := range env.GetDatabaseSteps() {
+		if result.GetStep() == step && result.GetName() == name {
+			return result.GetSucceeded()
+		}
+	}
+	return false
+}
+
+// recordStep stores the result of a step in the environment, replacing the
+// previous result of the same step. A single migration result is kept.
+func recordStep(env *pb.Environment, step pb.DatabaseStep, name, output string, err error) {
+	result := &pb.DatabaseStepResult{
+		Step:        step,
+		Name:        name,
+		Succeeded:   err == nil,
+		Output:      output,
+		CompletedAt: timestamppb.Now(),
+	}
+	if err != nil && output == "" {
+		result.Output = err.Error()
+	}
+	for i, previous := range env.GetDatabaseSteps() {
+		if previous.GetStep() == step && (previous.GetName() == name || step == pb.DatabaseStep_DATABASE_STEP_MIGRATION) {
+			env.DatabaseSteps[i] = result
+			return
+		}
+	}
+	env.DatabaseSteps = append(env.DatabaseSteps, result)
+}
+
+// stepName describes a step kind in error messages
+func stepName(step pb.DatabaseStep) string {
+	switch step {
+	case pb.DatabaseStep_DATABASE_STEP_INIT_SCRIPT:
+		return "init script"
+	case pb.DatabaseStep_DATABASE_STEP_SEED_DATA:
+		return "seed data"
+	default:
+		return "migration"
+	}
+}
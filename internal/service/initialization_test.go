@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"scheduler/internal/container"
+	pb "scheduler/proto/gen"
+)
+
+func TestMigrationRunsOncePerVersion(t *testing.T) {
+	tests := []struct {
+		name string
+		// persistent gives the database a named volume, so completed steps
+		// survive restarts
+		persistent bool
+		// version is the migration version after the first start
+		version string
+		want    int
+	}{
+		{name: "same version is skipped", persistent: true, version: "1", want: 1},
+		{name: "new version runs again", persistent: true, version: "2", want: 2},
+		{name: "database without data runs again", persistent: false, version: "1", want: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runtime := &container.Fake{}
+			s := newTestService(t, runtime)
+			spec := testSpec("migrations")
+			database := spec.ApplicationStack.Database
+			database.Migration = &pb.DatabaseMigration{Image: "migrate:1", Version: "1"}
+			if tt.persistent {
+				database.Container.Volumes = []*pb.VolumeMount{{Name: "migrations-data", MountPath: "/var/lib/postgresql/data"}}
+			}
+			env := createEnvironment(t, s, spec)
+
+			ctx := context.Background()
+			if _, err := s.StartEnvironment(ctx, &pb.StartEnvironmentRequest{Id: env.GetId()}); err != nil {
+				t.Fatal(err)
+			}
+			if tt.version != "1" {
+				database.Migration.Version = tt.version
+				if _, err := s.UpdateEnvironment(ctx, &pb.UpdateEnvironmentRequest{Id: env.GetId(), Spec: spec}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if _, err := s.RestartEnvironment(ctx, &pb.RestartEnvironmentRequest{Id: env.GetId()}); err != nil {
+				t.Fatal(err)
+			}
+
+			runs := 0
+			for _, call := range runtime.Calls() {
+				if call == "once "+env.GetId()+"-job-migration" {
+					runs++
+				}
+			}
+			if runs != tt.want {
+				t.Errorf("migration ran %d times, want %d", runs, tt.want)
+			}
+			stored, _ := s.store.Get(env.GetId())
+			if steps := stored.GetDatabaseSteps(); len(steps) != 1 || steps[0].GetName() != tt.version || !steps[0].GetSucceeded() {
+				t.Errorf("database steps = %v, want one succeeded migration %s", steps, tt.version)
+			}
+		})
+	}
+}
+
+func TestFailedMigrationRunsAgain(t *testing.T) {
+	runtime := &container.Fake{}
+	s := newTestService(t, runtime)
+	spec := testSpec("retry")
+	spec.ApplicationStack.Database.Migration = &pb.DatabaseMigration{Image: "migrate:1", Version: "1"}
+	spec.ApplicationStack.Database.Container.Volumes = []*pb.VolumeMount{{Name: "retry-data", MountPath: "/var/lib/postgresql/data"}}
+	env := createEnvironment(t, s, spec)
+
+	ctx := context.Background()
+	runtime.OnRunOnce = func(_, _ string, _ container.Spec, _ io.Writer) (int, error) {
+		return 3, nil
+	}
+	if _, err := s.StartEnvironment(ctx, &pb.StartEnvironmentRequest{Id: env.GetId()}); err == nil {
+		t.Fatal("StartEnvironment() succeeded despite the failing migration")
+	}
+	if ids := runtime.RunningIDs(testNamespace); len(ids) != 0 {
+		t.Errorf("containers left running after failed migration: %q", ids)
+	}
+
+	runtime.OnRunOnce = nil
+	if _, err := s.StartEnvironment(ctx, &pb.StartEnvironmentRequest{Id: env.GetId()}); err != nil {
+		t.Fatal(err)
+	}
+	stored, _ := s.store.Get(env.GetId())
+	if steps := stored.GetDatabaseSteps(); len(steps) != 1 || !steps[0].GetSucceeded() {
+		t.Errorf("database steps = %v, want the migration to have succeeded on retry", steps)
+	}
+}
+
+func TestReadScript(t *testing.T) {
+	root := t.TempDir()
+	scripts := filepath.Join(root, "scripts")
+	if err := os.MkdirAll(filepath.Join(scripts, "webapp"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(scripts, "webapp", "seed.sql"), []byte("SELECT 1;"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	outside := filepath.Join(root, "outside.sql")
+	if err := os.WriteFile(outside, []byte("SELECT 2;"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(outside, filepath.Join(scripts, "escape.sql")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join(scripts, "webapp", "seed.sql"), filepath.Join(scripts, "alias.sql")); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		scriptsDir string
+		hostPath   string
+		want       string
+		wantErr    string
+	}{
+		{name: "file in the directory", scriptsDir: scripts, hostPath: "webapp/seed.sql", want: "SELECT 1;"},
+		{name: "uncleaned path inside", scriptsDir: scripts, hostPath: "webapp/../webapp/./seed.sql", want: "SELECT 1;"},
+		{name: "link inside the directory", scriptsDir: scripts, hostPath: "alias.sql", want: "SELECT 1;"},
+		{name: "parent directory", scriptsDir: scripts, hostPath: "../outside.sql", wantErr: "outside the scripts directory"},
+		{name: "absolute path", scriptsDir: scripts, hostPath: outside, wantErr: "outside the scripts directory"},
+		{name: "link out of the directory", scriptsDir: scripts, hostPath: "escape.sql", wantErr: "outside the scripts directory"},
+		{name: "missing file", scriptsDir: scripts, hostPath: "missing.sql", wantErr: "failed to read missing.sql"},
+		{name: "no scripts directory", hostPath: "webapp/seed.sql", wantErr: "disabled"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &SchedulerService{scriptsDir: tt.scriptsDir}
+			data, err := s.readScript(tt.hostPath)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("readScript(%q) error = %v, want %q", tt.hostPath, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("readScript(%q) error = %v", tt.hostPath, err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("readScript(%q) = %q, want %q", tt.hostPath, data, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateScriptsHostPath(t *testing.T) {
+	tests := []struct {
+		hostPath string
+		valid    bool
+	}{
+		{hostPath: "seed.sql", valid: true},
+		{hostPath: "webapp/seed.sql", valid: true},
+		{hostPath: "/opt/webapp/seed.sql"},
+		{hostPath: "../seed.sql"},
+		{hostPath: "webapp/../../seed.sql"},
+	}
+	for _, tt := range tests {
+		err := validateScripts("database.seed_data", []*pb.DatabaseScript{{Name: "seed", HostPath: tt.hostPath}})
+		if (err == nil) != tt.valid {
+			t.Errorf("validateScripts(%q) error = %v, want valid %v", tt.hostPath, err, tt.valid)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/netip"
+	"strings"
+	"time"
+
+	"scheduler/internal/container"
+	"scheduler/internal/network"
+	pb "scheduler/proto/gen"
+)
+
+// maxJobOutput bounds how much output of one-shot containers and commands is
+// recorded
+const maxJobOutput = 4096
+
+// runJob runs a one-shot container on the network of an environment and
+// waits up to timeout for it to exit. The end of its output is returned
+// whether it succeeds or not.
+func (s *SchedulerService) runJob(ctx context.Context, env *pb.Environment, id string, spec container.Spec, timeout time.Duration) (string, error) {
+	address, err := jobAddress(env, id)
+	if err != nil {
+		return "", err
+	}
+	spec.IPAddress = address
+	if s.dns != nil {
+		spec.Nameserver = env.GetSpec().GetNetwork().GetGateway()
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	output := &tailBuffer{limit: maxJobOutput}
+	exitCode, err := s.runtime.RunOnce(ctx, s.namespaceFor(env), id, spec, output)
+	return jobResult(ctx, output, exitCode, err, timeout)
+}
+
+// runCommand runs a command inside a running container of an environment and
+// waits up to timeout for it to exit, like runJob
+func (s *SchedulerService) runCommand(ctx context.Context, env *pb.Environment, id string, opts container.ExecOptions, timeout time.Duration) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	output := &tailBuffer{limit: maxJobOutput}
+	opts.Stdout, opts.Stderr = output, output
+	exitCode, err := s.runtime.Exec(ctx, s.namespaceFor(env), id, opts)
+	return jobResult(ctx, output, exitCode, err, timeout)
+}
+
+// jobResult turns the outcome of a job or command into its output and an
+// error for failures, timeouts included
+func jobResult(ctx context.Context, output *tailBuffer, exitCode int, err error, timeout time.Duration) (string, error) {
+	text := strings.TrimSpace(output.String())
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return text, fmt.Errorf("timed out after %s", timeout)
+	case err != nil:
+		return text, err
+	case exitCode != 0:
+		return text, fmt.Errorf("exit code %d", exitCode)
+	}
+	return text, nil
+}
+
+// jobAddress picks an address for a one-shot container that no container of
+// the environment uses. Jobs are short-lived, so the address is not recorded.
+func jobAddress(env *pb.Environment, id string) (string, error) {
+	subnet, err := netip.ParsePrefix(env.GetSpec().GetNetwork().GetSubnet())
+	if err != nil {
+		return "", fmt.Errorf("environment %s has invalid subnet: %v", env.GetId(), err)
+	}
+	gateway, err := netip.ParseAddr(env.GetSpec().GetNetwork().GetGateway())
+	if err != nil {
+		return "", fmt.Errorf("environment %s has invalid gateway: %v", env.GetId(), err)
+	}
+	previous := make(map[string]netip.Addr, len(env.GetContainers()))
+	ids := make([]string, 0, len(env.GetContainers())+1)
+	for _, instance := range env.GetContainers() {
+		if addr, err := netip.ParseAddr(instance.GetIpAddress()); err == nil {
+			previous[instance.GetId()] = addr
+			ids = append(ids, instance.GetId())
+		}
+	}
+	addresses, err := network.AssignAddresses(subnet, gateway, append(ids, id), previous)
+	if err != nil {
+		return "", err
+	}
+	return addresses[id].String(), nil
+}
+
+// tailBuffer keeps the last limit bytes written to it, where the errors of
+// failed commands usually are
+type tailBuffer struct {
+	bytes.Buffer
+	limit int
+}
+
+// Write implements io.Writer
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	b.Buffer.Write(p)
+	if excess := b.Len() - b.limit; excess > 0 {
+		b.Next(excess)
+	}
+	return len(p), nil
+}
@@ -0,0 +1,465 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"scheduler/internal/container"
+	"scheduler/internal/diff"
+	"scheduler/internal/stack"
+	pb "scheduler/proto/gen"
+)
+
+// stopTimeout is how long containers get to exit after being asked to stop
+// before they are killed
+const stopTimeout = 10 * time.Second
+
+// StartEnvironment starts the containers of an environment in stack order.
+// Environments that do not fit into the remaining host capacity wait in the
+// admission queue, or are refused when queueing is disabled.
+func (s *SchedulerService) StartEnvironment(ctx context.Context, req *pb.StartEnvironmentRequest) (*pb.StartEnvironmentResponse, error) {
+	unlock := s.lockEnvironment(req.GetId())
+	defer unlock()
+
+	env, err := s.getEnvironment(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	if env.GetStatus() != pb.EnvironmentStatus_ENVIRONMENT_STATUS_RUNNING && s.withQueuePosition(env).GetQueuePosition() == 0 {
+		if err := s.startEnvironment(ctx, env); err != nil {
+			return nil, err
+		}
+	}
+	return &pb.StartEnvironmentResponse{Environment: s.withQueuePosition(env)}, nil
+}
+
+// StopEnvironment stops and removes the containers of an environment in
+// reverse stack order. Forced stops kill containers right away. Queued
+// environments just leave the admission queue.
+func (s *SchedulerService) StopEnvironment(ctx context.Context, req *pb.StopEnvironmentRequest) (*pb.StopEnvironmentResponse, error) {
+	unlock := s.lockEnvironment(req.GetId())
+	defer unlock()
+
+	env, err := s.getEnvironment(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	queued, err := s.dequeue(env)
+	if err != nil {
+		return nil, err
+	}
+	if queued {
+		if err := s.saveStatus(env, pb.EnvironmentStatus_ENVIRONMENT_STATUS_STOPPED); err != nil {
+			return nil, err
+		}
+		return &pb.StopEnvironmentResponse{Environment: env}, nil
+	}
+
+	timeout := stopTimeout
+	if req.GetForce() {
+		timeout = 0
+	}
+	if err := s.stopContainers(ctx, env, timeout); err != nil {
+		return nil, err
+	}
+	return &pb.StopEnvironmentResponse{Environment: env}, nil
+}
+
+// RestartEnvironment stops the containers of an environment and starts them
+// again, picking up changes made to the specification in the meantime.
+// Queued environments keep waiting.
+func (s *SchedulerService) RestartEnvironment(ctx context.Context, req *pb.RestartEnvironmentRequest) (*pb.RestartEnvironmentResponse, error) {
+	unlock := s.lockEnvironment(req.GetId())
+	defer unlock()
+
+	env, err := s.getEnvironment(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	if s.withQueuePosition(env).GetQueuePosition() > 0 {
+		return &pb.RestartEnvironmentResponse{Environment: env}, nil
+	}
+	if err := s.restartEnvironment(ctx, env); err != nil {
+		return nil, err
+	}
+	return &pb.RestartEnvironmentResponse{Environment: s.withQueuePosition(env)}, nil
+}
+
+// restartEnvironment stops the containers of an environment and admits it
+// again. It must be called with the environment lock held.
+func (s *SchedulerService) restartEnvironment(ctx context.Context, env *pb.Environment) error {
+	// Check before stopping so that a grown specification that no longer
+	// fits leaves the environment running
+	s.mu.Lock()
+	err := s.checkCapacity(env)
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if err := s.stopContainers(ctx, env, stopTimeout); err != nil {
+		return err
+	}
+	return s.startEnvironment(ctx, env)
+}
+
+// startContainers runs every container of an environment and records the
+// outcome. The preDeploy hooks run first, each container's postStart hook
+// runs once it started and the postDeploy hooks run last. When a container
+// or hook fails, the containers started before are removed again and the
+// environment is marked failed.
+func (s *SchedulerService) startContainers(ctx context.Context, env *pb.Environment) error {
+	members := stack.Members(env.GetSpec().GetApplicationStack())
+	specs := make([]container.Spec, len(members))
+	for i, member := range members {
+		spec, err := s.containerSpec(env, member)
+		if err != nil {
+			return err
+		}
+		specs[i] = spec
+	}
+
+	instances := make(map[string]*pb.ContainerInstance, len(env.GetContainers()))
+	for _, instance := range env.GetContainers() {
+		instances[instance.GetId()] = instance
+	}
+
+	var started []string
+	// fail undoes the start after the container id, if any, or a hook failed
+	fail := func(id string, err error) error {
+		if errors.Is(err, container.ErrUnavailable) {
+			return runtimeError(err)
+		}
+		for j := len(started) - 1; j >= 0; j-- {
+			if removeErr := s.runtime.Remove(ctx, s.namespaceFor(env), started[j], 0); removeErr != nil {
+				log.Printf("Failed to remove container %s after failed start: %v", started[j], removeErr)
+			}
+		}
+		for _, instance := range env.GetContainers() {
+			instance.Status = pb.ContainerStatus_CONTAINER_STATUS_STOPPED
+			instance.StartedAt = nil
+		}
+		if instance, ok := instances[id]; ok {
+			instance.Status = pb.ContainerStatus_CONTAINER_STATUS_FAILED
+		}
+		s.removeDNS(env)
+		s.saveStatus(env, pb.EnvironmentStatus_ENVIRONMENT_STATUS_FAILED)
+		return runtimeError(err)
+	}
+
+	hooks := env.GetSpec().GetHooks()
+	if err := s.runDeployHooks(ctx, env, pb.HookPhase_HOOK_PHASE_PRE_DEPLOY, hooks.GetPreDeploy()); err != nil {
+		return fail("", err)
+	}
+	for i, member := range members {
+		id := containerID(env.GetId(), member)
+		if err := s.runtime.Run(ctx, s.namespaceFor(env), id, specs[i]); err != nil {
+			return fail(id, fmt.Errorf("failed to start %s: %w", member, err))
+		}
+		started = append(started, id)
+		// The bridge exists once a container runs, so the resolver can bind
+		// to the gateway before hooks and later members look up names
+		s.syncDNS(env)
+		if err := s.runExecHook(ctx, env, member, pb.HookPhase_HOOK_PHASE_POST_START, member.Container.GetLifecycle().GetPostStart()); err != nil {
+			return fail(id, fmt.Errorf("failed to start %s: %w", member, err))
+		}
+		if !member.Additional && member.Alias == stack.RoleDatabase {
+			// The database is initialized before the backend starts
+			if err := s.initializeDatabase(ctx, env); err != nil {
+				return fail(id, fmt.Errorf("failed to start %s: %w", member, err))
+			}
+		}
+	}
+	if err := s.runDeployHooks(ctx, env, pb.HookPhase_HOOK_PHASE_POST_DEPLOY, hooks.GetPostDeploy()); err != nil {
+		return fail("", err)
+	}
+
+	now := timestamppb.Now()
+	for _, instance := range env.GetContainers() {
+		instance.Status = pb.ContainerStatus_CONTAINER_STATUS_RUNNING
+		instance.StartedAt = now
+	}
+	return s.saveStatus(env, pb.EnvironmentStatus_ENVIRONMENT_STATUS_RUNNING)
+}
+
+// reconcileContainers brings the containers of a running environment in line
+// with the specification that replaced previous, the way
+// PlanEnvironmentUpdate reports it. Removed and recreated containers are
+// removed in reverse stack order after their preStop hook, then created and
+// recreated ones start in stack order with their postStart hook, around the
+// deploy hooks. Unchanged containers keep running. When a step fails the
+// environment is marked failed with the containers it has left; restarting
+// or stopping it cleans up. It must be called with the environment lock held
+// and without s.mu.
+func (s *SchedulerService) reconcileContainers(ctx context.Context, env *pb.Environment, previous *pb.EnvironmentSpecification) error {
+	if env.GetStatus() != pb.EnvironmentStatus_ENVIRONMENT_STATUS_RUNNING {
+		return nil
+	}
+	actions := make(map[string]pb.ContainerAction)
+	changed := false
+	for _, plan := range planContainers(previous, env.GetSpec(), diff.Messages(previous, env.GetSpec())) {
+		actions[plan.GetAlias()] = plan.GetAction()
+		changed = changed || plan.GetAction() != pb.ContainerAction_CONTAINER_ACTION_UNCHANGED
+	}
+	if !changed {
+		return nil
+	}
+
+	members := stack.Members(env.GetSpec().GetApplicationStack())
+	specs := make([]container.Spec, len(members))
+	for i, member := range members {
+		spec, err := s.containerSpec(env, member)
+		if err != nil {
+			return err
+		}
+		specs[i] = spec
+	}
+	instances := make(map[string]*pb.ContainerInstance, len(env.GetContainers()))
+	for _, instance := range env.GetContainers() {
+		instances[instance.GetId()] = instance
+	}
+	if err := s.saveStatus(env, pb.EnvironmentStatus_ENVIRONMENT_STATUS_UPDATING); err != nil {
+		return err
+	}
+
+	fail := func(id string, err error) error {
+		if instance, ok := instances[id]; ok {
+			instance.Status = pb.ContainerStatus_CONTAINER_STATUS_FAILED
+			instance.StartedAt = nil
+		}
+		s.saveStatus(env, pb.EnvironmentStatus_ENVIRONMENT_STATUS_FAILED)
+		return runtimeError(err)
+	}
+
+	hooks := env.GetSpec().GetHooks()
+	if err := s.runDeployHooks(ctx, env, pb.HookPhase_HOOK_PHASE_PRE_DEPLOY, hooks.GetPreDeploy()); err != nil {
+		return fail("", err)
+	}
+
+	// The previous members still describe the containers that run, preStop
+	// hooks included
+	current := stack.Members(previous.GetApplicationStack())
+	for i := len(current) - 1; i >= 0; i-- {
+		member := current[i]
+		switch actions[member.Alias] {
+		case pb.ContainerAction_CONTAINER_ACTION_RECREATE, pb.ContainerAction_CONTAINER_ACTION_REMOVE:
+		default:
+			continue
+		}
+		id := containerID(env.GetId(), member)
+		if err := s.runExecHook(ctx, env, member, pb.HookPhase_HOOK_PHASE_PRE_STOP, member.Container.GetLifecycle().GetPreStop()); err != nil {
+			if errors.Is(err, container.ErrUnavailable) {
+				return fail("", err)
+			}
+			log.Printf("Failed to run preStop hook of %s: %v", id, err)
+		}
+		if err := s.runtime.Remove(ctx, s.namespaceFor(env), id, stopTimeout); err != nil {
+			return fail(id, fmt.Errorf("failed to stop %s: %w", member, err))
+		}
+		if instance, ok := instances[id]; ok {
+			instance.Status = pb.ContainerStatus_CONTAINER_STATUS_STOPPED
+			instance.StartedAt = nil
+		}
+	}
+
+	for i, member := range members {
+		switch actions[member.Alias] {
+		case pb.ContainerAction_CONTAINER_ACTION_CREATE, pb.ContainerAction_CONTAINER_ACTION_RECREATE:
+		default:
+			continue
+		}
+		id := containerID(env.GetId(), member)
+		if err := s.runtime.Run(ctx, s.namespaceFor(env), id, specs[i]); err != nil {
+			return fail(id, fmt.Errorf("failed to start %s: %w", member, err))
+		}
+		if instance, ok := instances[id]; ok {
+			instance.Status = pb.ContainerStatus_CONTAINER_STATUS_RUNNING
+			instance.StartedAt = timestamppb.Now()
+		}
+		s.syncDNS(env)
+		if err := s.runExecHook(ctx, env, member, pb.HookPhase_HOOK_PHASE_POST_START, member.Container.GetLifecycle().GetPostStart()); err != nil {
+			return fail(id, fmt.Errorf("failed to start %s: %w", member, err))
+		}
+		if !member.Additional && member.Alias == stack.RoleDatabase {
+			if err := s.initializeDatabase(ctx, env); err != nil {
+				return fail(id, fmt.Errorf("failed to start %s: %w", member, err))
+			}
+		}
+	}
+	if err := s.runDeployHooks(ctx, env, pb.HookPhase_HOOK_PHASE_POST_DEPLOY, hooks.GetPostDeploy()); err != nil {
+		return fail("", err)
+	}
+	s.syncDNS(env)
+	return s.saveStatus(env, pb.EnvironmentStatus_ENVIRONMENT_STATUS_RUNNING)
+}
+
+// stopContainers removes every container of an environment, continuing past
+// failures so that as much as possible is cleaned up. The preStop hook of a
+// running container runs before it is removed, unless the stop is forced.
+// The environment is stopping meanwhile, which keeps its capacity reserved.
+func (s *SchedulerService) stopContainers(ctx context.Context, env *pb.Environment, timeout time.Duration) error {
+	if err := s.saveStatus(env, pb.EnvironmentStatus_ENVIRONMENT_STATUS_STOPPING); err != nil {
+		return err
+	}
+	instances := make(map[string]*pb.ContainerInstance, len(env.GetContainers()))
+	for _, instance := range env.GetContainers() {
+		instances[instance.GetId()] = instance
+	}
+
+	members := stack.Members(env.GetSpec().GetApplicationStack())
+	var failed error
+	for i := len(members) - 1; i >= 0; i-- {
+		id := containerID(env.GetId(), members[i])
+		instance := instances[id]
+		if timeout > 0 && instance.GetStatus() == pb.ContainerStatus_CONTAINER_STATUS_RUNNING {
+			err := s.runExecHook(ctx, env, members[i], pb.HookPhase_HOOK_PHASE_PRE_STOP, members[i].Container.GetLifecycle().GetPreStop())
+			if errors.Is(err, container.ErrUnavailable) {
+				return runtimeError(err)
+			}
+			if err != nil {
+				log.Printf("Failed to run preStop hook of %s: %v", id, err)
+				if failed == nil {
+					failed = fmt.Errorf("failed to stop %s: %w", members[i], err)
+				}
+			}
+		}
+		if err := s.runtime.Remove(ctx, s.namespaceFor(env), id, timeout); err != nil {
+			if errors.Is(err, container.ErrUnavailable) {
+				return runtimeError(err)
+			}
+			log.Printf("Failed to remove container %s: %v", id, err)
+			if failed == nil {
+				failed = fmt.Errorf("failed to stop %s: %w", members[i], err)
+			}
+			continue
+		}
+		if instance != nil {
+			// Removed containers are stopped even when their preStop hook failed
+			instance.Status = pb.ContainerStatus_CONTAINER_STATUS_STOPPED
+			instance.StartedAt = nil
+		}
+	}
+	s.removeDNS(env)
+	if failed != nil {
+		s.saveStatus(env, pb.EnvironmentStatus_ENVIRONMENT_STATUS_FAILED)
+		return runtimeError(failed)
+	}
+	return s.saveStatus(env, pb.EnvironmentStatus_ENVIRONMENT_STATUS_STOPPED)
+}
+
+// saveStatus records a new environment status in the store
+func (s *SchedulerService) saveStatus(env *pb.Environment, envStatus pb.EnvironmentStatus) error {
+	env.Status = envStatus
+	env.UpdatedAt = timestamppb.Now()
+	if err := s.store.Update(env); err != nil {
+		return status.Errorf(codes.Internal, "failed to store environment: %v", err)
+	}
+	return nil
+}
+
+// containerSpec builds the runtime specification of a stack member's
+// container. Secret references are resolved here, so secret values only
+// ever exist in memory and inside the running container.
+func (s *SchedulerService) containerSpec(env *pb.Environment, member stack.Member) (container.Spec, error) {
+	config := member.Container
+	spec := container.Spec{
+		Image:       config.GetImage(),
+		Command:     config.GetCommand(),
+		Args:        config.GetArgs(),
+		Env:         make(map[string]string),
+		MemoryBytes: config.GetResources().GetMemoryMb() << 20,
+		CPUCores:    config.GetResources().GetCpuCores(),
+	}
+
+	id := containerID(env.GetId(), member)
+	for _, instance := range env.GetContainers() {
+		if instance.GetId() == id {
+			spec.IPAddress = instance.GetIpAddress()
+		}
+	}
+	if s.dns != nil {
+		spec.Nameserver = env.GetSpec().GetNetwork().GetGateway()
+	}
+
+	// Derived and backend settings are applied first so explicit environment
+	// variables win
+	if !member.Additional && member.Alias == stack.RoleDatabase {
+		variables, err := s.postgresEnv(env)
+		if err != nil {
+			return container.Spec{}, err
+		}
+		for key, value := range variables {
+			spec.Env[key] = value
+		}
+	}
+	if !member.Additional && member.Alias == stack.RoleBackend {
+		variables, err := s.databaseEnv(env)
+		if err != nil {
+			return container.Spec{}, err
+		}
+		for key, value := range variables {
+			spec.Env[key] = value
+		}
+
+		backend := env.GetSpec().GetApplicationStack().GetBackend()
+		if backend.GetDatabaseConnectionString() != "" {
+			resolved, err := s.resolveSecret(env, backend.GetDatabaseConnectionString())
+			if err != nil {
+				return container.Spec{}, err
+			}
+			spec.Env["DATABASE_URL"] = resolved
+		}
+		for key, value := range backend.GetApiKeys() {
+			resolved, err := s.resolveSecret(env, value)
+			if err != nil {
+				return container.Spec{}, err
+			}
+			spec.Env[key] = resolved
+		}
+	}
+	for key, value := range config.GetEnvironmentVariables() {
+		resolved, err := s.resolveSecret(env, value)
+		if err != nil {
+			return container.Spec{}, err
+		}
+		spec.Env[key] = resolved
+	}
+
+	for _, mount := range config.GetVolumes() {
+		source := mount.GetHostPath()
+		if source == "" {
+			source = s.volumes.DataPath(mount.GetName())
+		}
+		spec.Mounts = append(spec.Mounts, container.Mount{
+			Source:   source,
+			Target:   mount.GetMountPath(),
+			ReadOnly: mount.GetReadOnly(),
+		})
+	}
+
+	for _, file := range config.GetSecretFiles() {
+		value, err := s.secretValue(env, file.GetSecret())
+		if err != nil {
+			return container.Spec{}, err
+		}
+		spec.Files = append(spec.Files, container.File{Path: file.GetPath(), Data: value})
+	}
+
+	return spec, nil
+}
+
+// runtimeError maps container runtime errors onto gRPC status codes
+func runtimeError(err error) error {
+	switch {
+	case errors.Is(err, container.ErrUnavailable):
+		return status.Errorf(codes.FailedPrecondition, "%v", err)
+	default:
+		return status.Errorf(codes.Internal, "%v", err)
+	}
+}
@@ -0,0 +1,359 @@
+package service
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+
+	"scheduler/internal/container"
+	pb "scheduler/proto/gen"
+)
+
+func TestLifecycleOrdering(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    func(*pb.EnvironmentSpecification)
+		actions func(ctx context.Context, s *SchedulerService, id string) error
+		want    []string
+		status  pb.EnvironmentStatus
+	}{
+		{
+			name: "start runs the stack in order",
+			actions: func(ctx context.Context, s *SchedulerService, id string) error {
+				_, err := s.StartEnvironment(ctx, &pb.StartEnvironmentRequest{Id: id})
+				return err
+			},
+			want:   []string{"run {id}-database", "run {id}-backend", "run {id}-frontend"},
+			status: pb.EnvironmentStatus_ENVIRONMENT_STATUS_RUNNING,
+		},
+		{
+			name: "stop removes in reverse order",
+			actions: func(ctx context.Context, s *SchedulerService, id string) error {
+				if _, err := s.StartEnvironment(ctx, &pb.StartEnvironmentRequest{Id: id}); err != nil {
+					return err
+				}
+				_, err := s.StopEnvironment(ctx, &pb.StopEnvironmentRequest{Id: id})
+				return err
+			},
+			want: []string{
+				"run {id}-database", "run {id}-backend", "run {id}-frontend",
+				"remove {id}-frontend", "remove {id}-backend", "remove {id}-database",
+			},
+			status: pb.EnvironmentStatus_ENVIRONMENT_STATUS_STOPPED,
+		},
+		{
+			name: "restart stops before starting again",
+			actions: func(ctx context.Context, s *SchedulerService, id string) error {
+				if _, err := s.StartEnvironment(ctx, &pb.StartEnvironmentRequest{Id: id}); err != nil {
+					return err
+				}
+				_, err := s.RestartEnvironment(ctx, &pb.RestartEnvironmentRequest{Id: id})
+				return err
+			},
+			want: []string{
+				"run {id}-database", "run {id}-backend", "run {id}-frontend",
+				"remove {id}-frontend", "remove {id}-backend", "remove {id}-database",
+				"run {id}-database", "run {id}-backend", "run {id}-frontend",
+			},
+			status: pb.EnvironmentStatus_ENVIRONMENT_STATUS_RUNNING,
+		},
+		{
+			name: "hooks run around the containers",
+			spec: func(spec *pb.EnvironmentSpecification) {
+				backend := spec.ApplicationStack.Backend.Container
+				backend.Lifecycle = &pb.LifecycleHooks{
+					PostStart: &pb.ExecHook{Command: []string{"warm"}},
+					PreStop:   &pb.ExecHook{Command: []string{"drain"}},
+				}
+				spec.Hooks = &pb.DeployHooks{
+					PreDeploy:  []*pb.HookContainer{{Name: "check", Image: "tools:1"}},
+					PostDeploy: []*pb.HookContainer{{Name: "notify", Image: "tools:1"}},
+				}
+			},
+			actions: func(ctx context.Context, s *SchedulerService, id string) error {
+				if _, err := s.StartEnvironment(ctx, &pb.StartEnvironmentRequest{Id: id}); err != nil {
+					return err
+				}
+				_, err := s.StopEnvironment(ctx, &pb.StopEnvironmentRequest{Id: id})
+				return err
+			},
+			want: []string{
+				"once {id}-job-check",
+				"run {id}-database", "run {id}-backend", "exec {id}-backend warm", "run {id}-frontend",
+				"once {id}-job-notify",
+				"remove {id}-frontend", "exec {id}-backend drain", "remove {id}-backend", "remove {id}-database",
+			},
+			status: pb.EnvironmentStatus_ENVIRONMENT_STATUS_STOPPED,
+		},
+		{
+			name: "forced stops skip preStop hooks",
+			spec: func(spec *pb.EnvironmentSpecification) {
+				spec.ApplicationStack.Backend.Container.Lifecycle = &pb.LifecycleHooks{
+					PreStop: &pb.ExecHook{Command: []string{"drain"}},
+				}
+			},
+			actions: func(ctx context.Context, s *SchedulerService, id string) error {
+				if _, err := s.StartEnvironment(ctx, &pb.StartEnvironmentRequest{Id: id}); err != nil {
+					return err
+				}
+				_, err := s.StopEnvironment(ctx, &pb.StopEnvironmentRequest{Id: id, Force: true})
+				return err
+			},
+			want: []string{
+				"run {id}-database", "run {id}-backend", "run {id}-frontend",
+				"remove {id}-frontend", "remove {id}-backend", "remove {id}-database",
+			},
+			status: pb.EnvironmentStatus_ENVIRONMENT_STATUS_STOPPED,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runtime := &container.Fake{}
+			s := newTestService(t, runtime)
+			spec := testSpec("ordering")
+			if tt.spec != nil {
+				tt.spec(spec)
+			}
+			env := createEnvironment(t, s, spec)
+
+			ctx := context.Background()
+			if err := tt.actions(ctx, s, env.GetId()); err != nil {
+				t.Fatalf("actions error = %v", err)
+			}
+
+			want := make([]string, len(tt.want))
+			for i, call := range tt.want {
+				want[i] = strings.ReplaceAll(call, "{id}", env.GetId())
+			}
+			if got := runtime.Calls(); !reflect.DeepEqual(got, want) {
+				t.Errorf("calls = %q, want %q", got, want)
+			}
+			stored, _ := s.store.Get(env.GetId())
+			if stored.GetStatus() != tt.status {
+				t.Errorf("status = %v, want %v", stored.GetStatus(), tt.status)
+			}
+		})
+	}
+}
+
+func TestStartHookFailure(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    func(*pb.EnvironmentSpecification)
+		runtime func(*container.Fake)
+		// calls that must not happen once the hook failed
+		notCalled string
+	}{
+		{
+			name: "preDeploy hook",
+			spec: func(spec *pb.EnvironmentSpecification) {
+				spec.Hooks = &pb.DeployHooks{PreDeploy: []*pb.HookContainer{{Name: "check", Image: "tools:1"}}}
+			},
+			runtime: func(f *container.Fake) {
+				f.OnRunOnce = func(_, _ string, _ container.Spec, output io.Writer) (int, error) {
+					io.WriteString(output, "check failed")
+					return 1, nil
+				}
+			},
+			notCalled: "run {id}-database",
+		},
+		{
+			name: "postStart hook",
+			spec: func(spec *pb.EnvironmentSpecification) {
+				spec.ApplicationStack.Backend.Container.Lifecycle = &pb.LifecycleHooks{
+					PostStart: &pb.ExecHook{Command: []string{"warm"}},
+				}
+			},
+			runtime: func(f *container.Fake) {
+				f.OnExec = func(_, id string, _ container.ExecOptions) (int, error) {
+					if strings.HasSuffix(id, "-backend") {
+						return 0, container.ErrFake
+					}
+					return 0, nil
+				}
+			},
+			notCalled: "run {id}-frontend",
+		},
+		{
+			name: "postDeploy hook",
+			spec: func(spec *pb.EnvironmentSpecification) {
+				spec.Hooks = &pb.DeployHooks{PostDeploy: []*pb.HookContainer{{Name: "notify", Image: "tools:1"}}}
+			},
+			runtime: func(f *container.Fake) {
+				f.OnRunOnce = func(_, _ string, _ container.Spec, _ io.Writer) (int, error) {
+					return 0, container.ErrFake
+				}
+			},
+		},
+		{
+			name: "container start",
+			runtime: func(f *container.Fake) {
+				f.OnRun = func(_, id string, _ container.Spec) error {
+					if strings.HasSuffix(id, "-backend") {
+						return container.ErrFake
+					}
+					return nil
+				}
+			},
+			notCalled: "run {id}-frontend",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runtime := &container.Fake{}
+			tt.runtime(runtime)
+			s := newTestService(t, runtime)
+			spec := testSpec("hooks")
+			if tt.spec != nil {
+				tt.spec(spec)
+			}
+			env := createEnvironment(t, s, spec)
+
+			_, err := s.StartEnvironment(context.Background(), &pb.StartEnvironmentRequest{Id: env.GetId()})
+			if err == nil {
+				t.Fatal("StartEnvironment() succeeded, want an error")
+			}
+			if ids := runtime.RunningIDs(testNamespace); len(ids) != 0 {
+				t.Errorf("containers left running after failed start: %q", ids)
+			}
+			notCalled := strings.ReplaceAll(tt.notCalled, "{id}", env.GetId())
+			for _, call := range runtime.Calls() {
+				if notCalled != "" && call == notCalled {
+					t.Errorf("%q ran after the failure", call)
+				}
+			}
+			stored, _ := s.store.Get(env.GetId())
+			if stored.GetStatus() != pb.EnvironmentStatus_ENVIRONMENT_STATUS_FAILED {
+				t.Errorf("status = %v, want FAILED", stored.GetStatus())
+			}
+		})
+	}
+}
+
+func TestPreStopFailureStillRemoves(t *testing.T) {
+	runtime := &container.Fake{}
+	s := newTestService(t, runtime)
+	spec := testSpec("prestop")
+	spec.ApplicationStack.Backend.Container.Lifecycle = &pb.LifecycleHooks{
+		PreStop: &pb.ExecHook{Command: []string{"drain"}},
+	}
+	env := createEnvironment(t, s, spec)
+
+	ctx := context.Background()
+	if _, err := s.StartEnvironment(ctx, &pb.StartEnvironmentRequest{Id: env.GetId()}); err != nil {
+		t.Fatal(err)
+	}
+	runtime.OnExec = func(_, _ string, _ container.ExecOptions) (int, error) {
+		return 0, container.ErrFake
+	}
+	_, err := s.StopEnvironment(ctx, &pb.StopEnvironmentRequest{Id: env.GetId()})
+	if err == nil {
+		t.Fatal("StopEnvironment() succeeded, want the preStop failure")
+	}
+	if ids := runtime.RunningIDs(testNamespace); len(ids) != 0 {
+		t.Errorf("containers left running: %q", ids)
+	}
+}
+
+func TestUpdateReconcilesRunningEnvironment(t *testing.T) {
+	tests := []struct {
+		name   string
+		update func(*pb.EnvironmentSpecification)
+		// running starts the environment before the update
+		running bool
+		want    []string
+	}{
+		{
+			name:    "changed container is recreated",
+			running: true,
+			update: func(spec *pb.EnvironmentSpecification) {
+				spec.ApplicationStack.Backend.Container.Image = "backend:2"
+			},
+			want: []string{"remove {id}-backend", "run {id}-backend"},
+		},
+		{
+			name:    "added service is created",
+			running: true,
+			update: func(spec *pb.EnvironmentSpecification) {
+				spec.ApplicationStack.AdditionalServices = map[string]*pb.ContainerConfig{"cache": {Image: "redis:7"}}
+			},
+			want: []string{"run {id}-cache"},
+		},
+		{
+			name:    "dropped container is removed",
+			running: true,
+			update: func(spec *pb.EnvironmentSpecification) {
+				spec.ApplicationStack.Frontend = nil
+			},
+			want: []string{"remove {id}-frontend"},
+		},
+		{
+			name:    "unchanged containers are left alone",
+			running: true,
+			update: func(spec *pb.EnvironmentSpecification) {
+				spec.Description = "only metadata"
+			},
+		},
+		{
+			name: "stopped environments wait for their start",
+			update: func(spec *pb.EnvironmentSpecification) {
+				spec.ApplicationStack.Backend.Container.Image = "backend:2"
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runtime := &container.Fake{}
+			s := newTestService(t, runtime)
+			spec := testSpec("reconcile")
+			env := createEnvironment(t, s, spec)
+
+			ctx := context.Background()
+			if tt.running {
+				if _, err := s.StartEnvironment(ctx, &pb.StartEnvironmentRequest{Id: env.GetId()}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			before := len(runtime.Calls())
+
+			updated := proto.Clone(spec).(*pb.EnvironmentSpecification)
+			tt.update(updated)
+			plan, err := s.PlanEnvironmentUpdate(ctx, &pb.PlanEnvironmentUpdateRequest{Id: env.GetId(), Spec: updated})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := s.UpdateEnvironment(ctx, &pb.UpdateEnvironmentRequest{Id: env.GetId(), Spec: updated}); err != nil {
+				t.Fatal(err)
+			}
+
+			var want []string
+			for _, call := range tt.want {
+				want = append(want, strings.ReplaceAll(call, "{id}", env.GetId()))
+			}
+			if got := runtime.Calls()[before:]; !reflect.DeepEqual(got, want) && len(got)+len(want) > 0 {
+				t.Errorf("calls = %q, want %q", got, want)
+			}
+			if !tt.running {
+				return
+			}
+			for _, container := range plan.GetContainers() {
+				id := env.GetId() + "-" + container.GetAlias()
+				_, running := runtime.Running(testNamespace, id)
+				if wantRunning := container.GetAction() != pb.ContainerAction_CONTAINER_ACTION_REMOVE; running != wantRunning {
+					t.Errorf("%s running = %v after %v, want %v", id, running, container.GetAction(), wantRunning)
+				}
+			}
+			stored, _ := s.store.Get(env.GetId())
+			if stored.GetStatus() != pb.EnvironmentStatus_ENVIRONMENT_STATUS_RUNNING {
+				t.Errorf("status = %v, want RUNNING", stored.GetStatus())
+			}
+		})
+	}
+}
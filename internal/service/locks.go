@@ -0,0 +1,81 @@
+package service
+
+import (
+	"sort"
+	"sync"
+)
+
+// environmentLocks holds one mutex per environment. Lifecycle operations on
+// an environment hold its lock for their whole duration, hooks, migrations
+// and readiness waits included, so that they neither interleave with nor
+// block operations on other environments. Environment locks are always
+// taken before s.mu, never while holding it, except through
+// tryLockEnvironment which does not wait.
+type environmentLocks struct {
+	mu    sync.Mutex
+	locks map[string]*sync.Mutex
+}
+
+// get returns the mutex of an environment, creating it on first use
+func (l *environmentLocks) get(id string) *sync.Mutex {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.locks == nil {
+		l.locks = make(map[string]*sync.Mutex)
+	}
+	lock, ok := l.locks[id]
+	if !ok {
+		lock = &sync.Mutex{}
+		l.locks[id] = lock
+	}
+	return lock
+}
+
+// forget drops the mutex of a deleted environment. Callers still waiting
+// for it find the environment gone once they get it.
+func (l *environmentLocks) forget(id string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.locks, id)
+}
+
+// lockEnvironment waits for the lock of an environment. The returned
+// function releases it and wakes the admission queue, since the operation
+// may have freed capacity or left a queued environment free to start.
+func (s *SchedulerService) lockEnvironment(id string) func() {
+	lock := s.envLocks.get(id)
+	lock.Lock()
+	return func() {
+		lock.Unlock()
+		s.wakeQueue()
+	}
+}
+
+// tryLockEnvironment takes the lock of an environment only if nobody holds
+// it, which makes it safe to call with s.mu held
+func (s *SchedulerService) tryLockEnvironment(id string) (func(), bool) {
+	lock := s.envLocks.get(id)
+	if !lock.TryLock() {
+		return nil, false
+	}
+	return lock.Unlock, true
+}
+
+// lockEnvironments waits for the locks of several environments, taken in
+// the order of their IDs so that two callers cannot deadlock. The returned
+// function releases them all.
+func (s *SchedulerService) lockEnvironments(ids []string) func() {
+	sorted := append([]string(nil), ids...)
+	sort.Strings(sorted)
+	unlocks := make([]func(), len(sorted))
+	for i, id := range sorted {
+		unlocks[i] = s.lockEnvironment(id)
+	}
+	return func() {
+		for i := len(unlocks) - 1; i >= 0; i-- {
+			unlocks[i]()
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"sync"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"scheduler/internal/container"
+	"scheduler/internal/stack"
+	pb "scheduler/proto/gen"
+)
+
+// GetEnvironmentLogs streams the log lines of the containers of an
+// environment, or of the one named in the request, in stack order. Followed
+// logs interleave the containers as they write and run until the caller
+// cancels. Lines written to stderr have the error level, the others info.
+// Containers are deleted when they stop, and their logs with them.
+func (s *SchedulerService) GetEnvironmentLogs(req *pb.GetEnvironmentLogsRequest, stream pb.SchedulerService_GetEnvironmentLogsServer) error {
+	ctx := stream.Context()
+	if req.GetTailLines() < 0 {
+		return status.Errorf(codes.InvalidArgument, "tail_lines must not be negative")
+	}
+	env, err := s.getEnvironment(ctx, req.GetId())
+	if err != nil {
+		return err
+	}
+
+	var members []stack.Member
+	for _, member := range stack.Members(env.GetSpec().GetApplicationStack()) {
+		if req.GetContainerName() == "" || req.GetContainerName() == member.Name() || req.GetContainerName() == member.Alias {
+			members = append(members, member)
+		}
+	}
+	if len(members) == 0 {
+		return status.Errorf(codes.NotFound, "environment %s has no container %s", env.GetId(), req.GetContainerName())
+	}
+
+	opts := container.LogOptions{Tail: int(req.GetTailLines()), Follow: req.GetFollow()}
+	if req.GetSince() != nil {
+		opts.Since = req.GetSince().AsTime()
+	}
+	// Streams must not be sent to concurrently
+	var sendMu sync.Mutex
+	logs := func(ctx context.Context, member stack.Member) error {
+		send := func(line container.LogLine) error {
+			entry := &pb.GetEnvironmentLogsResponse{
+				ContainerName: member.Name(),
+				Message:       line.Message,
+				Timestamp:     timestamppb.New(line.Time),
+				Level:         "info",
+			}
+			if line.Stderr {
+				entry.Level = "error"
+			}
+			sendMu.Lock()
+			defer sendMu.Unlock()
+			return stream.Send(entry)
+		}
+		err := s.runtime.Logs(ctx, s.namespaceFor(env), containerID(env.GetId(), member), opts, send)
+		if err != nil && ctx.Err() == nil {
+			return err
+		}
+		return nil
+	}
+
+	if !req.GetFollow() {
+		for _, member := range members {
+			if err := logs(ctx, member); err != nil {
+				return logsError(err)
+			}
+		}
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var wg sync.WaitGroup
+	errs := make(chan error, len(members))
+	for _, member := range members {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := logs(ctx, member); err != nil {
+				errs <- err
+				cancel()
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	if err, ok := <-errs; ok {
+		return logsError(err)
+	}
+	return nil
+}
+
+// logsError maps errors of reading or sending logs onto gRPC status codes
+func logsError(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	return runtimeError(err)
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"scheduler/internal/container"
+	pb "scheduler/proto/gen"
+)
+
+// logStream collects the entries GetEnvironmentLogs sends
+type logStream struct {
+	grpc.ServerStream
+	ctx     context.Context
+	entries []*pb.GetEnvironmentLogsResponse
+}
+
+func (s *logStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *logStream) Send(entry *pb.GetEnvironmentLogsResponse) error {
+	s.entries = append(s.entries, entry)
+	return nil
+}
+
+func TestGetEnvironmentLogs(t *testing.T) {
+	runtime := &container.Fake{}
+	s := newTestService(t, runtime)
+	env := createEnvironment(t, s, testSpec("logs"))
+
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	runtime.WriteLog(testNamespace, env.GetId()+"-database", container.LogLine{Time: start, Message: "ready"})
+	runtime.WriteLog(testNamespace, env.GetId()+"-backend", container.LogLine{Time: start.Add(time.Second), Message: "listening"})
+	runtime.WriteLog(testNamespace, env.GetId()+"-backend", container.LogLine{Time: start.Add(2 * time.Second), Message: "boom", Stderr: true})
+
+	tests := []struct {
+		name string
+		req  *pb.GetEnvironmentLogsRequest
+		want []string
+		code codes.Code
+	}{
+		{
+			name: "every container in stack order",
+			req:  &pb.GetEnvironmentLogsRequest{},
+			want: []string{"database info ready", "backend info listening", "backend error boom"},
+		},
+		{
+			name: "one container by alias",
+			req:  &pb.GetEnvironmentLogsRequest{ContainerName: "backend"},
+			want: []string{"backend info listening", "backend error boom"},
+		},
+		{
+			name: "tail",
+			req:  &pb.GetEnvironmentLogsRequest{ContainerName: "backend", TailLines: 1},
+			want: []string{"backend error boom"},
+		},
+		{
+			name: "unknown container",
+			req:  &pb.GetEnvironmentLogsRequest{ContainerName: "cache"},
+			code: codes.NotFound,
+		},
+		{
+			name: "negative tail",
+			req:  &pb.GetEnvironmentLogsRequest{TailLines: -1},
+			code: codes.InvalidArgument,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.req.Id = env.GetId()
+			stream := &logStream{ctx: context.Background()}
+			err := s.GetEnvironmentLogs(tt.req, stream)
+			if status.Code(err) != tt.code {
+				t.Fatalf("GetEnvironmentLogs() error = %v, want code %v", err, tt.code)
+			}
+			var got []string
+			for _, entry := range stream.entries {
+				got = append(got, entry.GetContainerName()+" "+entry.GetLevel()+" "+entry.GetMessage())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("entries = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFollowEnvironmentLogsStopsWithTheCaller(t *testing.T) {
+	runtime := &container.Fake{}
+	s := newTestService(t, runtime)
+	env := createEnvironment(t, s, testSpec("follow"))
+	runtime.WriteLog(testNamespace, env.GetId()+"-frontend", container.LogLine{Time: time.Now(), Message: "served"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := &logStream{ctx: ctx}
+	done := make(chan error, 1)
+	go func() {
+		done <- s.GetEnvironmentLogs(&pb.GetEnvironmentLogsRequest{Id: env.GetId(), Follow: true}, stream)
+	}()
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("GetEnvironmentLogs() error = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("followed logs kept running after the caller canceled")
+	}
+}
@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"net/netip"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"scheduler/internal/diff"
+	"scheduler/internal/manifest"
+	"scheduler/internal/network"
+	pb "scheduler/proto/gen"
+)
+
+// ApplyManifest creates the environment a manifest declares, or updates the
+// environment of the same name in its tenant through the regular update
+// path. Manifests that match the current specification change nothing, and
+// dry runs only report what would change.
+func (s *SchedulerService) ApplyManifest(ctx context.Context, req *pb.ApplyManifestRequest) (*pb.ApplyManifestResponse, error) {
+	manifests, err := manifest.Parse([]byte(req.GetManifest()))
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid manifest:\n%v", err)
+	}
+	if len(manifests) != 1 {
+		return nil, status.Errorf(codes.InvalidArgument, "expected a single manifest, found %d", len(manifests))
+	}
+	m := manifests[0]
+	if err := validateSpec(m.Spec); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid specification: %v", err)
+	}
+	owner, err := s.resolveTenant(ctx, m.Tenant)
+	if err != nil {
+		return nil, err
+	}
+
+	// An existing environment is locked before s.mu, and must still be the
+	// one of that name once both are held
+	existing := s.findEnvironment(owner.Name, m.Spec.GetName())
+	if existing != nil {
+		unlock := s.lockEnvironment(existing.GetId())
+		defer unlock()
+	}
+	// s.mu is released before the containers of an updated environment are
+	// reconciled
+	s.mu.Lock()
+	locked := true
+	defer func() {
+		if locked {
+			s.mu.Unlock()
+		}
+	}()
+
+	if current := s.findEnvironment(owner.Name, m.Spec.GetName()); current.GetId() != existing.GetId() {
+		return nil, status.Errorf(codes.Aborted, "environment %s changed while applying, try again", m.Spec.GetName())
+	}
+	if existing == nil {
+		if err := checkScope(ctx, owner.Name, m.Spec.GetLabels()); err != nil {
+			return nil, err
+		}
+		resp := &pb.ApplyManifestResponse{
+			Action:  pb.ManifestAction_MANIFEST_ACTION_CREATED,
+			Changes: diff.Messages((*pb.EnvironmentSpecification)(nil), m.Spec),
+		}
+		if req.GetDryRun() {
+			planned := &pb.Environment{Name: m.Spec.GetName(), Spec: m.Spec, Tenant: owner.Name}
+			if err := s.checkAdmissible(ctx, planned); err != nil {
+				return nil, err
+			}
+			resp.Environment = planned
+			return resp, nil
+		}
+		if resp.Environment, err = s.createEnvironment(ctx, owner, m.Spec, nil); err != nil {
+			return nil, err
+		}
+		return resp, nil
+	}
+
+	// The caller must be able to see the environment it replaces, and
+	// applySpec checks the labels it ends up with
+	env, err := s.getEnvironment(ctx, existing.GetId())
+	if err != nil {
+		return nil, err
+	}
+	spec := withAssignedNetwork(env.GetSpec(), m.Spec)
+	resp := &pb.ApplyManifestResponse{
+		Environment: s.withQueuePosition(env),
+		Action:      pb.ManifestAction_MANIFEST_ACTION_UPDATED,
+		Changes:     diff.Messages(env.GetSpec(), spec),
+	}
+	if len(resp.Changes) == 0 {
+		resp.Action = pb.ManifestAction_MANIFEST_ACTION_UNCHANGED
+		return resp, nil
+	}
+	if req.GetDryRun() {
+		if err := s.checkUpdate(ctx, env, spec); err != nil {
+			return nil, err
+		}
+		return resp, nil
+	}
+	previous := env.GetSpec()
+	if err := s.applySpec(ctx, env, spec, 0); err != nil {
+		return nil, err
+	}
+	s.mu.Unlock()
+	locked = false
+	if err := s.deployRevision(ctx, env, previous); err != nil {
+		return nil, err
+	}
+	resp.Environment = s.withQueuePosition(env)
+	return resp, nil
+}
+
+// checkAdmissible runs the checks creating or updating to env would run,
+// without changing anything. Creating, updating and planning all go through
+// it, so a plan or dry run is only accepted when the change would be. It
+// must be called with s.mu held.
+func (s *SchedulerService) checkAdmissible(ctx context.Context, env *pb.Environment) error {
+	if err := s.checkSecrets(ctx, env.GetTenant(), env.GetSpec()); err != nil {
+		return err
+	}
+	if err := s.checkNameAvailable(env); err != nil {
+		return err
+	}
+	if err := s.checkQuota(env); err != nil {
+		return err
+	}
+	// Running environments pick up the specification right away, so it has
+	// to fit next to the others
+	if env.GetStatus() == pb.EnvironmentStatus_ENVIRONMENT_STATUS_RUNNING {
+		if err := s.checkCapacity(env); err != nil {
+			return err
+		}
+	}
+	return s.checkSubnet(env)
+}
+
+// withAssignedNetwork returns spec with the subnet and gateway an update
+// would end up with filled in: the subnet already assigned to the
+// environment when spec leaves it to the scheduler, and the default gateway
+// of the subnet. Comparing against the result shows no network change when
+// none was asked for.
+func withAssignedNetwork(current, spec *pb.EnvironmentSpecification) *pb.EnvironmentSpecification {
+	planned := proto.Clone(spec).(*pb.EnvironmentSpecification)
+	if planned.Network == nil {
+		planned.Network = &pb.NetworkConfig{}
+	}
+	subnet, err := netip.ParsePrefix(planned.Network.GetSubnet())
+	if planned.Network.GetSubnet() == "" {
+		subnet, err = netip.ParsePrefix(current.GetNetwork().GetSubnet())
+	}
+	if err != nil {
+		// Invalid subnets are left for the update to reject
+		return planned
+	}
+	planned.Network.Subnet = subnet.Masked().String()
+	if planned.Network.GetGateway() == "" {
+		planned.Network.Gateway = network.Gateway(subnet.Masked()).String()
+	}
+	return planned
+}
+
+// findEnvironment returns the environment of a tenant with the given name,
+// or nil when there is none
+func (s *SchedulerService) findEnvironment(tenantName, name string) *pb.Environment {
+	for _, env := range s.store.List() {
+		if env.GetTenant() == tenantName && env.GetName() == name {
+			return env
+		}
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"scheduler/internal/container"
+	pb "scheduler/proto/gen"
+)
+
+const testManifest = `apiVersion: scheduler/v1
+kind: Environment
+metadata:
+  name: shop
+  labels:
+    team: web
+spec:
+  application_stack:
+    database:
+      container:
+        image: postgres:16
+      database_name: app
+      username: app
+      password: secret
+    backend:
+      container:
+        image: backend:1
+`
+
+func TestApplyManifest(t *testing.T) {
+	s := newTestService(t, &container.Fake{})
+	ctx := context.Background()
+
+	planned, err := s.ApplyManifest(ctx, &pb.ApplyManifestRequest{Manifest: testManifest, DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if planned.GetAction() != pb.ManifestAction_MANIFEST_ACTION_CREATED || planned.GetEnvironment().GetId() != "" || len(planned.GetChanges()) == 0 {
+		t.Errorf("dry run = %v, want a planned creation without an ID", planned)
+	}
+	if len(s.store.List()) != 0 {
+		t.Fatal("dry run created an environment")
+	}
+
+	created, err := s.ApplyManifest(ctx, &pb.ApplyManifestRequest{Manifest: testManifest})
+	if err != nil {
+		t.Fatal(err)
+	}
+	env := created.GetEnvironment()
+	if created.GetAction() != pb.ManifestAction_MANIFEST_ACTION_CREATED || env.GetSpec().GetLabels()["team"] != "web" {
+		t.Fatalf("first ApplyManifest() = %v, want the environment created with its labels", created)
+	}
+
+	// Applying the same manifest again changes nothing, even though the
+	// environment has a subnet assigned the manifest leaves out
+	unchanged, err := s.ApplyManifest(ctx, &pb.ApplyManifestRequest{Manifest: testManifest})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if unchanged.GetAction() != pb.ManifestAction_MANIFEST_ACTION_UNCHANGED || len(unchanged.GetChanges()) != 0 {
+		t.Errorf("reapplying = %v, want it unchanged", unchanged)
+	}
+
+	changed := strings.Replace(testManifest, "backend:1", "backend:2", 1)
+	planned, err = s.ApplyManifest(ctx, &pb.ApplyManifestRequest{Manifest: changed, DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*pb.FieldChange{{
+		Path:     "application_stack.backend.container.image",
+		Type:     pb.ChangeType_CHANGE_TYPE_MODIFIED,
+		OldValue: "backend:1",
+		NewValue: "backend:2",
+	}}
+	if planned.GetAction() != pb.ManifestAction_MANIFEST_ACTION_UPDATED || !equalChanges(planned.GetChanges(), want) {
+		t.Errorf("dry run of an update = %v, want %v", planned, want)
+	}
+
+	updated, err := s.ApplyManifest(ctx, &pb.ApplyManifestRequest{Manifest: changed})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.GetAction() != pb.ManifestAction_MANIFEST_ACTION_UPDATED || updated.GetEnvironment().GetId() != env.GetId() {
+		t.Errorf("second ApplyManifest() = %v, want %s updated", updated, env.GetId())
+	}
+	if updated.GetEnvironment().GetRevision() != 2 {
+		t.Errorf("revision = %d, want 2", updated.GetEnvironment().GetRevision())
+	}
+
+	tests := []struct {
+		name     string
+		manifest string
+	}{
+		{name: "unknown field", manifest: strings.Replace(testManifest, "username: app", "user: app", 1)},
+		{name: "several manifests", manifest: testManifest + "---\n" + testManifest},
+		{name: "missing name", manifest: strings.Replace(testManifest, "  name: shop\n", "", 1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := s.ApplyManifest(ctx, &pb.ApplyManifestRequest{Manifest: tt.manifest}); status.Code(err) != codes.InvalidArgument {
+				t.Errorf("ApplyManifest() = %v, want InvalidArgument", err)
+			}
+		})
+	}
+}
+
+// equalChanges reports whether two lists of field changes are equal
+func equalChanges(got, want []*pb.FieldChange) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if !proto.Equal(got[i], want[i]) {
+			return false
+		}
+	}
+	return true
+}
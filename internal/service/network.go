@@ -0,0 +1,154 @@
+package service
+
+import (
+	"errors"
+	"net/netip"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"scheduler/internal/network"
+	"scheduler/internal/stack"
+	pb "scheduler/proto/gen"
+)
+
+// assignSubnet reserves the subnet requested in the environment's network
+// configuration, or carves one out of the tenant's pool when none was given, and
+// records the assignment and gateway back into the specification
+func (s *SchedulerService) assignSubnet(env *pb.Environment) error {
+	if env.Spec.Network == nil {
+		env.Spec.Network = &pb.NetworkConfig{}
+	}
+	netConfig := env.Spec.Network
+
+	requested, gateway, err := requestedNetwork(netConfig)
+	if err != nil {
+		return err
+	}
+	subnet := requested.Masked()
+	if !requested.IsValid() {
+		allocated, err := s.subnets.Allocate(env.GetId(), s.subnetPoolFor(env))
+		if err != nil {
+			return subnetError(err)
+		}
+		subnet = allocated
+	} else if err := s.subnets.Reserve(env.GetId(), requested); err != nil {
+		return subnetError(err)
+	}
+
+	if !gateway.IsValid() {
+		gateway = network.Gateway(subnet)
+	}
+	netConfig.Subnet = subnet.String()
+	netConfig.Gateway = gateway.String()
+	return nil
+}
+
+// checkSubnet runs the checks assignSubnet would run for env without
+// assigning anything. Subnets left to the scheduler are not checked, the
+// pool running out is only found when allocating.
+func (s *SchedulerService) checkSubnet(env *pb.Environment) error {
+	requested, _, err := requestedNetwork(env.GetSpec().GetNetwork())
+	if err != nil || !requested.IsValid() {
+		return err
+	}
+	if err := s.subnets.Check(env.GetId(), requested); err != nil {
+		return subnetError(err)
+	}
+	return nil
+}
+
+// requestedNetwork parses the subnet and gateway of a network configuration.
+// Both are invalid when the subnet is left to the scheduler.
+func requestedNetwork(netConfig *pb.NetworkConfig) (netip.Prefix, netip.Addr, error) {
+	var gateway netip.Addr
+	if netConfig.GetGateway() != "" {
+		parsed, err := netip.ParseAddr(netConfig.GetGateway())
+		if err != nil {
+			return netip.Prefix{}, netip.Addr{}, status.Errorf(codes.InvalidArgument, "invalid gateway %q: %v", netConfig.GetGateway(), err)
+		}
+		gateway = parsed
+	}
+
+	if netConfig.GetSubnet() == "" {
+		if gateway.IsValid() {
+			return netip.Prefix{}, netip.Addr{}, status.Errorf(codes.InvalidArgument, "gateway %s requires an explicit subnet", gateway)
+		}
+		return netip.Prefix{}, netip.Addr{}, nil
+	}
+	requested, err := netip.ParsePrefix(netConfig.GetSubnet())
+	if err != nil {
+		return netip.Prefix{}, netip.Addr{}, status.Errorf(codes.InvalidArgument, "invalid subnet %q: %v", netConfig.GetSubnet(), err)
+	}
+	if gateway.IsValid() && !requested.Contains(gateway) {
+		return netip.Prefix{}, netip.Addr{}, status.Errorf(codes.InvalidArgument, "gateway %s is not within subnet %s", gateway, requested)
+	}
+	return requested, gateway, nil
+}
+
+// subnetError maps allocator errors onto gRPC status codes
+func subnetError(err error) error {
+	switch {
+	case errors.Is(err, network.ErrPoolExhausted):
+		return status.Errorf(codes.ResourceExhausted, "%v", err)
+	case errors.Is(err, network.ErrSubnetOverlap):
+		return status.Errorf(codes.FailedPrecondition, "%v", err)
+	default:
+		return status.Errorf(codes.Internal, "%v", err)
+	}
+}
+
+// assignAddresses records a container instance with a stable address in the
+// environment subnet for every container of the stack. Instances that already
+// exist keep their address and status.
+func (s *SchedulerService) assignAddresses(env *pb.Environment) error {
+	subnet, err := netip.ParsePrefix(env.GetSpec().GetNetwork().GetSubnet())
+	if err != nil {
+		return status.Errorf(codes.Internal, "environment %s has invalid subnet: %v", env.GetId(), err)
+	}
+	gateway, err := netip.ParseAddr(env.GetSpec().GetNetwork().GetGateway())
+	if err != nil {
+		return status.Errorf(codes.Internal, "environment %s has invalid gateway: %v", env.GetId(), err)
+	}
+
+	existing := make(map[string]*pb.ContainerInstance, len(env.GetContainers()))
+	previous := make(map[string]netip.Addr, len(env.GetContainers()))
+	for _, instance := range env.GetContainers() {
+		existing[instance.GetId()] = instance
+		if addr, err := netip.ParseAddr(instance.GetIpAddress()); err == nil {
+			previous[instance.GetId()] = addr
+		}
+	}
+
+	members := stack.Members(env.GetSpec().GetApplicationStack())
+	ids := make([]string, 0, len(members))
+	for _, member := range members {
+		ids = append(ids, containerID(env.GetId(), member))
+	}
+	addresses, err := network.AssignAddresses(subnet, gateway, ids, previous)
+	if err != nil {
+		return status.Errorf(codes.ResourceExhausted, "%v", err)
+	}
+
+	containers := make([]*pb.ContainerInstance, 0, len(members))
+	for i, member := range members {
+		instance, ok := existing[ids[i]]
+		if !ok {
+			instance = &pb.ContainerInstance{
+				Id:     ids[i],
+				Status: pb.ContainerStatus_CONTAINER_STATUS_PENDING,
+			}
+		}
+		instance.Name = member.Name()
+		instance.Image = member.Container.GetImage()
+		instance.IpAddress = addresses[ids[i]].String()
+		containers = append(containers, instance)
+	}
+	env.Containers = containers
+	return nil
+}
+
+// containerID derives the ID of a stack member's container instance
+func containerID(environmentID string, member stack.Member) string {
+	return environmentID + "-" + member.Alias
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"scheduler/internal/capacity"
+	"scheduler/internal/quota"
+	pb "scheduler/proto/gen"
+)
+
+// GetNodeInfo reports the capacity of the host and how much of it running
+// environments have reserved
+func (s *SchedulerService) GetNodeInfo(ctx context.Context, req *pb.GetNodeInfoRequest) (*pb.GetNodeInfoResponse, error) {
+	if s.node == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "host capacity tracking is disabled")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	hostname, _ := os.Hostname()
+	reserved, running := s.reservedCapacity("")
+	return &pb.GetNodeInfoResponse{Node: &pb.NodeInfo{
+		Hostname:    hostname,
+		Capacity:    s.node.Capacity.ToProto(),
+		Allocatable: s.node.Allocatable().ToProto(),
+		Reserved:    reserved.ToProto(),
+		Overcommit: &pb.OvercommitRatios{
+			Memory: s.node.Overcommit.Memory,
+			Cpu:    s.node.Overcommit.CPU,
+			Disk:   s.node.Overcommit.Disk,
+		},
+		RunningEnvironments: int32(running),
+	}}, nil
+}
+
+// checkCapacity verifies that the host can fit env next to every other
+// running environment. It must be called with s.mu held.
+func (s *SchedulerService) checkCapacity(env *pb.Environment) error {
+	if s.node == nil {
+		return nil
+	}
+	reserved, _ := s.reservedCapacity(env.GetId())
+	requested := capacity.FromQuota(quota.Requested(env.GetSpec()))
+	if err := s.node.Fit(reserved, requested); err != nil {
+		return capacityError(err)
+	}
+	return nil
+}
+
+// reservedCapacity sums the resource limits of the running environments,
+// leaving out excludeID, and counts them. Environments whose containers are
+// being started, updated or stopped count as running, and so do failed ones
+// until they are stopped.
+func (s *SchedulerService) reservedCapacity(excludeID string) (capacity.Resources, int) {
+	var reserved capacity.Resources
+	running := 0
+	for _, env := range s.store.List() {
+		if env.GetId() == excludeID || !holdsCapacity(env.GetStatus()) {
+			continue
+		}
+		reserved = reserved.Add(capacity.FromQuota(quota.Requested(env.GetSpec())))
+		running++
+	}
+	return reserved, running
+}
+
+// holdsCapacity reports whether environments of a status have containers
+// that may be running. A failed update or stop leaves the containers it did
+// not get to running, so failed environments hold their capacity until
+// stopping or deleting them cleans up.
+func holdsCapacity(envStatus pb.EnvironmentStatus) bool {
+	switch envStatus {
+	case pb.EnvironmentStatus_ENVIRONMENT_STATUS_CREATING,
+		pb.EnvironmentStatus_ENVIRONMENT_STATUS_RUNNING,
+		pb.EnvironmentStatus_ENVIRONMENT_STATUS_UPDATING,
+		pb.EnvironmentStatus_ENVIRONMENT_STATUS_STOPPING,
+		pb.EnvironmentStatus_ENVIRONMENT_STATUS_FAILED:
+		return true
+	}
+	return false
+}
+
+// capacityError maps capacity errors onto gRPC status codes
+func capacityError(err error) error {
+	if errors.Is(err, capacity.ErrInsufficient) {
+		return status.Errorf(codes.ResourceExhausted, "%v", err)
+	}
+	return status.Errorf(codes.Internal, "%v", err)
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"scheduler/internal/capacity"
+	"scheduler/internal/container"
+	pb "scheduler/proto/gen"
+)
+
+// sizedSpec is testSpec with a backend reserving memoryMB
+func sizedSpec(name string, memoryMB int64) *pb.EnvironmentSpecification {
+	spec := testSpec(name)
+	spec.GetApplicationStack().GetBackend().GetContainer().Resources = &pb.ResourceLimits{MemoryMb: memoryMB}
+	return spec
+}
+
+func TestCapacity(t *testing.T) {
+	s := newTestService(t, &container.Fake{}, func(opts *Options) {
+		opts.Node = &capacity.Node{
+			Capacity:   capacity.Resources{MemoryMB: 1000, CPUCores: 4, DiskMB: 10000},
+			Overcommit: capacity.Overcommit{Memory: 1.5, CPU: 1, Disk: 1},
+		}
+	})
+	ctx := context.Background()
+
+	first := createEnvironment(t, s, sizedSpec("first", 1000))
+	second := createEnvironment(t, s, sizedSpec("second", 1000))
+	if _, err := s.StartEnvironment(ctx, &pb.StartEnvironmentRequest{Id: first.GetId()}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.StartEnvironment(ctx, &pb.StartEnvironmentRequest{Id: second.GetId()}); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("starting beyond the overcommitted capacity = %v, want ResourceExhausted", err)
+	}
+
+	info, err := s.GetNodeInfo(ctx, &pb.GetNodeInfoRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	node := info.GetNode()
+	if node.GetAllocatable().GetMemoryMb() != 1500 || node.GetReserved().GetMemoryMb() != 1000 || node.GetRunningEnvironments() != 1 {
+		t.Errorf("GetNodeInfo() = %v, want 1000 of 1500 MB reserved by one environment", node)
+	}
+
+	// Stopped environments release their reservation
+	if _, err := s.StopEnvironment(ctx, &pb.StopEnvironmentRequest{Id: first.GetId()}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.StartEnvironment(ctx, &pb.StartEnvironmentRequest{Id: second.GetId()}); err != nil {
+		t.Errorf("starting after another environment stopped = %v", err)
+	}
+}
+
+func TestFailedEnvironmentHoldsCapacity(t *testing.T) {
+	runtime := &container.Fake{}
+	s := newTestService(t, runtime, func(opts *Options) {
+		opts.Node = &capacity.Node{
+			Capacity:   capacity.Resources{MemoryMB: 1500, CPUCores: 4, DiskMB: 10000},
+			Overcommit: capacity.Overcommit{Memory: 1, CPU: 1, Disk: 1},
+		}
+	})
+	ctx := context.Background()
+
+	spec := sizedSpec("failing", 1000)
+	failing := createEnvironment(t, s, spec)
+	other := createEnvironment(t, s, sizedSpec("other", 1000))
+	if _, err := s.StartEnvironment(ctx, &pb.StartEnvironmentRequest{Id: failing.GetId()}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The failed update leaves the database and frontend running
+	runtime.OnRun = func(_, _ string, _ container.Spec) error { return container.ErrFake }
+	spec.ApplicationStack.Backend.Container.Image = "backend:2"
+	if _, err := s.UpdateEnvironment(ctx, &pb.UpdateEnvironmentRequest{Id: failing.GetId(), Spec: spec}); err == nil {
+		t.Fatal("UpdateEnvironment() succeeded despite the failing start")
+	}
+	runtime.OnRun = nil
+	if len(runtime.RunningIDs(testNamespace)) == 0 {
+		t.Fatal("no containers left running after the failed update")
+	}
+	if _, err := s.StartEnvironment(ctx, &pb.StartEnvironmentRequest{Id: other.GetId()}); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("starting next to a failed environment = %v, want ResourceExhausted", err)
+	}
+
+	if _, err := s.StopEnvironment(ctx, &pb.StopEnvironmentRequest{Id: failing.GetId()}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.StartEnvironment(ctx, &pb.StartEnvironmentRequest{Id: other.GetId()}); err != nil {
+		t.Errorf("starting after the failed environment stopped = %v", err)
+	}
+}
+
+func TestNodeInfoDisabled(t *testing.T) {
+	s := newTestService(t, &container.Fake{})
+	if _, err := s.GetNodeInfo(context.Background(), &pb.GetNodeInfoRequest{}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("GetNodeInfo() without capacity tracking = %v, want FailedPrecondition", err)
+	}
+}
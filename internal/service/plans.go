@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"scheduler/internal/diff"
+	"scheduler/internal/stack"
+	pb "scheduler/proto/gen"
+)
+
+// PlanEnvironmentUpdate reports what updating an environment to a
+// specification would do, container by container, without changing
+// anything. The update is checked the way UpdateEnvironment checks it, so a
+// plan is only returned for updates that would be accepted.
+func (s *SchedulerService) PlanEnvironmentUpdate(ctx context.Context, req *pb.PlanEnvironmentUpdateRequest) (*pb.PlanEnvironmentUpdateResponse, error) {
+	if err := validateSpec(req.GetSpec()); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid specification: %v", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	env, err := s.getEnvironment(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	spec := withAssignedNetwork(env.GetSpec(), req.GetSpec())
+	if err := s.checkUpdate(ctx, env, spec); err != nil {
+		return nil, err
+	}
+	changes := diff.Messages(env.GetSpec(), spec)
+	return &pb.PlanEnvironmentUpdateResponse{
+		Environment: s.withQueuePosition(env),
+		Changes:     changes,
+		Containers:  planContainers(env.GetSpec(), spec, changes),
+	}, nil
+}
+
+// checkUpdate runs the checks updating env to spec would run, without
+// changing anything
+func (s *SchedulerService) checkUpdate(ctx context.Context, env *pb.Environment, spec *pb.EnvironmentSpecification) error {
+	if err := checkScope(ctx, env.GetTenant(), spec.GetLabels()); err != nil {
+		return err
+	}
+	planned := proto.Clone(env).(*pb.Environment)
+	planned.Spec, planned.Name = spec, spec.GetName()
+	return s.checkAdmissible(ctx, planned)
+}
+
+// planContainers sorts the changes between two specifications by the
+// container they affect. A container is recreated when its own configuration
+// changes, or when the subnet changes and every container gets a new address.
+func planContainers(current, spec *pb.EnvironmentSpecification, changes []*pb.FieldChange) []*pb.ContainerPlan {
+	var networkChanges []*pb.FieldChange
+	for _, change := range changes {
+		if change.GetPath() == "network.subnet" || change.GetPath() == "network.gateway" {
+			networkChanges = append(networkChanges, change)
+		}
+	}
+
+	existing := make(map[string]bool)
+	for _, member := range stack.Members(current.GetApplicationStack()) {
+		existing[memberPath(member)] = true
+	}
+
+	var plans []*pb.ContainerPlan
+	planned := make(map[string]bool)
+	for _, member := range stack.Members(spec.GetApplicationStack()) {
+		planned[memberPath(member)] = true
+		plan := planContainer(member, changes)
+		switch {
+		case !existing[memberPath(member)]:
+			plan.Action = pb.ContainerAction_CONTAINER_ACTION_CREATE
+		case len(plan.Changes) > 0 || len(networkChanges) > 0:
+			plan.Action = pb.ContainerAction_CONTAINER_ACTION_RECREATE
+			plan.Changes = append(plan.Changes, networkChanges...)
+		default:
+			plan.Action = pb.ContainerAction_CONTAINER_ACTION_UNCHANGED
+		}
+		plans = append(plans, plan)
+	}
+	for _, member := range stack.Members(current.GetApplicationStack()) {
+		if !planned[memberPath(member)] {
+			plan := planContainer(member, changes)
+			plan.Action = pb.ContainerAction_CONTAINER_ACTION_REMOVE
+			plans = append(plans, plan)
+		}
+	}
+	return plans
+}
+
+// planContainer collects the changes that affect the container of a stack
+// member. Settings the container does not see, like the frontend domains,
+// are left out.
+func planContainer(member stack.Member, changes []*pb.FieldChange) *pb.ContainerPlan {
+	containerPath := memberPath(member) + ".container."
+	prefixes := []string{containerPath}
+	if member.Additional {
+		containerPath = memberPath(member) + "."
+		prefixes = []string{containerPath}
+	} else {
+		// The database credentials are passed to the database and the backend
+		// as environment variables, along with the backend settings and the
+		// database port
+		database := "application_stack." + stack.RoleDatabase
+		switch member.Alias {
+		case stack.RoleDatabase:
+			prefixes = append(prefixes, database+".database_name", database+".username", database+".password")
+		case stack.RoleBackend:
+			prefixes = append(prefixes,
+				memberPath(member)+".database_connection_string",
+				memberPath(member)+".api_keys.",
+				database+".database_name", database+".username", database+".password",
+				database+".container.ports")
+		}
+	}
+
+	plan := &pb.ContainerPlan{Alias: member.Alias, Name: member.Name()}
+	for _, change := range changes {
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(change.GetPath(), prefix) {
+				plan.Changes = append(plan.Changes, change)
+				break
+			}
+		}
+		plan.VolumesChanged = plan.VolumesChanged || strings.HasPrefix(change.GetPath(), containerPath+"volumes")
+		plan.PortsChanged = plan.PortsChanged || strings.HasPrefix(change.GetPath(), containerPath+"ports")
+	}
+	return plan
+}
+
+// memberPath returns the specification path of a stack member, the prefix
+// of the paths of its changes
+func memberPath(member stack.Member) string {
+	if member.Additional {
+		return "application_stack.additional_services." + member.Alias
+	}
+	return "application_stack." + member.Alias
+}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"scheduler/internal/capacity"
+	"scheduler/internal/container"
+	"scheduler/internal/diff"
+	pb "scheduler/proto/gen"
+)
+
+func TestPlanContainers(t *testing.T) {
+	const (
+		create    = pb.ContainerAction_CONTAINER_ACTION_CREATE
+		recreate  = pb.ContainerAction_CONTAINER_ACTION_RECREATE
+		unchanged = pb.ContainerAction_CONTAINER_ACTION_UNCHANGED
+		remove    = pb.ContainerAction_CONTAINER_ACTION_REMOVE
+	)
+
+	tests := []struct {
+		name   string
+		change func(spec *pb.EnvironmentSpecification)
+		want   map[string]pb.ContainerAction
+		// ports names the containers whose port mappings change
+		ports []string
+	}{
+		{
+			name: "unchanged",
+			want: map[string]pb.ContainerAction{"database": unchanged, "backend": unchanged, "frontend": unchanged},
+		},
+		{
+			name:   "backend image",
+			change: func(spec *pb.EnvironmentSpecification) { spec.ApplicationStack.Backend.Container.Image = "backend:2" },
+			want:   map[string]pb.ContainerAction{"database": unchanged, "backend": recreate, "frontend": unchanged},
+		},
+		{
+			// The credentials reach the backend through its environment
+			name:   "database password",
+			change: func(spec *pb.EnvironmentSpecification) { spec.ApplicationStack.Database.Password = "other" },
+			want:   map[string]pb.ContainerAction{"database": recreate, "backend": recreate, "frontend": unchanged},
+		},
+		{
+			// Domains are routed outside the container
+			name: "frontend domains",
+			change: func(spec *pb.EnvironmentSpecification) {
+				spec.ApplicationStack.Frontend.Domains = []string{"example.com"}
+			},
+			want: map[string]pb.ContainerAction{"database": unchanged, "backend": unchanged, "frontend": unchanged},
+		},
+		{
+			name: "frontend ports",
+			change: func(spec *pb.EnvironmentSpecification) {
+				spec.ApplicationStack.Frontend.Container.Ports = []*pb.PortMapping{{ContainerPort: 80, HostPort: 8080}}
+			},
+			want:  map[string]pb.ContainerAction{"database": unchanged, "backend": unchanged, "frontend": recreate},
+			ports: []string{"frontend"},
+		},
+		{
+			name: "service added and frontend removed",
+			change: func(spec *pb.EnvironmentSpecification) {
+				spec.ApplicationStack.Frontend = nil
+				spec.ApplicationStack.AdditionalServices = map[string]*pb.ContainerConfig{"cache": {Image: "redis:7"}}
+			},
+			want: map[string]pb.ContainerAction{"database": unchanged, "backend": unchanged, "cache": create, "frontend": remove},
+		},
+		{
+			name: "subnet",
+			change: func(spec *pb.EnvironmentSpecification) {
+				spec.Network = &pb.NetworkConfig{Subnet: "10.99.0.0/24"}
+			},
+			want: map[string]pb.ContainerAction{"database": recreate, "backend": recreate, "frontend": recreate},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current := testSpec("plan")
+			spec := proto.Clone(current).(*pb.EnvironmentSpecification)
+			if tt.change != nil {
+				tt.change(spec)
+			}
+
+			got := make(map[string]pb.ContainerAction)
+			var ports []string
+			for _, plan := range planContainers(current, spec, diff.Messages(current, spec)) {
+				got[plan.GetAlias()] = plan.GetAction()
+				if plan.GetPortsChanged() {
+					ports = append(ports, plan.GetAlias())
+				}
+				if plan.GetAction() == recreate && len(plan.GetChanges()) == 0 {
+					t.Errorf("%s is recreated without a change causing it", plan.GetAlias())
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("actions = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(ports, tt.ports) {
+				t.Errorf("ports changed for %v, want %v", ports, tt.ports)
+			}
+		})
+	}
+}
+
+func TestPlanRejectsUpdates(t *testing.T) {
+	s := newTestService(t, &container.Fake{}, func(opts *Options) {
+		opts.Node = &capacity.Node{
+			Capacity:   capacity.Resources{MemoryMB: 1500, CPUCores: 4, DiskMB: 10000},
+			Overcommit: capacity.Overcommit{Memory: 1, CPU: 1, Disk: 1},
+		}
+	})
+	ctx := context.Background()
+
+	spec := sizedSpec("planned", 500)
+	env := createEnvironment(t, s, spec)
+	other := createEnvironment(t, s, sizedSpec("other", 500))
+	for _, id := range []string{env.GetId(), other.GetId()} {
+		if _, err := s.StartEnvironment(ctx, &pb.StartEnvironmentRequest{Id: id}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		change func(spec *pb.EnvironmentSpecification)
+		want   codes.Code
+	}{
+		{
+			name: "capacity of a running environment",
+			change: func(spec *pb.EnvironmentSpecification) {
+				spec.ApplicationStack.Backend.Container.Resources.MemoryMb = 1200
+			},
+			want: codes.ResourceExhausted,
+		},
+		{
+			name: "subnet of another environment",
+			change: func(spec *pb.EnvironmentSpecification) {
+				spec.Network = &pb.NetworkConfig{Subnet: other.GetSpec().GetNetwork().GetSubnet()}
+			},
+			want: codes.FailedPrecondition,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updated := proto.Clone(spec).(*pb.EnvironmentSpecification)
+			tt.change(updated)
+			_, planErr := s.PlanEnvironmentUpdate(ctx, &pb.PlanEnvironmentUpdateRequest{Id: env.GetId(), Spec: updated})
+			if status.Code(planErr) != tt.want {
+				t.Errorf("PlanEnvironmentUpdate() error = %v, want %v", planErr, tt.want)
+			}
+			_, updateErr := s.UpdateEnvironment(ctx, &pb.UpdateEnvironmentRequest{Id: env.GetId(), Spec: updated})
+			if status.Code(updateErr) != tt.want {
+				t.Errorf("UpdateEnvironment() error = %v, want %v", updateErr, tt.want)
+			}
+		})
+	}
+}
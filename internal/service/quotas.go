@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"scheduler/internal/quota"
+	pb "scheduler/proto/gen"
+)
+
+// GetQuotaUsage reports the resources used by the environments of a tenant
+// and of the whole host against their quotas
+func (s *SchedulerService) GetQuotaUsage(ctx context.Context, req *pb.GetQuotaUsageRequest) (*pb.GetQuotaUsageResponse, error) {
+	owner, err := s.resolveTenant(ctx, req.GetTenant())
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return &pb.GetQuotaUsageResponse{
+		Tenant: owner.Name,
+		TenantUsage: &pb.QuotaUsage{
+			Used:  s.quotaUsage(owner.Name, "").ToProto(),
+			Limit: owner.Quota.ToProto(),
+		},
+		HostUsage: &pb.QuotaUsage{
+			Used:  s.quotaUsage("", "").ToProto(),
+			Limit: s.hostQuota.ToProto(),
+		},
+	}, nil
+}
+
+// checkQuota verifies that env fits into the quota of its tenant and the host
+// quota, counting every other environment as in use. It must be called with
+// s.mu held.
+func (s *SchedulerService) checkQuota(env *pb.Environment) error {
+	requested := quota.Requested(env.GetSpec())
+	owner := s.tenantOf(env)
+	if err := quota.Check("tenant "+owner.Name, owner.Quota, s.quotaUsage(owner.Name, env.GetId()), requested); err != nil {
+		return quotaError(err)
+	}
+	if err := quota.Check("the host", s.hostQuota, s.quotaUsage("", env.GetId()), requested); err != nil {
+		return quotaError(err)
+	}
+	return nil
+}
+
+// quotaUsage sums the resources of the environments of a tenant, or of all
+// environments when tenantName is empty, leaving out excludeID
+func (s *SchedulerService) quotaUsage(tenantName, excludeID string) quota.Resources {
+	var used quota.Resources
+	for _, env := range s.store.List() {
+		if env.GetId() == excludeID || (tenantName != "" && env.GetTenant() != tenantName) {
+			continue
+		}
+		used = used.Add(quota.Requested(env.GetSpec()))
+	}
+	return used
+}
+
+// quotaError maps quota errors onto gRPC status codes
+func quotaError(err error) error {
+	if errors.Is(err, quota.ErrExceeded) {
+		return status.Errorf(codes.ResourceExhausted, "%v", err)
+	}
+	return status.Errorf(codes.Internal, "%v", err)
+}
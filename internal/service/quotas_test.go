@@ -0,0 +1,47 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"scheduler/internal/container"
+	"scheduler/internal/quota"
+	pb "scheduler/proto/gen"
+)
+
+func TestQuotas(t *testing.T) {
+	s := newTestService(t, &container.Fake{}, func(opts *Options) {
+		opts.HostQuota = quota.Resources{Environments: 2}
+	})
+	ctx := context.Background()
+
+	first := createEnvironment(t, s, testSpec("first"))
+	createEnvironment(t, s, testSpec("second"))
+	if _, err := s.CreateEnvironment(ctx, &pb.CreateEnvironmentRequest{Spec: testSpec("third")}); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("creating an environment over the host quota = %v, want ResourceExhausted", err)
+	}
+
+	// Updates count the environment once, replacing its previous resources
+	if _, err := s.UpdateEnvironment(ctx, &pb.UpdateEnvironmentRequest{Id: first.GetId(), Spec: testSpec("first")}); err != nil {
+		t.Errorf("updating an environment at the host quota = %v", err)
+	}
+
+	usage, err := s.GetQuotaUsage(ctx, &pb.GetQuotaUsageRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if usage.GetTenant() != "default" || usage.GetTenantUsage().GetUsed().GetEnvironments() != 2 {
+		t.Errorf("tenant usage = %v, want two environments of the default tenant", usage)
+	}
+	if host := usage.GetHostUsage(); host.GetUsed().GetEnvironments() != 2 || host.GetLimit().GetEnvironments() != 2 {
+		t.Errorf("host usage = %v, want 2 of 2 environments", host)
+	}
+
+	if _, err := s.DeleteEnvironment(ctx, &pb.DeleteEnvironmentRequest{Id: first.GetId()}); err != nil {
+		t.Fatal(err)
+	}
+	createEnvironment(t, s, testSpec("third"))
+}
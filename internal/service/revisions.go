@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"scheduler/internal/auth"
+	"scheduler/internal/revision"
+	pb "scheduler/proto/gen"
+)
+
+// ListEnvironmentRevisions lists the specifications applied to an
+// environment, newest first
+func (s *SchedulerService) ListEnvironmentRevisions(ctx context.Context, req *pb.ListEnvironmentRevisionsRequest) (*pb.ListEnvironmentRevisionsResponse, error) {
+	env, err := s.getEnvironment(ctx, req.GetEnvironmentId())
+	if err != nil {
+		return nil, err
+	}
+	revisions, err := s.revisions.List(env.GetId())
+	if err != nil {
+		return nil, revisionError(err)
+	}
+	return &pb.ListEnvironmentRevisionsResponse{Revisions: revisions}, nil
+}
+
+// GetEnvironmentRevision retrieves one revision of an environment
+func (s *SchedulerService) GetEnvironmentRevision(ctx context.Context, req *pb.GetEnvironmentRevisionRequest) (*pb.GetEnvironmentRevisionResponse, error) {
+	env, err := s.getEnvironment(ctx, req.GetEnvironmentId())
+	if err != nil {
+		return nil, err
+	}
+	rev, err := s.revisions.Get(env.GetId(), req.GetNumber())
+	if err != nil {
+		return nil, revisionError(err)
+	}
+	return &pb.GetEnvironmentRevisionResponse{Revision: rev}, nil
+}
+
+// RollbackEnvironment applies the specification of an earlier revision
+// through the regular update path, recording it as a new revision. A running
+// environment is redeployed like on every update, and a failure to do so is
+// recorded in the new revision.
+func (s *SchedulerService) RollbackEnvironment(ctx context.Context, req *pb.RollbackEnvironmentRequest) (*pb.RollbackEnvironmentResponse, error) {
+	unlock := s.lockEnvironment(req.GetId())
+	defer unlock()
+
+	env, previous, err := s.rollbackSpec(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.deployRevision(ctx, env, previous); err != nil {
+		return nil, err
+	}
+	return &pb.RollbackEnvironmentResponse{Environment: s.withQueuePosition(env)}, nil
+}
+
+// rollbackSpec applies the specification of the requested revision to the
+// stored environment and returns it together with the specification it
+// replaced. It must be called with the environment lock held.
+func (s *SchedulerService) rollbackSpec(ctx context.Context, req *pb.RollbackEnvironmentRequest) (*pb.Environment, *pb.EnvironmentSpecification, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	env, err := s.getEnvironment(ctx, req.GetId())
+	if err != nil {
+		return nil, nil, err
+	}
+	if req.GetRevision() == env.GetRevision() {
+		return nil, nil, status.Errorf(codes.FailedPrecondition, "environment %s is already at revision %d", env.GetId(), env.GetRevision())
+	}
+	rev, err := s.revisions.Get(env.GetId(), req.GetRevision())
+	if err != nil {
+		return nil, nil, revisionError(err)
+	}
+	// Specifications are validated again since validation may have become
+	// stricter since the revision was applied
+	if err := validateSpec(rev.GetSpec()); err != nil {
+		return nil, nil, status.Errorf(codes.FailedPrecondition, "revision %d can no longer be applied: %v", rev.GetNumber(), err)
+	}
+	previous := env.GetSpec()
+	if err := s.applySpec(ctx, env, rev.GetSpec(), rev.GetNumber()); err != nil {
+		return nil, nil, err
+	}
+	return env, previous, nil
+}
+
+// deployRevision reconciles the containers of an environment that was just
+// updated from previous and records a failure in its current revision. It
+// must be called with the environment lock held and without s.mu.
+func (s *SchedulerService) deployRevision(ctx context.Context, env *pb.Environment, previous *pb.EnvironmentSpecification) error {
+	err := s.reconcileContainers(ctx, env, previous)
+	if err != nil {
+		if recordErr := s.revisions.SetDeployError(env.GetId(), env.GetRevision(), status.Convert(err).Message()); recordErr != nil {
+			log.Printf("Failed to record deploy failure of %s revision %d: %v", env.GetId(), env.GetRevision(), recordErr)
+		}
+	}
+	return err
+}
+
+// recordRevision stores the specification of env as its next revision,
+// authored by the caller, and records the revision number on env
+func (s *SchedulerService) recordRevision(ctx context.Context, env *pb.Environment, rollbackOf int32) error {
+	author := ""
+	if identity, ok := auth.FromContext(ctx); ok {
+		author = identity.Subject
+	}
+	rev, err := s.revisions.Record(env.GetId(), env.GetSpec(), author, rollbackOf)
+	if err != nil {
+		return revisionError(err)
+	}
+	env.Revision = rev.GetNumber()
+	return nil
+}
+
+// revisionError maps revision store errors onto gRPC status codes
+func revisionError(err error) error {
+	if errors.Is(err, revision.ErrNotFound) {
+		return status.Errorf(codes.NotFound, "%v", err)
+	}
+	return status.Errorf(codes.Internal, "%v", err)
+}
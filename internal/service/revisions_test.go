@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"scheduler/internal/auth"
+	"scheduler/internal/container"
+	pb "scheduler/proto/gen"
+)
+
+func TestRevisions(t *testing.T) {
+	s := newTestService(t, &container.Fake{})
+	ctx := auth.NewContext(context.Background(), &auth.Identity{Subject: "alice", Role: auth.RoleAdmin})
+	spec := testSpec("revisions")
+	resp, err := s.CreateEnvironment(ctx, &pb.CreateEnvironmentRequest{Spec: spec})
+	if err != nil {
+		t.Fatal(err)
+	}
+	env := resp.GetEnvironment()
+
+	updated := proto.Clone(spec).(*pb.EnvironmentSpecification)
+	updated.Description = "second"
+	if _, err := s.UpdateEnvironment(ctx, &pb.UpdateEnvironmentRequest{Id: env.GetId(), Spec: updated}); err != nil {
+		t.Fatal(err)
+	}
+
+	listed, err := s.ListEnvironmentRevisions(ctx, &pb.ListEnvironmentRevisionsRequest{EnvironmentId: env.GetId()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	revisions := listed.GetRevisions()
+	if len(revisions) != 2 || revisions[0].GetNumber() != 2 || revisions[0].GetSpec().GetDescription() != "second" {
+		t.Fatalf("ListEnvironmentRevisions() = %v, want the update newest first", revisions)
+	}
+	if revisions[1].GetAuthor() != "alice" {
+		t.Errorf("author = %q, want the caller", revisions[1].GetAuthor())
+	}
+
+	rolledBack, err := s.RollbackEnvironment(ctx, &pb.RollbackEnvironmentRequest{Id: env.GetId(), Revision: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := rolledBack.GetEnvironment(); got.GetRevision() != 3 || got.GetSpec().GetDescription() != "" {
+		t.Errorf("RollbackEnvironment() = revision %d with %q, want revision 3 restoring the first", got.GetRevision(), got.GetSpec().GetDescription())
+	}
+	if _, err := s.RollbackEnvironment(ctx, &pb.RollbackEnvironmentRequest{Id: env.GetId(), Revision: 3}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("rolling back to the current revision = %v, want FailedPrecondition", err)
+	}
+	if _, err := s.GetEnvironmentRevision(ctx, &pb.GetEnvironmentRevisionRequest{EnvironmentId: env.GetId(), Number: 9}); status.Code(err) != codes.NotFound {
+		t.Errorf("GetEnvironmentRevision() of a missing revision = %v, want NotFound", err)
+	}
+}
+
+func TestRollbackRedeploysRunningEnvironment(t *testing.T) {
+	tests := []struct {
+		name string
+		// failRun fails starting the backend during the rollback
+		failRun    bool
+		wantStatus pb.EnvironmentStatus
+	}{
+		{
+			name:       "redeployed",
+			wantStatus: pb.EnvironmentStatus_ENVIRONMENT_STATUS_RUNNING,
+		},
+		{
+			name:       "failure recorded in the revision",
+			failRun:    true,
+			wantStatus: pb.EnvironmentStatus_ENVIRONMENT_STATUS_FAILED,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runtime := &container.Fake{}
+			s := newTestService(t, runtime)
+			spec := testSpec("rollback")
+			env := createEnvironment(t, s, spec)
+
+			ctx := context.Background()
+			if _, err := s.StartEnvironment(ctx, &pb.StartEnvironmentRequest{Id: env.GetId()}); err != nil {
+				t.Fatal(err)
+			}
+			updated := proto.Clone(spec).(*pb.EnvironmentSpecification)
+			updated.ApplicationStack.Backend.Container.Image = "backend:2"
+			if _, err := s.UpdateEnvironment(ctx, &pb.UpdateEnvironmentRequest{Id: env.GetId(), Spec: updated}); err != nil {
+				t.Fatal(err)
+			}
+			if tt.failRun {
+				runtime.OnRun = func(_, _ string, _ container.Spec) error { return container.ErrFake }
+			}
+			before := len(runtime.Calls())
+
+			_, err := s.RollbackEnvironment(ctx, &pb.RollbackEnvironmentRequest{Id: env.GetId(), Revision: 1})
+			if (err != nil) != tt.failRun {
+				t.Fatalf("RollbackEnvironment() error = %v, want failure %v", err, tt.failRun)
+			}
+
+			// Only the container the rollback changes is replaced
+			want := []string{"remove {id}-backend", "run {id}-backend"}
+			for i := range want {
+				want[i] = strings.ReplaceAll(want[i], "{id}", env.GetId())
+			}
+			if got := runtime.Calls()[before:]; !reflect.DeepEqual(got, want) {
+				t.Errorf("calls = %q, want %q", got, want)
+			}
+			stored, _ := s.store.Get(env.GetId())
+			if stored.GetStatus() != tt.wantStatus {
+				t.Errorf("status = %v, want %v", stored.GetStatus(), tt.wantStatus)
+			}
+			if stored.GetSpec().GetApplicationStack().GetBackend().GetContainer().GetImage() != "backend:1" {
+				t.Errorf("specification was not rolled back")
+			}
+			if !tt.failRun {
+				started, _ := runtime.Running(testNamespace, env.GetId()+"-backend")
+				if started.Image != "backend:1" {
+					t.Errorf("backend runs %s, want the rolled back image", started.Image)
+				}
+			}
+
+			rev, err := s.revisions.Get(env.GetId(), stored.GetRevision())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if rev.GetRollbackOf() != 1 {
+				t.Errorf("rollback_of = %d, want 1", rev.GetRollbackOf())
+			}
+			if failed := rev.GetDeployError() != ""; failed != tt.failRun {
+				t.Errorf("deploy_error = %q, want failure %v", rev.GetDeployError(), tt.failRun)
+			}
+		})
+	}
+}
@@ -2,81 +2,533 @@ package service
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/netip"
+	"strconv"
+	"sync"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
+	"scheduler/internal/admission"
+	"scheduler/internal/audit"
+	"scheduler/internal/backup"
+	"scheduler/internal/capacity"
+	"scheduler/internal/container"
+	"scheduler/internal/dns"
+	"scheduler/internal/network"
+	"scheduler/internal/quota"
+	"scheduler/internal/redact"
+	"scheduler/internal/revision"
+	"scheduler/internal/secrets"
+	"scheduler/internal/snapshot"
+	"scheduler/internal/stack"
+	"scheduler/internal/store"
+	"scheduler/internal/templates"
+	"scheduler/internal/tenant"
+	"scheduler/internal/volume"
 	pb "scheduler/proto/gen"
 )
 
+const (
+	defaultPageSize = 50
+	maxPageSize     = 500
+)
+
+// Options holds the dependencies of the scheduler service
+type Options struct {
+	// Runtime drives containers, nil uses container.Unavailable
+	Runtime container.Runtime
+	// Tenants holds the tenants and their containerD namespaces and quotas
+	Tenants *tenant.Registry
+	// Node is the capacity of the host, nil disables capacity checks
+	Node *capacity.Node
+	// Queue holds environments waiting for capacity, nil refuses starts
+	// that do not fit instead
+	Queue *admission.Queue
+	// Preemption lets environments stop running ones of lower priority
+	// when the host is full
+	Preemption bool
+	// HostQuota caps the resources of all environments, zero amounts are
+	// unlimited
+	HostQuota quota.Resources
+	Store     *store.Store
+	Subnets   *network.SubnetAllocator
+	// DNS runs the embedded resolvers of environment networks, nil disables them
+	DNS       *dns.Manager
+	Volumes   *volume.Manager
+	Snapshots *snapshot.Manager
+	Backups   *backup.Manager
+	// Secrets holds encrypted secrets, nil disables secret references
+	Secrets *secrets.Store
+	// Audit holds the audit log served by ListAuditEvents, nil disables it
+	Audit     *audit.Log
+	Revisions *revision.Store
+	// Templates holds the stack templates, nil serves only the built-in ones
+	Templates *templates.Registry
+	// ScriptsDir holds the SQL files database scripts may name in host_path,
+	// empty refuses scripts read from the host
+	ScriptsDir string
+}
+
 // SchedulerService implements the gRPC SchedulerService interface
 type SchedulerService struct {
 	pb.UnimplementedSchedulerServiceServer
-	// TODO: Add dependencies like containerD client, database, etc.
+
+	// mu serializes the bookkeeping of mutations, such as name, quota,
+	// capacity and network checks and assignments, so that they cannot
+	// interleave. Starting and stopping containers happens outside of it,
+	// under the environment lock from envLocks.
+	mu         sync.Mutex
+	envLocks   environmentLocks
+	queueReady chan struct{}
+	runtime    container.Runtime
+	tenants    *tenant.Registry
+	hostQuota  quota.Resources
+	node       *capacity.Node
+	queue      *admission.Queue
+	preemption bool
+	store      *store.Store
+	subnets    *network.SubnetAllocator
+	dns        *dns.Manager
+	volumes    *volume.Manager
+	// restoring holds the volumes whose data is being replaced by a
+	// snapshot, guarded by mu
+	restoring  map[string]bool
+	snapshots  *snapshot.Manager
+	backups    *backup.Manager
+	secrets    *secrets.Store
+	audit      *audit.Log
+	revisions  *revision.Store
+	templates  *templates.Registry
+	scriptsDir string
 }
 
-// NewSchedulerService creates a new instance of the scheduler service
-func NewSchedulerService() *SchedulerService {
-	return &SchedulerService{}
+// NewSchedulerService creates a new instance of the scheduler service and
+// restores the network assignments of persisted environments
+func NewSchedulerService(opts Options) (*SchedulerService, error) {
+	s := &SchedulerService{
+		queueReady: make(chan struct{}, 1),
+		runtime:    opts.Runtime,
+		tenants:    opts.Tenants,
+		hostQuota:  opts.HostQuota,
+		node:       opts.Node,
+		queue:      opts.Queue,
+		preemption: opts.Preemption,
+		store:      opts.Store,
+		subnets:    opts.Subnets,
+		dns:        opts.DNS,
+		volumes:    opts.Volumes,
+		snapshots:  opts.Snapshots,
+		backups:    opts.Backups,
+		secrets:    opts.Secrets,
+		audit:      opts.Audit,
+		revisions:  opts.Revisions,
+		templates:  opts.Templates,
+		scriptsDir: opts.ScriptsDir,
+	}
+	if s.runtime == nil {
+		s.runtime = container.Unavailable{}
+	}
+	if s.templates == nil {
+		s.templates = templates.BuiltinRegistry()
+	}
+
+	for _, env := range s.store.List() {
+		// Environments persisted before tenants existed belong to the default
+		if env.GetTenant() == "" {
+			env.Tenant = tenant.Default
+			if err := s.store.Update(env); err != nil {
+				return nil, fmt.Errorf("failed to assign environment %s to the default tenant: %w", env.GetId(), err)
+			}
+		}
+		// Environments persisted before revisions existed start their
+		// history with the current specification
+		if env.GetRevision() == 0 {
+			if err := s.recordRevision(context.Background(), env, 0); err != nil {
+				return nil, fmt.Errorf("failed to record first revision of %s: %w", env.GetId(), err)
+			}
+			if err := s.store.Update(env); err != nil {
+				return nil, fmt.Errorf("failed to store environment %s: %w", env.GetId(), err)
+			}
+		}
+		// Environments the scheduler stopped in the middle of starting,
+		// updating or stopping may have some containers left
+		switch env.GetStatus() {
+		case pb.EnvironmentStatus_ENVIRONMENT_STATUS_CREATING, pb.EnvironmentStatus_ENVIRONMENT_STATUS_UPDATING,
+			pb.EnvironmentStatus_ENVIRONMENT_STATUS_STOPPING:
+			env.Status = pb.EnvironmentStatus_ENVIRONMENT_STATUS_FAILED
+			if err := s.store.Update(env); err != nil {
+				return nil, fmt.Errorf("failed to store environment %s: %w", env.GetId(), err)
+			}
+		}
+		subnet, err := netip.ParsePrefix(env.GetSpec().GetNetwork().GetSubnet())
+		if err != nil {
+			return nil, fmt.Errorf("environment %s has invalid subnet: %w", env.GetId(), err)
+		}
+		s.subnets.Restore(env.GetId(), subnet)
+		if env.GetStatus() == pb.EnvironmentStatus_ENVIRONMENT_STATUS_RUNNING {
+			s.syncDNS(env)
+		}
+	}
+	if err := s.assignVolumeOwners(); err != nil {
+		return nil, fmt.Errorf("failed to assign volume owners: %w", err)
+	}
+	if err := s.assignSecretOwners(); err != nil {
+		return nil, fmt.Errorf("failed to assign secret owners: %w", err)
+	}
+
+	return s, nil
 }
 
 // CreateEnvironment creates a new environment based on the specification
 func (s *SchedulerService) CreateEnvironment(ctx context.Context, req *pb.CreateEnvironmentRequest) (*pb.CreateEnvironmentResponse, error) {
-	// TODO: Implement environment creation logic
-	// For now, return a stub response
-	return nil, status.Errorf(codes.Unimplemented, "CreateEnvironment not yet implemented")
+	spec := req.GetSpec()
+	if err := validateSpec(spec); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid specification: %v", err)
+	}
+	owner, err := s.resolveTenant(ctx, req.GetTenant())
+	if err != nil {
+		return nil, err
+	}
+	if err := checkScope(ctx, owner.Name, spec.GetLabels()); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	env, err := s.createEnvironment(ctx, owner, spec, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.CreateEnvironmentResponse{Environment: env}, nil
+}
+
+// createEnvironment stores a new environment of owner with a validated
+// specification, recording the template it was expanded from if any. The
+// caller holds s.mu.
+func (s *SchedulerService) createEnvironment(ctx context.Context, owner tenant.Tenant, spec *pb.EnvironmentSpecification, from *pb.TemplateReference) (*pb.Environment, error) {
+	id, err := newEnvironmentID()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to generate environment ID: %v", err)
+	}
+
+	now := timestamppb.Now()
+	env := &pb.Environment{
+		Id:        id,
+		Name:      spec.GetName(),
+		Spec:      spec,
+		Status:    pb.EnvironmentStatus_ENVIRONMENT_STATUS_PENDING,
+		CreatedAt: now,
+		UpdatedAt: now,
+		Tenant:    owner.Name,
+		Template:  from,
+	}
+	if err := s.checkAdmissible(ctx, env); err != nil {
+		return nil, err
+	}
+
+	if err := s.assignSubnet(env); err != nil {
+		return nil, err
+	}
+	if err := s.assignAddresses(env); err != nil {
+		s.subnets.Release(id)
+		return nil, err
+	}
+	if err := s.ensureVolumes(ctx, env); err != nil {
+		s.subnets.Release(id)
+		return nil, err
+	}
+	if err := s.recordRevision(ctx, env, 0); err != nil {
+		s.subnets.Release(id)
+		return nil, err
+	}
+	if err := s.store.Create(env); err != nil {
+		s.subnets.Release(id)
+		s.revisions.Delete(id)
+		return nil, status.Errorf(codes.Internal, "failed to store environment: %v", err)
+	}
+
+	return env, nil
 }
 
 // GetEnvironment retrieves an environment by ID
 func (s *SchedulerService) GetEnvironment(ctx context.Context, req *pb.GetEnvironmentRequest) (*pb.GetEnvironmentResponse, error) {
-	// TODO: Implement environment retrieval logic
-	return nil, status.Errorf(codes.Unimplemented, "GetEnvironment not yet implemented")
+	env, err := s.getEnvironment(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetEnvironmentResponse{Environment: s.withQueuePosition(env)}, nil
 }
 
-// UpdateEnvironment updates an existing environment
+// UpdateEnvironment updates an existing environment, recording the new
+// specification as its next revision. The containers of a running
+// environment are reconciled right away, as PlanEnvironmentUpdate reports;
+// other environments use the specification when they next start.
 func (s *SchedulerService) UpdateEnvironment(ctx context.Context, req *pb.UpdateEnvironmentRequest) (*pb.UpdateEnvironmentResponse, error) {
-	// TODO: Implement environment update logic
-	return nil, status.Errorf(codes.Unimplemented, "UpdateEnvironment not yet implemented")
+	spec := req.GetSpec()
+	if err := validateSpec(spec); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid specification: %v", err)
+	}
+
+	unlock := s.lockEnvironment(req.GetId())
+	defer unlock()
+	s.mu.Lock()
+	env, err := s.getEnvironment(ctx, req.GetId())
+	previous := env.GetSpec()
+	if err == nil {
+		err = s.applySpec(ctx, env, spec, 0)
+	}
+	s.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.deployRevision(ctx, env, previous); err != nil {
+		return nil, err
+	}
+	return &pb.UpdateEnvironmentResponse{Environment: s.withQueuePosition(env)}, nil
 }
 
-// DeleteEnvironment deletes an environment by ID
+// applySpec replaces the specification of an environment and records it as
+// a new revision. rollbackOf names the revision a rollback restores, 0
+// otherwise. It must be called with the environment lock and s.mu held.
+func (s *SchedulerService) applySpec(ctx context.Context, env *pb.Environment, spec *pb.EnvironmentSpecification, rollbackOf int32) error {
+	if err := checkScope(ctx, env.GetTenant(), spec.GetLabels()); err != nil {
+		return err
+	}
+	previousSubnet, _ := netip.ParsePrefix(env.GetSpec().GetNetwork().GetSubnet())
+	previousSpec, previousName := env.Spec, env.Name
+
+	env.Spec = spec
+	env.Name = spec.GetName()
+	if err := s.checkAdmissible(ctx, env); err != nil {
+		env.Spec, env.Name = previousSpec, previousName
+		return err
+	}
+	env.UpdatedAt = timestamppb.Now()
+
+	if err := s.assignSubnet(env); err != nil {
+		return err
+	}
+	if err := s.assignAddresses(env); err != nil {
+		s.subnets.Restore(env.GetId(), previousSubnet)
+		return err
+	}
+	if err := s.ensureVolumes(ctx, env); err != nil {
+		s.subnets.Restore(env.GetId(), previousSubnet)
+		return err
+	}
+	if err := s.recordRevision(ctx, env, rollbackOf); err != nil {
+		s.subnets.Restore(env.GetId(), previousSubnet)
+		return err
+	}
+	if err := s.store.Update(env); err != nil {
+		s.subnets.Restore(env.GetId(), previousSubnet)
+		return status.Errorf(codes.Internal, "failed to store environment: %v", err)
+	}
+	if env.GetStatus() == pb.EnvironmentStatus_ENVIRONMENT_STATUS_RUNNING {
+		s.syncDNS(env)
+	}
+
+	// A queued environment moves with its priority. A smaller specification
+	// may make room for queued ones, which the admission queue picks up once
+	// the environment lock is released.
+	if s.withQueuePosition(env).GetQueuePosition() > 0 {
+		if err := s.queue.Push(env.GetId(), spec.GetPriority()); err != nil {
+			return status.Errorf(codes.Internal, "%v", err)
+		}
+	}
+	return nil
+}
+
+// DeleteEnvironment deletes an environment by ID, stopping its containers
+// first. Its revisions and database backups go with it. Named volumes are
+// kept unless the request asks for the ones the environment created to be
+// purged.
 func (s *SchedulerService) DeleteEnvironment(ctx context.Context, req *pb.DeleteEnvironmentRequest) (*pb.DeleteEnvironmentResponse, error) {
-	// TODO: Implement environment deletion logic
-	return nil, status.Errorf(codes.Unimplemented, "DeleteEnvironment not yet implemented")
+	unlock := s.lockEnvironment(req.GetId())
+	defer unlock()
+
+	env, err := s.getEnvironment(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	if req.GetPurgeVolumes() {
+		if err := s.checkPurgeable(env); err != nil {
+			return nil, err
+		}
+	}
+	switch env.GetStatus() {
+	case pb.EnvironmentStatus_ENVIRONMENT_STATUS_RUNNING, pb.EnvironmentStatus_ENVIRONMENT_STATUS_FAILED:
+		if err := s.stopContainers(ctx, env, stopTimeout); err != nil {
+			return nil, err
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.dequeue(env); err != nil {
+		return nil, err
+	}
+	if err := s.store.Delete(env.GetId()); err != nil {
+		return nil, storeError(err)
+	}
+	s.subnets.Release(env.GetId())
+	if err := s.revisions.Delete(env.GetId()); err != nil {
+		log.Printf("Failed to delete revisions of %s: %v", env.GetId(), err)
+	}
+	if s.backups != nil {
+		if err := s.backups.DeleteAll(env.GetId()); err != nil {
+			log.Printf("Failed to delete backups of %s: %v", env.GetId(), err)
+		}
+	}
+	s.removeDNS(env)
+	if req.GetPurgeVolumes() {
+		s.purgeVolumes(env)
+	}
+	s.envLocks.forget(env.GetId())
+
+	return &pb.DeleteEnvironmentResponse{Success: true}, nil
 }
 
-// ListEnvironments lists all environments with pagination
+// ListEnvironments lists the environments of the caller's tenant with
+// pagination. Callers not bound to a tenant see every tenant unless the
+// request names one.
 func (s *SchedulerService) ListEnvironments(ctx context.Context, req *pb.ListEnvironmentsRequest) (*pb.ListEnvironmentsResponse, error) {
-	// TODO: Implement environment listing logic
-	return nil, status.Errorf(codes.Unimplemented, "ListEnvironments not yet implemented")
+	pageSize := int(req.GetPageSize())
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+
+	offset := 0
+	if req.GetPageToken() != "" {
+		parsed, err := strconv.Atoi(req.GetPageToken())
+		if err != nil || parsed < 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid page token %q", req.GetPageToken())
+		}
+		offset = parsed
+	}
+
+	var matched []*pb.Environment
+	for _, env := range s.store.List() {
+		if req.GetTenant() != "" && env.GetTenant() != req.GetTenant() {
+			continue
+		}
+		if matchesLabels(env.GetSpec().GetLabels(), req.GetFilters()) && inScope(ctx, env.GetTenant(), env.GetSpec().GetLabels()) {
+			matched = append(matched, env)
+		}
+	}
+
+	resp := &pb.ListEnvironmentsResponse{TotalCount: int32(len(matched))}
+	if offset >= len(matched) {
+		return resp, nil
+	}
+	end := min(offset+pageSize, len(matched))
+	resp.Environments = matched[offset:end]
+	for _, env := range resp.Environments {
+		s.withQueuePosition(env)
+	}
+	if end < len(matched) {
+		resp.NextPageToken = strconv.Itoa(end)
+	}
+	return resp, nil
 }
 
-// StartEnvironment starts an existing environment
-func (s *SchedulerService) StartEnvironment(ctx context.Context, req *pb.StartEnvironmentRequest) (*pb.StartEnvironmentResponse, error) {
-	// TODO: Implement environment start logic
-	return nil, status.Errorf(codes.Unimplemented, "StartEnvironment not yet implemented")
+// GetEnvironmentStatus retrieves the current status of an environment and
+// its containers as last recorded. Container metrics are left empty until
+// the runtime reports them.
+func (s *SchedulerService) GetEnvironmentStatus(ctx context.Context, req *pb.GetEnvironmentStatusRequest) (*pb.GetEnvironmentStatusResponse, error) {
+	env, err := s.getEnvironment(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetEnvironmentStatusResponse{Environment: s.withQueuePosition(env)}, nil
 }
 
-// StopEnvironment stops a running environment
-func (s *SchedulerService) StopEnvironment(ctx context.Context, req *pb.StopEnvironmentRequest) (*pb.StopEnvironmentResponse, error) {
-	// TODO: Implement environment stop logic
-	return nil, status.Errorf(codes.Unimplemented, "StopEnvironment not yet implemented")
+// getEnvironment loads an environment the caller may access from the store,
+// translating store errors into gRPC status errors
+func (s *SchedulerService) getEnvironment(ctx context.Context, id string) (*pb.Environment, error) {
+	if id == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "environment ID is required")
+	}
+	env, err := s.store.Get(id)
+	if err != nil {
+		return nil, storeError(err)
+	}
+	if err := checkScope(ctx, env.GetTenant(), env.GetSpec().GetLabels()); err != nil {
+		return nil, err
+	}
+	return env, nil
 }
 
-// RestartEnvironment restarts an environment
-func (s *SchedulerService) RestartEnvironment(ctx context.Context, req *pb.RestartEnvironmentRequest) (*pb.RestartEnvironmentResponse, error) {
-	// TODO: Implement environment restart logic
-	return nil, status.Errorf(codes.Unimplemented, "RestartEnvironment not yet implemented")
+// validateSpec checks the parts of a specification the scheduler relies on
+func validateSpec(spec *pb.EnvironmentSpecification) error {
+	if spec == nil {
+		return errors.New("spec is required")
+	}
+	if spec.GetName() == "" {
+		return errors.New("name is required")
+	}
+	if path := redact.Masked(spec); path != "" {
+		return fmt.Errorf("%s holds the redaction mask %q instead of a value or secret reference", path, redact.Mask)
+	}
+	if err := stack.Validate(spec.GetApplicationStack()); err != nil {
+		return err
+	}
+	for _, name := range namedVolumes(spec) {
+		if err := volume.ValidateName(name); err != nil {
+			return err
+		}
+	}
+	if err := validateSecretReferences(spec); err != nil {
+		return err
+	}
+	if err := validateDatabaseSteps(spec.GetApplicationStack().GetDatabase()); err != nil {
+		return err
+	}
+	if err := validateHooks(spec); err != nil {
+		return err
+	}
+	policy := spec.GetApplicationStack().GetDatabase().GetBackupPolicy()
+	return validateSchedule("backup", policy.GetIntervalSeconds(), policy.GetRetain())
 }
 
-// GetEnvironmentStatus retrieves the current status of an environment
-func (s *SchedulerService) GetEnvironmentStatus(ctx context.Context, req *pb.GetEnvironmentStatusRequest) (*pb.GetEnvironmentStatusResponse, error) {
-	// TODO: Implement environment status retrieval logic
-	return nil, status.Errorf(codes.Unimplemented, "GetEnvironmentStatus not yet implemented")
+// matchesLabels reports whether labels contain every key/value pair in filters
+func matchesLabels(labels, filters map[string]string) bool {
+	for key, value := range filters {
+		if labels[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// newEnvironmentID generates a random environment identifier
+func newEnvironmentID() (string, error) {
+	buf := make([]byte, 6)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return "env-" + hex.EncodeToString(buf), nil
 }
 
-// GetEnvironmentLogs streams logs from an environment
-func (s *SchedulerService) GetEnvironmentLogs(req *pb.GetEnvironmentLogsRequest, stream pb.SchedulerService_GetEnvironmentLogsServer) error {
-	// TODO: Implement log streaming logic
-	return status.Errorf(codes.Unimplemented, "GetEnvironmentLogs not yet implemented")
+// storeError maps store errors onto gRPC status codes
+func storeError(err error) error {
+	switch {
+	case errors.Is(err, store.ErrNotFound):
+		return status.Errorf(codes.NotFound, "%v", err)
+	case errors.Is(err, store.ErrAlreadyExists):
+		return status.Errorf(codes.AlreadyExists, "%v", err)
+	default:
+		return status.Errorf(codes.Internal, "%v", err)
+	}
 }
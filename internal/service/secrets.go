@@ -0,0 +1,279 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"scheduler/internal/secrets"
+	"scheduler/internal/stack"
+	"scheduler/internal/tenant"
+	pb "scheduler/proto/gen"
+)
+
+// CreateSecret stores a new secret, encrypted at rest, owned by the requested
+// tenant or the caller's
+func (s *SchedulerService) CreateSecret(ctx context.Context, req *pb.CreateSecretRequest) (*pb.CreateSecretResponse, error) {
+	if s.secrets == nil {
+		return nil, errSecretsDisabled
+	}
+	if err := secrets.ValidateName(req.GetName()); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	if len(req.GetValue()) == 0 || len(req.GetValue()) > secrets.MaxValueSize {
+		return nil, status.Errorf(codes.InvalidArgument, "secret value must be between 1 and %d bytes", secrets.MaxValueSize)
+	}
+	owner, err := s.resolveTenant(ctx, req.GetTenant())
+	if err != nil {
+		return nil, err
+	}
+	if err := checkScope(ctx, owner.Name, req.GetLabels()); err != nil {
+		return nil, err
+	}
+
+	created, err := s.secrets.Create(req.GetName(), owner.Name, req.GetValue(), req.GetLabels())
+	if err != nil {
+		return nil, secretError(err)
+	}
+	return &pb.CreateSecretResponse{Secret: created}, nil
+}
+
+// ListSecrets lists the metadata of the secrets of the caller's tenant
+// matching the label filters. Callers not bound to a tenant see every tenant
+// unless the request names one.
+func (s *SchedulerService) ListSecrets(ctx context.Context, req *pb.ListSecretsRequest) (*pb.ListSecretsResponse, error) {
+	if s.secrets == nil {
+		return nil, errSecretsDisabled
+	}
+
+	listed, err := s.secrets.List()
+	if err != nil {
+		return nil, secretError(err)
+	}
+
+	resp := &pb.ListSecretsResponse{}
+	for _, secret := range listed {
+		if req.GetTenant() != "" && secret.GetTenant() != req.GetTenant() {
+			continue
+		}
+		if !matchesLabels(secret.GetLabels(), req.GetFilters()) || !inScope(ctx, secret.GetTenant(), secret.GetLabels()) {
+			continue
+		}
+		secret.UsedBy = s.secretUsers(secret.GetName())
+		resp.Secrets = append(resp.Secrets, secret)
+	}
+	return resp, nil
+}
+
+// DeleteSecret deletes a secret that no environment references
+func (s *SchedulerService) DeleteSecret(ctx context.Context, req *pb.DeleteSecretRequest) (*pb.DeleteSecretResponse, error) {
+	if s.secrets == nil {
+		return nil, errSecretsDisabled
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	found, err := s.secrets.Get(req.GetName())
+	if err != nil {
+		return nil, secretError(err)
+	}
+	if err := checkScope(ctx, found.GetTenant(), found.GetLabels()); err != nil {
+		return nil, err
+	}
+	if users := s.secretUsers(req.GetName()); len(users) > 0 {
+		return nil, status.Errorf(codes.FailedPrecondition, "secret %s is in use by environments %s", req.GetName(), strings.Join(users, ", "))
+	}
+	if err := s.secrets.Delete(req.GetName()); err != nil {
+		return nil, secretError(err)
+	}
+	return &pb.DeleteSecretResponse{Success: true}, nil
+}
+
+// errSecretsDisabled is returned by secret operations when no secrets key is
+// configured
+var errSecretsDisabled = status.Errorf(codes.FailedPrecondition, "secrets are not configured, set secrets.key_file")
+
+// checkSecrets verifies that every secret a specification of an environment
+// of tenantName references exists, belongs to the same tenant and is within
+// the caller's scope
+func (s *SchedulerService) checkSecrets(ctx context.Context, tenantName string, spec *pb.EnvironmentSpecification) error {
+	names := secretReferences(spec)
+	if len(names) == 0 {
+		return nil
+	}
+	if s.secrets == nil {
+		return errSecretsDisabled
+	}
+	for _, name := range names {
+		found, err := s.secrets.Get(name)
+		if errors.Is(err, secrets.ErrNotFound) {
+			return status.Errorf(codes.FailedPrecondition, "referenced %v", err)
+		}
+		if err != nil {
+			return secretError(err)
+		}
+		if found.GetTenant() != tenantName {
+			return status.Errorf(codes.PermissionDenied, "referenced %v: %s", secrets.ErrOtherTenant, name)
+		}
+		if err := checkScope(ctx, found.GetTenant(), found.GetLabels()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolveSecret returns value, or the value of the secret it refers to when
+// it is a secret://<name> reference
+func (s *SchedulerService) resolveSecret(env *pb.Environment, value string) (string, error) {
+	name, ok := secrets.ParseReference(value)
+	if !ok {
+		return value, nil
+	}
+	resolved, err := s.secretValue(env, name)
+	if err != nil {
+		return "", err
+	}
+	return string(resolved), nil
+}
+
+// secretValue decrypts the value of a secret of the environment's tenant
+func (s *SchedulerService) secretValue(env *pb.Environment, name string) ([]byte, error) {
+	if s.secrets == nil {
+		return nil, errSecretsDisabled
+	}
+	value, err := s.secrets.Value(name, env.GetTenant())
+	if errors.Is(err, secrets.ErrNotFound) {
+		return nil, status.Errorf(codes.FailedPrecondition, "referenced %v", err)
+	}
+	if errors.Is(err, secrets.ErrOtherTenant) {
+		return nil, status.Errorf(codes.PermissionDenied, "referenced %v", err)
+	}
+	if err != nil {
+		return nil, secretError(err)
+	}
+	return value, nil
+}
+
+// assignSecretOwners gives secrets created before secrets had owners to the
+// tenant of an environment referencing them, or to the default tenant
+func (s *SchedulerService) assignSecretOwners() error {
+	if s.secrets == nil {
+		return nil
+	}
+	listed, err := s.secrets.List()
+	if err != nil {
+		return err
+	}
+	for _, secret := range listed {
+		if secret.GetTenant() != "" {
+			continue
+		}
+		owner := tenant.Default
+		if users := s.secretUsers(secret.GetName()); len(users) > 0 {
+			if env, err := s.store.Get(users[0]); err == nil {
+				owner = env.GetTenant()
+			}
+		}
+		if _, err := s.secrets.Assign(secret.GetName(), owner); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// secretUsers returns the IDs of the environments that reference a secret
+func (s *SchedulerService) secretUsers(name string) []string {
+	var users []string
+	for _, env := range s.store.List() {
+		for _, referenced := range secretReferences(env.GetSpec()) {
+			if referenced == name {
+				users = append(users, env.GetId())
+				break
+			}
+		}
+	}
+	return users
+}
+
+// secretReferences returns the sorted, de-duplicated names of the secrets a
+// specification refers to
+func secretReferences(spec *pb.EnvironmentSpecification) []string {
+	seen := make(map[string]bool)
+	var names []string
+	add := func(name string) {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	addReference := func(value string) {
+		if name, ok := secrets.ParseReference(value); ok {
+			add(name)
+		}
+	}
+
+	applicationStack := spec.GetApplicationStack()
+	addReference(applicationStack.GetDatabase().GetPassword())
+	addReference(applicationStack.GetBackend().GetDatabaseConnectionString())
+	for _, value := range applicationStack.GetBackend().GetApiKeys() {
+		addReference(value)
+	}
+	for _, value := range applicationStack.GetDatabase().GetMigration().GetEnvironmentVariables() {
+		addReference(value)
+	}
+	for _, hooks := range [][]*pb.HookContainer{spec.GetHooks().GetPreDeploy(), spec.GetHooks().GetPostDeploy()} {
+		for _, hook := range hooks {
+			for _, value := range hook.GetEnvironmentVariables() {
+				addReference(value)
+			}
+		}
+	}
+	for _, member := range stack.Members(applicationStack) {
+		for _, value := range member.Container.GetEnvironmentVariables() {
+			addReference(value)
+		}
+		for _, file := range member.Container.GetSecretFiles() {
+			add(file.GetSecret())
+		}
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// validateSecretReferences checks the secret references and file paths of a
+// specification
+func validateSecretReferences(spec *pb.EnvironmentSpecification) error {
+	for _, name := range secretReferences(spec) {
+		if err := secrets.ValidateName(name); err != nil {
+			return err
+		}
+	}
+	for _, member := range stack.Members(spec.GetApplicationStack()) {
+		for _, file := range member.Container.GetSecretFiles() {
+			if !path.IsAbs(file.GetPath()) || path.Clean(file.GetPath()) == "/" {
+				return fmt.Errorf("%s: secret file path %q must be an absolute file path", member, file.GetPath())
+			}
+		}
+	}
+	return nil
+}
+
+// secretError maps secret store errors onto gRPC status codes
+func secretError(err error) error {
+	switch {
+	case errors.Is(err, secrets.ErrNotFound):
+		return status.Errorf(codes.NotFound, "%v", err)
+	case errors.Is(err, secrets.ErrAlreadyExists):
+		return status.Errorf(codes.AlreadyExists, "%v", err)
+	default:
+		return status.Errorf(codes.Internal, "%v", err)
+	}
+}
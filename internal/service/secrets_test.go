@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"scheduler/internal/auth"
+	"scheduler/internal/container"
+	pb "scheduler/proto/gen"
+)
+
+func TestSecretReferences(t *testing.T) {
+	runtime := &container.Fake{}
+	s := newTestService(t, runtime)
+	ctx := context.Background()
+
+	for name, value := range map[string]string{"db-url": "postgres://app@database/app", "api-key": "k3y", "tls-key": "PEM"} {
+		if _, err := s.CreateSecret(ctx, &pb.CreateSecretRequest{Name: name, Value: []byte(value)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	spec := testSpec("secrets")
+	backend := spec.GetApplicationStack().GetBackend()
+	backend.DatabaseConnectionString = "secret://db-url"
+	backend.ApiKeys = map[string]string{"PAYMENTS_KEY": "secret://api-key"}
+	backend.Container.EnvironmentVariables = map[string]string{"LOG_LEVEL": "debug"}
+	backend.Container.SecretFiles = []*pb.SecretFile{{Secret: "tls-key", Path: "/run/tls.key"}}
+
+	missing := testSpec("missing")
+	missing.GetApplicationStack().GetBackend().ApiKeys = map[string]string{"KEY": "secret://missing"}
+	if _, err := s.CreateEnvironment(ctx, &pb.CreateEnvironmentRequest{Spec: missing}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("creating an environment referencing a missing secret = %v, want FailedPrecondition", err)
+	}
+
+	env := createEnvironment(t, s, spec)
+	if _, err := s.StartEnvironment(ctx, &pb.StartEnvironmentRequest{Id: env.GetId()}); err != nil {
+		t.Fatal(err)
+	}
+
+	// References are resolved only in the container, the stored
+	// specification keeps them
+	started, ok := runtime.Running(testNamespace, env.GetId()+"-backend")
+	if !ok {
+		t.Fatal("backend is not running")
+	}
+	wantEnv := map[string]string{
+		"DATABASE_URL": "postgres://app@database/app",
+		"PAYMENTS_KEY": "k3y",
+		"LOG_LEVEL":    "debug",
+		"PGPASSWORD":   "secret",
+	}
+	for key, want := range wantEnv {
+		if got := started.Env[key]; got != want {
+			t.Errorf("backend %s = %q, want %q", key, got, want)
+		}
+	}
+	wantFiles := []container.File{{Path: "/run/tls.key", Data: []byte("PEM")}}
+	if !reflect.DeepEqual(started.Files, wantFiles) {
+		t.Errorf("backend files = %v, want %v", started.Files, wantFiles)
+	}
+	stored, err := s.GetEnvironment(ctx, &pb.GetEnvironmentRequest{Id: env.GetId()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := stored.GetEnvironment().GetSpec().GetApplicationStack().GetBackend().GetDatabaseConnectionString(); got != "secret://db-url" {
+		t.Errorf("stored connection string = %q, want the reference", got)
+	}
+
+	// Secrets in use cannot be deleted
+	listed, err := s.ListSecrets(ctx, &pb.ListSecretsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range listed.GetSecrets() {
+		if !reflect.DeepEqual(secret.GetUsedBy(), []string{env.GetId()}) {
+			t.Errorf("secret %s is used by %v, want [%s]", secret.GetName(), secret.GetUsedBy(), env.GetId())
+		}
+	}
+	if _, err := s.DeleteSecret(ctx, &pb.DeleteSecretRequest{Name: "api-key"}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("deleting a secret in use = %v, want FailedPrecondition", err)
+	}
+	if _, err := s.DeleteEnvironment(ctx, &pb.DeleteEnvironmentRequest{Id: env.GetId()}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.DeleteSecret(ctx, &pb.DeleteSecretRequest{Name: "api-key"}); err != nil {
+		t.Errorf("deleting an unused secret = %v", err)
+	}
+}
+
+func TestSecretsDisabled(t *testing.T) {
+	s := newTestService(t, &container.Fake{}, func(opts *Options) { opts.Secrets = nil })
+	ctx := context.Background()
+
+	if _, err := s.CreateSecret(ctx, &pb.CreateSecretRequest{Name: "a", Value: []byte("x")}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("CreateSecret() without a key = %v, want FailedPrecondition", err)
+	}
+	spec := testSpec("references")
+	spec.GetApplicationStack().GetDatabase().Password = "secret://db-password"
+	if _, err := s.CreateEnvironment(ctx, &pb.CreateEnvironmentRequest{Spec: spec}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("referencing a secret without a key = %v, want FailedPrecondition", err)
+	}
+	// Specifications without references work as before
+	createEnvironment(t, s, testSpec("plain"))
+}
+
+func TestSecretTenants(t *testing.T) {
+	s := newTestService(t, &container.Fake{}, withTeamA(t))
+	ctx := context.Background()
+	teamA := auth.NewContext(ctx, &auth.Identity{Subject: "ci", Role: auth.RoleAdmin, Tenant: "team-a"})
+
+	if _, err := s.CreateSecret(ctx, &pb.CreateSecretRequest{Name: "shared", Value: []byte("x")}); err != nil {
+		t.Fatal(err)
+	}
+	created, err := s.CreateSecret(teamA, &pb.CreateSecretRequest{Name: "own", Value: []byte("y")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.GetSecret().GetTenant() != "team-a" {
+		t.Errorf("tenant = %q, want the caller's", created.GetSecret().GetTenant())
+	}
+
+	listed, err := s.ListSecrets(teamA, &pb.ListSecretsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if secrets := listed.GetSecrets(); len(secrets) != 1 || secrets[0].GetName() != "own" {
+		t.Errorf("ListSecrets() = %v, want only the secret of team-a", secrets)
+	}
+	if _, err := s.DeleteSecret(teamA, &pb.DeleteSecretRequest{Name: "shared"}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("DeleteSecret() of another tenant = %v, want PermissionDenied", err)
+	}
+
+	// Specifications may only reference secrets of their own tenant
+	spec := testSpec("references")
+	spec.GetApplicationStack().GetDatabase().Password = "secret://shared"
+	if _, err := s.CreateEnvironment(teamA, &pb.CreateEnvironmentRequest{Spec: spec}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("referencing a secret of another tenant = %v, want PermissionDenied", err)
+	}
+	spec.GetApplicationStack().GetDatabase().Password = "secret://own"
+	if _, err := s.CreateEnvironment(teamA, &pb.CreateEnvironmentRequest{Spec: spec}); err != nil {
+		t.Errorf("referencing a secret of the same tenant = %v", err)
+	}
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+
+	"scheduler/internal/container"
+	"scheduler/internal/network"
+	"scheduler/internal/revision"
+	"scheduler/internal/secrets"
+	"scheduler/internal/store"
+	"scheduler/internal/tenant"
+	"scheduler/internal/volume"
+	pb "scheduler/proto/gen"
+)
+
+// testNamespace is the containerD namespace of the default tenant in tests
+const testNamespace = "test"
+
+// newTestService returns a service that drives the fake runtime, with its
+// state in a temporary directory. configure may set further options.
+func newTestService(t *testing.T, runtime *container.Fake, configure ...func(*Options)) *SchedulerService {
+	t.Helper()
+	dir := t.TempDir()
+
+	environments, err := store.New(filepath.Join(dir, "environments"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	subnets, err := network.NewSubnetAllocator([]string{"10.200.0.0/16"}, 24)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tenants, err := tenant.NewRegistry(nil, testNamespace, 24)
+	if err != nil {
+		t.Fatal(err)
+	}
+	volumes, err := volume.NewManager(filepath.Join(dir, "volumes"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	revisions, err := revision.NewStore(filepath.Join(dir, "revisions"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	secretStore, err := secrets.NewStore(filepath.Join(dir, "secrets"), bytes.Repeat([]byte{1}, secrets.KeySize))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	opts := Options{
+		Runtime:   runtime,
+		Tenants:   tenants,
+		Store:     environments,
+		Subnets:   subnets,
+		Volumes:   volumes,
+		Secrets:   secretStore,
+		Revisions: revisions,
+	}
+	for _, apply := range configure {
+		apply(&opts)
+	}
+	s, err := NewSchedulerService(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// createEnvironment creates an environment from spec and fails the test on
+// errors
+func createEnvironment(t *testing.T, s *SchedulerService, spec *pb.EnvironmentSpecification) *pb.Environment {
+	t.Helper()
+	resp, err := s.CreateEnvironment(context.Background(), &pb.CreateEnvironmentRequest{Spec: spec})
+	if err != nil {
+		t.Fatalf("CreateEnvironment() error = %v", err)
+	}
+	return resp.GetEnvironment()
+}
+
+// testSpec returns a specification with a database, a backend and a
+// frontend
+func testSpec(name string) *pb.EnvironmentSpecification {
+	return &pb.EnvironmentSpecification{
+		Name: name,
+		ApplicationStack: &pb.ApplicationStack{
+			Database: &pb.DatabaseConfig{
+				Container:    &pb.ContainerConfig{Image: "postgres:16"},
+				DatabaseName: "app",
+				Username:     "app",
+				Password:     "secret",
+			},
+			Backend:  &pb.BackendConfig{Container: &pb.ContainerConfig{Image: "backend:1"}},
+			Frontend: &pb.FrontendConfig{Container: &pb.ContainerConfig{Image: "frontend:1"}},
+		},
+	}
+}
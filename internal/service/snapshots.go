@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"scheduler/internal/snapshot"
+	"scheduler/internal/volume"
+	pb "scheduler/proto/gen"
+)
+
+// minScheduleInterval matches how often snapshot and backup schedules are
+// evaluated
+const minScheduleInterval = 60
+
+// restoredFromLabel records the snapshot a restored volume was created from
+const restoredFromLabel = "scheduler/restored-from"
+
+// SnapshotVolume takes a snapshot of a named volume
+func (s *SchedulerService) SnapshotVolume(ctx context.Context, req *pb.SnapshotVolumeRequest) (*pb.SnapshotVolumeResponse, error) {
+	if req.GetVolumeName() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "volume name is required")
+	}
+	if _, err := s.getVolume(ctx, req.GetVolumeName()); err != nil {
+		return nil, err
+	}
+
+	created, err := s.snapshots.Create(req.GetVolumeName(), req.GetMethod(), req.GetLabels(), false)
+	if err != nil {
+		return nil, snapshotError(err)
+	}
+	return &pb.SnapshotVolumeResponse{Snapshot: created}, nil
+}
+
+// ListVolumeSnapshots lists the snapshots of a volume, oldest first
+func (s *SchedulerService) ListVolumeSnapshots(ctx context.Context, req *pb.ListVolumeSnapshotsRequest) (*pb.ListVolumeSnapshotsResponse, error) {
+	if _, err := s.getVolume(ctx, req.GetVolumeName()); err != nil {
+		return nil, err
+	}
+	snapshots, err := s.snapshots.List(req.GetVolumeName())
+	if err != nil {
+		return nil, snapshotError(err)
+	}
+	return &pb.ListVolumeSnapshotsResponse{Snapshots: snapshots}, nil
+}
+
+// RestoreVolume restores a snapshot in place, or into a new volume when a
+// target volume name is given. In-place restores require every environment
+// mounting the volume to be stopped.
+func (s *SchedulerService) RestoreVolume(ctx context.Context, req *pb.RestoreVolumeRequest) (*pb.RestoreVolumeResponse, error) {
+	if req.GetVolumeName() == "" || req.GetSnapshotId() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "volume name and snapshot ID are required")
+	}
+
+	target := req.GetVolumeName()
+	if req.GetTargetVolumeName() != "" {
+		target = req.GetTargetVolumeName()
+	}
+	inPlace := target == req.GetVolumeName()
+	// The environments mounting a volume restored in place stay locked until
+	// the data is swapped in, so that none of them starts on it halfway
+	var users []string
+	if inPlace {
+		users = s.volumeUsers(target)
+	}
+	unlock := s.lockEnvironments(users)
+	defer unlock()
+
+	if err := s.prepareRestore(ctx, req, target, users); err != nil {
+		return nil, err
+	}
+	defer func() {
+		s.mu.Lock()
+		delete(s.restoring, target)
+		s.mu.Unlock()
+	}()
+
+	if err := s.snapshots.Restore(req.GetVolumeName(), req.GetSnapshotId(), target); err != nil {
+		// A volume created for the restore would be left half-written
+		if !inPlace {
+			s.mu.Lock()
+			if deleteErr := s.volumes.Delete(target); deleteErr != nil {
+				log.Printf("Failed to remove volume %s after failed restore: %v", target, deleteErr)
+			}
+			s.mu.Unlock()
+		}
+		return nil, snapshotError(err)
+	}
+
+	restored, err := s.volumes.Get(target)
+	if err != nil {
+		return nil, volumeError(err)
+	}
+	if err := s.measureVolume(restored); err != nil {
+		return nil, err
+	}
+	restored.UsedBy = s.volumeUsers(target)
+	return &pb.RestoreVolumeResponse{Volume: restored}, nil
+}
+
+// prepareRestore checks that a snapshot can be restored into target,
+// creating the target volume when it is not the snapshotted one, and marks
+// target as being restored. users are the locked environments mounting a
+// volume restored in place.
+func (s *SchedulerService) prepareRestore(ctx context.Context, req *pb.RestoreVolumeRequest, target string, users []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	source, err := s.getVolume(ctx, req.GetVolumeName())
+	if err != nil {
+		return err
+	}
+	if _, err := s.snapshots.Get(req.GetVolumeName(), req.GetSnapshotId()); err != nil {
+		return snapshotError(err)
+	}
+	if s.restoring[target] {
+		return status.Errorf(codes.FailedPrecondition, "volume %s is already being restored", target)
+	}
+
+	if target == req.GetVolumeName() {
+		current := s.volumeUsers(target)
+		slices.Sort(current)
+		if !slices.Equal(current, slices.Sorted(slices.Values(users))) {
+			return status.Errorf(codes.Aborted, "environments mounting volume %s changed while restoring, try again", target)
+		}
+		if err := s.checkVolumeIdle(target); err != nil {
+			return err
+		}
+	} else {
+		// The new volume belongs to the tenant of the source and keeps its
+		// labels so that it stays within the same scope, but was not created
+		// by the environment of the source
+		labels := make(map[string]string, len(source.GetLabels())+1)
+		for key, value := range source.GetLabels() {
+			labels[key] = value
+		}
+		delete(labels, environmentLabel)
+		labels[restoredFromLabel] = req.GetVolumeName() + "." + req.GetSnapshotId()
+		if _, err := s.volumes.Create(target, source.GetTenant(), labels, nil); err != nil {
+			return volumeError(err)
+		}
+	}
+	if s.restoring == nil {
+		s.restoring = make(map[string]bool)
+	}
+	s.restoring[target] = true
+	return nil
+}
+
+// checkVolumeIdle fails when an environment that mounts the volume may have
+// containers running, failed ones included until they are stopped
+func (s *SchedulerService) checkVolumeIdle(name string) error {
+	for _, id := range s.volumeUsers(name) {
+		env, err := s.store.Get(id)
+		if err != nil || !holdsCapacity(env.GetStatus()) {
+			continue
+		}
+		return status.Errorf(codes.FailedPrecondition, "volume %s is mounted by environment %s which is %s, stop it first", name, id, env.GetStatus())
+	}
+	return nil
+}
+
+// validateSnapshotPolicy checks the interval and retention of a policy
+func validateSnapshotPolicy(policy *pb.SnapshotPolicy) error {
+	return validateSchedule("snapshot", policy.GetIntervalSeconds(), policy.GetRetain())
+}
+
+// validateSchedule checks the interval and retention of a periodic job
+func validateSchedule(kind string, intervalSeconds, retain int32) error {
+	if intervalSeconds < 0 || retain < 0 {
+		return fmt.Errorf("%s interval and retention must not be negative", kind)
+	}
+	if intervalSeconds > 0 && intervalSeconds < minScheduleInterval {
+		return fmt.Errorf("%s interval must be at least %d seconds", kind, minScheduleInterval)
+	}
+	return nil
+}
+
+// snapshotError maps snapshot and volume errors onto gRPC status codes
+func snapshotError(err error) error {
+	if errors.Is(err, snapshot.ErrNotFound) {
+		return status.Errorf(codes.NotFound, "%v", err)
+	}
+	if errors.Is(err, snapshot.ErrReflinkUnsupported) {
+		return status.Errorf(codes.FailedPrecondition, "%v", err)
+	}
+	if errors.Is(err, volume.ErrNotFound) || errors.Is(err, volume.ErrAlreadyExists) || errors.Is(err, volume.ErrOtherTenant) {
+		return volumeError(err)
+	}
+	return status.Errorf(codes.Internal, "%v", err)
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"scheduler/internal/container"
+	"scheduler/internal/snapshot"
+	pb "scheduler/proto/gen"
+)
+
+// withSnapshots keeps volume snapshots in dir
+func withSnapshots(t *testing.T, dir string) func(*Options) {
+	return func(opts *Options) {
+		snapshots, err := snapshot.NewManager(dir, opts.Volumes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		opts.Snapshots = snapshots
+	}
+}
+
+func TestRestoreVolume(t *testing.T) {
+	dir := t.TempDir()
+	s := newTestService(t, &container.Fake{}, withSnapshots(t, dir))
+	ctx := context.Background()
+
+	if _, err := s.CreateVolume(ctx, &pb.CreateVolumeRequest{Name: "data"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(s.volumes.DataPath("data"), "file"), []byte("v1"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	taken, err := s.SnapshotVolume(ctx, &pb.SnapshotVolumeRequest{VolumeName: "data"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := taken.GetSnapshot().GetId()
+
+	restored, err := s.RestoreVolume(ctx, &pb.RestoreVolumeRequest{VolumeName: "data", SnapshotId: id, TargetVolumeName: "copy"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(restored.GetVolume().GetPath(), "file")); string(data) != "v1" {
+		t.Errorf("restored file = %q, want v1", data)
+	}
+
+	// In-place restores wait for the environments mounting the volume
+	spec := testSpec("mounts")
+	spec.ApplicationStack.Database.Container.Volumes = []*pb.VolumeMount{{Name: "data", MountPath: "/data"}}
+	env := createEnvironment(t, s, spec)
+	if _, err := s.StartEnvironment(ctx, &pb.StartEnvironmentRequest{Id: env.GetId()}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.RestoreVolume(ctx, &pb.RestoreVolumeRequest{VolumeName: "data", SnapshotId: id}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("restoring a mounted volume in place = %v, want FailedPrecondition", err)
+	}
+
+	// A volume created for a failed restore is removed again
+	err = filepath.WalkDir(dir, func(path string, entry os.DirEntry, err error) error {
+		if err == nil && strings.HasSuffix(path, ".tar.gz") {
+			err = os.WriteFile(path, []byte("corrupt"), 0o644)
+		}
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.RestoreVolume(ctx, &pb.RestoreVolumeRequest{VolumeName: "data", SnapshotId: id, TargetVolumeName: "broken"}); err == nil {
+		t.Fatal("restoring a corrupt snapshot succeeded")
+	}
+	if _, err := s.GetVolume(ctx, &pb.GetVolumeRequest{Name: "broken"}); status.Code(err) != codes.NotFound {
+		t.Errorf("GetVolume() after the failed restore = %v, want NotFound", err)
+	}
+}
+
+func TestRestoreVolumeInPlace(t *testing.T) {
+	runtime := &container.Fake{}
+	s := newTestService(t, runtime, withSnapshots(t, t.TempDir()))
+	ctx := context.Background()
+
+	if _, err := s.CreateVolume(ctx, &pb.CreateVolumeRequest{Name: "data"}); err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(s.volumes.DataPath("data"), "file")
+	if err := os.WriteFile(file, []byte("v1"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	taken, err := s.SnapshotVolume(ctx, &pb.SnapshotVolumeRequest{VolumeName: "data"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	restore := &pb.RestoreVolumeRequest{VolumeName: "data", SnapshotId: taken.GetSnapshot().GetId()}
+	if err := os.WriteFile(file, []byte("v2"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// A failed environment may have left containers mounting the volume
+	spec := testSpec("mounts")
+	spec.ApplicationStack.Database.Container.Volumes = []*pb.VolumeMount{{Name: "data", MountPath: "/data"}}
+	env := createEnvironment(t, s, spec)
+	if _, err := s.StartEnvironment(ctx, &pb.StartEnvironmentRequest{Id: env.GetId()}); err != nil {
+		t.Fatal(err)
+	}
+	runtime.OnRun = func(_, _ string, _ container.Spec) error { return container.ErrFake }
+	spec.ApplicationStack.Backend.Container.Image = "backend:2"
+	if _, err := s.UpdateEnvironment(ctx, &pb.UpdateEnvironmentRequest{Id: env.GetId(), Spec: spec}); err == nil {
+		t.Fatal("UpdateEnvironment() succeeded despite the failing start")
+	}
+	runtime.OnRun = nil
+	if _, err := s.RestoreVolume(ctx, restore); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("restoring a volume of a failed environment = %v, want FailedPrecondition", err)
+	}
+
+	if _, err := s.StopEnvironment(ctx, &pb.StopEnvironmentRequest{Id: env.GetId()}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.RestoreVolume(ctx, restore); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(file); string(data) != "v1" {
+		t.Errorf("file after the restore = %q, want v1", data)
+	}
+
+	// While a restore extracts, the volume cannot be mounted, deleted or
+	// restored again
+	if _, err := s.DeleteEnvironment(ctx, &pb.DeleteEnvironmentRequest{Id: env.GetId()}); err != nil {
+		t.Fatal(err)
+	}
+	s.restoring = map[string]bool{"data": true}
+	other := testSpec("other")
+	other.ApplicationStack.Database.Container.Volumes = []*pb.VolumeMount{{Name: "data", MountPath: "/data"}}
+	if _, err := s.CreateEnvironment(ctx, &pb.CreateEnvironmentRequest{Spec: other}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("mounting a volume being restored = %v, want FailedPrecondition", err)
+	}
+	if _, err := s.DeleteVolume(ctx, &pb.DeleteVolumeRequest{Name: "data"}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("deleting a volume being restored = %v, want FailedPrecondition", err)
+	}
+	if _, err := s.RestoreVolume(ctx, restore); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("restoring a volume being restored = %v, want FailedPrecondition", err)
+	}
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"scheduler/internal/templates"
+	pb "scheduler/proto/gen"
+)
+
+// ListTemplates lists the latest version of every template environments can
+// be created from
+func (s *SchedulerService) ListTemplates(ctx context.Context, req *pb.ListTemplatesRequest) (*pb.ListTemplatesResponse, error) {
+	list, err := s.templates.List()
+	if err != nil {
+		return nil, templateError(err)
+	}
+	resp := &pb.ListTemplatesResponse{Templates: make([]*pb.Template, len(list))}
+	for i, t := range list {
+		resp.Templates[i] = t.Proto()
+	}
+	return resp, nil
+}
+
+// GetTemplate retrieves a version of a template and its parameters by name
+func (s *SchedulerService) GetTemplate(ctx context.Context, req *pb.GetTemplateRequest) (*pb.GetTemplateResponse, error) {
+	t, err := s.templates.Get(req.GetName(), req.GetVersion())
+	if err != nil {
+		return nil, templateError(err)
+	}
+	return &pb.GetTemplateResponse{Template: t.Proto()}, nil
+}
+
+// CreateEnvironmentFromTemplate expands a template with the given parameters
+// and creates the resulting environment like CreateEnvironment would. The
+// environment records the template version it came from.
+func (s *SchedulerService) CreateEnvironmentFromTemplate(ctx context.Context, req *pb.CreateEnvironmentFromTemplateRequest) (*pb.CreateEnvironmentFromTemplateResponse, error) {
+	if req.GetName() == "" {
+		return nil, status.Error(codes.InvalidArgument, "name is required")
+	}
+	t, err := s.templates.Get(req.GetTemplate(), req.GetTemplateVersion())
+	if err != nil {
+		return nil, templateError(err)
+	}
+	spec, err := t.Expand(req.GetName(), req.GetParameters())
+	if err != nil {
+		return nil, templateError(err)
+	}
+	for key, value := range req.GetLabels() {
+		spec.Labels[key] = value
+	}
+	if err := validateSpec(spec); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "template %s expands to an invalid specification: %v", t.Name, err)
+	}
+	owner, err := s.resolveTenant(ctx, req.GetTenant())
+	if err != nil {
+		return nil, err
+	}
+	if err := checkScope(ctx, owner.Name, spec.GetLabels()); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	env, err := s.createEnvironment(ctx, owner, spec, t.Reference())
+	if err != nil {
+		return nil, err
+	}
+	return &pb.CreateEnvironmentFromTemplateResponse{Environment: env}, nil
+}
+
+// RegisterTemplate stores a template manifest as the next version of the
+// template it names. Environments created from earlier versions are not
+// changed.
+func (s *SchedulerService) RegisterTemplate(ctx context.Context, req *pb.RegisterTemplateRequest) (*pb.RegisterTemplateResponse, error) {
+	t, err := s.templates.Register([]byte(req.GetManifest()))
+	if err != nil {
+		return nil, templateError(err)
+	}
+	return &pb.RegisterTemplateResponse{Template: t.Proto()}, nil
+}
+
+// DeleteTemplate deletes every version of a user template
+func (s *SchedulerService) DeleteTemplate(ctx context.Context, req *pb.DeleteTemplateRequest) (*pb.DeleteTemplateResponse, error) {
+	if err := s.templates.Delete(req.GetName()); err != nil {
+		return nil, templateError(err)
+	}
+	return &pb.DeleteTemplateResponse{}, nil
+}
+
+// templateError maps template registry errors onto gRPC status codes
+func templateError(err error) error {
+	switch {
+	case errors.Is(err, templates.ErrNotFound):
+		return status.Errorf(codes.NotFound, "%v", err)
+	case errors.Is(err, templates.ErrInvalidParameters), errors.Is(err, templates.ErrInvalidTemplate):
+		return status.Errorf(codes.InvalidArgument, "%v", err)
+	case errors.Is(err, templates.ErrBuiltin), errors.Is(err, templates.ErrDisabled):
+		return status.Errorf(codes.FailedPrecondition, "%v", err)
+	default:
+		return status.Errorf(codes.Internal, "%v", err)
+	}
+}
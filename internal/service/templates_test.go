@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"scheduler/internal/container"
+	"scheduler/internal/templates"
+	pb "scheduler/proto/gen"
+)
+
+func TestCreateEnvironmentFromTemplate(t *testing.T) {
+	s := newTestService(t, &container.Fake{})
+	ctx := context.Background()
+
+	resp, err := s.CreateEnvironmentFromTemplate(ctx, &pb.CreateEnvironmentFromTemplateRequest{
+		Template:   "nginx-node-postgres",
+		Name:       "shop",
+		Parameters: map[string]string{"database_password": "hunter2"},
+		Labels:     map[string]string{"team": "web"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	labels := resp.GetEnvironment().GetSpec().GetLabels()
+	if labels[templates.LabelTemplate] != "nginx-node-postgres" || labels["team"] != "web" {
+		t.Errorf("labels = %v, want the template and the given labels", labels)
+	}
+
+	tests := []struct {
+		name string
+		req  *pb.CreateEnvironmentFromTemplateRequest
+		want codes.Code
+	}{
+		{name: "unknown template", req: &pb.CreateEnvironmentFromTemplateRequest{Template: "missing", Name: "a"}, want: codes.NotFound},
+		{name: "missing parameter", req: &pb.CreateEnvironmentFromTemplateRequest{Template: "nginx-node-postgres", Name: "a"}, want: codes.InvalidArgument},
+		{name: "missing name", req: &pb.CreateEnvironmentFromTemplateRequest{Template: "nginx-node-postgres"}, want: codes.InvalidArgument},
+		{
+			name: "name taken",
+			req: &pb.CreateEnvironmentFromTemplateRequest{
+				Template:   "nginx-node-postgres",
+				Name:       "shop",
+				Parameters: map[string]string{"database_password": "hunter2"},
+			},
+			want: codes.AlreadyExists,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := s.CreateEnvironmentFromTemplate(ctx, tt.req); status.Code(err) != tt.want {
+				t.Errorf("CreateEnvironmentFromTemplate() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetTemplate(t *testing.T) {
+	s := newTestService(t, &container.Fake{})
+	ctx := context.Background()
+
+	listed, err := s.ListTemplates(ctx, &pb.ListTemplatesRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(listed.GetTemplates()) != 2 {
+		t.Errorf("ListTemplates() = %d templates, want the built-in ones", len(listed.GetTemplates()))
+	}
+	got, err := s.GetTemplate(ctx, &pb.GetTemplateRequest{Name: "static-go-postgres"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.GetTemplate().GetName() != "static-go-postgres" || len(got.GetTemplate().GetParameters()) == 0 {
+		t.Errorf("GetTemplate() = %v, want the template with its parameters", got.GetTemplate())
+	}
+	if _, err := s.GetTemplate(ctx, &pb.GetTemplateRequest{Name: "missing"}); status.Code(err) != codes.NotFound {
+		t.Errorf("GetTemplate() of an unknown template error = %v, want NotFound", err)
+	}
+}
+
+func TestRegisterTemplate(t *testing.T) {
+	registry, err := templates.NewRegistry(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := newTestService(t, &container.Fake{}, func(opts *Options) { opts.Templates = registry })
+	ctx := context.Background()
+
+	const manifest = `apiVersion: scheduler/v1
+kind: Template
+metadata:
+  name: cache
+parameters:
+  - name: image
+    default_value: redis:7
+spec:
+  application_stack:
+    additional_services:
+      redis:
+        image: ${image}
+`
+	for _, image := range []string{"redis:7", "redis:8"} {
+		if _, err := s.RegisterTemplate(ctx, &pb.RegisterTemplateRequest{Manifest: strings.Replace(manifest, "redis:7", image, 1)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	resp, err := s.CreateEnvironmentFromTemplate(ctx, &pb.CreateEnvironmentFromTemplateRequest{Template: "cache", TemplateVersion: 1, Name: "cache"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	env := resp.GetEnvironment()
+	if env.GetTemplate().GetName() != "cache" || env.GetTemplate().GetVersion() != 1 {
+		t.Errorf("template = %v, want version 1 of cache", env.GetTemplate())
+	}
+	if image := env.GetSpec().GetApplicationStack().GetAdditionalServices()["redis"].GetImage(); image != "redis:7" {
+		t.Errorf("image = %q, want the one of version 1", image)
+	}
+
+	if _, err := s.RegisterTemplate(ctx, &pb.RegisterTemplateRequest{Manifest: "kind: Template"}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("RegisterTemplate() of an invalid manifest error = %v, want InvalidArgument", err)
+	}
+	if _, err := s.DeleteTemplate(ctx, &pb.DeleteTemplateRequest{Name: "nginx-node-postgres"}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("DeleteTemplate() of a built-in template error = %v, want FailedPrecondition", err)
+	}
+	if _, err := s.DeleteTemplate(ctx, &pb.DeleteTemplateRequest{Name: "cache"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.GetTemplate(ctx, &pb.GetTemplateRequest{Name: "cache"}); status.Code(err) != codes.NotFound {
+		t.Errorf("GetTemplate() after DeleteTemplate() error = %v, want NotFound", err)
+	}
+}
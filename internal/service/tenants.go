@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"scheduler/internal/auth"
+	"scheduler/internal/tenant"
+	pb "scheduler/proto/gen"
+)
+
+// resolveTenant picks the tenant a new environment belongs to: the requested
+// one, else the caller's, else the default tenant. Callers bound to a tenant
+// cannot create environments in another.
+func (s *SchedulerService) resolveTenant(ctx context.Context, requested string) (tenant.Tenant, error) {
+	name := requested
+	if identity, ok := auth.FromContext(ctx); ok && identity.Tenant != "" {
+		if name != "" && name != identity.Tenant {
+			return tenant.Tenant{}, status.Errorf(codes.PermissionDenied, "%s may only access tenant %s", identity.Subject, identity.Tenant)
+		}
+		name = identity.Tenant
+	}
+	if name == "" {
+		name = tenant.Default
+	}
+
+	t, ok := s.tenants.Get(name)
+	if !ok {
+		return tenant.Tenant{}, status.Errorf(codes.InvalidArgument, "unknown tenant %q", name)
+	}
+	return t, nil
+}
+
+// tenantOf returns the configured tenant of an environment. Environments of
+// tenants removed from the config fall back to the default tenant so that
+// they can still be stopped and deleted.
+func (s *SchedulerService) tenantOf(env *pb.Environment) tenant.Tenant {
+	if t, ok := s.tenants.Get(env.GetTenant()); ok {
+		return t
+	}
+	t, _ := s.tenants.Get(tenant.Default)
+	return t
+}
+
+// namespaceFor returns the containerD namespace of an environment's containers
+func (s *SchedulerService) namespaceFor(env *pb.Environment) string {
+	return s.tenantOf(env).Namespace
+}
+
+// subnetPoolFor returns the subnet pool an environment draws from, the empty
+// name selecting the default pool
+func (s *SchedulerService) subnetPoolFor(env *pb.Environment) string {
+	if t := s.tenantOf(env); t.HasOwnPool() {
+		return t.Name
+	}
+	return ""
+}
+
+// checkNameAvailable verifies that no other environment of the same tenant
+// uses the name of env
+func (s *SchedulerService) checkNameAvailable(env *pb.Environment) error {
+	for _, other := range s.store.List() {
+		if other.GetId() != env.GetId() && other.GetTenant() == env.GetTenant() && other.GetName() == env.GetName() {
+			return status.Errorf(codes.AlreadyExists, "tenant %s already has an environment named %s (%s)", env.GetTenant(), env.GetName(), other.GetId())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"net/netip"
+	"reflect"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"scheduler/internal/auth"
+	"scheduler/internal/container"
+	"scheduler/internal/tenant"
+	pb "scheduler/proto/gen"
+)
+
+// withTeamA registers tenant team-a with its own subnet pool
+func withTeamA(t *testing.T) func(*Options) {
+	return func(opts *Options) {
+		tenants, err := tenant.NewRegistry([]tenant.Tenant{{Name: "team-a", SubnetPool: []string{"172.30.0.0/16"}}}, testNamespace, 24)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := opts.Subnets.AddPool("team-a", []string{"172.30.0.0/16"}, 24); err != nil {
+			t.Fatal(err)
+		}
+		opts.Tenants = tenants
+	}
+}
+
+func TestTenants(t *testing.T) {
+	runtime := &container.Fake{}
+	s := newTestService(t, runtime, withTeamA(t))
+	ctx := context.Background()
+
+	shared := createEnvironment(t, s, testSpec("web"))
+	if shared.GetTenant() != tenant.Default {
+		t.Errorf("tenant = %q, want the default tenant", shared.GetTenant())
+	}
+	resp, err := s.CreateEnvironment(ctx, &pb.CreateEnvironmentRequest{Spec: testSpec("web"), Tenant: "team-a"})
+	if err != nil {
+		t.Fatalf("creating an environment of the same name in another tenant = %v", err)
+	}
+	own := resp.GetEnvironment()
+
+	if _, err := s.CreateEnvironment(ctx, &pb.CreateEnvironmentRequest{Spec: testSpec("web"), Tenant: "team-a"}); status.Code(err) != codes.AlreadyExists {
+		t.Errorf("creating a duplicate name within a tenant = %v, want AlreadyExists", err)
+	}
+	if _, err := s.CreateEnvironment(ctx, &pb.CreateEnvironmentRequest{Spec: testSpec("other"), Tenant: "missing"}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("creating an environment of an unknown tenant = %v, want InvalidArgument", err)
+	}
+
+	subnet := netip.MustParsePrefix(own.GetSpec().GetNetwork().GetSubnet())
+	if !netip.MustParsePrefix("172.30.0.0/16").Contains(subnet.Addr()) {
+		t.Errorf("subnet of team-a = %s, want one from its own pool", subnet)
+	}
+
+	if _, err := s.StartEnvironment(ctx, &pb.StartEnvironmentRequest{Id: own.GetId()}); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{own.GetId() + "-backend", own.GetId() + "-database", own.GetId() + "-frontend"}
+	if got := runtime.RunningIDs(testNamespace + "-team-a"); !reflect.DeepEqual(got, want) {
+		t.Errorf("containers in the team-a namespace = %v, want %v", got, want)
+	}
+	if got := runtime.RunningIDs(testNamespace); len(got) != 0 {
+		t.Errorf("containers in the default namespace = %v, want none", got)
+	}
+}
+
+func TestTenantBoundCaller(t *testing.T) {
+	s := newTestService(t, &container.Fake{}, withTeamA(t))
+	shared := createEnvironment(t, s, testSpec("shared"))
+	ctx := auth.NewContext(context.Background(), &auth.Identity{Subject: "ci", Role: auth.RoleAdmin, Tenant: "team-a"})
+
+	// Callers bound to a tenant create environments there by default
+	resp, err := s.CreateEnvironment(ctx, &pb.CreateEnvironmentRequest{Spec: testSpec("web")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.GetEnvironment().GetTenant() != "team-a" {
+		t.Errorf("tenant = %q, want the caller's", resp.GetEnvironment().GetTenant())
+	}
+	if _, err := s.CreateEnvironment(ctx, &pb.CreateEnvironmentRequest{Spec: testSpec("other"), Tenant: tenant.Default}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("creating an environment in another tenant = %v, want PermissionDenied", err)
+	}
+	if _, err := s.GetEnvironment(ctx, &pb.GetEnvironmentRequest{Id: shared.GetId()}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("reading an environment of another tenant = %v, want PermissionDenied", err)
+	}
+
+	listed, err := s.ListEnvironments(ctx, &pb.ListEnvironmentsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(listed.GetEnvironments()) != 1 || listed.GetEnvironments()[0].GetTenant() != "team-a" {
+		t.Errorf("ListEnvironments() = %v, want only the environment of team-a", listed.GetEnvironments())
+	}
+}
@@ -0,0 +1,298 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sort"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"scheduler/internal/stack"
+	"scheduler/internal/tenant"
+	"scheduler/internal/volume"
+	pb "scheduler/proto/gen"
+)
+
+// environmentLabel marks volumes that were created implicitly for an environment
+const environmentLabel = "scheduler/environment"
+
+// CreateVolume creates a named volume owned by the requested tenant, or the
+// caller's
+func (s *SchedulerService) CreateVolume(ctx context.Context, req *pb.CreateVolumeRequest) (*pb.CreateVolumeResponse, error) {
+	if err := volume.ValidateName(req.GetName()); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	if err := validateSnapshotPolicy(req.GetSnapshotPolicy()); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	owner, err := s.resolveTenant(ctx, req.GetTenant())
+	if err != nil {
+		return nil, err
+	}
+	if err := checkScope(ctx, owner.Name, req.GetLabels()); err != nil {
+		return nil, err
+	}
+
+	created, err := s.volumes.Create(req.GetName(), owner.Name, req.GetLabels(), req.GetSnapshotPolicy())
+	if err != nil {
+		return nil, volumeError(err)
+	}
+	return &pb.CreateVolumeResponse{Volume: created}, nil
+}
+
+// GetVolume retrieves a named volume along with its usage
+func (s *SchedulerService) GetVolume(ctx context.Context, req *pb.GetVolumeRequest) (*pb.GetVolumeResponse, error) {
+	found, err := s.getVolume(ctx, req.GetName())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.measureVolume(found); err != nil {
+		return nil, err
+	}
+	found.UsedBy = s.volumeUsers(found.GetName())
+	return &pb.GetVolumeResponse{Volume: found}, nil
+}
+
+// ListVolumes lists the named volumes of the caller's tenant matching the
+// label filters. Callers not bound to a tenant see every tenant unless the
+// request names one.
+func (s *SchedulerService) ListVolumes(ctx context.Context, req *pb.ListVolumesRequest) (*pb.ListVolumesResponse, error) {
+	volumes, err := s.volumes.List()
+	if err != nil {
+		return nil, volumeError(err)
+	}
+
+	resp := &pb.ListVolumesResponse{}
+	for _, listed := range volumes {
+		if req.GetTenant() != "" && listed.GetTenant() != req.GetTenant() {
+			continue
+		}
+		if !matchesLabels(listed.GetLabels(), req.GetFilters()) || !inScope(ctx, listed.GetTenant(), listed.GetLabels()) {
+			continue
+		}
+		if err := s.measureVolume(listed); err != nil {
+			return nil, err
+		}
+		listed.UsedBy = s.volumeUsers(listed.GetName())
+		resp.Volumes = append(resp.Volumes, listed)
+		resp.TotalSizeBytes += listed.GetSizeBytes()
+	}
+	return resp, nil
+}
+
+// UpdateVolume replaces the labels and snapshot policy of a named volume
+func (s *SchedulerService) UpdateVolume(ctx context.Context, req *pb.UpdateVolumeRequest) (*pb.UpdateVolumeResponse, error) {
+	if err := validateSnapshotPolicy(req.GetSnapshotPolicy()); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	found, err := s.getVolume(ctx, req.GetName())
+	if err != nil {
+		return nil, err
+	}
+	// The new labels must keep the volume within the caller's scope
+	if err := checkScope(ctx, found.GetTenant(), req.GetLabels()); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.volumes.Update(req.GetName(), req.GetLabels(), req.GetSnapshotPolicy())
+	if err != nil {
+		return nil, volumeError(err)
+	}
+	if err := s.measureVolume(updated); err != nil {
+		return nil, err
+	}
+	updated.UsedBy = s.volumeUsers(updated.GetName())
+	return &pb.UpdateVolumeResponse{Volume: updated}, nil
+}
+
+// DeleteVolume deletes a named volume that no environment mounts
+func (s *SchedulerService) DeleteVolume(ctx context.Context, req *pb.DeleteVolumeRequest) (*pb.DeleteVolumeResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.getVolume(ctx, req.GetName()); err != nil {
+		return nil, err
+	}
+	if users := s.volumeUsers(req.GetName()); len(users) > 0 {
+		return nil, status.Errorf(codes.FailedPrecondition, "volume %s is in use by environments %s", req.GetName(), strings.Join(users, ", "))
+	}
+	if s.restoring[req.GetName()] {
+		return nil, status.Errorf(codes.FailedPrecondition, "volume %s is being restored from a snapshot", req.GetName())
+	}
+	if err := s.volumes.Delete(req.GetName()); err != nil {
+		return nil, volumeError(err)
+	}
+	return &pb.DeleteVolumeResponse{Success: true}, nil
+}
+
+// measureVolume fills in the size of a volume that is reported to the
+// caller. Only reported volumes are measured, since it walks their data.
+func (s *SchedulerService) measureVolume(reported *pb.Volume) error {
+	size, err := s.volumes.Usage(reported.GetName())
+	if err != nil {
+		return volumeError(err)
+	}
+	reported.SizeBytes = size
+	return nil
+}
+
+// getVolume loads a named volume the caller may access
+func (s *SchedulerService) getVolume(ctx context.Context, name string) (*pb.Volume, error) {
+	found, err := s.volumes.Get(name)
+	if err != nil {
+		return nil, volumeError(err)
+	}
+	if err := checkScope(ctx, found.GetTenant(), found.GetLabels()); err != nil {
+		return nil, err
+	}
+	return found, nil
+}
+
+// ensureVolumes creates the named volumes an environment mounts that do not
+// exist yet. It must be called with s.mu held. Volumes created here belong to the tenant of the environment
+// and carry its labels. Existing volumes must belong to the same tenant and
+// be within the caller's scope.
+func (s *SchedulerService) ensureVolumes(ctx context.Context, env *pb.Environment) error {
+	for _, name := range namedVolumes(env.GetSpec()) {
+		// Environments already mounting a volume being restored are locked
+		// by the restore, others may not start mounting it meanwhile
+		if s.restoring[name] {
+			return status.Errorf(codes.FailedPrecondition, "volume %s is being restored from a snapshot, try again once it is done", name)
+		}
+		labels := make(map[string]string, len(env.GetSpec().GetLabels())+1)
+		for key, value := range env.GetSpec().GetLabels() {
+			labels[key] = value
+		}
+		labels[environmentLabel] = env.GetId()
+		ensured, err := s.volumes.Ensure(name, env.GetTenant(), labels)
+		if err != nil {
+			return volumeError(err)
+		}
+		if err := checkScope(ctx, ensured.GetTenant(), ensured.GetLabels()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// assignVolumeOwners gives volumes created before volumes had owners to the
+// tenant of an environment mounting them, or to the default tenant
+func (s *SchedulerService) assignVolumeOwners() error {
+	volumes, err := s.volumes.List()
+	if err != nil {
+		return err
+	}
+	for _, listed := range volumes {
+		if listed.GetTenant() != "" {
+			continue
+		}
+		owner := tenant.Default
+		if users := s.volumeUsers(listed.GetName()); len(users) > 0 {
+			if env, err := s.store.Get(users[0]); err == nil {
+				owner = env.GetTenant()
+			}
+		}
+		if _, err := s.volumes.Assign(listed.GetName(), owner); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkPurgeable refuses to purge the volumes of an environment while other
+// environments mount one of the volumes it created
+func (s *SchedulerService) checkPurgeable(env *pb.Environment) error {
+	for _, name := range s.ownedVolumes(env) {
+		var others []string
+		for _, id := range s.volumeUsers(name) {
+			if id != env.GetId() {
+				others = append(others, id)
+			}
+		}
+		if len(others) > 0 {
+			return status.Errorf(codes.FailedPrecondition, "volume %s is still used by environments %s, delete without purging volumes", name, strings.Join(others, ", "))
+		}
+	}
+	return nil
+}
+
+// ownedVolumes returns the names of the volumes an environment mounts that
+// it created itself
+func (s *SchedulerService) ownedVolumes(env *pb.Environment) []string {
+	var owned []string
+	for _, name := range namedVolumes(env.GetSpec()) {
+		found, err := s.volumes.Get(name)
+		if err != nil {
+			continue
+		}
+		if found.GetTenant() == env.GetTenant() && found.GetLabels()[environmentLabel] == env.GetId() {
+			owned = append(owned, name)
+		}
+	}
+	return owned
+}
+
+// purgeVolumes deletes the named volumes a deleted environment created
+// itself. Volumes created separately and volumes other environments still
+// mount are kept.
+func (s *SchedulerService) purgeVolumes(env *pb.Environment) {
+	for _, name := range s.ownedVolumes(env) {
+		if users := s.volumeUsers(name); len(users) > 0 {
+			log.Printf("Keeping volume %s of environment %s, still used by %s", name, env.GetId(), strings.Join(users, ", "))
+			continue
+		}
+		if err := s.volumes.Delete(name); err != nil && !errors.Is(err, volume.ErrNotFound) {
+			log.Printf("Failed to purge volume %s of environment %s: %v", name, env.GetId(), err)
+		}
+	}
+}
+
+// volumeUsers returns the IDs of the environments that mount a named volume
+func (s *SchedulerService) volumeUsers(name string) []string {
+	var users []string
+	for _, env := range s.store.List() {
+		for _, mounted := range namedVolumes(env.GetSpec()) {
+			if mounted == name {
+				users = append(users, env.GetId())
+				break
+			}
+		}
+	}
+	return users
+}
+
+// namedVolumes returns the sorted, de-duplicated names of the scheduler
+// managed volumes a specification mounts
+func namedVolumes(spec *pb.EnvironmentSpecification) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, member := range stack.Members(spec.GetApplicationStack()) {
+		for _, mount := range member.Container.GetVolumes() {
+			if mount.GetHostPath() != "" || seen[mount.GetName()] {
+				continue
+			}
+			seen[mount.GetName()] = true
+			names = append(names, mount.GetName())
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// volumeError maps volume manager errors onto gRPC status codes
+func volumeError(err error) error {
+	switch {
+	case errors.Is(err, volume.ErrNotFound):
+		return status.Errorf(codes.NotFound, "%v", err)
+	case errors.Is(err, volume.ErrAlreadyExists):
+		return status.Errorf(codes.AlreadyExists, "%v", err)
+	case errors.Is(err, volume.ErrOtherTenant):
+		return status.Errorf(codes.PermissionDenied, "%v", err)
+	default:
+		return status.Errorf(codes.Internal, "%v", err)
+	}
+}
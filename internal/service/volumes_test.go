@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"scheduler/internal/auth"
+	"scheduler/internal/container"
+	"scheduler/internal/tenant"
+	pb "scheduler/proto/gen"
+)
+
+func TestVolumeTenants(t *testing.T) {
+	s := newTestService(t, &container.Fake{}, withTeamA(t))
+	ctx := context.Background()
+	teamA := auth.NewContext(ctx, &auth.Identity{Subject: "ci", Role: auth.RoleAdmin, Tenant: "team-a"})
+
+	if _, err := s.CreateVolume(ctx, &pb.CreateVolumeRequest{Name: "shared"}); err != nil {
+		t.Fatal(err)
+	}
+	created, err := s.CreateVolume(teamA, &pb.CreateVolumeRequest{Name: "own"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.GetVolume().GetTenant() != "team-a" {
+		t.Errorf("tenant = %q, want the caller's", created.GetVolume().GetTenant())
+	}
+
+	listed, err := s.ListVolumes(teamA, &pb.ListVolumesRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if volumes := listed.GetVolumes(); len(volumes) != 1 || volumes[0].GetName() != "own" {
+		t.Errorf("ListVolumes() = %v, want only the volume of team-a", volumes)
+	}
+	if _, err := s.GetVolume(teamA, &pb.GetVolumeRequest{Name: "shared"}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("GetVolume() of another tenant = %v, want PermissionDenied", err)
+	}
+	if _, err := s.DeleteVolume(teamA, &pb.DeleteVolumeRequest{Name: "shared"}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("DeleteVolume() of another tenant = %v, want PermissionDenied", err)
+	}
+
+	// Environments may only mount volumes of their own tenant
+	spec := testSpec("mounts")
+	spec.ApplicationStack.Database.Container.Volumes = []*pb.VolumeMount{{Name: "shared", MountPath: "/data"}}
+	if _, err := s.CreateEnvironment(teamA, &pb.CreateEnvironmentRequest{Spec: spec}); status.Code(err) == codes.OK {
+		t.Error("mounting a volume of another tenant succeeded")
+	}
+	env := createEnvironment(t, s, spec)
+	if env.GetTenant() != tenant.Default {
+		t.Errorf("tenant = %q, want the default tenant", env.GetTenant())
+	}
+}
+
+func TestPurgeVolumes(t *testing.T) {
+	s := newTestService(t, &container.Fake{})
+	ctx := context.Background()
+
+	if _, err := s.CreateVolume(ctx, &pb.CreateVolumeRequest{Name: "separate"}); err != nil {
+		t.Fatal(err)
+	}
+	spec := testSpec("purge")
+	spec.ApplicationStack.Database.Container.Volumes = []*pb.VolumeMount{
+		{Name: "purge-data", MountPath: "/data"},
+		{Name: "separate", MountPath: "/backup"},
+	}
+	env := createEnvironment(t, s, spec)
+
+	// A volume the environment created cannot be purged while another
+	// environment mounts it
+	other := testSpec("other")
+	other.ApplicationStack.Database.Container.Volumes = []*pb.VolumeMount{{Name: "purge-data", MountPath: "/data"}}
+	shared := createEnvironment(t, s, other)
+	if _, err := s.DeleteEnvironment(ctx, &pb.DeleteEnvironmentRequest{Id: env.GetId(), PurgeVolumes: true}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("purging a volume in use = %v, want FailedPrecondition", err)
+	}
+	if _, err := s.DeleteEnvironment(ctx, &pb.DeleteEnvironmentRequest{Id: shared.GetId()}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.DeleteEnvironment(ctx, &pb.DeleteEnvironmentRequest{Id: env.GetId(), PurgeVolumes: true}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.GetVolume(ctx, &pb.GetVolumeRequest{Name: "purge-data"}); status.Code(err) != codes.NotFound {
+		t.Errorf("GetVolume() of the created volume = %v, want NotFound", err)
+	}
+	if _, err := s.GetVolume(ctx, &pb.GetVolumeRequest{Name: "separate"}); err != nil {
+		t.Errorf("GetVolume() of the separately created volume = %v, want it kept", err)
+	}
+}
+
+func TestVolumeUsage(t *testing.T) {
+	s := newTestService(t, &container.Fake{})
+	ctx := context.Background()
+
+	for _, name := range []string{"small", "large"} {
+		if _, err := s.CreateVolume(ctx, &pb.CreateVolumeRequest{Name: name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(s.volumes.DataPath("small"), "a"), make([]byte, 10), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(s.volumes.DataPath("large"), "b"), make([]byte, 100), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := s.GetVolume(ctx, &pb.GetVolumeRequest{Name: "small"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.GetVolume().GetSizeBytes() != 10 {
+		t.Errorf("GetVolume() size = %d, want 10", got.GetVolume().GetSizeBytes())
+	}
+	listed, err := s.ListVolumes(ctx, &pb.ListVolumesRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if listed.GetTotalSizeBytes() != 110 {
+		t.Errorf("ListVolumes() total = %d, want 110", listed.GetTotalSizeBytes())
+	}
+}
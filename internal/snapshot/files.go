@@ -0,0 +1,239 @@
+package snapshot
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrReflinkUnsupported is returned when the filesystem cannot share extents
+// between files
+var ErrReflinkUnsupported = errors.New("reflink copies are not supported by the filesystem")
+
+// reflinkTree copies a directory tree, sharing file extents with the source.
+// It fails with ErrReflinkUnsupported instead of falling back to a full copy.
+func reflinkTree(source, target string) error {
+	return walkCopy(source, target, cloneFile)
+}
+
+// copyTree copies a directory tree, sharing file extents with the source where
+// the filesystem allows and copying the bytes otherwise
+func copyTree(source, target string) error {
+	return walkCopy(source, target, func(dst, src *os.File) error {
+		err := cloneFile(dst, src)
+		if !errors.Is(err, ErrReflinkUnsupported) {
+			return err
+		}
+		_, err = io.Copy(dst, src)
+		return err
+	})
+}
+
+// walkCopy recreates the tree under source at target, delegating the copy of
+// regular file contents to copyContents
+func walkCopy(source, target string, copyContents func(dst, src *os.File) error) error {
+	return filepath.WalkDir(source, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		relative, err := filepath.Rel(source, path)
+		if err != nil {
+			return err
+		}
+		destination := filepath.Join(target, relative)
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case entry.IsDir():
+			if err := os.MkdirAll(destination, info.Mode().Perm()); err != nil {
+				return err
+			}
+		case entry.Type()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			if err := os.Symlink(link, destination); err != nil {
+				return err
+			}
+		case entry.Type().IsRegular():
+			if err := copyFile(path, destination, info.Mode().Perm(), copyContents); err != nil {
+				return err
+			}
+		default:
+			// Sockets, pipes and devices have no data worth preserving
+			return nil
+		}
+
+		uid, gid, ok := fileOwner(info)
+		if ok {
+			applyOwner(destination, uid, gid)
+		}
+		if entry.Type()&fs.ModeSymlink == 0 {
+			return os.Chtimes(destination, info.ModTime(), info.ModTime())
+		}
+		return nil
+	})
+}
+
+func copyFile(source, target string, perm fs.FileMode, copyContents func(dst, src *os.File) error) error {
+	src, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(target, os.O_CREATE|os.O_EXCL|os.O_WRONLY, perm)
+	if err != nil {
+		return err
+	}
+	if err := copyContents(dst, src); err != nil {
+		dst.Close()
+		return err
+	}
+	return dst.Close()
+}
+
+// writeTarball archives the tree under source into a gzip compressed tarball,
+// preserving modes, ownership and modification times
+func writeTarball(source, archive string) error {
+	file, err := os.OpenFile(archive, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	compressed := gzip.NewWriter(file)
+	writer := tar.NewWriter(compressed)
+
+	err = filepath.WalkDir(source, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		relative, err := filepath.Rel(source, path)
+		if err != nil || relative == "." {
+			return err
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		link := ""
+		if entry.Type()&fs.ModeSymlink != 0 {
+			if link, err = os.Readlink(path); err != nil {
+				return err
+			}
+		} else if !entry.IsDir() && !entry.Type().IsRegular() {
+			return nil
+		}
+
+		header, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(relative)
+		if entry.IsDir() {
+			header.Name += "/"
+		}
+		if err := writer.WriteHeader(header); err != nil {
+			return err
+		}
+
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		src, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer src.Close()
+		_, err = io.Copy(writer, src)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	if err := compressed.Close(); err != nil {
+		return err
+	}
+	return file.Sync()
+}
+
+// extractTarball unpacks a tarball written by writeTarball into target
+func extractTarball(archive, target string) error {
+	file, err := os.Open(archive)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	compressed, err := gzip.NewReader(file)
+	if err != nil {
+		return err
+	}
+	defer compressed.Close()
+
+	reader := tar.NewReader(compressed)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		name := filepath.Clean(filepath.FromSlash(header.Name))
+		if filepath.IsAbs(name) || name == ".." || strings.HasPrefix(name, ".."+string(filepath.Separator)) {
+			return fmt.Errorf("archive entry %q escapes the target directory", header.Name)
+		}
+		destination := filepath.Join(target, name)
+		mode := fs.FileMode(header.Mode).Perm()
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(destination, mode); err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			if err := os.Symlink(header.Linkname, destination); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			dst, err := os.OpenFile(destination, os.O_CREATE|os.O_EXCL|os.O_WRONLY, mode)
+			if err != nil {
+				return err
+			}
+			if _, err := io.Copy(dst, reader); err != nil {
+				dst.Close()
+				return err
+			}
+			if err := dst.Close(); err != nil {
+				return err
+			}
+		default:
+			continue
+		}
+
+		applyOwner(destination, header.Uid, header.Gid)
+		if header.Typeflag != tar.TypeSymlink {
+			if err := os.Chtimes(destination, header.ModTime, header.ModTime); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,11 @@
+//go:build !unix
+
+package snapshot
+
+import "io/fs"
+
+func fileOwner(info fs.FileInfo) (int, int, bool) {
+	return 0, 0, false
+}
+
+func applyOwner(path string, uid, gid int) {}
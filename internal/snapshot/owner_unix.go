@@ -0,0 +1,27 @@
+//go:build unix
+
+package snapshot
+
+import (
+	"io/fs"
+	"os"
+	"syscall"
+)
+
+// fileOwner returns the numeric owner of a file
+func fileOwner(info fs.FileInfo) (int, int, bool) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, 0, false
+	}
+	return int(stat.Uid), int(stat.Gid), true
+}
+
+// applyOwner restores the owner of a copied file. Only root may give files
+// away, so it is a no-op for unprivileged schedulers.
+func applyOwner(path string, uid, gid int) {
+	if os.Geteuid() != 0 {
+		return
+	}
+	os.Lchown(path, uid, gid)
+}
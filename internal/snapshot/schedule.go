@@ -0,0 +1,92 @@
+package snapshot
+
+import (
+	"context"
+	"log"
+	"time"
+
+	pb "scheduler/proto/gen"
+)
+
+// RunSchedule takes the snapshots that are due under each volume's snapshot
+// policy and prunes scheduled snapshots beyond the retention count. It checks
+// the policies every interval until ctx is canceled.
+func (m *Manager) RunSchedule(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		m.runScheduled(time.Now())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (m *Manager) runScheduled(now time.Time) {
+	volumes, err := m.volumes.List()
+	if err != nil {
+		log.Printf("snapshot: failed to list volumes: %v", err)
+		return
+	}
+
+	for _, scheduled := range volumes {
+		policy := scheduled.GetSnapshotPolicy()
+		if policy.GetIntervalSeconds() <= 0 {
+			continue
+		}
+
+		snapshots, err := m.List(scheduled.GetName())
+		if err != nil {
+			log.Printf("snapshot: failed to list snapshots of volume %s: %v", scheduled.GetName(), err)
+			continue
+		}
+
+		var latest *pb.VolumeSnapshot
+		for _, snapshot := range snapshots {
+			if snapshot.GetScheduled() {
+				latest = snapshot
+			}
+		}
+		interval := time.Duration(policy.GetIntervalSeconds()) * time.Second
+		if latest == nil || now.Sub(latest.GetCreatedAt().AsTime()) >= interval {
+			if _, err := m.Create(scheduled.GetName(), pb.SnapshotMethod_SNAPSHOT_METHOD_UNSPECIFIED, nil, true); err != nil {
+				log.Printf("snapshot: scheduled snapshot of volume %s failed: %v", scheduled.GetName(), err)
+				continue
+			}
+		}
+
+		m.prune(scheduled.GetName(), int(policy.GetRetain()))
+	}
+}
+
+// prune deletes the oldest scheduled snapshots of a volume so that at most
+// retain of them are left. Snapshots taken on request are never pruned.
+func (m *Manager) prune(volumeName string, retain int) {
+	if retain <= 0 {
+		return
+	}
+
+	snapshots, err := m.List(volumeName)
+	if err != nil {
+		log.Printf("snapshot: failed to list snapshots of volume %s: %v", volumeName, err)
+		return
+	}
+
+	var scheduled []*pb.VolumeSnapshot
+	for _, snapshot := range snapshots {
+		if snapshot.GetScheduled() {
+			scheduled = append(scheduled, snapshot)
+		}
+	}
+	for len(scheduled) > retain {
+		if err := m.Delete(volumeName, scheduled[0].GetId()); err != nil {
+			log.Printf("snapshot: failed to prune snapshot %s of volume %s: %v", scheduled[0].GetId(), volumeName, err)
+			return
+		}
+		scheduled = scheduled[1:]
+	}
+}
@@ -0,0 +1,250 @@
+package snapshot
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"scheduler/internal/fsutil"
+	"scheduler/internal/volume"
+	pb "scheduler/proto/gen"
+)
+
+// ErrNotFound is returned when a snapshot does not exist
+var ErrNotFound = errors.New("snapshot not found")
+
+const (
+	metadataFile = "snapshot.json"
+	tarballFile  = "data.tar.gz"
+	reflinkDir   = "data"
+)
+
+var idPattern = regexp.MustCompile(`^[0-9]{8}T[0-9]{6}Z-[0-9a-f]{8}$`)
+
+// Manager stores volume snapshots under a data root, one directory per
+// snapshot holding its metadata and either a compressed tarball or a
+// reflinked copy of the volume data
+type Manager struct {
+	mu      sync.Mutex
+	root    string
+	volumes *volume.Manager
+}
+
+// NewManager creates a manager storing snapshots of the volumes of volumes
+// under root
+func NewManager(root string, volumes *volume.Manager) (*Manager, error) {
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create snapshot root %s: %w", root, err)
+	}
+	return &Manager{root: root, volumes: volumes}, nil
+}
+
+// Create takes a snapshot of a volume. With SNAPSHOT_METHOD_UNSPECIFIED a
+// reflink copy is attempted first and a tarball is written if the filesystem
+// does not support it.
+func (m *Manager) Create(volumeName string, method pb.SnapshotMethod, labels map[string]string, scheduled bool) (*pb.VolumeSnapshot, error) {
+	source, err := m.volumes.Get(volumeName)
+	if err != nil {
+		return nil, err
+	}
+	size, err := m.volumes.Usage(volumeName)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := newID(time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate snapshot ID: %w", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	dir := m.dir(volumeName, id)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create snapshot directory %s: %w", dir, err)
+	}
+
+	snapshot := &pb.VolumeSnapshot{
+		Id:         id,
+		VolumeName: volumeName,
+		SizeBytes:  size,
+		Labels:     labels,
+		Scheduled:  scheduled,
+		CreatedAt:  timestamppb.Now(),
+	}
+
+	stored, usedMethod, err := m.capture(source.GetPath(), dir, method)
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, fmt.Errorf("failed to snapshot volume %s: %w", volumeName, err)
+	}
+	snapshot.Method = usedMethod
+	snapshot.StoredBytes = stored
+
+	data, err := protojson.MarshalOptions{Multiline: true}.Marshal(snapshot)
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, fmt.Errorf("failed to encode snapshot %s: %w", id, err)
+	}
+	if err := fsutil.WriteFileAtomic(filepath.Join(dir, metadataFile), data, 0o600); err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+// capture copies the volume data into dir and returns the bytes stored and
+// the method that was used
+func (m *Manager) capture(source, dir string, method pb.SnapshotMethod) (int64, pb.SnapshotMethod, error) {
+	if method != pb.SnapshotMethod_SNAPSHOT_METHOD_TARBALL {
+		target := filepath.Join(dir, reflinkDir)
+		err := reflinkTree(source, target)
+		if err == nil {
+			// Reflinked extents are shared with the volume until either side
+			// is modified, so the snapshot occupies no extra space up front
+			return 0, pb.SnapshotMethod_SNAPSHOT_METHOD_REFLINK, nil
+		}
+		os.RemoveAll(target)
+		if method == pb.SnapshotMethod_SNAPSHOT_METHOD_REFLINK || !errors.Is(err, ErrReflinkUnsupported) {
+			return 0, method, err
+		}
+	}
+
+	archive := filepath.Join(dir, tarballFile)
+	if err := writeTarball(source, archive); err != nil {
+		return 0, method, err
+	}
+	info, err := os.Stat(archive)
+	if err != nil {
+		return 0, method, err
+	}
+	return info.Size(), pb.SnapshotMethod_SNAPSHOT_METHOD_TARBALL, nil
+}
+
+// Get returns a snapshot of a volume
+func (m *Manager) Get(volumeName, id string) (*pb.VolumeSnapshot, error) {
+	if volume.ValidateName(volumeName) != nil || !idPattern.MatchString(id) {
+		return nil, fmt.Errorf("%w: %s/%s", ErrNotFound, volumeName, id)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.read(volumeName, id)
+}
+
+// List returns the snapshots of a volume, oldest first
+func (m *Manager) List(volumeName string) ([]*pb.VolumeSnapshot, error) {
+	if err := volume.ValidateName(volumeName); err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entries, err := os.ReadDir(filepath.Join(m.root, volumeName))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshots of volume %s: %w", volumeName, err)
+	}
+
+	var snapshots []*pb.VolumeSnapshot
+	for _, entry := range entries {
+		if !entry.IsDir() || !idPattern.MatchString(entry.Name()) {
+			continue
+		}
+		snapshot, err := m.read(volumeName, entry.Name())
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		left, right := snapshots[i].GetCreatedAt().AsTime(), snapshots[j].GetCreatedAt().AsTime()
+		if left.Equal(right) {
+			return snapshots[i].GetId() < snapshots[j].GetId()
+		}
+		return left.Before(right)
+	})
+	return snapshots, nil
+}
+
+// Delete removes a snapshot
+func (m *Manager) Delete(volumeName, id string) error {
+	if volume.ValidateName(volumeName) != nil || !idPattern.MatchString(id) {
+		return fmt.Errorf("%w: %s/%s", ErrNotFound, volumeName, id)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := os.RemoveAll(m.dir(volumeName, id)); err != nil {
+		return fmt.Errorf("failed to remove snapshot %s: %w", id, err)
+	}
+	return nil
+}
+
+// Restore replaces the data of the target volume with the contents of a
+// snapshot taken from volumeName
+func (m *Manager) Restore(volumeName, id, target string) error {
+	snapshot, err := m.Get(volumeName, id)
+	if err != nil {
+		return err
+	}
+
+	dir := m.dir(volumeName, id)
+	return m.volumes.ReplaceData(target, func(staging string) error {
+		switch snapshot.GetMethod() {
+		case pb.SnapshotMethod_SNAPSHOT_METHOD_REFLINK:
+			return copyTree(filepath.Join(dir, reflinkDir), staging)
+		default:
+			return extractTarball(filepath.Join(dir, tarballFile), staging)
+		}
+	})
+}
+
+func (m *Manager) read(volumeName, id string) (*pb.VolumeSnapshot, error) {
+	data, err := os.ReadFile(filepath.Join(m.dir(volumeName, id), metadataFile))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w: %s/%s", ErrNotFound, volumeName, id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot %s: %w", id, err)
+	}
+
+	snapshot := &pb.VolumeSnapshot{}
+	if err := protojson.Unmarshal(data, snapshot); err != nil {
+		return nil, fmt.Errorf("failed to decode snapshot %s: %w", id, err)
+	}
+	return snapshot, nil
+}
+
+func (m *Manager) dir(volumeName, id string) string {
+	return filepath.Join(m.root, volumeName, id)
+}
+
+// newID generates a snapshot ID that sorts by creation time
+func newID(now time.Time) (string, error) {
+	buf := make([]byte, 4)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return now.UTC().Format("20060102T150405Z") + "-" + hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,254 @@
+package snapshot
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"scheduler/internal/volume"
+	pb "scheduler/proto/gen"
+)
+
+// newTestManager returns a snapshot manager over a fresh volume manager
+// holding one volume named data
+func newTestManager(t *testing.T) (*Manager, *volume.Manager) {
+	t.Helper()
+	root := t.TempDir()
+	volumes, err := volume.NewManager(filepath.Join(root, "volumes"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := volumes.Create("data", "default", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	m, err := NewManager(filepath.Join(root, "snapshots"), volumes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m, volumes
+}
+
+// writeTree populates dir with a file, a nested file and a symlink
+func writeTree(t *testing.T, dir string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Join(dir, "nested"), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("alpha"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "nested", "b.txt"), []byte("beta"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("a.txt", filepath.Join(dir, "link")); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// checkTree verifies that dir holds what writeTree wrote
+func checkTree(t *testing.T, dir string) {
+	t.Helper()
+	for path, want := range map[string]string{"a.txt": "alpha", "nested/b.txt": "beta"} {
+		data, err := os.ReadFile(filepath.Join(dir, path))
+		if err != nil || string(data) != want {
+			t.Errorf("%s = %q, %v, want %q", path, data, err, want)
+		}
+	}
+	if info, err := os.Stat(filepath.Join(dir, "a.txt")); err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("mode of a.txt = %v, %v, want 0600", info, err)
+	}
+	if link, err := os.Readlink(filepath.Join(dir, "link")); err != nil || link != "a.txt" {
+		t.Errorf("link = %q, %v, want a.txt", link, err)
+	}
+}
+
+func TestTarballSnapshot(t *testing.T) {
+	m, volumes := newTestManager(t)
+	writeTree(t, volumes.DataPath("data"))
+
+	snapshot, err := m.Create("data", pb.SnapshotMethod_SNAPSHOT_METHOD_TARBALL, map[string]string{"reason": "test"}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snapshot.GetMethod() != pb.SnapshotMethod_SNAPSHOT_METHOD_TARBALL || snapshot.GetStoredBytes() == 0 {
+		t.Errorf("snapshot = %v, want a non-empty tarball", snapshot)
+	}
+	if snapshot.GetSizeBytes() != int64(len("alpha")+len("beta")) {
+		t.Errorf("SizeBytes = %d, want %d", snapshot.GetSizeBytes(), len("alpha")+len("beta"))
+	}
+
+	got, err := m.Get("data", snapshot.GetId())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.GetLabels()["reason"] != "test" {
+		t.Errorf("Get() = %v, want the labels of the snapshot", got)
+	}
+
+	// Restoring replaces whatever the volume holds now
+	if err := os.RemoveAll(filepath.Join(volumes.DataPath("data"), "nested")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(volumes.DataPath("data"), "extra"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Restore("data", snapshot.GetId(), "data"); err != nil {
+		t.Fatal(err)
+	}
+	checkTree(t, volumes.DataPath("data"))
+	if _, err := os.Stat(filepath.Join(volumes.DataPath("data"), "extra")); !os.IsNotExist(err) {
+		t.Errorf("file written after the snapshot = %v, want it gone", err)
+	}
+
+	// Snapshots can be restored into other volumes
+	if _, err := volumes.Create("copy", "default", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Restore("data", snapshot.GetId(), "copy"); err != nil {
+		t.Fatal(err)
+	}
+	checkTree(t, volumes.DataPath("copy"))
+}
+
+func TestDefaultMethod(t *testing.T) {
+	m, volumes := newTestManager(t)
+	writeTree(t, volumes.DataPath("data"))
+
+	// Without a method the snapshot is reflinked where the filesystem
+	// allows and archived otherwise
+	snapshot, err := m.Create("data", pb.SnapshotMethod_SNAPSHOT_METHOD_UNSPECIFIED, nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	switch snapshot.GetMethod() {
+	case pb.SnapshotMethod_SNAPSHOT_METHOD_REFLINK:
+		if snapshot.GetStoredBytes() != 0 {
+			t.Errorf("StoredBytes of a reflink snapshot = %d, want 0", snapshot.GetStoredBytes())
+		}
+	case pb.SnapshotMethod_SNAPSHOT_METHOD_TARBALL:
+		if _, err := m.Create("data", pb.SnapshotMethod_SNAPSHOT_METHOD_REFLINK, nil, false); !errors.Is(err, ErrReflinkUnsupported) {
+			t.Errorf("forcing a reflink snapshot = %v, want ErrReflinkUnsupported", err)
+		}
+	default:
+		t.Fatalf("Method = %v, want reflink or tarball", snapshot.GetMethod())
+	}
+
+	if err := m.Restore("data", snapshot.GetId(), "data"); err != nil {
+		t.Fatal(err)
+	}
+	checkTree(t, volumes.DataPath("data"))
+
+	snapshots, err := m.List("data")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snapshots) != 1 {
+		t.Errorf("List() = %v, want only the successful snapshot", snapshots)
+	}
+}
+
+func TestGetAndDelete(t *testing.T) {
+	m, _ := newTestManager(t)
+	snapshot, err := m.Create("data", pb.SnapshotMethod_SNAPSHOT_METHOD_TARBALL, nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, id := range []string{"../../volumes", "20240101T000000Z-00000000"} {
+		if _, err := m.Get("data", id); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Get(%q) = %v, want ErrNotFound", id, err)
+		}
+	}
+	if _, err := m.Create("missing", pb.SnapshotMethod_SNAPSHOT_METHOD_TARBALL, nil, false); !errors.Is(err, volume.ErrNotFound) {
+		t.Errorf("snapshotting a missing volume = %v, want volume.ErrNotFound", err)
+	}
+
+	if err := m.Delete("data", snapshot.GetId()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Get("data", snapshot.GetId()); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get after delete = %v, want ErrNotFound", err)
+	}
+	if snapshots, err := m.List("never-snapshotted"); err != nil || len(snapshots) != 0 {
+		t.Errorf("List() of a volume without snapshots = %v, %v, want none", snapshots, err)
+	}
+}
+
+func TestSchedule(t *testing.T) {
+	m, volumes := newTestManager(t)
+	if _, err := volumes.Create("unscheduled", "default", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	policy := &pb.SnapshotPolicy{IntervalSeconds: 3600, Retain: 2}
+	if _, err := volumes.Update("data", nil, policy); err != nil {
+		t.Fatal(err)
+	}
+	manual, err := m.Create("data", pb.SnapshotMethod_SNAPSHOT_METHOD_TARBALL, nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	m.runScheduled(now)
+	// Not due again before the interval has passed
+	m.runScheduled(now.Add(time.Minute))
+	if snapshots, _ := m.List("data"); len(snapshots) != 2 {
+		t.Fatalf("snapshots within the interval = %d, want the manual and one scheduled", len(snapshots))
+	}
+
+	m.runScheduled(now.Add(2 * time.Hour))
+	m.runScheduled(now.Add(4 * time.Hour))
+	snapshots, err := m.List("data")
+	if err != nil {
+		t.Fatal(err)
+	}
+	scheduled := 0
+	keptManual := false
+	for _, snapshot := range snapshots {
+		if snapshot.GetScheduled() {
+			scheduled++
+		}
+		if snapshot.GetId() == manual.GetId() {
+			keptManual = true
+		}
+	}
+	if scheduled != 2 || !keptManual {
+		t.Errorf("snapshots = %v, want two scheduled ones and the manual one", snapshots)
+	}
+	if snapshots, _ := m.List("unscheduled"); len(snapshots) != 0 {
+		t.Errorf("snapshots of a volume without policy = %v, want none", snapshots)
+	}
+}
+
+func TestExtractTarballRejectsEscapes(t *testing.T) {
+	archive := filepath.Join(t.TempDir(), "evil.tar.gz")
+	file, err := os.Create(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	compressed := gzip.NewWriter(file)
+	writer := tar.NewWriter(compressed)
+	if err := writer.WriteHeader(&tar.Header{Name: "../escaped", Typeflag: tar.TypeReg, Mode: 0o644}); err != nil {
+		t.Fatal(err)
+	}
+	for _, closer := range []interface{ Close() error }{writer, compressed, file} {
+		if err := closer.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	target := filepath.Join(t.TempDir(), "target")
+	if err := os.MkdirAll(target, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := extractTarball(archive, target); err == nil {
+		t.Error("extracting an entry outside the target succeeded")
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(target), "escaped")); !os.IsNotExist(err) {
+		t.Errorf("escaped file = %v, want it not written", err)
+	}
+}
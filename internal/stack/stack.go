@@ -0,0 +1,95 @@
+package stack
+
+import (
+	"fmt"
+	"sort"
+
+	pb "scheduler/proto/gen"
+)
+
+// Role aliases of the well-known containers of an application stack
+const (
+	RoleFrontend = "frontend"
+	RoleBackend  = "backend"
+	RoleDatabase = "database"
+)
+
+// Member is a container of an application stack together with the alias it
+// is known by inside the environment
+type Member struct {
+	// Alias is the role name for the frontend, backend and database
+	// containers and the service key for additional services
+	Alias     string
+	Container *pb.ContainerConfig
+	// Additional is set for containers from ApplicationStack.additional_services
+	Additional bool
+}
+
+// Name returns the container name, falling back to the alias when the
+// container configuration does not set one
+func (m Member) Name() string {
+	if m.Container.GetName() != "" {
+		return m.Container.GetName()
+	}
+	return m.Alias
+}
+
+// String describes the member for use in error messages
+func (m Member) String() string {
+	if m.Additional {
+		return fmt.Sprintf("additional service %q", m.Alias)
+	}
+	return m.Alias + " container"
+}
+
+// Members returns the containers of a stack in startup order: database,
+// backend, frontend and then the additional services sorted by key
+func Members(stack *pb.ApplicationStack) []Member {
+	var members []Member
+	if container := stack.GetDatabase().GetContainer(); container != nil {
+		members = append(members, Member{Alias: RoleDatabase, Container: container})
+	}
+	if container := stack.GetBackend().GetContainer(); container != nil {
+		members = append(members, Member{Alias: RoleBackend, Container: container})
+	}
+	if container := stack.GetFrontend().GetContainer(); container != nil {
+		members = append(members, Member{Alias: RoleFrontend, Container: container})
+	}
+
+	keys := make([]string, 0, len(stack.GetAdditionalServices()))
+	for key := range stack.GetAdditionalServices() {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		members = append(members, Member{Alias: key, Container: stack.GetAdditionalServices()[key], Additional: true})
+	}
+
+	return members
+}
+
+// Validate checks that every container of the stack can be addressed by a
+// unique name and alias
+func Validate(stack *pb.ApplicationStack) error {
+	owners := make(map[string]string)
+	claim := func(name, owner string) error {
+		if previous, ok := owners[name]; ok && previous != owner {
+			return fmt.Errorf("name %q is used by both %s and %s", name, previous, owner)
+		}
+		owners[name] = owner
+		return nil
+	}
+
+	for _, member := range Members(stack) {
+		if member.Container.GetImage() == "" {
+			return fmt.Errorf("%s image is required", member)
+		}
+		if err := claim(member.Alias, member.String()); err != nil {
+			return err
+		}
+		if err := claim(member.Name(), member.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
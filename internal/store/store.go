@@ -0,0 +1,154 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"scheduler/internal/fsutil"
+	pb "scheduler/proto/gen"
+)
+
+var (
+	// ErrNotFound is returned when an environment does not exist
+	ErrNotFound = errors.New("environment not found")
+	// ErrAlreadyExists is returned when creating an environment whose ID is taken
+	ErrAlreadyExists = errors.New("environment already exists")
+)
+
+const environmentsDir = "environments"
+
+// Store persists environments as JSON documents under a data directory and
+// keeps an in-memory copy for reads
+type Store struct {
+	mu           sync.RWMutex
+	dir          string
+	environments map[string]*pb.Environment
+}
+
+// New opens the store rooted at dataDir, loading any previously persisted
+// environments
+func New(dataDir string) (*Store, error) {
+	dir := filepath.Join(dataDir, environmentsDir)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create store directory %s: %w", dir, err)
+	}
+
+	s := &Store{
+		dir:          dir,
+		environments: make(map[string]*pb.Environment),
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read store directory %s: %w", dir, err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		env := &pb.Environment{}
+		if err := protojson.Unmarshal(data, env); err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", path, err)
+		}
+		s.environments[env.GetId()] = env
+	}
+
+	return s, nil
+}
+
+// Create persists a new environment
+func (s *Store) Create(env *pb.Environment) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.environments[env.GetId()]; ok {
+		return fmt.Errorf("%w: %s", ErrAlreadyExists, env.GetId())
+	}
+	return s.write(env)
+}
+
+// Get returns a copy of the environment with the given ID
+func (s *Store) Get(id string) (*pb.Environment, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	env, ok := s.environments[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	return proto.Clone(env).(*pb.Environment), nil
+}
+
+// Update replaces a previously created environment
+func (s *Store) Update(env *pb.Environment) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.environments[env.GetId()]; !ok {
+		return fmt.Errorf("%w: %s", ErrNotFound, env.GetId())
+	}
+	return s.write(env)
+}
+
+// Delete removes the environment with the given ID
+func (s *Store) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.environments[id]; !ok {
+		return fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	if err := os.Remove(s.path(id)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove environment %s: %w", id, err)
+	}
+	delete(s.environments, id)
+	return nil
+}
+
+// List returns copies of all environments ordered by creation time
+func (s *Store) List() []*pb.Environment {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	environments := make([]*pb.Environment, 0, len(s.environments))
+	for _, env := range s.environments {
+		environments = append(environments, proto.Clone(env).(*pb.Environment))
+	}
+	sort.Slice(environments, func(i, j int) bool {
+		left, right := environments[i].GetCreatedAt().AsTime(), environments[j].GetCreatedAt().AsTime()
+		if left.Equal(right) {
+			return environments[i].GetId() < environments[j].GetId()
+		}
+		return left.Before(right)
+	})
+	return environments
+}
+
+func (s *Store) write(env *pb.Environment) error {
+	data, err := protojson.MarshalOptions{Multiline: true}.Marshal(env)
+	if err != nil {
+		return fmt.Errorf("failed to encode environment %s: %w", env.GetId(), err)
+	}
+	if err := fsutil.WriteFileAtomic(s.path(env.GetId()), data, 0o600); err != nil {
+		return err
+	}
+	s.environments[env.GetId()] = proto.Clone(env).(*pb.Environment)
+	return nil
+}
+
+func (s *Store) path(id string) string {
+	return filepath.Join(s.dir, id+".json")
+}
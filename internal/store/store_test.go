@@ -0,0 +1,141 @@
+package store
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "scheduler/proto/gen"
+)
+
+func testEnvironment(id string, created time.Time) *pb.Environment {
+	return &pb.Environment{
+		Id:        id,
+		Name:      "env-" + id,
+		Status:    pb.EnvironmentStatus_ENVIRONMENT_STATUS_PENDING,
+		CreatedAt: timestamppb.New(created),
+	}
+}
+
+func TestStore(t *testing.T) {
+	s, err := New(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	env := testEnvironment("a", time.Unix(100, 0))
+	if err := s.Create(env); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Create(env); !errors.Is(err, ErrAlreadyExists) {
+		t.Errorf("creating twice = %v, want ErrAlreadyExists", err)
+	}
+
+	got, err := s.Get("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(got, env) {
+		t.Errorf("Get = %v, want %v", got, env)
+	}
+	// Callers get copies, so changing one leaves the store alone
+	got.Name = "changed"
+	if again, _ := s.Get("a"); again.GetName() != "env-a" {
+		t.Errorf("name after changing a copy = %q, want env-a", again.GetName())
+	}
+
+	env.Status = pb.EnvironmentStatus_ENVIRONMENT_STATUS_RUNNING
+	if err := s.Update(env); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := s.Get("a"); got.GetStatus() != pb.EnvironmentStatus_ENVIRONMENT_STATUS_RUNNING {
+		t.Errorf("status after update = %v, want RUNNING", got.GetStatus())
+	}
+	if err := s.Update(testEnvironment("missing", time.Unix(0, 0))); !errors.Is(err, ErrNotFound) {
+		t.Errorf("updating a missing environment = %v, want ErrNotFound", err)
+	}
+
+	if err := s.Delete("a"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Get("a"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get after delete = %v, want ErrNotFound", err)
+	}
+	if err := s.Delete("a"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("deleting twice = %v, want ErrNotFound", err)
+	}
+}
+
+func TestListOrder(t *testing.T) {
+	s, err := New(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, env := range []*pb.Environment{
+		testEnvironment("c", time.Unix(300, 0)),
+		testEnvironment("b", time.Unix(100, 0)),
+		testEnvironment("a", time.Unix(100, 0)),
+	} {
+		if err := s.Create(env); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var ids []string
+	for _, env := range s.List() {
+		ids = append(ids, env.GetId())
+	}
+	// Creation time first, IDs break ties
+	want := []string{"a", "b", "c"}
+	if len(ids) != len(want) {
+		t.Fatalf("List = %v, want %v", ids, want)
+	}
+	for i := range want {
+		if ids[i] != want[i] {
+			t.Fatalf("List = %v, want %v", ids, want)
+		}
+	}
+}
+
+func TestReopen(t *testing.T) {
+	dir := t.TempDir()
+	s, err := New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	env := testEnvironment("a", time.Unix(100, 0))
+	if err := s.Create(env); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Create(testEnvironment("b", time.Unix(200, 0))); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Delete("b"); err != nil {
+		t.Fatal(err)
+	}
+	// Files that are not environment documents are ignored
+	if err := os.WriteFile(filepath.Join(dir, environmentsDir, "notes.txt"), []byte("x"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	environments := reopened.List()
+	if len(environments) != 1 || !proto.Equal(environments[0], env) {
+		t.Errorf("environments after reopening = %v, want only %v", environments, env)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, environmentsDir, "broken.json"), []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := New(dir); err == nil {
+		t.Error("opening a store with a corrupt document succeeded")
+	}
+}
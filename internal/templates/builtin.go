@@ -0,0 +1,191 @@
+package templates
+
+import (
+	"strconv"
+	"strings"
+
+	"scheduler/internal/stack"
+	pb "scheduler/proto/gen"
+)
+
+// Parameters shared by the templates with a PostgreSQL database
+var databaseParameters = []*pb.TemplateParameter{
+	stringParameter("postgres_version", "PostgreSQL image tag", "16"),
+	stringParameter("database_name", "name of the application database", "app"),
+	stringParameter("database_user", "owner of the application database", "app"),
+	{
+		Name:        "database_password",
+		Description: "password of the database user, preferably a secret://<name> reference",
+		Type:        pb.ParameterType_PARAMETER_TYPE_STRING,
+		Required:    true,
+	},
+	booleanParameter("persistent_storage", "keep the database files in a named volume", true),
+	integerParameter("database_memory_mb", "memory limit of the database container", 1024),
+}
+
+// builtin returns the templates compiled into the scheduler
+func builtin() []*Template {
+	return []*Template{
+		{
+			Name:        "nginx-node-postgres",
+			Version:     1,
+			Builtin:     true,
+			Description: "Nginx in front of a Node.js backend with a PostgreSQL database",
+			Parameters: append([]*pb.TemplateParameter{
+				stringParameter("frontend_image", "Nginx image, configured to proxy to http://backend:<backend_port>", "nginx:1.27-alpine"),
+				integerParameter("http_port", "host port Nginx is published on", 8080),
+				stringParameter("backend_image", "Node.js image holding the application", "node:20-alpine"),
+				stringParameter("backend_command", "command starting the application", "node server.js"),
+				integerParameter("backend_port", "port the application listens on", 3000),
+				integerParameter("backend_memory_mb", "memory limit of the backend container", 512),
+			}, databaseParameters...),
+			build: buildNginxNodePostgres,
+		},
+		{
+			Name:        "static-go-postgres",
+			Version:     1,
+			Builtin:     true,
+			Description: "Static frontend served by Nginx, a Go API and a PostgreSQL database",
+			Parameters: append([]*pb.TemplateParameter{
+				{
+					Name:        "frontend_image",
+					Description: "image serving the built static site on port 80",
+					Type:        pb.ParameterType_PARAMETER_TYPE_STRING,
+					Required:    true,
+				},
+				integerParameter("http_port", "host port the site is published on", 8080),
+				{
+					Name:        "api_image",
+					Description: "image holding the Go API binary as its entrypoint",
+					Type:        pb.ParameterType_PARAMETER_TYPE_STRING,
+					Required:    true,
+				},
+				integerParameter("api_port", "port the API listens on and is published on", 8081),
+				integerParameter("api_memory_mb", "memory limit of the API container", 256),
+			}, databaseParameters...),
+			build: buildStaticGoPostgres,
+		},
+	}
+}
+
+func buildNginxNodePostgres(name string, values Values) (*pb.EnvironmentSpecification, error) {
+	backendPort := values.Int("backend_port")
+	backend := &pb.ContainerConfig{
+		Name:    name + "-backend",
+		Image:   values.String("backend_image"),
+		Command: strings.Fields(values.String("backend_command")),
+		Ports:   []*pb.PortMapping{{ContainerPort: backendPort, Protocol: "tcp"}},
+		EnvironmentVariables: map[string]string{
+			"NODE_ENV": "production",
+			"PORT":     strconv.Itoa(int(backendPort)),
+		},
+		Resources:     &pb.ResourceLimits{MemoryMb: int64(values.Int("backend_memory_mb")), CpuCores: 1},
+		RestartPolicy: pb.RestartPolicy_RESTART_POLICY_UNLESS_STOPPED,
+	}
+
+	return &pb.EnvironmentSpecification{
+		Description: "Nginx, Node.js and PostgreSQL",
+		ApplicationStack: &pb.ApplicationStack{
+			Name: name,
+			Frontend: &pb.FrontendConfig{Container: &pb.ContainerConfig{
+				Name:  name + "-frontend",
+				Image: values.String("frontend_image"),
+				Ports: []*pb.PortMapping{{ContainerPort: 80, HostPort: values.Int("http_port"), Protocol: "tcp"}},
+				EnvironmentVariables: map[string]string{
+					"BACKEND_URL": "http://" + stack.RoleBackend + ":" + strconv.Itoa(int(backendPort)),
+				},
+				Resources:     &pb.ResourceLimits{MemoryMb: 128, CpuCores: 0.5},
+				HealthCheck:   httpHealthCheck("http://localhost/"),
+				RestartPolicy: pb.RestartPolicy_RESTART_POLICY_UNLESS_STOPPED,
+			}},
+			Backend:  &pb.BackendConfig{Container: backend},
+			Database: database(name, values),
+		},
+	}, nil
+}
+
+func buildStaticGoPostgres(name string, values Values) (*pb.EnvironmentSpecification, error) {
+	apiPort := values.Int("api_port")
+	api := &pb.ContainerConfig{
+		Name:  name + "-api",
+		Image: values.String("api_image"),
+		Ports: []*pb.PortMapping{{ContainerPort: apiPort, HostPort: apiPort, Protocol: "tcp"}},
+		EnvironmentVariables: map[string]string{
+			"PORT": strconv.Itoa(int(apiPort)),
+		},
+		Resources:     &pb.ResourceLimits{MemoryMb: int64(values.Int("api_memory_mb")), CpuCores: 1},
+		RestartPolicy: pb.RestartPolicy_RESTART_POLICY_UNLESS_STOPPED,
+	}
+
+	return &pb.EnvironmentSpecification{
+		Description: "Static frontend, Go API and PostgreSQL",
+		ApplicationStack: &pb.ApplicationStack{
+			Name: name,
+			Frontend: &pb.FrontendConfig{Container: &pb.ContainerConfig{
+				Name:          name + "-frontend",
+				Image:         values.String("frontend_image"),
+				Ports:         []*pb.PortMapping{{ContainerPort: 80, HostPort: values.Int("http_port"), Protocol: "tcp"}},
+				Resources:     &pb.ResourceLimits{MemoryMb: 64, CpuCores: 0.25},
+				HealthCheck:   httpHealthCheck("http://localhost/"),
+				RestartPolicy: pb.RestartPolicy_RESTART_POLICY_UNLESS_STOPPED,
+			}},
+			Backend:  &pb.BackendConfig{Container: api},
+			Database: database(name, values),
+		},
+	}, nil
+}
+
+// database returns the PostgreSQL container of the database parameters. The
+// scheduler passes the credentials to the database and the backend.
+func database(name string, values Values) *pb.DatabaseConfig {
+	user, databaseName := values.String("database_user"), values.String("database_name")
+	container := &pb.ContainerConfig{
+		Name:  name + "-database",
+		Image: "postgres:" + values.String("postgres_version") + "-alpine",
+		Ports: []*pb.PortMapping{{ContainerPort: 5432, Protocol: "tcp"}},
+		EnvironmentVariables: map[string]string{
+			"PGDATA": "/var/lib/postgresql/data/pgdata",
+		},
+		Resources: &pb.ResourceLimits{MemoryMb: int64(values.Int("database_memory_mb")), CpuCores: 1},
+		HealthCheck: &pb.HealthCheck{
+			Command:            []string{"pg_isready", "-U", user, "-d", databaseName},
+			IntervalSeconds:    10,
+			TimeoutSeconds:     5,
+			Retries:            5,
+			StartPeriodSeconds: 30,
+		},
+		RestartPolicy: pb.RestartPolicy_RESTART_POLICY_UNLESS_STOPPED,
+	}
+	if values.Bool("persistent_storage") {
+		container.Volumes = []*pb.VolumeMount{{Name: name + "-postgres-data", MountPath: "/var/lib/postgresql/data"}}
+	}
+	return &pb.DatabaseConfig{
+		Container:         container,
+		DatabaseName:      databaseName,
+		Username:          user,
+		Password:          values.String("database_password"),
+		PersistentStorage: values.Bool("persistent_storage"),
+	}
+}
+
+// httpHealthCheck probes url with the wget of Alpine based images
+func httpHealthCheck(url string) *pb.HealthCheck {
+	return &pb.HealthCheck{
+		Command:         []string{"wget", "-q", "--spider", url},
+		IntervalSeconds: 30,
+		TimeoutSeconds:  5,
+		Retries:         3,
+	}
+}
+
+func stringParameter(name, description, defaultValue string) *pb.TemplateParameter {
+	return &pb.TemplateParameter{Name: name, Description: description, Type: pb.ParameterType_PARAMETER_TYPE_STRING, DefaultValue: defaultValue}
+}
+
+func integerParameter(name, description string, defaultValue int) *pb.TemplateParameter {
+	return &pb.TemplateParameter{Name: name, Description: description, Type: pb.ParameterType_PARAMETER_TYPE_INTEGER, DefaultValue: strconv.Itoa(defaultValue)}
+}
+
+func booleanParameter(name, description string, defaultValue bool) *pb.TemplateParameter {
+	return &pb.TemplateParameter{Name: name, Description: description, Type: pb.ParameterType_PARAMETER_TYPE_BOOLEAN, DefaultValue: strconv.FormatBool(defaultValue)}
+}
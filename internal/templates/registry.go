@@ -0,0 +1,246 @@
+package templates
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"scheduler/internal/fsutil"
+	pb "scheduler/proto/gen"
+)
+
+const templateExtension = ".json"
+
+// validName matches template names, which are also directory names
+var validName = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// Registry holds the built-in templates and the user templates registered at
+// runtime. Every version of a user template is kept as a JSON document under
+// a directory per template.
+type Registry struct {
+	mu      sync.Mutex
+	builtin map[string]*Template
+	// root is empty when user templates cannot be registered
+	root string
+}
+
+// BuiltinRegistry returns a registry holding only the built-in templates
+func BuiltinRegistry() *Registry {
+	r := &Registry{builtin: make(map[string]*Template)}
+	for _, t := range builtin() {
+		r.builtin[t.Name] = t
+	}
+	return r
+}
+
+// NewRegistry returns a registry of the built-in templates and the user
+// templates stored under root
+func NewRegistry(root string) (*Registry, error) {
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create template directory %s: %w", root, err)
+	}
+	r := BuiltinRegistry()
+	r.root = root
+	return r, nil
+}
+
+// List returns the latest version of every template sorted by name
+func (r *Registry) List() ([]*Template, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	list := make([]*Template, 0, len(r.builtin))
+	for _, t := range r.builtin {
+		list = append(list, t)
+	}
+	names, err := r.names()
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		t, err := r.read(name, 0)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, t)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list, nil
+}
+
+// Get returns a version of a template, the latest for version 0
+func (r *Registry) Get(name string, version int32) (*Template, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if t, ok := r.builtin[name]; ok {
+		if version != 0 && version != t.Version {
+			return nil, fmt.Errorf("%w: %s version %d", ErrNotFound, name, version)
+		}
+		return t, nil
+	}
+	return r.read(name, version)
+}
+
+// Register checks a template manifest and stores it as the next version of
+// the template it names
+func (r *Registry) Register(source []byte) (*Template, error) {
+	if r.root == "" {
+		return nil, ErrDisabled
+	}
+	t, err := parseUserTemplate(string(source))
+	if err != nil {
+		return nil, err
+	}
+	if err := validate(t); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.builtin[t.Name]; ok {
+		return nil, fmt.Errorf("%w: %s", ErrBuiltin, t.Name)
+	}
+	versions, err := r.versions(t.Name)
+	if err != nil {
+		return nil, err
+	}
+	t.Version = 1
+	if len(versions) > 0 {
+		t.Version = versions[len(versions)-1] + 1
+	}
+	t.CreatedAt = timestamppb.Now()
+
+	data, err := protojson.MarshalOptions{Indent: "  "}.Marshal(t.Proto())
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode template: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Join(r.root, t.Name), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create template directory: %w", err)
+	}
+	if err := fsutil.WriteFileAtomic(r.path(t.Name, t.Version), data, 0o600); err != nil {
+		return nil, fmt.Errorf("failed to write template: %w", err)
+	}
+	return t, nil
+}
+
+// Delete removes every version of a user template. Environments created from
+// it keep their specification.
+func (r *Registry) Delete(name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.builtin[name]; ok {
+		return fmt.Errorf("%w: %s", ErrBuiltin, name)
+	}
+	if r.root == "" || !validName.MatchString(name) {
+		return fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	versions, err := r.versions(name)
+	if err != nil {
+		return err
+	}
+	if len(versions) == 0 {
+		return fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	if err := os.RemoveAll(filepath.Join(r.root, name)); err != nil {
+		return fmt.Errorf("failed to delete template %s: %w", name, err)
+	}
+	return nil
+}
+
+// read loads a version of a user template, the latest for version 0
+func (r *Registry) read(name string, version int32) (*Template, error) {
+	if r.root == "" || !validName.MatchString(name) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	if version == 0 {
+		versions, err := r.versions(name)
+		if err != nil {
+			return nil, err
+		}
+		if len(versions) == 0 {
+			return nil, fmt.Errorf("%w: %s", ErrNotFound, name)
+		}
+		version = versions[len(versions)-1]
+	}
+
+	data, err := os.ReadFile(r.path(name, version))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s version %d", ErrNotFound, name, version)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template: %w", err)
+	}
+	stored := &pb.Template{}
+	if err := protojson.Unmarshal(data, stored); err != nil {
+		return nil, fmt.Errorf("failed to decode template %s version %d: %w", name, version, err)
+	}
+	t, err := parseUserTemplate(stored.GetSource())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load template %s version %d: %w", name, version, err)
+	}
+	t.Version = stored.GetVersion()
+	t.CreatedAt = stored.GetCreatedAt()
+	return t, nil
+}
+
+// names returns the names of the stored user templates
+func (r *Registry) names() ([]string, error) {
+	if r.root == "" {
+		return nil, nil
+	}
+	entries, err := os.ReadDir(r.root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list templates: %w", err)
+	}
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() && validName.MatchString(entry.Name()) {
+			names = append(names, entry.Name())
+		}
+	}
+	return names, nil
+}
+
+// versions returns the stored versions of a template in ascending order
+func (r *Registry) versions(name string) ([]int32, error) {
+	entries, err := os.ReadDir(filepath.Join(r.root, name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list versions of template %s: %w", name, err)
+	}
+	var versions []int32
+	for _, entry := range entries {
+		base, ok := strings.CutSuffix(entry.Name(), templateExtension)
+		if !ok || entry.IsDir() {
+			continue
+		}
+		version, err := strconv.ParseInt(base, 10, 32)
+		if err != nil {
+			continue
+		}
+		versions = append(versions, int32(version))
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i] < versions[j] })
+	return versions, nil
+}
+
+func (r *Registry) path(name string, version int32) string {
+	return filepath.Join(r.root, name, strconv.Itoa(int(version))+templateExtension)
+}
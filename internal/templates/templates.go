@@ -0,0 +1,191 @@
+package templates
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "scheduler/proto/gen"
+)
+
+var (
+	// ErrNotFound is returned when no template has the requested name
+	ErrNotFound = errors.New("template not found")
+	// ErrInvalidParameters is returned when parameter values do not match
+	// the parameters a template declares
+	ErrInvalidParameters = errors.New("invalid template parameters")
+	// ErrInvalidTemplate is returned when a template manifest cannot be
+	// registered
+	ErrInvalidTemplate = errors.New("invalid template")
+	// ErrBuiltin is returned when registering or deleting a template with
+	// the name of a built-in one
+	ErrBuiltin = errors.New("built-in templates cannot be changed")
+	// ErrDisabled is returned when registering templates in a registry
+	// without storage
+	ErrDisabled = errors.New("user templates are not enabled")
+)
+
+// LabelTemplate is the label naming the template an environment was
+// expanded from
+const LabelTemplate = "template"
+
+// Template is a stack definition that expands into an environment
+// specification from a set of typed parameter values
+type Template struct {
+	Name        string
+	Description string
+	Parameters  []*pb.TemplateParameter
+	Version     int32
+	Builtin     bool
+	// Source is the manifest a user template was registered from
+	Source    string
+	CreatedAt *timestamppb.Timestamp
+	// build returns the specification for the environment name and the
+	// checked parameter values
+	build func(name string, values Values) (*pb.EnvironmentSpecification, error)
+}
+
+// Proto describes the template for the API
+func (t *Template) Proto() *pb.Template {
+	parameters := make([]*pb.TemplateParameter, len(t.Parameters))
+	for i, parameter := range t.Parameters {
+		parameters[i] = proto.Clone(parameter).(*pb.TemplateParameter)
+	}
+	return &pb.Template{
+		Name:        t.Name,
+		Description: t.Description,
+		Parameters:  parameters,
+		Version:     t.Version,
+		Builtin:     t.Builtin,
+		Source:      t.Source,
+		CreatedAt:   t.CreatedAt,
+	}
+}
+
+// Reference names the template and version for the environments created
+// from it
+func (t *Template) Reference() *pb.TemplateReference {
+	return &pb.TemplateReference{Name: t.Name, Version: t.Version}
+}
+
+// Expand checks params against the parameters of the template and returns
+// the specification of an environment called name. Every environment
+// expanded from a template carries its name in the template label.
+func (t *Template) Expand(name string, params map[string]string) (*pb.EnvironmentSpecification, error) {
+	values, err := resolve(t.Parameters, params)
+	if err != nil {
+		return nil, err
+	}
+	spec, err := t.build(name, values)
+	if err != nil {
+		return nil, err
+	}
+	spec.Name = name
+	if spec.Labels == nil {
+		spec.Labels = make(map[string]string)
+	}
+	spec.Labels[LabelTemplate] = t.Name
+	return spec, nil
+}
+
+// Values holds the parameter values of an expansion, checked against the
+// parameter types and with defaults filled in
+type Values map[string]string
+
+// String returns the value of a parameter
+func (v Values) String(name string) string {
+	return v[name]
+}
+
+// Int returns the value of an integer parameter
+func (v Values) Int(name string) int32 {
+	n, _ := strconv.ParseInt(v[name], 10, 32)
+	return int32(n)
+}
+
+// Bool returns the value of a boolean parameter
+func (v Values) Bool(name string) bool {
+	b, _ := strconv.ParseBool(v[name])
+	return b
+}
+
+// resolve checks the given values against the declared parameters and fills
+// in defaults. Every problem is reported, not only the first.
+func resolve(parameters []*pb.TemplateParameter, given map[string]string) (Values, error) {
+	declared := make(map[string]bool, len(parameters))
+	values := make(Values, len(parameters))
+	var problems []string
+	for _, parameter := range parameters {
+		declared[parameter.GetName()] = true
+		value, ok := given[parameter.GetName()]
+		if !ok {
+			if parameter.GetRequired() {
+				problems = append(problems, fmt.Sprintf("%s is required", parameter.GetName()))
+				continue
+			}
+			value = parameter.GetDefaultValue()
+		}
+		if err := checkValue(parameter, value); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", parameter.GetName(), err))
+			continue
+		}
+		values[parameter.GetName()] = value
+	}
+
+	var unknown []string
+	for name := range given {
+		if !declared[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(unknown)
+	for _, name := range unknown {
+		problems = append(problems, fmt.Sprintf("%s is not a parameter of the template", name))
+	}
+
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidParameters, strings.Join(problems, "; "))
+	}
+	return values, nil
+}
+
+// checkValue reports whether value has the type of a parameter and matches
+// its pattern
+func checkValue(parameter *pb.TemplateParameter, value string) error {
+	switch parameter.GetType() {
+	case pb.ParameterType_PARAMETER_TYPE_INTEGER:
+		if _, err := strconv.ParseInt(value, 10, 32); err != nil {
+			return fmt.Errorf("%q is not an integer", value)
+		}
+	case pb.ParameterType_PARAMETER_TYPE_BOOLEAN:
+		if _, err := strconv.ParseBool(value); err != nil {
+			return fmt.Errorf("%q is not a boolean", value)
+		}
+	}
+	if parameter.GetPattern() != "" {
+		pattern, err := compilePattern(parameter.GetPattern())
+		if err != nil {
+			return err
+		}
+		if !pattern.MatchString(value) {
+			return fmt.Errorf("%q does not match %s", value, parameter.GetPattern())
+		}
+	}
+	return nil
+}
+
+// compilePattern compiles a parameter pattern so that it must match the
+// whole value
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	compiled, err := regexp.Compile("^(?:" + pattern + ")$")
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %v", pattern, err)
+	}
+	return compiled, nil
+}
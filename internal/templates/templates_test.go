@@ -0,0 +1,267 @@
+package templates
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const cacheTemplate = `apiVersion: scheduler/v1
+kind: Template
+metadata:
+  name: cache
+  labels:
+    team: ${team}
+parameters:
+  - name: team
+    required: true
+    pattern: "[a-z]+"
+  - name: redis_port
+    type: integer
+    default_value: "6379"
+  - name: persistent
+    type: boolean
+    default_value: "false"
+  - name: read_only
+    type: boolean
+    default_value: "true"
+spec:
+  priority: ${redis_port}
+  application_stack:
+    additional_services:
+      redis:
+        image: redis:7
+        environment_variables:
+          LITERAL: $${team}
+          PORT: "port ${redis_port}"
+        ports:
+          - container_port: ${redis_port}
+        volumes:
+          - name: redis-data
+            mount_path: /data
+            read_only: ${read_only}
+`
+
+func TestUserTemplateExpand(t *testing.T) {
+	tmpl, err := parseUserTemplate(cacheTemplate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := validate(tmpl); err != nil {
+		t.Fatal(err)
+	}
+
+	spec, err := tmpl.Expand("cache-1", map[string]string{"team": "payments", "redis_port": "6380"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	redis := spec.GetApplicationStack().GetAdditionalServices()["redis"]
+	if redis.GetPorts()[0].GetContainerPort() != 6380 || spec.GetPriority() != 6380 {
+		t.Errorf("port = %d, priority = %d, want both 6380", redis.GetPorts()[0].GetContainerPort(), spec.GetPriority())
+	}
+	if got := redis.GetEnvironmentVariables()["PORT"]; got != "port 6380" {
+		t.Errorf("PORT = %q, want the placeholder replaced inside the text", got)
+	}
+	if !redis.GetVolumes()[0].GetReadOnly() {
+		t.Errorf("read_only = false, want the boolean default")
+	}
+	if got := redis.GetEnvironmentVariables()["LITERAL"]; got != "${team}" {
+		t.Errorf("LITERAL = %q, want the escaped placeholder kept", got)
+	}
+	if spec.GetName() != "cache-1" || spec.GetApplicationStack().GetName() != "cache-1" {
+		t.Errorf("names = %q and %q, want cache-1", spec.GetName(), spec.GetApplicationStack().GetName())
+	}
+	if spec.GetLabels()["team"] != "payments" || spec.GetLabels()[LabelTemplate] != "cache" {
+		t.Errorf("labels = %v, want the team and the template", spec.GetLabels())
+	}
+
+	// The template expands again with other values
+	again, err := tmpl.Expand("cache-2", map[string]string{"team": "search"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.GetLabels()["team"] != "search" || again.GetPriority() != 6379 {
+		t.Errorf("second expansion = %v, want team search and the default port", again)
+	}
+}
+
+func TestExpandParameterErrors(t *testing.T) {
+	tmpl, err := parseUserTemplate(cacheTemplate)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		params map[string]string
+		want   []string
+	}{
+		{name: "missing required", params: map[string]string{}, want: []string{"team is required"}},
+		{name: "pattern", params: map[string]string{"team": "Team-1"}, want: []string{`team: "Team-1" does not match [a-z]+`}},
+		{
+			name:   "types and unknown parameters",
+			params: map[string]string{"team": "a", "redis_port": "high", "persistent": "maybe", "other": "x"},
+			want: []string{
+				`redis_port: "high" is not an integer`,
+				`persistent: "maybe" is not a boolean`,
+				"other is not a parameter of the template",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tmpl.Expand("cache", tt.params)
+			if !errors.Is(err, ErrInvalidParameters) {
+				t.Fatalf("Expand() error = %v, want ErrInvalidParameters", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Expand() error = %v, want %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		replace [2]string
+		want    string
+	}{
+		{
+			name:    "undeclared placeholder",
+			replace: [2]string{"image: redis:7", "image: redis:${version}"},
+			want:    "line 25: ${version} is not a declared parameter",
+		},
+		{
+			name:    "invalid name",
+			replace: [2]string{"name: cache", "name: Cache"},
+			want:    `metadata.name "Cache" must be lowercase letters, digits and dashes`,
+		},
+		{
+			name:    "required with a default",
+			replace: [2]string{"required: true", "required: true\n    default_value: x"},
+			want:    "parameters[0]: required parameters have no default",
+		},
+		{
+			name:    "default of the wrong type",
+			replace: [2]string{`default_value: "6379"`, `default_value: "redis"`},
+			want:    `parameters[1]: default "redis" is not an integer`,
+		},
+		{
+			name:    "invalid pattern",
+			replace: [2]string{`pattern: "[a-z]+"`, `pattern: "[a-z"`},
+			want:    "parameters[0]: invalid pattern",
+		},
+		{
+			name:    "duplicate parameter",
+			replace: [2]string{"name: persistent", "name: team"},
+			want:    "parameters[2]: team is declared more than once",
+		},
+		{
+			// The sample value of a string parameter is not a port number
+			name:    "string placeholder in a number",
+			replace: [2]string{"container_port: ${redis_port}", "container_port: ${team}"},
+			want:    `invalid value for int32 field containerPort: "sample"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := strings.Replace(cacheTemplate, tt.replace[0], tt.replace[1], 1)
+			tmpl, err := parseUserTemplate(source)
+			if err == nil {
+				err = validate(tmpl)
+			}
+			if !errors.Is(err, ErrInvalidTemplate) || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("validate() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	r, err := NewRegistry(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first, err := r.Register([]byte(cacheTemplate))
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := r.Register([]byte(strings.Replace(cacheTemplate, "redis:7", "redis:8", 1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Version != 1 || second.Version != 2 {
+		t.Errorf("versions = %d and %d, want 1 and 2", first.Version, second.Version)
+	}
+
+	latest, err := r.Get("cache", 0)
+	if err != nil || latest.Version != 2 {
+		t.Fatalf("Get(cache, 0) = %v, %v, want version 2", latest, err)
+	}
+	old, err := r.Get("cache", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	spec, err := old.Expand("cache", map[string]string{"team": "a"})
+	if err != nil || spec.GetApplicationStack().GetAdditionalServices()["redis"].GetImage() != "redis:7" {
+		t.Errorf("version 1 expanded to %v, %v, want redis:7", spec, err)
+	}
+
+	list, err := r.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, tmpl := range list {
+		names = append(names, tmpl.Name)
+	}
+	if strings.Join(names, ",") != "cache,nginx-node-postgres,static-go-postgres" {
+		t.Errorf("List() = %v, want the user and built-in templates by name", names)
+	}
+
+	builtin := strings.Replace(cacheTemplate, "name: cache", "name: static-go-postgres", 1)
+	if _, err := r.Register([]byte(builtin)); !errors.Is(err, ErrBuiltin) {
+		t.Errorf("Register() of a built-in name error = %v, want ErrBuiltin", err)
+	}
+	if err := r.Delete("static-go-postgres"); !errors.Is(err, ErrBuiltin) {
+		t.Errorf("Delete() of a built-in template error = %v, want ErrBuiltin", err)
+	}
+	if err := r.Delete("cache"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Get("cache", 0); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get() after Delete() error = %v, want ErrNotFound", err)
+	}
+	if _, err := BuiltinRegistry().Register([]byte(cacheTemplate)); !errors.Is(err, ErrDisabled) {
+		t.Errorf("Register() without storage error = %v, want ErrDisabled", err)
+	}
+}
+
+func TestBuiltinTemplatesExpand(t *testing.T) {
+	params := map[string]string{
+		"database_password": "secret://db",
+		"frontend_image":    "site:1",
+		"api_image":         "api:1",
+	}
+	for _, tmpl := range builtin() {
+		t.Run(tmpl.Name, func(t *testing.T) {
+			given := make(map[string]string)
+			for _, parameter := range tmpl.Parameters {
+				if value, ok := params[parameter.GetName()]; ok {
+					given[parameter.GetName()] = value
+				}
+			}
+			spec, err := tmpl.Expand("shop", given)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if spec.GetApplicationStack().GetDatabase().GetPassword() != "secret://db" {
+				t.Errorf("database password = %q, want the given reference", spec.GetApplicationStack().GetDatabase().GetPassword())
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+package templates
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"scheduler/internal/manifest"
+	pb "scheduler/proto/gen"
+)
+
+// placeholder matches ${name} references to parameters, and $${name} which
+// stands for a literal ${name}
+var placeholder = regexp.MustCompile(`\$(\$?)\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// validParameter matches parameter names
+var validParameter = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// parseUserTemplate reads a template manifest into a template that expands
+// by filling the placeholders of its labels and spec
+func parseUserTemplate(source string) (*Template, error) {
+	m, err := manifest.ParseTemplate([]byte(source))
+	if err != nil {
+		return nil, fmt.Errorf("%w:\n%v", ErrInvalidTemplate, err)
+	}
+	t := &Template{
+		Name:        m.Template.GetName(),
+		Description: m.Template.GetDescription(),
+		Parameters:  m.Template.GetParameters(),
+		Source:      source,
+	}
+	types := make(map[string]pb.ParameterType, len(t.Parameters))
+	for _, parameter := range t.Parameters {
+		types[parameter.GetName()] = parameter.GetType()
+	}
+	t.build = func(name string, values Values) (*pb.EnvironmentSpecification, error) {
+		// Nodes are filled in on copies so that the template can be expanded
+		// again, and keep their position for error messages
+		spec, err := manifest.DecodeTemplateSpec(substitute(m.Labels, values, types), substitute(m.Spec, values, types))
+		if err != nil {
+			return nil, fmt.Errorf("%w: template %s does not expand to a valid specification:\n%v", ErrInvalidParameters, t.Name, err)
+		}
+		spec.ApplicationStack = orEmpty(spec.ApplicationStack)
+		if spec.ApplicationStack.Name == "" {
+			spec.ApplicationStack.Name = name
+		}
+		return spec, nil
+	}
+	return t, nil
+}
+
+// substitute returns a copy of node with the placeholders in its keys and
+// values replaced. Scalars are replaced as text, so a placeholder can make
+// up a whole port number or part of an image reference alike, and values
+// cannot change the structure of the document. A plain value made up of a
+// single placeholder of an integer or boolean parameter takes the type of
+// the parameter, so that ${port} is read as a number and ${enabled} as true
+// or false.
+func substitute(node *yaml.Node, values Values, types map[string]pb.ParameterType) *yaml.Node {
+	if node == nil {
+		return nil
+	}
+	copied := *node
+	if copied.Kind == yaml.ScalarNode {
+		if groups := placeholder.FindStringSubmatch(copied.Value); copied.Style == 0 && groups != nil && groups[0] == copied.Value && groups[1] == "" {
+			switch types[groups[2]] {
+			case pb.ParameterType_PARAMETER_TYPE_INTEGER:
+				copied.Tag = "!!int"
+			case pb.ParameterType_PARAMETER_TYPE_BOOLEAN:
+				copied.Tag = "!!bool"
+			}
+		}
+		copied.Value = placeholder.ReplaceAllStringFunc(copied.Value, func(match string) string {
+			groups := placeholder.FindStringSubmatch(match)
+			if groups[1] != "" {
+				return match[1:]
+			}
+			return values[groups[2]]
+		})
+	}
+	if len(node.Content) > 0 {
+		copied.Content = make([]*yaml.Node, len(node.Content))
+		for i, child := range node.Content {
+			copied.Content[i] = substitute(child, values, types)
+		}
+	}
+	return &copied
+}
+
+// references returns the parameters a node refers to, with the line of
+// their first use
+func references(node *yaml.Node, found map[string]int) {
+	if node == nil {
+		return
+	}
+	if node.Kind == yaml.ScalarNode {
+		for _, groups := range placeholder.FindAllStringSubmatch(node.Value, -1) {
+			if _, ok := found[groups[2]]; !ok && groups[1] == "" {
+				found[groups[2]] = node.Line
+			}
+		}
+	}
+	for _, child := range node.Content {
+		references(child, found)
+	}
+}
+
+// validate checks a user template before it is registered: its name, the
+// parameter schema, the placeholders and that it expands to a valid
+// specification with sample values
+func validate(t *Template) error {
+	var problems []string
+	if !validName.MatchString(t.Name) {
+		problems = append(problems, fmt.Sprintf("metadata.name %q must be lowercase letters, digits and dashes", t.Name))
+	}
+
+	declared := make(map[string]bool)
+	for i, parameter := range t.Parameters {
+		path := fmt.Sprintf("parameters[%d]", i)
+		name := parameter.GetName()
+		switch {
+		case !validParameter.MatchString(name):
+			problems = append(problems, fmt.Sprintf("%s: name %q must be letters, digits and underscores", path, name))
+		case declared[name]:
+			problems = append(problems, fmt.Sprintf("%s: %s is declared more than once", path, name))
+		}
+		declared[name] = true
+		if parameter.GetPattern() != "" {
+			if _, err := compilePattern(parameter.GetPattern()); err != nil {
+				problems = append(problems, fmt.Sprintf("%s: %v", path, err))
+				continue
+			}
+		}
+		switch {
+		case parameter.GetRequired() && parameter.GetDefaultValue() != "":
+			problems = append(problems, fmt.Sprintf("%s: required parameters have no default", path))
+		case !parameter.GetRequired():
+			if err := checkValue(parameter, parameter.GetDefaultValue()); err != nil {
+				problems = append(problems, fmt.Sprintf("%s: default %v", path, err))
+			}
+		}
+	}
+
+	m, err := manifest.ParseTemplate([]byte(t.Source))
+	if err != nil {
+		return fmt.Errorf("%w:\n%v", ErrInvalidTemplate, err)
+	}
+	used := make(map[string]int)
+	references(m.Labels, used)
+	references(m.Spec, used)
+	var unknown []string
+	for name := range used {
+		if !declared[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Slice(unknown, func(i, j int) bool { return used[unknown[i]] < used[unknown[j]] })
+	for _, name := range unknown {
+		problems = append(problems, fmt.Sprintf("line %d: ${%s} is not a declared parameter", used[name], name))
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%w:\n%s", ErrInvalidTemplate, strings.Join(problems, "\n"))
+	}
+
+	// Required parameters get a sample value of their type, so that type
+	// errors such as a string placeholder in a port show up now
+	samples := make(map[string]string)
+	for _, parameter := range t.Parameters {
+		if parameter.GetRequired() {
+			samples[parameter.GetName()] = sampleValue(parameter.GetType())
+		}
+	}
+	values := make(Values)
+	for _, parameter := range t.Parameters {
+		values[parameter.GetName()] = parameter.GetDefaultValue()
+		if sample, ok := samples[parameter.GetName()]; ok {
+			values[parameter.GetName()] = sample
+		}
+	}
+	if _, err := t.build(t.Name, values); err != nil {
+		return fmt.Errorf("%w:\n%v", ErrInvalidTemplate, strings.TrimPrefix(err.Error(), ErrInvalidParameters.Error()+": "))
+	}
+	return nil
+}
+
+func sampleValue(kind pb.ParameterType) string {
+	switch kind {
+	case pb.ParameterType_PARAMETER_TYPE_INTEGER:
+		return "1"
+	case pb.ParameterType_PARAMETER_TYPE_BOOLEAN:
+		return "false"
+	default:
+		return "sample"
+	}
+}
+
+func orEmpty(stack *pb.ApplicationStack) *pb.ApplicationStack {
+	if stack == nil {
+		return &pb.ApplicationStack{}
+	}
+	return stack
+}
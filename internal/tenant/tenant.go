@@ -0,0 +1,105 @@
+package tenant
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+
+	"scheduler/internal/quota"
+)
+
+// Default is the tenant of environments created without one, and of
+// environments persisted before tenants existed
+const Default = "default"
+
+var namePattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// Tenant groups environments that share a containerD namespace, network pool
+// and quota. Environment names are unique within a tenant.
+type Tenant struct {
+	Name string `mapstructure:"name"`
+	// Namespace is the containerD namespace of the tenant's containers,
+	// defaults to the scheduler namespace suffixed with the tenant name
+	Namespace string `mapstructure:"namespace"`
+	// SubnetPool gives the tenant its own address range, empty shares the
+	// default pool
+	SubnetPool         []string `mapstructure:"subnet_pool"`
+	SubnetPrefixLength int      `mapstructure:"subnet_prefix_length"`
+	// Quota caps the resources of all environments of the tenant, zero
+	// amounts are unlimited
+	Quota quota.Resources `mapstructure:"quota"`
+}
+
+// HasOwnPool reports whether the tenant draws subnets from its own pool
+func (t Tenant) HasOwnPool() bool {
+	return len(t.SubnetPool) > 0
+}
+
+// ValidateName checks that a tenant name can be used in namespaces and labels
+func ValidateName(name string) error {
+	if !namePattern.MatchString(name) {
+		return fmt.Errorf("invalid tenant name %q: must match %s", name, namePattern)
+	}
+	return nil
+}
+
+// Registry holds the configured tenants
+type Registry struct {
+	tenants map[string]Tenant
+}
+
+// NewRegistry creates a registry of the configured tenants. The default
+// tenant always exists and uses baseNamespace unless it is configured
+// explicitly.
+func NewRegistry(tenants []Tenant, baseNamespace string, defaultPrefixLength int) (*Registry, error) {
+	r := &Registry{tenants: map[string]Tenant{
+		Default: {Name: Default, Namespace: baseNamespace},
+	}}
+	seen := make(map[string]bool)
+	namespaces := make(map[string]string)
+	for _, configured := range tenants {
+		if err := ValidateName(configured.Name); err != nil {
+			return nil, err
+		}
+		if seen[configured.Name] {
+			return nil, fmt.Errorf("tenant %s is configured twice", configured.Name)
+		}
+		seen[configured.Name] = true
+
+		if configured.Namespace == "" {
+			configured.Namespace = baseNamespace
+			if configured.Name != Default {
+				configured.Namespace = baseNamespace + "-" + configured.Name
+			}
+		}
+		if configured.HasOwnPool() && configured.SubnetPrefixLength == 0 {
+			configured.SubnetPrefixLength = defaultPrefixLength
+		}
+		r.tenants[configured.Name] = configured
+	}
+
+	for _, name := range r.Names() {
+		namespace := r.tenants[name].Namespace
+		if other, ok := namespaces[namespace]; ok {
+			return nil, fmt.Errorf("tenants %s and %s share containerD namespace %s", other, name, namespace)
+		}
+		namespaces[namespace] = name
+	}
+	return r, nil
+}
+
+// Get returns a configured tenant
+func (r *Registry) Get(name string) (Tenant, bool) {
+	t, ok := r.tenants[name]
+	return t, ok
+}
+
+// Names returns the names of all tenants, sorted
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.tenants))
+	for name := range r.tenants {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,80 @@
+package tenant
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewRegistry(t *testing.T) {
+	r, err := NewRegistry([]Tenant{
+		{Name: "team-a"},
+		{Name: "team-b", Namespace: "custom", SubnetPool: []string{"172.30.0.0/16"}},
+		{Name: "team-c", SubnetPool: []string{"172.31.0.0/16"}, SubnetPrefixLength: 26},
+	}, "scheduler", 24)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"default", "team-a", "team-b", "team-c"}; !reflect.DeepEqual(r.Names(), want) {
+		t.Errorf("Names() = %v, want %v", r.Names(), want)
+	}
+
+	tests := []struct {
+		name         string
+		namespace    string
+		ownPool      bool
+		prefixLength int
+	}{
+		{name: Default, namespace: "scheduler"},
+		{name: "team-a", namespace: "scheduler-team-a"},
+		{name: "team-b", namespace: "custom", ownPool: true, prefixLength: 24},
+		{name: "team-c", namespace: "scheduler-team-c", ownPool: true, prefixLength: 26},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := r.Get(tt.name)
+			if !ok {
+				t.Fatal("tenant is not registered")
+			}
+			if got.Namespace != tt.namespace || got.HasOwnPool() != tt.ownPool || got.SubnetPrefixLength != tt.prefixLength {
+				t.Errorf("Get() = %+v, want namespace %s, own pool %v, /%d", got, tt.namespace, tt.ownPool, tt.prefixLength)
+			}
+		})
+	}
+	if _, ok := r.Get("missing"); ok {
+		t.Error("Get() found an unconfigured tenant")
+	}
+}
+
+func TestNewRegistryDefault(t *testing.T) {
+	// The default tenant can be configured like any other, keeping the base
+	// namespace unless it names another
+	r, err := NewRegistry([]Tenant{{Name: Default, SubnetPool: []string{"10.0.0.0/8"}}}, "scheduler", 24)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := r.Get(Default)
+	if got.Namespace != "scheduler" || !got.HasOwnPool() {
+		t.Errorf("default tenant = %+v, want the base namespace and its own pool", got)
+	}
+}
+
+func TestNewRegistryRejects(t *testing.T) {
+	tests := []struct {
+		name    string
+		tenants []Tenant
+	}{
+		{name: "invalid name", tenants: []Tenant{{Name: "Team_A"}}},
+		{name: "empty name", tenants: []Tenant{{}}},
+		{name: "duplicate", tenants: []Tenant{{Name: "a"}, {Name: "a"}}},
+		{name: "shared namespace", tenants: []Tenant{{Name: "a", Namespace: "shared"}, {Name: "b", Namespace: "shared"}}},
+		{name: "namespace of the default tenant", tenants: []Tenant{{Name: "a", Namespace: "scheduler"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewRegistry(tt.tenants, "scheduler", 24); err == nil {
+				t.Error("NewRegistry() succeeded, want an error")
+			}
+		})
+	}
+}
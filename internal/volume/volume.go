@@ -0,0 +1,364 @@
+package volume
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"scheduler/internal/fsutil"
+	pb "scheduler/proto/gen"
+)
+
+var (
+	// ErrNotFound is returned when a volume does not exist
+	ErrNotFound = errors.New("volume not found")
+	// ErrAlreadyExists is returned when creating a volume whose name is taken
+	ErrAlreadyExists = errors.New("volume already exists")
+	// ErrOtherTenant is returned when a volume belongs to another tenant
+	ErrOtherTenant = errors.New("volume belongs to another tenant")
+)
+
+const (
+	metadataFile = "volume.json"
+	dataDir      = "_data"
+	stagingDir   = "_data.staging"
+	retiredDir   = "_data.old"
+)
+
+var namePattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]{0,127}$`)
+
+// ValidateName checks that a volume name is safe to use as a directory name
+func ValidateName(name string) error {
+	if !namePattern.MatchString(name) {
+		return fmt.Errorf("invalid volume name %q: must match %s", name, namePattern)
+	}
+	return nil
+}
+
+// Manager stores named volumes as directories under a data root. Each volume
+// directory holds its metadata next to a _data directory that is mounted
+// into containers.
+type Manager struct {
+	mu   sync.Mutex
+	root string
+}
+
+// NewManager creates a manager storing volumes under root
+func NewManager(root string) (*Manager, error) {
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create volume root %s: %w", root, err)
+	}
+	return &Manager{root: root}, nil
+}
+
+// Create creates an empty named volume owned by tenant
+func (m *Manager) Create(name, tenant string, labels map[string]string, policy *pb.SnapshotPolicy) (*pb.Volume, error) {
+	if err := ValidateName(name); err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, err := os.Stat(m.metadataPath(name)); err == nil {
+		return nil, fmt.Errorf("%w: %s", ErrAlreadyExists, name)
+	}
+	return m.create(name, tenant, labels, policy)
+}
+
+// Ensure returns the named volume of tenant, creating it if it does not
+// exist yet. Volumes of other tenants are never returned.
+func (m *Manager) Ensure(name, tenant string, labels map[string]string) (*pb.Volume, error) {
+	if err := ValidateName(name); err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	existing, err := m.read(name)
+	if err == nil {
+		if existing.GetTenant() != tenant {
+			return nil, fmt.Errorf("%w: %s", ErrOtherTenant, name)
+		}
+		return existing, nil
+	}
+	if !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+	return m.create(name, tenant, labels, nil)
+}
+
+// Assign makes tenant the owner of a volume, for volumes created before
+// volumes had owners
+func (m *Manager) Assign(name, tenant string) (*pb.Volume, error) {
+	if err := ValidateName(name); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	volume, err := m.read(name)
+	if err != nil {
+		return nil, err
+	}
+	volume.Tenant = tenant
+	if err := m.writeMetadata(volume); err != nil {
+		return nil, err
+	}
+	return volume, nil
+}
+
+// Update replaces the labels and snapshot policy of a volume
+func (m *Manager) Update(name string, labels map[string]string, policy *pb.SnapshotPolicy) (*pb.Volume, error) {
+	if err := ValidateName(name); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	volume, err := m.read(name)
+	if err != nil {
+		return nil, err
+	}
+	volume.Labels = labels
+	volume.SnapshotPolicy = policy
+	if err := m.writeMetadata(volume); err != nil {
+		return nil, err
+	}
+	return volume, nil
+}
+
+// ReplaceData swaps the data of a volume for a directory populated by fill.
+// The existing data is only removed once fill has succeeded. fill runs
+// without the manager lock, so that other volumes can be used while a large
+// one is filled; callers keep the volume itself from being used meanwhile.
+func (m *Manager) ReplaceData(name string, fill func(dir string) error) error {
+	if err := ValidateName(name); err != nil {
+		return fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+
+	staging, err := m.stage(name)
+	if err != nil {
+		return err
+	}
+	if err := fill(staging); err != nil {
+		os.RemoveAll(staging)
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, err := os.Stat(m.metadataPath(name)); os.IsNotExist(err) {
+		os.RemoveAll(staging)
+		return fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	retired := filepath.Join(m.root, name, retiredDir)
+	if err := os.RemoveAll(retired); err != nil {
+		os.RemoveAll(staging)
+		return fmt.Errorf("failed to clear retired data of volume %s: %w", name, err)
+	}
+	if err := os.Rename(m.DataPath(name), retired); err != nil && !os.IsNotExist(err) {
+		os.RemoveAll(staging)
+		return fmt.Errorf("failed to retire data of volume %s: %w", name, err)
+	}
+	if err := os.Rename(staging, m.DataPath(name)); err != nil {
+		return fmt.Errorf("failed to swap in data of volume %s: %w", name, err)
+	}
+	if err := os.RemoveAll(retired); err != nil {
+		return fmt.Errorf("failed to remove retired data of volume %s: %w", name, err)
+	}
+	return nil
+}
+
+// stage creates an empty staging directory next to the data of a volume
+func (m *Manager) stage(name string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, err := os.Stat(m.metadataPath(name)); os.IsNotExist(err) {
+		return "", fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	staging, err := os.MkdirTemp(filepath.Join(m.root, name), stagingDir+"-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create staging directory of volume %s: %w", name, err)
+	}
+	if err := os.Chmod(staging, 0o755); err != nil {
+		os.RemoveAll(staging)
+		return "", fmt.Errorf("failed to create staging directory of volume %s: %w", name, err)
+	}
+	return staging, nil
+}
+
+// Get returns the named volume. Its size is left to Usage.
+func (m *Manager) Get(name string) (*pb.Volume, error) {
+	if err := ValidateName(name); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.read(name)
+}
+
+// List returns every volume ordered by name, without their sizes
+func (m *Manager) List() ([]*pb.Volume, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entries, err := os.ReadDir(m.root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read volume root %s: %w", m.root, err)
+	}
+
+	var volumes []*pb.Volume
+	for _, entry := range entries {
+		if !entry.IsDir() || ValidateName(entry.Name()) != nil {
+			continue
+		}
+		volume, err := m.read(entry.Name())
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		volumes = append(volumes, volume)
+	}
+	sort.Slice(volumes, func(i, j int) bool {
+		return volumes[i].GetName() < volumes[j].GetName()
+	})
+	return volumes, nil
+}
+
+// Delete removes a volume and all of its data. Callers are responsible for
+// checking that no environment still mounts it.
+func (m *Manager) Delete(name string) error {
+	if err := ValidateName(name); err != nil {
+		return fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, err := os.Stat(m.metadataPath(name)); os.IsNotExist(err) {
+		return fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	if err := os.RemoveAll(filepath.Join(m.root, name)); err != nil {
+		return fmt.Errorf("failed to remove volume %s: %w", name, err)
+	}
+	return nil
+}
+
+// Usage measures the data of a volume. The data is walked without holding
+// the manager lock, so that measuring a large volume blocks no other volume
+// operation, and a volume written to meanwhile is measured as it is found.
+func (m *Manager) Usage(name string) (int64, error) {
+	if err := ValidateName(name); err != nil {
+		return 0, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	size, err := diskUsage(m.DataPath(name))
+	if err != nil {
+		return 0, fmt.Errorf("failed to measure volume %s: %w", name, err)
+	}
+	return size, nil
+}
+
+// DataPath returns the host directory that is mounted into containers
+func (m *Manager) DataPath(name string) string {
+	return filepath.Join(m.root, name, dataDir)
+}
+
+func (m *Manager) create(name, tenant string, labels map[string]string, policy *pb.SnapshotPolicy) (*pb.Volume, error) {
+	if err := os.MkdirAll(m.DataPath(name), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create volume %s: %w", name, err)
+	}
+
+	volume := &pb.Volume{
+		Name:           name,
+		Labels:         labels,
+		CreatedAt:      timestamppb.Now(),
+		SnapshotPolicy: policy,
+		Tenant:         tenant,
+	}
+	if err := m.writeMetadata(volume); err != nil {
+		return nil, err
+	}
+
+	volume.Path = m.DataPath(name)
+	return volume, nil
+}
+
+// writeMetadata persists the stored fields of a volume, leaving out the ones
+// computed on read
+func (m *Manager) writeMetadata(volume *pb.Volume) error {
+	stored := &pb.Volume{
+		Name:           volume.GetName(),
+		Labels:         volume.GetLabels(),
+		CreatedAt:      volume.GetCreatedAt(),
+		SnapshotPolicy: volume.GetSnapshotPolicy(),
+		Tenant:         volume.GetTenant(),
+	}
+	data, err := protojson.MarshalOptions{Multiline: true}.Marshal(stored)
+	if err != nil {
+		return fmt.Errorf("failed to encode volume %s: %w", volume.GetName(), err)
+	}
+	return fsutil.WriteFileAtomic(m.metadataPath(volume.GetName()), data, 0o600)
+}
+
+func (m *Manager) read(name string) (*pb.Volume, error) {
+	data, err := os.ReadFile(m.metadataPath(name))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read volume %s: %w", name, err)
+	}
+
+	volume := &pb.Volume{}
+	if err := protojson.Unmarshal(data, volume); err != nil {
+		return nil, fmt.Errorf("failed to decode volume %s: %w", name, err)
+	}
+
+	volume.Path = m.DataPath(name)
+	return volume, nil
+}
+
+func (m *Manager) metadataPath(name string) string {
+	return filepath.Join(m.root, name, metadataFile)
+}
+
+// diskUsage sums the sizes of the regular files below path
+func diskUsage(path string) (int64, error) {
+	var total int64
+	err := filepath.WalkDir(path, func(_ string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		total += info.Size()
+		return nil
+	})
+	return total, err
+}
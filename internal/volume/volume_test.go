@@ -0,0 +1,211 @@
+package volume
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	pb "scheduler/proto/gen"
+)
+
+func newTestManager(t *testing.T) *Manager {
+	t.Helper()
+	m, err := NewManager(filepath.Join(t.TempDir(), "volumes"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestValidateName(t *testing.T) {
+	for _, name := range []string{"data", "pg-16.data_1", "A"} {
+		if err := ValidateName(name); err != nil {
+			t.Errorf("ValidateName(%q) = %v, want nil", name, err)
+		}
+	}
+	for _, name := range []string{"", ".hidden", "-data", "a/b", "../data", "a b"} {
+		if err := ValidateName(name); err == nil {
+			t.Errorf("ValidateName(%q) succeeded, want an error", name)
+		}
+	}
+}
+
+func TestCreate(t *testing.T) {
+	m := newTestManager(t)
+
+	volume, err := m.Create("data", "a", map[string]string{"team": "a"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if volume.GetPath() != m.DataPath("data") || volume.GetLabels()["team"] != "a" || volume.GetTenant() != "a" || volume.GetCreatedAt() == nil {
+		t.Errorf("Create() = %v, want a labelled volume of tenant a at %s", volume, m.DataPath("data"))
+	}
+	if info, err := os.Stat(m.DataPath("data")); err != nil || !info.IsDir() {
+		t.Errorf("data directory = %v, %v, want a directory", info, err)
+	}
+	if _, err := m.Create("data", "a", nil, nil); !errors.Is(err, ErrAlreadyExists) {
+		t.Errorf("creating twice = %v, want ErrAlreadyExists", err)
+	}
+	if _, err := m.Create("../escape", "a", nil, nil); err == nil {
+		t.Error("creating a volume with an unsafe name succeeded")
+	}
+
+	// Ensure returns the existing volume instead of recreating it
+	ensured, err := m.Ensure("data", "a", map[string]string{"team": "b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ensured.GetLabels()["team"] != "a" {
+		t.Errorf("labels after Ensure = %v, want the original ones", ensured.GetLabels())
+	}
+	if _, err := m.Ensure("cache", "a", nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Get("cache"); err != nil {
+		t.Errorf("Get after Ensure = %v", err)
+	}
+	if _, err := m.Ensure("data", "b", nil); !errors.Is(err, ErrOtherTenant) {
+		t.Errorf("Ensure() of a volume of another tenant error = %v, want ErrOtherTenant", err)
+	}
+
+	// Volumes created before volumes had owners are assigned one
+	if _, err := m.Assign("cache", "b"); err != nil {
+		t.Fatal(err)
+	}
+	if assigned, _ := m.Get("cache"); assigned.GetTenant() != "b" {
+		t.Errorf("tenant after Assign = %q, want b", assigned.GetTenant())
+	}
+}
+
+func TestUsage(t *testing.T) {
+	m := newTestManager(t)
+	if _, err := m.Create("data", "a", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	dir := filepath.Join(m.DataPath("data"), "nested")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(m.DataPath("data"), "a"), make([]byte, 100), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "b"), make([]byte, 23), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// Links are not followed or counted
+	if err := os.Symlink("/", filepath.Join(dir, "root")); err != nil {
+		t.Fatal(err)
+	}
+
+	size, err := m.Usage("data")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size != 123 {
+		t.Errorf("Usage() = %d, want 123", size)
+	}
+}
+
+func TestListAndDelete(t *testing.T) {
+	m := newTestManager(t)
+	for _, name := range []string{"b", "a", "c"} {
+		if _, err := m.Create(name, "a", nil, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Directories without metadata are not volumes
+	if err := os.MkdirAll(filepath.Join(m.root, "stray"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.Delete("b"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(m.root, "b")); !os.IsNotExist(err) {
+		t.Errorf("volume directory after delete = %v, want it gone", err)
+	}
+	if err := m.Delete("b"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("deleting twice = %v, want ErrNotFound", err)
+	}
+	if _, err := m.Get("b"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get after delete = %v, want ErrNotFound", err)
+	}
+
+	volumes, err := m.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, volume := range volumes {
+		names = append(names, volume.GetName())
+	}
+	if len(names) != 2 || names[0] != "a" || names[1] != "c" {
+		t.Errorf("List() = %v, want [a c]", names)
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	m := newTestManager(t)
+	if _, err := m.Create("data", "a", map[string]string{"team": "a"}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	policy := &pb.SnapshotPolicy{IntervalSeconds: 3600, Retain: 3}
+	if _, err := m.Update("data", map[string]string{"team": "b"}, policy); err != nil {
+		t.Fatal(err)
+	}
+	volume, err := m.Get("data")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if volume.GetLabels()["team"] != "b" || volume.GetSnapshotPolicy().GetRetain() != 3 {
+		t.Errorf("volume after update = %v, want team b retaining 3 snapshots", volume)
+	}
+	if _, err := m.Update("missing", nil, nil); !errors.Is(err, ErrNotFound) {
+		t.Errorf("updating a missing volume = %v, want ErrNotFound", err)
+	}
+}
+
+func TestReplaceData(t *testing.T) {
+	m := newTestManager(t)
+	if _, err := m.Create("data", "a", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	old := filepath.Join(m.DataPath("data"), "old")
+	if err := os.WriteFile(old, []byte("old"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// A failing fill leaves the existing data alone
+	failure := errors.New("fill failed")
+	if err := m.ReplaceData("data", func(string) error { return failure }); !errors.Is(err, failure) {
+		t.Fatalf("ReplaceData() = %v, want the fill error", err)
+	}
+	if _, err := os.Stat(old); err != nil {
+		t.Errorf("data after a failed replace = %v, want it kept", err)
+	}
+
+	err := m.ReplaceData("data", func(dir string) error {
+		return os.WriteFile(filepath.Join(dir, "new"), []byte("new"), 0o644)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries, err := os.ReadDir(m.DataPath("data"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "new" {
+		t.Errorf("data after replace = %v, want only new", entries)
+	}
+	for _, dir := range []string{stagingDir, retiredDir} {
+		if _, err := os.Stat(filepath.Join(m.root, "data", dir)); !os.IsNotExist(err) {
+			t.Errorf("%s after replace = %v, want it removed", dir, err)
+		}
+	}
+
+	if err := m.ReplaceData("missing", func(string) error { return nil }); !errors.Is(err, ErrNotFound) {
+		t.Errorf("replacing the data of a missing volume = %v, want ErrNotFound", err)
+	}
+}
@@ -78,6 +78,115 @@ func (RestartPolicy) EnumDescriptor() ([]byte, []int) {
 	return file_scheduler_proto_rawDescGZIP(), []int{0}
 }
 
+// Point in the lifecycle of an environment a hook runs at
+type HookPhase int32
+
+const (
+	HookPhase_HOOK_PHASE_UNSPECIFIED HookPhase = 0
+	HookPhase_HOOK_PHASE_PRE_DEPLOY  HookPhase = 1
+	HookPhase_HOOK_PHASE_POST_START  HookPhase = 2
+	HookPhase_HOOK_PHASE_POST_DEPLOY HookPhase = 3
+	HookPhase_HOOK_PHASE_PRE_STOP    HookPhase = 4
+)
+
+// Enum value maps for HookPhase.
+var (
+	HookPhase_name = map[int32]string{
+		0: "HOOK_PHASE_UNSPECIFIED",
+		1: "HOOK_PHASE_PRE_DEPLOY",
+		2: "HOOK_PHASE_POST_START",
+		3: "HOOK_PHASE_POST_DEPLOY",
+		4: "HOOK_PHASE_PRE_STOP",
+	}
+	HookPhase_value = map[string]int32{
+		"HOOK_PHASE_UNSPECIFIED": 0,
+		"HOOK_PHASE_PRE_DEPLOY":  1,
+		"HOOK_PHASE_POST_START":  2,
+		"HOOK_PHASE_POST_DEPLOY": 3,
+		"HOOK_PHASE_PRE_STOP":    4,
+	}
+)
+
+func (x HookPhase) Enum() *HookPhase {
+	p := new(HookPhase)
+	*p = x
+	return p
+}
+
+func (x HookPhase) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (HookPhase) Descriptor() protoreflect.EnumDescriptor {
+	return file_scheduler_proto_enumTypes[1].Descriptor()
+}
+
+func (HookPhase) Type() protoreflect.EnumType {
+	return &file_scheduler_proto_enumTypes[1]
+}
+
+func (x HookPhase) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use HookPhase.Descriptor instead.
+func (HookPhase) EnumDescriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{1}
+}
+
+// Kind of a database initialization step
+type DatabaseStep int32
+
+const (
+	DatabaseStep_DATABASE_STEP_UNSPECIFIED DatabaseStep = 0
+	DatabaseStep_DATABASE_STEP_INIT_SCRIPT DatabaseStep = 1
+	DatabaseStep_DATABASE_STEP_MIGRATION   DatabaseStep = 2
+	DatabaseStep_DATABASE_STEP_SEED_DATA   DatabaseStep = 3
+)
+
+// Enum value maps for DatabaseStep.
+var (
+	DatabaseStep_name = map[int32]string{
+		0: "DATABASE_STEP_UNSPECIFIED",
+		1: "DATABASE_STEP_INIT_SCRIPT",
+		2: "DATABASE_STEP_MIGRATION",
+		3: "DATABASE_STEP_SEED_DATA",
+	}
+	DatabaseStep_value = map[string]int32{
+		"DATABASE_STEP_UNSPECIFIED": 0,
+		"DATABASE_STEP_INIT_SCRIPT": 1,
+		"DATABASE_STEP_MIGRATION":   2,
+		"DATABASE_STEP_SEED_DATA":   3,
+	}
+)
+
+func (x DatabaseStep) Enum() *DatabaseStep {
+	p := new(DatabaseStep)
+	*p = x
+	return p
+}
+
+func (x DatabaseStep) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DatabaseStep) Descriptor() protoreflect.EnumDescriptor {
+	return file_scheduler_proto_enumTypes[2].Descriptor()
+}
+
+func (DatabaseStep) Type() protoreflect.EnumType {
+	return &file_scheduler_proto_enumTypes[2]
+}
+
+func (x DatabaseStep) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DatabaseStep.Descriptor instead.
+func (DatabaseStep) EnumDescriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{2}
+}
+
 // Current status of an environment
 type EnvironmentStatus int32
 
@@ -127,11 +236,11 @@ func (x EnvironmentStatus) String() string {
 }
 
 func (EnvironmentStatus) Descriptor() protoreflect.EnumDescriptor {
-	return file_scheduler_proto_enumTypes[1].Descriptor()
+	return file_scheduler_proto_enumTypes[3].Descriptor()
 }
 
 func (EnvironmentStatus) Type() protoreflect.EnumType {
-	return &file_scheduler_proto_enumTypes[1]
+	return &file_scheduler_proto_enumTypes[3]
 }
 
 func (x EnvironmentStatus) Number() protoreflect.EnumNumber {
@@ -140,7 +249,7 @@ func (x EnvironmentStatus) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use EnvironmentStatus.Descriptor instead.
 func (EnvironmentStatus) EnumDescriptor() ([]byte, []int) {
-	return file_scheduler_proto_rawDescGZIP(), []int{1}
+	return file_scheduler_proto_rawDescGZIP(), []int{3}
 }
 
 // Current status of a container
@@ -192,11 +301,11 @@ func (x ContainerStatus) String() string {
 }
 
 func (ContainerStatus) Descriptor() protoreflect.EnumDescriptor {
-	return file_scheduler_proto_enumTypes[2].Descriptor()
+	return file_scheduler_proto_enumTypes[4].Descriptor()
 }
 
 func (ContainerStatus) Type() protoreflect.EnumType {
-	return &file_scheduler_proto_enumTypes[2]
+	return &file_scheduler_proto_enumTypes[4]
 }
 
 func (x ContainerStatus) Number() protoreflect.EnumNumber {
@@ -205,10 +314,278 @@ func (x ContainerStatus) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use ContainerStatus.Descriptor instead.
 func (ContainerStatus) EnumDescriptor() ([]byte, []int) {
-	return file_scheduler_proto_rawDescGZIP(), []int{2}
+	return file_scheduler_proto_rawDescGZIP(), []int{4}
+}
+
+// What applying a manifest did, or would do on a dry run
+type ManifestAction int32
+
+const (
+	ManifestAction_MANIFEST_ACTION_UNSPECIFIED ManifestAction = 0
+	ManifestAction_MANIFEST_ACTION_CREATED     ManifestAction = 1
+	ManifestAction_MANIFEST_ACTION_UPDATED     ManifestAction = 2
+	ManifestAction_MANIFEST_ACTION_UNCHANGED   ManifestAction = 3 // the specification already matches, no revision is recorded
+)
+
+// Enum value maps for ManifestAction.
+var (
+	ManifestAction_name = map[int32]string{
+		0: "MANIFEST_ACTION_UNSPECIFIED",
+		1: "MANIFEST_ACTION_CREATED",
+		2: "MANIFEST_ACTION_UPDATED",
+		3: "MANIFEST_ACTION_UNCHANGED",
+	}
+	ManifestAction_value = map[string]int32{
+		"MANIFEST_ACTION_UNSPECIFIED": 0,
+		"MANIFEST_ACTION_CREATED":     1,
+		"MANIFEST_ACTION_UPDATED":     2,
+		"MANIFEST_ACTION_UNCHANGED":   3,
+	}
+)
+
+func (x ManifestAction) Enum() *ManifestAction {
+	p := new(ManifestAction)
+	*p = x
+	return p
+}
+
+func (x ManifestAction) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ManifestAction) Descriptor() protoreflect.EnumDescriptor {
+	return file_scheduler_proto_enumTypes[5].Descriptor()
+}
+
+func (ManifestAction) Type() protoreflect.EnumType {
+	return &file_scheduler_proto_enumTypes[5]
+}
+
+func (x ManifestAction) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ManifestAction.Descriptor instead.
+func (ManifestAction) EnumDescriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{5}
+}
+
+// Kind of difference between two versions of a field
+type ChangeType int32
+
+const (
+	ChangeType_CHANGE_TYPE_UNSPECIFIED ChangeType = 0
+	ChangeType_CHANGE_TYPE_ADDED       ChangeType = 1
+	ChangeType_CHANGE_TYPE_REMOVED     ChangeType = 2
+	ChangeType_CHANGE_TYPE_MODIFIED    ChangeType = 3
+)
+
+// Enum value maps for ChangeType.
+var (
+	ChangeType_name = map[int32]string{
+		0: "CHANGE_TYPE_UNSPECIFIED",
+		1: "CHANGE_TYPE_ADDED",
+		2: "CHANGE_TYPE_REMOVED",
+		3: "CHANGE_TYPE_MODIFIED",
+	}
+	ChangeType_value = map[string]int32{
+		"CHANGE_TYPE_UNSPECIFIED": 0,
+		"CHANGE_TYPE_ADDED":       1,
+		"CHANGE_TYPE_REMOVED":     2,
+		"CHANGE_TYPE_MODIFIED":    3,
+	}
+)
+
+func (x ChangeType) Enum() *ChangeType {
+	p := new(ChangeType)
+	*p = x
+	return p
+}
+
+func (x ChangeType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ChangeType) Descriptor() protoreflect.EnumDescriptor {
+	return file_scheduler_proto_enumTypes[6].Descriptor()
+}
+
+func (ChangeType) Type() protoreflect.EnumType {
+	return &file_scheduler_proto_enumTypes[6]
+}
+
+func (x ChangeType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ChangeType.Descriptor instead.
+func (ChangeType) EnumDescriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{6}
+}
+
+// What an update does to a container. Updates of running environments apply
+// it right away, stopped environments start with the new specification.
+type ContainerAction int32
+
+const (
+	ContainerAction_CONTAINER_ACTION_UNSPECIFIED ContainerAction = 0
+	ContainerAction_CONTAINER_ACTION_CREATE      ContainerAction = 1
+	ContainerAction_CONTAINER_ACTION_RECREATE    ContainerAction = 2
+	ContainerAction_CONTAINER_ACTION_UNCHANGED   ContainerAction = 3
+	ContainerAction_CONTAINER_ACTION_REMOVE      ContainerAction = 4
+)
+
+// Enum value maps for ContainerAction.
+var (
+	ContainerAction_name = map[int32]string{
+		0: "CONTAINER_ACTION_UNSPECIFIED",
+		1: "CONTAINER_ACTION_CREATE",
+		2: "CONTAINER_ACTION_RECREATE",
+		3: "CONTAINER_ACTION_UNCHANGED",
+		4: "CONTAINER_ACTION_REMOVE",
+	}
+	ContainerAction_value = map[string]int32{
+		"CONTAINER_ACTION_UNSPECIFIED": 0,
+		"CONTAINER_ACTION_CREATE":      1,
+		"CONTAINER_ACTION_RECREATE":    2,
+		"CONTAINER_ACTION_UNCHANGED":   3,
+		"CONTAINER_ACTION_REMOVE":      4,
+	}
+)
+
+func (x ContainerAction) Enum() *ContainerAction {
+	p := new(ContainerAction)
+	*p = x
+	return p
+}
+
+func (x ContainerAction) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ContainerAction) Descriptor() protoreflect.EnumDescriptor {
+	return file_scheduler_proto_enumTypes[7].Descriptor()
+}
+
+func (ContainerAction) Type() protoreflect.EnumType {
+	return &file_scheduler_proto_enumTypes[7]
+}
+
+func (x ContainerAction) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ContainerAction.Descriptor instead.
+func (ContainerAction) EnumDescriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{7}
+}
+
+// Type a template parameter value must parse as
+type ParameterType int32
+
+const (
+	ParameterType_PARAMETER_TYPE_UNSPECIFIED ParameterType = 0 // any string
+	ParameterType_PARAMETER_TYPE_STRING      ParameterType = 1
+	ParameterType_PARAMETER_TYPE_INTEGER     ParameterType = 2
+	ParameterType_PARAMETER_TYPE_BOOLEAN     ParameterType = 3
+)
+
+// Enum value maps for ParameterType.
+var (
+	ParameterType_name = map[int32]string{
+		0: "PARAMETER_TYPE_UNSPECIFIED",
+		1: "PARAMETER_TYPE_STRING",
+		2: "PARAMETER_TYPE_INTEGER",
+		3: "PARAMETER_TYPE_BOOLEAN",
+	}
+	ParameterType_value = map[string]int32{
+		"PARAMETER_TYPE_UNSPECIFIED": 0,
+		"PARAMETER_TYPE_STRING":      1,
+		"PARAMETER_TYPE_INTEGER":     2,
+		"PARAMETER_TYPE_BOOLEAN":     3,
+	}
+)
+
+func (x ParameterType) Enum() *ParameterType {
+	p := new(ParameterType)
+	*p = x
+	return p
+}
+
+func (x ParameterType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ParameterType) Descriptor() protoreflect.EnumDescriptor {
+	return file_scheduler_proto_enumTypes[8].Descriptor()
+}
+
+func (ParameterType) Type() protoreflect.EnumType {
+	return &file_scheduler_proto_enumTypes[8]
+}
+
+func (x ParameterType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ParameterType.Descriptor instead.
+func (ParameterType) EnumDescriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{8}
+}
+
+// How a snapshot copies volume data
+type SnapshotMethod int32
+
+const (
+	SnapshotMethod_SNAPSHOT_METHOD_UNSPECIFIED SnapshotMethod = 0 // reflink copy where the filesystem allows, tarball otherwise
+	SnapshotMethod_SNAPSHOT_METHOD_TARBALL     SnapshotMethod = 1
+	SnapshotMethod_SNAPSHOT_METHOD_REFLINK     SnapshotMethod = 2
+)
+
+// Enum value maps for SnapshotMethod.
+var (
+	SnapshotMethod_name = map[int32]string{
+		0: "SNAPSHOT_METHOD_UNSPECIFIED",
+		1: "SNAPSHOT_METHOD_TARBALL",
+		2: "SNAPSHOT_METHOD_REFLINK",
+	}
+	SnapshotMethod_value = map[string]int32{
+		"SNAPSHOT_METHOD_UNSPECIFIED": 0,
+		"SNAPSHOT_METHOD_TARBALL":     1,
+		"SNAPSHOT_METHOD_REFLINK":     2,
+	}
+)
+
+func (x SnapshotMethod) Enum() *SnapshotMethod {
+	p := new(SnapshotMethod)
+	*p = x
+	return p
+}
+
+func (x SnapshotMethod) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SnapshotMethod) Descriptor() protoreflect.EnumDescriptor {
+	return file_scheduler_proto_enumTypes[9].Descriptor()
+}
+
+func (SnapshotMethod) Type() protoreflect.EnumType {
+	return &file_scheduler_proto_enumTypes[9]
+}
+
+func (x SnapshotMethod) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SnapshotMethod.Descriptor instead.
+func (SnapshotMethod) EnumDescriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{9}
 }
 
-// Container configuration for individual services within an environment
+// Container configuration for individual services within an environment.
+// Environment variable values of the form secret://<name> are replaced with
+// the value of the named secret when the container starts.
 type ContainerConfig struct {
 	state                protoimpl.MessageState `protogen:"open.v1"`
 	Name                 string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
@@ -221,6 +598,8 @@ type ContainerConfig struct {
 	Resources            *ResourceLimits        `protobuf:"bytes,8,opt,name=resources,proto3" json:"resources,omitempty"`
 	HealthCheck          *HealthCheck           `protobuf:"bytes,9,opt,name=health_check,json=healthCheck,proto3" json:"health_check,omitempty"`
 	RestartPolicy        RestartPolicy          `protobuf:"varint,10,opt,name=restart_policy,json=restartPolicy,proto3,enum=scheduler.v1.RestartPolicy" json:"restart_policy,omitempty"`
+	SecretFiles          []*SecretFile          `protobuf:"bytes,11,rep,name=secret_files,json=secretFiles,proto3" json:"secret_files,omitempty"`
+	Lifecycle            *LifecycleHooks        `protobuf:"bytes,12,opt,name=lifecycle,proto3" json:"lifecycle,omitempty"`
 	unknownFields        protoimpl.UnknownFields
 	sizeCache            protoimpl.SizeCache
 }
@@ -325,30 +704,43 @@ func (x *ContainerConfig) GetRestartPolicy() RestartPolicy {
 	return RestartPolicy_RESTART_POLICY_UNSPECIFIED
 }
 
-// Port mapping configuration
-type PortMapping struct {
+func (x *ContainerConfig) GetSecretFiles() []*SecretFile {
+	if x != nil {
+		return x.SecretFiles
+	}
+	return nil
+}
+
+func (x *ContainerConfig) GetLifecycle() *LifecycleHooks {
+	if x != nil {
+		return x.Lifecycle
+	}
+	return nil
+}
+
+// Commands run inside a container around its start and stop
+type LifecycleHooks struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	ContainerPort int32                  `protobuf:"varint,1,opt,name=container_port,json=containerPort,proto3" json:"container_port,omitempty"`
-	HostPort      int32                  `protobuf:"varint,2,opt,name=host_port,json=hostPort,proto3" json:"host_port,omitempty"`
-	Protocol      string                 `protobuf:"bytes,3,opt,name=protocol,proto3" json:"protocol,omitempty"` // tcp, udp
+	PostStart     *ExecHook              `protobuf:"bytes,1,opt,name=post_start,json=postStart,proto3" json:"post_start,omitempty"` // runs once the container started, a failure fails the start
+	PreStop       *ExecHook              `protobuf:"bytes,2,opt,name=pre_stop,json=preStop,proto3" json:"pre_stop,omitempty"`       // runs before the container is stopped, skipped by forced stops
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *PortMapping) Reset() {
-	*x = PortMapping{}
+func (x *LifecycleHooks) Reset() {
+	*x = LifecycleHooks{}
 	mi := &file_scheduler_proto_msgTypes[1]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *PortMapping) String() string {
+func (x *LifecycleHooks) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PortMapping) ProtoMessage() {}
+func (*LifecycleHooks) ProtoMessage() {}
 
-func (x *PortMapping) ProtoReflect() protoreflect.Message {
+func (x *LifecycleHooks) ProtoReflect() protoreflect.Message {
 	mi := &file_scheduler_proto_msgTypes[1]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -360,57 +752,48 @@ func (x *PortMapping) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PortMapping.ProtoReflect.Descriptor instead.
-func (*PortMapping) Descriptor() ([]byte, []int) {
+// Deprecated: Use LifecycleHooks.ProtoReflect.Descriptor instead.
+func (*LifecycleHooks) Descriptor() ([]byte, []int) {
 	return file_scheduler_proto_rawDescGZIP(), []int{1}
 }
 
-func (x *PortMapping) GetContainerPort() int32 {
-	if x != nil {
-		return x.ContainerPort
-	}
-	return 0
-}
-
-func (x *PortMapping) GetHostPort() int32 {
+func (x *LifecycleHooks) GetPostStart() *ExecHook {
 	if x != nil {
-		return x.HostPort
+		return x.PostStart
 	}
-	return 0
+	return nil
 }
 
-func (x *PortMapping) GetProtocol() string {
+func (x *LifecycleHooks) GetPreStop() *ExecHook {
 	if x != nil {
-		return x.Protocol
+		return x.PreStop
 	}
-	return ""
+	return nil
 }
 
-// Volume mount configuration
-type VolumeMount struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	MountPath     string                 `protobuf:"bytes,2,opt,name=mount_path,json=mountPath,proto3" json:"mount_path,omitempty"`
-	HostPath      string                 `protobuf:"bytes,3,opt,name=host_path,json=hostPath,proto3" json:"host_path,omitempty"`
-	ReadOnly      bool                   `protobuf:"varint,4,opt,name=read_only,json=readOnly,proto3" json:"read_only,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+// Command run inside a container by a lifecycle hook
+type ExecHook struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Command        []string               `protobuf:"bytes,1,rep,name=command,proto3" json:"command,omitempty"`
+	TimeoutSeconds int32                  `protobuf:"varint,2,opt,name=timeout_seconds,json=timeoutSeconds,proto3" json:"timeout_seconds,omitempty"` // 0 waits 30 seconds
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
-func (x *VolumeMount) Reset() {
-	*x = VolumeMount{}
+func (x *ExecHook) Reset() {
+	*x = ExecHook{}
 	mi := &file_scheduler_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *VolumeMount) String() string {
+func (x *ExecHook) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*VolumeMount) ProtoMessage() {}
+func (*ExecHook) ProtoMessage() {}
 
-func (x *VolumeMount) ProtoReflect() protoreflect.Message {
+func (x *ExecHook) ProtoReflect() protoreflect.Message {
 	mi := &file_scheduler_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -422,31 +805,201 @@ func (x *VolumeMount) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use VolumeMount.ProtoReflect.Descriptor instead.
-func (*VolumeMount) Descriptor() ([]byte, []int) {
+// Deprecated: Use ExecHook.ProtoReflect.Descriptor instead.
+func (*ExecHook) Descriptor() ([]byte, []int) {
 	return file_scheduler_proto_rawDescGZIP(), []int{2}
 }
 
-func (x *VolumeMount) GetName() string {
+func (x *ExecHook) GetCommand() []string {
 	if x != nil {
-		return x.Name
+		return x.Command
 	}
-	return ""
+	return nil
 }
 
-func (x *VolumeMount) GetMountPath() string {
+func (x *ExecHook) GetTimeoutSeconds() int32 {
 	if x != nil {
-		return x.MountPath
+		return x.TimeoutSeconds
 	}
-	return ""
+	return 0
 }
 
-func (x *VolumeMount) GetHostPath() string {
-	if x != nil {
-		return x.HostPath
-	}
-	return ""
-}
+// Secret written to a file on an in-memory filesystem inside the container
+type SecretFile struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Secret        string                 `protobuf:"bytes,1,opt,name=secret,proto3" json:"secret,omitempty"` // name of the secret
+	Path          string                 `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`     // absolute path of the file inside the container
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SecretFile) Reset() {
+	*x = SecretFile{}
+	mi := &file_scheduler_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SecretFile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SecretFile) ProtoMessage() {}
+
+func (x *SecretFile) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SecretFile.ProtoReflect.Descriptor instead.
+func (*SecretFile) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *SecretFile) GetSecret() string {
+	if x != nil {
+		return x.Secret
+	}
+	return ""
+}
+
+func (x *SecretFile) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+// Port mapping configuration
+type PortMapping struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ContainerPort int32                  `protobuf:"varint,1,opt,name=container_port,json=containerPort,proto3" json:"container_port,omitempty"`
+	HostPort      int32                  `protobuf:"varint,2,opt,name=host_port,json=hostPort,proto3" json:"host_port,omitempty"`
+	Protocol      string                 `protobuf:"bytes,3,opt,name=protocol,proto3" json:"protocol,omitempty"` // tcp, udp
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PortMapping) Reset() {
+	*x = PortMapping{}
+	mi := &file_scheduler_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PortMapping) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PortMapping) ProtoMessage() {}
+
+func (x *PortMapping) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PortMapping.ProtoReflect.Descriptor instead.
+func (*PortMapping) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *PortMapping) GetContainerPort() int32 {
+	if x != nil {
+		return x.ContainerPort
+	}
+	return 0
+}
+
+func (x *PortMapping) GetHostPort() int32 {
+	if x != nil {
+		return x.HostPort
+	}
+	return 0
+}
+
+func (x *PortMapping) GetProtocol() string {
+	if x != nil {
+		return x.Protocol
+	}
+	return ""
+}
+
+// Volume mount configuration. When host_path is empty, name refers to a named
+// volume managed by the scheduler, which is created on first use.
+type VolumeMount struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	MountPath     string                 `protobuf:"bytes,2,opt,name=mount_path,json=mountPath,proto3" json:"mount_path,omitempty"`
+	HostPath      string                 `protobuf:"bytes,3,opt,name=host_path,json=hostPath,proto3" json:"host_path,omitempty"`
+	ReadOnly      bool                   `protobuf:"varint,4,opt,name=read_only,json=readOnly,proto3" json:"read_only,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VolumeMount) Reset() {
+	*x = VolumeMount{}
+	mi := &file_scheduler_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VolumeMount) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VolumeMount) ProtoMessage() {}
+
+func (x *VolumeMount) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VolumeMount.ProtoReflect.Descriptor instead.
+func (*VolumeMount) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *VolumeMount) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *VolumeMount) GetMountPath() string {
+	if x != nil {
+		return x.MountPath
+	}
+	return ""
+}
+
+func (x *VolumeMount) GetHostPath() string {
+	if x != nil {
+		return x.HostPath
+	}
+	return ""
+}
 
 func (x *VolumeMount) GetReadOnly() bool {
 	if x != nil {
@@ -467,7 +1020,7 @@ type ResourceLimits struct {
 
 func (x *ResourceLimits) Reset() {
 	*x = ResourceLimits{}
-	mi := &file_scheduler_proto_msgTypes[3]
+	mi := &file_scheduler_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -479,7 +1032,7 @@ func (x *ResourceLimits) String() string {
 func (*ResourceLimits) ProtoMessage() {}
 
 func (x *ResourceLimits) ProtoReflect() protoreflect.Message {
-	mi := &file_scheduler_proto_msgTypes[3]
+	mi := &file_scheduler_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -492,7 +1045,7 @@ func (x *ResourceLimits) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ResourceLimits.ProtoReflect.Descriptor instead.
 func (*ResourceLimits) Descriptor() ([]byte, []int) {
-	return file_scheduler_proto_rawDescGZIP(), []int{3}
+	return file_scheduler_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *ResourceLimits) GetMemoryMb() int64 {
@@ -530,7 +1083,7 @@ type HealthCheck struct {
 
 func (x *HealthCheck) Reset() {
 	*x = HealthCheck{}
-	mi := &file_scheduler_proto_msgTypes[4]
+	mi := &file_scheduler_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -542,7 +1095,7 @@ func (x *HealthCheck) String() string {
 func (*HealthCheck) ProtoMessage() {}
 
 func (x *HealthCheck) ProtoReflect() protoreflect.Message {
-	mi := &file_scheduler_proto_msgTypes[4]
+	mi := &file_scheduler_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -555,7 +1108,7 @@ func (x *HealthCheck) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use HealthCheck.ProtoReflect.Descriptor instead.
 func (*HealthCheck) Descriptor() ([]byte, []int) {
-	return file_scheduler_proto_rawDescGZIP(), []int{4}
+	return file_scheduler_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *HealthCheck) GetCommand() []string {
@@ -608,7 +1161,7 @@ type ApplicationStack struct {
 
 func (x *ApplicationStack) Reset() {
 	*x = ApplicationStack{}
-	mi := &file_scheduler_proto_msgTypes[5]
+	mi := &file_scheduler_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -620,7 +1173,7 @@ func (x *ApplicationStack) String() string {
 func (*ApplicationStack) ProtoMessage() {}
 
 func (x *ApplicationStack) ProtoReflect() protoreflect.Message {
-	mi := &file_scheduler_proto_msgTypes[5]
+	mi := &file_scheduler_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -633,7 +1186,7 @@ func (x *ApplicationStack) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ApplicationStack.ProtoReflect.Descriptor instead.
 func (*ApplicationStack) Descriptor() ([]byte, []int) {
-	return file_scheduler_proto_rawDescGZIP(), []int{5}
+	return file_scheduler_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *ApplicationStack) GetName() string {
@@ -690,7 +1243,7 @@ type FrontendConfig struct {
 
 func (x *FrontendConfig) Reset() {
 	*x = FrontendConfig{}
-	mi := &file_scheduler_proto_msgTypes[6]
+	mi := &file_scheduler_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -702,7 +1255,7 @@ func (x *FrontendConfig) String() string {
 func (*FrontendConfig) ProtoMessage() {}
 
 func (x *FrontendConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_scheduler_proto_msgTypes[6]
+	mi := &file_scheduler_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -715,7 +1268,7 @@ func (x *FrontendConfig) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use FrontendConfig.ProtoReflect.Descriptor instead.
 func (*FrontendConfig) Descriptor() ([]byte, []int) {
-	return file_scheduler_proto_rawDescGZIP(), []int{6}
+	return file_scheduler_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *FrontendConfig) GetContainer() *ContainerConfig {
@@ -739,19 +1292,21 @@ func (x *FrontendConfig) GetSslEnabled() bool {
 	return false
 }
 
-// Backend container configuration
+// Backend container configuration. The connection string and API key values
+// may be secret://<name> references.
 type BackendConfig struct {
-	state                    protoimpl.MessageState `protogen:"open.v1"`
-	Container                *ContainerConfig       `protobuf:"bytes,1,opt,name=container,proto3" json:"container,omitempty"`
-	DatabaseConnectionString string                 `protobuf:"bytes,2,opt,name=database_connection_string,json=databaseConnectionString,proto3" json:"database_connection_string,omitempty"`
-	ApiKeys                  map[string]string      `protobuf:"bytes,3,rep,name=api_keys,json=apiKeys,proto3" json:"api_keys,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Container *ContainerConfig       `protobuf:"bytes,1,opt,name=container,proto3" json:"container,omitempty"`
+	// exposed as DATABASE_URL, derived from the database of the stack when empty
+	DatabaseConnectionString string            `protobuf:"bytes,2,opt,name=database_connection_string,json=databaseConnectionString,proto3" json:"database_connection_string,omitempty"`
+	ApiKeys                  map[string]string `protobuf:"bytes,3,rep,name=api_keys,json=apiKeys,proto3" json:"api_keys,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // exposed to the container as environment variables
 	unknownFields            protoimpl.UnknownFields
 	sizeCache                protoimpl.SizeCache
 }
 
 func (x *BackendConfig) Reset() {
 	*x = BackendConfig{}
-	mi := &file_scheduler_proto_msgTypes[7]
+	mi := &file_scheduler_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -763,7 +1318,7 @@ func (x *BackendConfig) String() string {
 func (*BackendConfig) ProtoMessage() {}
 
 func (x *BackendConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_scheduler_proto_msgTypes[7]
+	mi := &file_scheduler_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -776,7 +1331,7 @@ func (x *BackendConfig) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use BackendConfig.ProtoReflect.Descriptor instead.
 func (*BackendConfig) Descriptor() ([]byte, []int) {
-	return file_scheduler_proto_rawDescGZIP(), []int{7}
+	return file_scheduler_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *BackendConfig) GetContainer() *ContainerConfig {
@@ -800,22 +1355,31 @@ func (x *BackendConfig) GetApiKeys() map[string]string {
 	return nil
 }
 
-// Database container configuration
+// Database container configuration. The credentials are passed to the
+// database container as POSTGRES_* and to the backend as DATABASE_URL and PG*
+// environment variables, unless the containers set them explicitly.
 type DatabaseConfig struct {
 	state             protoimpl.MessageState `protogen:"open.v1"`
 	Container         *ContainerConfig       `protobuf:"bytes,1,opt,name=container,proto3" json:"container,omitempty"`
 	DatabaseName      string                 `protobuf:"bytes,2,opt,name=database_name,json=databaseName,proto3" json:"database_name,omitempty"`
 	Username          string                 `protobuf:"bytes,3,opt,name=username,proto3" json:"username,omitempty"`
-	Password          string                 `protobuf:"bytes,4,opt,name=password,proto3" json:"password,omitempty"`
+	Password          string                 `protobuf:"bytes,4,opt,name=password,proto3" json:"password,omitempty"` // may be a secret://<name> reference
 	PersistentStorage bool                   `protobuf:"varint,5,opt,name=persistent_storage,json=persistentStorage,proto3" json:"persistent_storage,omitempty"`
 	StoragePath       string                 `protobuf:"bytes,6,opt,name=storage_path,json=storagePath,proto3" json:"storage_path,omitempty"`
-	unknownFields     protoimpl.UnknownFields
-	sizeCache         protoimpl.SizeCache
+	BackupPolicy      *BackupPolicy          `protobuf:"bytes,7,opt,name=backup_policy,json=backupPolicy,proto3" json:"backup_policy,omitempty"`
+	// Once the database is healthy and before the backend starts, the init
+	// scripts, the migration and the seed data run in that order. Each step
+	// runs once and its result is recorded in Environment.database_steps.
+	InitScripts   []*DatabaseScript  `protobuf:"bytes,8,rep,name=init_scripts,json=initScripts,proto3" json:"init_scripts,omitempty"`
+	Migration     *DatabaseMigration `protobuf:"bytes,9,opt,name=migration,proto3" json:"migration,omitempty"`
+	SeedData      []*DatabaseScript  `protobuf:"bytes,10,rep,name=seed_data,json=seedData,proto3" json:"seed_data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *DatabaseConfig) Reset() {
 	*x = DatabaseConfig{}
-	mi := &file_scheduler_proto_msgTypes[8]
+	mi := &file_scheduler_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -827,7 +1391,7 @@ func (x *DatabaseConfig) String() string {
 func (*DatabaseConfig) ProtoMessage() {}
 
 func (x *DatabaseConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_scheduler_proto_msgTypes[8]
+	mi := &file_scheduler_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -840,7 +1404,7 @@ func (x *DatabaseConfig) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DatabaseConfig.ProtoReflect.Descriptor instead.
 func (*DatabaseConfig) Descriptor() ([]byte, []int) {
-	return file_scheduler_proto_rawDescGZIP(), []int{8}
+	return file_scheduler_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *DatabaseConfig) GetContainer() *ContainerConfig {
@@ -885,33 +1449,60 @@ func (x *DatabaseConfig) GetStoragePath() string {
 	return ""
 }
 
-// Environment specification - defines how an environment should be configured
-type EnvironmentSpecification struct {
-	state            protoimpl.MessageState `protogen:"open.v1"`
-	Name             string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Description      string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
-	ApplicationStack *ApplicationStack      `protobuf:"bytes,3,opt,name=application_stack,json=applicationStack,proto3" json:"application_stack,omitempty"`
-	Labels           map[string]string      `protobuf:"bytes,4,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
-	Network          *NetworkConfig         `protobuf:"bytes,5,opt,name=network,proto3" json:"network,omitempty"`
-	unknownFields    protoimpl.UnknownFields
-	sizeCache        protoimpl.SizeCache
+func (x *DatabaseConfig) GetBackupPolicy() *BackupPolicy {
+	if x != nil {
+		return x.BackupPolicy
+	}
+	return nil
 }
 
-func (x *EnvironmentSpecification) Reset() {
-	*x = EnvironmentSpecification{}
-	mi := &file_scheduler_proto_msgTypes[9]
+func (x *DatabaseConfig) GetInitScripts() []*DatabaseScript {
+	if x != nil {
+		return x.InitScripts
+	}
+	return nil
+}
+
+func (x *DatabaseConfig) GetMigration() *DatabaseMigration {
+	if x != nil {
+		return x.Migration
+	}
+	return nil
+}
+
+func (x *DatabaseConfig) GetSeedData() []*DatabaseScript {
+	if x != nil {
+		return x.SeedData
+	}
+	return nil
+}
+
+// SQL script run with psql inside the database container, given inline or
+// as a file from the scripts directory of the scheduler host
+type DatabaseScript struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"` // identifies the script in the recorded results
+	Sql           string                 `protobuf:"bytes,2,opt,name=sql,proto3" json:"sql,omitempty"`
+	HostPath      string                 `protobuf:"bytes,3,opt,name=host_path,json=hostPath,proto3" json:"host_path,omitempty"` // relative to database.scripts_dir, read when the script runs
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DatabaseScript) Reset() {
+	*x = DatabaseScript{}
+	mi := &file_scheduler_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *EnvironmentSpecification) String() string {
+func (x *DatabaseScript) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*EnvironmentSpecification) ProtoMessage() {}
+func (*DatabaseScript) ProtoMessage() {}
 
-func (x *EnvironmentSpecification) ProtoReflect() protoreflect.Message {
-	mi := &file_scheduler_proto_msgTypes[9]
+func (x *DatabaseScript) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -922,37 +1513,244 @@ func (x *EnvironmentSpecification) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use EnvironmentSpecification.ProtoReflect.Descriptor instead.
-func (*EnvironmentSpecification) Descriptor() ([]byte, []int) {
-	return file_scheduler_proto_rawDescGZIP(), []int{9}
+// Deprecated: Use DatabaseScript.ProtoReflect.Descriptor instead.
+func (*DatabaseScript) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{12}
 }
 
-func (x *EnvironmentSpecification) GetName() string {
+func (x *DatabaseScript) GetName() string {
 	if x != nil {
 		return x.Name
 	}
 	return ""
 }
 
-func (x *EnvironmentSpecification) GetDescription() string {
+func (x *DatabaseScript) GetSql() string {
 	if x != nil {
-		return x.Description
+		return x.Sql
 	}
 	return ""
 }
 
-func (x *EnvironmentSpecification) GetApplicationStack() *ApplicationStack {
+func (x *DatabaseScript) GetHostPath() string {
 	if x != nil {
-		return x.ApplicationStack
+		return x.HostPath
 	}
-	return nil
+	return ""
 }
 
-func (x *EnvironmentSpecification) GetLabels() map[string]string {
-	if x != nil {
-		return x.Labels
-	}
-	return nil
+// One-shot container applying schema migrations, with the DATABASE_URL and
+// PG* variables of the backend
+type DatabaseMigration struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	Image                string                 `protobuf:"bytes,1,opt,name=image,proto3" json:"image,omitempty"`
+	Command              []string               `protobuf:"bytes,2,rep,name=command,proto3" json:"command,omitempty"`
+	Args                 []string               `protobuf:"bytes,3,rep,name=args,proto3" json:"args,omitempty"`
+	EnvironmentVariables map[string]string      `protobuf:"bytes,4,rep,name=environment_variables,json=environmentVariables,proto3" json:"environment_variables,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Version              string                 `protobuf:"bytes,5,opt,name=version,proto3" json:"version,omitempty"`                                      // the migration runs again when it changes, defaults to the image
+	TimeoutSeconds       int32                  `protobuf:"varint,6,opt,name=timeout_seconds,json=timeoutSeconds,proto3" json:"timeout_seconds,omitempty"` // 0 waits 10 minutes
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *DatabaseMigration) Reset() {
+	*x = DatabaseMigration{}
+	mi := &file_scheduler_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DatabaseMigration) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DatabaseMigration) ProtoMessage() {}
+
+func (x *DatabaseMigration) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DatabaseMigration.ProtoReflect.Descriptor instead.
+func (*DatabaseMigration) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *DatabaseMigration) GetImage() string {
+	if x != nil {
+		return x.Image
+	}
+	return ""
+}
+
+func (x *DatabaseMigration) GetCommand() []string {
+	if x != nil {
+		return x.Command
+	}
+	return nil
+}
+
+func (x *DatabaseMigration) GetArgs() []string {
+	if x != nil {
+		return x.Args
+	}
+	return nil
+}
+
+func (x *DatabaseMigration) GetEnvironmentVariables() map[string]string {
+	if x != nil {
+		return x.EnvironmentVariables
+	}
+	return nil
+}
+
+func (x *DatabaseMigration) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *DatabaseMigration) GetTimeoutSeconds() int32 {
+	if x != nil {
+		return x.TimeoutSeconds
+	}
+	return 0
+}
+
+// Schedule for taking logical database backups automatically
+type BackupPolicy struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	IntervalSeconds int32                  `protobuf:"varint,1,opt,name=interval_seconds,json=intervalSeconds,proto3" json:"interval_seconds,omitempty"` // 0 disables scheduled backups
+	Retain          int32                  `protobuf:"varint,2,opt,name=retain,proto3" json:"retain,omitempty"`                                          // number of scheduled backups to keep, 0 keeps all
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *BackupPolicy) Reset() {
+	*x = BackupPolicy{}
+	mi := &file_scheduler_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BackupPolicy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BackupPolicy) ProtoMessage() {}
+
+func (x *BackupPolicy) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BackupPolicy.ProtoReflect.Descriptor instead.
+func (*BackupPolicy) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *BackupPolicy) GetIntervalSeconds() int32 {
+	if x != nil {
+		return x.IntervalSeconds
+	}
+	return 0
+}
+
+func (x *BackupPolicy) GetRetain() int32 {
+	if x != nil {
+		return x.Retain
+	}
+	return 0
+}
+
+// Environment specification - defines how an environment should be configured
+type EnvironmentSpecification struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Name             string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Description      string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	ApplicationStack *ApplicationStack      `protobuf:"bytes,3,opt,name=application_stack,json=applicationStack,proto3" json:"application_stack,omitempty"`
+	Labels           map[string]string      `protobuf:"bytes,4,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Network          *NetworkConfig         `protobuf:"bytes,5,opt,name=network,proto3" json:"network,omitempty"`
+	// Environments waiting for host capacity are admitted by descending
+	// priority, and may preempt running environments of lower priority when
+	// preemption is enabled
+	Priority      int32        `protobuf:"varint,6,opt,name=priority,proto3" json:"priority,omitempty"`
+	Hooks         *DeployHooks `protobuf:"bytes,7,opt,name=hooks,proto3" json:"hooks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EnvironmentSpecification) Reset() {
+	*x = EnvironmentSpecification{}
+	mi := &file_scheduler_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EnvironmentSpecification) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnvironmentSpecification) ProtoMessage() {}
+
+func (x *EnvironmentSpecification) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnvironmentSpecification.ProtoReflect.Descriptor instead.
+func (*EnvironmentSpecification) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *EnvironmentSpecification) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *EnvironmentSpecification) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *EnvironmentSpecification) GetApplicationStack() *ApplicationStack {
+	if x != nil {
+		return x.ApplicationStack
+	}
+	return nil
+}
+
+func (x *EnvironmentSpecification) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
 }
 
 func (x *EnvironmentSpecification) GetNetwork() *NetworkConfig {
@@ -962,32 +1760,45 @@ func (x *EnvironmentSpecification) GetNetwork() *NetworkConfig {
 	return nil
 }
 
-// Network configuration for the environment
-type NetworkConfig struct {
+func (x *EnvironmentSpecification) GetPriority() int32 {
+	if x != nil {
+		return x.Priority
+	}
+	return 0
+}
+
+func (x *EnvironmentSpecification) GetHooks() *DeployHooks {
+	if x != nil {
+		return x.Hooks
+	}
+	return nil
+}
+
+// One-shot containers run on the environment network whenever the
+// environment is started. A failing hook fails the start.
+type DeployHooks struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	NetworkName   string                 `protobuf:"bytes,1,opt,name=network_name,json=networkName,proto3" json:"network_name,omitempty"`
-	Subnet        string                 `protobuf:"bytes,2,opt,name=subnet,proto3" json:"subnet,omitempty"`
-	Gateway       string                 `protobuf:"bytes,3,opt,name=gateway,proto3" json:"gateway,omitempty"`
-	Isolated      bool                   `protobuf:"varint,4,opt,name=isolated,proto3" json:"isolated,omitempty"`
+	PreDeploy     []*HookContainer       `protobuf:"bytes,1,rep,name=pre_deploy,json=preDeploy,proto3" json:"pre_deploy,omitempty"`    // run in order before any container starts
+	PostDeploy    []*HookContainer       `protobuf:"bytes,2,rep,name=post_deploy,json=postDeploy,proto3" json:"post_deploy,omitempty"` // run in order once every container started
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *NetworkConfig) Reset() {
-	*x = NetworkConfig{}
-	mi := &file_scheduler_proto_msgTypes[10]
+func (x *DeployHooks) Reset() {
+	*x = DeployHooks{}
+	mi := &file_scheduler_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *NetworkConfig) String() string {
+func (x *DeployHooks) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*NetworkConfig) ProtoMessage() {}
+func (*DeployHooks) ProtoMessage() {}
 
-func (x *NetworkConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_scheduler_proto_msgTypes[10]
+func (x *DeployHooks) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -998,68 +1809,4316 @@ func (x *NetworkConfig) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use NetworkConfig.ProtoReflect.Descriptor instead.
-func (*NetworkConfig) Descriptor() ([]byte, []int) {
-	return file_scheduler_proto_rawDescGZIP(), []int{10}
+// Deprecated: Use DeployHooks.ProtoReflect.Descriptor instead.
+func (*DeployHooks) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *DeployHooks) GetPreDeploy() []*HookContainer {
+	if x != nil {
+		return x.PreDeploy
+	}
+	return nil
+}
+
+func (x *DeployHooks) GetPostDeploy() []*HookContainer {
+	if x != nil {
+		return x.PostDeploy
+	}
+	return nil
+}
+
+// One-shot container run by a deploy hook
+type HookContainer struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	Name                 string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"` // lowercase letters, digits and dashes, unique within its phase
+	Image                string                 `protobuf:"bytes,2,opt,name=image,proto3" json:"image,omitempty"`
+	Command              []string               `protobuf:"bytes,3,rep,name=command,proto3" json:"command,omitempty"`
+	Args                 []string               `protobuf:"bytes,4,rep,name=args,proto3" json:"args,omitempty"`
+	EnvironmentVariables map[string]string      `protobuf:"bytes,5,rep,name=environment_variables,json=environmentVariables,proto3" json:"environment_variables,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // values may be secret://<name> references
+	TimeoutSeconds       int32                  `protobuf:"varint,6,opt,name=timeout_seconds,json=timeoutSeconds,proto3" json:"timeout_seconds,omitempty"`                                                                                            // 0 waits 10 minutes
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *HookContainer) Reset() {
+	*x = HookContainer{}
+	mi := &file_scheduler_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HookContainer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HookContainer) ProtoMessage() {}
+
+func (x *HookContainer) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HookContainer.ProtoReflect.Descriptor instead.
+func (*HookContainer) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *HookContainer) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *HookContainer) GetImage() string {
+	if x != nil {
+		return x.Image
+	}
+	return ""
+}
+
+func (x *HookContainer) GetCommand() []string {
+	if x != nil {
+		return x.Command
+	}
+	return nil
+}
+
+func (x *HookContainer) GetArgs() []string {
+	if x != nil {
+		return x.Args
+	}
+	return nil
+}
+
+func (x *HookContainer) GetEnvironmentVariables() map[string]string {
+	if x != nil {
+		return x.EnvironmentVariables
+	}
+	return nil
+}
+
+func (x *HookContainer) GetTimeoutSeconds() int32 {
+	if x != nil {
+		return x.TimeoutSeconds
+	}
+	return 0
+}
+
+// Network configuration for the environment
+type NetworkConfig struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	NetworkName   string                 `protobuf:"bytes,1,opt,name=network_name,json=networkName,proto3" json:"network_name,omitempty"`
+	Subnet        string                 `protobuf:"bytes,2,opt,name=subnet,proto3" json:"subnet,omitempty"`
+	Gateway       string                 `protobuf:"bytes,3,opt,name=gateway,proto3" json:"gateway,omitempty"`
+	Isolated      bool                   `protobuf:"varint,4,opt,name=isolated,proto3" json:"isolated,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NetworkConfig) Reset() {
+	*x = NetworkConfig{}
+	mi := &file_scheduler_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NetworkConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NetworkConfig) ProtoMessage() {}
+
+func (x *NetworkConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NetworkConfig.ProtoReflect.Descriptor instead.
+func (*NetworkConfig) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *NetworkConfig) GetNetworkName() string {
+	if x != nil {
+		return x.NetworkName
+	}
+	return ""
+}
+
+func (x *NetworkConfig) GetSubnet() string {
+	if x != nil {
+		return x.Subnet
+	}
+	return ""
+}
+
+func (x *NetworkConfig) GetGateway() string {
+	if x != nil {
+		return x.Gateway
+	}
+	return ""
+}
+
+func (x *NetworkConfig) GetIsolated() bool {
+	if x != nil {
+		return x.Isolated
+	}
+	return false
+}
+
+// Environment represents a deployed containerized environment
+type Environment struct {
+	state         protoimpl.MessageState    `protogen:"open.v1"`
+	Id            string                    `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                    `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Spec          *EnvironmentSpecification `protobuf:"bytes,3,opt,name=spec,proto3" json:"spec,omitempty"`
+	Status        EnvironmentStatus         `protobuf:"varint,4,opt,name=status,proto3,enum=scheduler.v1.EnvironmentStatus" json:"status,omitempty"`
+	CreatedAt     *timestamppb.Timestamp    `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp    `protobuf:"bytes,6,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Containers    []*ContainerInstance      `protobuf:"bytes,7,rep,name=containers,proto3" json:"containers,omitempty"`
+	Tenant        string                    `protobuf:"bytes,8,opt,name=tenant,proto3" json:"tenant,omitempty"`                                     // tenant owning the environment, names are unique within it
+	QueuePosition int32                     `protobuf:"varint,9,opt,name=queue_position,json=queuePosition,proto3" json:"queue_position,omitempty"` // 1-based position in the admission queue while waiting for capacity, 0 otherwise
+	Revision      int32                     `protobuf:"varint,10,opt,name=revision,proto3" json:"revision,omitempty"`                               // number of the revision the current spec was applied as
+	Template      *TemplateReference        `protobuf:"bytes,11,opt,name=template,proto3" json:"template,omitempty"`                                // template the environment was created from, if any
+	DatabaseSteps []*DatabaseStepResult     `protobuf:"bytes,12,rep,name=database_steps,json=databaseSteps,proto3" json:"database_steps,omitempty"` // latest result of each database initialization step
+	HookResults   []*HookResult             `protobuf:"bytes,13,rep,name=hook_results,json=hookResults,proto3" json:"hook_results,omitempty"`       // latest result of each hook
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Environment) Reset() {
+	*x = Environment{}
+	mi := &file_scheduler_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Environment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Environment) ProtoMessage() {}
+
+func (x *Environment) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Environment.ProtoReflect.Descriptor instead.
+func (*Environment) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *Environment) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Environment) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Environment) GetSpec() *EnvironmentSpecification {
+	if x != nil {
+		return x.Spec
+	}
+	return nil
+}
+
+func (x *Environment) GetStatus() EnvironmentStatus {
+	if x != nil {
+		return x.Status
+	}
+	return EnvironmentStatus_ENVIRONMENT_STATUS_UNSPECIFIED
+}
+
+func (x *Environment) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Environment) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *Environment) GetContainers() []*ContainerInstance {
+	if x != nil {
+		return x.Containers
+	}
+	return nil
+}
+
+func (x *Environment) GetTenant() string {
+	if x != nil {
+		return x.Tenant
+	}
+	return ""
+}
+
+func (x *Environment) GetQueuePosition() int32 {
+	if x != nil {
+		return x.QueuePosition
+	}
+	return 0
+}
+
+func (x *Environment) GetRevision() int32 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+func (x *Environment) GetTemplate() *TemplateReference {
+	if x != nil {
+		return x.Template
+	}
+	return nil
+}
+
+func (x *Environment) GetDatabaseSteps() []*DatabaseStepResult {
+	if x != nil {
+		return x.DatabaseSteps
+	}
+	return nil
+}
+
+func (x *Environment) GetHookResults() []*HookResult {
+	if x != nil {
+		return x.HookResults
+	}
+	return nil
+}
+
+// Outcome of the latest run of a hook
+type HookResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Phase         HookPhase              `protobuf:"varint,1,opt,name=phase,proto3,enum=scheduler.v1.HookPhase" json:"phase,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"` // hook container name, or the alias of the container of an exec hook
+	Succeeded     bool                   `protobuf:"varint,3,opt,name=succeeded,proto3" json:"succeeded,omitempty"`
+	Output        string                 `protobuf:"bytes,4,opt,name=output,proto3" json:"output,omitempty"` // end of the output of the hook, may echo secrets
+	CompletedAt   *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=completed_at,json=completedAt,proto3" json:"completed_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HookResult) Reset() {
+	*x = HookResult{}
+	mi := &file_scheduler_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HookResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HookResult) ProtoMessage() {}
+
+func (x *HookResult) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HookResult.ProtoReflect.Descriptor instead.
+func (*HookResult) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *HookResult) GetPhase() HookPhase {
+	if x != nil {
+		return x.Phase
+	}
+	return HookPhase_HOOK_PHASE_UNSPECIFIED
+}
+
+func (x *HookResult) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *HookResult) GetSucceeded() bool {
+	if x != nil {
+		return x.Succeeded
+	}
+	return false
+}
+
+func (x *HookResult) GetOutput() string {
+	if x != nil {
+		return x.Output
+	}
+	return ""
+}
+
+func (x *HookResult) GetCompletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CompletedAt
+	}
+	return nil
+}
+
+// Outcome of running a database initialization step. Steps that succeeded
+// are skipped on later starts while the database keeps its data in a volume.
+type DatabaseStepResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Step          DatabaseStep           `protobuf:"varint,1,opt,name=step,proto3,enum=scheduler.v1.DatabaseStep" json:"step,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"` // script name or migration version
+	Succeeded     bool                   `protobuf:"varint,3,opt,name=succeeded,proto3" json:"succeeded,omitempty"`
+	Output        string                 `protobuf:"bytes,4,opt,name=output,proto3" json:"output,omitempty"` // end of the output of the step, may echo secrets and data
+	CompletedAt   *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=completed_at,json=completedAt,proto3" json:"completed_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DatabaseStepResult) Reset() {
+	*x = DatabaseStepResult{}
+	mi := &file_scheduler_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DatabaseStepResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DatabaseStepResult) ProtoMessage() {}
+
+func (x *DatabaseStepResult) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DatabaseStepResult.ProtoReflect.Descriptor instead.
+func (*DatabaseStepResult) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *DatabaseStepResult) GetStep() DatabaseStep {
+	if x != nil {
+		return x.Step
+	}
+	return DatabaseStep_DATABASE_STEP_UNSPECIFIED
+}
+
+func (x *DatabaseStepResult) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *DatabaseStepResult) GetSucceeded() bool {
+	if x != nil {
+		return x.Succeeded
+	}
+	return false
+}
+
+func (x *DatabaseStepResult) GetOutput() string {
+	if x != nil {
+		return x.Output
+	}
+	return ""
+}
+
+func (x *DatabaseStepResult) GetCompletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CompletedAt
+	}
+	return nil
+}
+
+// Instance of a running container within an environment
+type ContainerInstance struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Image         string                 `protobuf:"bytes,3,opt,name=image,proto3" json:"image,omitempty"`
+	Status        ContainerStatus        `protobuf:"varint,4,opt,name=status,proto3,enum=scheduler.v1.ContainerStatus" json:"status,omitempty"`
+	StartedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	ExposedPorts  []*PortMapping         `protobuf:"bytes,6,rep,name=exposed_ports,json=exposedPorts,proto3" json:"exposed_ports,omitempty"`
+	IpAddress     string                 `protobuf:"bytes,7,opt,name=ip_address,json=ipAddress,proto3" json:"ip_address,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ContainerInstance) Reset() {
+	*x = ContainerInstance{}
+	mi := &file_scheduler_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ContainerInstance) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ContainerInstance) ProtoMessage() {}
+
+func (x *ContainerInstance) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ContainerInstance.ProtoReflect.Descriptor instead.
+func (*ContainerInstance) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ContainerInstance) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ContainerInstance) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ContainerInstance) GetImage() string {
+	if x != nil {
+		return x.Image
+	}
+	return ""
+}
+
+func (x *ContainerInstance) GetStatus() ContainerStatus {
+	if x != nil {
+		return x.Status
+	}
+	return ContainerStatus_CONTAINER_STATUS_UNSPECIFIED
+}
+
+func (x *ContainerInstance) GetStartedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartedAt
+	}
+	return nil
+}
+
+func (x *ContainerInstance) GetExposedPorts() []*PortMapping {
+	if x != nil {
+		return x.ExposedPorts
+	}
+	return nil
+}
+
+func (x *ContainerInstance) GetIpAddress() string {
+	if x != nil {
+		return x.IpAddress
+	}
+	return ""
+}
+
+type CreateEnvironmentRequest struct {
+	state         protoimpl.MessageState    `protogen:"open.v1"`
+	Spec          *EnvironmentSpecification `protobuf:"bytes,1,opt,name=spec,proto3" json:"spec,omitempty"`
+	Tenant        string                    `protobuf:"bytes,2,opt,name=tenant,proto3" json:"tenant,omitempty"` // defaults to the caller's tenant, or "default"
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateEnvironmentRequest) Reset() {
+	*x = CreateEnvironmentRequest{}
+	mi := &file_scheduler_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateEnvironmentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateEnvironmentRequest) ProtoMessage() {}
+
+func (x *CreateEnvironmentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateEnvironmentRequest.ProtoReflect.Descriptor instead.
+func (*CreateEnvironmentRequest) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *CreateEnvironmentRequest) GetSpec() *EnvironmentSpecification {
+	if x != nil {
+		return x.Spec
+	}
+	return nil
+}
+
+func (x *CreateEnvironmentRequest) GetTenant() string {
+	if x != nil {
+		return x.Tenant
+	}
+	return ""
+}
+
+type CreateEnvironmentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Environment   *Environment           `protobuf:"bytes,1,opt,name=environment,proto3" json:"environment,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateEnvironmentResponse) Reset() {
+	*x = CreateEnvironmentResponse{}
+	mi := &file_scheduler_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateEnvironmentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateEnvironmentResponse) ProtoMessage() {}
+
+func (x *CreateEnvironmentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateEnvironmentResponse.ProtoReflect.Descriptor instead.
+func (*CreateEnvironmentResponse) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *CreateEnvironmentResponse) GetEnvironment() *Environment {
+	if x != nil {
+		return x.Environment
+	}
+	return nil
+}
+
+type GetEnvironmentRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Id              string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	RevealSensitive bool                   `protobuf:"varint,2,opt,name=reveal_sensitive,json=revealSensitive,proto3" json:"reveal_sensitive,omitempty"` // return sensitive fields unmasked, if permitted
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *GetEnvironmentRequest) Reset() {
+	*x = GetEnvironmentRequest{}
+	mi := &file_scheduler_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetEnvironmentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetEnvironmentRequest) ProtoMessage() {}
+
+func (x *GetEnvironmentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetEnvironmentRequest.ProtoReflect.Descriptor instead.
+func (*GetEnvironmentRequest) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *GetEnvironmentRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *GetEnvironmentRequest) GetRevealSensitive() bool {
+	if x != nil {
+		return x.RevealSensitive
+	}
+	return false
+}
+
+type GetEnvironmentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Environment   *Environment           `protobuf:"bytes,1,opt,name=environment,proto3" json:"environment,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetEnvironmentResponse) Reset() {
+	*x = GetEnvironmentResponse{}
+	mi := &file_scheduler_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetEnvironmentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetEnvironmentResponse) ProtoMessage() {}
+
+func (x *GetEnvironmentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetEnvironmentResponse.ProtoReflect.Descriptor instead.
+func (*GetEnvironmentResponse) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *GetEnvironmentResponse) GetEnvironment() *Environment {
+	if x != nil {
+		return x.Environment
+	}
+	return nil
+}
+
+type UpdateEnvironmentRequest struct {
+	state         protoimpl.MessageState    `protogen:"open.v1"`
+	Id            string                    `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Spec          *EnvironmentSpecification `protobuf:"bytes,2,opt,name=spec,proto3" json:"spec,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateEnvironmentRequest) Reset() {
+	*x = UpdateEnvironmentRequest{}
+	mi := &file_scheduler_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateEnvironmentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateEnvironmentRequest) ProtoMessage() {}
+
+func (x *UpdateEnvironmentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateEnvironmentRequest.ProtoReflect.Descriptor instead.
+func (*UpdateEnvironmentRequest) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *UpdateEnvironmentRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateEnvironmentRequest) GetSpec() *EnvironmentSpecification {
+	if x != nil {
+		return x.Spec
+	}
+	return nil
+}
+
+type UpdateEnvironmentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Environment   *Environment           `protobuf:"bytes,1,opt,name=environment,proto3" json:"environment,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateEnvironmentResponse) Reset() {
+	*x = UpdateEnvironmentResponse{}
+	mi := &file_scheduler_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateEnvironmentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateEnvironmentResponse) ProtoMessage() {}
+
+func (x *UpdateEnvironmentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateEnvironmentResponse.ProtoReflect.Descriptor instead.
+func (*UpdateEnvironmentResponse) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *UpdateEnvironmentResponse) GetEnvironment() *Environment {
+	if x != nil {
+		return x.Environment
+	}
+	return nil
+}
+
+type DeleteEnvironmentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	PurgeVolumes  bool                   `protobuf:"varint,2,opt,name=purge_volumes,json=purgeVolumes,proto3" json:"purge_volumes,omitempty"` // also delete the named volumes the environment created, refused while other environments mount them
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteEnvironmentRequest) Reset() {
+	*x = DeleteEnvironmentRequest{}
+	mi := &file_scheduler_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteEnvironmentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteEnvironmentRequest) ProtoMessage() {}
+
+func (x *DeleteEnvironmentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteEnvironmentRequest.ProtoReflect.Descriptor instead.
+func (*DeleteEnvironmentRequest) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *DeleteEnvironmentRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DeleteEnvironmentRequest) GetPurgeVolumes() bool {
+	if x != nil {
+		return x.PurgeVolumes
+	}
+	return false
+}
+
+type DeleteEnvironmentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteEnvironmentResponse) Reset() {
+	*x = DeleteEnvironmentResponse{}
+	mi := &file_scheduler_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteEnvironmentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteEnvironmentResponse) ProtoMessage() {}
+
+func (x *DeleteEnvironmentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteEnvironmentResponse.ProtoReflect.Descriptor instead.
+func (*DeleteEnvironmentResponse) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *DeleteEnvironmentResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+type ListEnvironmentsRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	PageSize        int32                  `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken       string                 `protobuf:"bytes,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	Filters         map[string]string      `protobuf:"bytes,3,rep,name=filters,proto3" json:"filters,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	RevealSensitive bool                   `protobuf:"varint,4,opt,name=reveal_sensitive,json=revealSensitive,proto3" json:"reveal_sensitive,omitempty"` // return sensitive fields unmasked, if permitted
+	Tenant          string                 `protobuf:"bytes,5,opt,name=tenant,proto3" json:"tenant,omitempty"`                                           // callers bound to a tenant only ever see their own
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ListEnvironmentsRequest) Reset() {
+	*x = ListEnvironmentsRequest{}
+	mi := &file_scheduler_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListEnvironmentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListEnvironmentsRequest) ProtoMessage() {}
+
+func (x *ListEnvironmentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListEnvironmentsRequest.ProtoReflect.Descriptor instead.
+func (*ListEnvironmentsRequest) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *ListEnvironmentsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListEnvironmentsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListEnvironmentsRequest) GetFilters() map[string]string {
+	if x != nil {
+		return x.Filters
+	}
+	return nil
+}
+
+func (x *ListEnvironmentsRequest) GetRevealSensitive() bool {
+	if x != nil {
+		return x.RevealSensitive
+	}
+	return false
+}
+
+func (x *ListEnvironmentsRequest) GetTenant() string {
+	if x != nil {
+		return x.Tenant
+	}
+	return ""
+}
+
+type ListEnvironmentsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Environments  []*Environment         `protobuf:"bytes,1,rep,name=environments,proto3" json:"environments,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	TotalCount    int32                  `protobuf:"varint,3,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListEnvironmentsResponse) Reset() {
+	*x = ListEnvironmentsResponse{}
+	mi := &file_scheduler_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListEnvironmentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListEnvironmentsResponse) ProtoMessage() {}
+
+func (x *ListEnvironmentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListEnvironmentsResponse.ProtoReflect.Descriptor instead.
+func (*ListEnvironmentsResponse) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *ListEnvironmentsResponse) GetEnvironments() []*Environment {
+	if x != nil {
+		return x.Environments
+	}
+	return nil
+}
+
+func (x *ListEnvironmentsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+func (x *ListEnvironmentsResponse) GetTotalCount() int32 {
+	if x != nil {
+		return x.TotalCount
+	}
+	return 0
+}
+
+// Raw apiVersion/kind manifest, which creates the environment it names or
+// updates the environment of that name in its tenant
+type ApplyManifestRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Manifest      string                 `protobuf:"bytes,1,opt,name=manifest,proto3" json:"manifest,omitempty"`            // YAML or JSON holding a single manifest, masked in logs since it may hold passwords
+	DryRun        bool                   `protobuf:"varint,2,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"` // check the manifest and report what applying it would do without changing anything
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ApplyManifestRequest) Reset() {
+	*x = ApplyManifestRequest{}
+	mi := &file_scheduler_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApplyManifestRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApplyManifestRequest) ProtoMessage() {}
+
+func (x *ApplyManifestRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApplyManifestRequest.ProtoReflect.Descriptor instead.
+func (*ApplyManifestRequest) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *ApplyManifestRequest) GetManifest() string {
+	if x != nil {
+		return x.Manifest
+	}
+	return ""
+}
+
+func (x *ApplyManifestRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+type ApplyManifestResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Environment   *Environment           `protobuf:"bytes,1,opt,name=environment,proto3" json:"environment,omitempty"` // the planned environment, without an ID, when a dry run would create it
+	Action        ManifestAction         `protobuf:"varint,2,opt,name=action,proto3,enum=scheduler.v1.ManifestAction" json:"action,omitempty"`
+	Changes       []*FieldChange         `protobuf:"bytes,3,rep,name=changes,proto3" json:"changes,omitempty"` // differences from the current specification
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ApplyManifestResponse) Reset() {
+	*x = ApplyManifestResponse{}
+	mi := &file_scheduler_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApplyManifestResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApplyManifestResponse) ProtoMessage() {}
+
+func (x *ApplyManifestResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApplyManifestResponse.ProtoReflect.Descriptor instead.
+func (*ApplyManifestResponse) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *ApplyManifestResponse) GetEnvironment() *Environment {
+	if x != nil {
+		return x.Environment
+	}
+	return nil
+}
+
+func (x *ApplyManifestResponse) GetAction() ManifestAction {
+	if x != nil {
+		return x.Action
+	}
+	return ManifestAction_MANIFEST_ACTION_UNSPECIFIED
+}
+
+func (x *ApplyManifestResponse) GetChanges() []*FieldChange {
+	if x != nil {
+		return x.Changes
+	}
+	return nil
+}
+
+// Difference at one field of a specification. Values of sensitive fields
+// are masked.
+type FieldChange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"` // such as application_stack.backend.container.ports[0].host_port
+	Type          ChangeType             `protobuf:"varint,2,opt,name=type,proto3,enum=scheduler.v1.ChangeType" json:"type,omitempty"`
+	OldValue      string                 `protobuf:"bytes,3,opt,name=old_value,json=oldValue,proto3" json:"old_value,omitempty"`
+	NewValue      string                 `protobuf:"bytes,4,opt,name=new_value,json=newValue,proto3" json:"new_value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FieldChange) Reset() {
+	*x = FieldChange{}
+	mi := &file_scheduler_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FieldChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FieldChange) ProtoMessage() {}
+
+func (x *FieldChange) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FieldChange.ProtoReflect.Descriptor instead.
+func (*FieldChange) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *FieldChange) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *FieldChange) GetType() ChangeType {
+	if x != nil {
+		return x.Type
+	}
+	return ChangeType_CHANGE_TYPE_UNSPECIFIED
+}
+
+func (x *FieldChange) GetOldValue() string {
+	if x != nil {
+		return x.OldValue
+	}
+	return ""
+}
+
+func (x *FieldChange) GetNewValue() string {
+	if x != nil {
+		return x.NewValue
+	}
+	return ""
+}
+
+type PlanEnvironmentUpdateRequest struct {
+	state         protoimpl.MessageState    `protogen:"open.v1"`
+	Id            string                    `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Spec          *EnvironmentSpecification `protobuf:"bytes,2,opt,name=spec,proto3" json:"spec,omitempty"` // the specification UpdateEnvironment would be called with
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PlanEnvironmentUpdateRequest) Reset() {
+	*x = PlanEnvironmentUpdateRequest{}
+	mi := &file_scheduler_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlanEnvironmentUpdateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlanEnvironmentUpdateRequest) ProtoMessage() {}
+
+func (x *PlanEnvironmentUpdateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlanEnvironmentUpdateRequest.ProtoReflect.Descriptor instead.
+func (*PlanEnvironmentUpdateRequest) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *PlanEnvironmentUpdateRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *PlanEnvironmentUpdateRequest) GetSpec() *EnvironmentSpecification {
+	if x != nil {
+		return x.Spec
+	}
+	return nil
+}
+
+type PlanEnvironmentUpdateResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Environment   *Environment           `protobuf:"bytes,1,opt,name=environment,proto3" json:"environment,omitempty"` // the environment as it is now
+	Changes       []*FieldChange         `protobuf:"bytes,2,rep,name=changes,proto3" json:"changes,omitempty"`         // every difference from the current specification
+	Containers    []*ContainerPlan       `protobuf:"bytes,3,rep,name=containers,proto3" json:"containers,omitempty"`   // in startup order, followed by the removed containers
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PlanEnvironmentUpdateResponse) Reset() {
+	*x = PlanEnvironmentUpdateResponse{}
+	mi := &file_scheduler_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlanEnvironmentUpdateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlanEnvironmentUpdateResponse) ProtoMessage() {}
+
+func (x *PlanEnvironmentUpdateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlanEnvironmentUpdateResponse.ProtoReflect.Descriptor instead.
+func (*PlanEnvironmentUpdateResponse) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *PlanEnvironmentUpdateResponse) GetEnvironment() *Environment {
+	if x != nil {
+		return x.Environment
+	}
+	return nil
+}
+
+func (x *PlanEnvironmentUpdateResponse) GetChanges() []*FieldChange {
+	if x != nil {
+		return x.Changes
+	}
+	return nil
+}
+
+func (x *PlanEnvironmentUpdateResponse) GetContainers() []*ContainerPlan {
+	if x != nil {
+		return x.Containers
+	}
+	return nil
+}
+
+// Effect of an update on one container of the stack
+type ContainerPlan struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Alias          string                 `protobuf:"bytes,1,opt,name=alias,proto3" json:"alias,omitempty"` // role of the container or key of the additional service
+	Name           string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Action         ContainerAction        `protobuf:"varint,3,opt,name=action,proto3,enum=scheduler.v1.ContainerAction" json:"action,omitempty"`
+	Changes        []*FieldChange         `protobuf:"bytes,4,rep,name=changes,proto3" json:"changes,omitempty"`                                      // the differences that cause the action
+	VolumesChanged bool                   `protobuf:"varint,5,opt,name=volumes_changed,json=volumesChanged,proto3" json:"volumes_changed,omitempty"` // volume mounts are added, removed or changed
+	PortsChanged   bool                   `protobuf:"varint,6,opt,name=ports_changed,json=portsChanged,proto3" json:"ports_changed,omitempty"`       // port mappings are added, removed or changed
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ContainerPlan) Reset() {
+	*x = ContainerPlan{}
+	mi := &file_scheduler_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ContainerPlan) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ContainerPlan) ProtoMessage() {}
+
+func (x *ContainerPlan) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ContainerPlan.ProtoReflect.Descriptor instead.
+func (*ContainerPlan) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *ContainerPlan) GetAlias() string {
+	if x != nil {
+		return x.Alias
+	}
+	return ""
+}
+
+func (x *ContainerPlan) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ContainerPlan) GetAction() ContainerAction {
+	if x != nil {
+		return x.Action
+	}
+	return ContainerAction_CONTAINER_ACTION_UNSPECIFIED
+}
+
+func (x *ContainerPlan) GetChanges() []*FieldChange {
+	if x != nil {
+		return x.Changes
+	}
+	return nil
+}
+
+func (x *ContainerPlan) GetVolumesChanged() bool {
+	if x != nil {
+		return x.VolumesChanged
+	}
+	return false
+}
+
+func (x *ContainerPlan) GetPortsChanged() bool {
+	if x != nil {
+		return x.PortsChanged
+	}
+	return false
+}
+
+// Stack definition that expands into an environment specification from a
+// set of typed parameters. Templates are built into the scheduler or
+// registered from a Template manifest, in which case registering the same
+// name again adds a version.
+type Template struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	Parameters    []*TemplateParameter   `protobuf:"bytes,3,rep,name=parameters,proto3" json:"parameters,omitempty"`
+	Version       int32                  `protobuf:"varint,4,opt,name=version,proto3" json:"version,omitempty"` // numbered from 1, built-in templates only have version 1
+	Builtin       bool                   `protobuf:"varint,5,opt,name=builtin,proto3" json:"builtin,omitempty"`
+	Source        string                 `protobuf:"bytes,6,opt,name=source,proto3" json:"source,omitempty"` // manifest the template was registered from, empty for built-in templates
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Template) Reset() {
+	*x = Template{}
+	mi := &file_scheduler_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Template) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Template) ProtoMessage() {}
+
+func (x *Template) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Template.ProtoReflect.Descriptor instead.
+func (*Template) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *Template) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Template) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Template) GetParameters() []*TemplateParameter {
+	if x != nil {
+		return x.Parameters
+	}
+	return nil
+}
+
+func (x *Template) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Template) GetBuiltin() bool {
+	if x != nil {
+		return x.Builtin
+	}
+	return false
+}
+
+func (x *Template) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *Template) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+// Template and version an environment was created from
+type TemplateReference struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Version       int32                  `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TemplateReference) Reset() {
+	*x = TemplateReference{}
+	mi := &file_scheduler_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TemplateReference) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TemplateReference) ProtoMessage() {}
+
+func (x *TemplateReference) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TemplateReference.ProtoReflect.Descriptor instead.
+func (*TemplateReference) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *TemplateReference) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *TemplateReference) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type TemplateParameter struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	Type          ParameterType          `protobuf:"varint,3,opt,name=type,proto3,enum=scheduler.v1.ParameterType" json:"type,omitempty"`
+	DefaultValue  string                 `protobuf:"bytes,4,opt,name=default_value,json=defaultValue,proto3" json:"default_value,omitempty"` // used when the parameter is not given
+	Required      bool                   `protobuf:"varint,5,opt,name=required,proto3" json:"required,omitempty"`                            // the parameter has no default and must be given
+	Pattern       string                 `protobuf:"bytes,6,opt,name=pattern,proto3" json:"pattern,omitempty"`                               // regular expression the whole value must match
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TemplateParameter) Reset() {
+	*x = TemplateParameter{}
+	mi := &file_scheduler_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TemplateParameter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TemplateParameter) ProtoMessage() {}
+
+func (x *TemplateParameter) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TemplateParameter.ProtoReflect.Descriptor instead.
+func (*TemplateParameter) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *TemplateParameter) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *TemplateParameter) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *TemplateParameter) GetType() ParameterType {
+	if x != nil {
+		return x.Type
+	}
+	return ParameterType_PARAMETER_TYPE_UNSPECIFIED
+}
+
+func (x *TemplateParameter) GetDefaultValue() string {
+	if x != nil {
+		return x.DefaultValue
+	}
+	return ""
+}
+
+func (x *TemplateParameter) GetRequired() bool {
+	if x != nil {
+		return x.Required
+	}
+	return false
+}
+
+func (x *TemplateParameter) GetPattern() string {
+	if x != nil {
+		return x.Pattern
+	}
+	return ""
+}
+
+type ListTemplatesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTemplatesRequest) Reset() {
+	*x = ListTemplatesRequest{}
+	mi := &file_scheduler_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTemplatesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTemplatesRequest) ProtoMessage() {}
+
+func (x *ListTemplatesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTemplatesRequest.ProtoReflect.Descriptor instead.
+func (*ListTemplatesRequest) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{42}
+}
+
+type ListTemplatesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Templates     []*Template            `protobuf:"bytes,1,rep,name=templates,proto3" json:"templates,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTemplatesResponse) Reset() {
+	*x = ListTemplatesResponse{}
+	mi := &file_scheduler_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTemplatesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTemplatesResponse) ProtoMessage() {}
+
+func (x *ListTemplatesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTemplatesResponse.ProtoReflect.Descriptor instead.
+func (*ListTemplatesResponse) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *ListTemplatesResponse) GetTemplates() []*Template {
+	if x != nil {
+		return x.Templates
+	}
+	return nil
+}
+
+type GetTemplateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Version       int32                  `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"` // 0 for the latest version
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTemplateRequest) Reset() {
+	*x = GetTemplateRequest{}
+	mi := &file_scheduler_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTemplateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTemplateRequest) ProtoMessage() {}
+
+func (x *GetTemplateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTemplateRequest.ProtoReflect.Descriptor instead.
+func (*GetTemplateRequest) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *GetTemplateRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *GetTemplateRequest) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type GetTemplateResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Template      *Template              `protobuf:"bytes,1,opt,name=template,proto3" json:"template,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTemplateResponse) Reset() {
+	*x = GetTemplateResponse{}
+	mi := &file_scheduler_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTemplateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTemplateResponse) ProtoMessage() {}
+
+func (x *GetTemplateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTemplateResponse.ProtoReflect.Descriptor instead.
+func (*GetTemplateResponse) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *GetTemplateResponse) GetTemplate() *Template {
+	if x != nil {
+		return x.Template
+	}
+	return nil
+}
+
+type CreateEnvironmentFromTemplateRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Template        string                 `protobuf:"bytes,1,opt,name=template,proto3" json:"template,omitempty"`
+	Name            string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`                                                                                       // name of the environment
+	Parameters      map[string]string      `protobuf:"bytes,3,rep,name=parameters,proto3" json:"parameters,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // may hold passwords, which can be secret://<name> references
+	Tenant          string                 `protobuf:"bytes,4,opt,name=tenant,proto3" json:"tenant,omitempty"`                                                                                   // defaults to the caller's tenant
+	Labels          map[string]string      `protobuf:"bytes,5,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`         // added to the labels of the template
+	TemplateVersion int32                  `protobuf:"varint,6,opt,name=template_version,json=templateVersion,proto3" json:"template_version,omitempty"`                                         // 0 for the latest version
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *CreateEnvironmentFromTemplateRequest) Reset() {
+	*x = CreateEnvironmentFromTemplateRequest{}
+	mi := &file_scheduler_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateEnvironmentFromTemplateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateEnvironmentFromTemplateRequest) ProtoMessage() {}
+
+func (x *CreateEnvironmentFromTemplateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateEnvironmentFromTemplateRequest.ProtoReflect.Descriptor instead.
+func (*CreateEnvironmentFromTemplateRequest) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *CreateEnvironmentFromTemplateRequest) GetTemplate() string {
+	if x != nil {
+		return x.Template
+	}
+	return ""
+}
+
+func (x *CreateEnvironmentFromTemplateRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateEnvironmentFromTemplateRequest) GetParameters() map[string]string {
+	if x != nil {
+		return x.Parameters
+	}
+	return nil
+}
+
+func (x *CreateEnvironmentFromTemplateRequest) GetTenant() string {
+	if x != nil {
+		return x.Tenant
+	}
+	return ""
+}
+
+func (x *CreateEnvironmentFromTemplateRequest) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *CreateEnvironmentFromTemplateRequest) GetTemplateVersion() int32 {
+	if x != nil {
+		return x.TemplateVersion
+	}
+	return 0
+}
+
+type CreateEnvironmentFromTemplateResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Environment   *Environment           `protobuf:"bytes,1,opt,name=environment,proto3" json:"environment,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateEnvironmentFromTemplateResponse) Reset() {
+	*x = CreateEnvironmentFromTemplateResponse{}
+	mi := &file_scheduler_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateEnvironmentFromTemplateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateEnvironmentFromTemplateResponse) ProtoMessage() {}
+
+func (x *CreateEnvironmentFromTemplateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateEnvironmentFromTemplateResponse.ProtoReflect.Descriptor instead.
+func (*CreateEnvironmentFromTemplateResponse) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *CreateEnvironmentFromTemplateResponse) GetEnvironment() *Environment {
+	if x != nil {
+		return x.Environment
+	}
+	return nil
+}
+
+type RegisterTemplateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Manifest      string                 `protobuf:"bytes,1,opt,name=manifest,proto3" json:"manifest,omitempty"` // YAML or JSON Template manifest
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterTemplateRequest) Reset() {
+	*x = RegisterTemplateRequest{}
+	mi := &file_scheduler_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterTemplateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterTemplateRequest) ProtoMessage() {}
+
+func (x *RegisterTemplateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterTemplateRequest.ProtoReflect.Descriptor instead.
+func (*RegisterTemplateRequest) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *RegisterTemplateRequest) GetManifest() string {
+	if x != nil {
+		return x.Manifest
+	}
+	return ""
+}
+
+type RegisterTemplateResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Template      *Template              `protobuf:"bytes,1,opt,name=template,proto3" json:"template,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterTemplateResponse) Reset() {
+	*x = RegisterTemplateResponse{}
+	mi := &file_scheduler_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterTemplateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterTemplateResponse) ProtoMessage() {}
+
+func (x *RegisterTemplateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterTemplateResponse.ProtoReflect.Descriptor instead.
+func (*RegisterTemplateResponse) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *RegisterTemplateResponse) GetTemplate() *Template {
+	if x != nil {
+		return x.Template
+	}
+	return nil
+}
+
+type DeleteTemplateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"` // every version of the template is deleted
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteTemplateRequest) Reset() {
+	*x = DeleteTemplateRequest{}
+	mi := &file_scheduler_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteTemplateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteTemplateRequest) ProtoMessage() {}
+
+func (x *DeleteTemplateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteTemplateRequest.ProtoReflect.Descriptor instead.
+func (*DeleteTemplateRequest) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *DeleteTemplateRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type DeleteTemplateResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteTemplateResponse) Reset() {
+	*x = DeleteTemplateResponse{}
+	mi := &file_scheduler_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteTemplateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteTemplateResponse) ProtoMessage() {}
+
+func (x *DeleteTemplateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteTemplateResponse.ProtoReflect.Descriptor instead.
+func (*DeleteTemplateResponse) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{51}
+}
+
+// Immutable record of a specification applied to an environment
+type EnvironmentRevision struct {
+	state         protoimpl.MessageState    `protogen:"open.v1"`
+	EnvironmentId string                    `protobuf:"bytes,1,opt,name=environment_id,json=environmentId,proto3" json:"environment_id,omitempty"`
+	Number        int32                     `protobuf:"varint,2,opt,name=number,proto3" json:"number,omitempty"` // numbered from 1 in the order revisions were applied
+	Spec          *EnvironmentSpecification `protobuf:"bytes,3,opt,name=spec,proto3" json:"spec,omitempty"`
+	CreatedAt     *timestamppb.Timestamp    `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	Author        string                    `protobuf:"bytes,5,opt,name=author,proto3" json:"author,omitempty"`                            // authenticated caller, empty when authentication is disabled
+	RollbackOf    int32                     `protobuf:"varint,6,opt,name=rollback_of,json=rollbackOf,proto3" json:"rollback_of,omitempty"` // revision this one restored, 0 unless created by a rollback
+	// why redeploying this revision to the running environment failed, empty
+	// when it succeeded or the environment was not running
+	DeployError   string `protobuf:"bytes,7,opt,name=deploy_error,json=deployError,proto3" json:"deploy_error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EnvironmentRevision) Reset() {
+	*x = EnvironmentRevision{}
+	mi := &file_scheduler_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EnvironmentRevision) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnvironmentRevision) ProtoMessage() {}
+
+func (x *EnvironmentRevision) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnvironmentRevision.ProtoReflect.Descriptor instead.
+func (*EnvironmentRevision) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *EnvironmentRevision) GetEnvironmentId() string {
+	if x != nil {
+		return x.EnvironmentId
+	}
+	return ""
+}
+
+func (x *EnvironmentRevision) GetNumber() int32 {
+	if x != nil {
+		return x.Number
+	}
+	return 0
+}
+
+func (x *EnvironmentRevision) GetSpec() *EnvironmentSpecification {
+	if x != nil {
+		return x.Spec
+	}
+	return nil
+}
+
+func (x *EnvironmentRevision) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *EnvironmentRevision) GetAuthor() string {
+	if x != nil {
+		return x.Author
+	}
+	return ""
+}
+
+func (x *EnvironmentRevision) GetRollbackOf() int32 {
+	if x != nil {
+		return x.RollbackOf
+	}
+	return 0
+}
+
+func (x *EnvironmentRevision) GetDeployError() string {
+	if x != nil {
+		return x.DeployError
+	}
+	return ""
+}
+
+type ListEnvironmentRevisionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EnvironmentId string                 `protobuf:"bytes,1,opt,name=environment_id,json=environmentId,proto3" json:"environment_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListEnvironmentRevisionsRequest) Reset() {
+	*x = ListEnvironmentRevisionsRequest{}
+	mi := &file_scheduler_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListEnvironmentRevisionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListEnvironmentRevisionsRequest) ProtoMessage() {}
+
+func (x *ListEnvironmentRevisionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListEnvironmentRevisionsRequest.ProtoReflect.Descriptor instead.
+func (*ListEnvironmentRevisionsRequest) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *ListEnvironmentRevisionsRequest) GetEnvironmentId() string {
+	if x != nil {
+		return x.EnvironmentId
+	}
+	return ""
+}
+
+type ListEnvironmentRevisionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Revisions     []*EnvironmentRevision `protobuf:"bytes,1,rep,name=revisions,proto3" json:"revisions,omitempty"` // newest first
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListEnvironmentRevisionsResponse) Reset() {
+	*x = ListEnvironmentRevisionsResponse{}
+	mi := &file_scheduler_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListEnvironmentRevisionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListEnvironmentRevisionsResponse) ProtoMessage() {}
+
+func (x *ListEnvironmentRevisionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListEnvironmentRevisionsResponse.ProtoReflect.Descriptor instead.
+func (*ListEnvironmentRevisionsResponse) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *ListEnvironmentRevisionsResponse) GetRevisions() []*EnvironmentRevision {
+	if x != nil {
+		return x.Revisions
+	}
+	return nil
+}
+
+type GetEnvironmentRevisionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EnvironmentId string                 `protobuf:"bytes,1,opt,name=environment_id,json=environmentId,proto3" json:"environment_id,omitempty"`
+	Number        int32                  `protobuf:"varint,2,opt,name=number,proto3" json:"number,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetEnvironmentRevisionRequest) Reset() {
+	*x = GetEnvironmentRevisionRequest{}
+	mi := &file_scheduler_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetEnvironmentRevisionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetEnvironmentRevisionRequest) ProtoMessage() {}
+
+func (x *GetEnvironmentRevisionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetEnvironmentRevisionRequest.ProtoReflect.Descriptor instead.
+func (*GetEnvironmentRevisionRequest) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *GetEnvironmentRevisionRequest) GetEnvironmentId() string {
+	if x != nil {
+		return x.EnvironmentId
+	}
+	return ""
+}
+
+func (x *GetEnvironmentRevisionRequest) GetNumber() int32 {
+	if x != nil {
+		return x.Number
+	}
+	return 0
+}
+
+type GetEnvironmentRevisionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Revision      *EnvironmentRevision   `protobuf:"bytes,1,opt,name=revision,proto3" json:"revision,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetEnvironmentRevisionResponse) Reset() {
+	*x = GetEnvironmentRevisionResponse{}
+	mi := &file_scheduler_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetEnvironmentRevisionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetEnvironmentRevisionResponse) ProtoMessage() {}
+
+func (x *GetEnvironmentRevisionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetEnvironmentRevisionResponse.ProtoReflect.Descriptor instead.
+func (*GetEnvironmentRevisionResponse) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *GetEnvironmentRevisionResponse) GetRevision() *EnvironmentRevision {
+	if x != nil {
+		return x.Revision
+	}
+	return nil
+}
+
+type RollbackEnvironmentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Revision      int32                  `protobuf:"varint,2,opt,name=revision,proto3" json:"revision,omitempty"` // revision whose spec is applied again as a new revision
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RollbackEnvironmentRequest) Reset() {
+	*x = RollbackEnvironmentRequest{}
+	mi := &file_scheduler_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RollbackEnvironmentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RollbackEnvironmentRequest) ProtoMessage() {}
+
+func (x *RollbackEnvironmentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RollbackEnvironmentRequest.ProtoReflect.Descriptor instead.
+func (*RollbackEnvironmentRequest) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *RollbackEnvironmentRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *RollbackEnvironmentRequest) GetRevision() int32 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+type RollbackEnvironmentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Environment   *Environment           `protobuf:"bytes,1,opt,name=environment,proto3" json:"environment,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RollbackEnvironmentResponse) Reset() {
+	*x = RollbackEnvironmentResponse{}
+	mi := &file_scheduler_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RollbackEnvironmentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RollbackEnvironmentResponse) ProtoMessage() {}
+
+func (x *RollbackEnvironmentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[58]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RollbackEnvironmentResponse.ProtoReflect.Descriptor instead.
+func (*RollbackEnvironmentResponse) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *RollbackEnvironmentResponse) GetEnvironment() *Environment {
+	if x != nil {
+		return x.Environment
+	}
+	return nil
+}
+
+type StartEnvironmentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StartEnvironmentRequest) Reset() {
+	*x = StartEnvironmentRequest{}
+	mi := &file_scheduler_proto_msgTypes[59]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StartEnvironmentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartEnvironmentRequest) ProtoMessage() {}
+
+func (x *StartEnvironmentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[59]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartEnvironmentRequest.ProtoReflect.Descriptor instead.
+func (*StartEnvironmentRequest) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *StartEnvironmentRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type StartEnvironmentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Environment   *Environment           `protobuf:"bytes,1,opt,name=environment,proto3" json:"environment,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StartEnvironmentResponse) Reset() {
+	*x = StartEnvironmentResponse{}
+	mi := &file_scheduler_proto_msgTypes[60]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StartEnvironmentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartEnvironmentResponse) ProtoMessage() {}
+
+func (x *StartEnvironmentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[60]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartEnvironmentResponse.ProtoReflect.Descriptor instead.
+func (*StartEnvironmentResponse) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{60}
+}
+
+func (x *StartEnvironmentResponse) GetEnvironment() *Environment {
+	if x != nil {
+		return x.Environment
+	}
+	return nil
+}
+
+type StopEnvironmentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Force         bool                   `protobuf:"varint,2,opt,name=force,proto3" json:"force,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StopEnvironmentRequest) Reset() {
+	*x = StopEnvironmentRequest{}
+	mi := &file_scheduler_proto_msgTypes[61]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StopEnvironmentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StopEnvironmentRequest) ProtoMessage() {}
+
+func (x *StopEnvironmentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[61]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StopEnvironmentRequest.ProtoReflect.Descriptor instead.
+func (*StopEnvironmentRequest) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{61}
+}
+
+func (x *StopEnvironmentRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *StopEnvironmentRequest) GetForce() bool {
+	if x != nil {
+		return x.Force
+	}
+	return false
+}
+
+type StopEnvironmentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Environment   *Environment           `protobuf:"bytes,1,opt,name=environment,proto3" json:"environment,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StopEnvironmentResponse) Reset() {
+	*x = StopEnvironmentResponse{}
+	mi := &file_scheduler_proto_msgTypes[62]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StopEnvironmentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StopEnvironmentResponse) ProtoMessage() {}
+
+func (x *StopEnvironmentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[62]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StopEnvironmentResponse.ProtoReflect.Descriptor instead.
+func (*StopEnvironmentResponse) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{62}
+}
+
+func (x *StopEnvironmentResponse) GetEnvironment() *Environment {
+	if x != nil {
+		return x.Environment
+	}
+	return nil
+}
+
+type RestartEnvironmentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestartEnvironmentRequest) Reset() {
+	*x = RestartEnvironmentRequest{}
+	mi := &file_scheduler_proto_msgTypes[63]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestartEnvironmentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestartEnvironmentRequest) ProtoMessage() {}
+
+func (x *RestartEnvironmentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[63]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestartEnvironmentRequest.ProtoReflect.Descriptor instead.
+func (*RestartEnvironmentRequest) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{63}
+}
+
+func (x *RestartEnvironmentRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type RestartEnvironmentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Environment   *Environment           `protobuf:"bytes,1,opt,name=environment,proto3" json:"environment,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestartEnvironmentResponse) Reset() {
+	*x = RestartEnvironmentResponse{}
+	mi := &file_scheduler_proto_msgTypes[64]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestartEnvironmentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestartEnvironmentResponse) ProtoMessage() {}
+
+func (x *RestartEnvironmentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[64]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestartEnvironmentResponse.ProtoReflect.Descriptor instead.
+func (*RestartEnvironmentResponse) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{64}
+}
+
+func (x *RestartEnvironmentResponse) GetEnvironment() *Environment {
+	if x != nil {
+		return x.Environment
+	}
+	return nil
+}
+
+type GetEnvironmentStatusRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Id              string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	RevealSensitive bool                   `protobuf:"varint,2,opt,name=reveal_sensitive,json=revealSensitive,proto3" json:"reveal_sensitive,omitempty"` // return step and hook output unmasked, if permitted
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *GetEnvironmentStatusRequest) Reset() {
+	*x = GetEnvironmentStatusRequest{}
+	mi := &file_scheduler_proto_msgTypes[65]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetEnvironmentStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetEnvironmentStatusRequest) ProtoMessage() {}
+
+func (x *GetEnvironmentStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[65]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetEnvironmentStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetEnvironmentStatusRequest) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{65}
+}
+
+func (x *GetEnvironmentStatusRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *GetEnvironmentStatusRequest) GetRevealSensitive() bool {
+	if x != nil {
+		return x.RevealSensitive
+	}
+	return false
+}
+
+type GetEnvironmentStatusResponse struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Environment      *Environment           `protobuf:"bytes,1,opt,name=environment,proto3" json:"environment,omitempty"`
+	ContainerMetrics []*ContainerMetrics    `protobuf:"bytes,2,rep,name=container_metrics,json=containerMetrics,proto3" json:"container_metrics,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *GetEnvironmentStatusResponse) Reset() {
+	*x = GetEnvironmentStatusResponse{}
+	mi := &file_scheduler_proto_msgTypes[66]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetEnvironmentStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetEnvironmentStatusResponse) ProtoMessage() {}
+
+func (x *GetEnvironmentStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[66]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetEnvironmentStatusResponse.ProtoReflect.Descriptor instead.
+func (*GetEnvironmentStatusResponse) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{66}
+}
+
+func (x *GetEnvironmentStatusResponse) GetEnvironment() *Environment {
+	if x != nil {
+		return x.Environment
+	}
+	return nil
+}
+
+func (x *GetEnvironmentStatusResponse) GetContainerMetrics() []*ContainerMetrics {
+	if x != nil {
+		return x.ContainerMetrics
+	}
+	return nil
+}
+
+type ContainerMetrics struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	ContainerId      string                 `protobuf:"bytes,1,opt,name=container_id,json=containerId,proto3" json:"container_id,omitempty"`
+	CpuUsagePercent  float64                `protobuf:"fixed64,2,opt,name=cpu_usage_percent,json=cpuUsagePercent,proto3" json:"cpu_usage_percent,omitempty"`
+	MemoryUsageBytes int64                  `protobuf:"varint,3,opt,name=memory_usage_bytes,json=memoryUsageBytes,proto3" json:"memory_usage_bytes,omitempty"`
+	MemoryLimitBytes int64                  `protobuf:"varint,4,opt,name=memory_limit_bytes,json=memoryLimitBytes,proto3" json:"memory_limit_bytes,omitempty"`
+	DiskUsageBytes   int64                  `protobuf:"varint,5,opt,name=disk_usage_bytes,json=diskUsageBytes,proto3" json:"disk_usage_bytes,omitempty"`
+	NetworkRxBytes   int64                  `protobuf:"varint,6,opt,name=network_rx_bytes,json=networkRxBytes,proto3" json:"network_rx_bytes,omitempty"`
+	NetworkTxBytes   int64                  `protobuf:"varint,7,opt,name=network_tx_bytes,json=networkTxBytes,proto3" json:"network_tx_bytes,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *ContainerMetrics) Reset() {
+	*x = ContainerMetrics{}
+	mi := &file_scheduler_proto_msgTypes[67]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ContainerMetrics) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ContainerMetrics) ProtoMessage() {}
+
+func (x *ContainerMetrics) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[67]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ContainerMetrics.ProtoReflect.Descriptor instead.
+func (*ContainerMetrics) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{67}
+}
+
+func (x *ContainerMetrics) GetContainerId() string {
+	if x != nil {
+		return x.ContainerId
+	}
+	return ""
+}
+
+func (x *ContainerMetrics) GetCpuUsagePercent() float64 {
+	if x != nil {
+		return x.CpuUsagePercent
+	}
+	return 0
+}
+
+func (x *ContainerMetrics) GetMemoryUsageBytes() int64 {
+	if x != nil {
+		return x.MemoryUsageBytes
+	}
+	return 0
+}
+
+func (x *ContainerMetrics) GetMemoryLimitBytes() int64 {
+	if x != nil {
+		return x.MemoryLimitBytes
+	}
+	return 0
+}
+
+func (x *ContainerMetrics) GetDiskUsageBytes() int64 {
+	if x != nil {
+		return x.DiskUsageBytes
+	}
+	return 0
+}
+
+func (x *ContainerMetrics) GetNetworkRxBytes() int64 {
+	if x != nil {
+		return x.NetworkRxBytes
+	}
+	return 0
+}
+
+func (x *ContainerMetrics) GetNetworkTxBytes() int64 {
+	if x != nil {
+		return x.NetworkTxBytes
+	}
+	return 0
+}
+
+type GetEnvironmentLogsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ContainerName string                 `protobuf:"bytes,2,opt,name=container_name,json=containerName,proto3" json:"container_name,omitempty"` // optional, if empty returns logs for all containers
+	Since         *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=since,proto3" json:"since,omitempty"`
+	TailLines     int32                  `protobuf:"varint,4,opt,name=tail_lines,json=tailLines,proto3" json:"tail_lines,omitempty"`
+	Follow        bool                   `protobuf:"varint,5,opt,name=follow,proto3" json:"follow,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetEnvironmentLogsRequest) Reset() {
+	*x = GetEnvironmentLogsRequest{}
+	mi := &file_scheduler_proto_msgTypes[68]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetEnvironmentLogsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetEnvironmentLogsRequest) ProtoMessage() {}
+
+func (x *GetEnvironmentLogsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[68]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetEnvironmentLogsRequest.ProtoReflect.Descriptor instead.
+func (*GetEnvironmentLogsRequest) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{68}
+}
+
+func (x *GetEnvironmentLogsRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *GetEnvironmentLogsRequest) GetContainerName() string {
+	if x != nil {
+		return x.ContainerName
+	}
+	return ""
+}
+
+func (x *GetEnvironmentLogsRequest) GetSince() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Since
+	}
+	return nil
+}
+
+func (x *GetEnvironmentLogsRequest) GetTailLines() int32 {
+	if x != nil {
+		return x.TailLines
+	}
+	return 0
+}
+
+func (x *GetEnvironmentLogsRequest) GetFollow() bool {
+	if x != nil {
+		return x.Follow
+	}
+	return false
+}
+
+type GetEnvironmentLogsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ContainerName string                 `protobuf:"bytes,1,opt,name=container_name,json=containerName,proto3" json:"container_name,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Level         string                 `protobuf:"bytes,4,opt,name=level,proto3" json:"level,omitempty"` // info, warn, error, debug
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetEnvironmentLogsResponse) Reset() {
+	*x = GetEnvironmentLogsResponse{}
+	mi := &file_scheduler_proto_msgTypes[69]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetEnvironmentLogsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetEnvironmentLogsResponse) ProtoMessage() {}
+
+func (x *GetEnvironmentLogsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[69]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetEnvironmentLogsResponse.ProtoReflect.Descriptor instead.
+func (*GetEnvironmentLogsResponse) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{69}
+}
+
+func (x *GetEnvironmentLogsResponse) GetContainerName() string {
+	if x != nil {
+		return x.ContainerName
+	}
+	return ""
+}
+
+func (x *GetEnvironmentLogsResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *GetEnvironmentLogsResponse) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *GetEnvironmentLogsResponse) GetLevel() string {
+	if x != nil {
+		return x.Level
+	}
+	return ""
+}
+
+// Named volume whose lifecycle is independent of environments. Only
+// environments of the owning tenant may mount it.
+type Volume struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Name           string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Labels         map[string]string      `protobuf:"bytes,2,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Path           string                 `protobuf:"bytes,3,opt,name=path,proto3" json:"path,omitempty"` // host directory holding the volume data
+	SizeBytes      int64                  `protobuf:"varint,4,opt,name=size_bytes,json=sizeBytes,proto3" json:"size_bytes,omitempty"`
+	UsedBy         []string               `protobuf:"bytes,5,rep,name=used_by,json=usedBy,proto3" json:"used_by,omitempty"` // IDs of environments mounting the volume
+	CreatedAt      *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	SnapshotPolicy *SnapshotPolicy        `protobuf:"bytes,7,opt,name=snapshot_policy,json=snapshotPolicy,proto3" json:"snapshot_policy,omitempty"`
+	Tenant         string                 `protobuf:"bytes,8,opt,name=tenant,proto3" json:"tenant,omitempty"` // tenant owning the volume
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Volume) Reset() {
+	*x = Volume{}
+	mi := &file_scheduler_proto_msgTypes[70]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Volume) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Volume) ProtoMessage() {}
+
+func (x *Volume) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[70]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Volume.ProtoReflect.Descriptor instead.
+func (*Volume) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{70}
+}
+
+func (x *Volume) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Volume) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *Volume) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *Volume) GetSizeBytes() int64 {
+	if x != nil {
+		return x.SizeBytes
+	}
+	return 0
+}
+
+func (x *Volume) GetUsedBy() []string {
+	if x != nil {
+		return x.UsedBy
+	}
+	return nil
+}
+
+func (x *Volume) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Volume) GetSnapshotPolicy() *SnapshotPolicy {
+	if x != nil {
+		return x.SnapshotPolicy
+	}
+	return nil
+}
+
+func (x *Volume) GetTenant() string {
+	if x != nil {
+		return x.Tenant
+	}
+	return ""
+}
+
+// Schedule for taking volume snapshots automatically
+type SnapshotPolicy struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	IntervalSeconds int32                  `protobuf:"varint,1,opt,name=interval_seconds,json=intervalSeconds,proto3" json:"interval_seconds,omitempty"` // 0 disables scheduled snapshots
+	Retain          int32                  `protobuf:"varint,2,opt,name=retain,proto3" json:"retain,omitempty"`                                          // number of scheduled snapshots to keep, 0 keeps all
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *SnapshotPolicy) Reset() {
+	*x = SnapshotPolicy{}
+	mi := &file_scheduler_proto_msgTypes[71]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SnapshotPolicy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SnapshotPolicy) ProtoMessage() {}
+
+func (x *SnapshotPolicy) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[71]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SnapshotPolicy.ProtoReflect.Descriptor instead.
+func (*SnapshotPolicy) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{71}
+}
+
+func (x *SnapshotPolicy) GetIntervalSeconds() int32 {
+	if x != nil {
+		return x.IntervalSeconds
+	}
+	return 0
+}
+
+func (x *SnapshotPolicy) GetRetain() int32 {
+	if x != nil {
+		return x.Retain
+	}
+	return 0
+}
+
+type CreateVolumeRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Name           string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Labels         map[string]string      `protobuf:"bytes,2,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	SnapshotPolicy *SnapshotPolicy        `protobuf:"bytes,3,opt,name=snapshot_policy,json=snapshotPolicy,proto3" json:"snapshot_policy,omitempty"`
+	Tenant         string                 `protobuf:"bytes,4,opt,name=tenant,proto3" json:"tenant,omitempty"` // defaults to the caller's tenant, or "default"
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CreateVolumeRequest) Reset() {
+	*x = CreateVolumeRequest{}
+	mi := &file_scheduler_proto_msgTypes[72]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateVolumeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateVolumeRequest) ProtoMessage() {}
+
+func (x *CreateVolumeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[72]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateVolumeRequest.ProtoReflect.Descriptor instead.
+func (*CreateVolumeRequest) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{72}
+}
+
+func (x *CreateVolumeRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateVolumeRequest) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *CreateVolumeRequest) GetSnapshotPolicy() *SnapshotPolicy {
+	if x != nil {
+		return x.SnapshotPolicy
+	}
+	return nil
+}
+
+func (x *CreateVolumeRequest) GetTenant() string {
+	if x != nil {
+		return x.Tenant
+	}
+	return ""
+}
+
+type CreateVolumeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Volume        *Volume                `protobuf:"bytes,1,opt,name=volume,proto3" json:"volume,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateVolumeResponse) Reset() {
+	*x = CreateVolumeResponse{}
+	mi := &file_scheduler_proto_msgTypes[73]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateVolumeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateVolumeResponse) ProtoMessage() {}
+
+func (x *CreateVolumeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[73]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateVolumeResponse.ProtoReflect.Descriptor instead.
+func (*CreateVolumeResponse) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{73}
+}
+
+func (x *CreateVolumeResponse) GetVolume() *Volume {
+	if x != nil {
+		return x.Volume
+	}
+	return nil
+}
+
+type GetVolumeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetVolumeRequest) Reset() {
+	*x = GetVolumeRequest{}
+	mi := &file_scheduler_proto_msgTypes[74]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetVolumeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetVolumeRequest) ProtoMessage() {}
+
+func (x *GetVolumeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[74]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetVolumeRequest.ProtoReflect.Descriptor instead.
+func (*GetVolumeRequest) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{74}
+}
+
+func (x *GetVolumeRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type GetVolumeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Volume        *Volume                `protobuf:"bytes,1,opt,name=volume,proto3" json:"volume,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetVolumeResponse) Reset() {
+	*x = GetVolumeResponse{}
+	mi := &file_scheduler_proto_msgTypes[75]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetVolumeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetVolumeResponse) ProtoMessage() {}
+
+func (x *GetVolumeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[75]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetVolumeResponse.ProtoReflect.Descriptor instead.
+func (*GetVolumeResponse) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{75}
+}
+
+func (x *GetVolumeResponse) GetVolume() *Volume {
+	if x != nil {
+		return x.Volume
+	}
+	return nil
+}
+
+type ListVolumesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Filters       map[string]string      `protobuf:"bytes,1,rep,name=filters,proto3" json:"filters,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Tenant        string                 `protobuf:"bytes,2,opt,name=tenant,proto3" json:"tenant,omitempty"` // callers bound to a tenant only ever see their own
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListVolumesRequest) Reset() {
+	*x = ListVolumesRequest{}
+	mi := &file_scheduler_proto_msgTypes[76]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListVolumesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListVolumesRequest) ProtoMessage() {}
+
+func (x *ListVolumesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[76]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListVolumesRequest.ProtoReflect.Descriptor instead.
+func (*ListVolumesRequest) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{76}
+}
+
+func (x *ListVolumesRequest) GetFilters() map[string]string {
+	if x != nil {
+		return x.Filters
+	}
+	return nil
+}
+
+func (x *ListVolumesRequest) GetTenant() string {
+	if x != nil {
+		return x.Tenant
+	}
+	return ""
+}
+
+type ListVolumesResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Volumes        []*Volume              `protobuf:"bytes,1,rep,name=volumes,proto3" json:"volumes,omitempty"`
+	TotalSizeBytes int64                  `protobuf:"varint,2,opt,name=total_size_bytes,json=totalSizeBytes,proto3" json:"total_size_bytes,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ListVolumesResponse) Reset() {
+	*x = ListVolumesResponse{}
+	mi := &file_scheduler_proto_msgTypes[77]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListVolumesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListVolumesResponse) ProtoMessage() {}
+
+func (x *ListVolumesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[77]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListVolumesResponse.ProtoReflect.Descriptor instead.
+func (*ListVolumesResponse) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{77}
+}
+
+func (x *ListVolumesResponse) GetVolumes() []*Volume {
+	if x != nil {
+		return x.Volumes
+	}
+	return nil
+}
+
+func (x *ListVolumesResponse) GetTotalSizeBytes() int64 {
+	if x != nil {
+		return x.TotalSizeBytes
+	}
+	return 0
+}
+
+type UpdateVolumeRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Name           string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Labels         map[string]string      `protobuf:"bytes,2,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	SnapshotPolicy *SnapshotPolicy        `protobuf:"bytes,3,opt,name=snapshot_policy,json=snapshotPolicy,proto3" json:"snapshot_policy,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *UpdateVolumeRequest) Reset() {
+	*x = UpdateVolumeRequest{}
+	mi := &file_scheduler_proto_msgTypes[78]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateVolumeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateVolumeRequest) ProtoMessage() {}
+
+func (x *UpdateVolumeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[78]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateVolumeRequest.ProtoReflect.Descriptor instead.
+func (*UpdateVolumeRequest) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{78}
+}
+
+func (x *UpdateVolumeRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *UpdateVolumeRequest) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *UpdateVolumeRequest) GetSnapshotPolicy() *SnapshotPolicy {
+	if x != nil {
+		return x.SnapshotPolicy
+	}
+	return nil
+}
+
+type UpdateVolumeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Volume        *Volume                `protobuf:"bytes,1,opt,name=volume,proto3" json:"volume,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateVolumeResponse) Reset() {
+	*x = UpdateVolumeResponse{}
+	mi := &file_scheduler_proto_msgTypes[79]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateVolumeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateVolumeResponse) ProtoMessage() {}
+
+func (x *UpdateVolumeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[79]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateVolumeResponse.ProtoReflect.Descriptor instead.
+func (*UpdateVolumeResponse) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{79}
+}
+
+func (x *UpdateVolumeResponse) GetVolume() *Volume {
+	if x != nil {
+		return x.Volume
+	}
+	return nil
+}
+
+type DeleteVolumeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteVolumeRequest) Reset() {
+	*x = DeleteVolumeRequest{}
+	mi := &file_scheduler_proto_msgTypes[80]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteVolumeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteVolumeRequest) ProtoMessage() {}
+
+func (x *DeleteVolumeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[80]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteVolumeRequest.ProtoReflect.Descriptor instead.
+func (*DeleteVolumeRequest) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{80}
+}
+
+func (x *DeleteVolumeRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type DeleteVolumeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteVolumeResponse) Reset() {
+	*x = DeleteVolumeResponse{}
+	mi := &file_scheduler_proto_msgTypes[81]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteVolumeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteVolumeResponse) ProtoMessage() {}
+
+func (x *DeleteVolumeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[81]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteVolumeResponse.ProtoReflect.Descriptor instead.
+func (*DeleteVolumeResponse) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{81}
+}
+
+func (x *DeleteVolumeResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+// Point-in-time copy of a volume's data
+type VolumeSnapshot struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	VolumeName    string                 `protobuf:"bytes,2,opt,name=volume_name,json=volumeName,proto3" json:"volume_name,omitempty"`
+	Method        SnapshotMethod         `protobuf:"varint,3,opt,name=method,proto3,enum=scheduler.v1.SnapshotMethod" json:"method,omitempty"`
+	SizeBytes     int64                  `protobuf:"varint,4,opt,name=size_bytes,json=sizeBytes,proto3" json:"size_bytes,omitempty"`       // size of the volume data captured
+	StoredBytes   int64                  `protobuf:"varint,5,opt,name=stored_bytes,json=storedBytes,proto3" json:"stored_bytes,omitempty"` // space the snapshot occupies, compressed for tarballs
+	Labels        map[string]string      `protobuf:"bytes,6,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Scheduled     bool                   `protobuf:"varint,7,opt,name=scheduled,proto3" json:"scheduled,omitempty"` // taken by the volume's snapshot policy
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VolumeSnapshot) Reset() {
+	*x = VolumeSnapshot{}
+	mi := &file_scheduler_proto_msgTypes[82]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VolumeSnapshot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VolumeSnapshot) ProtoMessage() {}
+
+func (x *VolumeSnapshot) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[82]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VolumeSnapshot.ProtoReflect.Descriptor instead.
+func (*VolumeSnapshot) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{82}
+}
+
+func (x *VolumeSnapshot) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *VolumeSnapshot) GetVolumeName() string {
+	if x != nil {
+		return x.VolumeName
+	}
+	return ""
+}
+
+func (x *VolumeSnapshot) GetMethod() SnapshotMethod {
+	if x != nil {
+		return x.Method
+	}
+	return SnapshotMethod_SNAPSHOT_METHOD_UNSPECIFIED
+}
+
+func (x *VolumeSnapshot) GetSizeBytes() int64 {
+	if x != nil {
+		return x.SizeBytes
+	}
+	return 0
+}
+
+func (x *VolumeSnapshot) GetStoredBytes() int64 {
+	if x != nil {
+		return x.StoredBytes
+	}
+	return 0
+}
+
+func (x *VolumeSnapshot) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *VolumeSnapshot) GetScheduled() bool {
+	if x != nil {
+		return x.Scheduled
+	}
+	return false
+}
+
+func (x *VolumeSnapshot) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type SnapshotVolumeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	VolumeName    string                 `protobuf:"bytes,1,opt,name=volume_name,json=volumeName,proto3" json:"volume_name,omitempty"`
+	Method        SnapshotMethod         `protobuf:"varint,2,opt,name=method,proto3,enum=scheduler.v1.SnapshotMethod" json:"method,omitempty"`
+	Labels        map[string]string      `protobuf:"bytes,3,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SnapshotVolumeRequest) Reset() {
+	*x = SnapshotVolumeRequest{}
+	mi := &file_scheduler_proto_msgTypes[83]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SnapshotVolumeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SnapshotVolumeRequest) ProtoMessage() {}
+
+func (x *SnapshotVolumeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[83]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SnapshotVolumeRequest.ProtoReflect.Descriptor instead.
+func (*SnapshotVolumeRequest) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{83}
+}
+
+func (x *SnapshotVolumeRequest) GetVolumeName() string {
+	if x != nil {
+		return x.VolumeName
+	}
+	return ""
+}
+
+func (x *SnapshotVolumeRequest) GetMethod() SnapshotMethod {
+	if x != nil {
+		return x.Method
+	}
+	return SnapshotMethod_SNAPSHOT_METHOD_UNSPECIFIED
+}
+
+func (x *SnapshotVolumeRequest) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+type SnapshotVolumeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Snapshot      *VolumeSnapshot        `protobuf:"bytes,1,opt,name=snapshot,proto3" json:"snapshot,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SnapshotVolumeResponse) Reset() {
+	*x = SnapshotVolumeResponse{}
+	mi := &file_scheduler_proto_msgTypes[84]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SnapshotVolumeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SnapshotVolumeResponse) ProtoMessage() {}
+
+func (x *SnapshotVolumeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[84]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SnapshotVolumeResponse.ProtoReflect.Descriptor instead.
+func (*SnapshotVolumeResponse) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{84}
+}
+
+func (x *SnapshotVolumeResponse) GetSnapshot() *VolumeSnapshot {
+	if x != nil {
+		return x.Snapshot
+	}
+	return nil
+}
+
+type ListVolumeSnapshotsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	VolumeName    string                 `protobuf:"bytes,1,opt,name=volume_name,json=volumeName,proto3" json:"volume_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListVolumeSnapshotsRequest) Reset() {
+	*x = ListVolumeSnapshotsRequest{}
+	mi := &file_scheduler_proto_msgTypes[85]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListVolumeSnapshotsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListVolumeSnapshotsRequest) ProtoMessage() {}
+
+func (x *ListVolumeSnapshotsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[85]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListVolumeSnapshotsRequest.ProtoReflect.Descriptor instead.
+func (*ListVolumeSnapshotsRequest) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{85}
+}
+
+func (x *ListVolumeSnapshotsRequest) GetVolumeName() string {
+	if x != nil {
+		return x.VolumeName
+	}
+	return ""
+}
+
+type ListVolumeSnapshotsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Snapshots     []*VolumeSnapshot      `protobuf:"bytes,1,rep,name=snapshots,proto3" json:"snapshots,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListVolumeSnapshotsResponse) Reset() {
+	*x = ListVolumeSnapshotsResponse{}
+	mi := &file_scheduler_proto_msgTypes[86]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListVolumeSnapshotsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListVolumeSnapshotsResponse) ProtoMessage() {}
+
+func (x *ListVolumeSnapshotsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[86]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListVolumeSnapshotsResponse.ProtoReflect.Descriptor instead.
+func (*ListVolumeSnapshotsResponse) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{86}
+}
+
+func (x *ListVolumeSnapshotsResponse) GetSnapshots() []*VolumeSnapshot {
+	if x != nil {
+		return x.Snapshots
+	}
+	return nil
+}
+
+type RestoreVolumeRequest struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	VolumeName       string                 `protobuf:"bytes,1,opt,name=volume_name,json=volumeName,proto3" json:"volume_name,omitempty"`
+	SnapshotId       string                 `protobuf:"bytes,2,opt,name=snapshot_id,json=snapshotId,proto3" json:"snapshot_id,omitempty"`
+	TargetVolumeName string                 `protobuf:"bytes,3,opt,name=target_volume_name,json=targetVolumeName,proto3" json:"target_volume_name,omitempty"` // optional, restores into a new volume instead of in place
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *RestoreVolumeRequest) Reset() {
+	*x = RestoreVolumeRequest{}
+	mi := &file_scheduler_proto_msgTypes[87]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreVolumeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreVolumeRequest) ProtoMessage() {}
+
+func (x *RestoreVolumeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[87]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreVolumeRequest.ProtoReflect.Descriptor instead.
+func (*RestoreVolumeRequest) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{87}
+}
+
+func (x *RestoreVolumeRequest) GetVolumeName() string {
+	if x != nil {
+		return x.VolumeName
+	}
+	return ""
+}
+
+func (x *RestoreVolumeRequest) GetSnapshotId() string {
+	if x != nil {
+		return x.SnapshotId
+	}
+	return ""
+}
+
+func (x *RestoreVolumeRequest) GetTargetVolumeName() string {
+	if x != nil {
+		return x.TargetVolumeName
+	}
+	return ""
+}
+
+type RestoreVolumeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Volume        *Volume                `protobuf:"bytes,1,opt,name=volume,proto3" json:"volume,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreVolumeResponse) Reset() {
+	*x = RestoreVolumeResponse{}
+	mi := &file_scheduler_proto_msgTypes[88]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreVolumeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreVolumeResponse) ProtoMessage() {}
+
+func (x *RestoreVolumeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[88]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreVolumeResponse.ProtoReflect.Descriptor instead.
+func (*RestoreVolumeResponse) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{88}
+}
+
+func (x *RestoreVolumeResponse) GetVolume() *Volume {
+	if x != nil {
+		return x.Volume
+	}
+	return nil
+}
+
+// Logical backup of an environment's database taken with pg_dump
+type DatabaseBackup struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	EnvironmentId string                 `protobuf:"bytes,2,opt,name=environment_id,json=environmentId,proto3" json:"environment_id,omitempty"`
+	DatabaseName  string                 `protobuf:"bytes,3,opt,name=database_name,json=databaseName,proto3" json:"database_name,omitempty"`
+	SizeBytes     int64                  `protobuf:"varint,4,opt,name=size_bytes,json=sizeBytes,proto3" json:"size_bytes,omitempty"`
+	Scheduled     bool                   `protobuf:"varint,5,opt,name=scheduled,proto3" json:"scheduled,omitempty"` // taken by the database's backup policy
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DatabaseBackup) Reset() {
+	*x = DatabaseBackup{}
+	mi := &file_scheduler_proto_msgTypes[89]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DatabaseBackup) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DatabaseBackup) ProtoMessage() {}
+
+func (x *DatabaseBackup) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[89]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DatabaseBackup.ProtoReflect.Descriptor instead.
+func (*DatabaseBackup) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{89}
+}
+
+func (x *DatabaseBackup) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
 }
 
-func (x *NetworkConfig) GetNetworkName() string {
+func (x *DatabaseBackup) GetEnvironmentId() string {
 	if x != nil {
-		return x.NetworkName
+		return x.EnvironmentId
 	}
 	return ""
 }
 
-func (x *NetworkConfig) GetSubnet() string {
+func (x *DatabaseBackup) GetDatabaseName() string {
 	if x != nil {
-		return x.Subnet
+		return x.DatabaseName
 	}
 	return ""
 }
 
-func (x *NetworkConfig) GetGateway() string {
+func (x *DatabaseBackup) GetSizeBytes() int64 {
 	if x != nil {
-		return x.Gateway
+		return x.SizeBytes
 	}
-	return ""
+	return 0
 }
 
-func (x *NetworkConfig) GetIsolated() bool {
+func (x *DatabaseBackup) GetScheduled() bool {
 	if x != nil {
-		return x.Isolated
+		return x.Scheduled
 	}
 	return false
 }
 
-// Environment represents a deployed containerized environment
-type Environment struct {
-	state         protoimpl.MessageState    `protogen:"open.v1"`
-	Id            string                    `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name          string                    `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Spec          *EnvironmentSpecification `protobuf:"bytes,3,opt,name=spec,proto3" json:"spec,omitempty"`
-	Status        EnvironmentStatus         `protobuf:"varint,4,opt,name=status,proto3,enum=scheduler.v1.EnvironmentStatus" json:"status,omitempty"`
-	CreatedAt     *timestamppb.Timestamp    `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	UpdatedAt     *timestamppb.Timestamp    `protobuf:"bytes,6,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
-	Containers    []*ContainerInstance      `protobuf:"bytes,7,rep,name=containers,proto3" json:"containers,omitempty"`
+func (x *DatabaseBackup) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type BackupDatabaseRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"` // environment ID
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Environment) Reset() {
-	*x = Environment{}
-	mi := &file_scheduler_proto_msgTypes[11]
+func (x *BackupDatabaseRequest) Reset() {
+	*x = BackupDatabaseRequest{}
+	mi := &file_scheduler_proto_msgTypes[90]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Environment) String() string {
+func (x *BackupDatabaseRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Environment) ProtoMessage() {}
+func (*BackupDatabaseRequest) ProtoMessage() {}
 
-func (x *Environment) ProtoReflect() protoreflect.Message {
-	mi := &file_scheduler_proto_msgTypes[11]
+func (x *BackupDatabaseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[90]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1070,89 +6129,84 @@ func (x *Environment) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Environment.ProtoReflect.Descriptor instead.
-func (*Environment) Descriptor() ([]byte, []int) {
-	return file_scheduler_proto_rawDescGZIP(), []int{11}
+// Deprecated: Use BackupDatabaseRequest.ProtoReflect.Descriptor instead.
+func (*BackupDatabaseRequest) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{90}
 }
 
-func (x *Environment) GetId() string {
+func (x *BackupDatabaseRequest) GetId() string {
 	if x != nil {
 		return x.Id
 	}
 	return ""
 }
 
-func (x *Environment) GetName() string {
-	if x != nil {
-		return x.Name
-	}
-	return ""
+type BackupDatabaseResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Backup        *DatabaseBackup        `protobuf:"bytes,1,opt,name=backup,proto3" json:"backup,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Environment) GetSpec() *EnvironmentSpecification {
-	if x != nil {
-		return x.Spec
-	}
-	return nil
+func (x *BackupDatabaseResponse) Reset() {
+	*x = BackupDatabaseResponse{}
+	mi := &file_scheduler_proto_msgTypes[91]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *Environment) GetStatus() EnvironmentStatus {
-	if x != nil {
-		return x.Status
-	}
-	return EnvironmentStatus_ENVIRONMENT_STATUS_UNSPECIFIED
+func (x *BackupDatabaseResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (x *Environment) GetCreatedAt() *timestamppb.Timestamp {
+func (*BackupDatabaseResponse) ProtoMessage() {}
+
+func (x *BackupDatabaseResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[91]
 	if x != nil {
-		return x.CreatedAt
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return nil
+	return mi.MessageOf(x)
 }
 
-func (x *Environment) GetUpdatedAt() *timestamppb.Timestamp {
-	if x != nil {
-		return x.UpdatedAt
-	}
-	return nil
+// Deprecated: Use BackupDatabaseResponse.ProtoReflect.Descriptor instead.
+func (*BackupDatabaseResponse) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{91}
 }
 
-func (x *Environment) GetContainers() []*ContainerInstance {
+func (x *BackupDatabaseResponse) GetBackup() *DatabaseBackup {
 	if x != nil {
-		return x.Containers
+		return x.Backup
 	}
 	return nil
 }
 
-// Instance of a running container within an environment
-type ContainerInstance struct {
+type ListDatabaseBackupsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Image         string                 `protobuf:"bytes,3,opt,name=image,proto3" json:"image,omitempty"`
-	Status        ContainerStatus        `protobuf:"varint,4,opt,name=status,proto3,enum=scheduler.v1.ContainerStatus" json:"status,omitempty"`
-	StartedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
-	ExposedPorts  []*PortMapping         `protobuf:"bytes,6,rep,name=exposed_ports,json=exposedPorts,proto3" json:"exposed_ports,omitempty"`
-	IpAddress     string                 `protobuf:"bytes,7,opt,name=ip_address,json=ipAddress,proto3" json:"ip_address,omitempty"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"` // environment ID
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ContainerInstance) Reset() {
-	*x = ContainerInstance{}
-	mi := &file_scheduler_proto_msgTypes[12]
+func (x *ListDatabaseBackupsRequest) Reset() {
+	*x = ListDatabaseBackupsRequest{}
+	mi := &file_scheduler_proto_msgTypes[92]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ContainerInstance) String() string {
+func (x *ListDatabaseBackupsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ContainerInstance) ProtoMessage() {}
+func (*ListDatabaseBackupsRequest) ProtoMessage() {}
 
-func (x *ContainerInstance) ProtoReflect() protoreflect.Message {
-	mi := &file_scheduler_proto_msgTypes[12]
+func (x *ListDatabaseBackupsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[92]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1163,82 +6217,136 @@ func (x *ContainerInstance) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ContainerInstance.ProtoReflect.Descriptor instead.
-func (*ContainerInstance) Descriptor() ([]byte, []int) {
-	return file_scheduler_proto_rawDescGZIP(), []int{12}
+// Deprecated: Use ListDatabaseBackupsRequest.ProtoReflect.Descriptor instead.
+func (*ListDatabaseBackupsRequest) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{92}
 }
 
-func (x *ContainerInstance) GetId() string {
+func (x *ListDatabaseBackupsRequest) GetId() string {
 	if x != nil {
 		return x.Id
 	}
 	return ""
 }
 
-func (x *ContainerInstance) GetName() string {
-	if x != nil {
-		return x.Name
-	}
-	return ""
+type ListDatabaseBackupsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Backups       []*DatabaseBackup      `protobuf:"bytes,1,rep,name=backups,proto3" json:"backups,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ContainerInstance) GetImage() string {
+func (x *ListDatabaseBackupsResponse) Reset() {
+	*x = ListDatabaseBackupsResponse{}
+	mi := &file_scheduler_proto_msgTypes[93]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDatabaseBackupsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDatabaseBackupsResponse) ProtoMessage() {}
+
+func (x *ListDatabaseBackupsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[93]
 	if x != nil {
-		return x.Image
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
 }
 
-func (x *ContainerInstance) GetStatus() ContainerStatus {
+// Deprecated: Use ListDatabaseBackupsResponse.ProtoReflect.Descriptor instead.
+func (*ListDatabaseBackupsResponse) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{93}
+}
+
+func (x *ListDatabaseBackupsResponse) GetBackups() []*DatabaseBackup {
 	if x != nil {
-		return x.Status
+		return x.Backups
 	}
-	return ContainerStatus_CONTAINER_STATUS_UNSPECIFIED
+	return nil
 }
 
-func (x *ContainerInstance) GetStartedAt() *timestamppb.Timestamp {
+type RestoreDatabaseRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"` // environment ID
+	BackupId      string                 `protobuf:"bytes,2,opt,name=backup_id,json=backupId,proto3" json:"backup_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreDatabaseRequest) Reset() {
+	*x = RestoreDatabaseRequest{}
+	mi := &file_scheduler_proto_msgTypes[94]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreDatabaseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreDatabaseRequest) ProtoMessage() {}
+
+func (x *RestoreDatabaseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[94]
 	if x != nil {
-		return x.StartedAt
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return nil
+	return mi.MessageOf(x)
 }
 
-func (x *ContainerInstance) GetExposedPorts() []*PortMapping {
+// Deprecated: Use RestoreDatabaseRequest.ProtoReflect.Descriptor instead.
+func (*RestoreDatabaseRequest) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{94}
+}
+
+func (x *RestoreDatabaseRequest) GetId() string {
 	if x != nil {
-		return x.ExposedPorts
+		return x.Id
 	}
-	return nil
+	return ""
 }
 
-func (x *ContainerInstance) GetIpAddress() string {
+func (x *RestoreDatabaseRequest) GetBackupId() string {
 	if x != nil {
-		return x.IpAddress
+		return x.BackupId
 	}
 	return ""
 }
 
-type CreateEnvironmentRequest struct {
-	state         protoimpl.MessageState    `protogen:"open.v1"`
-	Spec          *EnvironmentSpecification `protobuf:"bytes,1,opt,name=spec,proto3" json:"spec,omitempty"`
+type RestoreDatabaseResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Environment   *Environment           `protobuf:"bytes,1,opt,name=environment,proto3" json:"environment,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateEnvironmentRequest) Reset() {
-	*x = CreateEnvironmentRequest{}
-	mi := &file_scheduler_proto_msgTypes[13]
+func (x *RestoreDatabaseResponse) Reset() {
+	*x = RestoreDatabaseResponse{}
+	mi := &file_scheduler_proto_msgTypes[95]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateEnvironmentRequest) String() string {
+func (x *RestoreDatabaseResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateEnvironmentRequest) ProtoMessage() {}
+func (*RestoreDatabaseResponse) ProtoMessage() {}
 
-func (x *CreateEnvironmentRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_scheduler_proto_msgTypes[13]
+func (x *RestoreDatabaseResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[95]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1249,40 +6357,46 @@ func (x *CreateEnvironmentRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateEnvironmentRequest.ProtoReflect.Descriptor instead.
-func (*CreateEnvironmentRequest) Descriptor() ([]byte, []int) {
-	return file_scheduler_proto_rawDescGZIP(), []int{13}
+// Deprecated: Use RestoreDatabaseResponse.ProtoReflect.Descriptor instead.
+func (*RestoreDatabaseResponse) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{95}
 }
 
-func (x *CreateEnvironmentRequest) GetSpec() *EnvironmentSpecification {
+func (x *RestoreDatabaseResponse) GetEnvironment() *Environment {
 	if x != nil {
-		return x.Spec
+		return x.Environment
 	}
 	return nil
 }
 
-type CreateEnvironmentResponse struct {
+// Secret metadata. Values are write-only and never returned by the API.
+// Only environments of the owning tenant may reference a secret.
+type Secret struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Environment   *Environment           `protobuf:"bytes,1,opt,name=environment,proto3" json:"environment,omitempty"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Labels        map[string]string      `protobuf:"bytes,2,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	UsedBy        []string               `protobuf:"bytes,3,rep,name=used_by,json=usedBy,proto3" json:"used_by,omitempty"` // IDs of environments referencing the secret
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	Tenant        string                 `protobuf:"bytes,5,opt,name=tenant,proto3" json:"tenant,omitempty"` // tenant owning the secret
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateEnvironmentResponse) Reset() {
-	*x = CreateEnvironmentResponse{}
-	mi := &file_scheduler_proto_msgTypes[14]
+func (x *Secret) Reset() {
+	*x = Secret{}
+	mi := &file_scheduler_proto_msgTypes[96]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateEnvironmentResponse) String() string {
+func (x *Secret) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateEnvironmentResponse) ProtoMessage() {}
+func (*Secret) ProtoMessage() {}
 
-func (x *CreateEnvironmentResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_scheduler_proto_msgTypes[14]
+func (x *Secret) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[96]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1293,40 +6407,71 @@ func (x *CreateEnvironmentResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateEnvironmentResponse.ProtoReflect.Descriptor instead.
-func (*CreateEnvironmentResponse) Descriptor() ([]byte, []int) {
-	return file_scheduler_proto_rawDescGZIP(), []int{14}
+// Deprecated: Use Secret.ProtoReflect.Descriptor instead.
+func (*Secret) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{96}
 }
 
-func (x *CreateEnvironmentResponse) GetEnvironment() *Environment {
+func (x *Secret) GetName() string {
 	if x != nil {
-		return x.Environment
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Secret) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
 	}
 	return nil
 }
 
-type GetEnvironmentRequest struct {
+func (x *Secret) GetUsedBy() []string {
+	if x != nil {
+		return x.UsedBy
+	}
+	return nil
+}
+
+func (x *Secret) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Secret) GetTenant() string {
+	if x != nil {
+		return x.Tenant
+	}
+	return ""
+}
+
+type CreateSecretRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Value         []byte                 `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	Labels        map[string]string      `protobuf:"bytes,3,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Tenant        string                 `protobuf:"bytes,4,opt,name=tenant,proto3" json:"tenant,omitempty"` // defaults to the caller's tenant, or "default"
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetEnvironmentRequest) Reset() {
-	*x = GetEnvironmentRequest{}
-	mi := &file_scheduler_proto_msgTypes[15]
+func (x *CreateSecretRequest) Reset() {
+	*x = CreateSecretRequest{}
+	mi := &file_scheduler_proto_msgTypes[97]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetEnvironmentRequest) String() string {
+func (x *CreateSecretRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetEnvironmentRequest) ProtoMessage() {}
+func (*CreateSecretRequest) ProtoMessage() {}
 
-func (x *GetEnvironmentRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_scheduler_proto_msgTypes[15]
+func (x *CreateSecretRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[97]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1337,40 +6482,61 @@ func (x *GetEnvironmentRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetEnvironmentRequest.ProtoReflect.Descriptor instead.
-func (*GetEnvironmentRequest) Descriptor() ([]byte, []int) {
-	return file_scheduler_proto_rawDescGZIP(), []int{15}
+// Deprecated: Use CreateSecretRequest.ProtoReflect.Descriptor instead.
+func (*CreateSecretRequest) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{97}
 }
 
-func (x *GetEnvironmentRequest) GetId() string {
+func (x *CreateSecretRequest) GetName() string {
 	if x != nil {
-		return x.Id
+		return x.Name
 	}
 	return ""
 }
 
-type GetEnvironmentResponse struct {
+func (x *CreateSecretRequest) GetValue() []byte {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+func (x *CreateSecretRequest) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *CreateSecretRequest) GetTenant() string {
+	if x != nil {
+		return x.Tenant
+	}
+	return ""
+}
+
+type CreateSecretResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Environment   *Environment           `protobuf:"bytes,1,opt,name=environment,proto3" json:"environment,omitempty"`
+	Secret        *Secret                `protobuf:"bytes,1,opt,name=secret,proto3" json:"secret,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
-}
-
-func (x *GetEnvironmentResponse) Reset() {
-	*x = GetEnvironmentResponse{}
-	mi := &file_scheduler_proto_msgTypes[16]
+}
+
+func (x *CreateSecretResponse) Reset() {
+	*x = CreateSecretResponse{}
+	mi := &file_scheduler_proto_msgTypes[98]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetEnvironmentResponse) String() string {
+func (x *CreateSecretResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetEnvironmentResponse) ProtoMessage() {}
+func (*CreateSecretResponse) ProtoMessage() {}
 
-func (x *GetEnvironmentResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_scheduler_proto_msgTypes[16]
+func (x *CreateSecretResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[98]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1381,41 +6547,41 @@ func (x *GetEnvironmentResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetEnvironmentResponse.ProtoReflect.Descriptor instead.
-func (*GetEnvironmentResponse) Descriptor() ([]byte, []int) {
-	return file_scheduler_proto_rawDescGZIP(), []int{16}
+// Deprecated: Use CreateSecretResponse.ProtoReflect.Descriptor instead.
+func (*CreateSecretResponse) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{98}
 }
 
-func (x *GetEnvironmentResponse) GetEnvironment() *Environment {
+func (x *CreateSecretResponse) GetSecret() *Secret {
 	if x != nil {
-		return x.Environment
+		return x.Secret
 	}
 	return nil
 }
 
-type UpdateEnvironmentRequest struct {
-	state         protoimpl.MessageState    `protogen:"open.v1"`
-	Id            string                    `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Spec          *EnvironmentSpecification `protobuf:"bytes,2,opt,name=spec,proto3" json:"spec,omitempty"`
+type ListSecretsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Filters       map[string]string      `protobuf:"bytes,1,rep,name=filters,proto3" json:"filters,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Tenant        string                 `protobuf:"bytes,2,opt,name=tenant,proto3" json:"tenant,omitempty"` // callers bound to a tenant only ever see their own
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateEnvironmentRequest) Reset() {
-	*x = UpdateEnvironmentRequest{}
-	mi := &file_scheduler_proto_msgTypes[17]
+func (x *ListSecretsRequest) Reset() {
+	*x = ListSecretsRequest{}
+	mi := &file_scheduler_proto_msgTypes[99]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateEnvironmentRequest) String() string {
+func (x *ListSecretsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateEnvironmentRequest) ProtoMessage() {}
+func (*ListSecretsRequest) ProtoMessage() {}
 
-func (x *UpdateEnvironmentRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_scheduler_proto_msgTypes[17]
+func (x *ListSecretsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[99]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1426,47 +6592,47 @@ func (x *UpdateEnvironmentRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateEnvironmentRequest.ProtoReflect.Descriptor instead.
-func (*UpdateEnvironmentRequest) Descriptor() ([]byte, []int) {
-	return file_scheduler_proto_rawDescGZIP(), []int{17}
+// Deprecated: Use ListSecretsRequest.ProtoReflect.Descriptor instead.
+func (*ListSecretsRequest) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{99}
 }
 
-func (x *UpdateEnvironmentRequest) GetId() string {
+func (x *ListSecretsRequest) GetFilters() map[string]string {
 	if x != nil {
-		return x.Id
+		return x.Filters
 	}
-	return ""
+	return nil
 }
 
-func (x *UpdateEnvironmentRequest) GetSpec() *EnvironmentSpecification {
+func (x *ListSecretsRequest) GetTenant() string {
 	if x != nil {
-		return x.Spec
+		return x.Tenant
 	}
-	return nil
+	return ""
 }
 
-type UpdateEnvironmentResponse struct {
+type ListSecretsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Environment   *Environment           `protobuf:"bytes,1,opt,name=environment,proto3" json:"environment,omitempty"`
+	Secrets       []*Secret              `protobuf:"bytes,1,rep,name=secrets,proto3" json:"secrets,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateEnvironmentResponse) Reset() {
-	*x = UpdateEnvironmentResponse{}
-	mi := &file_scheduler_proto_msgTypes[18]
+func (x *ListSecretsResponse) Reset() {
+	*x = ListSecretsResponse{}
+	mi := &file_scheduler_proto_msgTypes[100]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateEnvironmentResponse) String() string {
+func (x *ListSecretsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateEnvironmentResponse) ProtoMessage() {}
+func (*ListSecretsResponse) ProtoMessage() {}
 
-func (x *UpdateEnvironmentResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_scheduler_proto_msgTypes[18]
+func (x *ListSecretsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[100]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1477,40 +6643,40 @@ func (x *UpdateEnvironmentResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateEnvironmentResponse.ProtoReflect.Descriptor instead.
-func (*UpdateEnvironmentResponse) Descriptor() ([]byte, []int) {
-	return file_scheduler_proto_rawDescGZIP(), []int{18}
+// Deprecated: Use ListSecretsResponse.ProtoReflect.Descriptor instead.
+func (*ListSecretsResponse) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{100}
 }
 
-func (x *UpdateEnvironmentResponse) GetEnvironment() *Environment {
+func (x *ListSecretsResponse) GetSecrets() []*Secret {
 	if x != nil {
-		return x.Environment
+		return x.Secrets
 	}
 	return nil
 }
 
-type DeleteEnvironmentRequest struct {
+type DeleteSecretRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteEnvironmentRequest) Reset() {
-	*x = DeleteEnvironmentRequest{}
-	mi := &file_scheduler_proto_msgTypes[19]
+func (x *DeleteSecretRequest) Reset() {
+	*x = DeleteSecretRequest{}
+	mi := &file_scheduler_proto_msgTypes[101]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteEnvironmentRequest) String() string {
+func (x *DeleteSecretRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteEnvironmentRequest) ProtoMessage() {}
+func (*DeleteSecretRequest) ProtoMessage() {}
 
-func (x *DeleteEnvironmentRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_scheduler_proto_msgTypes[19]
+func (x *DeleteSecretRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[101]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1521,40 +6687,40 @@ func (x *DeleteEnvironmentRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteEnvironmentRequest.ProtoReflect.Descriptor instead.
-func (*DeleteEnvironmentRequest) Descriptor() ([]byte, []int) {
-	return file_scheduler_proto_rawDescGZIP(), []int{19}
+// Deprecated: Use DeleteSecretRequest.ProtoReflect.Descriptor instead.
+func (*DeleteSecretRequest) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{101}
 }
 
-func (x *DeleteEnvironmentRequest) GetId() string {
+func (x *DeleteSecretRequest) GetName() string {
 	if x != nil {
-		return x.Id
+		return x.Name
 	}
 	return ""
 }
 
-type DeleteEnvironmentResponse struct {
+type DeleteSecretResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteEnvironmentResponse) Reset() {
-	*x = DeleteEnvironmentResponse{}
-	mi := &file_scheduler_proto_msgTypes[20]
+func (x *DeleteSecretResponse) Reset() {
+	*x = DeleteSecretResponse{}
+	mi := &file_scheduler_proto_msgTypes[102]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteEnvironmentResponse) String() string {
+func (x *DeleteSecretResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteEnvironmentResponse) ProtoMessage() {}
+func (*DeleteSecretResponse) ProtoMessage() {}
 
-func (x *DeleteEnvironmentResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_scheduler_proto_msgTypes[20]
+func (x *DeleteSecretResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[102]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1565,42 +6731,45 @@ func (x *DeleteEnvironmentResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteEnvironmentResponse.ProtoReflect.Descriptor instead.
-func (*DeleteEnvironmentResponse) Descriptor() ([]byte, []int) {
-	return file_scheduler_proto_rawDescGZIP(), []int{20}
+// Deprecated: Use DeleteSecretResponse.ProtoReflect.Descriptor instead.
+func (*DeleteSecretResponse) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{102}
 }
 
-func (x *DeleteEnvironmentResponse) GetSuccess() bool {
+func (x *DeleteSecretResponse) GetSuccess() bool {
 	if x != nil {
 		return x.Success
 	}
 	return false
 }
 
-type ListEnvironmentsRequest struct {
+// Amount of the resources quotas apply to. Resources are summed over the
+// ResourceLimits of every container of an environment.
+type ResourceQuota struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	PageSize      int32                  `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
-	PageToken     string                 `protobuf:"bytes,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
-	Filters       map[string]string      `protobuf:"bytes,3,rep,name=filters,proto3" json:"filters,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	MemoryMb      int64                  `protobuf:"varint,1,opt,name=memory_mb,json=memoryMb,proto3" json:"memory_mb,omitempty"`
+	CpuCores      float64                `protobuf:"fixed64,2,opt,name=cpu_cores,json=cpuCores,proto3" json:"cpu_cores,omitempty"`
+	DiskMb        int64                  `protobuf:"varint,3,opt,name=disk_mb,json=diskMb,proto3" json:"disk_mb,omitempty"`
+	Environments  int32                  `protobuf:"varint,4,opt,name=environments,proto3" json:"environments,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListEnvironmentsRequest) Reset() {
-	*x = ListEnvironmentsRequest{}
-	mi := &file_scheduler_proto_msgTypes[21]
+func (x *ResourceQuota) Reset() {
+	*x = ResourceQuota{}
+	mi := &file_scheduler_proto_msgTypes[103]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListEnvironmentsRequest) String() string {
+func (x *ResourceQuota) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListEnvironmentsRequest) ProtoMessage() {}
+func (*ResourceQuota) ProtoMessage() {}
 
-func (x *ListEnvironmentsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_scheduler_proto_msgTypes[21]
+func (x *ResourceQuota) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[103]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1611,56 +6780,63 @@ func (x *ListEnvironmentsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListEnvironmentsRequest.ProtoReflect.Descriptor instead.
-func (*ListEnvironmentsRequest) Descriptor() ([]byte, []int) {
-	return file_scheduler_proto_rawDescGZIP(), []int{21}
+// Deprecated: Use ResourceQuota.ProtoReflect.Descriptor instead.
+func (*ResourceQuota) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{103}
 }
 
-func (x *ListEnvironmentsRequest) GetPageSize() int32 {
+func (x *ResourceQuota) GetMemoryMb() int64 {
 	if x != nil {
-		return x.PageSize
+		return x.MemoryMb
 	}
 	return 0
 }
 
-func (x *ListEnvironmentsRequest) GetPageToken() string {
+func (x *ResourceQuota) GetCpuCores() float64 {
 	if x != nil {
-		return x.PageToken
+		return x.CpuCores
 	}
-	return ""
+	return 0
 }
 
-func (x *ListEnvironmentsRequest) GetFilters() map[string]string {
+func (x *ResourceQuota) GetDiskMb() int64 {
 	if x != nil {
-		return x.Filters
+		return x.DiskMb
 	}
-	return nil
+	return 0
 }
 
-type ListEnvironmentsResponse struct {
+func (x *ResourceQuota) GetEnvironments() int32 {
+	if x != nil {
+		return x.Environments
+	}
+	return 0
+}
+
+// Consumption of a quota. Zero limits are unlimited.
+type QuotaUsage struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Environments  []*Environment         `protobuf:"bytes,1,rep,name=environments,proto3" json:"environments,omitempty"`
-	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
-	TotalCount    int32                  `protobuf:"varint,3,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`
+	Used          *ResourceQuota         `protobuf:"bytes,1,opt,name=used,proto3" json:"used,omitempty"`
+	Limit         *ResourceQuota         `protobuf:"bytes,2,opt,name=limit,proto3" json:"limit,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListEnvironmentsResponse) Reset() {
-	*x = ListEnvironmentsResponse{}
-	mi := &file_scheduler_proto_msgTypes[22]
+func (x *QuotaUsage) Reset() {
+	*x = QuotaUsage{}
+	mi := &file_scheduler_proto_msgTypes[104]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListEnvironmentsResponse) String() string {
+func (x *QuotaUsage) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListEnvironmentsResponse) ProtoMessage() {}
+func (*QuotaUsage) ProtoMessage() {}
 
-func (x *ListEnvironmentsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_scheduler_proto_msgTypes[22]
+func (x *QuotaUsage) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[104]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1671,54 +6847,47 @@ func (x *ListEnvironmentsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListEnvironmentsResponse.ProtoReflect.Descriptor instead.
-func (*ListEnvironmentsResponse) Descriptor() ([]byte, []int) {
-	return file_scheduler_proto_rawDescGZIP(), []int{22}
+// Deprecated: Use QuotaUsage.ProtoReflect.Descriptor instead.
+func (*QuotaUsage) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{104}
 }
 
-func (x *ListEnvironmentsResponse) GetEnvironments() []*Environment {
+func (x *QuotaUsage) GetUsed() *ResourceQuota {
 	if x != nil {
-		return x.Environments
+		return x.Used
 	}
 	return nil
 }
 
-func (x *ListEnvironmentsResponse) GetNextPageToken() string {
-	if x != nil {
-		return x.NextPageToken
-	}
-	return ""
-}
-
-func (x *ListEnvironmentsResponse) GetTotalCount() int32 {
+func (x *QuotaUsage) GetLimit() *ResourceQuota {
 	if x != nil {
-		return x.TotalCount
+		return x.Limit
 	}
-	return 0
+	return nil
 }
 
-type StartEnvironmentRequest struct {
+type GetQuotaUsageRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Tenant        string                 `protobuf:"bytes,1,opt,name=tenant,proto3" json:"tenant,omitempty"` // defaults to the caller's tenant, or "default"
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *StartEnvironmentRequest) Reset() {
-	*x = StartEnvironmentRequest{}
-	mi := &file_scheduler_proto_msgTypes[23]
+func (x *GetQuotaUsageRequest) Reset() {
+	*x = GetQuotaUsageRequest{}
+	mi := &file_scheduler_proto_msgTypes[105]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *StartEnvironmentRequest) String() string {
+func (x *GetQuotaUsageRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*StartEnvironmentRequest) ProtoMessage() {}
+func (*GetQuotaUsageRequest) ProtoMessage() {}
 
-func (x *StartEnvironmentRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_scheduler_proto_msgTypes[23]
+func (x *GetQuotaUsageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[105]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1729,40 +6898,42 @@ func (x *StartEnvironmentRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use StartEnvironmentRequest.ProtoReflect.Descriptor instead.
-func (*StartEnvironmentRequest) Descriptor() ([]byte, []int) {
-	return file_scheduler_proto_rawDescGZIP(), []int{23}
+// Deprecated: Use GetQuotaUsageRequest.ProtoReflect.Descriptor instead.
+func (*GetQuotaUsageRequest) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{105}
 }
 
-func (x *StartEnvironmentRequest) GetId() string {
+func (x *GetQuotaUsageRequest) GetTenant() string {
 	if x != nil {
-		return x.Id
+		return x.Tenant
 	}
 	return ""
 }
 
-type StartEnvironmentResponse struct {
+type GetQuotaUsageResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Environment   *Environment           `protobuf:"bytes,1,opt,name=environment,proto3" json:"environment,omitempty"`
+	Tenant        string                 `protobuf:"bytes,1,opt,name=tenant,proto3" json:"tenant,omitempty"`
+	TenantUsage   *QuotaUsage            `protobuf:"bytes,2,opt,name=tenant_usage,json=tenantUsage,proto3" json:"tenant_usage,omitempty"`
+	HostUsage     *QuotaUsage            `protobuf:"bytes,3,opt,name=host_usage,json=hostUsage,proto3" json:"host_usage,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *StartEnvironmentResponse) Reset() {
-	*x = StartEnvironmentResponse{}
-	mi := &file_scheduler_proto_msgTypes[24]
+func (x *GetQuotaUsageResponse) Reset() {
+	*x = GetQuotaUsageResponse{}
+	mi := &file_scheduler_proto_msgTypes[106]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *StartEnvironmentResponse) String() string {
+func (x *GetQuotaUsageResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*StartEnvironmentResponse) ProtoMessage() {}
+func (*GetQuotaUsageResponse) ProtoMessage() {}
 
-func (x *StartEnvironmentResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_scheduler_proto_msgTypes[24]
+func (x *GetQuotaUsageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[106]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1773,41 +6944,58 @@ func (x *StartEnvironmentResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use StartEnvironmentResponse.ProtoReflect.Descriptor instead.
-func (*StartEnvironmentResponse) Descriptor() ([]byte, []int) {
-	return file_scheduler_proto_rawDescGZIP(), []int{24}
+// Deprecated: Use GetQuotaUsageResponse.ProtoReflect.Descriptor instead.
+func (*GetQuotaUsageResponse) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{106}
 }
 
-func (x *StartEnvironmentResponse) GetEnvironment() *Environment {
+func (x *GetQuotaUsageResponse) GetTenant() string {
 	if x != nil {
-		return x.Environment
+		return x.Tenant
+	}
+	return ""
+}
+
+func (x *GetQuotaUsageResponse) GetTenantUsage() *QuotaUsage {
+	if x != nil {
+		return x.TenantUsage
 	}
 	return nil
 }
 
-type StopEnvironmentRequest struct {
+func (x *GetQuotaUsageResponse) GetHostUsage() *QuotaUsage {
+	if x != nil {
+		return x.HostUsage
+	}
+	return nil
+}
+
+// Overcommit ratios by which the limits of running environments may exceed
+// the host capacity
+type OvercommitRatios struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Force         bool                   `protobuf:"varint,2,opt,name=force,proto3" json:"force,omitempty"`
+	Memory        float64                `protobuf:"fixed64,1,opt,name=memory,proto3" json:"memory,omitempty"`
+	Cpu           float64                `protobuf:"fixed64,2,opt,name=cpu,proto3" json:"cpu,omitempty"`
+	Disk          float64                `protobuf:"fixed64,3,opt,name=disk,proto3" json:"disk,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *StopEnvironmentRequest) Reset() {
-	*x = StopEnvironmentRequest{}
-	mi := &file_scheduler_proto_msgTypes[25]
+func (x *OvercommitRatios) Reset() {
+	*x = OvercommitRatios{}
+	mi := &file_scheduler_proto_msgTypes[107]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *StopEnvironmentRequest) String() string {
+func (x *OvercommitRatios) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*StopEnvironmentRequest) ProtoMessage() {}
+func (*OvercommitRatios) ProtoMessage() {}
 
-func (x *StopEnvironmentRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_scheduler_proto_msgTypes[25]
+func (x *OvercommitRatios) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[107]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1818,47 +7006,60 @@ func (x *StopEnvironmentRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use StopEnvironmentRequest.ProtoReflect.Descriptor instead.
-func (*StopEnvironmentRequest) Descriptor() ([]byte, []int) {
-	return file_scheduler_proto_rawDescGZIP(), []int{25}
+// Deprecated: Use OvercommitRatios.ProtoReflect.Descriptor instead.
+func (*OvercommitRatios) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{107}
 }
 
-func (x *StopEnvironmentRequest) GetId() string {
+func (x *OvercommitRatios) GetMemory() float64 {
 	if x != nil {
-		return x.Id
+		return x.Memory
 	}
-	return ""
+	return 0
 }
 
-func (x *StopEnvironmentRequest) GetForce() bool {
+func (x *OvercommitRatios) GetCpu() float64 {
 	if x != nil {
-		return x.Force
+		return x.Cpu
 	}
-	return false
+	return 0
 }
 
-type StopEnvironmentResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Environment   *Environment           `protobuf:"bytes,1,opt,name=environment,proto3" json:"environment,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *OvercommitRatios) GetDisk() float64 {
+	if x != nil {
+		return x.Disk
+	}
+	return 0
 }
 
-func (x *StopEnvironmentResponse) Reset() {
-	*x = StopEnvironmentResponse{}
-	mi := &file_scheduler_proto_msgTypes[26]
+// Capacity of the host the scheduler runs on
+type NodeInfo struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	Hostname            string                 `protobuf:"bytes,1,opt,name=hostname,proto3" json:"hostname,omitempty"`
+	Capacity            *ResourceLimits        `protobuf:"bytes,2,opt,name=capacity,proto3" json:"capacity,omitempty"`       // discovered at startup
+	Allocatable         *ResourceLimits        `protobuf:"bytes,3,opt,name=allocatable,proto3" json:"allocatable,omitempty"` // capacity scaled by the overcommit ratios
+	Reserved            *ResourceLimits        `protobuf:"bytes,4,opt,name=reserved,proto3" json:"reserved,omitempty"`       // summed limits of the running environments, failed ones included until stopped
+	Overcommit          *OvercommitRatios      `protobuf:"bytes,5,opt,name=overcommit,proto3" json:"overcommit,omitempty"`
+	RunningEnvironments int32                  `protobuf:"varint,6,opt,name=running_environments,json=runningEnvironments,proto3" json:"running_environments,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *NodeInfo) Reset() {
+	*x = NodeInfo{}
+	mi := &file_scheduler_proto_msgTypes[108]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *StopEnvironmentResponse) String() string {
+func (x *NodeInfo) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*StopEnvironmentResponse) ProtoMessage() {}
+func (*NodeInfo) ProtoMessage() {}
 
-func (x *StopEnvironmentResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_scheduler_proto_msgTypes[26]
+func (x *NodeInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[108]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1869,84 +7070,74 @@ func (x *StopEnvironmentResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use StopEnvironmentResponse.ProtoReflect.Descriptor instead.
-func (*StopEnvironmentResponse) Descriptor() ([]byte, []int) {
-	return file_scheduler_proto_rawDescGZIP(), []int{26}
+// Deprecated: Use NodeInfo.ProtoReflect.Descriptor instead.
+func (*NodeInfo) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{108}
 }
 
-func (x *StopEnvironmentResponse) GetEnvironment() *Environment {
+func (x *NodeInfo) GetHostname() string {
 	if x != nil {
-		return x.Environment
+		return x.Hostname
 	}
-	return nil
-}
-
-type RestartEnvironmentRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	return ""
 }
 
-func (x *RestartEnvironmentRequest) Reset() {
-	*x = RestartEnvironmentRequest{}
-	mi := &file_scheduler_proto_msgTypes[27]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *NodeInfo) GetCapacity() *ResourceLimits {
+	if x != nil {
+		return x.Capacity
+	}
+	return nil
 }
 
-func (x *RestartEnvironmentRequest) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *NodeInfo) GetAllocatable() *ResourceLimits {
+	if x != nil {
+		return x.Allocatable
+	}
+	return nil
 }
 
-func (*RestartEnvironmentRequest) ProtoMessage() {}
-
-func (x *RestartEnvironmentRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_scheduler_proto_msgTypes[27]
+func (x *NodeInfo) GetReserved() *ResourceLimits {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.Reserved
 	}
-	return mi.MessageOf(x)
+	return nil
 }
 
-// Deprecated: Use RestartEnvironmentRequest.ProtoReflect.Descriptor instead.
-func (*RestartEnvironmentRequest) Descriptor() ([]byte, []int) {
-	return file_scheduler_proto_rawDescGZIP(), []int{27}
+func (x *NodeInfo) GetOvercommit() *OvercommitRatios {
+	if x != nil {
+		return x.Overcommit
+	}
+	return nil
 }
 
-func (x *RestartEnvironmentRequest) GetId() string {
+func (x *NodeInfo) GetRunningEnvironments() int32 {
 	if x != nil {
-		return x.Id
+		return x.RunningEnvironments
 	}
-	return ""
+	return 0
 }
 
-type RestartEnvironmentResponse struct {
+type GetNodeInfoRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Environment   *Environment           `protobuf:"bytes,1,opt,name=environment,proto3" json:"environment,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RestartEnvironmentResponse) Reset() {
-	*x = RestartEnvironmentResponse{}
-	mi := &file_scheduler_proto_msgTypes[28]
+func (x *GetNodeInfoRequest) Reset() {
+	*x = GetNodeInfoRequest{}
+	mi := &file_scheduler_proto_msgTypes[109]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RestartEnvironmentResponse) String() string {
+func (x *GetNodeInfoRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RestartEnvironmentResponse) ProtoMessage() {}
+func (*GetNodeInfoRequest) ProtoMessage() {}
 
-func (x *RestartEnvironmentResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_scheduler_proto_msgTypes[28]
+func (x *GetNodeInfoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[109]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1957,40 +7148,33 @@ func (x *RestartEnvironmentResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RestartEnvironmentResponse.ProtoReflect.Descriptor instead.
-func (*RestartEnvironmentResponse) Descriptor() ([]byte, []int) {
-	return file_scheduler_proto_rawDescGZIP(), []int{28}
-}
-
-func (x *RestartEnvironmentResponse) GetEnvironment() *Environment {
-	if x != nil {
-		return x.Environment
-	}
-	return nil
+// Deprecated: Use GetNodeInfoRequest.ProtoReflect.Descriptor instead.
+func (*GetNodeInfoRequest) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{109}
 }
 
-type GetEnvironmentStatusRequest struct {
+type GetNodeInfoResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Node          *NodeInfo              `protobuf:"bytes,1,opt,name=node,proto3" json:"node,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetEnvironmentStatusRequest) Reset() {
-	*x = GetEnvironmentStatusRequest{}
-	mi := &file_scheduler_proto_msgTypes[29]
+func (x *GetNodeInfoResponse) Reset() {
+	*x = GetNodeInfoResponse{}
+	mi := &file_scheduler_proto_msgTypes[110]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetEnvironmentStatusRequest) String() string {
+func (x *GetNodeInfoResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetEnvironmentStatusRequest) ProtoMessage() {}
+func (*GetNodeInfoResponse) ProtoMessage() {}
 
-func (x *GetEnvironmentStatusRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_scheduler_proto_msgTypes[29]
+func (x *GetNodeInfoResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[110]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2001,41 +7185,53 @@ func (x *GetEnvironmentStatusRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetEnvironmentStatusRequest.ProtoReflect.Descriptor instead.
-func (*GetEnvironmentStatusRequest) Descriptor() ([]byte, []int) {
-	return file_scheduler_proto_rawDescGZIP(), []int{29}
+// Deprecated: Use GetNodeInfoResponse.ProtoReflect.Descriptor instead.
+func (*GetNodeInfoResponse) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{110}
 }
 
-func (x *GetEnvironmentStatusRequest) GetId() string {
+func (x *GetNodeInfoResponse) GetNode() *NodeInfo {
 	if x != nil {
-		return x.Id
+		return x.Node
 	}
-	return ""
+	return nil
 }
 
-type GetEnvironmentStatusResponse struct {
+// Record of a mutating call
+type AuditEvent struct {
 	state            protoimpl.MessageState `protogen:"open.v1"`
-	Environment      *Environment           `protobuf:"bytes,1,opt,name=environment,proto3" json:"environment,omitempty"`
-	ContainerMetrics []*ContainerMetrics    `protobuf:"bytes,2,rep,name=container_metrics,json=containerMetrics,proto3" json:"container_metrics,omitempty"`
+	Id               string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Time             *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=time,proto3" json:"time,omitempty"`
+	Method           string                 `protobuf:"bytes,3,opt,name=method,proto3" json:"method,omitempty"`   // full gRPC method name
+	Subject          string                 `protobuf:"bytes,4,opt,name=subject,proto3" json:"subject,omitempty"` // authenticated caller, empty when authentication is disabled
+	Role             string                 `protobuf:"bytes,5,opt,name=role,proto3" json:"role,omitempty"`
+	Tenant           string                 `protobuf:"bytes,6,opt,name=tenant,proto3" json:"tenant,omitempty"`                                    // tenant of the environment, volume or secret the call targeted, if any
+	EnvironmentId    string                 `protobuf:"bytes,7,opt,name=environment_id,json=environmentId,proto3" json:"environment_id,omitempty"` // environment the call targeted, if any
+	Request          string                 `protobuf:"bytes,8,opt,name=request,proto3" json:"request,omitempty"`                                  // request as JSON with sensitive fields masked
+	Code             string                 `protobuf:"bytes,9,opt,name=code,proto3" json:"code,omitempty"`                                        // gRPC status code of the result
+	Error            string                 `protobuf:"bytes,10,opt,name=error,proto3" json:"error,omitempty"`
+	DurationMs       int64                  `protobuf:"varint,11,opt,name=duration_ms,json=durationMs,proto3" json:"duration_ms,omitempty"`
+	CallerTenant     string                 `protobuf:"bytes,12,opt,name=caller_tenant,json=callerTenant,proto3" json:"caller_tenant,omitempty"`               // tenant the caller is bound to
+	ClientCommonName string                 `protobuf:"bytes,13,opt,name=client_common_name,json=clientCommonName,proto3" json:"client_common_name,omitempty"` // common name of the verified mTLS client certificate, if any
 	unknownFields    protoimpl.UnknownFields
 	sizeCache        protoimpl.SizeCache
 }
 
-func (x *GetEnvironmentStatusResponse) Reset() {
-	*x = GetEnvironmentStatusResponse{}
-	mi := &file_scheduler_proto_msgTypes[30]
+func (x *AuditEvent) Reset() {
+	*x = AuditEvent{}
+	mi := &file_scheduler_proto_msgTypes[111]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetEnvironmentStatusResponse) String() string {
+func (x *AuditEvent) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetEnvironmentStatusResponse) ProtoMessage() {}
+func (*AuditEvent) ProtoMessage() {}
 
-func (x *GetEnvironmentStatusResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_scheduler_proto_msgTypes[30]
+func (x *AuditEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[111]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2046,143 +7242,129 @@ func (x *GetEnvironmentStatusResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetEnvironmentStatusResponse.ProtoReflect.Descriptor instead.
-func (*GetEnvironmentStatusResponse) Descriptor() ([]byte, []int) {
-	return file_scheduler_proto_rawDescGZIP(), []int{30}
+// Deprecated: Use AuditEvent.ProtoReflect.Descriptor instead.
+func (*AuditEvent) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{111}
 }
 
-func (x *GetEnvironmentStatusResponse) GetEnvironment() *Environment {
+func (x *AuditEvent) GetId() string {
 	if x != nil {
-		return x.Environment
+		return x.Id
 	}
-	return nil
+	return ""
 }
 
-func (x *GetEnvironmentStatusResponse) GetContainerMetrics() []*ContainerMetrics {
+func (x *AuditEvent) GetTime() *timestamppb.Timestamp {
 	if x != nil {
-		return x.ContainerMetrics
+		return x.Time
 	}
 	return nil
 }
 
-type ContainerMetrics struct {
-	state            protoimpl.MessageState `protogen:"open.v1"`
-	ContainerId      string                 `protobuf:"bytes,1,opt,name=container_id,json=containerId,proto3" json:"container_id,omitempty"`
-	CpuUsagePercent  float64                `protobuf:"fixed64,2,opt,name=cpu_usage_percent,json=cpuUsagePercent,proto3" json:"cpu_usage_percent,omitempty"`
-	MemoryUsageBytes int64                  `protobuf:"varint,3,opt,name=memory_usage_bytes,json=memoryUsageBytes,proto3" json:"memory_usage_bytes,omitempty"`
-	MemoryLimitBytes int64                  `protobuf:"varint,4,opt,name=memory_limit_bytes,json=memoryLimitBytes,proto3" json:"memory_limit_bytes,omitempty"`
-	DiskUsageBytes   int64                  `protobuf:"varint,5,opt,name=disk_usage_bytes,json=diskUsageBytes,proto3" json:"disk_usage_bytes,omitempty"`
-	NetworkRxBytes   int64                  `protobuf:"varint,6,opt,name=network_rx_bytes,json=networkRxBytes,proto3" json:"network_rx_bytes,omitempty"`
-	NetworkTxBytes   int64                  `protobuf:"varint,7,opt,name=network_tx_bytes,json=networkTxBytes,proto3" json:"network_tx_bytes,omitempty"`
-	unknownFields    protoimpl.UnknownFields
-	sizeCache        protoimpl.SizeCache
-}
-
-func (x *ContainerMetrics) Reset() {
-	*x = ContainerMetrics{}
-	mi := &file_scheduler_proto_msgTypes[31]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *AuditEvent) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
 }
 
-func (x *ContainerMetrics) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *AuditEvent) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
 }
 
-func (*ContainerMetrics) ProtoMessage() {}
-
-func (x *ContainerMetrics) ProtoReflect() protoreflect.Message {
-	mi := &file_scheduler_proto_msgTypes[31]
+func (x *AuditEvent) GetRole() string {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.Role
 	}
-	return mi.MessageOf(x)
+	return ""
 }
 
-// Deprecated: Use ContainerMetrics.ProtoReflect.Descriptor instead.
-func (*ContainerMetrics) Descriptor() ([]byte, []int) {
-	return file_scheduler_proto_rawDescGZIP(), []int{31}
+func (x *AuditEvent) GetTenant() string {
+	if x != nil {
+		return x.Tenant
+	}
+	return ""
 }
 
-func (x *ContainerMetrics) GetContainerId() string {
+func (x *AuditEvent) GetEnvironmentId() string {
 	if x != nil {
-		return x.ContainerId
+		return x.EnvironmentId
 	}
 	return ""
 }
 
-func (x *ContainerMetrics) GetCpuUsagePercent() float64 {
+func (x *AuditEvent) GetRequest() string {
 	if x != nil {
-		return x.CpuUsagePercent
+		return x.Request
 	}
-	return 0
+	return ""
 }
 
-func (x *ContainerMetrics) GetMemoryUsageBytes() int64 {
+func (x *AuditEvent) GetCode() string {
 	if x != nil {
-		return x.MemoryUsageBytes
+		return x.Code
 	}
-	return 0
+	return ""
 }
 
-func (x *ContainerMetrics) GetMemoryLimitBytes() int64 {
+func (x *AuditEvent) GetError() string {
 	if x != nil {
-		return x.MemoryLimitBytes
+		return x.Error
 	}
-	return 0
+	return ""
 }
 
-func (x *ContainerMetrics) GetDiskUsageBytes() int64 {
+func (x *AuditEvent) GetDurationMs() int64 {
 	if x != nil {
-		return x.DiskUsageBytes
+		return x.DurationMs
 	}
 	return 0
 }
 
-func (x *ContainerMetrics) GetNetworkRxBytes() int64 {
+func (x *AuditEvent) GetCallerTenant() string {
 	if x != nil {
-		return x.NetworkRxBytes
+		return x.CallerTenant
 	}
-	return 0
+	return ""
 }
 
-func (x *ContainerMetrics) GetNetworkTxBytes() int64 {
+func (x *AuditEvent) GetClientCommonName() string {
 	if x != nil {
-		return x.NetworkTxBytes
+		return x.ClientCommonName
 	}
-	return 0
+	return ""
 }
 
-type GetEnvironmentLogsRequest struct {
+type ListAuditEventsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	ContainerName string                 `protobuf:"bytes,2,opt,name=container_name,json=containerName,proto3" json:"container_name,omitempty"` // optional, if empty returns logs for all containers
-	Since         *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=since,proto3" json:"since,omitempty"`
-	TailLines     int32                  `protobuf:"varint,4,opt,name=tail_lines,json=tailLines,proto3" json:"tail_lines,omitempty"`
-	Follow        bool                   `protobuf:"varint,5,opt,name=follow,proto3" json:"follow,omitempty"`
+	EnvironmentId string                 `protobuf:"bytes,1,opt,name=environment_id,json=environmentId,proto3" json:"environment_id,omitempty"`
+	Subject       string                 `protobuf:"bytes,2,opt,name=subject,proto3" json:"subject,omitempty"`
+	Since         *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=since,proto3" json:"since,omitempty"` // inclusive
+	Until         *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=until,proto3" json:"until,omitempty"` // exclusive
+	PageSize      int32                  `protobuf:"varint,5,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken     string                 `protobuf:"bytes,6,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetEnvironmentLogsRequest) Reset() {
-	*x = GetEnvironmentLogsRequest{}
-	mi := &file_scheduler_proto_msgTypes[32]
+func (x *ListAuditEventsRequest) Reset() {
+	*x = ListAuditEventsRequest{}
+	mi := &file_scheduler_proto_msgTypes[112]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetEnvironmentLogsRequest) String() string {
+func (x *ListAuditEventsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetEnvironmentLogsRequest) ProtoMessage() {}
+func (*ListAuditEventsRequest) ProtoMessage() {}
 
-func (x *GetEnvironmentLogsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_scheduler_proto_msgTypes[32]
+func (x *ListAuditEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[112]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2193,71 +7375,76 @@ func (x *GetEnvironmentLogsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetEnvironmentLogsRequest.ProtoReflect.Descriptor instead.
-func (*GetEnvironmentLogsRequest) Descriptor() ([]byte, []int) {
-	return file_scheduler_proto_rawDescGZIP(), []int{32}
+// Deprecated: Use ListAuditEventsRequest.ProtoReflect.Descriptor instead.
+func (*ListAuditEventsRequest) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{112}
 }
 
-func (x *GetEnvironmentLogsRequest) GetId() string {
+func (x *ListAuditEventsRequest) GetEnvironmentId() string {
 	if x != nil {
-		return x.Id
+		return x.EnvironmentId
 	}
 	return ""
 }
 
-func (x *GetEnvironmentLogsRequest) GetContainerName() string {
+func (x *ListAuditEventsRequest) GetSubject() string {
 	if x != nil {
-		return x.ContainerName
+		return x.Subject
 	}
 	return ""
 }
 
-func (x *GetEnvironmentLogsRequest) GetSince() *timestamppb.Timestamp {
+func (x *ListAuditEventsRequest) GetSince() *timestamppb.Timestamp {
 	if x != nil {
 		return x.Since
 	}
 	return nil
 }
 
-func (x *GetEnvironmentLogsRequest) GetTailLines() int32 {
+func (x *ListAuditEventsRequest) GetUntil() *timestamppb.Timestamp {
 	if x != nil {
-		return x.TailLines
+		return x.Until
+	}
+	return nil
+}
+
+func (x *ListAuditEventsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
 	}
 	return 0
 }
 
-func (x *GetEnvironmentLogsRequest) GetFollow() bool {
+func (x *ListAuditEventsRequest) GetPageToken() string {
 	if x != nil {
-		return x.Follow
+		return x.PageToken
 	}
-	return false
+	return ""
 }
 
-type GetEnvironmentLogsResponse struct {
+type ListAuditEventsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	ContainerName string                 `protobuf:"bytes,1,opt,name=container_name,json=containerName,proto3" json:"container_name,omitempty"`
-	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
-	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
-	Level         string                 `protobuf:"bytes,4,opt,name=level,proto3" json:"level,omitempty"` // info, warn, error, debug
+	Events        []*AuditEvent          `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"` // newest first
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetEnvironmentLogsResponse) Reset() {
-	*x = GetEnvironmentLogsResponse{}
-	mi := &file_scheduler_proto_msgTypes[33]
+func (x *ListAuditEventsResponse) Reset() {
+	*x = ListAuditEventsResponse{}
+	mi := &file_scheduler_proto_msgTypes[113]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetEnvironmentLogsResponse) String() string {
+func (x *ListAuditEventsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetEnvironmentLogsResponse) ProtoMessage() {}
+func (*ListAuditEventsResponse) ProtoMessage() {}
 
-func (x *GetEnvironmentLogsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_scheduler_proto_msgTypes[33]
+func (x *ListAuditEventsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_proto_msgTypes[113]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2268,35 +7455,21 @@ func (x *GetEnvironmentLogsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetEnvironmentLogsResponse.ProtoReflect.Descriptor instead.
-func (*GetEnvironmentLogsResponse) Descriptor() ([]byte, []int) {
-	return file_scheduler_proto_rawDescGZIP(), []int{33}
-}
-
-func (x *GetEnvironmentLogsResponse) GetContainerName() string {
-	if x != nil {
-		return x.ContainerName
-	}
-	return ""
-}
-
-func (x *GetEnvironmentLogsResponse) GetMessage() string {
-	if x != nil {
-		return x.Message
-	}
-	return ""
+// Deprecated: Use ListAuditEventsResponse.ProtoReflect.Descriptor instead.
+func (*ListAuditEventsResponse) Descriptor() ([]byte, []int) {
+	return file_scheduler_proto_rawDescGZIP(), []int{113}
 }
 
-func (x *GetEnvironmentLogsResponse) GetTimestamp() *timestamppb.Timestamp {
+func (x *ListAuditEventsResponse) GetEvents() []*AuditEvent {
 	if x != nil {
-		return x.Timestamp
+		return x.Events
 	}
 	return nil
 }
 
-func (x *GetEnvironmentLogsResponse) GetLevel() string {
+func (x *ListAuditEventsResponse) GetNextPageToken() string {
 	if x != nil {
-		return x.Level
+		return x.NextPageToken
 	}
 	return ""
 }
@@ -2305,7 +7478,7 @@ var File_scheduler_proto protoreflect.FileDescriptor
 
 const file_scheduler_proto_rawDesc = "" +
 	"\n" +
-	"\x0fscheduler.proto\x12\fscheduler.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xc4\x04\n" +
+	"\x0fscheduler.proto\x12\fscheduler.v1\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\roptions.proto\"\xbd\x05\n" +
 	"\x0fContainerConfig\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
 	"\x05image\x18\x02 \x01(\tR\x05image\x12\x18\n" +
@@ -2317,10 +7490,23 @@ const file_scheduler_proto_rawDesc = "" +
 	"\tresources\x18\b \x01(\v2\x1c.scheduler.v1.ResourceLimitsR\tresources\x12<\n" +
 	"\fhealth_check\x18\t \x01(\v2\x19.scheduler.v1.HealthCheckR\vhealthCheck\x12B\n" +
 	"\x0erestart_policy\x18\n" +
-	" \x01(\x0e2\x1b.scheduler.v1.RestartPolicyR\rrestartPolicy\x1aG\n" +
+	" \x01(\x0e2\x1b.scheduler.v1.RestartPolicyR\rrestartPolicy\x12;\n" +
+	"\fsecret_files\x18\v \x03(\v2\x18.scheduler.v1.SecretFileR\vsecretFiles\x12:\n" +
+	"\tlifecycle\x18\f \x01(\v2\x1c.scheduler.v1.LifecycleHooksR\tlifecycle\x1aG\n" +
 	"\x19EnvironmentVariablesEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"m\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"z\n" +
+	"\x0eLifecycleHooks\x125\n" +
+	"\n" +
+	"post_start\x18\x01 \x01(\v2\x16.scheduler.v1.ExecHookR\tpostStart\x121\n" +
+	"\bpre_stop\x18\x02 \x01(\v2\x16.scheduler.v1.ExecHookR\apreStop\"M\n" +
+	"\bExecHook\x12\x18\n" +
+	"\acommand\x18\x01 \x03(\tR\acommand\x12'\n" +
+	"\x0ftimeout_seconds\x18\x02 \x01(\x05R\x0etimeoutSeconds\"8\n" +
+	"\n" +
+	"SecretFile\x12\x16\n" +
+	"\x06secret\x18\x01 \x01(\tR\x06secret\x12\x12\n" +
+	"\x04path\x18\x02 \x01(\tR\x04path\"m\n" +
 	"\vPortMapping\x12%\n" +
 	"\x0econtainer_port\x18\x01 \x01(\x05R\rcontainerPort\x12\x1b\n" +
 	"\thost_port\x18\x02 \x01(\x05R\bhostPort\x12\x1a\n" +
@@ -2355,35 +7541,74 @@ const file_scheduler_proto_rawDesc = "" +
 	"\tcontainer\x18\x01 \x01(\v2\x1d.scheduler.v1.ContainerConfigR\tcontainer\x12\x18\n" +
 	"\adomains\x18\x02 \x03(\tR\adomains\x12\x1f\n" +
 	"\vssl_enabled\x18\x03 \x01(\bR\n" +
-	"sslEnabled\"\x8b\x02\n" +
+	"sslEnabled\"\x97\x02\n" +
 	"\rBackendConfig\x12;\n" +
-	"\tcontainer\x18\x01 \x01(\v2\x1d.scheduler.v1.ContainerConfigR\tcontainer\x12<\n" +
-	"\x1adatabase_connection_string\x18\x02 \x01(\tR\x18databaseConnectionString\x12C\n" +
-	"\bapi_keys\x18\x03 \x03(\v2(.scheduler.v1.BackendConfig.ApiKeysEntryR\aapiKeys\x1a:\n" +
+	"\tcontainer\x18\x01 \x01(\v2\x1d.scheduler.v1.ContainerConfigR\tcontainer\x12B\n" +
+	"\x1adatabase_connection_string\x18\x02 \x01(\tB\x04\x88\xb5\x18\x01R\x18databaseConnectionString\x12I\n" +
+	"\bapi_keys\x18\x03 \x03(\v2(.scheduler.v1.BackendConfig.ApiKeysEntryB\x04\x88\xb5\x18\x01R\aapiKeys\x1a:\n" +
 	"\fApiKeysEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xfc\x01\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xfe\x03\n" +
 	"\x0eDatabaseConfig\x12;\n" +
 	"\tcontainer\x18\x01 \x01(\v2\x1d.scheduler.v1.ContainerConfigR\tcontainer\x12#\n" +
 	"\rdatabase_name\x18\x02 \x01(\tR\fdatabaseName\x12\x1a\n" +
-	"\busername\x18\x03 \x01(\tR\busername\x12\x1a\n" +
-	"\bpassword\x18\x04 \x01(\tR\bpassword\x12-\n" +
+	"\busername\x18\x03 \x01(\tR\busername\x12 \n" +
+	"\bpassword\x18\x04 \x01(\tB\x04\x88\xb5\x18\x01R\bpassword\x12-\n" +
 	"\x12persistent_storage\x18\x05 \x01(\bR\x11persistentStorage\x12!\n" +
-	"\fstorage_path\x18\x06 \x01(\tR\vstoragePath\"\xdb\x02\n" +
+	"\fstorage_path\x18\x06 \x01(\tR\vstoragePath\x12?\n" +
+	"\rbackup_policy\x18\a \x01(\v2\x1a.scheduler.v1.BackupPolicyR\fbackupPolicy\x12?\n" +
+	"\finit_scripts\x18\b \x03(\v2\x1c.scheduler.v1.DatabaseScriptR\vinitScripts\x12=\n" +
+	"\tmigration\x18\t \x01(\v2\x1f.scheduler.v1.DatabaseMigrationR\tmigration\x129\n" +
+	"\tseed_data\x18\n" +
+	" \x03(\v2\x1c.scheduler.v1.DatabaseScriptR\bseedData\"S\n" +
+	"\x0eDatabaseScript\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x10\n" +
+	"\x03sql\x18\x02 \x01(\tR\x03sql\x12\x1b\n" +
+	"\thost_path\x18\x03 \x01(\tR\bhostPath\"\xd3\x02\n" +
+	"\x11DatabaseMigration\x12\x14\n" +
+	"\x05image\x18\x01 \x01(\tR\x05image\x12\x18\n" +
+	"\acommand\x18\x02 \x03(\tR\acommand\x12\x12\n" +
+	"\x04args\x18\x03 \x03(\tR\x04args\x12n\n" +
+	"\x15environment_variables\x18\x04 \x03(\v29.scheduler.v1.DatabaseMigration.EnvironmentVariablesEntryR\x14environmentVariables\x12\x18\n" +
+	"\aversion\x18\x05 \x01(\tR\aversion\x12'\n" +
+	"\x0ftimeout_seconds\x18\x06 \x01(\x05R\x0etimeoutSeconds\x1aG\n" +
+	"\x19EnvironmentVariablesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"Q\n" +
+	"\fBackupPolicy\x12)\n" +
+	"\x10interval_seconds\x18\x01 \x01(\x05R\x0fintervalSeconds\x12\x16\n" +
+	"\x06retain\x18\x02 \x01(\x05R\x06retain\"\xa8\x03\n" +
 	"\x18EnvironmentSpecification\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12 \n" +
 	"\vdescription\x18\x02 \x01(\tR\vdescription\x12K\n" +
 	"\x11application_stack\x18\x03 \x01(\v2\x1e.scheduler.v1.ApplicationStackR\x10applicationStack\x12J\n" +
 	"\x06labels\x18\x04 \x03(\v22.scheduler.v1.EnvironmentSpecification.LabelsEntryR\x06labels\x125\n" +
-	"\anetwork\x18\x05 \x01(\v2\x1b.scheduler.v1.NetworkConfigR\anetwork\x1a9\n" +
+	"\anetwork\x18\x05 \x01(\v2\x1b.scheduler.v1.NetworkConfigR\anetwork\x12\x1a\n" +
+	"\bpriority\x18\x06 \x01(\x05R\bpriority\x12/\n" +
+	"\x05hooks\x18\a \x01(\v2\x19.scheduler.v1.DeployHooksR\x05hooks\x1a9\n" +
 	"\vLabelsEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x87\x01\n" +
+	"\vDeployHooks\x12:\n" +
+	"\n" +
+	"pre_deploy\x18\x01 \x03(\v2\x1b.scheduler.v1.HookContainerR\tpreDeploy\x12<\n" +
+	"\vpost_deploy\x18\x02 \x03(\v2\x1b.scheduler.v1.HookContainerR\n" +
+	"postDeploy\"\xc5\x02\n" +
+	"\rHookContainer\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05image\x18\x02 \x01(\tR\x05image\x12\x18\n" +
+	"\acommand\x18\x03 \x03(\tR\acommand\x12\x12\n" +
+	"\x04args\x18\x04 \x03(\tR\x04args\x12j\n" +
+	"\x15environment_variables\x18\x05 \x03(\v25.scheduler.v1.HookContainer.EnvironmentVariablesEntryR\x14environmentVariables\x12'\n" +
+	"\x0ftimeout_seconds\x18\x06 \x01(\x05R\x0etimeoutSeconds\x1aG\n" +
+	"\x19EnvironmentVariablesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
 	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x80\x01\n" +
 	"\rNetworkConfig\x12!\n" +
 	"\fnetwork_name\x18\x01 \x01(\tR\vnetworkName\x12\x16\n" +
 	"\x06subnet\x18\x02 \x01(\tR\x06subnet\x12\x18\n" +
 	"\agateway\x18\x03 \x01(\tR\agateway\x12\x1a\n" +
-	"\bisolated\x18\x04 \x01(\bR\bisolated\"\xdd\x02\n" +
+	"\bisolated\x18\x04 \x01(\bR\bisolated\"\xfb\x04\n" +
 	"\vEnvironment\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12:\n" +
@@ -2395,7 +7620,27 @@ const file_scheduler_proto_rawDesc = "" +
 	"updated_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12?\n" +
 	"\n" +
 	"containers\x18\a \x03(\v2\x1f.scheduler.v1.ContainerInstanceR\n" +
-	"containers\"\x9e\x02\n" +
+	"containers\x12\x16\n" +
+	"\x06tenant\x18\b \x01(\tR\x06tenant\x12%\n" +
+	"\x0equeue_position\x18\t \x01(\x05R\rqueuePosition\x12\x1a\n" +
+	"\brevision\x18\n" +
+	" \x01(\x05R\brevision\x12;\n" +
+	"\btemplate\x18\v \x01(\v2\x1f.scheduler.v1.TemplateReferenceR\btemplate\x12G\n" +
+	"\x0edatabase_steps\x18\f \x03(\v2 .scheduler.v1.DatabaseStepResultR\rdatabaseSteps\x12;\n" +
+	"\fhook_results\x18\r \x03(\v2\x18.scheduler.v1.HookResultR\vhookResults\"\xca\x01\n" +
+	"\n" +
+	"HookResult\x12-\n" +
+	"\x05phase\x18\x01 \x01(\x0e2\x17.scheduler.v1.HookPhaseR\x05phase\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1c\n" +
+	"\tsucceeded\x18\x03 \x01(\bR\tsucceeded\x12\x1c\n" +
+	"\x06output\x18\x04 \x01(\tB\x04\x88\xb5\x18\x01R\x06output\x12=\n" +
+	"\fcompleted_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\vcompletedAt\"\xd3\x01\n" +
+	"\x12DatabaseStepResult\x12.\n" +
+	"\x04step\x18\x01 \x01(\x0e2\x1a.scheduler.v1.DatabaseStepR\x04step\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1c\n" +
+	"\tsucceeded\x18\x03 \x01(\bR\tsucceeded\x12\x1c\n" +
+	"\x06output\x18\x04 \x01(\tB\x04\x88\xb5\x18\x01R\x06output\x12=\n" +
+	"\fcompleted_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\vcompletedAt\"\x9e\x02\n" +
 	"\x11ContainerInstance\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
@@ -2405,29 +7650,34 @@ const file_scheduler_proto_rawDesc = "" +
 	"started_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tstartedAt\x12>\n" +
 	"\rexposed_ports\x18\x06 \x03(\v2\x19.scheduler.v1.PortMappingR\fexposedPorts\x12\x1d\n" +
 	"\n" +
-	"ip_address\x18\a \x01(\tR\tipAddress\"V\n" +
+	"ip_address\x18\a \x01(\tR\tipAddress\"n\n" +
 	"\x18CreateEnvironmentRequest\x12:\n" +
-	"\x04spec\x18\x01 \x01(\v2&.scheduler.v1.EnvironmentSpecificationR\x04spec\"X\n" +
+	"\x04spec\x18\x01 \x01(\v2&.scheduler.v1.EnvironmentSpecificationR\x04spec\x12\x16\n" +
+	"\x06tenant\x18\x02 \x01(\tR\x06tenant\"X\n" +
 	"\x19CreateEnvironmentResponse\x12;\n" +
-	"\venvironment\x18\x01 \x01(\v2\x19.scheduler.v1.EnvironmentR\venvironment\"'\n" +
+	"\venvironment\x18\x01 \x01(\v2\x19.scheduler.v1.EnvironmentR\venvironment\"R\n" +
 	"\x15GetEnvironmentRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\"U\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12)\n" +
+	"\x10reveal_sensitive\x18\x02 \x01(\bR\x0frevealSensitive\"U\n" +
 	"\x16GetEnvironmentResponse\x12;\n" +
 	"\venvironment\x18\x01 \x01(\v2\x19.scheduler.v1.EnvironmentR\venvironment\"f\n" +
 	"\x18UpdateEnvironmentRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12:\n" +
 	"\x04spec\x18\x02 \x01(\v2&.scheduler.v1.EnvironmentSpecificationR\x04spec\"X\n" +
 	"\x19UpdateEnvironmentResponse\x12;\n" +
-	"\venvironment\x18\x01 \x01(\v2\x19.scheduler.v1.EnvironmentR\venvironment\"*\n" +
+	"\venvironment\x18\x01 \x01(\v2\x19.scheduler.v1.EnvironmentR\venvironment\"O\n" +
 	"\x18DeleteEnvironmentRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\"5\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12#\n" +
+	"\rpurge_volumes\x18\x02 \x01(\bR\fpurgeVolumes\"5\n" +
 	"\x19DeleteEnvironmentResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\"\xdf\x01\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"\xa2\x02\n" +
 	"\x17ListEnvironmentsRequest\x12\x1b\n" +
 	"\tpage_size\x18\x01 \x01(\x05R\bpageSize\x12\x1d\n" +
 	"\n" +
 	"page_token\x18\x02 \x01(\tR\tpageToken\x12L\n" +
-	"\afilters\x18\x03 \x03(\v22.scheduler.v1.ListEnvironmentsRequest.FiltersEntryR\afilters\x1a:\n" +
+	"\afilters\x18\x03 \x03(\v22.scheduler.v1.ListEnvironmentsRequest.FiltersEntryR\afilters\x12)\n" +
+	"\x10reveal_sensitive\x18\x04 \x01(\bR\x0frevealSensitive\x12\x16\n" +
+	"\x06tenant\x18\x05 \x01(\tR\x06tenant\x1a:\n" +
 	"\fFiltersEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
 	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xa2\x01\n" +
@@ -2435,7 +7685,112 @@ const file_scheduler_proto_rawDesc = "" +
 	"\fenvironments\x18\x01 \x03(\v2\x19.scheduler.v1.EnvironmentR\fenvironments\x12&\n" +
 	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\x12\x1f\n" +
 	"\vtotal_count\x18\x03 \x01(\x05R\n" +
-	"totalCount\")\n" +
+	"totalCount\"Q\n" +
+	"\x14ApplyManifestRequest\x12 \n" +
+	"\bmanifest\x18\x01 \x01(\tB\x04\x88\xb5\x18\x01R\bmanifest\x12\x17\n" +
+	"\adry_run\x18\x02 \x01(\bR\x06dryRun\"\xbf\x01\n" +
+	"\x15ApplyManifestResponse\x12;\n" +
+	"\venvironment\x18\x01 \x01(\v2\x19.scheduler.v1.EnvironmentR\venvironment\x124\n" +
+	"\x06action\x18\x02 \x01(\x0e2\x1c.scheduler.v1.ManifestActionR\x06action\x123\n" +
+	"\achanges\x18\x03 \x03(\v2\x19.scheduler.v1.FieldChangeR\achanges\"\x89\x01\n" +
+	"\vFieldChange\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12,\n" +
+	"\x04type\x18\x02 \x01(\x0e2\x18.scheduler.v1.ChangeTypeR\x04type\x12\x1b\n" +
+	"\told_value\x18\x03 \x01(\tR\boldValue\x12\x1b\n" +
+	"\tnew_value\x18\x04 \x01(\tR\bnewValue\"j\n" +
+	"\x1cPlanEnvironmentUpdateRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12:\n" +
+	"\x04spec\x18\x02 \x01(\v2&.scheduler.v1.EnvironmentSpecificationR\x04spec\"\xce\x01\n" +
+	"\x1dPlanEnvironmentUpdateResponse\x12;\n" +
+	"\venvironment\x18\x01 \x01(\v2\x19.scheduler.v1.EnvironmentR\venvironment\x123\n" +
+	"\achanges\x18\x02 \x03(\v2\x19.scheduler.v1.FieldChangeR\achanges\x12;\n" +
+	"\n" +
+	"containers\x18\x03 \x03(\v2\x1b.scheduler.v1.ContainerPlanR\n" +
+	"containers\"\xf3\x01\n" +
+	"\rContainerPlan\x12\x14\n" +
+	"\x05alias\x18\x01 \x01(\tR\x05alias\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x125\n" +
+	"\x06action\x18\x03 \x01(\x0e2\x1d.scheduler.v1.ContainerActionR\x06action\x123\n" +
+	"\achanges\x18\x04 \x03(\v2\x19.scheduler.v1.FieldChangeR\achanges\x12'\n" +
+	"\x0fvolumes_changed\x18\x05 \x01(\bR\x0evolumesChanged\x12#\n" +
+	"\rports_changed\x18\x06 \x01(\bR\fportsChanged\"\x88\x02\n" +
+	"\bTemplate\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12?\n" +
+	"\n" +
+	"parameters\x18\x03 \x03(\v2\x1f.scheduler.v1.TemplateParameterR\n" +
+	"parameters\x12\x18\n" +
+	"\aversion\x18\x04 \x01(\x05R\aversion\x12\x18\n" +
+	"\abuiltin\x18\x05 \x01(\bR\abuiltin\x12\x16\n" +
+	"\x06source\x18\x06 \x01(\tR\x06source\x129\n" +
+	"\n" +
+	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"A\n" +
+	"\x11TemplateReference\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\x05R\aversion\"\xd5\x01\n" +
+	"\x11TemplateParameter\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12/\n" +
+	"\x04type\x18\x03 \x01(\x0e2\x1b.scheduler.v1.ParameterTypeR\x04type\x12#\n" +
+	"\rdefault_value\x18\x04 \x01(\tR\fdefaultValue\x12\x1a\n" +
+	"\brequired\x18\x05 \x01(\bR\brequired\x12\x18\n" +
+	"\apattern\x18\x06 \x01(\tR\apattern\"\x16\n" +
+	"\x14ListTemplatesRequest\"M\n" +
+	"\x15ListTemplatesResponse\x124\n" +
+	"\ttemplates\x18\x01 \x03(\v2\x16.scheduler.v1.TemplateR\ttemplates\"B\n" +
+	"\x12GetTemplateRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\x05R\aversion\"I\n" +
+	"\x13GetTemplateResponse\x122\n" +
+	"\btemplate\x18\x01 \x01(\v2\x16.scheduler.v1.TemplateR\btemplate\"\xd5\x03\n" +
+	"$CreateEnvironmentFromTemplateRequest\x12\x1a\n" +
+	"\btemplate\x18\x01 \x01(\tR\btemplate\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12h\n" +
+	"\n" +
+	"parameters\x18\x03 \x03(\v2B.scheduler.v1.CreateEnvironmentFromTemplateRequest.ParametersEntryB\x04\x88\xb5\x18\x01R\n" +
+	"parameters\x12\x16\n" +
+	"\x06tenant\x18\x04 \x01(\tR\x06tenant\x12V\n" +
+	"\x06labels\x18\x05 \x03(\v2>.scheduler.v1.CreateEnvironmentFromTemplateRequest.LabelsEntryR\x06labels\x12)\n" +
+	"\x10template_version\x18\x06 \x01(\x05R\x0ftemplateVersion\x1a=\n" +
+	"\x0fParametersEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"d\n" +
+	"%CreateEnvironmentFromTemplateResponse\x12;\n" +
+	"\venvironment\x18\x01 \x01(\v2\x19.scheduler.v1.EnvironmentR\venvironment\"5\n" +
+	"\x17RegisterTemplateRequest\x12\x1a\n" +
+	"\bmanifest\x18\x01 \x01(\tR\bmanifest\"N\n" +
+	"\x18RegisterTemplateResponse\x122\n" +
+	"\btemplate\x18\x01 \x01(\v2\x16.scheduler.v1.TemplateR\btemplate\"+\n" +
+	"\x15DeleteTemplateRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"\x18\n" +
+	"\x16DeleteTemplateResponse\"\xa7\x02\n" +
+	"\x13EnvironmentRevision\x12%\n" +
+	"\x0eenvironment_id\x18\x01 \x01(\tR\renvironmentId\x12\x16\n" +
+	"\x06number\x18\x02 \x01(\x05R\x06number\x12:\n" +
+	"\x04spec\x18\x03 \x01(\v2&.scheduler.v1.EnvironmentSpecificationR\x04spec\x129\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12\x16\n" +
+	"\x06author\x18\x05 \x01(\tR\x06author\x12\x1f\n" +
+	"\vrollback_of\x18\x06 \x01(\x05R\n" +
+	"rollbackOf\x12!\n" +
+	"\fdeploy_error\x18\a \x01(\tR\vdeployError\"H\n" +
+	"\x1fListEnvironmentRevisionsRequest\x12%\n" +
+	"\x0eenvironment_id\x18\x01 \x01(\tR\renvironmentId\"c\n" +
+	" ListEnvironmentRevisionsResponse\x12?\n" +
+	"\trevisions\x18\x01 \x03(\v2!.scheduler.v1.EnvironmentRevisionR\trevisions\"^\n" +
+	"\x1dGetEnvironmentRevisionRequest\x12%\n" +
+	"\x0eenvironment_id\x18\x01 \x01(\tR\renvironmentId\x12\x16\n" +
+	"\x06number\x18\x02 \x01(\x05R\x06number\"_\n" +
+	"\x1eGetEnvironmentRevisionResponse\x12=\n" +
+	"\brevision\x18\x01 \x01(\v2!.scheduler.v1.EnvironmentRevisionR\brevision\"H\n" +
+	"\x1aRollbackEnvironmentRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1a\n" +
+	"\brevision\x18\x02 \x01(\x05R\brevision\"Z\n" +
+	"\x1bRollbackEnvironmentResponse\x12;\n" +
+	"\venvironment\x18\x01 \x01(\v2\x19.scheduler.v1.EnvironmentR\venvironment\")\n" +
 	"\x17StartEnvironmentRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\"W\n" +
 	"\x18StartEnvironmentResponse\x12;\n" +
@@ -2448,9 +7803,10 @@ const file_scheduler_proto_rawDesc = "" +
 	"\x19RestartEnvironmentRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\"Y\n" +
 	"\x1aRestartEnvironmentResponse\x12;\n" +
-	"\venvironment\x18\x01 \x01(\v2\x19.scheduler.v1.EnvironmentR\venvironment\"-\n" +
+	"\venvironment\x18\x01 \x01(\v2\x19.scheduler.v1.EnvironmentR\venvironment\"X\n" +
 	"\x1bGetEnvironmentStatusRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\"\xa8\x01\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12)\n" +
+	"\x10reveal_sensitive\x18\x02 \x01(\bR\x0frevealSensitive\"\xa8\x01\n" +
 	"\x1cGetEnvironmentStatusResponse\x12;\n" +
 	"\venvironment\x18\x01 \x01(\v2\x19.scheduler.v1.EnvironmentR\venvironment\x12K\n" +
 	"\x11container_metrics\x18\x02 \x03(\v2\x1e.scheduler.v1.ContainerMetricsR\x10containerMetrics\"\xbb\x02\n" +
@@ -2473,13 +7829,229 @@ const file_scheduler_proto_rawDesc = "" +
 	"\x0econtainer_name\x18\x01 \x01(\tR\rcontainerName\x12\x18\n" +
 	"\amessage\x18\x02 \x01(\tR\amessage\x128\n" +
 	"\ttimestamp\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12\x14\n" +
-	"\x05level\x18\x04 \x01(\tR\x05level*\xa3\x01\n" +
+	"\x05level\x18\x04 \x01(\tR\x05level\"\xf7\x02\n" +
+	"\x06Volume\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x128\n" +
+	"\x06labels\x18\x02 \x03(\v2 .scheduler.v1.Volume.LabelsEntryR\x06labels\x12\x12\n" +
+	"\x04path\x18\x03 \x01(\tR\x04path\x12\x1d\n" +
+	"\n" +
+	"size_bytes\x18\x04 \x01(\x03R\tsizeBytes\x12\x17\n" +
+	"\aused_by\x18\x05 \x03(\tR\x06usedBy\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12E\n" +
+	"\x0fsnapshot_policy\x18\a \x01(\v2\x1c.scheduler.v1.SnapshotPolicyR\x0esnapshotPolicy\x12\x16\n" +
+	"\x06tenant\x18\b \x01(\tR\x06tenant\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"S\n" +
+	"\x0eSnapshotPolicy\x12)\n" +
+	"\x10interval_seconds\x18\x01 \x01(\x05R\x0fintervalSeconds\x12\x16\n" +
+	"\x06retain\x18\x02 \x01(\x05R\x06retain\"\x8a\x02\n" +
+	"\x13CreateVolumeRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12E\n" +
+	"\x06labels\x18\x02 \x03(\v2-.scheduler.v1.CreateVolumeRequest.LabelsEntryR\x06labels\x12E\n" +
+	"\x0fsnapshot_policy\x18\x03 \x01(\v2\x1c.scheduler.v1.SnapshotPolicyR\x0esnapshotPolicy\x12\x16\n" +
+	"\x06tenant\x18\x04 \x01(\tR\x06tenant\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"D\n" +
+	"\x14CreateVolumeResponse\x12,\n" +
+	"\x06volume\x18\x01 \x01(\v2\x14.scheduler.v1.VolumeR\x06volume\"&\n" +
+	"\x10GetVolumeRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"A\n" +
+	"\x11GetVolumeResponse\x12,\n" +
+	"\x06volume\x18\x01 \x01(\v2\x14.scheduler.v1.VolumeR\x06volume\"\xb1\x01\n" +
+	"\x12ListVolumesRequest\x12G\n" +
+	"\afilters\x18\x01 \x03(\v2-.scheduler.v1.ListVolumesRequest.FiltersEntryR\afilters\x12\x16\n" +
+	"\x06tenant\x18\x02 \x01(\tR\x06tenant\x1a:\n" +
+	"\fFiltersEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"o\n" +
+	"\x13ListVolumesResponse\x12.\n" +
+	"\avolumes\x18\x01 \x03(\v2\x14.scheduler.v1.VolumeR\avolumes\x12(\n" +
+	"\x10total_size_bytes\x18\x02 \x01(\x03R\x0etotalSizeBytes\"\xf2\x01\n" +
+	"\x13UpdateVolumeRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12E\n" +
+	"\x06labels\x18\x02 \x03(\v2-.scheduler.v1.UpdateVolumeRequest.LabelsEntryR\x06labels\x12E\n" +
+	"\x0fsnapshot_policy\x18\x03 \x01(\v2\x1c.scheduler.v1.SnapshotPolicyR\x0esnapshotPolicy\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"D\n" +
+	"\x14UpdateVolumeResponse\x12,\n" +
+	"\x06volume\x18\x01 \x01(\v2\x14.scheduler.v1.VolumeR\x06volume\")\n" +
+	"\x13DeleteVolumeRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"0\n" +
+	"\x14DeleteVolumeResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"\x8f\x03\n" +
+	"\x0eVolumeSnapshot\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1f\n" +
+	"\vvolume_name\x18\x02 \x01(\tR\n" +
+	"volumeName\x124\n" +
+	"\x06method\x18\x03 \x01(\x0e2\x1c.scheduler.v1.SnapshotMethodR\x06method\x12\x1d\n" +
+	"\n" +
+	"size_bytes\x18\x04 \x01(\x03R\tsizeBytes\x12!\n" +
+	"\fstored_bytes\x18\x05 \x01(\x03R\vstoredBytes\x12@\n" +
+	"\x06labels\x18\x06 \x03(\v2(.scheduler.v1.VolumeSnapshot.LabelsEntryR\x06labels\x12\x1c\n" +
+	"\tscheduled\x18\a \x01(\bR\tscheduled\x129\n" +
+	"\n" +
+	"created_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xf2\x01\n" +
+	"\x15SnapshotVolumeRequest\x12\x1f\n" +
+	"\vvolume_name\x18\x01 \x01(\tR\n" +
+	"volumeName\x124\n" +
+	"\x06method\x18\x02 \x01(\x0e2\x1c.scheduler.v1.SnapshotMethodR\x06method\x12G\n" +
+	"\x06labels\x18\x03 \x03(\v2/.scheduler.v1.SnapshotVolumeRequest.LabelsEntryR\x06labels\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"R\n" +
+	"\x16SnapshotVolumeResponse\x128\n" +
+	"\bsnapshot\x18\x01 \x01(\v2\x1c.scheduler.v1.VolumeSnapshotR\bsnapshot\"=\n" +
+	"\x1aListVolumeSnapshotsRequest\x12\x1f\n" +
+	"\vvolume_name\x18\x01 \x01(\tR\n" +
+	"volumeName\"Y\n" +
+	"\x1bListVolumeSnapshotsResponse\x12:\n" +
+	"\tsnapshots\x18\x01 \x03(\v2\x1c.scheduler.v1.VolumeSnapshotR\tsnapshots\"\x86\x01\n" +
+	"\x14RestoreVolumeRequest\x12\x1f\n" +
+	"\vvolume_name\x18\x01 \x01(\tR\n" +
+	"volumeName\x12\x1f\n" +
+	"\vsnapshot_id\x18\x02 \x01(\tR\n" +
+	"snapshotId\x12,\n" +
+	"\x12target_volume_name\x18\x03 \x01(\tR\x10targetVolumeName\"E\n" +
+	"\x15RestoreVolumeResponse\x12,\n" +
+	"\x06volume\x18\x01 \x01(\v2\x14.scheduler.v1.VolumeR\x06volume\"\xe4\x01\n" +
+	"\x0eDatabaseBackup\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12%\n" +
+	"\x0eenvironment_id\x18\x02 \x01(\tR\renvironmentId\x12#\n" +
+	"\rdatabase_name\x18\x03 \x01(\tR\fdatabaseName\x12\x1d\n" +
+	"\n" +
+	"size_bytes\x18\x04 \x01(\x03R\tsizeBytes\x12\x1c\n" +
+	"\tscheduled\x18\x05 \x01(\bR\tscheduled\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"'\n" +
+	"\x15BackupDatabaseRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"N\n" +
+	"\x16BackupDatabaseResponse\x124\n" +
+	"\x06backup\x18\x01 \x01(\v2\x1c.scheduler.v1.DatabaseBackupR\x06backup\",\n" +
+	"\x1aListDatabaseBackupsRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"U\n" +
+	"\x1bListDatabaseBackupsResponse\x126\n" +
+	"\abackups\x18\x01 \x03(\v2\x1c.scheduler.v1.DatabaseBackupR\abackups\"E\n" +
+	"\x16RestoreDatabaseRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1b\n" +
+	"\tbackup_id\x18\x02 \x01(\tR\bbackupId\"V\n" +
+	"\x17RestoreDatabaseResponse\x12;\n" +
+	"\venvironment\x18\x01 \x01(\v2\x19.scheduler.v1.EnvironmentR\venvironment\"\xfd\x01\n" +
+	"\x06Secret\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x128\n" +
+	"\x06labels\x18\x02 \x03(\v2 .scheduler.v1.Secret.LabelsEntryR\x06labels\x12\x17\n" +
+	"\aused_by\x18\x03 \x03(\tR\x06usedBy\x129\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12\x16\n" +
+	"\x06tenant\x18\x05 \x01(\tR\x06tenant\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xdf\x01\n" +
+	"\x13CreateSecretRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1a\n" +
+	"\x05value\x18\x02 \x01(\fB\x04\x88\xb5\x18\x01R\x05value\x12E\n" +
+	"\x06labels\x18\x03 \x03(\v2-.scheduler.v1.CreateSecretRequest.LabelsEntryR\x06labels\x12\x16\n" +
+	"\x06tenant\x18\x04 \x01(\tR\x06tenant\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"D\n" +
+	"\x14CreateSecretResponse\x12,\n" +
+	"\x06secret\x18\x01 \x01(\v2\x14.scheduler.v1.SecretR\x06secret\"\xb1\x01\n" +
+	"\x12ListSecretsRequest\x12G\n" +
+	"\afilters\x18\x01 \x03(\v2-.scheduler.v1.ListSecretsRequest.FiltersEntryR\afilters\x12\x16\n" +
+	"\x06tenant\x18\x02 \x01(\tR\x06tenant\x1a:\n" +
+	"\fFiltersEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"E\n" +
+	"\x13ListSecretsResponse\x12.\n" +
+	"\asecrets\x18\x01 \x03(\v2\x14.scheduler.v1.SecretR\asecrets\")\n" +
+	"\x13DeleteSecretRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"0\n" +
+	"\x14DeleteSecretResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"\x86\x01\n" +
+	"\rResourceQuota\x12\x1b\n" +
+	"\tmemory_mb\x18\x01 \x01(\x03R\bmemoryMb\x12\x1b\n" +
+	"\tcpu_cores\x18\x02 \x01(\x01R\bcpuCores\x12\x17\n" +
+	"\adisk_mb\x18\x03 \x01(\x03R\x06diskMb\x12\"\n" +
+	"\fenvironments\x18\x04 \x01(\x05R\fenvironments\"p\n" +
+	"\n" +
+	"QuotaUsage\x12/\n" +
+	"\x04used\x18\x01 \x01(\v2\x1b.scheduler.v1.ResourceQuotaR\x04used\x121\n" +
+	"\x05limit\x18\x02 \x01(\v2\x1b.scheduler.v1.ResourceQuotaR\x05limit\".\n" +
+	"\x14GetQuotaUsageRequest\x12\x16\n" +
+	"\x06tenant\x18\x01 \x01(\tR\x06tenant\"\xa5\x01\n" +
+	"\x15GetQuotaUsageResponse\x12\x16\n" +
+	"\x06tenant\x18\x01 \x01(\tR\x06tenant\x12;\n" +
+	"\ftenant_usage\x18\x02 \x01(\v2\x18.scheduler.v1.QuotaUsageR\vtenantUsage\x127\n" +
+	"\n" +
+	"host_usage\x18\x03 \x01(\v2\x18.scheduler.v1.QuotaUsageR\thostUsage\"P\n" +
+	"\x10OvercommitRatios\x12\x16\n" +
+	"\x06memory\x18\x01 \x01(\x01R\x06memory\x12\x10\n" +
+	"\x03cpu\x18\x02 \x01(\x01R\x03cpu\x12\x12\n" +
+	"\x04disk\x18\x03 \x01(\x01R\x04disk\"\xcd\x02\n" +
+	"\bNodeInfo\x12\x1a\n" +
+	"\bhostname\x18\x01 \x01(\tR\bhostname\x128\n" +
+	"\bcapacity\x18\x02 \x01(\v2\x1c.scheduler.v1.ResourceLimitsR\bcapacity\x12>\n" +
+	"\vallocatable\x18\x03 \x01(\v2\x1c.scheduler.v1.ResourceLimitsR\vallocatable\x128\n" +
+	"\breserved\x18\x04 \x01(\v2\x1c.scheduler.v1.ResourceLimitsR\breserved\x12>\n" +
+	"\n" +
+	"overcommit\x18\x05 \x01(\v2\x1e.scheduler.v1.OvercommitRatiosR\n" +
+	"overcommit\x121\n" +
+	"\x14running_environments\x18\x06 \x01(\x05R\x13runningEnvironments\"\x14\n" +
+	"\x12GetNodeInfoRequest\"A\n" +
+	"\x13GetNodeInfoResponse\x12*\n" +
+	"\x04node\x18\x01 \x01(\v2\x16.scheduler.v1.NodeInfoR\x04node\"\x89\x03\n" +
+	"\n" +
+	"AuditEvent\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12.\n" +
+	"\x04time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x04time\x12\x16\n" +
+	"\x06method\x18\x03 \x01(\tR\x06method\x12\x18\n" +
+	"\asubject\x18\x04 \x01(\tR\asubject\x12\x12\n" +
+	"\x04role\x18\x05 \x01(\tR\x04role\x12\x16\n" +
+	"\x06tenant\x18\x06 \x01(\tR\x06tenant\x12%\n" +
+	"\x0eenvironment_id\x18\a \x01(\tR\renvironmentId\x12\x18\n" +
+	"\arequest\x18\b \x01(\tR\arequest\x12\x12\n" +
+	"\x04code\x18\t \x01(\tR\x04code\x12\x14\n" +
+	"\x05error\x18\n" +
+	" \x01(\tR\x05error\x12\x1f\n" +
+	"\vduration_ms\x18\v \x01(\x03R\n" +
+	"durationMs\x12#\n" +
+	"\rcaller_tenant\x18\f \x01(\tR\fcallerTenant\x12,\n" +
+	"\x12client_common_name\x18\r \x01(\tR\x10clientCommonName\"\xf9\x01\n" +
+	"\x16ListAuditEventsRequest\x12%\n" +
+	"\x0eenvironment_id\x18\x01 \x01(\tR\renvironmentId\x12\x18\n" +
+	"\asubject\x18\x02 \x01(\tR\asubject\x120\n" +
+	"\x05since\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x05since\x120\n" +
+	"\x05until\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x05until\x12\x1b\n" +
+	"\tpage_size\x18\x05 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x06 \x01(\tR\tpageToken\"s\n" +
+	"\x17ListAuditEventsResponse\x120\n" +
+	"\x06events\x18\x01 \x03(\v2\x18.scheduler.v1.AuditEventR\x06events\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken*\xa3\x01\n" +
 	"\rRestartPolicy\x12\x1e\n" +
 	"\x1aRESTART_POLICY_UNSPECIFIED\x10\x00\x12\x15\n" +
 	"\x11RESTART_POLICY_NO\x10\x01\x12\x19\n" +
 	"\x15RESTART_POLICY_ALWAYS\x10\x02\x12\x1d\n" +
 	"\x19RESTART_POLICY_ON_FAILURE\x10\x03\x12!\n" +
-	"\x1dRESTART_POLICY_UNLESS_STOPPED\x10\x04*\x99\x02\n" +
+	"\x1dRESTART_POLICY_UNLESS_STOPPED\x10\x04*\x92\x01\n" +
+	"\tHookPhase\x12\x1a\n" +
+	"\x16HOOK_PHASE_UNSPECIFIED\x10\x00\x12\x19\n" +
+	"\x15HOOK_PHASE_PRE_DEPLOY\x10\x01\x12\x19\n" +
+	"\x15HOOK_PHASE_POST_START\x10\x02\x12\x1a\n" +
+	"\x16HOOK_PHASE_POST_DEPLOY\x10\x03\x12\x17\n" +
+	"\x13HOOK_PHASE_PRE_STOP\x10\x04*\x86\x01\n" +
+	"\fDatabaseStep\x12\x1d\n" +
+	"\x19DATABASE_STEP_UNSPECIFIED\x10\x00\x12\x1d\n" +
+	"\x19DATABASE_STEP_INIT_SCRIPT\x10\x01\x12\x1b\n" +
+	"\x17DATABASE_STEP_MIGRATION\x10\x02\x12\x1b\n" +
+	"\x17DATABASE_STEP_SEED_DATA\x10\x03*\x99\x02\n" +
 	"\x11EnvironmentStatus\x12\"\n" +
 	"\x1eENVIRONMENT_STATUS_UNSPECIFIED\x10\x00\x12\x1e\n" +
 	"\x1aENVIRONMENT_STATUS_PENDING\x10\x01\x12\x1f\n" +
@@ -2497,18 +8069,71 @@ const file_scheduler_proto_rawDesc = "" +
 	"\x19CONTAINER_STATUS_STOPPING\x10\x04\x12\x1c\n" +
 	"\x18CONTAINER_STATUS_STOPPED\x10\x05\x12\x1b\n" +
 	"\x17CONTAINER_STATUS_FAILED\x10\x06\x12\x1f\n" +
-	"\x1bCONTAINER_STATUS_RESTARTING\x10\a2\x8a\b\n" +
+	"\x1bCONTAINER_STATUS_RESTARTING\x10\a*\x8a\x01\n" +
+	"\x0eManifestAction\x12\x1f\n" +
+	"\x1bMANIFEST_ACTION_UNSPECIFIED\x10\x00\x12\x1b\n" +
+	"\x17MANIFEST_ACTION_CREATED\x10\x01\x12\x1b\n" +
+	"\x17MANIFEST_ACTION_UPDATED\x10\x02\x12\x1d\n" +
+	"\x19MANIFEST_ACTION_UNCHANGED\x10\x03*s\n" +
+	"\n" +
+	"ChangeType\x12\x1b\n" +
+	"\x17CHANGE_TYPE_UNSPECIFIED\x10\x00\x12\x15\n" +
+	"\x11CHANGE_TYPE_ADDED\x10\x01\x12\x17\n" +
+	"\x13CHANGE_TYPE_REMOVED\x10\x02\x12\x18\n" +
+	"\x14CHANGE_TYPE_MODIFIED\x10\x03*\xac\x01\n" +
+	"\x0fContainerAction\x12 \n" +
+	"\x1cCONTAINER_ACTION_UNSPECIFIED\x10\x00\x12\x1b\n" +
+	"\x17CONTAINER_ACTION_CREATE\x10\x01\x12\x1d\n" +
+	"\x19CONTAINER_ACTION_RECREATE\x10\x02\x12\x1e\n" +
+	"\x1aCONTAINER_ACTION_UNCHANGED\x10\x03\x12\x1b\n" +
+	"\x17CONTAINER_ACTION_REMOVE\x10\x04*\x82\x01\n" +
+	"\rParameterType\x12\x1e\n" +
+	"\x1aPARAMETER_TYPE_UNSPECIFIED\x10\x00\x12\x19\n" +
+	"\x15PARAMETER_TYPE_STRING\x10\x01\x12\x1a\n" +
+	"\x16PARAMETER_TYPE_INTEGER\x10\x02\x12\x1a\n" +
+	"\x16PARAMETER_TYPE_BOOLEAN\x10\x03*k\n" +
+	"\x0eSnapshotMethod\x12\x1f\n" +
+	"\x1bSNAPSHOT_METHOD_UNSPECIFIED\x10\x00\x12\x1b\n" +
+	"\x17SNAPSHOT_METHOD_TARBALL\x10\x01\x12\x1b\n" +
+	"\x17SNAPSHOT_METHOD_REFLINK\x10\x022\xae\x1c\n" +
 	"\x10SchedulerService\x12d\n" +
 	"\x11CreateEnvironment\x12&.scheduler.v1.CreateEnvironmentRequest\x1a'.scheduler.v1.CreateEnvironmentResponse\x12[\n" +
 	"\x0eGetEnvironment\x12#.scheduler.v1.GetEnvironmentRequest\x1a$.scheduler.v1.GetEnvironmentResponse\x12d\n" +
 	"\x11UpdateEnvironment\x12&.scheduler.v1.UpdateEnvironmentRequest\x1a'.scheduler.v1.UpdateEnvironmentResponse\x12d\n" +
 	"\x11DeleteEnvironment\x12&.scheduler.v1.DeleteEnvironmentRequest\x1a'.scheduler.v1.DeleteEnvironmentResponse\x12a\n" +
-	"\x10ListEnvironments\x12%.scheduler.v1.ListEnvironmentsRequest\x1a&.scheduler.v1.ListEnvironmentsResponse\x12a\n" +
+	"\x10ListEnvironments\x12%.scheduler.v1.ListEnvironmentsRequest\x1a&.scheduler.v1.ListEnvironmentsResponse\x12X\n" +
+	"\rApplyManifest\x12\".scheduler.v1.ApplyManifestRequest\x1a#.scheduler.v1.ApplyManifestResponse\x12p\n" +
+	"\x15PlanEnvironmentUpdate\x12*.scheduler.v1.PlanEnvironmentUpdateRequest\x1a+.scheduler.v1.PlanEnvironmentUpdateResponse\x12X\n" +
+	"\rListTemplates\x12\".scheduler.v1.ListTemplatesRequest\x1a#.scheduler.v1.ListTemplatesResponse\x12R\n" +
+	"\vGetTemplate\x12 .scheduler.v1.GetTemplateRequest\x1a!.scheduler.v1.GetTemplateResponse\x12\x88\x01\n" +
+	"\x1dCreateEnvironmentFromTemplate\x122.scheduler.v1.CreateEnvironmentFromTemplateRequest\x1a3.scheduler.v1.CreateEnvironmentFromTemplateResponse\x12a\n" +
+	"\x10RegisterTemplate\x12%.scheduler.v1.RegisterTemplateRequest\x1a&.scheduler.v1.RegisterTemplateResponse\x12[\n" +
+	"\x0eDeleteTemplate\x12#.scheduler.v1.DeleteTemplateRequest\x1a$.scheduler.v1.DeleteTemplateResponse\x12y\n" +
+	"\x18ListEnvironmentRevisions\x12-.scheduler.v1.ListEnvironmentRevisionsRequest\x1a..scheduler.v1.ListEnvironmentRevisionsResponse\x12s\n" +
+	"\x16GetEnvironmentRevision\x12+.scheduler.v1.GetEnvironmentRevisionRequest\x1a,.scheduler.v1.GetEnvironmentRevisionResponse\x12j\n" +
+	"\x13RollbackEnvironment\x12(.scheduler.v1.RollbackEnvironmentRequest\x1a).scheduler.v1.RollbackEnvironmentResponse\x12a\n" +
 	"\x10StartEnvironment\x12%.scheduler.v1.StartEnvironmentRequest\x1a&.scheduler.v1.StartEnvironmentResponse\x12^\n" +
 	"\x0fStopEnvironment\x12$.scheduler.v1.StopEnvironmentRequest\x1a%.scheduler.v1.StopEnvironmentResponse\x12g\n" +
 	"\x12RestartEnvironment\x12'.scheduler.v1.RestartEnvironmentRequest\x1a(.scheduler.v1.RestartEnvironmentResponse\x12m\n" +
 	"\x14GetEnvironmentStatus\x12).scheduler.v1.GetEnvironmentStatusRequest\x1a*.scheduler.v1.GetEnvironmentStatusResponse\x12i\n" +
-	"\x12GetEnvironmentLogs\x12'.scheduler.v1.GetEnvironmentLogsRequest\x1a(.scheduler.v1.GetEnvironmentLogsResponse0\x01B\x15Z\x13scheduler/proto/genb\x06proto3"
+	"\x12GetEnvironmentLogs\x12'.scheduler.v1.GetEnvironmentLogsRequest\x1a(.scheduler.v1.GetEnvironmentLogsResponse0\x01\x12U\n" +
+	"\fCreateVolume\x12!.scheduler.v1.CreateVolumeRequest\x1a\".scheduler.v1.CreateVolumeResponse\x12L\n" +
+	"\tGetVolume\x12\x1e.scheduler.v1.GetVolumeRequest\x1a\x1f.scheduler.v1.GetVolumeResponse\x12R\n" +
+	"\vListVolumes\x12 .scheduler.v1.ListVolumesRequest\x1a!.scheduler.v1.ListVolumesResponse\x12U\n" +
+	"\fUpdateVolume\x12!.scheduler.v1.UpdateVolumeRequest\x1a\".scheduler.v1.UpdateVolumeResponse\x12U\n" +
+	"\fDeleteVolume\x12!.scheduler.v1.DeleteVolumeRequest\x1a\".scheduler.v1.DeleteVolumeResponse\x12[\n" +
+	"\x0eSnapshotVolume\x12#.scheduler.v1.SnapshotVolumeRequest\x1a$.scheduler.v1.SnapshotVolumeResponse\x12j\n" +
+	"\x13ListVolumeSnapshots\x12(.scheduler.v1.ListVolumeSnapshotsRequest\x1a).scheduler.v1.ListVolumeSnapshotsResponse\x12X\n" +
+	"\rRestoreVolume\x12\".scheduler.v1.RestoreVolumeRequest\x1a#.scheduler.v1.RestoreVolumeResponse\x12[\n" +
+	"\x0eBackupDatabase\x12#.scheduler.v1.BackupDatabaseRequest\x1a$.scheduler.v1.BackupDatabaseResponse\x12j\n" +
+	"\x13ListDatabaseBackups\x12(.scheduler.v1.ListDatabaseBackupsRequest\x1a).scheduler.v1.ListDatabaseBackupsResponse\x12^\n" +
+	"\x0fRestoreDatabase\x12$.scheduler.v1.RestoreDatabaseRequest\x1a%.scheduler.v1.RestoreDatabaseResponse\x12U\n" +
+	"\fCreateSecret\x12!.scheduler.v1.CreateSecretRequest\x1a\".scheduler.v1.CreateSecretResponse\x12R\n" +
+	"\vListSecrets\x12 .scheduler.v1.ListSecretsRequest\x1a!.scheduler.v1.ListSecretsResponse\x12U\n" +
+	"\fDeleteSecret\x12!.scheduler.v1.DeleteSecretRequest\x1a\".scheduler.v1.DeleteSecretResponse\x12X\n" +
+	"\rGetQuotaUsage\x12\".scheduler.v1.GetQuotaUsageRequest\x1a#.scheduler.v1.GetQuotaUsageResponse\x12R\n" +
+	"\vGetNodeInfo\x12 .scheduler.v1.GetNodeInfoRequest\x1a!.scheduler.v1.GetNodeInfoResponse\x12^\n" +
+	"\x0fListAuditEvents\x12$.scheduler.v1.ListAuditEventsRequest\x1a%.scheduler.v1.ListAuditEventsResponseB\x15Z\x13scheduler/proto/genb\x06proto3"
 
 var (
 	file_scheduler_proto_rawDescOnce sync.Once
@@ -2522,119 +8147,360 @@ func file_scheduler_proto_rawDescGZIP() []byte {
 	return file_scheduler_proto_rawDescData
 }
 
-var file_scheduler_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
-var file_scheduler_proto_msgTypes = make([]protoimpl.MessageInfo, 39)
+var file_scheduler_proto_enumTypes = make([]protoimpl.EnumInfo, 10)
+var file_scheduler_proto_msgTypes = make([]protoimpl.MessageInfo, 132)
 var file_scheduler_proto_goTypes = []any{
-	(RestartPolicy)(0),                   // 0: scheduler.v1.RestartPolicy
-	(EnvironmentStatus)(0),               // 1: scheduler.v1.EnvironmentStatus
-	(ContainerStatus)(0),                 // 2: scheduler.v1.ContainerStatus
-	(*ContainerConfig)(nil),              // 3: scheduler.v1.ContainerConfig
-	(*PortMapping)(nil),                  // 4: scheduler.v1.PortMapping
-	(*VolumeMount)(nil),                  // 5: scheduler.v1.VolumeMount
-	(*ResourceLimits)(nil),               // 6: scheduler.v1.ResourceLimits
-	(*HealthCheck)(nil),                  // 7: scheduler.v1.HealthCheck
-	(*ApplicationStack)(nil),             // 8: scheduler.v1.ApplicationStack
-	(*FrontendConfig)(nil),               // 9: scheduler.v1.FrontendConfig
-	(*BackendConfig)(nil),                // 10: scheduler.v1.BackendConfig
-	(*DatabaseConfig)(nil),               // 11: scheduler.v1.DatabaseConfig
-	(*EnvironmentSpecification)(nil),     // 12: scheduler.v1.EnvironmentSpecification
-	(*NetworkConfig)(nil),                // 13: scheduler.v1.NetworkConfig
-	(*Environment)(nil),                  // 14: scheduler.v1.Environment
-	(*ContainerInstance)(nil),            // 15: scheduler.v1.ContainerInstance
-	(*CreateEnvironmentRequest)(nil),     // 16: scheduler.v1.CreateEnvironmentRequest
-	(*CreateEnvironmentResponse)(nil),    // 17: scheduler.v1.CreateEnvironmentResponse
-	(*GetEnvironmentRequest)(nil),        // 18: scheduler.v1.GetEnvironmentRequest
-	(*GetEnvironmentResponse)(nil),       // 19: scheduler.v1.GetEnvironmentResponse
-	(*UpdateEnvironmentRequest)(nil),     // 20: scheduler.v1.UpdateEnvironmentRequest
-	(*UpdateEnvironmentResponse)(nil),    // 21: scheduler.v1.UpdateEnvironmentResponse
-	(*DeleteEnvironmentRequest)(nil),     // 22: scheduler.v1.DeleteEnvironmentRequest
-	(*DeleteEnvironmentResponse)(nil),    // 23: scheduler.v1.DeleteEnvironmentResponse
-	(*ListEnvironmentsRequest)(nil),      // 24: scheduler.v1.ListEnvironmentsRequest
-	(*ListEnvironmentsResponse)(nil),     // 25: scheduler.v1.ListEnvironmentsResponse
-	(*StartEnvironmentRequest)(nil),      // 26: scheduler.v1.StartEnvironmentRequest
-	(*StartEnvironmentResponse)(nil),     // 27: scheduler.v1.StartEnvironmentResponse
-	(*StopEnvironmentRequest)(nil),       // 28: scheduler.v1.StopEnvironmentRequest
-	(*StopEnvironmentResponse)(nil),      // 29: scheduler.v1.StopEnvironmentResponse
-	(*RestartEnvironmentRequest)(nil),    // 30: scheduler.v1.RestartEnvironmentRequest
-	(*RestartEnvironmentResponse)(nil),   // 31: scheduler.v1.RestartEnvironmentResponse
-	(*GetEnvironmentStatusRequest)(nil),  // 32: scheduler.v1.GetEnvironmentStatusRequest
-	(*GetEnvironmentStatusResponse)(nil), // 33: scheduler.v1.GetEnvironmentStatusResponse
-	(*ContainerMetrics)(nil),             // 34: scheduler.v1.ContainerMetrics
-	(*GetEnvironmentLogsRequest)(nil),    // 35: scheduler.v1.GetEnvironmentLogsRequest
-	(*GetEnvironmentLogsResponse)(nil),   // 36: scheduler.v1.GetEnvironmentLogsResponse
-	nil,                                  // 37: scheduler.v1.ContainerConfig.EnvironmentVariablesEntry
-	nil,                                  // 38: scheduler.v1.ApplicationStack.AdditionalServicesEntry
-	nil,                                  // 39: scheduler.v1.BackendConfig.ApiKeysEntry
-	nil,                                  // 40: scheduler.v1.EnvironmentSpecification.LabelsEntry
-	nil,                                  // 41: scheduler.v1.ListEnvironmentsRequest.FiltersEntry
-	(*timestamppb.Timestamp)(nil),        // 42: google.protobuf.Timestamp
+	(RestartPolicy)(0),                            // 0: scheduler.v1.RestartPolicy
+	(HookPhase)(0),                                // 1: scheduler.v1.HookPhase
+	(DatabaseStep)(0),                             // 2: scheduler.v1.DatabaseStep
+	(EnvironmentStatus)(0),                        // 3: scheduler.v1.EnvironmentStatus
+	(ContainerStatus)(0),                          // 4: scheduler.v1.ContainerStatus
+	(ManifestAction)(0),                           // 5: scheduler.v1.ManifestAction
+	(ChangeType)(0),                               // 6: scheduler.v1.ChangeType
+	(ContainerAction)(0),                          // 7: scheduler.v1.ContainerAction
+	(ParameterType)(0),                            // 8: scheduler.v1.ParameterType
+	(SnapshotMethod)(0),                           // 9: scheduler.v1.SnapshotMethod
+	(*ContainerConfig)(nil),                       // 10: scheduler.v1.ContainerConfig
+	(*LifecycleHooks)(nil),                        // 11: scheduler.v1.LifecycleHooks
+	(*ExecHook)(nil),                              // 12: scheduler.v1.ExecHook
+	(*SecretFile)(nil),                            // 13: scheduler.v1.SecretFile
+	(*PortMapping)(nil),                           // 14: scheduler.v1.PortMapping
+	(*VolumeMount)(nil),                           // 15: scheduler.v1.VolumeMount
+	(*ResourceLimits)(nil),                        // 16: scheduler.v1.ResourceLimits
+	(*HealthCheck)(nil),                           // 17: scheduler.v1.HealthCheck
+	(*ApplicationStack)(nil),                      // 18: scheduler.v1.ApplicationStack
+	(*FrontendConfig)(nil),                        // 19: scheduler.v1.FrontendConfig
+	(*BackendConfig)(nil),                         // 20: scheduler.v1.BackendConfig
+	(*DatabaseConfig)(nil),                        // 21: scheduler.v1.DatabaseConfig
+	(*DatabaseScript)(nil),                        // 22: scheduler.v1.DatabaseScript
+	(*DatabaseMigration)(nil),                     // 23: scheduler.v1.DatabaseMigration
+	(*BackupPolicy)(nil),                          // 24: scheduler.v1.BackupPolicy
+	(*EnvironmentSpecification)(nil),              // 25: scheduler.v1.EnvironmentSpecification
+	(*DeployHooks)(nil),                           // 26: scheduler.v1.DeployHooks
+	(*HookContainer)(nil),                         // 27: scheduler.v1.HookContainer
+	(*NetworkConfig)(nil),                         // 28: scheduler.v1.NetworkConfig
+	(*Environment)(nil),                           // 29: scheduler.v1.Environment
+	(*HookResult)(nil),                            // 30: scheduler.v1.HookResult
+	(*DatabaseStepResult)(nil),                    // 31: scheduler.v1.DatabaseStepResult
+	(*ContainerInstance)(nil),                     // 32: scheduler.v1.ContainerInstance
+	(*CreateEnvironmentRequest)(nil),              // 33: scheduler.v1.CreateEnvironmentRequest
+	(*CreateEnvironmentResponse)(nil),             // 34: scheduler.v1.CreateEnvironmentResponse
+	(*GetEnvironmentRequest)(nil),                 // 35: scheduler.v1.GetEnvironmentRequest
+	(*GetEnvironmentResponse)(nil),                // 36: scheduler.v1.GetEnvironmentResponse
+	(*UpdateEnvironmentRequest)(nil),              // 37: scheduler.v1.UpdateEnvironmentRequest
+	(*UpdateEnvironmentResponse)(nil),             // 38: scheduler.v1.UpdateEnvironmentResponse
+	(*DeleteEnvironmentRequest)(nil),              // 39: scheduler.v1.DeleteEnvironmentRequest
+	(*DeleteEnvironmentResponse)(nil),             // 40: scheduler.v1.DeleteEnvironmentResponse
+	(*ListEnvironmentsRequest)(nil),               // 41: scheduler.v1.ListEnvironmentsRequest
+	(*ListEnvironmentsResponse)(nil),              // 42: scheduler.v1.ListEnvironmentsResponse
+	(*ApplyManifestRequest)(nil),                  // 43: scheduler.v1.ApplyManifestRequest
+	(*ApplyManifestResponse)(nil),                 // 44: scheduler.v1.ApplyManifestResponse
+	(*FieldChange)(nil),                           // 45: scheduler.v1.FieldChange
+	(*PlanEnvironmentUpdateRequest)(nil),          // 46: scheduler.v1.PlanEnvironmentUpdateRequest
+	(*PlanEnvironmentUpdateResponse)(nil),         // 47: scheduler.v1.PlanEnvironmentUpdateResponse
+	(*ContainerPlan)(nil),                         // 48: scheduler.v1.ContainerPlan
+	(*Template)(nil),                              // 49: scheduler.v1.Template
+	(*TemplateReference)(nil),                     // 50: scheduler.v1.TemplateReference
+	(*TemplateParameter)(nil),                     // 51: scheduler.v1.TemplateParameter
+	(*ListTemplatesRequest)(nil),                  // 52: scheduler.v1.ListTemplatesRequest
+	(*ListTemplatesResponse)(nil),                 // 53: scheduler.v1.ListTemplatesResponse
+	(*GetTemplateRequest)(nil),                    // 54: scheduler.v1.GetTemplateRequest
+	(*GetTemplateResponse)(nil),                   // 55: scheduler.v1.GetTemplateResponse
+	(*CreateEnvironmentFromTemplateRequest)(nil),  // 56: scheduler.v1.CreateEnvironmentFromTemplateRequest
+	(*CreateEnvironmentFromTemplateResponse)(nil), // 57: scheduler.v1.CreateEnvironmentFromTemplateResponse
+	(*RegisterTemplateRequest)(nil),               // 58: scheduler.v1.RegisterTemplateRequest
+	(*RegisterTemplateResponse)(nil),              // 59: scheduler.v1.RegisterTemplateResponse
+	(*DeleteTemplateRequest)(nil),                 // 60: scheduler.v1.DeleteTemplateRequest
+	(*DeleteTemplateResponse)(nil),                // 61: scheduler.v1.DeleteTemplateResponse
+	(*EnvironmentRevision)(nil),                   // 62: scheduler.v1.EnvironmentRevision
+	(*ListEnvironmentRevisionsRequest)(nil),       // 63: scheduler.v1.ListEnvironmentRevisionsRequest
+	(*ListEnvironmentRevisionsResponse)(nil),      // 64: scheduler.v1.ListEnvironmentRevisionsResponse
+	(*GetEnvironmentRevisionRequest)(nil),         // 65: scheduler.v1.GetEnvironmentRevisionRequest
+	(*GetEnvironmentRevisionResponse)(nil),        // 66: scheduler.v1.GetEnvironmentRevisionResponse
+	(*RollbackEnvironmentRequest)(nil),            // 67: scheduler.v1.RollbackEnvironmentRequest
+	(*RollbackEnvironmentResponse)(nil),           // 68: scheduler.v1.RollbackEnvironmentResponse
+	(*StartEnvironmentRequest)(nil),               // 69: scheduler.v1.StartEnvironmentRequest
+	(*StartEnvironmentResponse)(nil),              // 70: scheduler.v1.StartEnvironmentResponse
+	(*StopEnvironmentRequest)(nil),                // 71: scheduler.v1.StopEnvironmentRequest
+	(*StopEnvironmentResponse)(nil),               // 72: scheduler.v1.StopEnvironmentResponse
+	(*RestartEnvironmentRequest)(nil),             // 73: scheduler.v1.RestartEnvironmentRequest
+	(*RestartEnvironmentResponse)(nil),            // 74: scheduler.v1.RestartEnvironmentResponse
+	(*GetEnvironmentStatusRequest)(nil),           // 75: scheduler.v1.GetEnvironmentStatusRequest
+	(*GetEnvironmentStatusResponse)(nil),          // 76: scheduler.v1.GetEnvironmentStatusResponse
+	(*ContainerMetrics)(nil),                      // 77: scheduler.v1.ContainerMetrics
+	(*GetEnvironmentLogsRequest)(nil),             // 78: scheduler.v1.GetEnvironmentLogsRequest
+	(*GetEnvironmentLogsResponse)(nil),            // 79: scheduler.v1.GetEnvironmentLogsResponse
+	(*Volume)(nil),                                // 80: scheduler.v1.Volume
+	(*SnapshotPolicy)(nil),                        // 81: scheduler.v1.SnapshotPolicy
+	(*CreateVolumeRequest)(nil),                   // 82: scheduler.v1.CreateVolumeRequest
+	(*CreateVolumeResponse)(nil),                  // 83: scheduler.v1.CreateVolumeResponse
+	(*GetVolumeRequest)(nil),                      // 84: scheduler.v1.GetVolumeRequest
+	(*GetVolumeResponse)(nil),                     // 85: scheduler.v1.GetVolumeResponse
+	(*ListVolumesRequest)(nil),                    // 86: scheduler.v1.ListVolumesRequest
+	(*ListVolumesResponse)(nil),                   // 87: scheduler.v1.ListVolumesResponse
+	(*UpdateVolumeRequest)(nil),                   // 88: scheduler.v1.UpdateVolumeRequest
+	(*UpdateVolumeResponse)(nil),                  // 89: scheduler.v1.UpdateVolumeResponse
+	(*DeleteVolumeRequest)(nil),                   // 90: scheduler.v1.DeleteVolumeRequest
+	(*DeleteVolumeResponse)(nil),                  // 91: scheduler.v1.DeleteVolumeResponse
+	(*VolumeSnapshot)(nil),                        // 92: scheduler.v1.VolumeSnapshot
+	(*SnapshotVolumeRequest)(nil),                 // 93: scheduler.v1.SnapshotVolumeRequest
+	(*SnapshotVolumeResponse)(nil),                // 94: scheduler.v1.SnapshotVolumeResponse
+	(*ListVolumeSnapshotsRequest)(nil),            // 95: scheduler.v1.ListVolumeSnapshotsRequest
+	(*ListVolumeSnapshotsResponse)(nil),           // 96: scheduler.v1.ListVolumeSnapshotsResponse
+	(*RestoreVolumeRequest)(nil),                  // 97: scheduler.v1.RestoreVolumeRequest
+	(*RestoreVolumeResponse)(nil),                 // 98: scheduler.v1.RestoreVolumeResponse
+	(*DatabaseBackup)(nil),                        // 99: scheduler.v1.DatabaseBackup
+	(*BackupDatabaseRequest)(nil),                 // 100: scheduler.v1.BackupDatabaseRequest
+	(*BackupDatabaseResponse)(nil),                // 101: scheduler.v1.BackupDatabaseResponse
+	(*ListDatabaseBackupsRequest)(nil),            // 102: scheduler.v1.ListDatabaseBackupsRequest
+	(*ListDatabaseBackupsResponse)(nil),           // 103: scheduler.v1.ListDatabaseBackupsResponse
+	(*RestoreDatabaseRequest)(nil),                // 104: scheduler.v1.RestoreDatabaseRequest
+	(*RestoreDatabaseResponse)(nil),               // 105: scheduler.v1.RestoreDatabaseResponse
+	(*Secret)(nil),                                // 106: scheduler.v1.Secret
+	(*CreateSecretRequest)(nil),                   // 107: scheduler.v1.CreateSecretRequest
+	(*CreateSecretResponse)(nil),                  // 108: scheduler.v1.CreateSecretResponse
+	(*ListSecretsRequest)(nil),                    // 109: scheduler.v1.ListSecretsRequest
+	(*ListSecretsResponse)(nil),                   // 110: scheduler.v1.ListSecretsResponse
+	(*DeleteSecretRequest)(nil),                   // 111: scheduler.v1.DeleteSecretRequest
+	(*DeleteSecretResponse)(nil),                  // 112: scheduler.v1.DeleteSecretResponse
+	(*ResourceQuota)(nil),                         // 113: scheduler.v1.ResourceQuota
+	(*QuotaUsage)(nil),                            // 114: scheduler.v1.QuotaUsage
+	(*GetQuotaUsageRequest)(nil),                  // 115: scheduler.v1.GetQuotaUsageRequest
+	(*GetQuotaUsageResponse)(nil),                 // 116: scheduler.v1.GetQuotaUsageResponse
+	(*OvercommitRatios)(nil),                      // 117: scheduler.v1.OvercommitRatios
+	(*NodeInfo)(nil),                              // 118: scheduler.v1.NodeInfo
+	(*GetNodeInfoRequest)(nil),                    // 119: scheduler.v1.GetNodeInfoRequest
+	(*GetNodeInfoResponse)(nil),                   // 120: scheduler.v1.GetNodeInfoResponse
+	(*AuditEvent)(nil),                            // 121: scheduler.v1.AuditEvent
+	(*ListAuditEventsRequest)(nil),                // 122: scheduler.v1.ListAuditEventsRequest
+	(*ListAuditEventsResponse)(nil),               // 123: scheduler.v1.ListAuditEventsResponse
+	nil,                                           // 124: scheduler.v1.ContainerConfig.EnvironmentVariablesEntry
+	nil,                                           // 125: scheduler.v1.ApplicationStack.AdditionalServicesEntry
+	nil,                                           // 126: scheduler.v1.BackendConfig.ApiKeysEntry
+	nil,                                           // 127: scheduler.v1.DatabaseMigration.EnvironmentVariablesEntry
+	nil,                                           // 128: scheduler.v1.EnvironmentSpecification.LabelsEntry
+	nil,                                           // 129: scheduler.v1.HookContainer.EnvironmentVariablesEntry
+	nil,                                           // 130: scheduler.v1.ListEnvironmentsRequest.FiltersEntry
+	nil,                                           // 131: scheduler.v1.CreateEnvironmentFromTemplateRequest.ParametersEntry
+	nil,                                           // 132: scheduler.v1.CreateEnvironmentFromTemplateRequest.LabelsEntry
+	nil,                                           // 133: scheduler.v1.Volume.LabelsEntry
+	nil,                                           // 134: scheduler.v1.CreateVolumeRequest.LabelsEntry
+	nil,                                           // 135: scheduler.v1.ListVolumesRequest.FiltersEntry
+	nil,                                           // 136: scheduler.v1.UpdateVolumeRequest.LabelsEntry
+	nil,                                           // 137: scheduler.v1.VolumeSnapshot.LabelsEntry
+	nil,                                           // 138: scheduler.v1.SnapshotVolumeRequest.LabelsEntry
+	nil,                                           // 139: scheduler.v1.Secret.LabelsEntry
+	nil,                                           // 140: scheduler.v1.CreateSecretRequest.LabelsEntry
+	nil,                                           // 141: scheduler.v1.ListSecretsRequest.FiltersEntry
+	(*timestamppb.Timestamp)(nil),                 // 142: google.protobuf.Timestamp
 }
 var file_scheduler_proto_depIdxs = []int32{
-	4,  // 0: scheduler.v1.ContainerConfig.ports:type_name -> scheduler.v1.PortMapping
-	5,  // 1: scheduler.v1.ContainerConfig.volumes:type_name -> scheduler.v1.VolumeMount
-	37, // 2: scheduler.v1.ContainerConfig.environment_variables:type_name -> scheduler.v1.ContainerConfig.EnvironmentVariablesEntry
-	6,  // 3: scheduler.v1.ContainerConfig.resources:type_name -> scheduler.v1.ResourceLimits
-	7,  // 4: scheduler.v1.ContainerConfig.health_check:type_name -> scheduler.v1.HealthCheck
-	0,  // 5: scheduler.v1.ContainerConfig.restart_policy:type_name -> scheduler.v1.RestartPolicy
-	9,  // 6: scheduler.v1.ApplicationStack.frontend:type_name -> scheduler.v1.FrontendConfig
-	10, // 7: scheduler.v1.ApplicationStack.backend:type_name -> scheduler.v1.BackendConfig
-	11, // 8: scheduler.v1.ApplicationStack.database:type_name -> scheduler.v1.DatabaseConfig
-	38, // 9: scheduler.v1.ApplicationStack.additional_services:type_name -> scheduler.v1.ApplicationStack.AdditionalServicesEntry
-	3,  // 10: scheduler.v1.FrontendConfig.container:type_name -> scheduler.v1.ContainerConfig
-	3,  // 11: scheduler.v1.BackendConfig.container:type_name -> scheduler.v1.ContainerConfig
-	39, // 12: scheduler.v1.BackendConfig.api_keys:type_name -> scheduler.v1.BackendConfig.ApiKeysEntry
-	3,  // 13: scheduler.v1.DatabaseConfig.container:type_name -> scheduler.v1.ContainerConfig
-	8,  // 14: scheduler.v1.EnvironmentSpecification.application_stack:type_name -> scheduler.v1.ApplicationStack
-	40, // 15: scheduler.v1.EnvironmentSpecification.labels:type_name -> scheduler.v1.EnvironmentSpecification.LabelsEntry
-	13, // 16: scheduler.v1.EnvironmentSpecification.network:type_name -> scheduler.v1.NetworkConfig
-	12, // 17: scheduler.v1.Environment.spec:type_name -> scheduler.v1.EnvironmentSpecification
-	1,  // 18: scheduler.v1.Environment.status:type_name -> scheduler.v1.EnvironmentStatus
-	42, // 19: scheduler.v1.Environment.created_at:type_name -> google.protobuf.Timestamp
-	42, // 20: scheduler.v1.Environment.updated_at:type_name -> google.protobuf.Timestamp
-	15, // 21: scheduler.v1.Environment.containers:type_name -> scheduler.v1.ContainerInstance
-	2,  // 22: scheduler.v1.ContainerInstance.status:type_name -> scheduler.v1.ContainerStatus
-	42, // 23: scheduler.v1.ContainerInstance.started_at:type_name -> google.protobuf.Timestamp
-	4,  // 24: scheduler.v1.ContainerInstance.exposed_ports:type_name -> scheduler.v1.PortMapping
-	12, // 25: scheduler.v1.CreateEnvironmentRequest.spec:type_name -> scheduler.v1.EnvironmentSpecification
-	14, // 26: scheduler.v1.CreateEnvironmentResponse.environment:type_name -> scheduler.v1.Environment
-	14, // 27: scheduler.v1.GetEnvironmentResponse.environment:type_name -> scheduler.v1.Environment
-	12, // 28: scheduler.v1.UpdateEnvironmentRequest.spec:type_name -> scheduler.v1.EnvironmentSpecification
-	14, // 29: scheduler.v1.UpdateEnvironmentResponse.environment:type_name -> scheduler.v1.Environment
-	41, // 30: scheduler.v1.ListEnvironmentsRequest.filters:type_name -> scheduler.v1.ListEnvironmentsRequest.FiltersEntry
-	14, // 31: scheduler.v1.ListEnvironmentsResponse.environments:type_name -> scheduler.v1.Environment
-	14, // 32: scheduler.v1.StartEnvironmentResponse.environment:type_name -> scheduler.v1.Environment
-	14, // 33: scheduler.v1.StopEnvironmentResponse.environment:type_name -> scheduler.v1.Environment
-	14, // 34: scheduler.v1.RestartEnvironmentResponse.environment:type_name -> scheduler.v1.Environment
-	14, // 35: scheduler.v1.GetEnvironmentStatusResponse.environment:type_name -> scheduler.v1.Environment
-	34, // 36: scheduler.v1.GetEnvironmentStatusResponse.container_metrics:type_name -> scheduler.v1.ContainerMetrics
-	42, // 37: scheduler.v1.GetEnvironmentLogsRequest.since:type_name -> google.protobuf.Timestamp
-	42, // 38: scheduler.v1.GetEnvironmentLogsResponse.timestamp:type_name -> google.protobuf.Timestamp
-	3,  // 39: scheduler.v1.ApplicationStack.AdditionalServicesEntry.value:type_name -> scheduler.v1.ContainerConfig
-	16, // 40: scheduler.v1.SchedulerService.CreateEnvironment:input_type -> scheduler.v1.CreateEnvironmentRequest
-	18, // 41: scheduler.v1.SchedulerService.GetEnvironment:input_type -> scheduler.v1.GetEnvironmentRequest
-	20, // 42: scheduler.v1.SchedulerService.UpdateEnvironment:input_type -> scheduler.v1.UpdateEnvironmentRequest
-	22, // 43: scheduler.v1.SchedulerService.DeleteEnvironment:input_type -> scheduler.v1.DeleteEnvironmentRequest
-	24, // 44: scheduler.v1.SchedulerService.ListEnvironments:input_type -> scheduler.v1.ListEnvironmentsRequest
-	26, // 45: scheduler.v1.SchedulerService.StartEnvironment:input_type -> scheduler.v1.StartEnvironmentRequest
-	28, // 46: scheduler.v1.SchedulerService.StopEnvironment:input_type -> scheduler.v1.StopEnvironmentRequest
-	30, // 47: scheduler.v1.SchedulerService.RestartEnvironment:input_type -> scheduler.v1.RestartEnvironmentRequest
-	32, // 48: scheduler.v1.SchedulerService.GetEnvironmentStatus:input_type -> scheduler.v1.GetEnvironmentStatusRequest
-	35, // 49: scheduler.v1.SchedulerService.GetEnvironmentLogs:input_type -> scheduler.v1.GetEnvironmentLogsRequest
-	17, // 50: scheduler.v1.SchedulerService.CreateEnvironment:output_type -> scheduler.v1.CreateEnvironmentResponse
-	19, // 51: scheduler.v1.SchedulerService.GetEnvironment:output_type -> scheduler.v1.GetEnvironmentResponse
-	21, // 52: scheduler.v1.SchedulerService.UpdateEnvironment:output_type -> scheduler.v1.UpdateEnvironmentResponse
-	23, // 53: scheduler.v1.SchedulerService.DeleteEnvironment:output_type -> scheduler.v1.DeleteEnvironmentResponse
-	25, // 54: scheduler.v1.SchedulerService.ListEnvironments:output_type -> scheduler.v1.ListEnvironmentsResponse
-	27, // 55: scheduler.v1.SchedulerService.StartEnvironment:output_type -> scheduler.v1.StartEnvironmentResponse
-	29, // 56: scheduler.v1.SchedulerService.StopEnvironment:output_type -> scheduler.v1.StopEnvironmentResponse
-	31, // 57: scheduler.v1.SchedulerService.RestartEnvironment:output_type -> scheduler.v1.RestartEnvironmentResponse
-	33, // 58: scheduler.v1.SchedulerService.GetEnvironmentStatus:output_type -> scheduler.v1.GetEnvironmentStatusResponse
-	36, // 59: scheduler.v1.SchedulerService.GetEnvironmentLogs:output_type -> scheduler.v1.GetEnvironmentLogsResponse
-	50, // [50:60] is the sub-list for method output_type
-	40, // [40:50] is the sub-list for method input_type
-	40, // [40:40] is the sub-list for extension type_name
-	40, // [40:40] is the sub-list for extension extendee
-	0,  // [0:40] is the sub-list for field type_name
+	14,  // 0: scheduler.v1.ContainerConfig.ports:type_name -> scheduler.v1.PortMapping
+	15,  // 1: scheduler.v1.ContainerConfig.volumes:type_name -> scheduler.v1.VolumeMount
+	124, // 2: scheduler.v1.ContainerConfig.environment_variables:type_name -> scheduler.v1.ContainerConfig.EnvironmentVariablesEntry
+	16,  // 3: scheduler.v1.ContainerConfig.resources:type_name -> scheduler.v1.ResourceLimits
+	17,  // 4: scheduler.v1.ContainerConfig.health_check:type_name -> scheduler.v1.HealthCheck
+	0,   // 5: scheduler.v1.ContainerConfig.restart_policy:type_name -> scheduler.v1.RestartPolicy
+	13,  // 6: scheduler.v1.ContainerConfig.secret_files:type_name -> scheduler.v1.SecretFile
+	11,  // 7: scheduler.v1.ContainerConfig.lifecycle:type_name -> scheduler.v1.LifecycleHooks
+	12,  // 8: scheduler.v1.LifecycleHooks.post_start:type_name -> scheduler.v1.ExecHook
+	12,  // 9: scheduler.v1.LifecycleHooks.pre_stop:type_name -> scheduler.v1.ExecHook
+	19,  // 10: scheduler.v1.ApplicationStack.frontend:type_name -> scheduler.v1.FrontendConfig
+	20,  // 11: scheduler.v1.ApplicationStack.backend:type_name -> scheduler.v1.BackendConfig
+	21,  // 12: scheduler.v1.ApplicationStack.database:type_name -> scheduler.v1.DatabaseConfig
+	125, // 13: scheduler.v1.ApplicationStack.additional_services:type_name -> scheduler.v1.ApplicationStack.AdditionalServicesEntry
+	10,  // 14: scheduler.v1.FrontendConfig.container:type_name -> scheduler.v1.ContainerConfig
+	10,  // 15: scheduler.v1.BackendConfig.container:type_name -> scheduler.v1.ContainerConfig
+	126, // 16: scheduler.v1.BackendConfig.api_keys:type_name -> scheduler.v1.BackendConfig.ApiKeysEntry
+	10,  // 17: scheduler.v1.DatabaseConfig.container:type_name -> scheduler.v1.ContainerConfig
+	24,  // 18: scheduler.v1.DatabaseConfig.backup_policy:type_name -> scheduler.v1.BackupPolicy
+	22,  // 19: scheduler.v1.DatabaseConfig.init_scripts:type_name -> scheduler.v1.DatabaseScript
+	23,  // 20: scheduler.v1.DatabaseConfig.migration:type_name -> scheduler.v1.DatabaseMigration
+	22,  // 21: scheduler.v1.DatabaseConfig.seed_data:type_name -> scheduler.v1.DatabaseScript
+	127, // 22: scheduler.v1.DatabaseMigration.environment_variables:type_name -> scheduler.v1.DatabaseMigration.EnvironmentVariablesEntry
+	18,  // 23: scheduler.v1.EnvironmentSpecification.application_stack:type_name -> scheduler.v1.ApplicationStack
+	128, // 24: scheduler.v1.EnvironmentSpecification.labels:type_name -> scheduler.v1.EnvironmentSpecification.LabelsEntry
+	28,  // 25: scheduler.v1.EnvironmentSpecification.network:type_name -> scheduler.v1.NetworkConfig
+	26,  // 26: scheduler.v1.EnvironmentSpecification.hooks:type_name -> scheduler.v1.DeployHooks
+	27,  // 27: scheduler.v1.DeployHooks.pre_deploy:type_name -> scheduler.v1.HookContainer
+	27,  // 28: scheduler.v1.DeployHooks.post_deploy:type_name -> scheduler.v1.HookContainer
+	129, // 29: scheduler.v1.HookContainer.environment_variables:type_name -> scheduler.v1.HookContainer.EnvironmentVariablesEntry
+	25,  // 30: scheduler.v1.Environment.spec:type_name -> scheduler.v1.EnvironmentSpecification
+	3,   // 31: scheduler.v1.Environment.status:type_name -> scheduler.v1.EnvironmentStatus
+	142, // 32: scheduler.v1.Environment.created_at:type_name -> google.protobuf.Timestamp
+	142, // 33: scheduler.v1.Environment.updated_at:type_name -> google.protobuf.Timestamp
+	32,  // 34: scheduler.v1.Environment.containers:type_name -> scheduler.v1.ContainerInstance
+	50,  // 35: scheduler.v1.Environment.template:type_name -> scheduler.v1.TemplateReference
+	31,  // 36: scheduler.v1.Environment.database_steps:type_name -> scheduler.v1.DatabaseStepResult
+	30,  // 37: scheduler.v1.Environment.hook_results:type_name -> scheduler.v1.HookResult
+	1,   // 38: scheduler.v1.HookResult.phase:type_name -> scheduler.v1.HookPhase
+	142, // 39: scheduler.v1.HookResult.completed_at:type_name -> google.protobuf.Timestamp
+	2,   // 40: scheduler.v1.DatabaseStepResult.step:type_name -> scheduler.v1.DatabaseStep
+	142, // 41: scheduler.v1.DatabaseStepResult.completed_at:type_name -> google.protobuf.Timestamp
+	4,   // 42: scheduler.v1.ContainerInstance.status:type_name -> scheduler.v1.ContainerStatus
+	142, // 43: scheduler.v1.ContainerInstance.started_at:type_name -> google.protobuf.Timestamp
+	14,  // 44: scheduler.v1.ContainerInstance.exposed_ports:type_name -> scheduler.v1.PortMapping
+	25,  // 45: scheduler.v1.CreateEnvironmentRequest.spec:type_name -> scheduler.v1.EnvironmentSpecification
+	29,  // 46: scheduler.v1.CreateEnvironmentResponse.environment:type_name -> scheduler.v1.Environment
+	29,  // 47: scheduler.v1.GetEnvironmentResponse.environment:type_name -> scheduler.v1.Environment
+	25,  // 48: scheduler.v1.UpdateEnvironmentRequest.spec:type_name -> scheduler.v1.EnvironmentSpecification
+	29,  // 49: scheduler.v1.UpdateEnvironmentResponse.environment:type_name -> scheduler.v1.Environment
+	130, // 50: scheduler.v1.ListEnvironmentsRequest.filters:type_name -> scheduler.v1.ListEnvironmentsRequest.FiltersEntry
+	29,  // 51: scheduler.v1.ListEnvironmentsResponse.environments:type_name -> scheduler.v1.Environment
+	29,  // 52: scheduler.v1.ApplyManifestResponse.environment:type_name -> scheduler.v1.Environment
+	5,   // 53: scheduler.v1.ApplyManifestResponse.action:type_name -> scheduler.v1.ManifestAction
+	45,  // 54: scheduler.v1.ApplyManifestResponse.changes:type_name -> scheduler.v1.FieldChange
+	6,   // 55: scheduler.v1.FieldChange.type:type_name -> scheduler.v1.ChangeType
+	25,  // 56: scheduler.v1.PlanEnvironmentUpdateRequest.spec:type_name -> scheduler.v1.EnvironmentSpecification
+	29,  // 57: scheduler.v1.PlanEnvironmentUpdateResponse.environment:type_name -> scheduler.v1.Environment
+	45,  // 58: scheduler.v1.PlanEnvironmentUpdateResponse.changes:type_name -> scheduler.v1.FieldChange
+	48,  // 59: scheduler.v1.PlanEnvironmentUpdateResponse.containers:type_name -> scheduler.v1.ContainerPlan
+	7,   // 60: scheduler.v1.ContainerPlan.action:type_name -> scheduler.v1.ContainerAction
+	45,  // 61: scheduler.v1.ContainerPlan.changes:type_name -> scheduler.v1.FieldChange
+	51,  // 62: scheduler.v1.Template.parameters:type_name -> scheduler.v1.TemplateParameter
+	142, // 63: scheduler.v1.Template.created_at:type_name -> google.protobuf.Timestamp
+	8,   // 64: scheduler.v1.TemplateParameter.type:type_name -> scheduler.v1.ParameterType
+	49,  // 65: scheduler.v1.ListTemplatesResponse.templates:type_name -> scheduler.v1.Template
+	49,  // 66: scheduler.v1.GetTemplateResponse.template:type_name -> scheduler.v1.Template
+	131, // 67: scheduler.v1.CreateEnvironmentFromTemplateRequest.parameters:type_name -> scheduler.v1.CreateEnvironmentFromTemplateRequest.ParametersEntry
+	132, // 68: scheduler.v1.CreateEnvironmentFromTemplateRequest.labels:type_name -> scheduler.v1.CreateEnvironmentFromTemplateRequest.LabelsEntry
+	29,  // 69: scheduler.v1.CreateEnvironmentFromTemplateResponse.environment:type_name -> scheduler.v1.Environment
+	49,  // 70: scheduler.v1.RegisterTemplateResponse.template:type_name -> scheduler.v1.Template
+	25,  // 71: scheduler.v1.EnvironmentRevision.spec:type_name -> scheduler.v1.EnvironmentSpecification
+	142, // 72: scheduler.v1.EnvironmentRevision.created_at:type_name -> google.protobuf.Timestamp
+	62,  // 73: scheduler.v1.ListEnvironmentRevisionsResponse.revisions:type_name -> scheduler.v1.EnvironmentRevision
+	62,  // 74: scheduler.v1.GetEnvironmentRevisionResponse.revision:type_name -> scheduler.v1.EnvironmentRevision
+	29,  // 75: scheduler.v1.RollbackEnvironmentResponse.environment:type_name -> scheduler.v1.Environment
+	29,  // 76: scheduler.v1.StartEnvironmentResponse.environment:type_name -> scheduler.v1.Environment
+	29,  // 77: scheduler.v1.StopEnvironmentResponse.environment:type_name -> scheduler.v1.Environment
+	29,  // 78: scheduler.v1.RestartEnvironmentResponse.environment:type_name -> scheduler.v1.Environment
+	29,  // 79: scheduler.v1.GetEnvironmentStatusResponse.environment:type_name -> scheduler.v1.Environment
+	77,  // 80: scheduler.v1.GetEnvironmentStatusResponse.container_metrics:type_name -> scheduler.v1.ContainerMetrics
+	142, // 81: scheduler.v1.GetEnvironmentLogsRequest.since:type_name -> google.protobuf.Timestamp
+	142, // 82: scheduler.v1.GetEnvironmentLogsResponse.timestamp:type_name -> google.protobuf.Timestamp
+	133, // 83: scheduler.v1.Volume.labels:type_name -> scheduler.v1.Volume.LabelsEntry
+	142, // 84: scheduler.v1.Volume.created_at:type_name -> google.protobuf.Timestamp
+	81,  // 85: scheduler.v1.Volume.snapshot_policy:type_name -> scheduler.v1.SnapshotPolicy
+	134, // 86: scheduler.v1.CreateVolumeRequest.labels:type_name -> scheduler.v1.CreateVolumeRequest.LabelsEntry
+	81,  // 87: scheduler.v1.CreateVolumeRequest.snapshot_policy:type_name -> scheduler.v1.SnapshotPolicy
+	80,  // 88: scheduler.v1.CreateVolumeResponse.volume:type_name -> scheduler.v1.Volume
+	80,  // 89: scheduler.v1.GetVolumeResponse.volume:type_name -> scheduler.v1.Volume
+	135, // 90: scheduler.v1.ListVolumesRequest.filters:type_name -> scheduler.v1.ListVolumesRequest.FiltersEntry
+	80,  // 91: scheduler.v1.ListVolumesResponse.volumes:type_name -> scheduler.v1.Volume
+	136, // 92: scheduler.v1.UpdateVolumeRequest.labels:type_name -> scheduler.v1.UpdateVolumeRequest.LabelsEntry
+	81,  // 93: scheduler.v1.UpdateVolumeRequest.snapshot_policy:type_name -> scheduler.v1.SnapshotPolicy
+	80,  // 94: scheduler.v1.UpdateVolumeResponse.volume:type_name -> scheduler.v1.Volume
+	9,   // 95: scheduler.v1.VolumeSnapshot.method:type_name -> scheduler.v1.SnapshotMethod
+	137, // 96: scheduler.v1.VolumeSnapshot.labels:type_name -> scheduler.v1.VolumeSnapshot.LabelsEntry
+	142, // 97: scheduler.v1.VolumeSnapshot.created_at:type_name -> google.protobuf.Timestamp
+	9,   // 98: scheduler.v1.SnapshotVolumeRequest.method:type_name -> scheduler.v1.SnapshotMethod
+	138, // 99: scheduler.v1.SnapshotVolumeRequest.labels:type_name -> scheduler.v1.SnapshotVolumeRequest.LabelsEntry
+	92,  // 100: scheduler.v1.SnapshotVolumeResponse.snapshot:type_name -> scheduler.v1.VolumeSnapshot
+	92,  // 101: scheduler.v1.ListVolumeSnapshotsResponse.snapshots:type_name -> scheduler.v1.VolumeSnapshot
+	80,  // 102: scheduler.v1.RestoreVolumeResponse.volume:type_name -> scheduler.v1.Volume
+	142, // 103: scheduler.v1.DatabaseBackup.created_at:type_name -> google.protobuf.Timestamp
+	99,  // 104: scheduler.v1.BackupDatabaseResponse.backup:type_name -> scheduler.v1.DatabaseBackup
+	99,  // 105: scheduler.v1.ListDatabaseBackupsResponse.backups:type_name -> scheduler.v1.DatabaseBackup
+	29,  // 106: scheduler.v1.RestoreDatabaseResponse.environment:type_name -> scheduler.v1.Environment
+	139, // 107: scheduler.v1.Secret.labels:type_name -> scheduler.v1.Secret.LabelsEntry
+	142, // 108: scheduler.v1.Secret.created_at:type_name -> google.protobuf.Timestamp
+	140, // 109: scheduler.v1.CreateSecretRequest.labels:type_name -> scheduler.v1.CreateSecretRequest.LabelsEntry
+	106, // 110: scheduler.v1.CreateSecretResponse.secret:type_name -> scheduler.v1.Secret
+	141, // 111: scheduler.v1.ListSecretsRequest.filters:type_name -> scheduler.v1.ListSecretsRequest.FiltersEntry
+	106, // 112: scheduler.v1.ListSecretsResponse.secrets:type_name -> scheduler.v1.Secret
+	113, // 113: scheduler.v1.QuotaUsage.used:type_name -> scheduler.v1.ResourceQuota
+	113, // 114: scheduler.v1.QuotaUsage.limit:type_name -> scheduler.v1.ResourceQuota
+	114, // 115: scheduler.v1.GetQuotaUsageResponse.tenant_usage:type_name -> scheduler.v1.QuotaUsage
+	114, // 116: scheduler.v1.GetQuotaUsageResponse.host_usage:type_name -> scheduler.v1.QuotaUsage
+	16,  // 117: scheduler.v1.NodeInfo.capacity:type_name -> scheduler.v1.ResourceLimits
+	16,  // 118: scheduler.v1.NodeInfo.allocatable:type_name -> scheduler.v1.ResourceLimits
+	16,  // 119: scheduler.v1.NodeInfo.reserved:type_name -> scheduler.v1.ResourceLimits
+	117, // 120: scheduler.v1.NodeInfo.overcommit:type_name -> scheduler.v1.OvercommitRatios
+	118, // 121: scheduler.v1.GetNodeInfoResponse.node:type_name -> scheduler.v1.NodeInfo
+	142, // 122: scheduler.v1.AuditEvent.time:type_name -> google.protobuf.Timestamp
+	142, // 123: scheduler.v1.ListAuditEventsRequest.since:type_name -> google.protobuf.Timestamp
+	142, // 124: scheduler.v1.ListAuditEventsRequest.until:type_name -> google.protobuf.Timestamp
+	121, // 125: scheduler.v1.ListAuditEventsResponse.events:type_name -> scheduler.v1.AuditEvent
+	10,  // 126: scheduler.v1.ApplicationStack.AdditionalServicesEntry.value:type_name -> scheduler.v1.ContainerConfig
+	33,  // 127: scheduler.v1.SchedulerService.CreateEnvironment:input_type -> scheduler.v1.CreateEnvironmentRequest
+	35,  // 128: scheduler.v1.SchedulerService.GetEnvironment:input_type -> scheduler.v1.GetEnvironmentRequest
+	37,  // 129: scheduler.v1.SchedulerService.UpdateEnvironment:input_type -> scheduler.v1.UpdateEnvironmentRequest
+	39,  // 130: scheduler.v1.SchedulerService.DeleteEnvironment:input_type -> scheduler.v1.DeleteEnvironmentRequest
+	41,  // 131: scheduler.v1.SchedulerService.ListEnvironments:input_type -> scheduler.v1.ListEnvironmentsRequest
+	43,  // 132: scheduler.v1.SchedulerService.ApplyManifest:input_type -> scheduler.v1.ApplyManifestRequest
+	46,  // 133: scheduler.v1.SchedulerService.PlanEnvironmentUpdate:input_type -> scheduler.v1.PlanEnvironmentUpdateRequest
+	52,  // 134: scheduler.v1.SchedulerService.ListTemplates:input_type -> scheduler.v1.ListTemplatesRequest
+	54,  // 135: scheduler.v1.SchedulerService.GetTemplate:input_type -> scheduler.v1.GetTemplateRequest
+	56,  // 136: scheduler.v1.SchedulerService.CreateEnvironmentFromTemplate:input_type -> scheduler.v1.CreateEnvironmentFromTemplateRequest
+	58,  // 137: scheduler.v1.SchedulerService.RegisterTemplate:input_type -> scheduler.v1.RegisterTemplateRequest
+	60,  // 138: scheduler.v1.SchedulerService.DeleteTemplate:input_type -> scheduler.v1.DeleteTemplateRequest
+	63,  // 139: scheduler.v1.SchedulerService.ListEnvironmentRevisions:input_type -> scheduler.v1.ListEnvironmentRevisionsRequest
+	65,  // 140: scheduler.v1.SchedulerService.GetEnvironmentRevision:input_type -> scheduler.v1.GetEnvironmentRevisionRequest
+	67,  // 141: scheduler.v1.SchedulerService.RollbackEnvironment:input_type -> scheduler.v1.RollbackEnvironmentRequest
+	69,  // 142: scheduler.v1.SchedulerService.StartEnvironment:input_type -> scheduler.v1.StartEnvironmentRequest
+	71,  // 143: scheduler.v1.SchedulerService.StopEnvironment:input_type -> scheduler.v1.StopEnvironmentRequest
+	73,  // 144: scheduler.v1.SchedulerService.RestartEnvironment:input_type -> scheduler.v1.RestartEnvironmentRequest
+	75,  // 145: scheduler.v1.SchedulerService.GetEnvironmentStatus:input_type -> scheduler.v1.GetEnvironmentStatusRequest
+	78,  // 146: scheduler.v1.SchedulerService.GetEnvironmentLogs:input_type -> scheduler.v1.GetEnvironmentLogsRequest
+	82,  // 147: scheduler.v1.SchedulerService.CreateVolume:input_type -> scheduler.v1.CreateVolumeRequest
+	84,  // 148: scheduler.v1.SchedulerService.GetVolume:input_type -> scheduler.v1.GetVolumeRequest
+	86,  // 149: scheduler.v1.SchedulerService.ListVolumes:input_type -> scheduler.v1.ListVolumesRequest
+	88,  // 150: scheduler.v1.SchedulerService.UpdateVolume:input_type -> scheduler.v1.UpdateVolumeRequest
+	90,  // 151: scheduler.v1.SchedulerService.DeleteVolume:input_type -> scheduler.v1.DeleteVolumeRequest
+	93,  // 152: scheduler.v1.SchedulerService.SnapshotVolume:input_type -> scheduler.v1.SnapshotVolumeRequest
+	95,  // 153: scheduler.v1.SchedulerService.ListVolumeSnapshots:input_type -> scheduler.v1.ListVolumeSnapshotsRequest
+	97,  // 154: scheduler.v1.SchedulerService.RestoreVolume:input_type -> scheduler.v1.RestoreVolumeRequest
+	100, // 155: scheduler.v1.SchedulerService.BackupDatabase:input_type -> scheduler.v1.BackupDatabaseRequest
+	102, // 156: scheduler.v1.SchedulerService.ListDatabaseBackups:input_type -> scheduler.v1.ListDatabaseBackupsRequest
+	104, // 157: scheduler.v1.SchedulerService.RestoreDatabase:input_type -> scheduler.v1.RestoreDatabaseRequest
+	107, // 158: scheduler.v1.SchedulerService.CreateSecret:input_type -> scheduler.v1.CreateSecretRequest
+	109, // 159: scheduler.v1.SchedulerService.ListSecrets:input_type -> scheduler.v1.ListSecretsRequest
+	111, // 160: scheduler.v1.SchedulerService.DeleteSecret:input_type -> scheduler.v1.DeleteSecretRequest
+	115, // 161: scheduler.v1.SchedulerService.GetQuotaUsage:input_type -> scheduler.v1.GetQuotaUsageRequest
+	119, // 162: scheduler.v1.SchedulerService.GetNodeInfo:input_type -> scheduler.v1.GetNodeInfoRequest
+	122, // 163: scheduler.v1.SchedulerService.ListAuditEvents:input_type -> scheduler.v1.ListAuditEventsRequest
+	34,  // 164: scheduler.v1.SchedulerService.CreateEnvironment:output_type -> scheduler.v1.CreateEnvironmentResponse
+	36,  // 165: scheduler.v1.SchedulerService.GetEnvironment:output_type -> scheduler.v1.GetEnvironmentResponse
+	38,  // 166: scheduler.v1.SchedulerService.UpdateEnvironment:output_type -> scheduler.v1.UpdateEnvironmentResponse
+	40,  // 167: scheduler.v1.SchedulerService.DeleteEnvironment:output_type -> scheduler.v1.DeleteEnvironmentResponse
+	42,  // 168: scheduler.v1.SchedulerService.ListEnvironments:output_type -> scheduler.v1.ListEnvironmentsResponse
+	44,  // 169: scheduler.v1.SchedulerService.ApplyManifest:output_type -> scheduler.v1.ApplyManifestResponse
+	47,  // 170: scheduler.v1.SchedulerService.PlanEnvironmentUpdate:output_type -> scheduler.v1.PlanEnvironmentUpdateResponse
+	53,  // 171: scheduler.v1.SchedulerService.ListTemplates:output_type -> scheduler.v1.ListTemplatesResponse
+	55,  // 172: scheduler.v1.SchedulerService.GetTemplate:output_type -> scheduler.v1.GetTemplateResponse
+	57,  // 173: scheduler.v1.SchedulerService.CreateEnvironmentFromTemplate:output_type -> scheduler.v1.CreateEnvironmentFromTemplateResponse
+	59,  // 174: scheduler.v1.SchedulerService.RegisterTemplate:output_type -> scheduler.v1.RegisterTemplateResponse
+	61,  // 175: scheduler.v1.SchedulerService.DeleteTemplate:output_type -> scheduler.v1.DeleteTemplateResponse
+	64,  // 176: scheduler.v1.SchedulerService.ListEnvironmentRevisions:output_type -> scheduler.v1.ListEnvironmentRevisionsResponse
+	66,  // 177: scheduler.v1.SchedulerService.GetEnvironmentRevision:output_type -> scheduler.v1.GetEnvironmentRevisionResponse
+	68,  // 178: scheduler.v1.SchedulerService.RollbackEnvironment:output_type -> scheduler.v1.RollbackEnvironmentResponse
+	70,  // 179: scheduler.v1.SchedulerService.StartEnvironment:output_type -> scheduler.v1.StartEnvironmentResponse
+	72,  // 180: scheduler.v1.SchedulerService.StopEnvironment:output_type -> scheduler.v1.StopEnvironmentResponse
+	74,  // 181: scheduler.v1.SchedulerService.RestartEnvironment:output_type -> scheduler.v1.RestartEnvironmentResponse
+	76,  // 182: scheduler.v1.SchedulerService.GetEnvironmentStatus:output_type -> scheduler.v1.GetEnvironmentStatusResponse
+	79,  // 183: scheduler.v1.SchedulerService.GetEnvironmentLogs:output_type -> scheduler.v1.GetEnvironmentLogsResponse
+	83,  // 184: scheduler.v1.SchedulerService.CreateVolume:output_type -> scheduler.v1.CreateVolumeResponse
+	85,  // 185: scheduler.v1.SchedulerService.GetVolume:output_type -> scheduler.v1.GetVolumeResponse
+	87,  // 186: scheduler.v1.SchedulerService.ListVolumes:output_type -> scheduler.v1.ListVolumesResponse
+	89,  // 187: scheduler.v1.SchedulerService.UpdateVolume:output_type -> scheduler.v1.UpdateVolumeResponse
+	91,  // 188: scheduler.v1.SchedulerService.DeleteVolume:output_type -> scheduler.v1.DeleteVolumeResponse
+	94,  // 189: scheduler.v1.SchedulerService.SnapshotVolume:output_type -> scheduler.v1.SnapshotVolumeResponse
+	96,  // 190: scheduler.v1.SchedulerService.ListVolumeSnapshots:output_type -> scheduler.v1.ListVolumeSnapshotsResponse
+	98,  // 191: scheduler.v1.SchedulerService.RestoreVolume:output_type -> scheduler.v1.RestoreVolumeResponse
+	101, // 192: scheduler.v1.SchedulerService.BackupDatabase:output_type -> scheduler.v1.BackupDatabaseResponse
+	103, // 193: scheduler.v1.SchedulerService.ListDatabaseBackups:output_type -> scheduler.v1.ListDatabaseBackupsResponse
+	105, // 194: scheduler.v1.SchedulerService.RestoreDatabase:output_type -> scheduler.v1.RestoreDatabaseResponse
+	108, // 195: scheduler.v1.SchedulerService.CreateSecret:output_type -> scheduler.v1.CreateSecretResponse
+	110, // 196: scheduler.v1.SchedulerService.ListSecrets:output_type -> scheduler.v1.ListSecretsResponse
+	112, // 197: scheduler.v1.SchedulerService.DeleteSecret:output_type -> scheduler.v1.DeleteSecretResponse
+	116, // 198: scheduler.v1.SchedulerService.GetQuotaUsage:output_type -> scheduler.v1.GetQuotaUsageResponse
+	120, // 199: scheduler.v1.SchedulerService.GetNodeInfo:output_type -> scheduler.v1.GetNodeInfoResponse
+	123, // 200: scheduler.v1.SchedulerService.ListAuditEvents:output_type -> scheduler.v1.ListAuditEventsResponse
+	164, // [164:201] is the sub-list for method output_type
+	127, // [127:164] is the sub-list for method input_type
+	127, // [127:127] is the sub-list for extension type_name
+	127, // [127:127] is the sub-list for extension extendee
+	0,   // [0:127] is the sub-list for field type_name
 }
 
 func init() { file_scheduler_proto_init() }
@@ -2642,13 +8508,14 @@ func file_scheduler_proto_init() {
 	if File_scheduler_proto != nil {
 		return
 	}
+	file_options_proto_init()
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_scheduler_proto_rawDesc), len(file_scheduler_proto_rawDesc)),
-			NumEnums:      3,
-			NumMessages:   39,
+			NumEnums:      10,
+			NumMessages:   132,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
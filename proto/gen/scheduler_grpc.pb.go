@@ -19,16 +19,43 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	SchedulerService_CreateEnvironment_FullMethodName    = "/scheduler.v1.SchedulerService/CreateEnvironment"
-	SchedulerService_GetEnvironment_FullMethodName       = "/scheduler.v1.SchedulerService/GetEnvironment"
-	SchedulerService_UpdateEnvironment_FullMethodName    = "/scheduler.v1.SchedulerService/UpdateEnvironment"
-	SchedulerService_DeleteEnvironment_FullMethodName    = "/scheduler.v1.SchedulerService/DeleteEnvironment"
-	SchedulerService_ListEnvironments_FullMethodName     = "/scheduler.v1.SchedulerService/ListEnvironments"
-	SchedulerService_StartEnvironment_FullMethodName     = "/scheduler.v1.SchedulerService/StartEnvironment"
-	SchedulerService_StopEnvironment_FullMethodName      = "/scheduler.v1.SchedulerService/StopEnvironment"
-	SchedulerService_RestartEnvironment_FullMethodName   = "/scheduler.v1.SchedulerService/RestartEnvironment"
-	SchedulerService_GetEnvironmentStatus_FullMethodName = "/scheduler.v1.SchedulerService/GetEnvironmentStatus"
-	SchedulerService_GetEnvironmentLogs_FullMethodName   = "/scheduler.v1.SchedulerService/GetEnvironmentLogs"
+	SchedulerService_CreateEnvironment_FullMethodName             = "/scheduler.v1.SchedulerService/CreateEnvironment"
+	SchedulerService_GetEnvironment_FullMethodName                = "/scheduler.v1.SchedulerService/GetEnvironment"
+	SchedulerService_UpdateEnvironment_FullMethodName             = "/scheduler.v1.SchedulerService/UpdateEnvironment"
+	SchedulerService_DeleteEnvironment_FullMethodName             = "/scheduler.v1.SchedulerService/DeleteEnvironment"
+	SchedulerService_ListEnvironments_FullMethodName              = "/scheduler.v1.SchedulerService/ListEnvironments"
+	SchedulerService_ApplyManifest_FullMethodName                 = "/scheduler.v1.SchedulerService/ApplyManifest"
+	SchedulerService_PlanEnvironmentUpdate_FullMethodName         = "/scheduler.v1.SchedulerService/PlanEnvironmentUpdate"
+	SchedulerService_ListTemplates_FullMethodName                 = "/scheduler.v1.SchedulerService/ListTemplates"
+	SchedulerService_GetTemplate_FullMethodName                   = "/scheduler.v1.SchedulerService/GetTemplate"
+	SchedulerService_CreateEnvironmentFromTemplate_FullMethodName = "/scheduler.v1.SchedulerService/CreateEnvironmentFromTemplate"
+	SchedulerService_RegisterTemplate_FullMethodName              = "/scheduler.v1.SchedulerService/RegisterTemplate"
+	SchedulerService_DeleteTemplate_FullMethodName                = "/scheduler.v1.SchedulerService/DeleteTemplate"
+	SchedulerService_ListEnvironmentRevisions_FullMethodName      = "/scheduler.v1.SchedulerService/ListEnvironmentRevisions"
+	SchedulerService_GetEnvironmentRevision_FullMethodName        = "/scheduler.v1.SchedulerService/GetEnvironmentRevision"
+	SchedulerService_RollbackEnvironment_FullMethodName           = "/scheduler.v1.SchedulerService/RollbackEnvironment"
+	SchedulerService_StartEnvironment_FullMethodName              = "/scheduler.v1.SchedulerService/StartEnvironment"
+	SchedulerService_StopEnvironment_FullMethodName               = "/scheduler.v1.SchedulerService/StopEnvironment"
+	SchedulerService_RestartEnvironment_FullMethodName            = "/scheduler.v1.SchedulerService/RestartEnvironment"
+	SchedulerService_GetEnvironmentStatus_FullMethodName          = "/scheduler.v1.SchedulerService/GetEnvironmentStatus"
+	SchedulerService_GetEnvironmentLogs_FullMethodName            = "/scheduler.v1.SchedulerService/GetEnvironmentLogs"
+	SchedulerService_CreateVolume_FullMethodName                  = "/scheduler.v1.SchedulerService/CreateVolume"
+	SchedulerService_GetVolume_FullMethodName                     = "/scheduler.v1.SchedulerService/GetVolume"
+	SchedulerService_ListVolumes_FullMethodName                   = "/scheduler.v1.SchedulerService/ListVolumes"
+	SchedulerService_UpdateVolume_FullMethodName                  = "/scheduler.v1.SchedulerService/UpdateVolume"
+	SchedulerService_DeleteVolume_FullMethodName                  = "/scheduler.v1.SchedulerService/DeleteVolume"
+	SchedulerService_SnapshotVolume_FullMethodName                = "/scheduler.v1.SchedulerService/SnapshotVolume"
+	SchedulerService_ListVolumeSnapshots_FullMethodName           = "/scheduler.v1.SchedulerService/ListVolumeSnapshots"
+	SchedulerService_RestoreVolume_FullMethodName                 = "/scheduler.v1.SchedulerService/RestoreVolume"
+	SchedulerService_BackupDatabase_FullMethodName                = "/scheduler.v1.SchedulerService/BackupDatabase"
+	SchedulerService_ListDatabaseBackups_FullMethodName           = "/scheduler.v1.SchedulerService/ListDatabaseBackups"
+	SchedulerService_RestoreDatabase_FullMethodName               = "/scheduler.v1.SchedulerService/RestoreDatabase"
+	SchedulerService_CreateSecret_FullMethodName                  = "/scheduler.v1.SchedulerService/CreateSecret"
+	SchedulerService_ListSecrets_FullMethodName                   = "/scheduler.v1.SchedulerService/ListSecrets"
+	SchedulerService_DeleteSecret_FullMethodName                  = "/scheduler.v1.SchedulerService/DeleteSecret"
+	SchedulerService_GetQuotaUsage_FullMethodName                 = "/scheduler.v1.SchedulerService/GetQuotaUsage"
+	SchedulerService_GetNodeInfo_FullMethodName                   = "/scheduler.v1.SchedulerService/GetNodeInfo"
+	SchedulerService_ListAuditEvents_FullMethodName               = "/scheduler.v1.SchedulerService/ListAuditEvents"
 )
 
 // SchedulerServiceClient is the client API for SchedulerService service.
@@ -43,13 +70,56 @@ type SchedulerServiceClient interface {
 	UpdateEnvironment(ctx context.Context, in *UpdateEnvironmentRequest, opts ...grpc.CallOption) (*UpdateEnvironmentResponse, error)
 	DeleteEnvironment(ctx context.Context, in *DeleteEnvironmentRequest, opts ...grpc.CallOption) (*DeleteEnvironmentResponse, error)
 	ListEnvironments(ctx context.Context, in *ListEnvironmentsRequest, opts ...grpc.CallOption) (*ListEnvironmentsResponse, error)
-	// Environment lifecycle operations
+	// Manifest operations
+	ApplyManifest(ctx context.Context, in *ApplyManifestRequest, opts ...grpc.CallOption) (*ApplyManifestResponse, error)
+	// Plan operations
+	PlanEnvironmentUpdate(ctx context.Context, in *PlanEnvironmentUpdateRequest, opts ...grpc.CallOption) (*PlanEnvironmentUpdateResponse, error)
+	// Template operations
+	ListTemplates(ctx context.Context, in *ListTemplatesRequest, opts ...grpc.CallOption) (*ListTemplatesResponse, error)
+	GetTemplate(ctx context.Context, in *GetTemplateRequest, opts ...grpc.CallOption) (*GetTemplateResponse, error)
+	CreateEnvironmentFromTemplate(ctx context.Context, in *CreateEnvironmentFromTemplateRequest, opts ...grpc.CallOption) (*CreateEnvironmentFromTemplateResponse, error)
+	RegisterTemplate(ctx context.Context, in *RegisterTemplateRequest, opts ...grpc.CallOption) (*RegisterTemplateResponse, error)
+	DeleteTemplate(ctx context.Context, in *DeleteTemplateRequest, opts ...grpc.CallOption) (*DeleteTemplateResponse, error)
+	// Revision operations
+	ListEnvironmentRevisions(ctx context.Context, in *ListEnvironmentRevisionsRequest, opts ...grpc.CallOption) (*ListEnvironmentRevisionsResponse, error)
+	GetEnvironmentRevision(ctx context.Context, in *GetEnvironmentRevisionRequest, opts ...grpc.CallOption) (*GetEnvironmentRevisionResponse, error)
+	RollbackEnvironment(ctx context.Context, in *RollbackEnvironmentRequest, opts ...grpc.CallOption) (*RollbackEnvironmentResponse, error)
+	// Environment lifecycle operations. They run containers, so until
+	// `scheduler run` is given a container runtime they fail with
+	// FAILED_PRECONDITION, as do updates of running environments.
 	StartEnvironment(ctx context.Context, in *StartEnvironmentRequest, opts ...grpc.CallOption) (*StartEnvironmentResponse, error)
 	StopEnvironment(ctx context.Context, in *StopEnvironmentRequest, opts ...grpc.CallOption) (*StopEnvironmentResponse, error)
 	RestartEnvironment(ctx context.Context, in *RestartEnvironmentRequest, opts ...grpc.CallOption) (*RestartEnvironmentResponse, error)
 	// Monitoring operations
 	GetEnvironmentStatus(ctx context.Context, in *GetEnvironmentStatusRequest, opts ...grpc.CallOption) (*GetEnvironmentStatusResponse, error)
+	// Fails with FAILED_PRECONDITION without a container runtime
 	GetEnvironmentLogs(ctx context.Context, in *GetEnvironmentLogsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[GetEnvironmentLogsResponse], error)
+	// Volume operations
+	CreateVolume(ctx context.Context, in *CreateVolumeRequest, opts ...grpc.CallOption) (*CreateVolumeResponse, error)
+	GetVolume(ctx context.Context, in *GetVolumeRequest, opts ...grpc.CallOption) (*GetVolumeResponse, error)
+	ListVolumes(ctx context.Context, in *ListVolumesRequest, opts ...grpc.CallOption) (*ListVolumesResponse, error)
+	UpdateVolume(ctx context.Context, in *UpdateVolumeRequest, opts ...grpc.CallOption) (*UpdateVolumeResponse, error)
+	DeleteVolume(ctx context.Context, in *DeleteVolumeRequest, opts ...grpc.CallOption) (*DeleteVolumeResponse, error)
+	// Volume snapshot operations
+	SnapshotVolume(ctx context.Context, in *SnapshotVolumeRequest, opts ...grpc.CallOption) (*SnapshotVolumeResponse, error)
+	ListVolumeSnapshots(ctx context.Context, in *ListVolumeSnapshotsRequest, opts ...grpc.CallOption) (*ListVolumeSnapshotsResponse, error)
+	RestoreVolume(ctx context.Context, in *RestoreVolumeRequest, opts ...grpc.CallOption) (*RestoreVolumeResponse, error)
+	// Database backup operations. Backups and restores run pg_dump and
+	// pg_restore in the database container and fail with FAILED_PRECONDITION
+	// without a container runtime.
+	BackupDatabase(ctx context.Context, in *BackupDatabaseRequest, opts ...grpc.CallOption) (*BackupDatabaseResponse, error)
+	ListDatabaseBackups(ctx context.Context, in *ListDatabaseBackupsRequest, opts ...grpc.CallOption) (*ListDatabaseBackupsResponse, error)
+	RestoreDatabase(ctx context.Context, in *RestoreDatabaseRequest, opts ...grpc.CallOption) (*RestoreDatabaseResponse, error)
+	// Secret operations
+	CreateSecret(ctx context.Context, in *CreateSecretRequest, opts ...grpc.CallOption) (*CreateSecretResponse, error)
+	ListSecrets(ctx context.Context, in *ListSecretsRequest, opts ...grpc.CallOption) (*ListSecretsResponse, error)
+	DeleteSecret(ctx context.Context, in *DeleteSecretRequest, opts ...grpc.CallOption) (*DeleteSecretResponse, error)
+	// Quota operations
+	GetQuotaUsage(ctx context.Context, in *GetQuotaUsageRequest, opts ...grpc.CallOption) (*GetQuotaUsageResponse, error)
+	// Node operations
+	GetNodeInfo(ctx context.Context, in *GetNodeInfoRequest, opts ...grpc.CallOption) (*GetNodeInfoResponse, error)
+	// Audit operations
+	ListAuditEvents(ctx context.Context, in *ListAuditEventsRequest, opts ...grpc.CallOption) (*ListAuditEventsResponse, error)
 }
 
 type schedulerServiceClient struct {
@@ -110,6 +180,106 @@ func (c *schedulerServiceClient) ListEnvironments(ctx context.Context, in *ListE
 	return out, nil
 }
 
+func (c *schedulerServiceClient) ApplyManifest(ctx context.Context, in *ApplyManifestRequest, opts ...grpc.CallOption) (*ApplyManifestResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ApplyManifestResponse)
+	err := c.cc.Invoke(ctx, SchedulerService_ApplyManifest_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schedulerServiceClient) PlanEnvironmentUpdate(ctx context.Context, in *PlanEnvironmentUpdateRequest, opts ...grpc.CallOption) (*PlanEnvironmentUpdateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PlanEnvironmentUpdateResponse)
+	err := c.cc.Invoke(ctx, SchedulerService_PlanEnvironmentUpdate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schedulerServiceClient) ListTemplates(ctx context.Context, in *ListTemplatesRequest, opts ...grpc.CallOption) (*ListTemplatesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListTemplatesResponse)
+	err := c.cc.Invoke(ctx, SchedulerService_ListTemplates_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schedulerServiceClient) GetTemplate(ctx context.Context, in *GetTemplateRequest, opts ...grpc.CallOption) (*GetTemplateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetTemplateResponse)
+	err := c.cc.Invoke(ctx, SchedulerService_GetTemplate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schedulerServiceClient) CreateEnvironmentFromTemplate(ctx context.Context, in *CreateEnvironmentFromTemplateRequest, opts ...grpc.CallOption) (*CreateEnvironmentFromTemplateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateEnvironmentFromTemplateResponse)
+	err := c.cc.Invoke(ctx, SchedulerService_CreateEnvironmentFromTemplate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schedulerServiceClient) RegisterTemplate(ctx context.Context, in *RegisterTemplateRequest, opts ...grpc.CallOption) (*RegisterTemplateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RegisterTemplateResponse)
+	err := c.cc.Invoke(ctx, SchedulerService_RegisterTemplate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schedulerServiceClient) DeleteTemplate(ctx context.Context, in *DeleteTemplateRequest, opts ...grpc.CallOption) (*DeleteTemplateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteTemplateResponse)
+	err := c.cc.Invoke(ctx, SchedulerService_DeleteTemplate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schedulerServiceClient) ListEnvironmentRevisions(ctx context.Context, in *ListEnvironmentRevisionsRequest, opts ...grpc.CallOption) (*ListEnvironmentRevisionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListEnvironmentRevisionsResponse)
+	err := c.cc.Invoke(ctx, SchedulerService_ListEnvironmentRevisions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schedulerServiceClient) GetEnvironmentRevision(ctx context.Context, in *GetEnvironmentRevisionRequest, opts ...grpc.CallOption) (*GetEnvironmentRevisionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetEnvironmentRevisionResponse)
+	err := c.cc.Invoke(ctx, SchedulerService_GetEnvironmentRevision_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schedulerServiceClient) RollbackEnvironment(ctx context.Context, in *RollbackEnvironmentRequest, opts ...grpc.CallOption) (*RollbackEnvironmentResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RollbackEnvironmentResponse)
+	err := c.cc.Invoke(ctx, SchedulerService_RollbackEnvironment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *schedulerServiceClient) StartEnvironment(ctx context.Context, in *StartEnvironmentRequest, opts ...grpc.CallOption) (*StartEnvironmentResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(StartEnvironmentResponse)
@@ -169,6 +339,176 @@ func (c *schedulerServiceClient) GetEnvironmentLogs(ctx context.Context, in *Get
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type SchedulerService_GetEnvironmentLogsClient = grpc.ServerStreamingClient[GetEnvironmentLogsResponse]
 
+func (c *schedulerServiceClient) CreateVolume(ctx context.Context, in *CreateVolumeRequest, opts ...grpc.CallOption) (*CreateVolumeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateVolumeResponse)
+	err := c.cc.Invoke(ctx, SchedulerService_CreateVolume_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schedulerServiceClient) GetVolume(ctx context.Context, in *GetVolumeRequest, opts ...grpc.CallOption) (*GetVolumeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetVolumeResponse)
+	err := c.cc.Invoke(ctx, SchedulerService_GetVolume_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schedulerServiceClient) ListVolumes(ctx context.Context, in *ListVolumesRequest, opts ...grpc.CallOption) (*ListVolumesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListVolumesResponse)
+	err := c.cc.Invoke(ctx, SchedulerService_ListVolumes_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schedulerServiceClient) UpdateVolume(ctx context.Context, in *UpdateVolumeRequest, opts ...grpc.CallOption) (*UpdateVolumeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateVolumeResponse)
+	err := c.cc.Invoke(ctx, SchedulerService_UpdateVolume_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schedulerServiceClient) DeleteVolume(ctx context.Context, in *DeleteVolumeRequest, opts ...grpc.CallOption) (*DeleteVolumeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteVolumeResponse)
+	err := c.cc.Invoke(ctx, SchedulerService_DeleteVolume_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schedulerServiceClient) SnapshotVolume(ctx context.Context, in *SnapshotVolumeRequest, opts ...grpc.CallOption) (*SnapshotVolumeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SnapshotVolumeResponse)
+	err := c.cc.Invoke(ctx, SchedulerService_SnapshotVolume_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schedulerServiceClient) ListVolumeSnapshots(ctx context.Context, in *ListVolumeSnapshotsRequest, opts ...grpc.CallOption) (*ListVolumeSnapshotsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListVolumeSnapshotsResponse)
+	err := c.cc.Invoke(ctx, SchedulerService_ListVolumeSnapshots_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schedulerServiceClient) RestoreVolume(ctx context.Context, in *RestoreVolumeRequest, opts ...grpc.CallOption) (*RestoreVolumeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RestoreVolumeResponse)
+	err := c.cc.Invoke(ctx, SchedulerService_RestoreVolume_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schedulerServiceClient) BackupDatabase(ctx context.Context, in *BackupDatabaseRequest, opts ...grpc.CallOption) (*BackupDatabaseResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BackupDatabaseResponse)
+	err := c.cc.Invoke(ctx, SchedulerService_BackupDatabase_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schedulerServiceClient) ListDatabaseBackups(ctx context.Context, in *ListDatabaseBackupsRequest, opts ...grpc.CallOption) (*ListDatabaseBackupsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListDatabaseBackupsResponse)
+	err := c.cc.Invoke(ctx, SchedulerService_ListDatabaseBackups_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schedulerServiceClient) RestoreDatabase(ctx context.Context, in *RestoreDatabaseRequest, opts ...grpc.CallOption) (*RestoreDatabaseResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RestoreDatabaseResponse)
+	err := c.cc.Invoke(ctx, SchedulerService_RestoreDatabase_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schedulerServiceClient) CreateSecret(ctx context.Context, in *CreateSecretRequest, opts ...grpc.CallOption) (*CreateSecretResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateSecretResponse)
+	err := c.cc.Invoke(ctx, SchedulerService_CreateSecret_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schedulerServiceClient) ListSecrets(ctx context.Context, in *ListSecretsRequest, opts ...grpc.CallOption) (*ListSecretsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSecretsResponse)
+	err := c.cc.Invoke(ctx, SchedulerService_ListSecrets_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schedulerServiceClient) DeleteSecret(ctx context.Context, in *DeleteSecretRequest, opts ...grpc.CallOption) (*DeleteSecretResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteSecretResponse)
+	err := c.cc.Invoke(ctx, SchedulerService_DeleteSecret_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schedulerServiceClient) GetQuotaUsage(ctx context.Context, in *GetQuotaUsageRequest, opts ...grpc.CallOption) (*GetQuotaUsageResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetQuotaUsageResponse)
+	err := c.cc.Invoke(ctx, SchedulerService_GetQuotaUsage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schedulerServiceClient) GetNodeInfo(ctx context.Context, in *GetNodeInfoRequest, opts ...grpc.CallOption) (*GetNodeInfoResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetNodeInfoResponse)
+	err := c.cc.Invoke(ctx, SchedulerService_GetNodeInfo_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schedulerServiceClient) ListAuditEvents(ctx context.Context, in *ListAuditEventsRequest, opts ...grpc.CallOption) (*ListAuditEventsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAuditEventsResponse)
+	err := c.cc.Invoke(ctx, SchedulerService_ListAuditEvents_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // SchedulerServiceServer is the server API for SchedulerService service.
 // All implementations must embed UnimplementedSchedulerServiceServer
 // for forward compatibility.
@@ -181,13 +521,56 @@ type SchedulerServiceServer interface {
 	UpdateEnvironment(context.Context, *UpdateEnvironmentRequest) (*UpdateEnvironmentResponse, error)
 	DeleteEnvironment(context.Context, *DeleteEnvironmentRequest) (*DeleteEnvironmentResponse, error)
 	ListEnvironments(context.Context, *ListEnvironmentsRequest) (*ListEnvironmentsResponse, error)
-	// Environment lifecycle operations
+	// Manifest operations
+	ApplyManifest(context.Context, *ApplyManifestRequest) (*ApplyManifestResponse, error)
+	// Plan operations
+	PlanEnvironmentUpdate(context.Context, *PlanEnvironmentUpdateRequest) (*PlanEnvironmentUpdateResponse, error)
+	// Template operations
+	ListTemplates(context.Context, *ListTemplatesRequest) (*ListTemplatesResponse, error)
+	GetTemplate(context.Context, *GetTemplateRequest) (*GetTemplateResponse, error)
+	CreateEnvironmentFromTemplate(context.Context, *CreateEnvironmentFromTemplateRequest) (*CreateEnvironmentFromTemplateResponse, error)
+	RegisterTemplate(context.Context, *RegisterTemplateRequest) (*RegisterTemplateResponse, error)
+	DeleteTemplate(context.Context, *DeleteTemplateRequest) (*DeleteTemplateResponse, error)
+	// Revision operations
+	ListEnvironmentRevisions(context.Context, *ListEnvironmentRevisionsRequest) (*ListEnvironmentRevisionsResponse, error)
+	GetEnvironmentRevision(context.Context, *GetEnvironmentRevisionRequest) (*GetEnvironmentRevisionResponse, error)
+	RollbackEnvironment(context.Context, *RollbackEnvironmentRequest) (*RollbackEnvironmentResponse, error)
+	// Environment lifecycle operations. They run containers, so until
+	// `scheduler run` is given a container runtime they fail with
+	// FAILED_PRECONDITION, as do updates of running environments.
 	StartEnvironment(context.Context, *StartEnvironmentRequest) (*StartEnvironmentResponse, error)
 	StopEnvironment(context.Context, *StopEnvironmentRequest) (*StopEnvironmentResponse, error)
 	RestartEnvironment(context.Context, *RestartEnvironmentRequest) (*RestartEnvironmentResponse, error)
 	// Monitoring operations
 	GetEnvironmentStatus(context.Context, *GetEnvironmentStatusRequest) (*GetEnvironmentStatusResponse, error)
+	// Fails with FAILED_PRECONDITION without a container runtime
 	GetEnvironmentLogs(*GetEnvironmentLogsRequest, grpc.ServerStreamingServer[GetEnvironmentLogsResponse]) error
+	// Volume operations
+	CreateVolume(context.Context, *CreateVolumeRequest) (*CreateVolumeResponse, error)
+	GetVolume(context.Context, *GetVolumeRequest) (*GetVolumeResponse, error)
+	ListVolumes(context.Context, *ListVolumesRequest) (*ListVolumesResponse, error)
+	UpdateVolume(context.Context, *UpdateVolumeRequest) (*UpdateVolumeResponse, error)
+	DeleteVolume(context.Context, *DeleteVolumeRequest) (*DeleteVolumeResponse, error)
+	// Volume snapshot operations
+	SnapshotVolume(context.Context, *SnapshotVolumeRequest) (*SnapshotVolumeResponse, error)
+	ListVolumeSnapshots(context.Context, *ListVolumeSnapshotsRequest) (*ListVolumeSnapshotsResponse, error)
+	RestoreVolume(context.Context, *RestoreVolumeRequest) (*RestoreVolumeResponse, error)
+	// Database backup operations. Backups and restores run pg_dump and
+	// pg_restore in the database container and fail with FAILED_PRECONDITION
+	// without a container runtime.
+	BackupDatabase(context.Context, *BackupDatabaseRequest) (*BackupDatabaseResponse, error)
+	ListDatabaseBackups(context.Context, *ListDatabaseBackupsRequest) (*ListDatabaseBackupsResponse, error)
+	RestoreDatabase(context.Context, *RestoreDatabaseRequest) (*RestoreDatabaseResponse, error)
+	// Secret operations
+	CreateSecret(context.Context, *CreateSecretRequest) (*CreateSecretResponse, error)
+	ListSecrets(context.Context, *ListSecretsRequest) (*ListSecretsResponse, error)
+	DeleteSecret(context.Context, *DeleteSecretRequest) (*DeleteSecretResponse, error)
+	// Quota operations
+	GetQuotaUsage(context.Context, *GetQuotaUsageRequest) (*GetQuotaUsageResponse, error)
+	// Node operations
+	GetNodeInfo(context.Context, *GetNodeInfoRequest) (*GetNodeInfoResponse, error)
+	// Audit operations
+	ListAuditEvents(context.Context, *ListAuditEventsRequest) (*ListAuditEventsResponse, error)
 	mustEmbedUnimplementedSchedulerServiceServer()
 }
 
@@ -213,6 +596,36 @@ func (UnimplementedSchedulerServiceServer) DeleteEnvironment(context.Context, *D
 func (UnimplementedSchedulerServiceServer) ListEnvironments(context.Context, *ListEnvironmentsRequest) (*ListEnvironmentsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListEnvironments not implemented")
 }
+func (UnimplementedSchedulerServiceServer) ApplyManifest(context.Context, *ApplyManifestRequest) (*ApplyManifestResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApplyManifest not implemented")
+}
+func (UnimplementedSchedulerServiceServer) PlanEnvironmentUpdate(context.Context, *PlanEnvironmentUpdateRequest) (*PlanEnvironmentUpdateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PlanEnvironmentUpdate not implemented")
+}
+func (UnimplementedSchedulerServiceServer) ListTemplates(context.Context, *ListTemplatesRequest) (*ListTemplatesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTemplates not implemented")
+}
+func (UnimplementedSchedulerServiceServer) GetTemplate(context.Context, *GetTemplateRequest) (*GetTemplateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTemplate not implemented")
+}
+func (UnimplementedSchedulerServiceServer) CreateEnvironmentFromTemplate(context.Context, *CreateEnvironmentFromTemplateRequest) (*CreateEnvironmentFromTemplateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateEnvironmentFromTemplate not implemented")
+}
+func (UnimplementedSchedulerServiceServer) RegisterTemplate(context.Context, *RegisterTemplateRequest) (*RegisterTemplateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterTemplate not implemented")
+}
+func (UnimplementedSchedulerServiceServer) DeleteTemplate(context.Context, *DeleteTemplateRequest) (*DeleteTemplateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteTemplate not implemented")
+}
+func (UnimplementedSchedulerServiceServer) ListEnvironmentRevisions(context.Context, *ListEnvironmentRevisionsRequest) (*ListEnvironmentRevisionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListEnvironmentRevisions not implemented")
+}
+func (UnimplementedSchedulerServiceServer) GetEnvironmentRevision(context.Context, *GetEnvironmentRevisionRequest) (*GetEnvironmentRevisionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetEnvironmentRevision not implemented")
+}
+func (UnimplementedSchedulerServiceServer) RollbackEnvironment(context.Context, *RollbackEnvironmentRequest) (*RollbackEnvironmentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RollbackEnvironment not implemented")
+}
 func (UnimplementedSchedulerServiceServer) StartEnvironment(context.Context, *StartEnvironmentRequest) (*StartEnvironmentResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method StartEnvironment not implemented")
 }
@@ -228,6 +641,57 @@ func (UnimplementedSchedulerServiceServer) GetEnvironmentStatus(context.Context,
 func (UnimplementedSchedulerServiceServer) GetEnvironmentLogs(*GetEnvironmentLogsRequest, grpc.ServerStreamingServer[GetEnvironmentLogsResponse]) error {
 	return status.Errorf(codes.Unimplemented, "method GetEnvironmentLogs not implemented")
 }
+func (UnimplementedSchedulerServiceServer) CreateVolume(context.Context, *CreateVolumeRequest) (*CreateVolumeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateVolume not implemented")
+}
+func (UnimplementedSchedulerServiceServer) GetVolume(context.Context, *GetVolumeRequest) (*GetVolumeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetVolume not implemented")
+}
+func (UnimplementedSchedulerServiceServer) ListVolumes(context.Context, *ListVolumesRequest) (*ListVolumesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListVolumes not implemented")
+}
+func (UnimplementedSchedulerServiceServer) UpdateVolume(context.Context, *UpdateVolumeRequest) (*UpdateVolumeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateVolume not implemented")
+}
+func (UnimplementedSchedulerServiceServer) DeleteVolume(context.Context, *DeleteVolumeRequest) (*DeleteVolumeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteVolume not implemented")
+}
+func (UnimplementedSchedulerServiceServer) SnapshotVolume(context.Context, *SnapshotVolumeRequest) (*SnapshotVolumeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SnapshotVolume not implemented")
+}
+func (UnimplementedSchedulerServiceServer) ListVolumeSnapshots(context.Context, *ListVolumeSnapshotsRequest) (*ListVolumeSnapshotsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListVolumeSnapshots not implemented")
+}
+func (UnimplementedSchedulerServiceServer) RestoreVolume(context.Context, *RestoreVolumeRequest) (*RestoreVolumeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreVolume not implemented")
+}
+func (UnimplementedSchedulerServiceServer) BackupDatabase(context.Context, *BackupDatabaseRequest) (*BackupDatabaseResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BackupDatabase not implemented")
+}
+func (UnimplementedSchedulerServiceServer) ListDatabaseBackups(context.Context, *ListDatabaseBackupsRequest) (*ListDatabaseBackupsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDatabaseBackups not implemented")
+}
+func (UnimplementedSchedulerServiceServer) RestoreDatabase(context.Context, *RestoreDatabaseRequest) (*RestoreDatabaseResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreDatabase not implemented")
+}
+func (UnimplementedSchedulerServiceServer) CreateSecret(context.Context, *CreateSecretRequest) (*CreateSecretResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateSecret not implemented")
+}
+func (UnimplementedSchedulerServiceServer) ListSecrets(context.Context, *ListSecretsRequest) (*ListSecretsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSecrets not implemented")
+}
+func (UnimplementedSchedulerServiceServer) DeleteSecret(context.Context, *DeleteSecretRequest) (*DeleteSecretResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteSecret not implemented")
+}
+func (UnimplementedSchedulerServiceServer) GetQuotaUsage(context.Context, *GetQuotaUsageRequest) (*GetQuotaUsageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetQuotaUsage not implemented")
+}
+func (UnimplementedSchedulerServiceServer) GetNodeInfo(context.Context, *GetNodeInfoRequest) (*GetNodeInfoResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetNodeInfo not implemented")
+}
+func (UnimplementedSchedulerServiceServer) ListAuditEvents(context.Context, *ListAuditEventsRequest) (*ListAuditEventsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAuditEvents not implemented")
+}
 func (UnimplementedSchedulerServiceServer) mustEmbedUnimplementedSchedulerServiceServer() {}
 func (UnimplementedSchedulerServiceServer) testEmbeddedByValue()                          {}
 
@@ -339,91 +803,577 @@ func _SchedulerService_ListEnvironments_Handler(srv interface{}, ctx context.Con
 	return interceptor(ctx, in, info, handler)
 }
 
-func _SchedulerService_StartEnvironment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(StartEnvironmentRequest)
+func _SchedulerService_ApplyManifest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ApplyManifestRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(SchedulerServiceServer).StartEnvironment(ctx, in)
+		return srv.(SchedulerServiceServer).ApplyManifest(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: SchedulerService_StartEnvironment_FullMethodName,
+		FullMethod: SchedulerService_ApplyManifest_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(SchedulerServiceServer).StartEnvironment(ctx, req.(*StartEnvironmentRequest))
+		return srv.(SchedulerServiceServer).ApplyManifest(ctx, req.(*ApplyManifestRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _SchedulerService_StopEnvironment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(StopEnvironmentRequest)
+func _SchedulerService_PlanEnvironmentUpdate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PlanEnvironmentUpdateRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(SchedulerServiceServer).StopEnvironment(ctx, in)
+		return srv.(SchedulerServiceServer).PlanEnvironmentUpdate(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: SchedulerService_StopEnvironment_FullMethodName,
+		FullMethod: SchedulerService_PlanEnvironmentUpdate_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(SchedulerServiceServer).StopEnvironment(ctx, req.(*StopEnvironmentRequest))
+		return srv.(SchedulerServiceServer).PlanEnvironmentUpdate(ctx, req.(*PlanEnvironmentUpdateRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _SchedulerService_RestartEnvironment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(RestartEnvironmentRequest)
+func _SchedulerService_ListTemplates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTemplatesRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(SchedulerServiceServer).RestartEnvironment(ctx, in)
+		return srv.(SchedulerServiceServer).ListTemplates(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: SchedulerService_RestartEnvironment_FullMethodName,
+		FullMethod: SchedulerService_ListTemplates_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(SchedulerServiceServer).RestartEnvironment(ctx, req.(*RestartEnvironmentRequest))
+		return srv.(SchedulerServiceServer).ListTemplates(ctx, req.(*ListTemplatesRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _SchedulerService_GetEnvironmentStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetEnvironmentStatusRequest)
+func _SchedulerService_GetTemplate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTemplateRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(SchedulerServiceServer).GetEnvironmentStatus(ctx, in)
+		return srv.(SchedulerServiceServer).GetTemplate(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: SchedulerService_GetEnvironmentStatus_FullMethodName,
+		FullMethod: SchedulerService_GetTemplate_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(SchedulerServiceServer).GetEnvironmentStatus(ctx, req.(*GetEnvironmentStatusRequest))
+		return srv.(SchedulerServiceServer).GetTemplate(ctx, req.(*GetTemplateRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _SchedulerService_GetEnvironmentLogs_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(GetEnvironmentLogsRequest)
-	if err := stream.RecvMsg(m); err != nil {
-		return err
+func _SchedulerService_CreateEnvironmentFromTemplate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateEnvironmentFromTemplateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
 	}
-	return srv.(SchedulerServiceServer).GetEnvironmentLogs(m, &grpc.GenericServerStream[GetEnvironmentLogsRequest, GetEnvironmentLogsResponse]{ServerStream: stream})
+	if interceptor == nil {
+		return srv.(SchedulerServiceServer).CreateEnvironmentFromTemplate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SchedulerService_CreateEnvironmentFromTemplate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SchedulerServiceServer).CreateEnvironmentFromTemplate(ctx, req.(*CreateEnvironmentFromTemplateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
-type SchedulerService_GetEnvironmentLogsServer = grpc.ServerStreamingServer[GetEnvironmentLogsResponse]
-
-// SchedulerService_ServiceDesc is the grpc.ServiceDesc for SchedulerService service.
-// It's only intended for direct use with grpc.RegisterService,
+func _SchedulerService_RegisterTemplate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterTemplateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SchedulerServiceServer).RegisterTemplate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SchedulerService_RegisterTemplate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SchedulerServiceServer).RegisterTemplate(ctx, req.(*RegisterTemplateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SchedulerService_DeleteTemplate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteTemplateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SchedulerServiceServer).DeleteTemplate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SchedulerService_DeleteTemplate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SchedulerServiceServer).DeleteTemplate(ctx, req.(*DeleteTemplateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SchedulerService_ListEnvironmentRevisions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListEnvironmentRevisionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SchedulerServiceServer).ListEnvironmentRevisions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SchedulerService_ListEnvironmentRevisions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SchedulerServiceServer).ListEnvironmentRevisions(ctx, req.(*ListEnvironmentRevisionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SchedulerService_GetEnvironmentRevision_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetEnvironmentRevisionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SchedulerServiceServer).GetEnvironmentRevision(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SchedulerService_GetEnvironmentRevision_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SchedulerServiceServer).GetEnvironmentRevision(ctx, req.(*GetEnvironmentRevisionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SchedulerService_RollbackEnvironment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RollbackEnvironmentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SchedulerServiceServer).RollbackEnvironment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SchedulerService_RollbackEnvironment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SchedulerServiceServer).RollbackEnvironment(ctx, req.(*RollbackEnvironmentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SchedulerService_StartEnvironment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartEnvironmentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SchedulerServiceServer).StartEnvironment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SchedulerService_StartEnvironment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SchedulerServiceServer).StartEnvironment(ctx, req.(*StartEnvironmentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SchedulerService_StopEnvironment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StopEnvironmentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SchedulerServiceServer).StopEnvironment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SchedulerService_StopEnvironment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SchedulerServiceServer).StopEnvironment(ctx, req.(*StopEnvironmentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SchedulerService_RestartEnvironment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestartEnvironmentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SchedulerServiceServer).RestartEnvironment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SchedulerService_RestartEnvironment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SchedulerServiceServer).RestartEnvironment(ctx, req.(*RestartEnvironmentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SchedulerService_GetEnvironmentStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetEnvironmentStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SchedulerServiceServer).GetEnvironmentStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SchedulerService_GetEnvironmentStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SchedulerServiceServer).GetEnvironmentStatus(ctx, req.(*GetEnvironmentStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SchedulerService_GetEnvironmentLogs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetEnvironmentLogsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SchedulerServiceServer).GetEnvironmentLogs(m, &grpc.GenericServerStream[GetEnvironmentLogsRequest, GetEnvironmentLogsResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type SchedulerService_GetEnvironmentLogsServer = grpc.ServerStreamingServer[GetEnvironmentLogsResponse]
+
+func _SchedulerService_CreateVolume_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateVolumeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SchedulerServiceServer).CreateVolume(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SchedulerService_CreateVolume_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SchedulerServiceServer).CreateVolume(ctx, req.(*CreateVolumeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SchedulerService_GetVolume_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetVolumeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SchedulerServiceServer).GetVolume(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SchedulerService_GetVolume_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SchedulerServiceServer).GetVolume(ctx, req.(*GetVolumeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SchedulerService_ListVolumes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListVolumesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SchedulerServiceServer).ListVolumes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SchedulerService_ListVolumes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SchedulerServiceServer).ListVolumes(ctx, req.(*ListVolumesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SchedulerService_UpdateVolume_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateVolumeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SchedulerServiceServer).UpdateVolume(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SchedulerService_UpdateVolume_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SchedulerServiceServer).UpdateVolume(ctx, req.(*UpdateVolumeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SchedulerService_DeleteVolume_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteVolumeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SchedulerServiceServer).DeleteVolume(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SchedulerService_DeleteVolume_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SchedulerServiceServer).DeleteVolume(ctx, req.(*DeleteVolumeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SchedulerService_SnapshotVolume_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SnapshotVolumeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SchedulerServiceServer).SnapshotVolume(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SchedulerService_SnapshotVolume_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SchedulerServiceServer).SnapshotVolume(ctx, req.(*SnapshotVolumeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SchedulerService_ListVolumeSnapshots_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListVolumeSnapshotsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SchedulerServiceServer).ListVolumeSnapshots(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SchedulerService_ListVolumeSnapshots_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SchedulerServiceServer).ListVolumeSnapshots(ctx, req.(*ListVolumeSnapshotsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SchedulerService_RestoreVolume_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreVolumeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SchedulerServiceServer).RestoreVolume(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SchedulerService_RestoreVolume_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SchedulerServiceServer).RestoreVolume(ctx, req.(*RestoreVolumeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SchedulerService_BackupDatabase_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BackupDatabaseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SchedulerServiceServer).BackupDatabase(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SchedulerService_BackupDatabase_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SchedulerServiceServer).BackupDatabase(ctx, req.(*BackupDatabaseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SchedulerService_ListDatabaseBackups_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDatabaseBackupsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SchedulerServiceServer).ListDatabaseBackups(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SchedulerService_ListDatabaseBackups_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SchedulerServiceServer).ListDatabaseBackups(ctx, req.(*ListDatabaseBackupsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SchedulerService_RestoreDatabase_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreDatabaseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SchedulerServiceServer).RestoreDatabase(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SchedulerService_RestoreDatabase_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SchedulerServiceServer).RestoreDatabase(ctx, req.(*RestoreDatabaseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SchedulerService_CreateSecret_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateSecretRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SchedulerServiceServer).CreateSecret(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SchedulerService_CreateSecret_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SchedulerServiceServer).CreateSecret(ctx, req.(*CreateSecretRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SchedulerService_ListSecrets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSecretsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SchedulerServiceServer).ListSecrets(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SchedulerService_ListSecrets_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SchedulerServiceServer).ListSecrets(ctx, req.(*ListSecretsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SchedulerService_DeleteSecret_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteSecretRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SchedulerServiceServer).DeleteSecret(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SchedulerService_DeleteSecret_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SchedulerServiceServer).DeleteSecret(ctx, req.(*DeleteSecretRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SchedulerService_GetQuotaUsage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetQuotaUsageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SchedulerServiceServer).GetQuotaUsage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SchedulerService_GetQuotaUsage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SchedulerServiceServer).GetQuotaUsage(ctx, req.(*GetQuotaUsageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SchedulerService_GetNodeInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetNodeInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SchedulerServiceServer).GetNodeInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SchedulerService_GetNodeInfo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SchedulerServiceServer).GetNodeInfo(ctx, req.(*GetNodeInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SchedulerService_ListAuditEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAuditEventsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SchedulerServiceServer).ListAuditEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SchedulerService_ListAuditEvents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SchedulerServiceServer).ListAuditEvents(ctx, req.(*ListAuditEventsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// SchedulerService_ServiceDesc is the grpc.ServiceDesc for SchedulerService service.
+// It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
 var SchedulerService_ServiceDesc = grpc.ServiceDesc{
 	ServiceName: "scheduler.v1.SchedulerService",
@@ -449,6 +1399,46 @@ var SchedulerService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListEnvironments",
 			Handler:    _SchedulerService_ListEnvironments_Handler,
 		},
+		{
+			MethodName: "ApplyManifest",
+			Handler:    _SchedulerService_ApplyManifest_Handler,
+		},
+		{
+			MethodName: "PlanEnvironmentUpdate",
+			Handler:    _SchedulerService_PlanEnvironmentUpdate_Handler,
+		},
+		{
+			MethodName: "ListTemplates",
+			Handler:    _SchedulerService_ListTemplates_Handler,
+		},
+		{
+			MethodName: "GetTemplate",
+			Handler:    _SchedulerService_GetTemplate_Handler,
+		},
+		{
+			MethodName: "CreateEnvironmentFromTemplate",
+			Handler:    _SchedulerService_CreateEnvironmentFromTemplate_Handler,
+		},
+		{
+			MethodName: "RegisterTemplate",
+			Handler:    _SchedulerService_RegisterTemplate_Handler,
+		},
+		{
+			MethodName: "DeleteTemplate",
+			Handler:    _SchedulerService_DeleteTemplate_Handler,
+		},
+		{
+			MethodName: "ListEnvironmentRevisions",
+			Handler:    _SchedulerService_ListEnvironmentRevisions_Handler,
+		},
+		{
+			MethodName: "GetEnvironmentRevision",
+			Handler:    _SchedulerService_GetEnvironmentRevision_Handler,
+		},
+		{
+			MethodName: "RollbackEnvironment",
+			Handler:    _SchedulerService_RollbackEnvironment_Handler,
+		},
 		{
 			MethodName: "StartEnvironment",
 			Handler:    _SchedulerService_StartEnvironment_Handler,
@@ -465,6 +1455,74 @@ var SchedulerService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetEnvironmentStatus",
 			Handler:    _SchedulerService_GetEnvironmentStatus_Handler,
 		},
+		{
+			MethodName: "CreateVolume",
+			Handler:    _SchedulerService_CreateVolume_Handler,
+		},
+		{
+			MethodName: "GetVolume",
+			Handler:    _SchedulerService_GetVolume_Handler,
+		},
+		{
+			MethodName: "ListVolumes",
+			Handler:    _SchedulerService_ListVolumes_Handler,
+		},
+		{
+			MethodName: "UpdateVolume",
+			Handler:    _SchedulerService_UpdateVolume_Handler,
+		},
+		{
+			MethodName: "DeleteVolume",
+			Handler:    _SchedulerService_DeleteVolume_Handler,
+		},
+		{
+			MethodName: "SnapshotVolume",
+			Handler:    _SchedulerService_SnapshotVolume_Handler,
+		},
+		{
+			MethodName: "ListVolumeSnapshots",
+			Handler:    _SchedulerService_ListVolumeSnapshots_Handler,
+		},
+		{
+			MethodName: "RestoreVolume",
+			Handler:    _SchedulerService_RestoreVolume_Handler,
+		},
+		{
+			MethodName: "BackupDatabase",
+			Handler:    _SchedulerService_BackupDatabase_Handler,
+		},
+		{
+			MethodName: "ListDatabaseBackups",
+			Handler:    _SchedulerService_ListDatabaseBackups_Handler,
+		},
+		{
+			MethodName: "RestoreDatabase",
+			Handler:    _SchedulerService_RestoreDatabase_Handler,
+		},
+		{
+			MethodName: "CreateSecret",
+			Handler:    _SchedulerService_CreateSecret_Handler,
+		},
+		{
+			MethodName: "ListSecrets",
+			Handler:    _SchedulerService_ListSecrets_Handler,
+		},
+		{
+			MethodName: "DeleteSecret",
+			Handler:    _SchedulerService_DeleteSecret_Handler,
+		},
+		{
+			MethodName: "GetQuotaUsage",
+			Handler:    _SchedulerService_GetQuotaUsage_Handler,
+		},
+		{
+			MethodName: "GetNodeInfo",
+			Handler:    _SchedulerService_GetNodeInfo_Handler,
+		},
+		{
+			MethodName: "ListAuditEvents",
+			Handler:    _SchedulerService_ListAuditEvents_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{